@@ -53,6 +53,11 @@ var unRecoverableTable = map[string]map[string]struct{}{
 
 		// replace into view is not supported now
 		"tidb_mdl_view": {},
+
+		// the refresh status refers to the table IDs, which are changed by restore.
+		"tidb_mview_refresh": {},
+		// the feedback refers to the table IDs, which are changed by restore.
+		"stats_cardinality_feedback": {},
 	},
 	"sys": {
 		// replace into view is not supported now
@@ -48,6 +48,8 @@ const (
 	Builtin = "builtin"
 	// History indicate the binding is created from statement summary by plan digest
 	History = "history"
+	// Evolve indicates the binding is created by the automatic plan evolution for a regressed statement.
+	Evolve = "evolve"
 )
 
 // Binding stores the basic bind hint info.
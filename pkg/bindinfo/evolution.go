@@ -0,0 +1,330 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/bindinfo/internal/logutil"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	utilparser "github.com/pingcap/tidb/pkg/util/parser"
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
+	stmtsummaryv2 "github.com/pingcap/tidb/pkg/util/stmtsummary/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// evolveMinExecCount is the minimal execution count of a plan to be compared,
+	// the average latency of the plans executed fewer times is not reliable.
+	evolveMinExecCount = 5
+	// planRegressionRatio is the ratio of the average latencies of the current plan and the better plan,
+	// the current plan is regarded as regressed if its average latency is larger than that of the better plan by it.
+	planRegressionRatio = 2.0
+
+	// the actions recorded in mysql.plan_evolution_history.
+	// evolveActionAccepted means the better plan is verified and bound by a global binding.
+	evolveActionAccepted = "accepted"
+	// evolveActionRecommended means the better plan is only recommended, since the automatic binding is disabled or
+	// the better plan can't be verified by running the statement.
+	evolveActionRecommended = "recommended"
+	// evolveActionRejected means the better plan fails the verification.
+	evolveActionRejected = "rejected"
+)
+
+// GetBindableStmtsForEvolution gets the statements used to detect the plan regressions.
+// It's a variable so that it can be replaced in tests.
+var GetBindableStmtsForEvolution = func() []*stmtsummary.BindableStmt {
+	return stmtsummaryv2.GetMoreThanCntBindableStmt(0)
+}
+
+// PlanRegression is a statement whose current plan runs much slower than a plan it used before,
+// which usually happens after the statistics or the schema are changed.
+type PlanRegression struct {
+	// Regressed is the statement executed with the current plan.
+	Regressed *stmtsummary.BindableStmt
+	// Better is the statement executed with the previous plan which has the lowest average latency.
+	Better *stmtsummary.BindableStmt
+}
+
+// avgLatency returns the average latency of the statement.
+func avgLatency(stmt *stmtsummary.BindableStmt) time.Duration {
+	if stmt.ExecCount == 0 {
+		return 0
+	}
+	return stmt.SumLatency / time.Duration(stmt.ExecCount)
+}
+
+// DetectPlanRegressions compares the plans of the same statement, and returns the statements whose latest plan
+// is slower than a plan used before by planRegressionRatio.
+func DetectPlanRegressions(stmts []*stmtsummary.BindableStmt) []*PlanRegression {
+	type stmtKey struct {
+		schema string
+		digest string
+	}
+	// the same plan of a statement may appear in several summaries, merge them first.
+	plans := make(map[stmtKey]map[string]*stmtsummary.BindableStmt)
+	for _, stmt := range stmts {
+		if stmt.Digest == "" || stmt.PlanDigest == "" {
+			continue
+		}
+		key := stmtKey{schema: stmt.Schema, digest: stmt.Digest}
+		if plans[key] == nil {
+			plans[key] = make(map[string]*stmtsummary.BindableStmt)
+		}
+		merged, ok := plans[key][stmt.PlanDigest]
+		if !ok {
+			cloned := *stmt
+			plans[key][stmt.PlanDigest] = &cloned
+			continue
+		}
+		merged.ExecCount += stmt.ExecCount
+		merged.SumLatency += stmt.SumLatency
+		if stmt.FirstSeen.Before(merged.FirstSeen) {
+			merged.FirstSeen = stmt.FirstSeen
+		}
+		if stmt.LastSeen.After(merged.LastSeen) {
+			merged.LastSeen = stmt.LastSeen
+			merged.Query = stmt.Query
+		}
+	}
+
+	regressions := make([]*PlanRegression, 0)
+	for _, planMap := range plans {
+		if len(planMap) < 2 {
+			continue
+		}
+		var current *stmtsummary.BindableStmt
+		for _, plan := range planMap {
+			if current == nil || plan.LastSeen.After(current.LastSeen) {
+				current = plan
+			}
+		}
+		if current.ExecCount < evolveMinExecCount {
+			continue
+		}
+		var better *stmtsummary.BindableStmt
+		for _, plan := range planMap {
+			// only the plans used before the current one are candidates.
+			if plan == current || plan.PlanHint == "" || plan.ExecCount < evolveMinExecCount ||
+				!plan.FirstSeen.Before(current.FirstSeen) {
+				continue
+			}
+			if better == nil || avgLatency(plan) < avgLatency(better) {
+				better = plan
+			}
+		}
+		if better == nil || float64(avgLatency(current)) <= planRegressionRatio*float64(avgLatency(better)) {
+			continue
+		}
+		regressions = append(regressions, &PlanRegression{Regressed: current, Better: better})
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		if regressions[i].Regressed.Digest != regressions[j].Regressed.Digest {
+			return regressions[i].Regressed.Digest < regressions[j].Regressed.Digest
+		}
+		return regressions[i].Regressed.Schema < regressions[j].Regressed.Schema
+	})
+	return regressions
+}
+
+// EvolveBaselines detects the plan regressions from the statement summary, verifies the better plans of them,
+// and creates the global bindings for the better plans if createBinding is true, otherwise only recommends them.
+// The better plans which can't be verified by running the statements are only recommended too. All the decisions
+// are recorded in mysql.plan_evolution_history.
+func (h *globalBindingHandle) EvolveBaselines(createBinding bool) {
+	parser4Evolve := parser.New()
+	for _, regression := range DetectPlanRegressions(GetBindableStmtsForEvolution()) {
+		better := regression.Better
+		stmt, err := parser4Evolve.ParseOneStmt(better.Query, better.Charset, better.Collation)
+		if err != nil {
+			logutil.BindLogger().Debug("parse SQL failed in plan evolution", zap.String("SQL", better.Query), zap.Error(err))
+			continue
+		}
+		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+			continue
+		}
+		dbName := utilparser.GetDefaultDB(stmt, better.Schema)
+		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, better.Query))
+		if r := h.getCache().GetBinding(digest.String()); HasAvailableBinding(r) {
+			continue
+		}
+		recorded, err := h.isPlanRegressionRecorded(digest.String(), regression)
+		if err != nil || recorded {
+			continue
+		}
+		bindSQL := GenerateBindingSQL(stmt, better.PlanHint, true, dbName)
+		if bindSQL == "" {
+			continue
+		}
+
+		action := evolveActionRecommended
+		reason := fmt.Sprintf("the average latency of the plan %s is %v, which is more than %v times of %v of the plan %s",
+			regression.Regressed.PlanDigest, avgLatency(regression.Regressed), planRegressionRatio,
+			avgLatency(better), better.PlanDigest)
+		currentLatency, betterLatency, err := h.verifyEvolvedPlan(stmt, dbName, bindSQL, regression)
+		switch {
+		case err != nil:
+			action, reason = evolveActionRejected, err.Error()
+		case betterLatency == 0:
+			reason += ", and it isn't verified by running the statement"
+		default:
+			reason += fmt.Sprintf(", and it runs in %v while the current plan runs in %v in the verification", betterLatency, currentLatency)
+		}
+		if createBinding && betterLatency > 0 && err == nil {
+			action = evolveActionAccepted
+			binding := Binding{
+				OriginalSQL: normalizedSQL,
+				Db:          dbName,
+				BindSQL:     bindSQL,
+				Status:      Enabled,
+				Charset:     better.Charset,
+				Collation:   better.Collation,
+				Source:      Evolve,
+				SQLDigest:   digest.String(),
+				PlanDigest:  better.PlanDigest,
+			}
+			// We don't need to pass the `sctx` because the BindSQL has been verified already.
+			if err := h.CreateGlobalBinding(nil, binding); err != nil {
+				logutil.BindLogger().Warn("create binding failed in plan evolution", zap.String("SQL", better.Query), zap.Error(err))
+				continue
+			}
+		}
+		if err := h.recordPlanEvolution(digest.String(), dbName, normalizedSQL, bindSQL, action, reason, regression); err != nil {
+			logutil.BindLogger().Warn("record plan evolution failed", zap.String("SQL", better.Query), zap.Error(err))
+		}
+	}
+}
+
+// verifyEvolvedPlan checks whether the better plan can still be generated with the binding SQL, the plan may become
+// unavailable if the schema is changed, e.g. the index used by it is dropped. Then it runs the statement with the
+// current plan and the better plan, and checks whether the better plan is really faster. The latencies of the runs
+// are 0 if the statement can't be run for the verification, i.e. it isn't a query or it has param markers.
+func (h *globalBindingHandle) verifyEvolvedPlan(stmt ast.StmtNode, dbName, bindSQL string, regression *PlanRegression) (currentLatency, betterLatency time.Duration, err error) {
+	paramChecker := &paramMarkerChecker{}
+	stmt.Accept(paramChecker)
+	if paramChecker.hasParamMarker {
+		// we cannot explain the statements with param markers, trust the plan recorded.
+		return 0, 0, nil
+	}
+	err = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		planHint := regression.Better.PlanHint
+		hints, err := getHintsForSQL(sctx, bindSQL)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSpace(hints), strings.TrimSpace(planHint)) {
+			return fmt.Errorf("the plan generated with the binding differs from the better plan, expected hints: %s, actual hints: %s", planHint, hints)
+		}
+		// the DMLs can't be run for the verification since they modify the data.
+		if _, ok := stmt.(*ast.SelectStmt); !ok {
+			return nil
+		}
+
+		// the current plan is also run with its hints, so that it's the plan recorded by the statement summary.
+		currentSQL := GenerateBindingSQL(stmt, regression.Regressed.PlanHint, true, dbName)
+		if currentSQL == "" {
+			currentSQL = utilparser.RestoreWithDefaultDB(stmt, dbName, regression.Regressed.Query)
+		}
+		maxTimeStr, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBEvolvePlanTaskMaxTime)
+		if err != nil {
+			return err
+		}
+		maxTime, err := strconv.ParseInt(maxTimeStr, 10, 64)
+		if err != nil {
+			return err
+		}
+		if currentLatency, err = runForEvolution(sctx, currentSQL, time.Duration(maxTime)*time.Second); err != nil {
+			return err
+		}
+		// the better plan is stopped once it runs longer than the current plan.
+		if betterLatency, err = runForEvolution(sctx, bindSQL, currentLatency); err != nil {
+			return err
+		}
+		if betterLatency >= currentLatency {
+			return fmt.Errorf("the better plan runs in %v, which isn't faster than %v of the current plan in the verification", betterLatency, currentLatency)
+		}
+		return nil
+	})
+	return
+}
+
+// runForEvolution runs the statement without the bindings and returns its latency, the statement is stopped and
+// maxTime is returned if it doesn't finish in maxTime. The time isn't limited if maxTime isn't positive.
+func runForEvolution(sctx sessionctx.Context, sql string, maxTime time.Duration) (time.Duration, error) {
+	origVals := sctx.GetSessionVars().UsePlanBaselines
+	sctx.GetSessionVars().UsePlanBaselines = false
+	defer func() {
+		sctx.GetSessionVars().UsePlanBaselines = origVals
+	}()
+
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
+	if maxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxTime)
+		defer cancel()
+	}
+	start := time.Now()
+	err := drainForEvolution(ctx, sctx, sql)
+	if ctx.Err() != nil {
+		return maxTime, nil
+	}
+	return time.Since(start), err
+}
+
+func drainForEvolution(ctx context.Context, sctx sessionctx.Context, sql string) error {
+	rs, err := sctx.GetSQLExecutor().ExecuteInternal(ctx, sql)
+	if err != nil || rs == nil {
+		return err
+	}
+	defer terror.Call(rs.Close)
+	chk := rs.NewChunk(nil)
+	for {
+		if err := rs.Next(ctx, chk); err != nil || chk.NumRows() == 0 {
+			return err
+		}
+	}
+}
+
+// isPlanRegressionRecorded checks whether the plan regression has been handled before.
+func (h *globalBindingHandle) isPlanRegressionRecorded(sqlDigest string, regression *PlanRegression) (recorded bool, err error) {
+	err = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		rows, _, err := execRows(sctx, `SELECT 1 FROM mysql.plan_evolution_history WHERE sql_digest = %? AND regressed_plan_digest = %? AND better_plan_digest = %? LIMIT 1`,
+			sqlDigest, regression.Regressed.PlanDigest, regression.Better.PlanDigest)
+		recorded = len(rows) > 0
+		return err
+	})
+	return
+}
+
+// recordPlanEvolution records the action for the plan regression into mysql.plan_evolution_history.
+func (h *globalBindingHandle) recordPlanEvolution(sqlDigest, db, originalSQL, bindSQL, action, reason string, regression *PlanRegression) error {
+	return h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		_, err := exec(sctx, `INSERT INTO mysql.plan_evolution_history (sql_digest, db, original_sql, regressed_plan_digest, regressed_avg_latency,
+			better_plan_digest, better_avg_latency, bind_sql, action, reason) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`,
+			sqlDigest, db, originalSQL, regression.Regressed.PlanDigest, uint64(avgLatency(regression.Regressed)),
+			regression.Better.PlanDigest, uint64(avgLatency(regression.Better)), bindSQL, action, reason)
+		return err
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/bindinfo"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
+	"github.com/stretchr/testify/require"
+)
+
+func newBindableStmtForEvolution(digest, planDigest, planHint string, execCount int64, avgLatency time.Duration, firstSeen, lastSeen time.Time) *stmtsummary.BindableStmt {
+	return &stmtsummary.BindableStmt{
+		Schema:     "test",
+		Query:      "select * from t where a = 1 and b = 1",
+		PlanHint:   planHint,
+		Charset:    "utf8mb4",
+		Collation:  "utf8mb4_bin",
+		Digest:     digest,
+		PlanDigest: planDigest,
+		ExecCount:  execCount,
+		SumLatency: avgLatency * time.Duration(execCount),
+		FirstSeen:  firstSeen,
+		LastSeen:   lastSeen,
+	}
+}
+
+func TestDetectPlanRegressions(t *testing.T) {
+	t0 := time.Now().Add(-time.Hour)
+	t1, t2, t3 := t0.Add(10*time.Minute), t0.Add(20*time.Minute), t0.Add(30*time.Minute)
+	stmts := []*stmtsummary.BindableStmt{
+		// d1: the plan changes from p1 to p2 and becomes much slower.
+		newBindableStmtForEvolution("d1", "p1", "h1", 10, time.Millisecond, t0, t1),
+		newBindableStmtForEvolution("d1", "p2", "h2", 10, 5*time.Millisecond, t1, t3),
+		// d1: p3 is even faster but executed too few times.
+		newBindableStmtForEvolution("d1", "p3", "h3", 2, time.Microsecond, t0, t1),
+		// d2: the plan changes but isn't slower enough.
+		newBindableStmtForEvolution("d2", "p1", "h1", 10, time.Millisecond, t0, t1),
+		newBindableStmtForEvolution("d2", "p2", "h2", 10, 1500*time.Microsecond, t1, t3),
+		// d3: the slow plan is the old one.
+		newBindableStmtForEvolution("d3", "p1", "h1", 10, 5*time.Millisecond, t0, t1),
+		newBindableStmtForEvolution("d3", "p2", "h2", 10, time.Millisecond, t1, t3),
+		// d4: the same plan appears in two summaries and is merged.
+		newBindableStmtForEvolution("d4", "p1", "h1", 3, time.Millisecond, t0, t1),
+		newBindableStmtForEvolution("d4", "p1", "h1", 3, time.Millisecond, t1, t2),
+		newBindableStmtForEvolution("d4", "p2", "h2", 10, 3*time.Millisecond, t2, t3),
+		// d5: only one plan.
+		newBindableStmtForEvolution("d5", "p1", "h1", 10, time.Second, t0, t3),
+	}
+	regressions := bindinfo.DetectPlanRegressions(stmts)
+	require.Len(t, regressions, 2)
+	require.Equal(t, "d1", regressions[0].Regressed.Digest)
+	require.Equal(t, "p2", regressions[0].Regressed.PlanDigest)
+	require.Equal(t, "p1", regressions[0].Better.PlanDigest)
+	require.Equal(t, "d4", regressions[1].Regressed.Digest)
+	require.Equal(t, "p2", regressions[1].Regressed.PlanDigest)
+	require.Equal(t, "p1", regressions[1].Better.PlanDigest)
+	require.Equal(t, int64(6), regressions[1].Better.ExecCount)
+	require.Equal(t, t0, regressions[1].Better.FirstSeen)
+	// the input isn't modified by the merge.
+	require.Equal(t, int64(3), stmts[7].ExecCount)
+}
+
+func TestEvolveBaselines(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, key idx_a(a), key idx_b(b))")
+	// idx_a is much faster than idx_b for the query, since only one row has a = 1 while all the rows have b = 1.
+	tk.MustExec("set @@cte_max_recursion_depth = 20000")
+	tk.MustExec("insert into t with recursive s(n) as (select 1 union all select n + 1 from s where n < 20000) select n, 1 from s")
+
+	hintA := tk.MustQuery("explain format='hint' select /*+ use_index(t, idx_a) */ * from t where a = 1 and b = 1").Rows()[0][0].(string)
+	hintB := tk.MustQuery("explain format='hint' select /*+ use_index(t, idx_b) */ * from t where a = 1 and b = 1").Rows()[0][0].(string)
+	t0 := time.Now().Add(-time.Hour)
+	t1, t2 := t0.Add(10*time.Minute), t0.Add(20*time.Minute)
+	origin := bindinfo.GetBindableStmtsForEvolution
+	defer func() { bindinfo.GetBindableStmtsForEvolution = origin }()
+	regressedToB := func() []*stmtsummary.BindableStmt {
+		return []*stmtsummary.BindableStmt{
+			newBindableStmtForEvolution("d", "plan_a", hintA, 10, time.Millisecond, t0, t1),
+			newBindableStmtForEvolution("d", "plan_b", hintB, 10, 10*time.Millisecond, t1, t2),
+		}
+	}
+	bindinfo.GetBindableStmtsForEvolution = regressedToB
+
+	// only recommend the better plan by default.
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery("select db, regressed_plan_digest, regressed_avg_latency, better_plan_digest, better_avg_latency, action from mysql.plan_evolution_history").
+		Check(testkit.Rows("test plan_b 10000000 plan_a 1000000 recommended"))
+	reason := tk.MustQuery("select reason from mysql.plan_evolution_history").Rows()[0][0].(string)
+	require.Contains(t, reason, "in the verification")
+	// the handled regression isn't recorded again.
+	dom.BindHandle().EvolveBaselines(false)
+	tk.MustQuery("select count(*) from mysql.plan_evolution_history").Check(testkit.Rows("1"))
+
+	tk.MustExec("delete from mysql.plan_evolution_history")
+	tk.MustExec("set @@global.tidb_auto_plan_evolution = 'ON'")
+	defer tk.MustExec("set @@global.tidb_auto_plan_evolution = default")
+	tk.MustExec("admin evolve bindings")
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "select * from `test` . `t` where `a` = ? and `b` = ?", rows[0][0])
+	require.Contains(t, rows[0][1], "idx_a")
+	require.Equal(t, bindinfo.Evolve, rows[0][8])
+	require.Equal(t, "plan_a", rows[0][10])
+	tk.MustQuery("select action from mysql.plan_evolution_history").Check(testkit.Rows("accepted"))
+	require.True(t, tk.MustUseIndex("select * from t where a = 2 and b = 2", "idx_a"))
+
+	// the better plan is rejected if it isn't faster than the current plan when running the statement.
+	tk.MustExec("drop global binding for select * from t where a = 1 and b = 1")
+	tk.MustExec("delete from mysql.plan_evolution_history")
+	bindinfo.GetBindableStmtsForEvolution = func() []*stmtsummary.BindableStmt {
+		return []*stmtsummary.BindableStmt{
+			newBindableStmtForEvolution("d", "plan_b", hintB, 10, time.Millisecond, t0, t1),
+			newBindableStmtForEvolution("d", "plan_a", hintA, 10, 10*time.Millisecond, t1, t2),
+		}
+	}
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery("select action from mysql.plan_evolution_history").Check(testkit.Rows("rejected"))
+	reason = tk.MustQuery("select reason from mysql.plan_evolution_history").Rows()[0][0].(string)
+	require.Contains(t, reason, "isn't faster than")
+
+	// the better plan is rejected if it can't be generated anymore.
+	bindinfo.GetBindableStmtsForEvolution = regressedToB
+	tk.MustExec("delete from mysql.plan_evolution_history")
+	tk.MustExec("alter table t drop index idx_a")
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery("select action from mysql.plan_evolution_history").Check(testkit.Rows("rejected"))
+}
+
+func TestAutoPlanEvolutionVar(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select @@global.tidb_auto_plan_evolution").Check(testkit.Rows("OFF"))
+	tk.MustExec("set @@global.tidb_auto_plan_evolution = 'recommend'")
+	tk.MustQuery("select @@global.tidb_auto_plan_evolution").Check(testkit.Rows("RECOMMEND"))
+	tk.MustExec("set @@global.tidb_auto_plan_evolution = default")
+	tk.MustGetErrMsg("set @@global.tidb_auto_plan_evolution = 'bind'", "[variable:1231]Variable 'tidb_auto_plan_evolution' can't be set to the value of 'bind'")
+	tk.MustGetErrMsg("set @@tidb_auto_plan_evolution = 'ON'", "[variable:1229]Variable 'tidb_auto_plan_evolution' is a GLOBAL variable and should be set with SET GLOBAL")
+}
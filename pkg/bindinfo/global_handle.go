@@ -104,6 +104,9 @@ type GlobalBindingHandle interface {
 	// CaptureBaselines is used to automatically capture plan baselines.
 	CaptureBaselines()
 
+	// EvolveBaselines detects the statements whose plans regressed and binds (or only recommends) their better plans.
+	EvolveBaselines(createBinding bool)
+
 	variable.Statistics
 }
 
@@ -1023,7 +1023,7 @@ func buildColumnAndConstraint(
 // In NO_ZERO_DATE SQL mode, TIMESTAMP/DATE/DATETIME type can't have zero date like '0000-00-00' or '0000-00-00 00:00:00'.
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value any) (bool, any, error) {
 	hasDefaultValue := true
-	if value != nil && (col.GetType() == mysql.TypeJSON ||
+	if value != nil && (col.GetType() == mysql.TypeJSON || col.GetType() == mysql.TypeGeometry || col.GetType() == mysql.TypeTiDBVectorFloat32 ||
 		col.GetType() == mysql.TypeTinyBlob || col.GetType() == mysql.TypeMediumBlob ||
 		col.GetType() == mysql.TypeLongBlob || col.GetType() == mysql.TypeBlob) {
 		// In non-strict SQL mode.
@@ -1252,6 +1252,10 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 				}
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.FastGenByArgs())
+			case ast.ColumnOptionSRID:
+				if err = setColumnSRID(col, v); err != nil {
+					return nil, nil, errors.Trace(err)
+				}
 			case ast.ColumnOptionCheck:
 				if !variable.EnableCheckConstraint.Load() {
 					ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackError("the switch of check constraint is off"))
@@ -1278,6 +1282,22 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 	return col, constraints, nil
 }
 
+// setColumnSRID restricts the SRS of the values of a geometry column.
+func setColumnSRID(col *table.Column, option *ast.ColumnOption) error {
+	if col.GetType() != mysql.TypeGeometry {
+		return dbterror.ErrWrongUsage.GenWithStackByArgs("SRID", "non-geometry column")
+	}
+	if option.SRID > math.MaxUint32 {
+		return types.ErrSRSNotFound.GenWithStackByArgs(option.SRID)
+	}
+	srid := uint32(option.SRID)
+	if _, err := types.GetSpatialReferenceSystem(srid); err != nil {
+		return err
+	}
+	col.SRID = &srid
+	return nil
+}
+
 func restoreFuncCall(expr *ast.FuncCallExpr) (string, error) {
 	var sb strings.Builder
 	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
@@ -2112,10 +2132,28 @@ func BuildTableInfo(
 	}
 	foreignKeyID := tbInfo.MaxForeignKeyID
 	for _, constr := range constraints {
-		// Build hidden columns if necessary.
-		hiddenCols, err := buildHiddenColumnInfoWithCheck(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
-		if err != nil {
-			return nil, err
+		indexOption := constr.Option
+		if constr.Tp == ast.ConstraintFulltext {
+			// FULLTEXT index can't be built on expressions, check it before building hidden columns.
+			if _, err := buildFullTextIndexColumns(tbInfo.Columns, constr.Keys); err != nil {
+				return nil, errors.Trace(err)
+			}
+			indexOption = fullTextIndexOption(constr.Option)
+		}
+		var hiddenCols []*model.ColumnInfo
+		if constr.Tp == ast.ConstraintVector {
+			// VECTOR index is defined on a distance function, but it indexes the column itself.
+			indexOption = vectorIndexOption(constr.Option)
+			if _, _, err := buildVectorIndexColumns(tbInfo.Columns, constr.Keys, indexOption); err != nil {
+				return nil, errors.Trace(err)
+			}
+		} else {
+			// Build hidden columns if necessary.
+			var err error
+			hiddenCols, err = buildHiddenColumnInfoWithCheck(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
+			if err != nil {
+				return nil, err
+			}
 		}
 		for _, hiddenCol := range hiddenCols {
 			hiddenCol.State = model.StatePublic
@@ -2174,11 +2212,6 @@ func BuildTableInfo(
 			}
 		}
 
-		if constr.Tp == ast.ConstraintFulltext {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.FastGenByArgs())
-			continue
-		}
-
 		var (
 			indexName       = constr.Name
 			primary, unique bool
@@ -2266,7 +2299,7 @@ func BuildTableInfo(
 			unique,
 			false,
 			constr.Keys,
-			constr.Option,
+			indexOption,
 			model.StatePublic,
 		)
 		if err != nil {
@@ -2508,6 +2541,7 @@ func BuildTableInfoWithLike(ctx sessionctx.Context, ident ast.Ident, referTblInf
 	tblInfo.Name = ident.Name
 	tblInfo.AutoIncID = 0
 	tblInfo.ForeignKeys = nil
+	tblInfo.Triggers = nil
 	// Ignore TiFlash replicas for temporary tables.
 	if s.TemporaryKeyword != ast.TemporaryNone {
 		tblInfo.TiFlashReplica = nil
@@ -2589,6 +2623,65 @@ func BuildSessionTemporaryTableInfo(ctx sessionctx.Context, is infoschema.InfoSc
 	return tbInfo, err
 }
 
+// SelectFieldColumnType converts the type of a select field to the type of the column created for it by
+// `CREATE TABLE ... SELECT` and `CREATE MATERIALIZED VIEW`.
+// Only the flags that are part of the column type are kept, keys, defaults and auto increment
+// attributes are not inherited, which is the same as MySQL.
+func SelectFieldColumnType(ft *types.FieldType) *types.FieldType {
+	tp := ft.Clone()
+	tp.SetFlag(tp.GetFlag() & (mysql.UnsignedFlag | mysql.BinaryFlag | mysql.ZerofillFlag))
+	switch tp.GetType() {
+	case mysql.TypeNull:
+		// `CREATE TABLE t SELECT NULL AS a` creates `a` as BINARY(0) in MySQL.
+		tp = types.NewFieldType(mysql.TypeString)
+		tp.SetFlen(0)
+		tp.SetCharset(charset.CharsetBin)
+		tp.SetCollate(charset.CollationBin)
+		tp.AddFlag(mysql.BinaryFlag)
+	case mysql.TypeDate, mysql.TypeJSON, mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		// These types don't take a length in the column definition.
+		tp.SetFlen(types.UnspecifiedLength)
+	case mysql.TypeString:
+		if tp.GetFlen() <= mysql.MaxFieldCharLength {
+			break
+		}
+		fallthrough
+	case mysql.TypeVarString, mysql.TypeVarchar:
+		tp.SetType(mysql.TypeVarchar)
+		if tp.GetFlen() == types.UnspecifiedLength {
+			tp.SetType(mysql.TypeLongBlob)
+			break
+		}
+		maxLen := 1
+		if cs, err := charset.GetCharsetInfo(tp.GetCharset()); err == nil {
+			maxLen = cs.Maxlen
+		}
+		// Convert the overlong VARCHAR to TEXT/BLOB like the auto conversion of CREATE TABLE does.
+		switch byteLen := tp.GetFlen() * maxLen; {
+		case byteLen <= mysql.MaxFieldVarCharLength:
+		case byteLen <= 1<<24-1:
+			tp.SetType(mysql.TypeMediumBlob)
+			tp.SetFlen(types.UnspecifiedLength)
+		default:
+			tp.SetType(mysql.TypeLongBlob)
+			tp.SetFlen(types.UnspecifiedLength)
+		}
+	case mysql.TypeNewDecimal:
+		if tp.GetFlen() > mysql.MaxDecimalWidth {
+			tp.SetFlen(mysql.MaxDecimalWidth)
+		}
+		if tp.GetDecimal() > mysql.MaxDecimalScale {
+			tp.SetDecimal(mysql.MaxDecimalScale)
+		}
+	case mysql.TypeFloat, mysql.TypeDouble:
+		if tp.GetDecimal() == types.UnspecifiedLength || tp.GetFlen() > mysql.MaxFloatingTypeWidth {
+			tp.SetFlen(types.UnspecifiedLength)
+			tp.SetDecimal(types.UnspecifiedLength)
+		}
+	}
+	return tp
+}
+
 // BuildTableInfoWithStmt builds model.TableInfo from a SQL statement without validity check
 func BuildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCharset, dbCollate string, placementPolicyRef *model.PolicyRefInfo) (*model.TableInfo, error) {
 	colDefs := s.Cols
@@ -3288,7 +3381,7 @@ func checkPartitionByList(ctx sessionctx.Context, tbInfo *model.TableInfo) error
 
 func isValidKeyPartitionColType(fieldType types.FieldType) bool {
 	switch fieldType.GetType() {
-	case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeJSON, mysql.TypeGeometry:
+	case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeJSON, mysql.TypeGeometry, mysql.TypeTiDBVectorFloat32:
 		return false
 	default:
 		return true
@@ -3944,7 +4037,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 			case ast.ConstraintPrimaryKey:
 				err = d.CreatePrimaryKey(sctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, constr.Option)
 			case ast.ConstraintFulltext:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt)
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
+			case ast.ConstraintVector:
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeVector, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
 			case ast.ConstraintCheck:
 				if !variable.EnableCheckConstraint.Load() {
 					sctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackError("the switch of check constraint is off"))
@@ -5655,6 +5752,10 @@ func ProcessModifyColumnOptions(ctx sessionctx.Context, col *table.Column, optio
 			return errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't modify with references"))
 		case ast.ColumnOptionFulltext:
 			return errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't modify with full text"))
+		case ast.ColumnOptionSRID:
+			if err = setColumnSRID(col, opt); err != nil {
+				return errors.Trace(err)
+			}
 		case ast.ColumnOptionCheck:
 			return errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't modify with check"))
 		// Ignore ColumnOptionAutoRandom. It will be handled later.
@@ -5844,6 +5945,10 @@ func GetModifiableColumnJob(
 	if err = ProcessModifyColumnOptions(sctx, newCol, specNewColumn.Options); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The existing values aren't checked against the SRID, so it can only be kept or dropped.
+	if newCol.SRID != nil && (col.SRID == nil || *col.SRID != *newCol.SRID) {
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't add or change the SRID of a column")
+	}
 
 	if err = checkModifyTypes(&col.FieldType, &newCol.FieldType, isColumnWithIndex(col.Name.L, t.Meta().Indices)); err != nil {
 		if strings.Contains(err.Error(), "Unsupported modifying collation") {
@@ -6702,10 +6807,6 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
@@ -6735,10 +6836,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
@@ -6929,9 +7029,10 @@ const (
 	tableObject objectType = iota
 	viewObject
 	sequenceObject
+	materializedViewObject
 )
 
-// dropTableObject provides common logic to DROP TABLE/VIEW/SEQUENCE.
+// dropTableObject provides common logic to DROP TABLE/VIEW/SEQUENCE/MATERIALIZED VIEW.
 func (d *ddl) dropTableObject(
 	ctx sessionctx.Context,
 	objects []*ast.TableName,
@@ -6968,6 +7069,11 @@ func (d *ddl) dropTableObject(
 	case sequenceObject:
 		dropExistErr = infoschema.ErrSequenceDropExists
 		jobType = model.ActionDropSequence
+	case materializedViewObject:
+		// The materialized view and its log table are stored as normal tables.
+		dropExistErr = infoschema.ErrTableDropExists
+		jobType = model.ActionDropTable
+		jobArgs = []any{[]ast.Ident{}, false}
 	}
 	for _, tn := range objects {
 		fullti := ast.Ident{Schema: tn.Schema, Name: tn.Name}
@@ -6999,6 +7105,9 @@ func (d *ddl) dropTableObject(
 				notExistTables = append(notExistTables, fullti.String())
 				continue
 			}
+			if tableInfo.Meta().IsMaterializedView() || tableInfo.Meta().IsMaterializedViewLog {
+				return dbterror.ErrWrongObject.GenWithStackByArgs(fullti.Schema, fullti.Name, "BASE TABLE")
+			}
 
 			tempTableType := tableInfo.Meta().TempTableType
 			if config.CheckTableBeforeDrop && tempTableType == model.TempTableNone {
@@ -7030,6 +7139,10 @@ func (d *ddl) dropTableObject(
 				}
 				return err
 			}
+		case materializedViewObject:
+			if !tableInfo.Meta().IsMaterializedView() {
+				return dbterror.ErrWrongObject.GenWithStackByArgs(fullti.Schema, fullti.Name, "MATERIALIZED VIEW")
+			}
 		}
 
 		job := &model.Job{
@@ -7053,6 +7166,11 @@ func (d *ddl) dropTableObject(
 		} else if err != nil {
 			return errors.Trace(err)
 		}
+		if tableObjectType == materializedViewObject {
+			if err = d.dropMaterializedViewLog(ctx, schema, tableInfo.Meta()); err != nil {
+				return errors.Trace(err)
+			}
+		}
 
 		// unlock table after drop
 		if tableObjectType != tableObject {
@@ -7087,6 +7205,41 @@ func (d *ddl) DropView(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err err
 	return d.dropTableObject(ctx, stmt.Tables, stmt.IfExists, viewObject)
 }
 
+// DropMaterializedView drops a materialized view and its log table.
+func (d *ddl) DropMaterializedView(ctx sessionctx.Context, stmt *ast.DropMaterializedViewStmt) (err error) {
+	return d.dropTableObject(ctx, []*ast.TableName{stmt.ViewName}, stmt.IfExists, materializedViewObject)
+}
+
+// dropMaterializedViewLog drops the log table of a dropped materialized view.
+func (d *ddl) dropMaterializedViewLog(ctx sessionctx.Context, schema *model.DBInfo, mv *model.TableInfo) error {
+	logName := mv.MaterializedView.LogTable
+	if logName.L == "" {
+		return nil
+	}
+	logInfo, err := d.GetInfoSchemaWithInterceptor(ctx).TableInfoByName(schema.Name, logName)
+	if err != nil || !logInfo.IsMaterializedViewLog {
+		return nil
+	}
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        logInfo.ID,
+		SchemaName:     schema.Name.L,
+		SchemaState:    schema.State,
+		TableName:      logInfo.Name.L,
+		Type:           model.ActionDropTable,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{[]ast.Ident{}, false},
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	if infoschema.ErrTableNotExists.Equal(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
 func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
@@ -7095,6 +7248,9 @@ func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
 	if tb.Meta().IsView() || tb.Meta().IsSequence() {
 		return infoschema.ErrTableNotExists.GenWithStackByArgs(schema.Name.O, tb.Meta().Name.O)
 	}
+	if tb.Meta().IsMaterializedView() || tb.Meta().IsMaterializedViewLog {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(schema.Name.O, tb.Meta().Name.O, "BASE TABLE")
+	}
 	if tb.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Truncate Table")
 	}
@@ -7187,6 +7343,10 @@ func (d *ddl) renameTable(ctx sessionctx.Context, oldIdent, newIdent ast.Ident,
 		if tbl.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 			return errors.Trace(dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Rename Table"))
 		}
+		// The triggers must be in the same schema as their table.
+		if len(tbl.Meta().Triggers) > 0 && schemas[0].ID != schemas[1].ID {
+			return errors.Trace(dbterror.ErrTrgInWrongSchema)
+		}
 	}
 
 	job := &model.Job{
@@ -7236,6 +7396,9 @@ func (d *ddl) renameTables(ctx sessionctx.Context, oldIdents, newIdents []ast.Id
 			if t.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 				return errors.Trace(dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Rename Tables"))
 			}
+			if len(t.Meta().Triggers) > 0 && schemas[0].ID != schemas[1].ID {
+				return errors.Trace(dbterror.ErrTrgInWrongSchema)
+			}
 		}
 
 		tableIDs = append(tableIDs, tableID)
@@ -7609,9 +7772,17 @@ func (*ddl) addHypoIndexIntoCtx(ctx sessionctx.Context, schemaName, tableName mo
 
 func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
-	// not support Spatial and FullText index
-	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
-		return dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
+	// not support Spatial index
+	if keyType == ast.IndexKeyTypeSpatial {
+		return dbterror.ErrUnsupportedIndexType.GenWithStack("SPATIAL index is not supported")
+	}
+	fullText := keyType == ast.IndexKeyTypeFullText
+	if fullText {
+		indexOption = fullTextIndexOption(indexOption)
+	}
+	vector := keyType == ast.IndexKeyTypeVector
+	if vector {
+		indexOption = vectorIndexOption(indexOption)
 	}
 	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
@@ -7653,10 +7824,37 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 
 	tblInfo := t.Meta()
 
+	if fullText {
+		// FULLTEXT index can't be built on expressions, check it before building hidden columns.
+		if _, err = buildFullTextIndexColumns(tblInfo.Columns, indexPartSpecifications); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err = buildFullTextIndexInfo(indexOption.ParserName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if vector {
+		// VECTOR index is defined on a distance function but indexes the column itself, replace the index part with
+		// the column since the expression can't be decoded from the DDL job.
+		colName, distanceFunc, err := extractVectorIndexPart(indexPartSpecifications, indexOption)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		indexOption.DistanceFunc = distanceFunc
+		indexPartSpecifications = []*ast.IndexPartSpecification{{
+			Column: &ast.ColumnName{Name: colName},
+			Length: types.UnspecifiedLength,
+		}}
+	}
+
 	// Build hidden columns if necessary.
-	hiddenCols, err := buildHiddenColumnInfoWithCheck(ctx, indexPartSpecifications, indexName, t.Meta(), t.Cols())
-	if err != nil {
-		return err
+	var hiddenCols []*model.ColumnInfo
+	if !vector {
+		hiddenCols, err = buildHiddenColumnInfoWithCheck(ctx, indexPartSpecifications, indexName, t.Meta(), t.Cols())
+		if err != nil {
+			return err
+		}
 	}
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + len(hiddenCols)); err != nil {
 		return errors.Trace(err)
@@ -7671,7 +7869,14 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// After DDL job is put to the queue, and if the check fail, TiDB will run the DDL cancel logic.
 	// The recover step causes DDL wait a few seconds, makes the unit test painfully slow.
 	// For same reason, decide whether index is global here.
-	indexColumns, _, err := buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
+	var indexColumns []*model.IndexColumn
+	if fullText {
+		indexColumns, err = buildFullTextIndexColumns(finalColumns, indexPartSpecifications)
+	} else if vector {
+		indexColumns, _, err = buildVectorIndexColumns(finalColumns, indexPartSpecifications, indexOption)
+	} else {
+		indexColumns, _, err = buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -8632,6 +8837,109 @@ func (d *ddl) DropSequence(ctx sessionctx.Context, stmt *ast.DropSequenceStmt) (
 	return d.dropTableObject(ctx, stmt.Sequences, stmt.IfExists, sequenceObject)
 }
 
+func (d *ddl) CreateTrigger(ctx sessionctx.Context, stmt *ast.CreateTriggerStmt) error {
+	if stmt.TriggerName.Schema.L != stmt.Table.Schema.L {
+		return errors.Trace(dbterror.ErrTrgInWrongSchema)
+	}
+	ident := ast.Ident{Schema: stmt.Table.Schema, Name: stmt.Table.Name}
+	schema, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if util.IsMemOrSysDB(schema.Name.L) {
+		return errors.Trace(dbterror.ErrNoTriggersOnSystemSchema)
+	}
+	tblInfo := tbl.Meta()
+	if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone {
+		return dbterror.ErrTrgOnViewOrTempTable.GenWithStackByArgs(tblInfo.Name.O)
+	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	if findTriggerTable(is, schema.Name, stmt.TriggerName.Name) != nil {
+		err = dbterror.ErrTrgAlreadyExists.FastGenByArgs()
+		if stmt.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	sessVars := ctx.GetSessionVars()
+	charsetClient, _ := sessVars.GetSystemVar(variable.CharacterSetClient)
+	sqlMode, _ := sessVars.GetSystemVar(variable.SQLModeVar)
+	_, collationConnection := sessVars.GetCharsetInfo()
+	dbCollation := schema.Collate
+	if dbCollation == "" {
+		dbCollation = mysql.DefaultCollationName
+	}
+	trigger := &model.TriggerInfo{
+		Name:                stmt.TriggerName.Name,
+		Timing:              stmt.Timing,
+		Event:               stmt.Event,
+		Body:                stmt.Body.Text(),
+		Definer:             stmt.Definer,
+		SQLMode:             sqlMode,
+		CharsetClient:       charsetClient,
+		CollationConnection: collationConnection,
+		DBCollation:         dbCollation,
+		Created:             time.Now(),
+	}
+	var orderName model.CIStr
+	var follows bool
+	if stmt.Order != nil {
+		orderName, follows = stmt.Order.TriggerName, stmt.Order.Follows
+		if _, err = triggerPosition(tblInfo, trigger, orderName, follows); err != nil {
+			return err
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        tblInfo.ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tblInfo.Name.L,
+		Type:           model.ActionCreateTrigger,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{trigger, orderName, follows},
+		CDCWriteSource: sessVars.CDCWriteSource,
+		SQLMode:        sessVars.SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) DropTrigger(ctx sessionctx.Context, stmt *ast.DropTriggerStmt) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(stmt.TriggerName.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(stmt.TriggerName.Schema)
+	}
+	tblInfo := findTriggerTable(is, schema.Name, stmt.TriggerName.Name)
+	if tblInfo == nil {
+		err := dbterror.ErrTrgDoesNotExist.FastGenByArgs()
+		if stmt.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        tblInfo.ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tblInfo.Name.L,
+		Type:           model.ActionDropTrigger,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{stmt.TriggerName.Name},
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	err := d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) AlterIndexVisibility(ctx sessionctx.Context, ident ast.Ident, indexName model.CIStr, visibility ast.IndexVisibility) error {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
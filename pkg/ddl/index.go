@@ -67,6 +67,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/size"
 	"github.com/pingcap/tidb/pkg/util/sqlexec"
 	"github.com/pingcap/tidb/pkg/util/stringutil"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
@@ -157,6 +158,131 @@ func buildIndexColumns(ctx sessionctx.Context, columns []*model.ColumnInfo, inde
 	return idxParts, mvIndex, nil
 }
 
+// buildFullTextIndexColumns builds the columns of a FULLTEXT index. Unlike the normal index, a FULLTEXT
+// index stores the tokens of the text instead of the column values, so it accepts TEXT columns without
+// prefix length and isn't limited by the max index length.
+func buildFullTextIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*ast.IndexPartSpecification) ([]*model.IndexColumn, error) {
+	idxParts := make([]*model.IndexColumn, 0, len(indexPartSpecifications))
+	for _, ip := range indexPartSpecifications {
+		if ip.Expr != nil {
+			return nil, dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT index on expression is not supported")
+		}
+		col := model.FindColumnInfo(columns, ip.Column.Name.L)
+		if col == nil {
+			return nil, dbterror.ErrKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
+		}
+		tp := col.FieldType.GetType()
+		if !(types.IsTypeChar(tp) || types.IsTypeVarchar(tp) || types.IsTypeBlob(tp)) || col.GetCharset() == charset.CharsetBin {
+			return nil, dbterror.ErrBadFtColumn.GenWithStackByArgs(col.Name.O)
+		}
+		if ip.Length != types.UnspecifiedLength {
+			return nil, errors.Trace(dbterror.ErrIncorrectPrefixKey)
+		}
+		idxParts = append(idxParts, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: types.UnspecifiedLength,
+		})
+	}
+	return idxParts, nil
+}
+
+// buildFullTextIndexInfo builds the FULLTEXT information from the `WITH PARSER` option.
+func buildFullTextIndexInfo(parserName model.CIStr) (*model.FullTextIndexInfo, error) {
+	switch parserName.L {
+	case "":
+		return &model.FullTextIndexInfo{ParserType: model.FullTextParserTypeStandard}, nil
+	case "ngram":
+		return &model.FullTextIndexInfo{ParserType: model.FullTextParserTypeNgram}, nil
+	default:
+		return nil, dbterror.ErrFtParserNotDefined.GenWithStackByArgs(parserName.O)
+	}
+}
+
+// fullTextIndexOption returns a copy of the index option with the FULLTEXT index type.
+func fullTextIndexOption(indexOption *ast.IndexOption) *ast.IndexOption {
+	opt := &ast.IndexOption{}
+	if indexOption != nil {
+		*opt = *indexOption
+	}
+	opt.Tp = model.IndexTypeFullText
+	return opt
+}
+
+// buildVectorIndexColumns builds the column and the VECTOR information of a VECTOR index. The index is defined on a
+// distance function like `VEC_COSINE_DISTANCE(col)`, it stores the partition of the vector instead of the column
+// value, so only the queries ordering by the same distance can use it.
+func buildVectorIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) ([]*model.IndexColumn, *model.VectorIndexInfo, error) {
+	colName, distanceFunc, err := extractVectorIndexPart(indexPartSpecifications, indexOption)
+	if err != nil {
+		return nil, nil, err
+	}
+	metric, ok := vectorindex.DistanceMetricOf(distanceFunc.L)
+	if !ok {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("distance function %s is not supported by VECTOR index", distanceFunc.O)
+	}
+	col := model.FindColumnInfo(columns, colName.L)
+	if col == nil {
+		return nil, nil, dbterror.ErrKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", colName)
+	}
+	if col.GetType() != mysql.TypeTiDBVectorFloat32 || col.GetFlen() == types.UnspecifiedLength {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index can only be defined on a VECTOR(n) column with a fixed dimension")
+	}
+	idxParts := []*model.IndexColumn{{
+		Name:   col.Name,
+		Offset: col.Offset,
+		Length: types.UnspecifiedLength,
+	}}
+	vectorInfo := &model.VectorIndexInfo{
+		Dimension:      col.GetFlen(),
+		DistanceMetric: metric,
+		PartitionBits:  vectorindex.DefaultPartitionBits,
+		Seed:           vectorindex.DefaultSeed,
+	}
+	return idxParts, vectorInfo, nil
+}
+
+// extractVectorIndexPart returns the column and the distance function of a VECTOR index. The index part is either
+// the distance function of the column, or the column itself with the distance function in the index option.
+func extractVectorIndexPart(indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) (colName, distanceFunc model.CIStr, err error) {
+	if len(indexPartSpecifications) != 1 {
+		return colName, distanceFunc, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be defined on exactly one distance function")
+	}
+	if indexPartSpecifications[0].Expr == nil {
+		return indexPartSpecifications[0].Column.Name, indexOption.DistanceFunc, nil
+	}
+	expr := indexPartSpecifications[0].Expr
+	for {
+		paren, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+	fn, ok := expr.(*ast.FuncCallExpr)
+	if !ok {
+		return colName, distanceFunc, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be defined on a distance function like VEC_COSINE_DISTANCE(col)")
+	}
+	var colExpr *ast.ColumnNameExpr
+	if len(fn.Args) == 1 {
+		colExpr, ok = fn.Args[0].(*ast.ColumnNameExpr)
+	}
+	if colExpr == nil || !ok {
+		return colName, distanceFunc, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be defined on a distance function of one column")
+	}
+	return colExpr.Name.Name, fn.FnName, nil
+}
+
+// vectorIndexOption returns a copy of the index option with the VECTOR index type.
+func vectorIndexOption(indexOption *ast.IndexOption) *ast.IndexOption {
+	opt := &ast.IndexOption{}
+	if indexOption != nil {
+		*opt = *indexOption
+	}
+	opt.Tp = model.IndexTypeVector
+	return opt
+}
+
 // CheckPKOnGeneratedColumn checks the specification of PK is valid.
 func CheckPKOnGeneratedColumn(tblInfo *model.TableInfo, indexPartSpecifications []*ast.IndexPartSpecification) (*model.ColumnInfo, error) {
 	var lastCol *model.ColumnInfo
@@ -204,6 +330,19 @@ func checkIndexColumn(ctx sessionctx.Context, col *model.ColumnInfo, indexColumn
 		return errors.Trace(dbterror.ErrJSONUsedAsKey.GenWithStackByArgs(col.Name.O))
 	}
 
+	// Geometry column cannot index, the SPATIAL index isn't supported either.
+	if col.FieldType.GetType() == mysql.TypeGeometry {
+		if col.Hidden {
+			return dbterror.ErrFunctionalIndexOnJSONOrGeometryFunction
+		}
+		return errors.Trace(dbterror.ErrUnsupportedIndexType.GenWithStack("index on geometry column '%s' is not supported", col.Name.O))
+	}
+
+	// Vector column can only be indexed by the VECTOR index.
+	if col.FieldType.GetType() == mysql.TypeTiDBVectorFloat32 {
+		return errors.Trace(dbterror.ErrUnsupportedIndexType.GenWithStack("index on vector column '%s' is not supported, use VECTOR index instead", col.Name.O))
+	}
+
 	// Length must be specified and non-zero for BLOB and TEXT column indexes.
 	if types.IsTypeBlob(col.FieldType.GetType()) {
 		if indexColumnLen == types.UnspecifiedLength {
@@ -309,20 +448,42 @@ func BuildIndexInfo(
 		return nil, errors.Trace(err)
 	}
 
-	idxColumns, mvIndex, err := buildIndexColumns(ctx, allTableColumns, indexPartSpecifications)
-	if err != nil {
-		return nil, errors.Trace(err)
+	var (
+		idxColumns   []*model.IndexColumn
+		mvIndex      bool
+		fullTextInfo *model.FullTextIndexInfo
+		vectorInfo   *model.VectorIndexInfo
+		err          error
+	)
+	if indexOption != nil && indexOption.Tp == model.IndexTypeVector {
+		if idxColumns, vectorInfo, err = buildVectorIndexColumns(allTableColumns, indexPartSpecifications, indexOption); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if indexOption != nil && indexOption.Tp == model.IndexTypeFullText {
+		if idxColumns, err = buildFullTextIndexColumns(allTableColumns, indexPartSpecifications); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fullTextInfo, err = buildFullTextIndexInfo(indexOption.ParserName); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		idxColumns, mvIndex, err = buildIndexColumns(ctx, allTableColumns, indexPartSpecifications)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Create index info.
 	idxInfo := &model.IndexInfo{
-		Name:    indexName,
-		Columns: idxColumns,
-		State:   state,
-		Primary: isPrimary,
-		Unique:  isUnique,
-		Global:  isGlobal,
-		MVIndex: mvIndex,
+		Name:         indexName,
+		Columns:      idxColumns,
+		State:        state,
+		Primary:      isPrimary,
+		Unique:       isUnique,
+		Global:       isGlobal,
+		MVIndex:      mvIndex,
+		FullTextInfo: fullTextInfo,
+		VectorInfo:   vectorInfo,
 	}
 
 	if indexOption != nil {
@@ -243,7 +243,8 @@ func (d *ddl) checkHistoryJobInTest(ctx sessionctx.Context, historyJob *model.Jo
 			_, isCreateTable := st.(*ast.CreateTableStmt)
 			_, isCreateSeq := st.(*ast.CreateSequenceStmt)
 			_, isCreateView := st.(*ast.CreateViewStmt)
-			if !isCreateTable && !isCreateSeq && !isCreateView {
+			_, isCreateMView := st.(*ast.CreateMaterializedViewStmt)
+			if !isCreateTable && !isCreateSeq && !isCreateView && !isCreateMView {
 				panic(fmt.Sprintf("job ID %d, parse ddl job failed, query %s", historyJob.ID, historyJob.Query))
 			}
 		default:
@@ -413,6 +413,24 @@ func (*Checker) AlterSequence(_ sessionctx.Context, _ *ast.AlterSequenceStmt) er
 	panic("implement me")
 }
 
+// DropMaterializedView implements the DDL interface.
+func (*Checker) DropMaterializedView(_ sessionctx.Context, _ *ast.DropMaterializedViewStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// CreateTrigger implements the DDL interface.
+func (*Checker) CreateTrigger(_ sessionctx.Context, _ *ast.CreateTriggerStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// DropTrigger implements the DDL interface.
+func (*Checker) DropTrigger(_ sessionctx.Context, _ *ast.DropTriggerStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 // CreatePlacementPolicy implements the DDL interface.
 func (*Checker) CreatePlacementPolicy(_ sessionctx.Context, _ *ast.CreatePlacementPolicyStmt) error {
 	//TODO implement me
@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"slices"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+func onCreateTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	trigger := &model.TriggerInfo{}
+	var orderName model.CIStr
+	var follows bool
+	if err := job.DecodeArgs(trigger, &orderName, &follows); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if findTrigger(tblInfo, trigger.Name) >= 0 {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(dbterror.ErrTrgAlreadyExists)
+	}
+	pos, err := triggerPosition(tblInfo, trigger, orderName, follows)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo.Triggers = slices.Insert(tblInfo.Triggers, pos, trigger)
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var name model.CIStr
+	if err := job.DecodeArgs(&name); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	idx := findTrigger(tblInfo, name)
+	if idx < 0 {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(dbterror.ErrTrgDoesNotExist)
+	}
+	tblInfo.Triggers = slices.Delete(tblInfo.Triggers, idx, idx+1)
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// findTrigger returns the offset of the trigger in the table, or -1 if it isn't found.
+func findTrigger(tblInfo *model.TableInfo, name model.CIStr) int {
+	return slices.IndexFunc(tblInfo.Triggers, func(trigger *model.TriggerInfo) bool {
+		return trigger.Name.L == name.L
+	})
+}
+
+// findTriggerTable returns the table of the trigger, the trigger names are unique in a schema.
+func findTriggerTable(is infoschema.InfoSchema, schema, name model.CIStr) *model.TableInfo {
+	for _, tblInfo := range is.SchemaTableInfos(schema) {
+		if findTrigger(tblInfo, name) >= 0 {
+			return tblInfo
+		}
+	}
+	return nil
+}
+
+// triggerPosition returns the offset to insert the new trigger. The triggers of the same timing and event are fired
+// in their order, a new trigger is fired after the existing ones unless FOLLOWS or PRECEDES is specified.
+func triggerPosition(tblInfo *model.TableInfo, trigger *model.TriggerInfo, orderName model.CIStr, follows bool) (int, error) {
+	if orderName.L == "" {
+		return len(tblInfo.Triggers), nil
+	}
+	for i, other := range tblInfo.Triggers {
+		if other.Name.L != orderName.L || other.Timing != trigger.Timing || other.Event != trigger.Event {
+			continue
+		}
+		if follows {
+			return i + 1, nil
+		}
+		return i, nil
+	}
+	return 0, dbterror.ErrReferencedTrgDoesNotExist.GenWithStackByArgs(orderName.O)
+}
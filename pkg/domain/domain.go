@@ -55,6 +55,7 @@ import (
 	"github.com/pingcap/tidb/pkg/meta"
 	"github.com/pingcap/tidb/pkg/meta/autoid"
 	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/mview"
 	"github.com/pingcap/tidb/pkg/owner"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
@@ -171,10 +172,15 @@ type Domain struct {
 		sync.RWMutex
 		expiredTimeStamp types.Time
 	}
+	instancePlanCache struct {
+		initOnce sync.Once
+		cache    sessionctx.InstancePlanCache
+	}
 
 	logBackupAdvancer        *daemon.OwnerDaemon
 	historicalStatsWorker    *HistoricalStatsWorker
 	ttlJobManager            atomic.Pointer[ttlworker.JobManager]
+	mviewRefreshManager      atomic.Pointer[mview.RefreshManager]
 	runawayManager           *resourcegroup.RunawayManager
 	runawaySyncer            *runawaySyncer
 	resourceGroupsController *rmclient.ResourceGroupsController
@@ -542,6 +548,20 @@ func (do *Domain) SetExpiredTimeStamp4PC(time types.Time) {
 	do.expiredTimeStamp4PC.expiredTimeStamp = time
 }
 
+// NewInstancePlanCache creates the plan cache shared by the sessions of the instance.
+// It's set by the planner to avoid the import cycle.
+var NewInstancePlanCache func() sessionctx.InstancePlanCache
+
+// InstancePlanCache returns the plan cache shared by the sessions of this domain, it returns nil if there isn't one.
+func (do *Domain) InstancePlanCache() sessionctx.InstancePlanCache {
+	do.instancePlanCache.initOnce.Do(func() {
+		if NewInstancePlanCache != nil {
+			do.instancePlanCache.cache = NewInstancePlanCache()
+		}
+	})
+	return do.instancePlanCache.cache
+}
+
 // DDL gets DDL from domain.
 func (do *Domain) DDL() ddl.DDL {
 	return do.ddl
@@ -1044,6 +1064,9 @@ func (do *Domain) Close() {
 			logutil.BgLogger().Info("ttlJobManager exited.")
 		}
 	}
+	if mviewRefreshManager := do.mviewRefreshManager.Load(); mviewRefreshManager != nil {
+		mviewRefreshManager.Stop()
+	}
 	do.releaseServerID(context.Background())
 	close(do.exit)
 	if do.etcdClient != nil {
@@ -1920,6 +1943,10 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if err == nil && variable.TiDBOptOn(optVal) {
 					bindHandle.CaptureBaselines()
 				}
+				evolveVal, err := do.GetGlobalVar(variable.TiDBAutoPlanEvolution)
+				if err == nil && !strings.EqualFold(evolveVal, variable.Off) {
+					bindHandle.EvolveBaselines(strings.EqualFold(evolveVal, variable.On))
+				}
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
 					continue
@@ -2442,6 +2469,10 @@ func (do *Domain) updateStatsWorker(_ sessionctx.Context, owner owner.Manager) {
 			if err != nil {
 				logutil.BgLogger().Debug("dump column stats usage failed", zap.Error(err))
 			}
+			err = statsHandle.DumpCardinalityFeedbackToKV()
+			if err != nil {
+				logutil.BgLogger().Debug("dump cardinality feedback failed", zap.Error(err))
+			}
 
 		case <-readMemTricker.C:
 			memory.ForceReadMemStats()
@@ -2974,6 +3005,13 @@ func (do *Domain) TTLJobManager() *ttlworker.JobManager {
 	return do.ttlJobManager.Load()
 }
 
+// StartMViewRefreshManager creates and starts the manager of the scheduled refresh of the materialized views.
+func (do *Domain) StartMViewRefreshManager() {
+	mviewRefreshManager := mview.NewRefreshManager(do.sysSessionPool, do.etcdClient, do.ddl.OwnerManager().IsOwner)
+	do.mviewRefreshManager.Store(mviewRefreshManager)
+	mviewRefreshManager.Start()
+}
+
 // StopAutoAnalyze stops (*Domain).autoAnalyzeWorker to launch new auto analyze jobs.
 func (do *Domain) StopAutoAnalyze() {
 	do.stopAutoAnalyze.Store(true)
@@ -823,6 +823,7 @@ const (
 	ErrRowInWrongPartition                                   = 1863
 	ErrErrorLast                                             = 1863
 	ErrForeignKeyCascadeDepthExceeded                        = 3008
+	ErrReferencedTrgDoesNotExist                             = 3011
 	ErrInvalidFieldSize                                      = 3013
 	ErrPasswordExpireAnonymousUser                           = 3016
 	ErrInvalidArgumentForLogarithm                           = 3020
@@ -831,9 +832,13 @@ const (
 	ErrUserLockWrongName                                     = 3057
 	ErrUserLockDeadlock                                      = 3058
 	ErrIncorrectType                                         = 3064
+	ErrGISDifferentSRIDs                                     = 3033
+	ErrGISUnsupportedArgument                                = 3034
+	ErrGISInvalidData                                        = 3037
 	ErrFieldInOrderNotSelect                                 = 3065
 	ErrAggregateInOrderNotSelect                             = 3066
 	ErrInvalidJSONData                                       = 3069
+	ErrInvalidGeoJSONUnspecified                             = 3074
 	ErrGeneratedColumnFunctionIsNotAllowed                   = 3102
 	ErrUnsupportedAlterInplaceOnVirtualColumn                = 3103
 	ErrWrongFKOptionForGeneratedColumn                       = 3104
@@ -863,6 +868,7 @@ const (
 	ErrPKIndexCantBeInvisible                                = 3522
 	ErrGrantRole                                             = 3523
 	ErrRoleNotGranted                                        = 3530
+	ErrSRSNotFound                                           = 3548
 	ErrLockAcquireFailAndNoWaitSet                           = 3572
 	ErrCTERecursiveRequiresUnion                             = 3573
 	ErrCTERecursiveRequiresNonRecursiveFirst                 = 3574
@@ -892,10 +898,19 @@ const (
 	ErrWindowFunctionIgnoresFrame                            = 3599
 	ErrInvalidNumberOfArgs                                   = 3601
 	ErrFieldInGroupingNotGroupBy                             = 3602
+	ErrLongitudeOutOfRange                                   = 3616
+	ErrLatitudeOutOfRange                                    = 3617
+	ErrNotImplementedForGeographicSRS                        = 3618
 	ErrIllegalPrivilegeLevel                                 = 3619
 	ErrCTEMaxRecursionDepth                                  = 3636
 	ErrNotHintUpdatable                                      = 3637
 	ErrExistsInHistoryPassword                               = 3638
+	ErrWrongSRIDForColumn                                    = 3643
+	ErrMissingJSONTableValue                                 = 3665
+	ErrWrongJSONTableValue                                   = 3666
+	ErrTFMustHaveAlias                                       = 3667
+	ErrTFForbiddenJoinType                                   = 3668
+	ErrJTValueOutOfRange                                     = 3669
 	ErrInvalidDefaultUTF8MB4Collation                        = 3721
 	ErrForeignKeyCannotDropParent                            = 3730
 	ErrForeignKeyCannotUseVirtualColumn                      = 3733
@@ -1136,6 +1151,18 @@ const (
 	ErrPausedDDLJob       = 8262
 	ErrBDRRestrictedDDL   = 8263
 
+	// Materialized view errors.
+	ErrMViewNotFastRefreshable     = 8264
+	ErrMViewInvalidRefreshInterval = 8265
+
+	// MERGE statement errors.
+	ErrMergeRowMatchedMoreThanOnce = 8266
+
+	// Vector errors.
+	ErrInvalidVector           = 8267
+	ErrVectorDimensionMismatch = 8268
+	ErrVectorDimensionNotFit   = 8269
+
 	// Resource group errors.
 	ErrResourceGroupExists                    = 8248
 	ErrResourceGroupNotExists                 = 8249
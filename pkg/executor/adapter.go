@@ -547,6 +547,10 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		terror.Log(exec.Close(e))
 		return nil, err
 	}
+	if call, ok := e.(*CallExec); ok {
+		// The result set of a procedure is known after it's executed when the executor is opened.
+		a.OutputNames = call.outputNames
+	}
 
 	isPessimistic := sctx.GetSessionVars().TxnCtx.IsPessimistic
 
@@ -556,6 +560,7 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}
 
 	a.prepareFKCascadeContext(e)
+	a.prepareTriggerContext(e)
 	if handled, result, err := a.handleNoDelay(ctx, e, isPessimistic); handled || err != nil {
 		return result, err
 	}
@@ -712,6 +717,14 @@ func (a *ExecStmt) handleForeignKeyCascade(ctx context.Context, fkc *FKCascadeEx
 	}
 }
 
+// prepareTriggerContext marks the statement fires triggers, so the executors keep the txn mem-buffer
+// consistent for the statements in the trigger bodies.
+func (a *ExecStmt) prepareTriggerContext(e exec.Executor) {
+	if exec, ok := e.(WithTrigger); ok && exec.HasTriggers() {
+		a.Ctx.GetSessionVars().StmtCtx.HasTriggers = true
+	}
+}
+
 // prepareFKCascadeContext records a transaction savepoint for foreign key cascade when this ExecStmt has foreign key
 // cascade behaviour and this ExecStmt is in transaction.
 func (a *ExecStmt) prepareFKCascadeContext(e exec.Executor) {
@@ -787,7 +800,8 @@ func (a *ExecStmt) handleNoDelay(ctx context.Context, e exec.Executor, isPessimi
 	// If the executor doesn't return any result to the client, we execute it without delay.
 	if toCheck.Schema().Len() == 0 {
 		handled = !isExplainAnalyze
-		if isPessimistic {
+		// The statements in a procedure lock the keys by themselves.
+		if _, isCall := toCheck.(*CallExec); isPessimistic && !isCall {
 			err := a.handlePessimisticDML(ctx, toCheck)
 			return handled, nil, err
 		}
@@ -1361,6 +1375,9 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	// `LowSlowQuery` and `SummaryStmt` must be called before recording `PrevStmt`.
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
+	if succ && a.Plan != nil && sessVars.EnableCardinalityFeedback && !sessVars.InRestrictedSQL {
+		plannercore.CollectCardinalityFeedback(a.Ctx.GetPlanCtx(), a.Plan)
+	}
 	a.observeStmtFinishedForTopSQL()
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
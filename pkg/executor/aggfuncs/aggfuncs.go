@@ -230,6 +230,14 @@ type SlidingWindowAggFunc interface {
 	Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error
 }
 
+// RowsReferringAggFunc is the interface of the window functions whose partial results refer to the rows passed to
+// UpdatePartialResult instead of copying their values, so the chunks of the rows must be kept in memory until the
+// partial results are reset.
+type RowsReferringAggFunc interface {
+	// ReferRows is only a marker.
+	ReferRows()
+}
+
 // MaxMinSlidingWindowAggFunc is the interface to evaluate the max/min agg function using sliding window
 type MaxMinSlidingWindowAggFunc interface {
 	// SetWindowStart sets the start position of window
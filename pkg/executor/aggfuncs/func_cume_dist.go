@@ -47,6 +47,9 @@ func (*cumeDist) ResetPartialResult(pr PartialResult) {
 	p.rows = p.rows[:0]
 }
 
+// ReferRows implements the RowsReferringAggFunc interface.
+func (*cumeDist) ReferRows() {}
+
 func (*cumeDist) UpdatePartialResult(_ AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4CumeDist)(pr)
 	p.rows = append(p.rows, rowsInGroup...)
@@ -49,6 +49,9 @@ func (*baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p.curIdx = 0
 }
 
+// ReferRows implements the RowsReferringAggFunc interface.
+func (*baseLeadLag) ReferRows() {}
+
 func (*baseLeadLag) UpdatePartialResult(_ AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
 	p.rows = append(p.rows, rowsInGroup...)
@@ -36,6 +36,9 @@ func (*percentRank) ResetPartialResult(partial PartialResult) {
 	p.rows = p.rows[:0]
 }
 
+// ReferRows implements the RowsReferringAggFunc interface.
+func (*percentRank) ReferRows() {}
+
 func (*percentRank) UpdatePartialResult(_ AggFuncUpdateContext, rowsInGroup []chunk.Row, partial PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4Rank)(partial)
 	p.rows = append(p.rows, rowsInGroup...)
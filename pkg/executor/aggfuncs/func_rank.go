@@ -49,6 +49,9 @@ func (*rank) ResetPartialResult(pr PartialResult) {
 	p.rows = p.rows[:0]
 }
 
+// ReferRows implements the RowsReferringAggFunc interface.
+func (*rank) ReferRows() {}
+
 func (*rank) UpdatePartialResult(_ AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4Rank)(pr)
 	p.rows = append(p.rows, rowsInGroup...)
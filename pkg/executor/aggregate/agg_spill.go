@@ -183,7 +183,7 @@ func (p *parallelHashAggSpillHelper) checkNeedSpill() bool {
 func (p *parallelHashAggSpillHelper) setNeedSpill(executorTracker *memory.Tracker, triggeredTracker *memory.Tracker) bool {
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	if hasEnoughDataToSpill(executorTracker, triggeredTracker) {
+	if memory.HasEnoughDataToSpill(executorTracker, triggeredTracker) {
 		p.lock.status = needSpill
 		p.lock.memoryConsumption = triggeredTracker.BytesConsumed()
 		p.lock.memoryQuota = triggeredTracker.GetBytesLimit()
@@ -319,11 +319,6 @@ func (p *parallelHashAggSpillHelper) processRow(context *processRowContext) (tot
 	return totalMemDelta, expandMem, nil
 }
 
-// Guarantee that processed data is at least 20% of the threshold, to avoid spilling too frequently.
-func hasEnoughDataToSpill(aggTracker *memory.Tracker, passedInTracker *memory.Tracker) bool {
-	return aggTracker.BytesConsumed() >= passedInTracker.GetBytesLimit()/5
-}
-
 // AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg.
 // If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
 // triggered.
@@ -335,7 +330,7 @@ type AggSpillDiskAction struct {
 
 // Action set HashAggExec spill mode.
 func (a *AggSpillDiskAction) Action(t *memory.Tracker) {
-	if atomic.LoadUint32(&a.e.inSpillMode) == 0 && hasEnoughDataToSpill(a.e.memTracker, t) && a.spillTimes < maxSpillTimes {
+	if atomic.LoadUint32(&a.e.inSpillMode) == 0 && memory.HasEnoughDataToSpill(a.e.memTracker, t) && a.spillTimes < maxSpillTimes {
 		a.spillTimes++
 		logutil.BgLogger().Info(spillLogInfo,
 			zap.Uint32("spillTimes", a.spillTimes),
@@ -60,6 +60,7 @@ type BatchPointGetExec struct {
 	txn            kv.Transaction
 	lock           bool
 	waitTime       int64
+	lockSkipLocked bool
 	inited         uint32
 	values         [][]byte
 	index          int
@@ -222,7 +223,9 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 	var indexKeys []kv.Key
 	var err error
 	batchGetter := e.batchGetter
-	rc := e.Ctx().GetSessionVars().IsPessimisticReadConsistency()
+	// SKIP LOCKED locks the existing rows after reading them like read committed does,
+	// so that each row can be filtered by the result of locking its own keys.
+	rc := e.Ctx().GetSessionVars().IsPessimisticReadConsistency() || e.lockSkipLocked
 	if e.idxInfo != nil && !isCommonHandleRead(e.tblInfo, e.idxInfo) {
 		// `SELECT a, b FROM t WHERE (a, b) IN ((1, 2), (1, 2), (2, 1), (1, 2))` should not return duplicated rows
 		dedup := make(map[hack.MutableString]struct{})
@@ -451,7 +454,15 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 	}
 	// Lock exists keys only for Read Committed Isolation.
 	if e.lock && rc {
-		err = LockKeys(ctx, e.Ctx(), e.waitTime, existKeys...)
+		if e.lockSkipLocked {
+			keysPerRow := 1
+			if len(indexKeys) != 0 {
+				keysPerRow = 2
+			}
+			handles, err = e.lockRowsSkipLocked(ctx, handles, existKeys, keysPerRow)
+		} else {
+			err = LockKeys(ctx, e.Ctx(), e.waitTime, existKeys...)
+		}
 		if err != nil {
 			return err
 		}
@@ -460,6 +471,29 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 	return nil
 }
 
+// lockRowsSkipLocked locks the keys of each row without waiting, and filters out the rows having any key
+// locked by other transactions. rowKeys are the keys of the rows in order, keysPerRow keys for each row.
+func (e *BatchPointGetExec) lockRowsSkipLocked(ctx context.Context, handles []kv.Handle, rowKeys []kv.Key, keysPerRow int) ([]kv.Handle, error) {
+	keys := make([][]kv.Key, 0, len(handles))
+	for i := range handles {
+		keys = append(keys, rowKeys[i*keysPerRow:(i+1)*keysPerRow])
+	}
+	locked, err := newSkipLockedLocker(e.Ctx()).lockRows(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for i, handle := range handles {
+		if !locked[i] {
+			continue
+		}
+		handles[n], e.values[n] = handle, e.values[i]
+		n++
+	}
+	e.values = e.values[:n]
+	return handles[:n], nil
+}
+
 // LockKeys locks the keys for pessimistic transaction.
 func LockKeys(ctx context.Context, sctx sessionctx.Context, lockWaitTime int64, keys ...kv.Key) error {
 	txnCtx := sctx.GetSessionVars().TxnCtx
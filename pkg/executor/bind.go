@@ -16,6 +16,7 @@ package executor
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/bindinfo"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 )
 
@@ -60,7 +62,7 @@ func (e *SQLBindExec) Next(_ context.Context, req *chunk.Chunk) error {
 	case plannercore.OpCaptureBindings:
 		e.captureBindings()
 	case plannercore.OpEvolveBindings:
-		return nil // not support yet
+		return e.evolveBindings()
 	case plannercore.OpReloadBindings:
 		return e.reloadBindings()
 	case plannercore.OpSetBindingStatus:
@@ -155,6 +157,16 @@ func (e *SQLBindExec) captureBindings() {
 	domain.GetDomain(e.Ctx()).BindHandle().CaptureBaselines()
 }
 
+func (e *SQLBindExec) evolveBindings() error {
+	mode, err := e.Ctx().GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAutoPlanEvolution)
+	if err != nil {
+		return err
+	}
+	// the better plans are only recommended unless tidb_auto_plan_evolution is ON.
+	domain.GetDomain(e.Ctx()).BindHandle().EvolveBaselines(strings.EqualFold(mode, variable.On))
+	return nil
+}
+
 func (e *SQLBindExec) reloadBindings() error {
 	return domain.GetDomain(e.Ctx()).BindHandle().LoadFromStorageToCache(true)
 }
@@ -119,6 +119,9 @@ type executorBuilder struct {
 
 	// Used when building MPPGather.
 	encounterUnionScan bool
+
+	// triggerRT is shared by the executors of the statement and the statements in the trigger bodies.
+	triggerRT *triggerRuntime
 }
 
 // CTEStorages stores resTbl and iterInTbl for CTEExec.
@@ -210,6 +213,8 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildUnlockStats(v)
 	case *plannercore.IndexAdvise:
 		return b.buildIndexAdvise(v)
+	case *plannercore.RecommendIndex:
+		return b.buildRecommendIndex(v)
 	case *plannercore.PlanReplayer:
 		return b.buildPlanReplayer(v)
 	case *plannercore.PhysicalLimit:
@@ -254,6 +259,8 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildUnionAll(v)
 	case *plannercore.Update:
 		return b.buildUpdate(v)
+	case *plannercore.Merge:
+		return b.buildMerge(v)
 	case *plannercore.PhysicalUnionScan:
 		return b.buildUnionScanExec(v)
 	case *plannercore.PhysicalHashJoin:
@@ -261,11 +268,11 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 	case *plannercore.PhysicalMergeJoin:
 		return b.buildMergeJoin(v)
 	case *plannercore.PhysicalIndexJoin:
-		return b.buildIndexLookUpJoin(v)
+		return b.buildAdaptiveIndexJoin(v, b.buildIndexLookUpJoin(v))
 	case *plannercore.PhysicalIndexMergeJoin:
 		return b.buildIndexLookUpMergeJoin(v)
 	case *plannercore.PhysicalIndexHashJoin:
-		return b.buildIndexNestedLoopHashJoin(v)
+		return b.buildAdaptiveIndexJoin(&v.PhysicalIndexJoin, b.buildIndexNestedLoopHashJoin(v))
 	case *plannercore.PhysicalSelection:
 		return b.buildSelection(v)
 	case *plannercore.PhysicalHashAgg:
@@ -278,6 +285,8 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -292,6 +301,8 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildIndexReader(v)
 	case *plannercore.PhysicalIndexLookUpReader:
 		return b.buildIndexLookUpReader(v)
+	case *plannercore.PhysicalLooseIndexScan:
+		return b.buildLooseIndexScan(v)
 	case *plannercore.PhysicalWindow:
 		return b.buildWindow(v)
 	case *plannercore.PhysicalShuffle:
@@ -866,6 +877,7 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) exec.Executor {
 		Table:                 v.Table,
 		Partition:             v.Partition,
 		Column:                v.Column,
+		Procedure:             v.Procedure,
 		IndexName:             v.IndexName,
 		ResourceGroupName:     model.NewCIStr(v.ResourceGroupName),
 		Flag:                  v.Flag,
@@ -913,6 +925,11 @@ func (b *executorBuilder) buildSimple(v *plannercore.Simple) exec.Executor {
 			tp:           s.Tp,
 			jobID:        s.JobID,
 		}
+	case *ast.CallStmt:
+		return &CallExec{
+			BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			stmt:         s,
+		}
 	}
 	base := exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID())
 	base.SetInitCap(chunk.ZeroCapacity)
@@ -985,6 +1002,10 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	ivs.triggers, b.err = b.buildTriggerExec(ivs.Table, []int64{ivs.Table.Meta().ID})
+	if b.err != nil {
+		return nil
+	}
 
 	if v.IsReplace {
 		return b.buildReplace(ivs)
@@ -1095,6 +1116,13 @@ func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) exec.Exec
 	return e
 }
 
+func (b *executorBuilder) buildRecommendIndex(v *plannercore.RecommendIndex) exec.Executor {
+	return &RecommendIndexExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		SQL:          v.SQL,
+	}
+}
+
 func (b *executorBuilder) buildPlanReplayer(v *plannercore.PlanReplayer) exec.Executor {
 	if v.Load {
 		e := &PlanReplayerLoadExec{
@@ -1182,6 +1210,8 @@ func (b *executorBuilder) buildDDL(v *plannercore.DDL) exec.Executor {
 		stmt:         v.Statement,
 		is:           b.is,
 		tempTableDDL: temptable.GetTemporaryTableDDL(b.ctx),
+		selectPlan:   v.SelectPlan,
+		selectNames:  v.SelectNames,
 	}
 	return e
 }
@@ -1486,7 +1516,11 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) exec.Ex
 	if b.err != nil {
 		return nil
 	}
+	return b.buildHashJoinWithChildren(v, leftExec, rightExec)
+}
 
+// buildHashJoinWithChildren builds the hash join on the executors of its children.
+func (b *executorBuilder) buildHashJoinWithChildren(v *plannercore.PhysicalHashJoin, leftExec, rightExec exec.Executor) exec.Executor {
 	e := &join.HashJoinExec{
 		BaseExecutor:          exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID(), leftExec, rightExec),
 		ProbeSideTupleFetcher: &join.ProbeSideTupleFetcher{},
@@ -1583,6 +1617,18 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) exec.Ex
 	}
 	e.BuildWorker.BuildKeyColIdx, e.BuildWorker.BuildNAKeyColIdx, e.BuildWorker.BuildSideExec, e.BuildWorker.HashJoinCtx = buildKeyColIdx, buildNAKeyColIdx, buildSideExec, e.HashJoinCtx
 	e.HashJoinCtx.IsNullAware = isNAJoin
+	// Only the build side and the probe side of the inner join can be swapped freely.
+	sessionVars := b.ctx.GetSessionVars()
+	if sessionVars.EnableAdaptiveJoin && v.JoinType == plannercore.InnerJoin && !isNAJoin && len(e.OuterFilter) == 0 {
+		e.Adaptive = &join.AdaptiveJoinCtx{
+			BuildSideEstRows: v.Children()[v.InnerChildIdx].StatsCount(),
+			Threshold:        sessionVars.AdaptiveJoinThreshold,
+			SwappedJoiners:   make([]join.Joiner, e.Concurrency),
+		}
+		for i := range e.Adaptive.SwappedJoiners {
+			e.Adaptive.SwappedJoiners[i] = join.NewJoiner(b.ctx, v.JoinType, v.InnerChildIdx != 0, defaultValues, v.OtherConditions, lhsTypes, rhsTypes, childrenUsedSchema, isNAJoin)
+		}
+	}
 	executor_metrics.ExecutorCountHashJoinExec.Inc()
 
 	// We should use JoinKey to construct the type information using by hashing, instead of using the child's schema directly.
@@ -1812,6 +1858,15 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) exec.
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) exec.Executor {
+	return &JSONTableExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		expr:         v.Expr,
+		rootPath:     v.RootPath,
+		asName:       v.AsName,
+	}
+}
+
 // `getSnapshotTS` returns for-update-ts if in insert/update/delete/lock statement otherwise the isolation read ts
 // Please notice that in RC isolation, the above two ts are the same
 func (b *executorBuilder) getSnapshotTS() (ts uint64, err error) {
@@ -1985,6 +2040,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableStatistics),
 			strings.ToLower(infoschema.TableTiDBIndexes),
 			strings.ToLower(infoschema.TableViews),
+			strings.ToLower(infoschema.TableRoutines),
+			strings.ToLower(infoschema.TableTriggers),
+			strings.ToLower(infoschema.TableMaterializedViews),
 			strings.ToLower(infoschema.TableTables),
 			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableSequences),
@@ -2027,7 +2085,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableTiDBCheckConstraints),
 			strings.ToLower(infoschema.TableKeywords),
 			strings.ToLower(infoschema.TableTiDBIndexUsage),
-			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage):
+			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage),
+			strings.ToLower(infoschema.TableInstancePlanCache),
+			strings.ToLower(infoschema.TableInstancePlanCacheStats):
 			memTracker := memory.NewTracker(v.ID(), -1)
 			memTracker.AttachTo(b.ctx.GetSessionVars().StmtCtx.MemTracker)
 			return &MemTableReaderExec{
@@ -2418,6 +2478,10 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	updateExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
 	return updateExec
 }
 
@@ -2470,6 +2534,112 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	deleteExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
+	return deleteExec
+}
+
+func (b *executorBuilder) buildMerge(v *plannercore.Merge) exec.Executor {
+	b.inUpdateStmt = true
+	tbl, _ := b.is.TableByID(v.TblColPosInfo.TblID)
+	tblID2table := map[int64]table.Table{v.TblColPosInfo.TblID: tbl}
+	if b.err = b.updateForUpdateTS(); b.err != nil {
+		return nil
+	}
+
+	selExec := b.build(v.SelectPlan)
+	if b.err != nil {
+		return nil
+	}
+	base := exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID(), selExec)
+	base.SetInitCap(chunk.ZeroCapacity)
+	mergeExec := &MergeExec{
+		BaseExecutor:  base,
+		tbl:           tbl,
+		tblColPosInfo: v.TblColPosInfo,
+		mergedHandles: kv.NewMemAwareHandleMap[struct{}](),
+	}
+	for _, clause := range v.Clauses {
+		c := &mergeClauseExec{
+			matched:   clause.Matched,
+			condition: clause.Condition,
+			values:    clause.Values,
+		}
+		switch {
+		case clause.Update != nil:
+			c.update = b.buildMergeUpdate(clause.Update, tblID2table, selExec.Schema().Len())
+		case clause.Delete != nil:
+			c.delete = b.buildMergeDelete(clause.Delete, tblID2table)
+		case clause.Insert != nil:
+			insertExec := b.buildInsert(clause.Insert)
+			if b.err == nil {
+				c.insert = insertExec.(*InsertExec)
+			}
+		}
+		if b.err != nil {
+			return nil
+		}
+		mergeExec.clauses = append(mergeExec.clauses, c)
+	}
+	return mergeExec
+}
+
+// buildMergeUpdate builds the update executor of a MERGE clause, which has no child and updates the rows
+// passed by the MERGE executor.
+func (b *executorBuilder) buildMergeUpdate(v *plannercore.Update, tblID2table map[int64]table.Table, schemaLen int) *UpdateExec {
+	assignFlag, err := getAssignFlag(b.ctx, v, schemaLen)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	if b.err = plannercore.CheckUpdateList(assignFlag, v, tblID2table); b.err != nil {
+		return nil
+	}
+	updateExec := &UpdateExec{
+		BaseExecutor:             exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		OrderedList:              v.OrderedList,
+		virtualAssignmentsOffset: v.VirtualAssignmentsOffset,
+		tblID2table:              tblID2table,
+		tblColPosInfos:           v.TblColPosInfos,
+		assignFlag:               assignFlag,
+	}
+	updateExec.fkChecks, b.err = buildTblID2FKCheckExecs(b.ctx, tblID2table, v.FKChecks)
+	if b.err != nil {
+		return nil
+	}
+	updateExec.fkCascades, b.err = b.buildTblID2FKCascadeExecs(tblID2table, v.FKCascades)
+	if b.err != nil {
+		return nil
+	}
+	updateExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
+	return updateExec
+}
+
+// buildMergeDelete builds the delete executor of a MERGE clause, which has no child and removes the rows
+// passed by the MERGE executor.
+func (b *executorBuilder) buildMergeDelete(v *plannercore.Delete, tblID2table map[int64]table.Table) *DeleteExec {
+	deleteExec := &DeleteExec{
+		BaseExecutor:   exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		tblID2Table:    tblID2table,
+		tblColPosInfos: v.TblColPosInfos,
+	}
+	deleteExec.fkChecks, b.err = buildTblID2FKCheckExecs(b.ctx, tblID2table, v.FKChecks)
+	if b.err != nil {
+		return nil
+	}
+	deleteExec.fkCascades, b.err = b.buildTblID2FKCascadeExecs(tblID2table, v.FKCascades)
+	if b.err != nil {
+		return nil
+	}
+	deleteExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
 	return deleteExec
 }
 
@@ -3207,6 +3377,43 @@ func (b *executorBuilder) buildIndexNestedLoopHashJoin(v *plannercore.PhysicalIn
 	return idxHash
 }
 
+// buildAdaptiveIndexJoin wraps the index join by an executor which switches to the hash join at runtime when the
+// outer side is much larger than estimated.
+func (b *executorBuilder) buildAdaptiveIndexJoin(v *plannercore.PhysicalIndexJoin, indexJoin exec.Executor) exec.Executor {
+	if b.err != nil || v.AdaptiveHashJoin == nil {
+		return indexJoin
+	}
+	outerExec := indexJoin.AllChildren()[0]
+	outerBuffer := join.NewBufferedExec(b.ctx, outerExec)
+	switch x := indexJoin.(type) {
+	case *join.IndexLookUpJoin:
+		x.SetChildren(0, outerBuffer)
+	case *join.IndexNestedLoopHashJoin:
+		x.SetChildren(0, outerBuffer)
+	}
+
+	hj := v.AdaptiveHashJoin
+	children := make([]exec.Executor, 2)
+	children[1-hj.InnerChildIdx] = outerBuffer
+	children[hj.InnerChildIdx] = b.build(hj.Children()[hj.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	hashJoin := b.buildHashJoinWithChildren(hj, children[0], children[1])
+	if b.err != nil {
+		return nil
+	}
+	return &join.AdaptiveIndexJoinExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), 0, outerExec),
+		IndexJoin:    indexJoin,
+		HashJoin:     hashJoin,
+		OuterBuffer:  outerBuffer,
+		OuterEstRows: v.Children()[1-v.InnerChildIdx].StatsCount(),
+		Threshold:    b.ctx.GetSessionVars().AdaptiveJoinThreshold,
+		PlanID:       v.ID(),
+	}
+}
+
 func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableReader) (*TableReaderExecutor, error) {
 	tablePlans := v.TablePlans
 	if v.StoreType == kv.TiFlash {
@@ -3625,6 +3832,13 @@ func (b *executorBuilder) buildIndexReader(v *plannercore.PhysicalIndexReader) e
 	}
 
 	ret.ranges = is.Ranges
+	if is.SkipScanRanges != nil {
+		ret.skipScan = &skipScan{
+			seeker:       b.newIndexSeeker(is, ret.physicalTableID, ret.startTS, 1),
+			suffixRanges: is.SkipScanRanges,
+			accessConds:  is.AccessCondition,
+		}
+	}
 	sctx := b.ctx.GetSessionVars().StmtCtx
 	sctx.IndexNames = append(sctx.IndexNames, is.Table.Name.O+":"+is.Index.Name.O)
 
@@ -3722,6 +3936,35 @@ func buildIndexReq(ctx sessionctx.Context, columns []*model.IndexColumn, handleL
 	return indexReq, err
 }
 
+func (b *executorBuilder) buildLooseIndexScan(v *plannercore.PhysicalLooseIndexScan) exec.Executor {
+	startTS, err := b.getSnapshotTS()
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	is := v.IndexScan
+	hasMin, hasMax := v.HasMinMax()
+	numCols := v.PrefixLen
+	if hasMin || hasMax {
+		numCols++
+	}
+	e := &LooseIndexScanExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		seeker:       b.newIndexSeeker(is, is.Table.ID, startTS, numCols),
+		ranges:       is.Ranges,
+		prefixLen:    v.PrefixLen,
+		argOffsets:   v.ArgOffsets,
+		hasMin:       hasMin,
+		hasMax:       hasMax,
+	}
+	for _, aggFunc := range v.AggFuncs {
+		e.aggFuncNames = append(e.aggFuncNames, aggFunc.Name)
+	}
+	sctx := b.ctx.GetSessionVars().StmtCtx
+	sctx.IndexNames = append(sctx.IndexNames, is.Table.Name.O+":"+is.Index.Name.O)
+	return e
+}
+
 func buildNoRangeIndexLookUpReader(b *executorBuilder, v *plannercore.PhysicalIndexLookUpReader) (*IndexLookUpExecutor, error) {
 	is := v.IndexPlans[0].(*plannercore.PhysicalIndexScan)
 	var handleLen int
@@ -3812,6 +4055,13 @@ func (b *executorBuilder) buildIndexLookUpReader(v *plannercore.PhysicalIndexLoo
 	ts := v.TablePlans[0].(*plannercore.PhysicalTableScan)
 
 	ret.ranges = is.Ranges
+	if is.SkipScanRanges != nil {
+		ret.skipScan = &skipScan{
+			seeker:       b.newIndexSeeker(is, is.Table.ID, ret.startTS, 1),
+			suffixRanges: is.SkipScanRanges,
+			accessConds:  is.AccessCondition,
+		}
+	}
 	executor_metrics.ExecutorCounterIndexLookUpExecutor.Inc()
 
 	sctx := b.ctx.GetSessionVars().StmtCtx
@@ -4981,6 +5231,7 @@ func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan
 		desc:               plan.Desc,
 		lock:               plan.Lock,
 		waitTime:           plan.LockWaitTime,
+		lockSkipLocked:     plan.LockSkipLocked,
 		columns:            plan.Columns,
 		handles:            handles,
 		idxVals:            plan.IndexValues,
@@ -5030,6 +5281,7 @@ func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan
 		// Temporary table should not do any lock operations
 		e.lock = false
 		e.waitTime = 0
+		e.lockSkipLocked = false
 	}
 
 	if e.lock {
@@ -165,6 +165,8 @@ func needLowerPriority(p base.Plan) bool {
 		if x.SelectPlan != nil {
 			return isPhysicalPlanNeedLowerPriority(x.SelectPlan)
 		}
+	case *plannercore.Merge:
+		return isPhysicalPlanNeedLowerPriority(x.SelectPlan)
 	}
 	return false
 }
@@ -270,6 +272,11 @@ func getStmtDbLabel(stmtNode ast.StmtNode) map[string]struct{} {
 			dbLabel := x.ViewName.Schema.O
 			dbLabelSet[dbLabel] = struct{}{}
 		}
+	case *ast.CreateMaterializedViewStmt:
+		if x.ViewName != nil {
+			dbLabel := x.ViewName.Schema.O
+			dbLabelSet[dbLabel] = struct{}{}
+		}
 	case *ast.RenameTableStmt:
 		tables := x.TableToTables
 		for _, table := range tables {
@@ -312,6 +319,10 @@ func getStmtDbLabel(stmtNode ast.StmtNode) map[string]struct{} {
 				dbLabelSet[db] = struct{}{}
 			}
 		}
+	case *ast.MergeStmt:
+		for _, db := range getDbFromResultNode(&ast.Join{Left: x.Target, Right: x.Source}) {
+			dbLabelSet[db] = struct{}{}
+		}
 	case *ast.CallStmt:
 		if x.Procedure != nil {
 			dbLabel := x.Procedure.Schema.O
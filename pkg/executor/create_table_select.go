@@ -0,0 +1,263 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+// ctasHiddenTablePrefix is the name prefix of the table which is filled by `CREATE TABLE ... SELECT`
+// before it is renamed to the target name.
+const ctasHiddenTablePrefix = "_tidb_ctas_"
+
+// executeCreateTableSelect executes `CREATE TABLE ... SELECT`. The table is created under a hidden name,
+// filled by the select, and renamed to the target name at last, so other sessions never see a partially
+// filled table. The hidden table is dropped if the statement fails.
+func (e *DDLExec) executeCreateTableSelect(ctx context.Context, s *ast.CreateTableStmt) error {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	if is.TableExists(s.Table.Schema, s.Table.Name) {
+		err := infoschema.ErrTableExists.FastGenByArgs(ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name})
+		if s.IfNotExists {
+			// MySQL doesn't insert any row if the table exists.
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	txn, err := e.Ctx().Txn(true)
+	if err != nil {
+		return err
+	}
+	hidden := &ast.TableName{
+		Schema: s.Table.Schema,
+		Name:   model.NewCIStr(fmt.Sprintf("%s%d", ctasHiddenTablePrefix, txn.StartTS())),
+	}
+	createStmt, cols := e.buildCreateTableSelectStmt(s)
+	createStmt.Table = hidden
+	if err = e.executeCreateTable(createStmt); err != nil {
+		return err
+	}
+
+	// The rows are written in a new transaction, which sees the created table.
+	if err = sessiontxn.NewTxnInStmt(ctx, e.Ctx()); err == nil {
+		err = e.fillCreateTableSelect(ctx, s, hidden, cols)
+	}
+	if err == nil {
+		e.Ctx().StmtCommit(ctx)
+		err = sessiontxn.NewTxnInStmt(ctx, e.Ctx())
+	}
+	if err == nil {
+		err = domain.GetDomain(e.Ctx()).DDL().RenameTable(e.Ctx(), &ast.RenameTableStmt{
+			TableToTables: []*ast.TableToTable{{OldTable: hidden, NewTable: s.Table}},
+		})
+	}
+	if err != nil {
+		e.Ctx().StmtRollback(ctx, false)
+		stmt := &ast.DropTableStmt{IfExists: true, Tables: []*ast.TableName{hidden}}
+		if dropErr := domain.GetDomain(e.Ctx()).DDL().DropTable(e.Ctx(), stmt); dropErr != nil {
+			logutil.Logger(ctx).Warn("drop the table of failed CREATE TABLE ... SELECT failed",
+				zap.Stringer("schema", hidden.Schema), zap.Stringer("table", hidden.Name), zap.Error(dropErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// createSessionTemporaryTableSelect executes `CREATE TEMPORARY TABLE ... SELECT`. The table is only
+// visible to the session, so it is filled in the transaction of the statement directly.
+func (e *DDLExec) createSessionTemporaryTableSelect(ctx context.Context, s *ast.CreateTableStmt) error {
+	if _, exists := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); exists {
+		return e.createSessionTemporaryTable(s)
+	}
+	createStmt, cols := e.buildCreateTableSelectStmt(s)
+	if err := e.createSessionTemporaryTable(createStmt); err != nil {
+		return err
+	}
+	if err := e.fillCreateTableSelect(ctx, s, s.Table, cols); err != nil {
+		if dropErr := e.tempTableDDL.DropLocalTemporaryTable(s.Table.Schema, s.Table.Name); dropErr != nil {
+			logutil.Logger(ctx).Warn("drop the table of failed CREATE TABLE ... SELECT failed",
+				zap.Stringer("schema", s.Table.Schema), zap.Stringer("table", s.Table.Name), zap.Error(dropErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// buildCreateTableSelectStmt builds the CREATE TABLE statement without the select part. The fields of the
+// select which are not declared explicitly are appended after the declared columns, and the target column
+// of each field is returned in order.
+func (e *DDLExec) buildCreateTableSelectStmt(s *ast.CreateTableStmt) (*ast.CreateTableStmt, []*ast.ColumnName) {
+	declared := make(map[string]struct{}, len(s.Cols))
+	for _, col := range s.Cols {
+		declared[col.Name.Name.L] = struct{}{}
+	}
+	cols := make([]*ast.ColumnDef, 0, len(s.Cols)+len(e.selectNames))
+	cols = append(cols, s.Cols...)
+	insertCols := make([]*ast.ColumnName, 0, len(e.selectNames))
+	for i, name := range e.selectNames {
+		insertCols = append(insertCols, &ast.ColumnName{Name: name.ColName})
+		if _, ok := declared[name.ColName.L]; ok {
+			continue
+		}
+		ft := e.selectPlan.Schema().Columns[i].RetType
+		colDef := &ast.ColumnDef{
+			Name: &ast.ColumnName{Name: name.ColName},
+			Tp:   ddl.SelectFieldColumnType(ft),
+		}
+		if mysql.HasNotNullFlag(ft.GetFlag()) {
+			colDef.Options = append(colDef.Options, &ast.ColumnOption{Tp: ast.ColumnOptionNotNull})
+		}
+		cols = append(cols, colDef)
+	}
+
+	createStmt := *s
+	createStmt.Cols = cols
+	createStmt.Select = nil
+	createStmt.OnDuplicate = ast.OnDuplicateKeyHandlingError
+	return &createStmt, insertCols
+}
+
+// fillCreateTableSelect writes the selected rows into the created table. The rows are imported by IMPORT INTO
+// if it is asked for and has the same semantics as the INSERT, that is, there is no IGNORE or REPLACE clause
+// and the table is a normal table.
+func (e *DDLExec) fillCreateTableSelect(ctx context.Context, s *ast.CreateTableStmt, tn *ast.TableName, cols []*ast.ColumnName) (err error) {
+	is := e.Ctx().GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(tn.Schema, tn.Name)
+	if err != nil {
+		return err
+	}
+	dbInfo, ok := is.SchemaByName(tn.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(tn.Schema.O)
+	}
+
+	b := newExecutorBuilder(e.Ctx(), is)
+	var fillExec exec.Executor
+	if e.Ctx().GetSessionVars().CTASImportInto && s.OnDuplicate == ast.OnDuplicateKeyHandlingError &&
+		s.TemporaryKeyword == ast.TemporaryNone {
+		columns := make([]*ast.ColumnNameOrUserVar, 0, len(cols))
+		for _, col := range cols {
+			columns = append(columns, &ast.ColumnNameOrUserVar{ColumnName: col})
+		}
+		plan := plannercore.ImportInto{
+			Table:              &ast.TableName{Schema: tn.Schema, Name: tn.Name, DBInfo: dbInfo, TableInfo: tbl.Meta()},
+			ColumnsAndUserVars: columns,
+			Stmt:               s.Text(),
+			SelectPlan:         e.selectPlan,
+		}.Init(e.Ctx().GetPlanCtx())
+		fillExec = b.build(plan)
+	} else {
+		b.inInsertStmt = true
+		fillExec = e.buildCreateTableSelectInsert(b, s, tbl, cols)
+	}
+	if b.err != nil {
+		return b.err
+	}
+
+	if err = exec.Open(ctx, fillExec); err != nil {
+		terror.Log(exec.Close(fillExec))
+		return err
+	}
+	defer func() {
+		if closeErr := exec.Close(fillExec); err == nil {
+			err = closeErr
+		}
+	}()
+	return exec.Next(ctx, fillExec, exec.NewFirstChunk(fillExec))
+}
+
+// buildCreateTableSelectInsert builds the executor of `INSERT ... SELECT` from the plan of the select.
+func (e *DDLExec) buildCreateTableSelectInsert(b *executorBuilder, s *ast.CreateTableStmt, tbl table.Table, cols []*ast.ColumnName) exec.Executor {
+	selectExec := b.build(e.selectPlan)
+	if b.err != nil {
+		return nil
+	}
+	genExprs, err := buildGeneratedExprs(e.Ctx(), s.Table.Schema, tbl)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	baseExec := exec.NewBaseExecutor(e.Ctx(), nil, e.ID(), selectExec)
+	baseExec.SetInitCap(chunk.ZeroCapacity)
+	ivs := &InsertValues{
+		BaseExecutor: baseExec,
+		Table:        tbl,
+		Columns:      cols,
+		GenExprs:     genExprs,
+		SelectExec:   selectExec,
+		rowLen:       len(cols),
+	}
+	if b.err = ivs.initInsertColumns(); b.err != nil {
+		return nil
+	}
+	if ivs.triggers, b.err = b.buildTriggerExec(tbl, []int64{tbl.Meta().ID}); b.err != nil {
+		return nil
+	}
+	if s.OnDuplicate == ast.OnDuplicateKeyHandlingReplace {
+		return b.buildReplace(ivs)
+	}
+	return &InsertExec{InsertValues: ivs}
+}
+
+// buildGeneratedExprs builds the expressions of the generated columns of the table in order.
+func buildGeneratedExprs(sctx sessionctx.Context, dbName model.CIStr, tbl table.Table) ([]expression.Expression, error) {
+	tblInfo := tbl.Meta()
+	cols := tbl.Cols()
+	colInfos := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		colInfos = append(colInfos, col.ColumnInfo)
+	}
+	exprCtx := sctx.GetExprCtx()
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(exprCtx, dbName, tblInfo.Name, colInfos, tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	schema := expression.NewSchema(columns...)
+	var exprs []expression.Expression
+	for _, col := range cols {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, err := expression.ParseSimpleExpr(exprCtx, col.GeneratedExprString,
+			expression.WithInputSchemaAndNames(schema, names, tblInfo), expression.WithAllowCastArray(true))
+		if err != nil {
+			return nil, err
+		}
+		if expr, err = expr.ResolveIndices(schema); err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
@@ -31,11 +31,13 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
 	"github.com/pingcap/tidb/pkg/sessiontxn/staleread"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/temptable"
+	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/dbterror"
 	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
@@ -54,6 +56,10 @@ type DDLExec struct {
 	is           infoschema.InfoSchema
 	tempTableDDL temptable.TemporaryTableDDL
 	done         bool
+
+	// selectPlan and selectNames are the plan and the output names of the select in `CREATE TABLE ... SELECT`.
+	selectPlan  base.PhysicalPlan
+	selectNames types.NameSlice
 }
 
 // toErr converts the error to the ErrInfoSchemaChanged when the schema is outdated.
@@ -100,6 +106,9 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	switch s := e.stmt.(type) {
 	case *ast.CreateTableStmt:
 		if s.TemporaryKeyword == ast.TemporaryLocal {
+			if s.Select != nil {
+				return e.createSessionTemporaryTableSelect(ctx, s)
+			}
 			return e.createSessionTemporaryTable(s)
 		}
 	case *ast.DropTableStmt:
@@ -157,9 +166,17 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	case *ast.FlashBackDatabaseStmt:
 		err = e.executeFlashbackDatabase(x)
 	case *ast.CreateTableStmt:
-		err = e.executeCreateTable(x)
+		if x.Select != nil {
+			err = e.executeCreateTableSelect(ctx, x)
+		} else {
+			err = e.executeCreateTable(x)
+		}
 	case *ast.CreateViewStmt:
 		err = e.executeCreateView(ctx, x)
+	case *ast.CreateMaterializedViewStmt:
+		err = e.executeCreateMaterializedView(ctx, x)
+	case *ast.DropMaterializedViewStmt:
+		err = e.executeDropMaterializedView(ctx, x)
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
 	case *ast.DropDatabaseStmt:
@@ -203,6 +220,10 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeDropSequence(x)
 	case *ast.AlterSequenceStmt:
 		err = e.executeAlterSequence(x)
+	case *ast.CreateTriggerStmt:
+		err = e.executeCreateTrigger(x)
+	case *ast.DropTriggerStmt:
+		err = e.executeDropTrigger(x)
 	case *ast.CreatePlacementPolicyStmt:
 		err = e.executeCreatePlacementPolicy(x)
 	case *ast.DropPlacementPolicyStmt:
@@ -330,6 +351,9 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	}
 
 	err := domain.GetDomain(e.Ctx()).DDL().DropSchema(e.Ctx(), s)
+	if err == nil {
+		err = dropSchemaRoutines(e.Ctx(), dbName)
+	}
 	sessionVars := e.Ctx().GetSessionVars()
 	if err == nil && strings.ToLower(sessionVars.CurrentDB) == dbName.L {
 		sessionVars.CurrentDB = ""
@@ -206,6 +206,8 @@ type IndexReaderExecutor struct {
 	idxCols        []*expression.Column
 	colLens        []int
 	plans          []base.PhysicalPlan
+	// skipScan builds the ranges at execution if the index is read by a skip scan.
+	skipScan *skipScan
 
 	memTracker *memory.Tracker
 
@@ -275,6 +277,10 @@ func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 			return err
 		}
 	}
+	if e.skipScan != nil {
+		// The ranges are built by batches while reading the index, see skipScanResult.
+		return e.open(ctx, nil)
+	}
 
 	var kvRanges []kv.KeyRange
 	if len(e.partitions) > 0 {
@@ -299,10 +305,10 @@ func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 	return e.open(ctx, kvRanges)
 }
 
-func (e *IndexReaderExecutor) buildKVReq(r []kv.KeyRange) (*kv.Request, error) {
+func (e *IndexReaderExecutor) buildKVReq(r []kv.KeyRange, dagPB *tipb.DAGRequest) (*kv.Request, error) {
 	var builder distsql.RequestBuilder
 	builder.SetKeyRanges(r).
-		SetDAGRequest(e.dagPB).
+		SetDAGRequest(dagPB).
 		SetStartTS(e.startTS).
 		SetDesc(e.desc).
 		SetKeepOrder(e.keepOrder).
@@ -345,12 +351,16 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 		e.memTracker = memory.NewTracker(e.ID(), -1)
 	}
 	e.memTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.MemTracker)
+	if e.skipScan != nil {
+		e.result = e.skipScan.newResult(e.desc, e.memTracker, e.selectSkipScanRanges)
+		return nil
+	}
 	slices.SortFunc(kvRanges, func(i, j kv.KeyRange) int {
 		return bytes.Compare(i.StartKey, j.StartKey)
 	})
 	// use sortedSelectResults only when byItems pushed down and partition numbers > 1
 	if e.byItems == nil || len(e.partitions) <= 1 {
-		kvReq, err := e.buildKVReq(kvRanges)
+		kvReq, err := e.buildKVReq(kvRanges, e.dagPB)
 		if err != nil {
 			return err
 		}
@@ -361,7 +371,7 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 	} else {
 		kvReqs := make([]*kv.Request, 0, len(kvRanges))
 		for _, kvRange := range kvRanges {
-			kvReq, err := e.buildKVReq([]kv.KeyRange{kvRange})
+			kvReq, err := e.buildKVReq([]kv.KeyRange{kvRange}, e.dagPB)
 			if err != nil {
 				return err
 			}
@@ -380,6 +390,25 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 	return nil
 }
 
+// selectSkipScanRanges sends the request reading a batch of ranges of the skip scan.
+func (e *IndexReaderExecutor) selectSkipScanRanges(ctx context.Context, ranges []*ranger.Range, fallback bool) (distsql.SelectResult, error) {
+	kvRanges, err := e.buildKeyRanges(e.Ctx().GetDistSQLCtx(), ranges, e.physicalTableID)
+	if err != nil {
+		return nil, err
+	}
+	plans, dagPB := e.plans, e.dagPB
+	if fallback {
+		if plans, dagPB, err = e.skipScan.fallbackDAG(plans, dagPB); err != nil {
+			return nil, err
+		}
+	}
+	kvReq, err := e.buildKVReq(kvRanges, dagPB)
+	if err != nil {
+		return nil, err
+	}
+	return e.SelectResult(ctx, e.Ctx().GetDistSQLCtx(), kvReq, exec.RetTypes(e), getPhysicalPlanIDs(plans), e.ID())
+}
+
 // IndexLookUpExecutor implements double read for index scan.
 type IndexLookUpExecutor struct {
 	exec.BaseExecutor
@@ -442,6 +471,8 @@ type IndexLookUpExecutor struct {
 	tblPlans        []base.PhysicalPlan
 	idxCols         []*expression.Column
 	colLens         []int
+	// skipScan builds the ranges at execution if the index is read by a skip scan.
+	skipScan *skipScan
 	// PushedLimit is used to skip the preceding and tailing handles when Limit is sunk into IndexLookUpReader.
 	PushedLimit *plannercore.PushedDownLimit
 
@@ -486,9 +517,12 @@ func (e *IndexLookUpExecutor) Open(ctx context.Context) error {
 			return err
 		}
 	}
-	err = e.buildTableKeyRanges()
-	if err != nil {
-		return err
+	// The ranges of a skip scan are built by batches while reading the index, see skipScanResult.
+	if e.skipScan == nil {
+		err = e.buildTableKeyRanges()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Treat temporary table as dummy table, avoid sending distsql request to TiKV.
@@ -698,6 +732,11 @@ func (e *IndexLookUpExecutor) startIndexWorker(ctx context.Context, workCh chan<
 			if finished {
 				break
 			}
+			if e.skipScan != nil {
+				// The ranges of the skip scan are built and read by batches.
+				results = append(results, e.skipScan.newResult(e.desc, tracker, e.selectSkipScanRanges(&builder, tps, idxID)))
+				continue
+			}
 
 			// init kvReq, result and worker for this partition
 			// The key ranges should be ordered.
@@ -738,6 +777,29 @@ func (e *IndexLookUpExecutor) startIndexWorker(ctx context.Context, workCh chan<
 	return nil
 }
 
+// selectSkipScanRanges returns the function sending the requests reading the batches of ranges of the skip scan.
+func (e *IndexLookUpExecutor) selectSkipScanRanges(builder *distsql.RequestBuilder, tps []*types.FieldType, idxID int) func(context.Context, []*ranger.Range, bool) (distsql.SelectResult, error) {
+	return func(ctx context.Context, ranges []*ranger.Range, fallback bool) (distsql.SelectResult, error) {
+		kvRanges, err := distsql.IndexRangesToKVRanges(e.Ctx().GetDistSQLCtx(), getPhysicalTableID(e.table), e.index.ID, ranges)
+		if err != nil {
+			return nil, err
+		}
+		plans := e.idxPlans
+		if fallback {
+			var dagPB *tipb.DAGRequest
+			if plans, dagPB, err = e.skipScan.fallbackDAG(plans, e.dagPB); err != nil {
+				return nil, err
+			}
+			builder.SetDAGRequest(dagPB)
+		}
+		kvReq, err := builder.SetKeyRanges(kvRanges.FirstPartitionRange()).Build()
+		if err != nil {
+			return nil, err
+		}
+		return distsql.SelectWithRuntimeStats(ctx, e.Ctx().GetDistSQLCtx(), kvReq, tps, getPhysicalPlanIDs(plans), idxID)
+	}
+}
+
 // startTableWorker launchs some background goroutines which pick tasks from workCh and execute the task.
 func (e *IndexLookUpExecutor) startTableWorker(ctx context.Context, workCh <-chan *lookupTableTask) {
 	lookupConcurrencyLimit := e.Ctx().GetSessionVars().IndexLookupConcurrency()
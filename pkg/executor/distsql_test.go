@@ -591,3 +591,111 @@ func TestCoprCacheWithoutExecutionInfo(t *testing.T) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("1", "2", "3"))
 	tk.MustQueryWithContext(ctx, "select * from t").Check(testkit.Rows("1", "2", "3"))
 }
+
+func TestIndexSkipScan(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a varchar(10) collate utf8mb4_general_ci, b int, c int, key idx(a, b))")
+	tk.MustExec("insert into t values ('x', 1, 1), ('X', 2, 2), ('y', 1, 3), (null, 1, 4), (null, 3, 5), ('z', 2, 6), ('Y', 3, 7)")
+
+	tk.MustHavePlan("select /*+ skip_scan(t idx) */ a, b from t where b = 1", "IndexSkipScan")
+	tk.MustHavePlan("select /*+ skip_scan(t idx) */ * from t where b = 1", "IndexSkipScan")
+	tk.MustHavePlan("select /*+ use_index(t, idx) skip_scan(t) */ a, b from t where b = 1", "IndexSkipScan")
+	tk.MustNotHavePlan("select /*+ skip_scan(t idx) no_skip_scan(t idx) */ a, b from t where b = 1", "IndexSkipScan")
+
+	for _, cond := range []string{"b = 1", "b > 1", "b in (1, 3)", "b = 1 and c > 1", "b is null"} {
+		for _, cols := range []string{"a, b", "*"} {
+			expected := tk.MustQuery(fmt.Sprintf("select /*+ use_index(t) */ %s from t where %s", cols, cond)).Sort().Rows()
+			tk.MustQuery(fmt.Sprintf("select /*+ skip_scan(t idx) */ %s from t where %s", cols, cond)).Sort().Check(expected)
+		}
+	}
+	tk.MustQuery("select /*+ skip_scan(t idx) */ a, b from t where b >= 2 order by a, b").Check(testkit.Rows("<nil> 3", "X 2", "Y 3", "z 2"))
+	tk.MustQuery("select /*+ skip_scan(t idx) */ a, b from t where b > 2 order by a desc, b desc").Check(testkit.Rows("Y 3", "<nil> 3"))
+
+	// The ranges are built at execution, so the plan isn't cached.
+	tk.MustExec("prepare stmt from 'select /*+ skip_scan(t idx) */ a, b from t where b = ?'")
+	tk.MustExec("set @b = 1")
+	tk.MustQuery("execute stmt using @b").Sort().Check(testkit.Rows("<nil> 1", "x 1", "y 1"))
+	tk.MustQuery("execute stmt using @b").Sort().Check(testkit.Rows("<nil> 1", "x 1", "y 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The skip scan is inapplicable if the leading column is restricted already.
+	tk.MustNotHavePlan("select /*+ skip_scan(t idx) */ a, b from t where a = 'x' and b = 1", "IndexSkipScan")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1815 Optimizer Hint SKIP_SCAN is inapplicable for index idx"))
+
+	// The distinct values of the leading column are sought and read by batches, and the rest of the index is read by
+	// a single range after too many values.
+	tk.MustExec("create table t2(a int, b int, c int, key idx(a, b))")
+	tk.MustExec("insert into t2 values (null, 1, 0)")
+	for i := 0; i < 200; i += 20 {
+		values := make([]string, 0, 20)
+		for j := i; j < i+20; j++ {
+			values = append(values, fmt.Sprintf("(%d, %d, %d)", j, j%3, j))
+		}
+		tk.MustExec("insert into t2 values " + strings.Join(values, ", "))
+	}
+	defer func(maxPrefixes int) {
+		executor.MaxSkipScanPrefixes = maxPrefixes
+	}(executor.MaxSkipScanPrefixes)
+	for _, maxPrefixes := range []int{executor.MaxSkipScanPrefixes, 100, 1} {
+		executor.MaxSkipScanPrefixes = maxPrefixes
+		for _, order := range []string{"a, b", "a desc, b desc"} {
+			for _, cols := range []string{"a, b", "*"} {
+				for _, cond := range []string{"b = 1", "b > 0 and c < 100", "b is null"} {
+					query := fmt.Sprintf("select %%s %s from t2 where %s order by %s", cols, cond, order)
+					expected := tk.MustQuery(fmt.Sprintf(query, "/*+ use_index(t2) */")).Rows()
+					tk.MustQuery(fmt.Sprintf(query, "/*+ skip_scan(t2 idx) */")).Check(expected)
+					tk.MustQuery(fmt.Sprintf(query, "/*+ skip_scan(t2 idx) */") + " limit 5").Check(expected[:min(5, len(expected))])
+				}
+			}
+		}
+	}
+}
+
+func TestLooseIndexScan(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a varchar(10) collate utf8mb4_general_ci, b int, c int, key idx(a, b))")
+	tk.MustExec("insert into t values ('x', 1, 1), ('X', 2, 2), ('y', null, 3), ('y', 5, 4), (null, 3, 5), ('z', null, 6)")
+	for i := 0; i < 8; i++ {
+		tk.MustExec("insert into t select * from t")
+	}
+	tk.MustExec("analyze table t")
+	// The seeks are much cheaper than reading all the entries in the cost model ver1.
+	tk.MustExec("set @@tidb_cost_model_version=1")
+
+	tk.MustHavePlan("select /*+ use_index(t, idx) */ distinct a from t", "LooseIndexScan")
+	tk.MustQuery("select /*+ use_index(t, idx) */ distinct a from t").Sort().Check(testkit.Rows("<nil>", "x", "y", "z"))
+	tk.MustHavePlan("select /*+ use_index(t, idx) */ a, min(b), max(b) from t group by a", "LooseIndexScan")
+	tk.MustQuery("select /*+ use_index(t, idx) */ a, min(b), max(b) from t group by a").Sort().Check(
+		testkit.Rows("<nil> 3 3", "x 1 2", "y 5 5", "z <nil> <nil>"))
+	tk.MustHavePlan("select /*+ use_index(t, idx) */ max(b), a from t where a >= 'y' group by a", "LooseIndexScan")
+	tk.MustQuery("select /*+ use_index(t, idx) */ max(b), a from t where a >= 'y' group by a").Sort().Check(
+		testkit.Rows("5 y", "<nil> z"))
+	tk.MustHavePlan("select /*+ use_index(t, idx) */ b, a from t group by b, a", "LooseIndexScan")
+	tk.MustQuery("select /*+ use_index(t, idx) */ b, a from t group by b, a").Sort().Check(
+		testkit.Rows("1 x", "2 X", "3 <nil>", "5 y", "<nil> y", "<nil> z"))
+	tk.MustQuery("select /*+ use_index(t, idx) */ a, min(b) from t where a in ('x', 'z') group by a order by a").Check(
+		testkit.Rows("x 1", "z <nil>"))
+
+	// The loose index scan competes with the aggregation algorithms, so it's not chosen if one of them is forced.
+	tk.MustNotHavePlan("select /*+ use_index(t, idx) hash_agg() */ distinct a from t", "LooseIndexScan")
+	tk.MustNotHavePlan("select /*+ use_index(t, idx) stream_agg() */ distinct a from t", "LooseIndexScan")
+	tk.MustHavePlan("select * from (select /*+ use_index(t, idx) */ a, max(b) m from t group by a) s where m > 1", "LooseIndexScan")
+	tk.MustQuery("select * from (select /*+ use_index(t, idx) */ a, max(b) m from t group by a) s where m > 1").Sort().Check(
+		testkit.Rows("<nil> 3", "x 2", "y 5"))
+
+	// Only FIRSTROW of the prefix and MIN/MAX of the following column are supported.
+	tk.MustNotHavePlan("select /*+ use_index(t, idx) */ a, count(*) from t group by a", "LooseIndexScan")
+	tk.MustNotHavePlan("select /*+ use_index(t, idx) */ b, min(a) from t group by b", "LooseIndexScan")
+	tk.MustNotHavePlan("select /*+ use_index(t, idx) */ a, max(b) from t where b > 1 group by a", "LooseIndexScan")
+
+	// The plan isn't cached since the seeks are decided by the ranges.
+	tk.MustExec("prepare stmt from 'select /*+ use_index(t, idx) */ a, max(b) from t where a > ? group by a'")
+	tk.MustExec("set @a = 'x'")
+	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("y 5", "z <nil>"))
+	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("y 5", "z <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
@@ -64,6 +64,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	storeerr "github.com/pingcap/tidb/pkg/store/driver/error"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/tables"
 	"github.com/pingcap/tidb/pkg/tablecodec"
@@ -1122,6 +1123,12 @@ type SelectLockExec struct {
 	// due to issues with chunk handling between the TableReaderExecutor and the
 	// SelectReader result.
 	tblID2PhysTblIDColIdx map[int64]int
+
+	// The following fields are used by SKIP LOCKED, which locks the rows as they are returned
+	// instead of locking all of them at the end, see nextSkipLocked.
+	childChk         *chunk.Chunk
+	childRowIdx      int
+	skipLockedLocker *skipLockedLocker
 }
 
 // Open implements the Executor Open interface.
@@ -1140,11 +1147,15 @@ func (e *SelectLockExec) Open(ctx context.Context) error {
 			}
 		}
 	}
+	e.childChk, e.childRowIdx = nil, 0
 	return e.BaseExecutor.Open(ctx)
 }
 
 // Next implements the Executor Next interface.
 func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if len(e.tblID2Handle) > 0 && plannercore.IsSelectSkipLockedLockType(e.Lock.LockType) {
+		return e.nextSkipLocked(ctx, req)
+	}
 	req.GrowAndReset(e.MaxChunkSize())
 	err := exec.Next(ctx, e.Children(0), req)
 	if err != nil {
@@ -1158,27 +1169,9 @@ func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if req.NumRows() > 0 {
 		iter := chunk.NewIterator4Chunk(req)
 		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
-			for tblID, cols := range e.tblID2Handle {
-				for _, col := range cols {
-					handle, err := col.BuildHandle(row)
-					if err != nil {
-						return err
-					}
-					physTblID := tblID
-					if physTblColIdx, ok := e.tblID2PhysTblIDColIdx[tblID]; ok {
-						physTblID = row.GetInt64(physTblColIdx)
-						if physTblID == 0 {
-							// select * from t1 left join t2 on t1.c = t2.c for update
-							// The join right side might be added NULL in left join
-							// In that case, physTblID is 0, so skip adding the lock.
-							//
-							// Note, we can't distinguish whether it's the left join case,
-							// or a bug that TiKV return without correct physical ID column.
-							continue
-						}
-					}
-					e.keys = append(e.keys, tablecodec.EncodeRowKeyWithHandle(physTblID, handle))
-				}
+			e.keys, err = e.appendRowKeys(e.keys, row)
+			if err != nil {
+				return err
 			}
 		}
 		return nil
@@ -1200,6 +1193,155 @@ func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return doLockKeys(ctx, e.Ctx(), lockCtx, e.keys...)
 }
 
+// appendRowKeys appends the keys to lock of the row.
+func (e *SelectLockExec) appendRowKeys(keys []kv.Key, row chunk.Row) ([]kv.Key, error) {
+	for tblID, cols := range e.tblID2Handle {
+		for _, col := range cols {
+			handle, err := col.BuildHandle(row)
+			if err != nil {
+				return nil, err
+			}
+			physTblID := tblID
+			if physTblColIdx, ok := e.tblID2PhysTblIDColIdx[tblID]; ok {
+				physTblID = row.GetInt64(physTblColIdx)
+				if physTblID == 0 {
+					// select * from t1 left join t2 on t1.c = t2.c for update
+					// The join right side might be added NULL in left join
+					// In that case, physTblID is 0, so skip adding the lock.
+					//
+					// Note, we can't distinguish whether it's the left join case,
+					// or a bug that TiKV return without correct physical ID column.
+					continue
+				}
+			}
+			keys = append(keys, tablecodec.EncodeRowKeyWithHandle(physTblID, handle))
+		}
+	}
+	return keys, nil
+}
+
+// nextSkipLocked implements Next for `SELECT ... SKIP LOCKED`.
+// The rows are locked in batches without waiting as they are returned, and the rows having any key
+// locked by other transactions are skipped, see skipLockedLocker.
+func (e *SelectLockExec) nextSkipLocked(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.childChk == nil {
+		e.childChk = exec.TryNewCacheChunk(e.Children(0))
+		e.skipLockedLocker = newSkipLockedLocker(e.Ctx())
+		for id := range e.tblID2Handle {
+			e.UpdateDeltaForTableID(id)
+		}
+	}
+	for !req.IsFull() {
+		if e.childRowIdx >= e.childChk.NumRows() {
+			if err := exec.Next(ctx, e.Children(0), e.childChk); err != nil {
+				return err
+			}
+			e.childRowIdx = 0
+			if e.childChk.NumRows() == 0 {
+				return nil
+			}
+		}
+		// Don't lock more rows than required, so a limit above only locks the rows it returns.
+		numRows := min(e.childChk.NumRows()-e.childRowIdx, req.RequiredRows()-req.NumRows())
+		rowKeys := make([][]kv.Key, 0, numRows)
+		for i := 0; i < numRows; i++ {
+			keys, err := e.appendRowKeys(nil, e.childChk.GetRow(e.childRowIdx+i))
+			if err != nil {
+				return err
+			}
+			rowKeys = append(rowKeys, keys)
+		}
+		locked, err := e.skipLockedLocker.lockRows(ctx, rowKeys)
+		if err != nil {
+			return err
+		}
+		for i, ok := range locked {
+			if ok {
+				req.AppendRow(e.childChk.GetRow(e.childRowIdx + i))
+			}
+		}
+		e.childRowIdx += numRows
+	}
+	return nil
+}
+
+// maxSkipLockedBatchRows is the max number of the rows locked in a batch by skipLockedLocker.
+const maxSkipLockedBatchRows = 1024
+
+// skipLockedLocker locks the rows for `SKIP LOCKED` without waiting, and skips the rows having any key
+// locked by other transactions. The keys of multiple rows are locked in a batch to save the round trips.
+// A failed lock request rolls back all its keys asynchronously, which may remove the locks acquired on
+// them later in the statement, so the keys of a failed batch are never locked again and all the rows of
+// the batch are skipped. To skip as few unlocked rows as possible, the batch shrinks to a single row after
+// a failure, and doubles after each success.
+type skipLockedLocker struct {
+	sctx sessionctx.Context
+	// skippedKeys are the keys failed to lock in the statement.
+	skippedKeys map[string]struct{}
+	batchRows   int
+}
+
+func newSkipLockedLocker(sctx sessionctx.Context) *skipLockedLocker {
+	return &skipLockedLocker{
+		sctx:        sctx,
+		skippedKeys: make(map[string]struct{}),
+		batchRows:   1,
+	}
+}
+
+// lockRows locks the keys of the rows, rowKeys[i] are the keys of the i-th row, and returns whether each
+// row is locked.
+func (l *skipLockedLocker) lockRows(ctx context.Context, rowKeys [][]kv.Key) ([]bool, error) {
+	locked := make([]bool, len(rowKeys))
+	batch := make([]int, 0, min(l.batchRows, len(rowKeys)))
+	var keys []kv.Key
+	for i := 0; i < len(rowKeys); {
+		batch, keys = batch[:0], keys[:0]
+		for ; i < len(rowKeys) && len(batch) < l.batchRows; i++ {
+			if l.hasSkippedKey(rowKeys[i]) {
+				continue
+			}
+			batch = append(batch, i)
+			keys = append(keys, rowKeys[i]...)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		err := LockKeys(ctx, l.sctx, tikvstore.LockNoWait, keys...)
+		if isLockSkippedErr(err) {
+			for _, key := range keys {
+				l.skippedKeys[string(key)] = struct{}{}
+			}
+			l.batchRows = 1
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range batch {
+			locked[idx] = true
+		}
+		l.batchRows = min(l.batchRows*2, maxSkipLockedBatchRows)
+	}
+	return locked, nil
+}
+
+func (l *skipLockedLocker) hasSkippedKey(keys []kv.Key) bool {
+	for _, key := range keys {
+		if _, ok := l.skippedKeys[string(key)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isLockSkippedErr checks if the error of locking keys without waiting is caused by the locks of other
+// transactions, in which case `SELECT ... SKIP LOCKED` skips the rows of the keys instead of failing.
+func isLockSkippedErr(err error) bool {
+	return storeerr.ErrLockAcquireFailAndNoWaitSet.Equal(err)
+}
+
 func newLockCtx(sctx sessionctx.Context, lockWaitTime int64, numKeys int) (*tikvstore.LockCtx, error) {
 	seVars := sctx.GetSessionVars()
 	forUpdateTS, err := sessiontxn.GetTxnManager(sctx).GetStmtForUpdateTS()
@@ -1771,9 +1913,11 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	}
 	vars.StmtCtx.SetVarHintRestore = nil
 	var sc *stmtctx.StatementContext
-	if vars.TxnCtx.CouldRetry || vars.HasStatusFlag(mysql.ServerStatusCursorExists) {
+	_, isCall := s.(*ast.CallStmt)
+	if vars.TxnCtx.CouldRetry || vars.HasStatusFlag(mysql.ServerStatusCursorExists) || isCall {
 		// Must construct new statement context object, the retry history need context for every statement.
 		// TODO: Maybe one day we can get rid of transaction retry, then this logic can be deleted.
+		// The statements of a procedure are executed in the CALL statement, they can't reuse its context.
 		sc = stmtctx.NewStmtCtx()
 	} else {
 		sc = vars.InitStatementContext()
@@ -1916,6 +2060,19 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	case *ast.DeleteStmt:
 		ResetDeleteStmtCtx(sc, stmt, vars)
 		errLevels = sc.ErrLevels()
+	case *ast.MergeStmt:
+		// MERGE writes the rows like UPDATE, it returns errors for the invalid values in the strict mode.
+		sc.InUpdateStmt = true
+		errLevels[errctx.ErrGroupBadNull] = errctx.ResolveErrLevel(false, !strictSQLMode)
+		errLevels[errctx.ErrGroupDividedByZero] = errctx.ResolveErrLevel(
+			!vars.SQLMode.HasErrorForDivisionByZeroMode(),
+			!strictSQLMode,
+		)
+		sc.SetTypeFlags(sc.TypeFlags().
+			WithTruncateAsWarning(!strictSQLMode).
+			WithIgnoreInvalidDateErr(vars.SQLMode.HasAllowInvalidDatesMode()).
+			WithIgnoreZeroInDate(!vars.SQLMode.HasNoZeroInDateMode() || !vars.SQLMode.HasNoZeroDateMode() ||
+				!strictSQLMode || vars.SQLMode.HasAllowInvalidDatesMode()))
 	case *ast.InsertStmt:
 		sc.InInsertStmt = true
 		// For insert statement (not for update statement), disabling the StrictSQLMode
@@ -1946,6 +2103,22 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 			WithIgnoreZeroInDate(!vars.SQLMode.HasNoZeroInDateMode() || !strictSQLMode ||
 				vars.SQLMode.HasAllowInvalidDatesMode()).
 			WithIgnoreZeroDateErr(!vars.SQLMode.HasNoZeroDateMode() || !strictSQLMode))
+		if createStmt, ok := stmt.(*ast.CreateTableStmt); ok && createStmt.Select != nil {
+			// The selected rows are written like `INSERT ... SELECT`.
+			ignoreErr := createStmt.OnDuplicate == ast.OnDuplicateKeyHandlingIgnore
+			sc.InInsertStmt = true
+			if ignoreErr {
+				errLevels[errctx.ErrGroupDupKey] = errctx.LevelWarn
+				errLevels[errctx.ErrGroupAutoIncReadFailed] = errctx.LevelWarn
+				errLevels[errctx.ErrGroupNoMatchedPartition] = errctx.LevelWarn
+			}
+			errLevels[errctx.ErrGroupBadNull] = errctx.ResolveErrLevel(false, !strictSQLMode || ignoreErr)
+			errLevels[errctx.ErrGroupDividedByZero] = errctx.ResolveErrLevel(
+				!vars.SQLMode.HasErrorForDivisionByZeroMode(),
+				!strictSQLMode || ignoreErr,
+			)
+			sc.SetTypeFlags(sc.TypeFlags().WithTruncateAsWarning(!strictSQLMode || ignoreErr))
+		}
 
 	case *ast.LoadDataStmt:
 		sc.InLoadDataStmt = true
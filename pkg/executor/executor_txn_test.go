@@ -694,3 +694,46 @@ func TestSavepointWithBinlog(t *testing.T) {
 	tk.MustExec("commit")
 	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
 }
+
+func TestSelectForUpdateSkipLockedBatch(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk3 := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk2.MustExec("use test")
+	tk3.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, unique key uk(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)")
+	// Each lock request merges its details into the statement, the first one initializes them.
+	lockRequests := func() int {
+		return tk2.Session().GetSessionVars().StmtCtx.GetExecDetails().LockKeysDetail.RetryCount + 1
+	}
+
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where a = 1 for update").Check(testkit.Rows("1 1"))
+	tk2.MustExec("begin pessimistic")
+	// The batch shrinks to a single row after a failure and doubles after each success.
+	tk2.MustQuery("select * from t where b > 0 order by a for update skip locked").Check(testkit.Rows(
+		"2 2", "3 3", "4 4", "5 5", "6 6", "7 7", "8 8", "9 9", "10 10"))
+	require.Equal(t, 5, lockRequests())
+	tk2.MustExec("rollback")
+
+	// A limit above only locks the rows it returns.
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from t where b > 0 order by a limit 3 for update skip locked").Check(testkit.Rows("2 2", "3 3", "4 4"))
+	tk3.MustExec("begin pessimistic")
+	tk3.MustQuery("select * from t where b > 0 order by a for update skip locked").Check(testkit.Rows(
+		"5 5", "6 6", "7 7", "8 8", "9 9", "10 10"))
+	tk3.MustExec("rollback")
+	tk2.MustExec("rollback")
+
+	// The rows of the batch point get are locked in batches too.
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from t where a in (1, 2, 3, 4) for update skip locked").Sort().Check(testkit.Rows("2 2", "3 3", "4 4"))
+	require.Equal(t, 3, lockRequests())
+	tk2.MustQuery("select * from t where b in (5, 6, 7) for update skip locked").Sort().Check(testkit.Rows("5 5", "6 6", "7 7"))
+	require.Equal(t, 2, lockRequests())
+	tk2.MustExec("rollback")
+	tk.MustExec("rollback")
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/distsql"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/expression/aggregation"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
+	"github.com/pingcap/tidb/pkg/util/intest"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"github.com/pingcap/tidb/pkg/util/timeutil"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+func init() {
+	plannercore.CountFullTextDocs = func(ctx context.Context, pctx base.PlanContext, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, terms []fulltext.Term) (map[fulltext.Term]int64, error) {
+		sctx, err := plannercore.AsSctx(pctx)
+		intest.AssertNoError(err)
+		if err != nil {
+			return nil, err
+		}
+		startTS, err := sessiontxn.GetTxnManager(sctx).GetStmtReadTS()
+		if err != nil {
+			return nil, err
+		}
+		c := &fullTextDocCounter{sctx: sctx, table: tblInfo, index: idxInfo, startTS: startTS}
+		docFreqs := make(map[fulltext.Term]int64, len(terms))
+		for _, term := range terms {
+			if _, ok := docFreqs[term]; ok {
+				continue
+			}
+			cnt, err := c.count(ctx, term)
+			if err != nil {
+				return nil, err
+			}
+			docFreqs[term] = cnt
+		}
+		return docFreqs, nil
+	}
+}
+
+// fullTextDocCounter counts the rows containing a term in a FULLTEXT index. Each row has one key for each
+// of its distinct tokens in the index, so the count is the number of keys of the term, which is pushed down
+// to the coprocessor as a count aggregation on the index scan. The keys written by the current transaction
+// are merged from the memory buffer.
+type fullTextDocCounter struct {
+	sctx    sessionctx.Context
+	table   *model.TableInfo
+	index   *model.IndexInfo
+	startTS uint64
+}
+
+func (c *fullTextDocCounter) physicalIDs() []int64 {
+	pi := c.table.GetPartitionInfo()
+	if pi == nil {
+		return []int64{c.table.ID}
+	}
+	ids := make([]int64, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		ids = append(ids, def.ID)
+	}
+	return ids
+}
+
+func (c *fullTextDocCounter) buildDAGPB() (*tipb.DAGRequest, error) {
+	vars := c.sctx.GetSessionVars()
+	dagReq := &tipb.DAGRequest{OutputOffsets: []uint32{0}}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(vars.Location())
+	dagReq.Flags = vars.StmtCtx.PushDownFlags()
+	// Every key of the FULLTEXT index has only one token, so the index is scanned as if it has only the first column.
+	idxScan := &tipb.IndexScan{
+		TableId: c.table.ID,
+		IndexId: c.index.ID,
+		Columns: util.ColumnsToProto([]*model.ColumnInfo{c.table.Columns[c.index.Columns[0].Offset]}, c.table.PKIsHandle, true),
+	}
+	countDesc, err := aggregation.NewAggFuncDesc(c.sctx.GetExprCtx(), ast.AggFuncCount, []expression.Expression{expression.NewOne()}, false)
+	if err != nil {
+		return nil, err
+	}
+	countFunc, err := aggregation.AggFuncToPBExpr(plannercore.GetPushDownCtx(c.sctx.GetPlanCtx()), countDesc, kv.TiKV)
+	if err != nil {
+		return nil, err
+	}
+	dagReq.Executors = append(dagReq.Executors,
+		&tipb.Executor{Tp: tipb.ExecType_TypeIndexScan, IdxScan: idxScan},
+		&tipb.Executor{Tp: tipb.ExecType_TypeAggregation, Aggregation: &tipb.Aggregation{AggFunc: []*tipb.Expr{countFunc}}})
+	distsql.SetEncodeType(c.sctx.GetDistSQLCtx(), dagReq)
+	return dagReq, nil
+}
+
+// count returns the number of rows containing the term at the snapshot of the statement.
+func (c *fullTextDocCounter) count(ctx context.Context, term fulltext.Term) (int64, error) {
+	if err := c.sctx.GetSessionVars().SQLKiller.HandleSignal(); err != nil {
+		return 0, err
+	}
+	dctx := c.sctx.GetDistSQLCtx()
+	kvRanges, err := distsql.IndexRangesToKVRangesForTables(dctx, c.physicalIDs(), c.index.ID, []*ranger.Range{plannercore.BuildFullTextTermRange(term)})
+	if err != nil {
+		return 0, err
+	}
+	dagReq, err := c.buildDAGPB()
+	if err != nil {
+		return 0, err
+	}
+	var builder distsql.RequestBuilder
+	kvReq, err := builder.SetWrappedKeyRanges(kvRanges).
+		SetDAGRequest(dagReq).
+		SetStartTS(c.startTS).
+		SetKeepOrder(false).
+		SetFromSessionVars(dctx).
+		SetFromInfoSchema(c.sctx.GetInfoSchema()).
+		SetConnIDAndConnAlias(c.sctx.GetSessionVars().ConnectionID, c.sctx.GetSessionVars().SessionAlias).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+	fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	result, err := distsql.Select(ctx, dctx, kvReq, fieldTypes)
+	if err != nil {
+		return 0, err
+	}
+	var cnt int64
+	chk := chunk.NewChunkWithCapacity(fieldTypes, 32)
+	for {
+		err = result.Next(ctx, chk)
+		if err != nil || chk.NumRows() == 0 {
+			break
+		}
+		// Each region returns a partial count.
+		for i := 0; i < chk.NumRows(); i++ {
+			cnt += chk.GetRow(i).GetInt64(0)
+		}
+	}
+	if closeErr := result.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	delta, err := c.countTxnDelta(ctx, kvRanges.AppendSelfTo(nil))
+	if err != nil {
+		return 0, err
+	}
+	return cnt + delta, nil
+}
+
+// countTxnDelta returns the number of the keys in the ranges added by the current transaction minus the deleted ones.
+func (c *fullTextDocCounter) countTxnDelta(ctx context.Context, ranges []kv.KeyRange) (int64, error) {
+	txn, err := c.sctx.Txn(false)
+	if err != nil || !txn.Valid() || txn.IsReadOnly() {
+		return 0, err
+	}
+	var keys []kv.Key
+	var delta int64
+	for _, r := range ranges {
+		iter, err := txn.GetMemBuffer().Iter(r.StartKey, r.EndKey)
+		if err != nil {
+			return 0, err
+		}
+		for iter.Valid() {
+			keys = append(keys, iter.Key().Clone())
+			if len(iter.Value()) > 0 {
+				delta++
+			}
+			if err = iter.Next(); err != nil {
+				break
+			}
+		}
+		iter.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	// The keys in the memory buffer may exist in the snapshot, e.g. they are deleted or overwritten.
+	existing, err := c.sctx.GetStore().GetSnapshot(kv.NewVersion(c.startTS)).BatchGet(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+	return delta - int64(len(existing)), nil
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/executor"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/stretchr/testify/require"
@@ -65,3 +66,42 @@ func TestIndexAdvise(t *testing.T) {
 	require.Equal(t, uint64(4), ia.MaxIndexNum.PerTable)
 	require.Equal(t, uint64(5), ia.MaxIndexNum.PerDB)
 }
+
+func TestRecommendIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("create table t (a int, b int, c varchar(20), d int, key idx_d (d))")
+	tk.MustExec("create table s (a int primary key, b int)")
+
+	tk.MustQuery("recommend index run for 'select * from t where a = 1 and c > \"x\"'").Check(testkit.Rows(
+		"test t idx_a a 91.98% 1 select * from t where a = 1 and c > \"x\" CREATE INDEX `idx_a` ON `test`.`t` (`a`)"))
+	// The index on (d, b) reduces the cost of the second query, but it's too little for the workload.
+	tk.MustQuery("recommend index run for 'select * from t where b = 1; select * from t where d = 1 order by b'").Check(testkit.Rows(
+		"test t idx_b b 82.39% 1 select * from t where b = 1 CREATE INDEX `idx_b` ON `test`.`t` (`b`)"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	// The hypothetical indexes of the session are not changed.
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` varchar(20) DEFAULT NULL,\n" +
+		"  `d` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_d` (`d`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The workload is loaded from the statement summary.
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	tk.MustExec("select * from t where c = 'x'")
+	tk.MustExec("select * from t where c = 'y'")
+	tk.MustExec("delete from s where b < 5")
+	tk.MustQuery("recommend index run").Check(testkit.Rows(
+		"test t idx_c c 61.79% 1 select * from t where c = 'x' CREATE INDEX `idx_c` ON `test`.`t` (`c`)",
+		"test s idx_b b 20.04% 1 delete from s where b < 5 CREATE INDEX `idx_b` ON `test`.`s` (`b`)"))
+
+	err := tk.QueryToErr("recommend index run for 'insert into t values (1, 1, \"x\", 1)'")
+	require.EqualError(t, err, "[planner:1235]This version of TiDB doesn't yet support 'RECOMMEND INDEX for Insert'")
+	err = tk.QueryToErr("recommend index run for 'select * from'")
+	require.ErrorContains(t, err, "You have an error in your SQL syntax")
+}
@@ -0,0 +1,323 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/distsql"
+	"github.com/pingcap/tidb/pkg/executor/internal/builder"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"github.com/pingcap/tidb/pkg/util/timeutil"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// indexSeeker seeks the first entry of an index in a range. It reads the leading columns of the index by a
+// coprocessor request with limit 1, so each seek is a range read pushed down through distsql.
+type indexSeeker struct {
+	sctx             sessionctx.Context
+	table            *model.TableInfo
+	index            *model.IndexInfo
+	physicalTableID  int64
+	startTS          uint64
+	txnScope         string
+	readReplicaScope string
+	isStaleness      bool
+
+	// columns are the leading columns of the index to read.
+	columns    []*model.ColumnInfo
+	fieldTypes []*types.FieldType
+	chk        *chunk.Chunk
+}
+
+// newIndexSeeker creates an indexSeeker which reads the first numCols columns of the index.
+func (b *executorBuilder) newIndexSeeker(is *plannercore.PhysicalIndexScan, physicalTableID int64, startTS uint64, numCols int) *indexSeeker {
+	s := &indexSeeker{
+		sctx:             b.ctx,
+		table:            is.Table,
+		index:            is.Index,
+		physicalTableID:  physicalTableID,
+		startTS:          startTS,
+		txnScope:         b.txnScope,
+		readReplicaScope: b.readReplicaScope,
+		isStaleness:      b.isStaleness,
+		columns:          make([]*model.ColumnInfo, 0, numCols),
+		fieldTypes:       make([]*types.FieldType, 0, numCols),
+	}
+	for _, idxCol := range is.Index.Columns[:numCols] {
+		col := is.Table.Columns[idxCol.Offset]
+		s.columns = append(s.columns, col)
+		s.fieldTypes = append(s.fieldTypes, &col.FieldType)
+	}
+	s.chk = chunk.NewChunkWithCapacity(s.fieldTypes, 1)
+	return s
+}
+
+func (s *indexSeeker) buildDAGPB(desc bool) *tipb.DAGRequest {
+	dagReq := &tipb.DAGRequest{}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(s.sctx.GetSessionVars().Location())
+	dagReq.Flags = s.sctx.GetSessionVars().StmtCtx.PushDownFlags()
+	for i := range s.columns {
+		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(i))
+	}
+	idxScan := &tipb.IndexScan{
+		TableId: s.physicalTableID,
+		IndexId: s.index.ID,
+		Columns: util.ColumnsToProto(s.columns, s.table.PKIsHandle, true),
+		Desc:    desc,
+	}
+	dagReq.Executors = append(dagReq.Executors,
+		&tipb.Executor{Tp: tipb.ExecType_TypeIndexScan, IdxScan: idxScan},
+		&tipb.Executor{Tp: tipb.ExecType_TypeLimit, Limit: &tipb.Limit{Limit: 1}})
+	distsql.SetEncodeType(s.sctx.GetDistSQLCtx(), dagReq)
+	return dagReq
+}
+
+// seek returns the leading columns of the first index entry in the range, or the last one if desc is true.
+// It returns nil if there's no entry in the range.
+func (s *indexSeeker) seek(ctx context.Context, ran *ranger.Range, desc bool) ([]types.Datum, error) {
+	if err := s.sctx.GetSessionVars().SQLKiller.HandleSignal(); err != nil {
+		return nil, err
+	}
+	dctx := s.sctx.GetDistSQLCtx()
+	kvRanges, err := distsql.IndexRangesToKVRanges(dctx, s.physicalTableID, s.index.ID, []*ranger.Range{ran})
+	if err != nil {
+		return nil, err
+	}
+	// The range after the last prefix may be empty, e.g. the range is a point.
+	if kvRange := kvRanges.FirstPartitionRange()[0]; bytes.Compare(kvRange.StartKey, kvRange.EndKey) >= 0 {
+		return nil, nil
+	}
+	var builder distsql.RequestBuilder
+	kvReq, err := builder.SetWrappedKeyRanges(kvRanges).
+		SetDAGRequest(s.buildDAGPB(desc)).
+		SetStartTS(s.startTS).
+		SetDesc(desc).
+		SetKeepOrder(true).
+		SetTxnScope(s.txnScope).
+		SetReadReplicaScope(s.readReplicaScope).
+		SetIsStaleness(s.isStaleness).
+		SetFromSessionVars(dctx).
+		SetFromInfoSchema(s.sctx.GetInfoSchema()).
+		SetConnIDAndConnAlias(s.sctx.GetSessionVars().ConnectionID, s.sctx.GetSessionVars().SessionAlias).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	// Only the first entry is needed, so the regions are read one by one.
+	kvReq.Concurrency = 1
+	result, err := distsql.Select(ctx, dctx, kvReq, s.fieldTypes)
+	if err != nil {
+		return nil, err
+	}
+	s.chk.Reset()
+	err = result.Next(ctx, s.chk)
+	if closeErr := result.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || s.chk.NumRows() == 0 {
+		return nil, err
+	}
+	return types.CloneRow(s.chk.GetRow(0).GetDatumRow(s.fieldTypes)), nil
+}
+
+// skipScanBatchSize is the number of distinct values of the leading column sought before their ranges are read.
+const skipScanBatchSize = 64
+
+// MaxSkipScanPrefixes is the max number of distinct values of the leading column sought by an index skip scan. The
+// seeks cost more than reading the entries if there are many more values than estimated, so the rest of the index is
+// read by a single range after that, with the conditions on the following columns pushed down to filter the entries.
+var MaxSkipScanPrefixes = 4096
+
+// skipScan reads an index by a skip scan. It seeks the distinct values of the leading column of the index one after
+// another, and appends the ranges on the following columns to each of them.
+type skipScan struct {
+	seeker       *indexSeeker
+	suffixRanges []*ranger.Range
+	// accessConds are the conditions building the suffix ranges, they filter the entries if the skip scan falls back
+	// to read the rest of the index by a single range.
+	accessConds []expression.Expression
+}
+
+// fallbackDAG returns the plans and the DAG request reading the index by a single range, the access conditions are
+// pushed down as a selection right after the index scan.
+func (s *skipScan) fallbackDAG(plans []base.PhysicalPlan, dagPB *tipb.DAGRequest) ([]base.PhysicalPlan, *tipb.DAGRequest, error) {
+	is := plans[0]
+	conds := make([]expression.Expression, 0, len(s.accessConds))
+	for _, cond := range s.accessConds {
+		cond, err := cond.ResolveIndices(is.Schema())
+		if err != nil {
+			return nil, nil, err
+		}
+		conds = append(conds, cond)
+	}
+	sel := plannercore.PhysicalSelection{Conditions: conds}.Init(s.seeker.sctx.GetPlanCtx(), is.StatsInfo(), is.QueryBlockOffset())
+	sel.SetChildren(is)
+	fallbackPlans := make([]base.PhysicalPlan, 0, len(plans)+1)
+	fallbackPlans = append(fallbackPlans, is, sel)
+	fallbackPlans = append(fallbackPlans, plans[1:]...)
+	executors, err := builder.ConstructListBasedDistExec(s.seeker.sctx.GetBuildPBCtx(), fallbackPlans)
+	if err != nil {
+		return nil, nil, err
+	}
+	fallbackDAG := *dagPB
+	fallbackDAG.Executors = executors
+	return fallbackPlans, &fallbackDAG, nil
+}
+
+// skipScanResult reads the ranges of a skip scan by batches. It seeks skipScanBatchSize distinct values of the leading
+// column at a time and reads their ranges by a distsql request before seeking the next ones, so the ranges of all the
+// values are never buffered at once, and the reads start as soon as the first values are found.
+type skipScanResult struct {
+	*skipScan
+	desc       bool
+	memTracker *memory.Tracker
+	// selectRanges sends the distsql request to read the ranges, by the fallback plans if fallback is true.
+	selectRanges func(ctx context.Context, ranges []*ranger.Range, fallback bool) (distsql.SelectResult, error)
+
+	prev        *types.Datum
+	numPrefixes int
+	exhausted   bool
+	result      distsql.SelectResult
+	rangesMem   int64
+}
+
+func (s *skipScan) newResult(desc bool, memTracker *memory.Tracker,
+	selectRanges func(context.Context, []*ranger.Range, bool) (distsql.SelectResult, error)) *skipScanResult {
+	return &skipScanResult{
+		skipScan:     s,
+		desc:         desc,
+		memTracker:   memTracker,
+		selectRanges: selectRanges,
+	}
+}
+
+// nextResult seeks the next batch of distinct values and sends the request reading their ranges. The result is nil
+// if all the values have been read.
+func (r *skipScanResult) nextResult(ctx context.Context) (distsql.SelectResult, error) {
+	ft := r.seeker.fieldTypes[0]
+	if r.numPrefixes >= MaxSkipScanPrefixes {
+		r.exhausted = true
+		return r.selectBatch(ctx, []*ranger.Range{ranger.SkipScanPrefixRange(r.prev, ft, r.desc)}, true)
+	}
+	prefixes := make([]types.Datum, 0, skipScanBatchSize)
+	for len(prefixes) < skipScanBatchSize && r.numPrefixes < MaxSkipScanPrefixes {
+		row, err := r.seeker.seek(ctx, ranger.SkipScanPrefixRange(r.prev, ft, r.desc), r.desc)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			r.exhausted = true
+			break
+		}
+		prefixes = append(prefixes, row[0])
+		r.prev = &row[0]
+		r.numPrefixes++
+	}
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+	if r.desc {
+		// The ranges are sorted in the order of the index, the request reads them backward.
+		slices.Reverse(prefixes)
+	}
+	return r.selectBatch(ctx, ranger.BuildSkipScanRanges(prefixes, ft, r.suffixRanges), false)
+}
+
+func (r *skipScanResult) selectBatch(ctx context.Context, ranges ranger.Ranges, fallback bool) (distsql.SelectResult, error) {
+	memUsage := ranges.MemUsage()
+	r.memTracker.Consume(memUsage - r.rangesMem)
+	r.rangesMem = memUsage
+	return r.selectRanges(ctx, ranges, fallback)
+}
+
+// Next implements the distsql.SelectResult interface.
+func (r *skipScanResult) Next(ctx context.Context, chk *chunk.Chunk) error {
+	for {
+		if r.result == nil {
+			if r.exhausted {
+				chk.Reset()
+				return nil
+			}
+			result, err := r.nextResult(ctx)
+			if err != nil {
+				return err
+			}
+			if result == nil {
+				continue
+			}
+			r.result = result
+		}
+		if err := r.result.Next(ctx, chk); err != nil || chk.NumRows() > 0 {
+			return err
+		}
+		if err := r.closeResult(); err != nil {
+			return err
+		}
+	}
+}
+
+// NextRaw implements the distsql.SelectResult interface.
+func (r *skipScanResult) NextRaw(ctx context.Context) ([]byte, error) {
+	for {
+		if r.result == nil {
+			if r.exhausted {
+				return nil, nil
+			}
+			result, err := r.nextResult(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				continue
+			}
+			r.result = result
+		}
+		data, err := r.result.NextRaw(ctx)
+		if err != nil || data != nil {
+			return data, err
+		}
+		if err := r.closeResult(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (r *skipScanResult) closeResult() error {
+	err := r.result.Close()
+	r.result = nil
+	return err
+}
+
+// Close implements the distsql.SelectResult interface.
+func (r *skipScanResult) Close() error {
+	r.memTracker.Consume(-r.rangesMem)
+	r.rangesMem = 0
+	if r.result == nil {
+		return nil
+	}
+	return r.closeResult()
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/autoid"
+	"github.com/pingcap/tidb/pkg/mview"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/charset"
 	"github.com/pingcap/tidb/pkg/parser/model"
@@ -73,6 +74,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/keydecoder"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/memory"
+	utilpc "github.com/pingcap/tidb/pkg/util/plancache"
 	"github.com/pingcap/tidb/pkg/util/resourcegrouptag"
 	"github.com/pingcap/tidb/pkg/util/sem"
 	"github.com/pingcap/tidb/pkg/util/servermemorylimit"
@@ -138,6 +140,12 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexes(sctx, dbs)
 		case infoschema.TableViews:
 			e.setDataFromViews(sctx, dbs)
+		case infoschema.TableRoutines:
+			err = e.setDataFromRoutines(ctx, sctx)
+		case infoschema.TableTriggers:
+			e.setDataFromTriggers(sctx, dbs)
+		case infoschema.TableMaterializedViews:
+			err = e.setDataFromMaterializedViews(ctx, sctx, dbs)
 		case infoschema.TableEngines:
 			e.setDataFromEngines()
 		case infoschema.TableCharacterSets:
@@ -210,6 +218,10 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexUsage(sctx, dbs)
 		case infoschema.ClusterTableTiDBIndexUsage:
 			err = e.setDataForClusterIndexUsage(sctx, dbs)
+		case infoschema.TableInstancePlanCache:
+			err = e.setDataFromInstancePlanCache(sctx)
+		case infoschema.TableInstancePlanCacheStats:
+			e.setDataFromInstancePlanCacheStats(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -434,6 +446,12 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema model.CIStr, tabl
 		if index.Unique {
 			nonUnique = "0"
 		}
+		indexType := "BTREE"
+		if index.IsFullText() {
+			indexType = model.IndexTypeFullText.String()
+		} else if index.IsVector() {
+			indexType = model.IndexTypeVector.String()
+		}
 		for i, key := range index.Columns {
 			col := nameToCol[key.Name.L]
 			nullable := "YES"
@@ -469,7 +487,7 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema model.CIStr, tabl
 				nil,                   // SUB_PART
 				nil,                   // PACKED
 				nullable,              // NULLABLE
-				"BTREE",               // INDEX_TYPE
+				indexType,             // INDEX_TYPE
 				"",                    // COMMENT
 				index.Comment,         // INDEX_COMMENT
 				visible,               // IS_VISIBLE
@@ -1342,6 +1360,167 @@ func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []m
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataFromRoutines(ctx context.Context, sctx sessionctx.Context) error {
+	routines, err := getProcedures(ctx, sctx, "", "")
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(routines))
+	for _, routine := range routines {
+		if !routineIsVisible(sctx, routine.schema) {
+			continue
+		}
+		record := types.MakeDatums(
+			routine.name,                // SPECIFIC_NAME
+			infoschema.CatalogVal,       // ROUTINE_CATALOG
+			routine.schema,              // ROUTINE_SCHEMA
+			routine.name,                // ROUTINE_NAME
+			routineTypeProcedure,        // ROUTINE_TYPE
+			"",                          // DATA_TYPE
+			nil,                         // CHARACTER_MAXIMUM_LENGTH
+			nil,                         // CHARACTER_OCTET_LENGTH
+			nil,                         // NUMERIC_PRECISION
+			nil,                         // NUMERIC_SCALE
+			nil,                         // DATETIME_PRECISION
+			nil,                         // CHARACTER_SET_NAME
+			nil,                         // COLLATION_NAME
+			nil,                         // DTD_IDENTIFIER
+			"SQL",                       // ROUTINE_BODY
+			routine.body,                // ROUTINE_DEFINITION
+			nil,                         // EXTERNAL_NAME
+			"SQL",                       // EXTERNAL_LANGUAGE
+			"SQL",                       // PARAMETER_STYLE
+			"NO",                        // IS_DETERMINISTIC
+			"CONTAINS SQL",              // SQL_DATA_ACCESS
+			nil,                         // SQL_PATH
+			"DEFINER",                   // SECURITY_TYPE
+			routine.created,             // CREATED
+			routine.lastAltered,         // LAST_ALTERED
+			routine.sqlMode,             // SQL_MODE
+			routine.comment,             // ROUTINE_COMMENT
+			routine.definer,             // DEFINER
+			routine.charsetClient,       // CHARACTER_SET_CLIENT
+			routine.collationConnection, // COLLATION_CONNECTION
+			routine.dbCollation,         // DATABASE_COLLATION
+		)
+		rows = append(rows, record)
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataFromTriggers(sctx sessionctx.Context, schemas []model.CIStr) {
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tblInfo := range e.is.SchemaTableInfos(schema) {
+			if len(tblInfo.Triggers) == 0 || !triggerIsVisible(sctx, schema.L, tblInfo.Name.L) {
+				continue
+			}
+			// ACTION_ORDER is the order of the trigger among the triggers of the same timing and event.
+			orders := make(map[[2]byte]int)
+			for _, trigger := range tblInfo.Triggers {
+				key := [2]byte{byte(trigger.Timing), byte(trigger.Event)}
+				orders[key]++
+				record := types.MakeDatums(
+					infoschema.CatalogVal,         // TRIGGER_CATALOG
+					schema.O,                      // TRIGGER_SCHEMA
+					trigger.Name.O,                // TRIGGER_NAME
+					trigger.Event.String(),        // EVENT_MANIPULATION
+					infoschema.CatalogVal,         // EVENT_OBJECT_CATALOG
+					schema.O,                      // EVENT_OBJECT_SCHEMA
+					tblInfo.Name.O,                // EVENT_OBJECT_TABLE
+					orders[key],                   // ACTION_ORDER
+					nil,                           // ACTION_CONDITION
+					trigger.Body,                  // ACTION_STATEMENT
+					"ROW",                         // ACTION_ORIENTATION
+					trigger.Timing.String(),       // ACTION_TIMING
+					nil,                           // ACTION_REFERENCE_OLD_TABLE
+					nil,                           // ACTION_REFERENCE_NEW_TABLE
+					"OLD",                         // ACTION_REFERENCE_OLD_ROW
+					"NEW",                         // ACTION_REFERENCE_NEW_ROW
+					triggerCreated(sctx, trigger), // CREATED
+					trigger.SQLMode,               // SQL_MODE
+					trigger.Definer.String(),      // DEFINER
+					trigger.CharsetClient,         // CHARACTER_SET_CLIENT
+					trigger.CollationConnection,   // COLLATION_CONNECTION
+					trigger.DBCollation,           // DATABASE_COLLATION
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataFromMaterializedViews(ctx context.Context, sctx sessionctx.Context, schemas []model.CIStr) error {
+	const sql = "SELECT table_id, last_refresh_tso, CONVERT_TZ(last_refresh_time, @@TIME_ZONE, '+00:00'), last_refresh_method, last_error FROM %n.%n"
+	exec := sctx.GetRestrictedSQLExecutor()
+	kctx := kv.WithInternalSourceType(ctx, kv.InternalTxnMView)
+	chunkRows, _, err := exec.ExecRestrictedSQL(kctx, nil, sql, mysql.SystemDB, mview.RefreshStatusTable)
+	if err != nil {
+		return err
+	}
+	status := make(map[int64]chunk.Row, len(chunkRows))
+	for _, chunkRow := range chunkRows {
+		status[chunkRow.GetInt64(0)] = chunkRow
+	}
+
+	checker := privilege.GetPrivilegeManager(sctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tblInfo := range e.is.SchemaTableInfos(schema) {
+			if !tblInfo.IsMaterializedView() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, tblInfo.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			info := tblInfo.MaterializedView
+			fastRefreshable := "NO"
+			if info.LogTable.L != "" {
+				fastRefreshable = "YES"
+			}
+			var refreshInterval, lastTSO, lastTime, lastMethod, lastError any
+			if info.RefreshInterval != "" {
+				refreshInterval = info.RefreshInterval
+			}
+			if chunkRow, ok := status[tblInfo.ID]; ok {
+				if !chunkRow.IsNull(1) {
+					lastTSO = chunkRow.GetUint64(1)
+				}
+				if !chunkRow.IsNull(2) {
+					t, err := chunkRow.GetTime(2).GoTime(time.UTC)
+					if err != nil {
+						return err
+					}
+					lastTime = types.NewTime(types.FromGoTime(t.In(sctx.GetSessionVars().TimeZone)), mysql.TypeDatetime, 0)
+				}
+				if !chunkRow.IsNull(3) {
+					lastMethod = chunkRow.GetString(3)
+				}
+				if !chunkRow.IsNull(4) {
+					lastError = chunkRow.GetString(4)
+				}
+			}
+			record := types.MakeDatums(
+				schema.O,                    // TABLE_SCHEMA
+				tblInfo.Name.O,              // TABLE_NAME
+				info.Definition,             // VIEW_DEFINITION
+				info.RefreshMethod.String(), // REFRESH_METHOD
+				refreshInterval,             // REFRESH_INTERVAL
+				fastRefreshable,             // FAST_REFRESHABLE
+				lastTSO,                     // LAST_REFRESH_TSO
+				lastTime,                    // LAST_REFRESH_TIME
+				lastMethod,                  // LAST_REFRESH_METHOD
+				lastError,                   // LAST_ERROR
+			)
+			rows = append(rows, record)
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) dataForTiKVStoreStatus(ctx context.Context, sctx sessionctx.Context) (err error) {
 	tikvStore, ok := sctx.GetStore().(helper.Storage)
 	if !ok {
@@ -3619,6 +3798,50 @@ func (e *memtableRetriever) setDataFromIndexUsage(ctx sessionctx.Context, schema
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataFromInstancePlanCache(ctx sessionctx.Context) error {
+	// The SQL texts are from all sessions, so only the users with PROCESS privilege can see them.
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	cache := domain.GetDomain(ctx).InstancePlanCache()
+	if cache == nil {
+		return nil
+	}
+	entries := cache.Entries()
+	rows := make([][]types.Datum, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, types.MakeDatums(
+			entry.SchemaName,
+			entry.SQLText,
+			entry.PlanDigest,
+			entry.MemUsage,
+			entry.Hits,
+			types.NewTime(types.FromGoTime(entry.LoadTime), mysql.TypeDatetime, 0),
+			types.NewTime(types.FromGoTime(entry.LastActiveTime), mysql.TypeDatetime, 0),
+		))
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataFromInstancePlanCacheStats(ctx sessionctx.Context) {
+	var stats utilpc.InstancePlanCacheStats
+	if cache := domain.GetDomain(ctx).InstancePlanCache(); cache != nil {
+		stats = cache.Stats()
+	} else {
+		stats.MemCapacity = variable.InstancePlanCacheMaxMemSize.Load()
+	}
+	e.rows = [][]types.Datum{types.MakeDatums(
+		stats.Plans,
+		stats.MemUsage,
+		stats.MemCapacity,
+		stats.Hits,
+		stats.Misses,
+		stats.Evictions,
+		stats.EvictedMemUsage,
+	)}
+}
+
 func (e *memtableRetriever) setDataForClusterIndexUsage(ctx sessionctx.Context, schemas []model.CIStr) error {
 	e.setDataFromIndexUsage(ctx, schemas)
 	rows, err := infoschema.AppendHostInfoToRows(ctx, e.rows)
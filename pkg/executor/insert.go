@@ -80,6 +80,18 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 		}
 	}
 	sessVars.StmtCtx.AddRecordRows(uint64(len(rows)))
+	err = e.writeRowsWithTriggers(ctx, rows, func(rows [][]types.Datum) error {
+		return e.writeRows(ctx, rows, ignoreErr)
+	})
+	if err != nil {
+		return err
+	}
+	return txn.MayFlush()
+}
+
+// writeRows writes the rows to the table, the duplicate rows are updated or ignored as the statement specifies.
+func (e *InsertExec) writeRows(ctx context.Context, rows [][]types.Datum, ignoreErr bool) error {
+	sessVars := e.Ctx().GetSessionVars()
 	// If you use the IGNORE keyword, duplicate-key error that occurs while executing the INSERT statement are ignored.
 	// For example, without IGNORE, a row that duplicates an existing UNIQUE index or PRIMARY KEY value in
 	// the table causes a duplicate-key error and the statement is aborted. With IGNORE, the row is discarded and no error occurs.
@@ -120,7 +132,7 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 			e.stats.CheckInsertTime += time.Since(start)
 		}
 	}
-	return txn.MayFlush()
+	return nil
 }
 
 func prefetchUniqueIndices(ctx context.Context, txn kv.Transaction, rows []toBeCheckedRow) (map[string][]byte, error) {
@@ -426,7 +438,7 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
-	_, err := updateRecord(ctx, e.Ctx(), handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker, e.fkChecks, e.fkCascades)
+	_, err := updateRecord(ctx, e.Ctx(), handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker, e.fkChecks, e.fkCascades, e.triggers)
 	if err != nil {
 		return err
 	}
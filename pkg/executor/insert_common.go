@@ -95,6 +95,8 @@ type InsertValues struct {
 	// fkChecks contains the foreign key checkers.
 	fkChecks   []*FKCheckExec
 	fkCascades []*FKCascadeExec
+	// triggers fires the triggers of the table, it's nil if the table has no trigger.
+	triggers *TriggerExec
 }
 
 type defaultVal struct {
@@ -1373,6 +1375,10 @@ func (e *InsertValues) removeRow(
 		return true, nil
 	}
 
+	err = e.triggers.fire(ctx, model.TriggerTimingBefore, model.TriggerEventDelete, oldRow, nil)
+	if err != nil {
+		return false, err
+	}
 	err = r.t.RemoveRecord(e.Ctx().GetTableCtx(), handle, oldRow)
 	if err != nil {
 		return false, err
@@ -1381,6 +1387,10 @@ func (e *InsertValues) removeRow(
 	if err != nil {
 		return false, err
 	}
+	err = e.triggers.fire(ctx, model.TriggerTimingAfter, model.TriggerEventDelete, oldRow, nil)
+	if err != nil {
+		return false, err
+	}
 	if inReplace {
 		e.Ctx().GetSessionVars().StmtCtx.AddAffectedRows(1)
 	} else {
@@ -1439,6 +1449,24 @@ func (e *InsertValues) addRecordWithAutoIDHint(
 			}
 		}
 	}
+	return e.triggers.fire(ctx, model.TriggerTimingAfter, model.TriggerEventInsert, nil, row)
+}
+
+// writeRowsWithTriggers writes the rows by write. If the table has triggers, the rows are written one by one, the
+// BEFORE INSERT triggers of a row are fired right before it's written and the AFTER INSERT ones right after it, so
+// the triggers of a row see the rows written before it. The rows may be changed by the BEFORE INSERT triggers.
+func (e *InsertValues) writeRowsWithTriggers(ctx context.Context, rows [][]types.Datum, write func(rows [][]types.Datum) error) error {
+	if e.triggers == nil {
+		return write(rows)
+	}
+	for i, row := range rows {
+		if err := e.triggers.fire(ctx, model.TriggerTimingBefore, model.TriggerEventInsert, nil, row); err != nil {
+			return err
+		}
+		if err := write(rows[i : i+1]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,360 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package join
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/execdetails"
+	"github.com/pingcap/tidb/pkg/util/memory"
+)
+
+// AdaptiveJoinCtx is used by the hash join to re-decide its build side at runtime.
+// When the build side turns out to be much larger than estimated, the hash join reads the build side and the
+// probe side alternately until one of them is exhausted, and builds the hash table on the exhausted one,
+// which is the smaller side.
+type AdaptiveJoinCtx struct {
+	// BuildSideEstRows is the estimated row count of the build side.
+	BuildSideEstRows float64
+	// Threshold is the ratio of the actual row count to BuildSideEstRows which triggers the re-decision.
+	Threshold float64
+	// SwappedJoiners are the joiners used by the probe workers after the build side and the probe side are swapped.
+	SwappedJoiners []Joiner
+
+	// the original executors of the two sides, they are restored when the hash join is closed.
+	buildSideExec exec.Executor
+	probeSideExec exec.Executor
+	swapped       bool
+	// decision is recorded in the runtime stats.
+	decision string
+}
+
+// BufferedExec returns the rows read in advance first, and then the remaining rows of the child. The rows read in
+// advance are kept in a row container, which spills to disk when the memory quota of the statement is exceeded.
+type BufferedExec struct {
+	exec.BaseExecutor
+
+	child        exec.Executor
+	rowContainer *chunk.RowContainer
+	chkIdx       int
+}
+
+// NewBufferedExec creates a BufferedExec on the child. The child is neither opened nor closed by the BufferedExec.
+func NewBufferedExec(sctx sessionctx.Context, child exec.Executor) *BufferedExec {
+	return &BufferedExec{
+		BaseExecutor: exec.NewBaseExecutor(sctx, child.Schema(), 0),
+		child:        child,
+	}
+}
+
+// fill makes the BufferedExec return the rows read in advance by the side.
+func (e *BufferedExec) fill(side *bufferedSide) {
+	e.rowContainer, e.chkIdx = side.rowContainer, 0
+	e.child = side.exec
+	if side.exhausted {
+		e.child = nil
+	}
+}
+
+// Next implements the Executor Next interface.
+func (e *BufferedExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.rowContainer != nil {
+		if e.chkIdx < e.rowContainer.NumChunks() {
+			chk, err := e.rowContainer.GetChunk(e.chkIdx)
+			if err != nil {
+				return err
+			}
+			e.chkIdx++
+			req.Append(chk, 0, chk.NumRows())
+			return nil
+		}
+		if err := e.closeRowContainer(); err != nil {
+			return err
+		}
+	}
+	if e.child == nil {
+		return nil
+	}
+	return exec.Next(ctx, e.child, req)
+}
+
+// Close implements the Executor Close interface.
+func (e *BufferedExec) Close() error {
+	if err := e.closeRowContainer(); err != nil {
+		return err
+	}
+	return e.BaseExecutor.Close()
+}
+
+func (e *BufferedExec) closeRowContainer() error {
+	if e.rowContainer == nil {
+		return nil
+	}
+	err := e.rowContainer.Close()
+	e.rowContainer = nil
+	return err
+}
+
+// bufferedSide is one side of the join read in advance.
+type bufferedSide struct {
+	exec         exec.Executor
+	rowContainer *chunk.RowContainer
+	rows         int
+	exhausted    bool
+}
+
+func newBufferedSide(sctx sessionctx.Context, e exec.Executor, maxChunkSize int, memTracker *memory.Tracker, diskTracker *disk.Tracker) *bufferedSide {
+	rowContainer := chunk.NewRowContainer(exec.RetTypes(e), maxChunkSize)
+	rowContainer.GetMemTracker().AttachTo(memTracker)
+	rowContainer.GetMemTracker().SetLabel(memory.LabelForRowContainer)
+	rowContainer.GetDiskTracker().AttachTo(diskTracker)
+	rowContainer.GetDiskTracker().SetLabel(memory.LabelForRowContainer)
+	if variable.EnableTmpStorageOnOOM.Load() {
+		sctx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(rowContainer.ActionSpill())
+	}
+	return &bufferedSide{exec: e, rowContainer: rowContainer}
+}
+
+func (s *bufferedSide) readChunk(ctx context.Context) error {
+	chk := exec.NewFirstChunk(s.exec)
+	if err := exec.Next(ctx, s.exec, chk); err != nil {
+		return err
+	}
+	if chk.NumRows() == 0 {
+		s.exhausted = true
+		return nil
+	}
+	if err := s.rowContainer.Add(chk); err != nil {
+		return err
+	}
+	s.rows += chk.NumRows()
+	return nil
+}
+
+func (e *HashJoinExec) newBufferedSide(side exec.Executor) *bufferedSide {
+	return newBufferedSide(e.Ctx(), side, e.MaxChunkSize(), e.memTracker, e.diskTracker)
+}
+
+func (e *HashJoinExec) newBufferedExec(side *bufferedSide) *BufferedExec {
+	b := NewBufferedExec(e.Ctx(), side.exec)
+	b.fill(side)
+	return b
+}
+
+// adaptBuildSide reads the build side in advance, and swaps the build side and the probe side if the build side is
+// much larger than estimated and the probe side is smaller than it.
+func (e *HashJoinExec) adaptBuildSide(ctx context.Context) (err error) {
+	adaptive := e.Adaptive
+	adaptive.buildSideExec, adaptive.probeSideExec = e.BuildWorker.BuildSideExec, e.ProbeSideTupleFetcher.ProbeSideExec
+	build := e.newBufferedSide(adaptive.buildSideExec)
+	var probe *bufferedSide
+	defer func() {
+		if err != nil {
+			terror.Log(build.rowContainer.Close())
+			if probe != nil {
+				terror.Log(probe.rowContainer.Close())
+			}
+		}
+	}()
+	limit := adaptive.Threshold * math.Max(adaptive.BuildSideEstRows, 1)
+	for !build.exhausted && float64(build.rows) <= limit {
+		if err = build.readChunk(ctx); err != nil {
+			return err
+		}
+	}
+	if build.exhausted {
+		e.BuildWorker.BuildSideExec = e.newBufferedExec(build)
+		return nil
+	}
+
+	// The estimation of the build side is badly off. Read the side which has fewer rows read until one side is
+	// exhausted, the exhausted side is the smaller one.
+	probe = e.newBufferedSide(adaptive.probeSideExec)
+	for !build.exhausted && !probe.exhausted {
+		side := probe
+		if build.rows < probe.rows {
+			side = build
+		}
+		if err = side.readChunk(ctx); err != nil {
+			return err
+		}
+	}
+	if !probe.exhausted || probe.rows >= build.rows {
+		adaptive.decision = fmt.Sprintf("keep build side, est_build_rows:%v, act_build_rows:%d", adaptive.BuildSideEstRows, build.rows)
+		e.BuildWorker.BuildSideExec = e.newBufferedExec(build)
+		e.ProbeSideTupleFetcher.ProbeSideExec = e.newBufferedExec(probe)
+		return nil
+	}
+	adaptive.decision = fmt.Sprintf("swap build and probe side, est_build_rows:%v, act_build_rows:>=%d, act_probe_rows:%d",
+		adaptive.BuildSideEstRows, build.rows, probe.rows)
+	e.BuildWorker.BuildSideExec = e.newBufferedExec(probe)
+	e.ProbeSideTupleFetcher.ProbeSideExec = e.newBufferedExec(build)
+	e.swapJoinSides()
+	return nil
+}
+
+// swapJoinSides swaps the key columns, the types and the joiners of the build side and the probe side.
+// Calling it twice restores the original sides.
+func (e *HashJoinExec) swapJoinSides() {
+	adaptive := e.Adaptive
+	adaptive.swapped = !adaptive.swapped
+	e.BuildTypes, e.ProbeTypes = e.ProbeTypes, e.BuildTypes
+	probeKeyColIdx := e.ProbeWorkers[0].ProbeKeyColIdx
+	for i, w := range e.ProbeWorkers {
+		w.ProbeKeyColIdx = e.BuildWorker.BuildKeyColIdx
+		w.Joiner, adaptive.SwappedJoiners[i] = adaptive.SwappedJoiners[i], w.Joiner
+	}
+	e.BuildWorker.BuildKeyColIdx = probeKeyColIdx
+}
+
+// resetJoinSides restores the original build side and probe side, so that the hash join can be executed again.
+func (e *HashJoinExec) resetJoinSides() {
+	adaptive := e.Adaptive
+	if adaptive.buildSideExec == nil {
+		return
+	}
+	if adaptive.swapped {
+		e.swapJoinSides()
+	}
+	// Release the rows read in advance but not consumed, the hash join may be closed before it's drained.
+	for _, side := range []exec.Executor{e.BuildWorker.BuildSideExec, e.ProbeSideTupleFetcher.ProbeSideExec} {
+		if side != adaptive.buildSideExec && side != adaptive.probeSideExec {
+			terror.Log(exec.Close(side))
+		}
+	}
+	e.BuildWorker.BuildSideExec, e.ProbeSideTupleFetcher.ProbeSideExec = adaptive.buildSideExec, adaptive.probeSideExec
+	adaptive.buildSideExec, adaptive.probeSideExec = nil, nil
+}
+
+// AdaptiveIndexJoinExec executes the index join, and switches to the hash join at runtime when the outer side turns
+// out to be much larger than estimated, in which case looking up the inner side for every outer batch is slower
+// than reading the whole inner side once. It reads the outer side in advance until it's exhausted or the rows read
+// exceed Threshold times OuterEstRows, and the two joins read the outer side from OuterBuffer.
+type AdaptiveIndexJoinExec struct {
+	exec.BaseExecutor
+
+	IndexJoin exec.Executor
+	HashJoin  exec.Executor
+	// OuterBuffer is the outer child of both IndexJoin and HashJoin.
+	OuterBuffer *BufferedExec
+	// OuterEstRows is the estimated row count of the outer side.
+	OuterEstRows float64
+	// Threshold is the ratio of the actual row count to OuterEstRows which triggers the switch.
+	Threshold float64
+	// PlanID is the ID of the index join plan, the decision is recorded in its runtime stats.
+	PlanID int
+
+	picked      exec.Executor
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *AdaptiveIndexJoinExec) Open(ctx context.Context) error {
+	if err := e.BaseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.PlanID, -1)
+	e.memTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.PlanID, -1)
+	e.diskTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.DiskTracker)
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *AdaptiveIndexJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.picked == nil {
+		if err := e.pickJoin(ctx); err != nil {
+			return err
+		}
+	}
+	return exec.Next(ctx, e.picked, req)
+}
+
+// pickJoin reads the outer side in advance and picks the join to execute.
+func (e *AdaptiveIndexJoinExec) pickJoin(ctx context.Context) error {
+	outer := newBufferedSide(e.Ctx(), e.Children(0), e.MaxChunkSize(), e.memTracker, e.diskTracker)
+	limit := e.Threshold * math.Max(e.OuterEstRows, 1)
+	for !outer.exhausted && float64(outer.rows) <= limit {
+		if err := outer.readChunk(ctx); err != nil {
+			terror.Log(outer.rowContainer.Close())
+			return err
+		}
+	}
+	e.OuterBuffer.fill(outer)
+	e.picked = e.IndexJoin
+	if !outer.exhausted {
+		e.picked = e.HashJoin
+		if coll := e.Ctx().GetSessionVars().StmtCtx.RuntimeStatsColl; coll != nil {
+			coll.RegisterStats(e.PlanID, &adaptiveJoinRuntimeStats{
+				decision: fmt.Sprintf("switch to hash join, est_outer_rows:%v, act_outer_rows:>%d", e.OuterEstRows, outer.rows),
+			})
+		}
+	}
+	return exec.Open(ctx, e.picked)
+}
+
+// Close implements the Executor Close interface.
+func (e *AdaptiveIndexJoinExec) Close() error {
+	var err error
+	if e.picked != nil {
+		err = exec.Close(e.picked)
+		e.picked = nil
+	}
+	if closeErr := e.OuterBuffer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := e.BaseExecutor.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// adaptiveJoinRuntimeStats records the decision of the adaptive index join.
+type adaptiveJoinRuntimeStats struct {
+	decision string
+}
+
+func (e *adaptiveJoinRuntimeStats) String() string {
+	return "adaptive:{" + e.decision + "}"
+}
+
+func (e *adaptiveJoinRuntimeStats) Clone() execdetails.RuntimeStats {
+	return &adaptiveJoinRuntimeStats{decision: e.decision}
+}
+
+func (e *adaptiveJoinRuntimeStats) Merge(rs execdetails.RuntimeStats) {
+	tmp, ok := rs.(*adaptiveJoinRuntimeStats)
+	if !ok {
+		return
+	}
+	if e.decision == "" {
+		e.decision = tmp.decision
+	}
+}
+
+// Tp implements the RuntimeStats interface.
+func (*adaptiveJoinRuntimeStats) Tp() int {
+	return execdetails.TpAdaptiveJoinRuntimeStats
+}
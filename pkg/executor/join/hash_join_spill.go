@@ -0,0 +1,467 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package join
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"go.uber.org/zap"
+)
+
+type spillStatus int32
+
+const (
+	noSpill spillStatus = iota
+	needSpill
+	spilled
+)
+
+const (
+	// spilledPartitionBits is the number of bits of the hash value used to choose the partition of a spilled row.
+	spilledPartitionBits = 4
+	spilledPartitionNum  = 1 << spilledPartitionBits
+	// maxSpillDepth indicates how many times a partition can be repartitioned at most. The hash table built from
+	// the partitions which can't be repartitioned any more is spilled by the row container instead.
+	maxSpillDepth = 3
+
+	spillLogInfo string = "memory exceeds quota, spill the hash join to disk by partitions"
+)
+
+// spillPartitions stores the rows of one side of the hash join into partitions on disk.
+type spillPartitions struct {
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	diskTracker  *disk.Tracker
+
+	disks []*chunk.DataInDiskByChunks
+	// chks buffer the rows of each partition, they are written to disk once they are full.
+	chks []*chunk.Chunk
+}
+
+func newSpillPartitions(fieldTypes []*types.FieldType, maxChunkSize int, diskTracker *disk.Tracker) *spillPartitions {
+	return &spillPartitions{
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+		diskTracker:  diskTracker,
+		disks:        make([]*chunk.DataInDiskByChunks, spilledPartitionNum),
+		chks:         make([]*chunk.Chunk, spilledPartitionNum),
+	}
+}
+
+func (p *spillPartitions) appendRow(partIdx int, row chunk.Row) error {
+	chk := p.chks[partIdx]
+	if chk == nil {
+		chk = chunk.New(p.fieldTypes, chunk.InitialCapacity, p.maxChunkSize)
+		p.chks[partIdx] = chk
+	}
+	chk.AppendRow(row)
+	if chk.IsFull() {
+		return p.flush(partIdx)
+	}
+	return nil
+}
+
+func (p *spillPartitions) flush(partIdx int) error {
+	chk := p.chks[partIdx]
+	if chk == nil || chk.NumRows() == 0 {
+		return nil
+	}
+	if p.disks[partIdx] == nil {
+		p.disks[partIdx] = chunk.NewDataInDiskByChunks(p.fieldTypes)
+		p.disks[partIdx].GetDiskTracker().AttachTo(p.diskTracker)
+	}
+	if err := p.disks[partIdx].Add(chk); err != nil {
+		return err
+	}
+	chk.Reset()
+	return nil
+}
+
+func (p *spillPartitions) flushAll() error {
+	for i := range p.chks {
+		if err := p.flush(i); err != nil {
+			return err
+		}
+	}
+	p.chks = make([]*chunk.Chunk, spilledPartitionNum)
+	return nil
+}
+
+func (p *spillPartitions) close() {
+	for _, d := range p.disks {
+		if d != nil {
+			d.Close()
+		}
+	}
+}
+
+// spilledPartition is a pair of the build side rows and the probe side rows whose join keys fall into the
+// same partition, it's joined after all the rows of the current round are spilled.
+type spilledPartition struct {
+	build *chunk.DataInDiskByChunks
+	probe *chunk.DataInDiskByChunks
+	// depth is the times the rows of the partition have been spilled.
+	depth int
+}
+
+func (p *spilledPartition) close() {
+	if p.build != nil {
+		p.build.Close()
+	}
+	if p.probe != nil {
+		p.probe.Close()
+	}
+}
+
+// hashJoinSpillHelper implements the grace hash join. If the memory quota is exceeded when the hash table is being
+// built, the rows of the build side are spilled into partitions by the hash values of their join keys, and then the
+// rows of the probe side are spilled by the same way. After that, the partitions are joined one by one, a partition
+// is repartitioned with the next bits of the hash values if it still exceeds the memory quota.
+type hashJoinSpillHelper struct {
+	hashJoinCtx *HashJoinCtx
+	status      atomic.Int32
+
+	// depth is the spill depth of the rows joined in the current round, it's 0 for the rows read from the children.
+	depth        int
+	buildHashCtx *HashContext
+	probeHashCtx *HashContext
+	// buildPartitions and probePartitions store the rows spilled in the current round.
+	buildPartitions *spillPartitions
+	probePartitions *spillPartitions
+	// current is the partition joined in the current round.
+	current *spilledPartition
+	pending []*spilledPartition
+	action  *hashJoinSpillAction
+
+	// the executors of the two sides in the first round, they are restored when the hash join is closed.
+	buildSideExec exec.Executor
+	probeSideExec exec.Executor
+
+	// the statistics shown in the runtime stats.
+	joinedPartitions int
+	maxDepth         int
+}
+
+func newHashJoinSpillHelper(hashJoinCtx *HashJoinCtx) *hashJoinSpillHelper {
+	return &hashJoinSpillHelper{hashJoinCtx: hashJoinCtx}
+}
+
+func (h *hashJoinSpillHelper) getStatus() spillStatus {
+	return spillStatus(h.status.Load())
+}
+
+func (h *hashJoinSpillHelper) isSpilled() bool {
+	return h.getStatus() == spilled
+}
+
+// canSpill indicates whether the rows of the current round can be spilled by partitions.
+func (h *hashJoinSpillHelper) canSpill() bool {
+	return h.depth < maxSpillDepth
+}
+
+// startSpillRound prepares to spill the rows of the current round.
+func (e *HashJoinExec) startSpillRound() {
+	h := e.spillHelper
+	maxChunkSize := e.MaxChunkSize()
+	h.status.Store(int32(noSpill))
+	h.buildHashCtx = &HashContext{
+		AllTypes:  e.BuildTypes,
+		KeyColIdx: e.BuildWorker.BuildKeyColIdx,
+	}
+	h.probeHashCtx = &HashContext{
+		AllTypes:  e.ProbeTypes,
+		KeyColIdx: e.ProbeWorkers[0].ProbeKeyColIdx,
+	}
+	h.buildPartitions = newSpillPartitions(exec.RetTypes(e.BuildWorker.BuildSideExec), maxChunkSize, e.diskTracker)
+	h.probePartitions = newSpillPartitions(exec.RetTypes(e.ProbeSideTupleFetcher.ProbeSideExec), maxChunkSize, e.diskTracker)
+}
+
+// finishRound closes the partition joined in the current round, and adds the partitions spilled in this round to
+// the pending ones.
+func (h *hashJoinSpillHelper) finishRound() {
+	if h.current != nil {
+		h.current.close()
+		h.current = nil
+	}
+	if h.buildPartitions == nil {
+		return
+	}
+	if h.isSpilled() {
+		for i := spilledPartitionNum - 1; i >= 0; i-- {
+			build, probe := h.buildPartitions.disks[i], h.probePartitions.disks[i]
+			if build == nil && probe == nil {
+				continue
+			}
+			h.pending = append(h.pending, &spilledPartition{build: build, probe: probe, depth: h.depth + 1})
+		}
+	} else {
+		h.buildPartitions.close()
+		h.probePartitions.close()
+	}
+	h.buildPartitions, h.probePartitions = nil, nil
+	h.status.Store(int32(noSpill))
+}
+
+func (h *hashJoinSpillHelper) popPartition() *spilledPartition {
+	if len(h.pending) == 0 {
+		return nil
+	}
+	p := h.pending[len(h.pending)-1]
+	h.pending = h.pending[:len(h.pending)-1]
+	return p
+}
+
+func (h *hashJoinSpillHelper) close() {
+	h.finishRound()
+	for _, p := range h.pending {
+		p.close()
+	}
+	h.pending = nil
+	if h.action != nil {
+		h.action.SetFinished()
+		h.action = nil
+	}
+}
+
+func (h *hashJoinSpillHelper) newSpillAction() *hashJoinSpillAction {
+	h.action = &hashJoinSpillAction{helper: h}
+	return h.action
+}
+
+// setNeedSpill returns true if the build side will be spilled.
+func (h *hashJoinSpillHelper) setNeedSpill(t *memory.Tracker) bool {
+	switch h.getStatus() {
+	case needSpill:
+		// The build worker will spill the rows soon.
+		return true
+	case spilled:
+		return false
+	}
+	if !memory.HasEnoughDataToSpill(h.hashJoinCtx.memTracker, t) {
+		return false
+	}
+	if !h.status.CompareAndSwap(int32(noSpill), int32(needSpill)) {
+		return h.getStatus() == needSpill
+	}
+	logutil.BgLogger().Info(spillLogInfo,
+		zap.Int("depth", h.depth),
+		zap.Int64("consumed", t.BytesConsumed()),
+		zap.Int64("quota", t.GetBytesLimit()))
+	memory.QueryForceDisk.Add(1)
+	return true
+}
+
+// spillChunk splits the rows of chk into the partitions by the hash values of their join keys.
+func (h *hashJoinSpillHelper) spillChunk(sctx sessionctx.Context, hCtx *HashContext, chk *chunk.Chunk, partitions *spillPartitions, isBuildSide bool) error {
+	numRows := chk.NumRows()
+	hCtx.InitHash(numRows)
+	typeCtx := sctx.GetSessionVars().StmtCtx.TypeCtx()
+	for keyIdx, colIdx := range hCtx.KeyColIdx {
+		ignoreNull := len(h.hashJoinCtx.IsNullEQ) > keyIdx && h.hashJoinCtx.IsNullEQ[keyIdx]
+		err := codec.HashChunkSelected(typeCtx, hCtx.HashVals, chk, hCtx.AllTypes[keyIdx], colIdx, hCtx.Buf, hCtx.HasNull, nil, ignoreNull)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// The partitions of the deeper rounds use the lower bits of the hash values.
+	shift := 64 - spilledPartitionBits*(h.depth+1)
+	for i := 0; i < numRows; i++ {
+		if isBuildSide && hCtx.HasNull[i] {
+			// The build side rows with null join keys can't match any probe side row.
+			continue
+		}
+		partIdx := int(hCtx.HashVals[i].Sum64()>>shift) & (spilledPartitionNum - 1)
+		if err := partitions.appendRow(partIdx, chk.GetRow(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spillBuildSideIfNeeded spills the rows in the row container into partitions when the spill is triggered, and the
+// chunk is also spilled if it's not nil. It returns true if the build side has been spilled.
+func (w *BuildWorker) spillBuildSideIfNeeded(rowContainer *hashRowContainer, chk *chunk.Chunk) (bool, error) {
+	h := w.HashJoinCtx.spillHelper
+	switch h.getStatus() {
+	case noSpill:
+		return false, nil
+	case needSpill:
+		for i := 0; i < rowContainer.NumChunks(); i++ {
+			inMemChk, err := rowContainer.GetChunk(i)
+			if err != nil {
+				return true, err
+			}
+			if err = h.spillChunk(w.HashJoinCtx.SessCtx, h.buildHashCtx, inMemChk, h.buildPartitions, true); err != nil {
+				return true, err
+			}
+		}
+		// Release the rows in memory, the hash table is released after the current round is done.
+		if err := rowContainer.rowContainer.Close(); err != nil {
+			return true, err
+		}
+		h.status.Store(int32(spilled))
+	}
+	if chk == nil {
+		return true, nil
+	}
+	return true, h.spillChunk(w.HashJoinCtx.SessCtx, h.buildHashCtx, chk, h.buildPartitions, true)
+}
+
+// finishBuildSideSpill spills the rows in the row container if the spill is triggered by the last chunk, and writes
+// the buffered rows of the partitions to disk.
+func (w *BuildWorker) finishBuildSideSpill(rowContainer *hashRowContainer) error {
+	h := w.HashJoinCtx.spillHelper
+	if h.action != nil {
+		h.action.SetFinished()
+		h.action = nil
+	}
+	if _, err := w.spillBuildSideIfNeeded(rowContainer, nil); err != nil {
+		return err
+	}
+	return h.buildPartitions.flushAll()
+}
+
+// spillProbeSide spills all the rows of the probe side into partitions after the build side is spilled,
+// chk is the chunk fetched before the build side is finished.
+func (fetcher *ProbeSideTupleFetcher) spillProbeSide(ctx context.Context, chk *chunk.Chunk) error {
+	h := fetcher.spillHelper
+	maxChunkSize := fetcher.SessCtx.GetSessionVars().MaxChunkSize
+	chk.SetRequiredRows(maxChunkSize, maxChunkSize)
+	for chk.NumRows() > 0 {
+		if fetcher.finished.Load() {
+			return nil
+		}
+		if err := h.spillChunk(fetcher.SessCtx, h.probeHashCtx, chk, h.probePartitions, false); err != nil {
+			return err
+		}
+		if err := exec.Next(ctx, fetcher.ProbeSideExec, chk); err != nil {
+			return err
+		}
+	}
+	return h.probePartitions.flushAll()
+}
+
+// canSpillByPartitions indicates whether the hash join can spill the rows of both sides by partitions.
+// The null-aware join and the join using the outer side to build are spilled by the row container only.
+func (e *HashJoinExec) canSpillByPartitions() bool {
+	return e.Ctx().GetSessionVars().EnableHashJoinSpill && !e.IsNullAware && !e.UseOuterToBuild &&
+		len(e.BuildWorker.BuildKeyColIdx) > 0
+}
+
+// needJoinPartition indicates whether the partition may produce any result.
+func (e *HashJoinExec) needJoinPartition(p *spilledPartition) bool {
+	if p.probe == nil || p.probe.NumRows() == 0 {
+		return false
+	}
+	if p.build == nil || p.build.NumRows() == 0 {
+		return e.JoinType != plannercore.InnerJoin && e.JoinType != plannercore.SemiJoin
+	}
+	return true
+}
+
+// joinNextSpilledPartition starts to join the next spilled partition after the current round is done.
+// It returns false if all the spilled partitions have been joined.
+func (e *HashJoinExec) joinNextSpilledPartition(ctx context.Context) (bool, error) {
+	e.waiterWg.Wait()
+	h := e.spillHelper
+	h.finishRound()
+	for {
+		p := h.popPartition()
+		if p == nil {
+			return false, nil
+		}
+		if !e.needJoinPartition(p) {
+			p.close()
+			continue
+		}
+		h.current, h.depth = p, p.depth
+		h.joinedPartitions++
+		if h.maxDepth < p.depth {
+			h.maxDepth = p.depth
+		}
+		e.BuildWorker.BuildSideExec = newSpilledPartitionExec(e.Ctx(), h.buildSideExec.Schema(), p.build)
+		e.ProbeSideTupleFetcher.ProbeSideExec = newSpilledPartitionExec(e.Ctx(), h.probeSideExec.Schema(), p.probe)
+		stat := e.RowContainer.stat
+		if err := e.RowContainer.Close(); err != nil {
+			return false, err
+		}
+		e.startHashJoin(ctx, stat)
+		return true, nil
+	}
+}
+
+// spilledPartitionExec reads the rows of one side of a spilled partition.
+type spilledPartitionExec struct {
+	exec.BaseExecutor
+
+	data   *chunk.DataInDiskByChunks
+	chkIdx int
+}
+
+func newSpilledPartitionExec(sctx sessionctx.Context, schema *expression.Schema, data *chunk.DataInDiskByChunks) *spilledPartitionExec {
+	return &spilledPartitionExec{
+		BaseExecutor: exec.NewBaseExecutor(sctx, schema, 0),
+		data:         data,
+	}
+}
+
+// Next implements the Executor Next interface.
+func (e *spilledPartitionExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.data == nil || e.chkIdx >= e.data.NumChunks() {
+		return nil
+	}
+	chk, err := e.data.GetChunk(e.chkIdx)
+	if err != nil {
+		return err
+	}
+	e.chkIdx++
+	req.SwapColumns(chk)
+	return nil
+}
+
+// hashJoinSpillAction implements memory.ActionOnExceed for the hash join. If the memory quota of a query is exceeded
+// when the hash table is being built, the rows of both sides are spilled into partitions on disk.
+type hashJoinSpillAction struct {
+	memory.BaseOOMAction
+	helper *hashJoinSpillHelper
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *hashJoinSpillAction) Action(t *memory.Tracker) {
+	if a.helper.setNeedSpill(t) {
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*hashJoinSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
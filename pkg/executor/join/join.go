@@ -75,6 +75,8 @@ type HashJoinCtx struct {
 	IsNullAware        bool
 	memTracker         *memory.Tracker // track memory usage.
 	diskTracker        *disk.Tracker   // track disk usage.
+	// spillHelper is not nil if the rows of both sides can be spilled by partitions.
+	spillHelper *hashJoinSpillHelper
 }
 
 // ProbeSideTupleFetcher reads tuples from ProbeSideExec and send them to ProbeWorkers.
@@ -134,6 +136,8 @@ type HashJoinExec struct {
 	waiterWg util.WaitGroupWrapper
 
 	Prepared bool
+	// Adaptive is not nil if the build side can be re-decided at runtime.
+	Adaptive *AdaptiveJoinCtx
 }
 
 // probeChkResource stores the result of the join probe side fetch worker,
@@ -182,6 +186,24 @@ func (e *HashJoinExec) Close() error {
 		terror.Call(e.RowContainer.Close)
 		e.waiterWg.Wait()
 	}
+	if e.spillHelper != nil {
+		e.spillHelper.close()
+		if e.spillHelper.buildSideExec != nil {
+			e.BuildWorker.BuildSideExec, e.ProbeSideTupleFetcher.ProbeSideExec = e.spillHelper.buildSideExec, e.spillHelper.probeSideExec
+		}
+		if e.stats != nil {
+			e.stats.spilledPartitions = e.spillHelper.joinedPartitions
+			e.stats.maxSpillDepth = e.spillHelper.maxDepth
+		}
+		e.spillHelper = nil
+	}
+	if e.Adaptive != nil {
+		if e.stats != nil {
+			e.stats.adaptive = e.Adaptive.decision
+		}
+		e.Adaptive.decision = ""
+		e.resetJoinSides()
+	}
 	e.outerMatchedStatus = e.outerMatchedStatus[:0]
 	for _, w := range e.ProbeWorkers {
 		w.buildSideRows = nil
@@ -225,6 +247,9 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 	e.waiterWg = util.WaitGroupWrapper{}
 	e.closeCh = make(chan struct{})
 	e.finished.Store(false)
+	if variable.EnableTmpStorageOnOOM.Load() && e.canSpillByPartitions() {
+		e.spillHelper = newHashJoinSpillHelper(e.HashJoinCtx)
+	}
 
 	if e.RuntimeStats() != nil {
 		e.stats = &hashJoinRuntimeStats{
@@ -282,6 +307,15 @@ func (fetcher *ProbeSideTupleFetcher) fetchProbeSideChunks(ctx context.Context,
 				return
 			}
 			hasWaitedForBuild = true
+			if fetcher.spillHelper != nil && fetcher.spillHelper.isSpilled() {
+				// The rows of the probe side are joined with the spilled partitions later.
+				if err = fetcher.spillProbeSide(ctx, probeSideResult); err != nil {
+					fetcher.joinResultCh <- &hashjoinWorkerResult{
+						err: err,
+					}
+				}
+				return
+			}
 		}
 
 		if probeSideResult.NumRows() == 0 {
@@ -301,6 +335,9 @@ func (fetcher *ProbeSideTupleFetcher) wait4BuildSide() (emptyBuild bool, err err
 			return false, err
 		}
 	}
+	if fetcher.spillHelper != nil && fetcher.spillHelper.isSpilled() {
+		return false, nil
+	}
 	if fetcher.RowContainer.Len() == uint64(0) && (fetcher.JoinType == plannercore.InnerJoin || fetcher.JoinType == plannercore.SemiJoin) {
 		return true, nil
 	}
@@ -1164,29 +1201,15 @@ func (w *ProbeWorker) join2ChunkForOuterHashJoin(probeSideChk *chunk.Chunk, hCtx
 // step 2. fetch data from probe child in a background goroutine and probe the hash table in multiple join workers.
 func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	if !e.Prepared {
-		e.buildFinished = make(chan error, 1)
-		hCtx := &HashContext{
-			AllTypes:    e.BuildTypes,
-			KeyColIdx:   e.BuildWorker.BuildKeyColIdx,
-			NaKeyColIdx: e.BuildWorker.BuildNAKeyColIdx,
-		}
-		e.RowContainer = newHashRowContainer(e.Ctx(), hCtx, exec.RetTypes(e.BuildWorker.BuildSideExec))
-		// we shallow copies RowContainer for each probe worker to avoid lock contention
-		for i := uint(0); i < e.Concurrency; i++ {
-			if i == 0 {
-				e.ProbeWorkers[i].rowContainerForProbe = e.RowContainer
-			} else {
-				e.ProbeWorkers[i].rowContainerForProbe = e.RowContainer.ShallowCopy()
+		if e.Adaptive != nil {
+			if err = e.adaptBuildSide(ctx); err != nil {
+				return err
 			}
 		}
-		for i := uint(0); i < e.Concurrency; i++ {
-			e.ProbeWorkers[i].rowIters = chunk.NewIterator4Slice([]chunk.Row{})
+		if e.spillHelper != nil {
+			e.spillHelper.buildSideExec, e.spillHelper.probeSideExec = e.BuildWorker.BuildSideExec, e.ProbeSideTupleFetcher.ProbeSideExec
 		}
-		e.workerWg.RunWithRecover(func() {
-			defer trace.StartRegion(ctx, "HashJoinHashTableBuilder").End()
-			e.fetchAndBuildHashTable(ctx)
-		}, e.handleFetchAndBuildHashTablePanic)
-		e.fetchAndProbeHashTable(ctx)
+		e.startHashJoin(ctx, nil)
 		e.Prepared = true
 	}
 	if e.IsOuterJoin {
@@ -1195,6 +1218,17 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	req.Reset()
 
 	result, ok := <-e.joinResultCh
+	for !ok && e.spillHelper != nil {
+		// The current round is done, continue to join the spilled partitions.
+		hasMore, err := e.joinNextSpilledPartition(ctx)
+		if err != nil || !hasMore {
+			return err
+		}
+		if e.IsOuterJoin {
+			atomic.StoreInt64(&e.ProbeSideTupleFetcher.requiredRows, int64(req.RequiredRows()))
+		}
+		result, ok = <-e.joinResultCh
+	}
 	if !ok {
 		return nil
 	}
@@ -1207,6 +1241,40 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	return nil
 }
 
+// startHashJoin builds the hash table and probes it in the background goroutines, the hash join may be started
+// several times if the rows are spilled by partitions. stat is the statistic shared with the previous rounds.
+func (e *HashJoinExec) startHashJoin(ctx context.Context, stat *hashStatistic) {
+	e.buildFinished = make(chan error, 1)
+	hCtx := &HashContext{
+		AllTypes:    e.BuildTypes,
+		KeyColIdx:   e.BuildWorker.BuildKeyColIdx,
+		NaKeyColIdx: e.BuildWorker.BuildNAKeyColIdx,
+	}
+	e.RowContainer = newHashRowContainer(e.Ctx(), hCtx, exec.RetTypes(e.BuildWorker.BuildSideExec))
+	if stat != nil {
+		e.RowContainer.stat = stat
+	}
+	// we shallow copies RowContainer for each probe worker to avoid lock contention
+	for i := uint(0); i < e.Concurrency; i++ {
+		if i == 0 {
+			e.ProbeWorkers[i].rowContainerForProbe = e.RowContainer
+		} else {
+			e.ProbeWorkers[i].rowContainerForProbe = e.RowContainer.ShallowCopy()
+		}
+	}
+	for i := uint(0); i < e.Concurrency; i++ {
+		e.ProbeWorkers[i].rowIters = chunk.NewIterator4Slice([]chunk.Row{})
+	}
+	if e.spillHelper != nil {
+		e.startSpillRound()
+	}
+	e.workerWg.RunWithRecover(func() {
+		defer trace.StartRegion(ctx, "HashJoinHashTableBuilder").End()
+		e.fetchAndBuildHashTable(ctx)
+	}, e.handleFetchAndBuildHashTablePanic)
+	e.fetchAndProbeHashTable(ctx)
+}
+
 func (e *HashJoinExec) handleFetchAndBuildHashTablePanic(r any) {
 	if r != nil {
 		e.buildFinished <- util.GetRecoverError(r)
@@ -1218,7 +1286,7 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) {
 	if e.stats != nil {
 		start := time.Now()
 		defer func() {
-			e.stats.fetchAndBuildHashTable = time.Since(start)
+			e.stats.fetchAndBuildHashTable += time.Since(start)
 		}()
 	}
 	// buildSideResultCh transfers build side chunk from build side fetch to build hash table.
@@ -1257,28 +1325,48 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) {
 }
 
 // BuildHashTableForList builds hash table from `list`.
-func (w *BuildWorker) BuildHashTableForList(buildSideResultCh <-chan *chunk.Chunk) error {
-	var err error
+func (w *BuildWorker) BuildHashTableForList(buildSideResultCh <-chan *chunk.Chunk) (err error) {
 	var selected []bool
 	rowContainer := w.HashJoinCtx.RowContainer
 	rowContainer.GetMemTracker().AttachTo(w.HashJoinCtx.memTracker)
 	rowContainer.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
 	rowContainer.GetDiskTracker().AttachTo(w.HashJoinCtx.diskTracker)
 	rowContainer.GetDiskTracker().SetLabel(memory.LabelForBuildSideResult)
+	spillHelper := w.HashJoinCtx.spillHelper
 	if variable.EnableTmpStorageOnOOM.Load() {
-		actionSpill := rowContainer.ActionSpill()
-		failpoint.Inject("testRowContainerSpill", func(val failpoint.Value) {
-			if val.(bool) {
-				actionSpill = rowContainer.rowContainer.ActionSpillForTest()
-				defer actionSpill.(*chunk.SpillDiskAction).WaitForTest()
+		if spillHelper != nil && spillHelper.canSpill() {
+			w.HashJoinCtx.SessCtx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(spillHelper.newSpillAction())
+		} else {
+			actionSpill := rowContainer.ActionSpill()
+			failpoint.Inject("testRowContainerSpill", func(val failpoint.Value) {
+				if val.(bool) {
+					actionSpill = rowContainer.rowContainer.ActionSpillForTest()
+					defer actionSpill.(*chunk.SpillDiskAction).WaitForTest()
+				}
+			})
+			w.HashJoinCtx.SessCtx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(actionSpill)
+		}
+	}
+	if spillHelper != nil {
+		defer func() {
+			if spillErr := w.finishBuildSideSpill(rowContainer); err == nil {
+				err = spillErr
 			}
-		})
-		w.HashJoinCtx.SessCtx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(actionSpill)
+		}()
 	}
 	for chk := range buildSideResultCh {
 		if w.HashJoinCtx.finished.Load() {
 			return nil
 		}
+		if spillHelper != nil {
+			spilled, err := w.spillBuildSideIfNeeded(rowContainer, chk)
+			if err != nil {
+				return err
+			}
+			if spilled {
+				continue
+			}
+		}
 		if !w.HashJoinCtx.UseOuterToBuild {
 			err = rowContainer.PutChunk(chk, w.HashJoinCtx.IsNullEQ)
 		} else {
@@ -1634,6 +1722,11 @@ type hashJoinRuntimeStats struct {
 	probe                  int64
 	concurrent             int
 	maxFetchAndProbe       int64
+	// adaptive is the decision of the adaptive join.
+	adaptive string
+	// spilledPartitions is the number of the spilled partitions joined, maxSpillDepth is the max times they are spilled.
+	spilledPartitions int
+	maxSpillDepth     int
 }
 
 func (e *hashJoinRuntimeStats) setMaxFetchAndProbeTime(t int64) {
@@ -1683,6 +1776,18 @@ func (e *hashJoinRuntimeStats) String() string {
 		}
 		buf.WriteString("}")
 	}
+	if e.spilledPartitions > 0 {
+		buf.WriteString(", spill:{partitions:")
+		buf.WriteString(strconv.Itoa(e.spilledPartitions))
+		buf.WriteString(", max_depth:")
+		buf.WriteString(strconv.Itoa(e.maxSpillDepth))
+		buf.WriteString("}")
+	}
+	if e.adaptive != "" {
+		buf.WriteString(", adaptive:{")
+		buf.WriteString(e.adaptive)
+		buf.WriteString("}")
+	}
 	return buf.String()
 }
 
@@ -1694,6 +1799,9 @@ func (e *hashJoinRuntimeStats) Clone() execdetails.RuntimeStats {
 		probe:                  e.probe,
 		concurrent:             e.concurrent,
 		maxFetchAndProbe:       e.maxFetchAndProbe,
+		adaptive:               e.adaptive,
+		spilledPartitions:      e.spilledPartitions,
+		maxSpillDepth:          e.maxSpillDepth,
 	}
 }
 
@@ -1710,4 +1818,11 @@ func (e *hashJoinRuntimeStats) Merge(rs execdetails.RuntimeStats) {
 	if e.maxFetchAndProbe < tmp.maxFetchAndProbe {
 		e.maxFetchAndProbe = tmp.maxFetchAndProbe
 	}
+	if e.adaptive == "" {
+		e.adaptive = tmp.adaptive
+	}
+	e.spilledPartitions += tmp.spilledPartitions
+	if e.maxSpillDepth < tmp.maxSpillDepth {
+		e.maxSpillDepth = tmp.maxSpillDepth
+	}
 }
@@ -0,0 +1,208 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+)
+
+var _ exec.Executor = &JSONTableExec{}
+
+// JSONTableExec generates the rows of JSON_TABLE from a JSON document.
+// The document is evaluated when the executor is opened, so it's re-evaluated for every outer row
+// when JSON_TABLE references the tables before it and is the inner side of an Apply.
+type JSONTableExec struct {
+	exec.BaseExecutor
+
+	expr     expression.Expression
+	rootPath *plannercore.JSONTablePath
+	asName   model.CIStr
+
+	rows   [][]types.Datum
+	cursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	if err := e.BaseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.rows = e.rows[:0]
+	e.cursor = 0
+	doc, err := e.expr.Eval(e.Ctx().GetExprCtx().GetEvalCtx(), chunk.Row{})
+	if err != nil || doc.IsNull() {
+		return err
+	}
+	row := make([]types.Datum, e.Schema().Len())
+	_, err = e.appendPathRows(doc.GetMysqlJSON(), e.rootPath, row)
+	return err
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; e.cursor < len(e.rows) && !req.IsFull(); e.cursor++ {
+		for i := range e.rows[e.cursor] {
+			req.AppendDatum(i, &e.rows[e.cursor][i])
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	e.rows = e.rows[:0]
+	return e.BaseExecutor.Close()
+}
+
+// appendPathRows generates the rows for each value matched by the path, it returns whether any row is generated.
+// The columns of the outer paths are already filled in the row.
+func (e *JSONTableExec) appendPathRows(doc types.BinaryJSON, path *plannercore.JSONTablePath, row []types.Datum) (bool, error) {
+	values := doc.ExtractAll(path.Path)
+	for i, value := range values {
+		for _, col := range path.Columns {
+			d, err := e.evalColumn(value, col, i+1)
+			if err != nil {
+				return false, err
+			}
+			row[col.Offset] = d
+		}
+		// The nested paths of the same path are siblings, the columns of the other siblings are NULL
+		// in the rows generated by a nested path. If none of them has a match, a row is generated with
+		// all the nested columns NULL, just like an outer join.
+		matched := false
+		for _, nested := range path.Nested {
+			ok, err := e.appendPathRows(value, nested, row)
+			if err != nil {
+				return false, err
+			}
+			matched = matched || ok
+			resetJSONTablePathColumns(nested, row)
+		}
+		if !matched {
+			e.rows = append(e.rows, slices.Clone(row))
+		}
+	}
+	return len(values) > 0, nil
+}
+
+func resetJSONTablePathColumns(path *plannercore.JSONTablePath, row []types.Datum) {
+	for _, col := range path.Columns {
+		row[col.Offset].SetNull()
+	}
+	for _, nested := range path.Nested {
+		resetJSONTablePathColumns(nested, row)
+	}
+}
+
+// evalColumn evaluates the column from the value of the current row, ordinality is the row number of the value.
+func (e *JSONTableExec) evalColumn(value types.BinaryJSON, col *plannercore.JSONTableColumn, ordinality int) (types.Datum, error) {
+	ft := e.Schema().Columns[col.Offset].RetType
+	switch col.Tp {
+	case ast.JSONTableColumnOrdinality:
+		return types.NewUintDatum(uint64(ordinality)), nil
+	case ast.JSONTableColumnExistsPath:
+		exists := types.NewIntDatum(0)
+		if len(value.ExtractAll(col.Path)) > 0 {
+			exists.SetInt64(1)
+		}
+		return e.convertDatum(exists, col, ft)
+	}
+	values := value.ExtractAll(col.Path)
+	if len(values) == 0 {
+		return e.handleResponse(col, col.OnEmpty, ft, exeerrors.ErrMissingJSONTableValue.GenWithStackByArgs(col.Name.O))
+	}
+	if len(values) > 1 {
+		return e.handleResponse(col, col.OnError, ft, exeerrors.ErrWrongJSONTableValue.GenWithStackByArgs(col.Name.O, e.asName.O))
+	}
+	d, err := e.convertJSON(values[0], col, ft)
+	if err != nil {
+		return e.handleResponse(col, col.OnError, ft, err)
+	}
+	return d, nil
+}
+
+// handleResponse returns the value of the ON EMPTY or ON ERROR response.
+func (e *JSONTableExec) handleResponse(col *plannercore.JSONTableColumn, resp plannercore.JSONTableOnResponse, ft *types.FieldType, err error) (types.Datum, error) {
+	switch resp.Tp {
+	case ast.JSONTableOnResponseError:
+		return types.Datum{}, err
+	case ast.JSONTableOnResponseDefault:
+		return e.convertJSON(resp.Default, col, ft)
+	default:
+		return types.Datum{}, nil
+	}
+}
+
+// convertJSON converts the JSON value to the type of the column.
+func (e *JSONTableExec) convertJSON(value types.BinaryJSON, col *plannercore.JSONTableColumn, ft *types.FieldType) (types.Datum, error) {
+	var d types.Datum
+	if ft.GetType() == mysql.TypeJSON {
+		d.SetMysqlJSON(value)
+		return d, nil
+	}
+	switch value.TypeCode {
+	case types.JSONTypeCodeObject, types.JSONTypeCodeArray:
+		return d, exeerrors.ErrWrongJSONTableValue.GenWithStackByArgs(col.Name.O, e.asName.O)
+	case types.JSONTypeCodeLiteral:
+		switch value.Value[0] {
+		case types.JSONLiteralNil:
+			return d, nil
+		case types.JSONLiteralTrue:
+			d.SetInt64(1)
+		default:
+			d.SetInt64(0)
+		}
+		if types.IsString(ft.GetType()) {
+			d.SetString(value.String(), mysql.DefaultCollationName)
+		}
+	case types.JSONTypeCodeInt64:
+		d.SetInt64(value.GetInt64())
+	case types.JSONTypeCodeUint64:
+		d.SetUint64(value.GetUint64())
+	case types.JSONTypeCodeFloat64:
+		d.SetFloat64(value.GetFloat64())
+	default:
+		s, err := value.Unquote()
+		if err != nil {
+			return d, err
+		}
+		d.SetString(s, mysql.DefaultCollationName)
+	}
+	return e.convertDatum(d, col, ft)
+}
+
+// convertDatum converts the datum to the type of the column strictly, the value which is out of range
+// or truncated is an error, so it can be handled by the ON ERROR response.
+func (e *JSONTableExec) convertDatum(d types.Datum, col *plannercore.JSONTableColumn, ft *types.FieldType) (types.Datum, error) {
+	typeCtx := e.Ctx().GetSessionVars().StmtCtx.TypeCtx()
+	typeCtx = typeCtx.WithFlags(types.StrictFlags)
+	res, err := d.ConvertTo(typeCtx, ft)
+	if types.ErrOverflow.Equal(err) || types.ErrWarnDataOutOfRange.Equal(err) || types.ErrDataTooLong.Equal(err) {
+		return res, exeerrors.ErrJTValueOutOfRange.GenWithStackByArgs(col.Name.O)
+	}
+	return res, err
+}
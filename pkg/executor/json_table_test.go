@@ -0,0 +1,103 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/testkit"
+)
+
+func TestJSONTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": "x"}, {"a": 2}, {"b": [1]}]', '$[*]' columns (
+		id for ordinality,
+		a int path '$.a',
+		b varchar(10) path '$.b',
+		has_a int exists path '$.a')) as jt`).Check(testkit.Rows(
+		"1 1 x 1",
+		"2 2 <nil> 1",
+		"3 <nil> <nil> 0",
+	))
+
+	// ON EMPTY and ON ERROR.
+	tk.MustQuery(`select * from json_table('[{"a": 1}, {"a": "x"}, {}]', '$[*]' columns (
+		a int path '$.a' default '10' on empty default '20' on error)) as jt`).Check(testkit.Rows("1", "20", "10"))
+	tk.MustQuery(`select * from json_table('[{"a": [1]}, {}]', '$[*]' columns (
+		a int path '$.a')) as jt`).Check(testkit.Rows("<nil>", "<nil>"))
+	tk.MustQuery(`select * from json_table('[{"a": [1]}]', '$[*]' columns (
+		a json path '$.a')) as jt`).Check(testkit.Rows("[1]"))
+	tk.MustGetErrCode(`select * from json_table('[{}]', '$[*]' columns (
+		a int path '$.a' error on empty)) as jt`, errno.ErrMissingJSONTableValue)
+	tk.MustGetErrCode(`select * from json_table('[{"a": [1]}]', '$[*]' columns (
+		a int path '$.a' error on error)) as jt`, errno.ErrWrongJSONTableValue)
+	tk.MustGetErrCode(`select * from json_table('[{"a": 1000}]', '$[*]' columns (
+		a tinyint path '$.a' error on error)) as jt`, errno.ErrJTValueOutOfRange)
+
+	// NESTED PATH generates the rows of the sibling nested paths separately.
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": [1, 2], "c": [3]}, {"a": 2, "b": []}]', '$[*]' columns (
+		a int path '$.a',
+		nested path '$.b[*]' columns (b_id for ordinality, b int path '$'),
+		nested path '$.c[*]' columns (c int path '$'))) as jt`).Check(testkit.Rows(
+		"1 1 1 <nil>",
+		"1 2 2 <nil>",
+		"1 <nil> <nil> 3",
+		"2 <nil> <nil> <nil>",
+	))
+
+	tk.MustQuery(`select * from json_table(null, '$[*]' columns (a int path '$')) as jt`).Check(testkit.Rows())
+	tk.MustGetErrCode(`select * from json_table('[]', '$[*]' columns (a int path '$'))`, errno.ErrTFMustHaveAlias)
+	tk.MustGetErrCode(`select * from json_table('[]', '$[*]' columns (a int path '$', a int path '$')) as jt`, errno.ErrDupFieldName)
+	tk.MustExec("create table t (j json)")
+	tk.MustGetErrCode(`select * from t, json_table((select j from t t1 where t1.j = t.j), '$[*]' columns (a int path '$')) as jt`, errno.ErrNotSupportedYet)
+}
+
+func TestJSONTableReferencePrecedingTables(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '[1, 2]'), (2, '[]'), (3, '[3]'), (4, null)`)
+
+	tk.MustQuery(`select t.id, jt.v from t, json_table(t.j, '$[*]' columns (v int path '$')) as jt order by t.id, jt.v`).Check(testkit.Rows(
+		"1 1",
+		"1 2",
+		"3 3",
+	))
+	tk.MustQuery(`select t.id, jt.v from t left join json_table(t.j, '$[*]' columns (v int path '$')) as jt on true order by t.id, jt.v`).Check(testkit.Rows(
+		"1 1",
+		"1 2",
+		"2 <nil>",
+		"3 3",
+		"4 <nil>",
+	))
+	tk.MustQuery(`select t.id, jt.v from t join json_table(t.j, '$[*]' columns (v int path '$')) as jt on jt.v > 1 order by t.id, jt.v`).Check(testkit.Rows(
+		"1 2",
+		"3 3",
+	))
+	tk.MustQuery(`select t.id, (select sum(v) from json_table(t.j, '$[*]' columns (v int path '$')) as jt) from t order by t.id`).Check(testkit.Rows(
+		"1 3",
+		"2 <nil>",
+		"3 3",
+		"4 <nil>",
+	))
+	tk.MustHavePlan(`select * from t, json_table(t.j, '$[*]' columns (v int path '$')) as jt`, "Apply")
+	tk.MustGetErrCode(`select * from t right join json_table(t.j, '$[*]' columns (v int path '$')) as jt on true`, errno.ErrTFForbiddenJoinType)
+	tk.MustGetErrCode(`select * from json_table(t.j, '$[*]' columns (v int path '$')) as jt, t`, errno.ErrBadField)
+}
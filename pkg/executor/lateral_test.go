@@ -0,0 +1,78 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/testkit"
+)
+
+func TestLateralDerivedTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table g (id int primary key)")
+	tk.MustExec("create table t (id int primary key, g int, v int)")
+	tk.MustExec("insert into g values (1), (2), (3)")
+	tk.MustExec("insert into t values (1, 1, 10), (2, 1, 30), (3, 1, 20), (4, 2, 5), (5, 2, 15)")
+
+	// Top N per group.
+	topN := "select g.id, dt.v from g, lateral (select t.v from t where t.g = g.id order by t.v desc limit 2) as dt"
+	tk.MustQuery(topN + " order by g.id, dt.v").Check(testkit.Rows(
+		"1 20",
+		"1 30",
+		"2 5",
+		"2 15",
+	))
+	tk.MustQuery("select g.id, dt.v from g left join lateral (select t.v from t where t.g = g.id order by t.v desc limit 1) as dt on true order by g.id").Check(testkit.Rows(
+		"1 30",
+		"2 15",
+		"3 <nil>",
+	))
+	tk.MustQuery("select g.id, dt.v from g join lateral (select t.v from t where t.g = g.id) as dt on dt.v > 10 order by g.id, dt.v").Check(testkit.Rows(
+		"1 20",
+		"1 30",
+		"2 15",
+	))
+
+	// The apply can be decorrelated into a join if the derived table is simple enough.
+	decorrelated := "select g.id, dt.s from g, lateral (select sum(t.v) as s from t where t.g = g.id) as dt"
+	tk.MustQuery(decorrelated + " order by g.id").Check(testkit.Rows(
+		"1 60",
+		"2 20",
+		"3 <nil>",
+	))
+	tk.MustNotHavePlan(decorrelated, "Apply")
+	tk.MustNotHavePlan("select * from g, lateral (select * from t where t.g = g.id) as dt", "Apply")
+
+	// Otherwise it's executed as an apply, which can be parallel.
+	checkApplyPlan(t, tk, topN, 0)
+	tk.MustExec("set tidb_enable_parallel_apply = on")
+	checkApplyPlan(t, tk, topN, 1)
+	tk.MustQuery(topN).Sort().Check(testkit.Rows(
+		"1 20",
+		"1 30",
+		"2 15",
+		"2 5",
+	))
+
+	// A derived table without LATERAL can't reference the tables before it.
+	tk.MustGetErrCode("select * from g, (select * from t where t.g = g.id) as dt", errno.ErrBadField)
+	tk.MustGetErrCode("select * from g, lateral (select * from t where t.g = g.id)", errno.ErrDerivedMustHaveAlias)
+	tk.MustGetErrCode("select * from g right join lateral (select * from t where t.g = g.id) as dt on true", errno.ErrTFForbiddenJoinType)
+	tk.MustGetErrCode("select * from lateral (select * from t where t.g = g.id) as dt, g", errno.ErrBadField)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// LooseIndexScanExec computes the aggregation grouped by the leading columns of an index by seeking the distinct
+// prefixes one after another. For each prefix, the first entry gives the MIN of the following column unless it's
+// NULL, and the MAX is sought backward in the prefix.
+type LooseIndexScanExec struct {
+	exec.BaseExecutor
+
+	seeker    *indexSeeker
+	ranges    []*ranger.Range
+	prefixLen int
+	// aggFuncNames and argOffsets describe the output columns, the argOffsets are the offsets in the index.
+	aggFuncNames []string
+	argOffsets   []int
+	hasMin       bool
+	hasMax       bool
+
+	rangeIdx int
+	// cur is the remaining part of the current range to seek.
+	cur *ranger.Range
+}
+
+// Open implements the Executor Open interface.
+func (e *LooseIndexScanExec) Open(ctx context.Context) error {
+	e.rangeIdx = 0
+	e.cur = nil
+	if len(e.ranges) > 0 {
+		e.cur = e.ranges[0]
+	}
+	return e.BaseExecutor.Open(ctx)
+}
+
+// Next implements the Executor Next interface.
+func (e *LooseIndexScanExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.MaxChunkSize())
+	for e.cur != nil && !req.IsFull() {
+		row, err := e.seeker.seek(ctx, e.cur, false)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			e.rangeIdx++
+			e.cur = nil
+			if e.rangeIdx < len(e.ranges) {
+				e.cur = e.ranges[e.rangeIdx]
+			}
+			continue
+		}
+		prefix := row[:e.prefixLen]
+		var minVal, maxVal types.Datum
+		if e.hasMin {
+			minVal = row[e.prefixLen]
+			if minVal.IsNull() {
+				// NULL is ahead of the other values in the index, so seek the first non-NULL value.
+				minVal, err = e.seekFollowingColumn(ctx, prefix, true, false)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if e.hasMax {
+			maxVal, err = e.seekFollowingColumn(ctx, prefix, false, true)
+			if err != nil {
+				return err
+			}
+		}
+		for i, name := range e.aggFuncNames {
+			switch name {
+			case ast.AggFuncMin:
+				req.AppendDatum(i, &minVal)
+			case ast.AggFuncMax:
+				req.AppendDatum(i, &maxVal)
+			default:
+				req.AppendDatum(i, &row[e.argOffsets[i]])
+			}
+		}
+		e.cur = ranger.NextPrefixRange(prefix, e.seeker.fieldTypes[:e.prefixLen], e.ranges[e.rangeIdx])
+	}
+	return nil
+}
+
+// seekFollowingColumn returns the first or the last value of the column following the prefix.
+func (e *LooseIndexScanExec) seekFollowingColumn(ctx context.Context, prefix []types.Datum, notNull, desc bool) (types.Datum, error) {
+	ran := ranger.PrefixRange(prefix, e.seeker.fieldTypes[:e.prefixLen], notNull)
+	row, err := e.seeker.seek(ctx, ran, desc)
+	if err != nil || row == nil {
+		return types.Datum{}, err
+	}
+	return row[e.prefixLen], nil
+}
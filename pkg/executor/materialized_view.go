@@ -0,0 +1,365 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/mview"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+	"go.uber.org/zap"
+)
+
+// mviewSourceAlias is the alias of the derived table used to get the output schema of the view query.
+const mviewSourceAlias = "_tidb_mview_src"
+
+var mviewLogEvents = []model.TriggerEvent{model.TriggerEventInsert, model.TriggerEventUpdate, model.TriggerEventDelete}
+
+// executeCreateMaterializedView creates the table of the view and, for a view refreshed fast, the log table
+// and the triggers which log the changes of the base table. The view is refreshed completely at last, a failed
+// statement drops all of them.
+func (e *DDLExec) executeCreateMaterializedView(ctx context.Context, s *ast.CreateMaterializedViewStmt) (err error) {
+	// The query is checked and refreshed by an internal session. The table names of the query have been qualified
+	// by the schema names, and the user session mustn't hold the metadata lock of the base table, otherwise
+	// it blocks the creation of the triggers on the base table.
+	sysCtx, err := e.GetSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.ReleaseSysSession(ctx, sysCtx)
+	ret := &plannercore.PreprocessorReturn{}
+	if err = plannercore.Preprocess(ctx, sysCtx, s.Select, plannercore.WithPreprocessorReturn(ret)); err != nil {
+		return errors.Trace(err)
+	}
+	if ret.IsStaleness {
+		return exeerrors.ErrViewInvalid.GenWithStackByArgs(s.ViewName.Schema.L, s.ViewName.Name.L)
+	}
+
+	dom := domain.GetDomain(e.Ctx())
+	is := dom.InfoSchema()
+	schema, ok := is.SchemaByName(s.ViewName.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.ViewName.Schema)
+	}
+	if is.TableExists(s.ViewName.Schema, s.ViewName.Name) {
+		err = infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name})
+		if s.IfNotExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	// Always Use `format.RestoreNameBackQuotes` to restore `SELECT` statement despite the `ANSI_QUOTES` SQL Mode is enabled or not.
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	if err = s.Select.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return errors.Trace(err)
+	}
+	definition := sb.String()
+	def, err := mview.Analyze(is, definition)
+	if err != nil {
+		return err
+	}
+	method := s.RefreshMethod
+	reason := def.FastRefreshUnsupportedReason()
+	switch {
+	case method == model.RefreshMethodFast && reason != "":
+		return dbterror.ErrMViewNotFastRefreshable.GenWithStackByArgs(s.ViewName.Name.O, reason)
+	case method == model.RefreshMethodUnspecified && reason == "":
+		method = model.RefreshMethodFast
+	case method == model.RefreshMethodUnspecified:
+		method = model.RefreshMethodComplete
+	}
+	if s.RefreshInterval != "" {
+		if err = mview.CheckRefreshInterval(s.RefreshInterval); err != nil {
+			return dbterror.ErrMViewInvalidRefreshInterval.GenWithStackByArgs(s.RefreshInterval, s.ViewName.Name.O)
+		}
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnMView)
+	fieldTypes, err := materializedViewFieldTypes(ctx, sysCtx, definition)
+	if err != nil {
+		return err
+	}
+	if len(s.Cols) != len(fieldTypes) {
+		return dbterror.ErrViewWrongList
+	}
+
+	mvInfo, err := buildMaterializedViewInfo(e.Ctx(), schema, s, fieldTypes)
+	if err != nil {
+		return err
+	}
+	mvInfo.MaterializedView = &model.MaterializedViewInfo{
+		Definition:      definition,
+		RefreshMethod:   method,
+		RefreshInterval: s.RefreshInterval,
+	}
+	if def.Table != nil && def.Schema.L == schema.Name.L {
+		mvInfo.MaterializedView.BaseTable = def.Table.Name
+	}
+	infos := []*model.TableInfo{mvInfo}
+	if method == model.RefreshMethodFast {
+		logInfo, err := buildMaterializedViewLogInfo(e.Ctx(), schema, s.ViewName.Name, def)
+		if err != nil {
+			return err
+		}
+		mvInfo.MaterializedView.LogTable = logInfo.Name
+		infos = append(infos, logInfo)
+	}
+
+	d := dom.DDL()
+	if err = d.BatchCreateTableWithInfo(e.Ctx(), schema.Name, infos, ddl.OnExistError); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			e.dropMaterializedView(ctx, sysCtx, schema.Name, mvInfo)
+		}
+	}()
+	if method == model.RefreshMethodFast {
+		if err = e.createMaterializedViewLogTriggers(def, schema.Name, mvInfo); err != nil {
+			return err
+		}
+	}
+	_, err = mview.Refresh(ctx, sysCtx, schema.Name, mvInfo, model.RefreshMethodComplete)
+	return err
+}
+
+// materializedViewFieldTypes returns the types of the fields of the view query. It runs
+// `SELECT * FROM (query) LIMIT 0` so that the query is planned by the usual path without reading any row.
+func materializedViewFieldTypes(ctx context.Context, sctx sessionctx.Context, definition string) ([]*types.FieldType, error) {
+	sql := fmt.Sprintf("SELECT * FROM (%s) AS %s LIMIT 0", strings.ReplaceAll(definition, "%", "%%"), mviewSourceAlias)
+	rs, err := sctx.GetSQLExecutor().ExecuteInternal(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	if rs == nil {
+		return nil, errors.New("the query of the materialized view returns no result set")
+	}
+	defer terror.Call(rs.Close)
+	fields := rs.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, field := range fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	return fieldTypes, nil
+}
+
+// buildMaterializedViewInfo builds the table of the view, whose columns are derived from the fields of the query.
+func buildMaterializedViewInfo(sctx sessionctx.Context, schema *model.DBInfo, s *ast.CreateMaterializedViewStmt, fieldTypes []*types.FieldType) (*model.TableInfo, error) {
+	cols := make([]*ast.ColumnDef, 0, len(fieldTypes))
+	for i, ft := range fieldTypes {
+		colDef := &ast.ColumnDef{
+			Name: &ast.ColumnName{Name: s.Cols[i]},
+			Tp:   ddl.SelectFieldColumnType(ft),
+		}
+		if mysql.HasNotNullFlag(ft.GetFlag()) {
+			colDef.Options = append(colDef.Options, &ast.ColumnOption{Tp: ast.ColumnOptionNotNull})
+		}
+		cols = append(cols, colDef)
+	}
+	createStmt := &ast.CreateTableStmt{
+		Table: &ast.TableName{Schema: schema.Name, Name: s.ViewName.Name},
+		Cols:  cols,
+	}
+	return ddl.BuildTableInfoWithStmt(sctx, createStmt, schema.Charset, schema.Collate, schema.PlacementPolicyRef)
+}
+
+// buildMaterializedViewLogInfo builds the log table of the view, which has the columns of the base table the
+// view refers to and a sign column.
+func buildMaterializedViewLogInfo(sctx sessionctx.Context, schema *model.DBInfo, mvName model.CIStr, def *mview.Definition) (*model.TableInfo, error) {
+	logName := mview.LogTableName(mvName)
+	if len(logName.L) > mysql.MaxTableNameLength {
+		return nil, dbterror.ErrTooLongIdent.GenWithStackByArgs(logName.O)
+	}
+	cols := make([]*ast.ColumnDef, 0, len(def.Columns)+1)
+	for _, offset := range def.Columns {
+		col := def.Table.Columns[offset]
+		cols = append(cols, &ast.ColumnDef{
+			Name: &ast.ColumnName{Name: col.Name},
+			Tp:   ddl.SelectFieldColumnType(&col.FieldType),
+		})
+	}
+	cols = append(cols, &ast.ColumnDef{
+		Name:    &ast.ColumnName{Name: model.NewCIStr(mview.SignColumn)},
+		Tp:      types.NewFieldType(mysql.TypeTiny),
+		Options: []*ast.ColumnOption{{Tp: ast.ColumnOptionNotNull}},
+	})
+	createStmt := &ast.CreateTableStmt{
+		Table: &ast.TableName{Schema: schema.Name, Name: logName},
+		Cols:  cols,
+	}
+	logInfo, err := ddl.BuildTableInfoWithStmt(sctx, createStmt, schema.Charset, schema.Collate, schema.PlacementPolicyRef)
+	if err != nil {
+		return nil, err
+	}
+	logInfo.IsMaterializedViewLog = true
+	return logInfo, nil
+}
+
+// createMaterializedViewLogTriggers creates the triggers which log the changes of the base table.
+func (e *DDLExec) createMaterializedViewLogTriggers(def *mview.Definition, schema model.CIStr, mvInfo *model.TableInfo) error {
+	logTable := quoteTableName(schema, mvInfo.MaterializedView.LogTable)
+	baseTable := quoteTableName(def.Schema, def.Table.Name)
+	cols := make([]string, 0, len(def.Columns)+1)
+	for _, offset := range def.Columns {
+		cols = append(cols, stringutil.Escape(def.Table.Columns[offset].Name.O, mysql.ModeNone))
+	}
+	values := func(row string, sign int) string {
+		exprs := make([]string, 0, len(cols)+1)
+		for _, col := range cols {
+			exprs = append(exprs, row+"."+col)
+		}
+		return fmt.Sprintf("(%s, %d)", strings.Join(exprs, ", "), sign)
+	}
+	insertCols := strings.Join(append(cols, stringutil.Escape(mview.SignColumn, mysql.ModeNone)), ", ")
+
+	for _, event := range mviewLogEvents {
+		var rows string
+		switch event {
+		case model.TriggerEventInsert:
+			rows = values("NEW", 1)
+		case model.TriggerEventUpdate:
+			rows = values("OLD", -1) + ", " + values("NEW", 1)
+		case model.TriggerEventDelete:
+			rows = values("OLD", -1)
+		}
+		triggerName := quoteTableName(def.Schema, mview.TriggerName(mvInfo.ID, event))
+		sql := fmt.Sprintf("CREATE TRIGGER %s AFTER %s ON %s FOR EACH ROW INSERT INTO %s (%s) VALUES %s",
+			triggerName, event, baseTable, logTable, insertCols, rows)
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		createTrigger := stmt.(*ast.CreateTriggerStmt)
+		if user := e.Ctx().GetSessionVars().User; user != nil {
+			createTrigger.Definer = user
+		}
+		if err = domain.GetDomain(e.Ctx()).DDL().CreateTrigger(e.Ctx(), createTrigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *DDLExec) executeDropMaterializedView(ctx context.Context, s *ast.DropMaterializedViewStmt) error {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	tblInfo, err := is.TableInfoByName(s.ViewName.Schema, s.ViewName.Name)
+	if err != nil || !tblInfo.IsMaterializedView() {
+		// The error is reported by DropMaterializedView.
+		return domain.GetDomain(e.Ctx()).DDL().DropMaterializedView(e.Ctx(), s)
+	}
+	if err = e.dropMaterializedViewLogTriggers(is, tblInfo); err != nil {
+		return err
+	}
+	if err = domain.GetDomain(e.Ctx()).DDL().DropMaterializedView(e.Ctx(), s); err != nil {
+		return err
+	}
+	sysCtx, err := e.GetSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.ReleaseSysSession(ctx, sysCtx)
+	return mview.DeleteRefreshStatus(ctx, sysCtx.GetSQLExecutor(), tblInfo.ID)
+}
+
+// dropMaterializedViewLogTriggers drops the triggers which log the changes for the view. The triggers are
+// looked up in every schema since the base table may have been renamed to another schema.
+func (e *DDLExec) dropMaterializedViewLogTriggers(is infoschema.InfoSchema, mvInfo *model.TableInfo) error {
+	names := make(map[string]struct{}, len(mviewLogEvents))
+	for _, event := range mviewLogEvents {
+		names[mview.TriggerName(mvInfo.ID, event).L] = struct{}{}
+	}
+	for _, db := range is.AllSchemas() {
+		for _, tblInfo := range is.SchemaTableInfos(db.Name) {
+			for _, trigger := range tblInfo.Triggers {
+				if _, ok := names[trigger.Name.L]; !ok {
+					continue
+				}
+				stmt := &ast.DropTriggerStmt{IfExists: true, TriggerName: &ast.TableName{Schema: db.Name, Name: trigger.Name}}
+				if err := domain.GetDomain(e.Ctx()).DDL().DropTrigger(e.Ctx(), stmt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dropMaterializedView drops a view which fails to be created.
+func (e *DDLExec) dropMaterializedView(ctx context.Context, sysCtx sessionctx.Context, schema model.CIStr, mvInfo *model.TableInfo) {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	err := e.dropMaterializedViewLogTriggers(is, mvInfo)
+	if err == nil {
+		stmt := &ast.DropMaterializedViewStmt{IfExists: true, ViewName: &ast.TableName{Schema: schema, Name: mvInfo.Name}}
+		err = domain.GetDomain(e.Ctx()).DDL().DropMaterializedView(e.Ctx(), stmt)
+	}
+	if err == nil {
+		err = mview.DeleteRefreshStatus(ctx, sysCtx.GetSQLExecutor(), mvInfo.ID)
+	}
+	if err != nil {
+		logutil.Logger(ctx).Warn("drop the materialized view which fails to be created failed",
+			zap.String("schema", schema.O), zap.String("table", mvInfo.Name.O), zap.Error(err))
+	}
+}
+
+func (e *SimpleExec) executeRefreshMaterializedView(ctx context.Context, s *ast.RefreshMaterializedViewStmt) error {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	tblInfo, err := is.TableInfoByName(s.ViewName.Schema, s.ViewName.Name)
+	if err != nil {
+		return err
+	}
+	if !tblInfo.IsMaterializedView() {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(s.ViewName.Schema.O, s.ViewName.Name.O, "MATERIALIZED VIEW")
+	}
+	sysCtx, err := e.GetSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.ReleaseSysSession(ctx, sysCtx)
+	method, err := mview.Refresh(ctx, sysCtx, s.ViewName.Schema, tblInfo, s.Method)
+	if err != nil {
+		return err
+	}
+	if s.Method == model.RefreshMethodFast && method != model.RefreshMethodFast {
+		e.Ctx().GetSessionVars().StmtCtx.AppendNote(errors.NewNoStackErrorf(
+			"materialized view '%s' is refreshed completely since the log doesn't contain all the changes", tblInfo.Name.O))
+	}
+	return nil
+}
+
+func quoteTableName(schema, name model.CIStr) string {
+	return stringutil.Escape(schema.O, mysql.ModeNone) + "." + stringutil.Escape(name.O, mysql.ModeNone)
+}
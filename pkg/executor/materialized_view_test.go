@@ -0,0 +1,147 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDropMaterializedView(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (1, 20), (2, 30)")
+
+	tk.MustExec("create materialized view mv1 as select a, count(*) cnt, sum(b) s, count(b) cb from t group by a")
+	tk.MustQuery("select * from mv1 order by a").Check(testkit.Rows("1 2 30 2", "2 1 30 1"))
+	tk.MustExec("create materialized view mv2 (x, y) refresh complete as select a, max(b) from t group by a")
+	tk.MustQuery("select * from mv2 order by x").Check(testkit.Rows("1 20", "2 30"))
+	tk.MustGetErrCode("create materialized view mv3 refresh fast as select a, max(b) from t group by a", errno.ErrMViewNotFastRefreshable)
+	tk.MustGetErrCode("create materialized view mv3 refresh every 'abc' as select a from t", errno.ErrMViewInvalidRefreshInterval)
+	tk.MustGetErrCode("create materialized view mv3 (x) as select a, b from t", errno.ErrViewWrongList)
+	tk.MustGetErrCode("create materialized view mv1 as select a from t", errno.ErrTableExists)
+	tk.MustExec("create materialized view if not exists mv1 as select a from t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1050 Table 'test.mv1' already exists"))
+
+	tk.MustQuery(`select table_name, refresh_method, refresh_interval, fast_refreshable, last_refresh_method, last_error
+		from information_schema.materialized_views where table_schema = 'test' order by table_name`).Check(testkit.Rows(
+		"mv1 FAST <nil> YES COMPLETE <nil>",
+		"mv2 COMPLETE <nil> NO COMPLETE <nil>",
+	))
+	require.Equal(t, []model.CIStr{model.NewCIStr("mv1"), model.NewCIStr("mv2")}, dom.InfoSchema().GetTableMaterializedViews("test", "t"))
+
+	// The views and the log can't be written by the user statements.
+	tk.MustGetErrCode("insert into mv1 values (3, 1, 1, 1)", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("update mv1 set cnt = 0", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from mv2", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from `mlog$_mv1`", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("drop table mv1", errno.ErrWrongObject)
+	tk.MustGetErrCode("truncate table `mlog$_mv1`", errno.ErrWrongObject)
+	tk.MustGetErrCode("refresh materialized view t", errno.ErrWrongObject)
+
+	tk.MustExec("drop materialized view mv1")
+	require.Equal(t, []model.CIStr{model.NewCIStr("mv2")}, dom.InfoSchema().GetTableMaterializedViews("test", "t"))
+	tk.MustExec("drop materialized view mv2")
+	require.Empty(t, dom.InfoSchema().GetTableMaterializedViews("test", "t"))
+	tk.MustGetErrCode("drop materialized view mv1", errno.ErrBadTable)
+	tk.MustExec("drop materialized view if exists mv1")
+	tk.MustQuery("show tables").Check(testkit.Rows("t"))
+	tk.MustQuery("show triggers").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from mysql.tidb_mview_refresh").Check(testkit.Rows("0"))
+	// The base table is writable without the log triggers.
+	tk.MustExec("insert into t values (3, 40)")
+}
+
+func TestRefreshMaterializedView(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 10), (1, 20), (2, 30)")
+	tk.MustExec("create materialized view mv as select a, count(*) cnt, sum(b) s, count(b) cb from t group by a")
+	tk.MustExec("create materialized view mv_all as select count(*) cnt, sum(b) s, count(b) cb from t")
+	tk.MustExec("create materialized view mv_complete refresh complete as select a, max(b) from t group by a")
+
+	tk.MustExec("insert into t values (2, null), (3, 50)")
+	tk.MustExec("update t set b = b + 1 where a = 1")
+	tk.MustExec("delete from t where b = 30")
+	tk.MustQuery("select a, cnt, s, cb from mv order by a").Check(testkit.Rows("1 2 30 2", "2 1 30 1"))
+	tk.MustQuery("select count(*) > 0 from `mlog$_mv`").Check(testkit.Rows("1"))
+
+	tk.MustExec("refresh materialized view mv fast")
+	tk.MustQuery("select a, cnt, s, cb from mv order by a").Check(testkit.Rows("1 2 32 2", "2 1 <nil> 0", "3 1 50 1"))
+	tk.MustQuery("select count(*) from `mlog$_mv`").Check(testkit.Rows("0"))
+	tk.MustQuery("select last_refresh_method from information_schema.materialized_views where table_name = 'mv'").Check(testkit.Rows("FAST"))
+	tk.MustExec("refresh materialized view mv_all")
+	tk.MustQuery("select * from mv_all").Check(testkit.Rows("4 82 3"))
+
+	// The groups whose rows are all deleted are removed from the view.
+	tk.MustExec("delete from t where a = 2")
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select a, cnt, s, cb from mv order by a").Check(testkit.Rows("1 2 32 2", "3 1 50 1"))
+	tk.MustExec("delete from t")
+	tk.MustExec("refresh materialized view mv_all fast")
+	tk.MustQuery("select * from mv_all").Check(testkit.Rows("0 <nil> 0"))
+
+	// A truncated base table makes the fast refresh fall back to the complete refresh.
+	tk.MustExec("insert into t values (5, 1)")
+	tk.MustExec("truncate table t")
+	tk.MustExec("insert into t values (6, 2)")
+	tk.MustExec("refresh materialized view mv fast")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 materialized view 'mv' is refreshed completely since the log doesn't contain all the changes"))
+	tk.MustQuery("select a, cnt, s, cb from mv").Check(testkit.Rows("6 1 2 1"))
+
+	tk.MustGetErrCode("refresh materialized view mv_complete fast", errno.ErrMViewNotFastRefreshable)
+	tk.MustExec("refresh materialized view mv_complete")
+	tk.MustQuery("select * from mv_complete").Check(testkit.Rows("6 2"))
+}
+
+func TestMaterializedViewRewrite(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (1, 2, 20), (2, 1, 30)")
+	tk.MustExec("create materialized view mv as select a, b, count(*) cnt, sum(c) s, count(c) cc from t where c > 0 group by a, b")
+
+	tk.MustExec("insert into t values (3, 1, 40)")
+	// The rewrite is disabled by default.
+	tk.MustQuery("select a, sum(c) from t where c > 0 group by a order by a").Check(testkit.Rows("1 30", "2 30", "3 40"))
+
+	tk.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = 1")
+	// The rewritten query reads the stale view.
+	tk.MustQuery("select a, sum(c) from t where c > 0 group by a order by a").Check(testkit.Rows("1 30", "2 30"))
+	tk.MustQuery("select a, b, count(*) from t where c > 0 group by a, b having count(*) > 0 order by a, b").Check(testkit.Rows("1 1 1", "1 2 1", "2 1 1"))
+	tk.MustQuery("select count(*) from t where c > 0").Check(testkit.Rows("3"))
+	tk.MustQuery("select sum(c) + 1 from t where c > 0 group by a, b order by 1").Check(testkit.Rows("11", "21", "31"))
+	tk.MustQuery("explain format = 'brief' select count(*) from t where c > 0").CheckContain("table:mv")
+	// The queries which can't be derived from the view read the base table.
+	tk.MustQuery("select a, sum(c) from t where c > 10 group by a order by a").Check(testkit.Rows("1 20", "2 30", "3 40"))
+	tk.MustQuery("select a, max(c) from t where c > 0 group by a order by a").Check(testkit.Rows("1 20", "2 30", "3 40"))
+	tk.MustQuery("select c from t where c > 0 order by c").Check(testkit.Rows("10", "20", "30", "40"))
+
+	tk.MustExec("begin")
+	tk.MustQuery("select count(*) from t where c > 0").Check(testkit.Rows("4"))
+	tk.MustExec("commit")
+
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select a, sum(c) from t where c > 0 group by a order by a").Check(testkit.Rows("1 30", "2 30", "3 40"))
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/memory"
+)
+
+// MergeExec represents a MERGE executor. It reads the rows of the join of the target and the source, and each
+// row is written by the first clause whose condition is satisfied. The clauses reuse the update, delete and
+// insert executors, which have no child.
+type MergeExec struct {
+	exec.BaseExecutor
+
+	tbl           table.Table
+	tblColPosInfo plannercore.TblColPosInfo
+	clauses       []*mergeClauseExec
+
+	// mergedHandles are the target rows which have been updated or deleted, a target row can't be matched
+	// by more than one source row.
+	mergedHandles *kv.MemAwareHandleMap[struct{}]
+	rowIdx        int
+	drained       bool
+	memTracker    *memory.Tracker
+}
+
+type mergeClauseExec struct {
+	matched   bool
+	condition expression.Expression
+
+	update *UpdateExec
+	delete *DeleteExec
+	insert *InsertExec
+	values []expression.Expression
+}
+
+type mergeWriter interface {
+	WithForeignKeyTrigger
+	WithTrigger
+}
+
+func (c *mergeClauseExec) writer() mergeWriter {
+	switch {
+	case c.update != nil:
+		return c.update
+	case c.delete != nil:
+		return c.delete
+	default:
+		return c.insert
+	}
+}
+
+// Open implements the Executor Open interface.
+func (e *MergeExec) Open(ctx context.Context) error {
+	e.memTracker = memory.NewTracker(e.ID(), -1)
+	e.memTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.MemTracker)
+	for _, clause := range e.clauses {
+		switch {
+		case clause.update != nil:
+			clause.update.memTracker = e.memTracker
+		case clause.delete != nil:
+			clause.delete.memTracker = e.memTracker
+		default:
+			if err := clause.insert.Open(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return exec.Open(ctx, e.Children(0))
+}
+
+// Next implements the Executor Next interface.
+func (e *MergeExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.drained {
+		return nil
+	}
+	e.drained = true
+	fields := exec.RetTypes(e.Children(0))
+	colsInfo := plannercore.GetUpdateColumnsInfo(map[int64]table.Table{e.tblColPosInfo.TblID: e.tbl},
+		plannercore.TblColPosInfoSlice{e.tblColPosInfo}, len(fields))
+	for _, clause := range e.clauses {
+		if clause.update != nil {
+			clause.update.evalBuffer = chunk.MutRowFromTypes(fields)
+		}
+	}
+	chk := exec.TryNewCacheChunk(e.Children(0))
+	memUsageOfChk := int64(0)
+	for {
+		e.memTracker.Consume(-memUsageOfChk)
+		if err := exec.Next(ctx, e.Children(0), chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		memUsageOfChk = chk.MemoryUsage()
+		e.memTracker.Consume(memUsageOfChk)
+		for i := 0; i < chk.NumRows(); i++ {
+			if err := e.mergeRow(ctx, chk.GetRow(i), fields, colsInfo); err != nil {
+				return err
+			}
+			e.rowIdx++
+		}
+		chk = chunk.Renew(chk, e.MaxChunkSize())
+	}
+}
+
+func (e *MergeExec) mergeRow(ctx context.Context, chunkRow chunk.Row, fields []*types.FieldType, colsInfo []*table.Column) error {
+	row := chunkRow.GetDatumRow(fields)
+	matched := !unmatchedOuterRow(e.tblColPosInfo, row)
+	for _, clause := range e.clauses {
+		if clause.matched != matched {
+			continue
+		}
+		if clause.condition != nil {
+			ok, _, err := expression.EvalBool(e.Ctx().GetExprCtx().GetEvalCtx(), expression.CNFExprs{clause.condition}, chunkRow)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		if !matched {
+			return e.insertRow(ctx, clause, chunkRow)
+		}
+
+		handle, err := e.tblColPosInfo.HandleCols.BuildHandleByDatums(row)
+		if err != nil {
+			return err
+		}
+		if _, ok := e.mergedHandles.Get(handle); ok {
+			return exeerrors.ErrMergeRowMatchedMoreThanOnce.GenWithStackByArgs(handle.String(), e.tbl.Meta().Name.O)
+		}
+		memDelta := e.mergedHandles.Set(handle, struct{}{}) + int64(handle.ExtraMemSize())
+		e.memTracker.Consume(memDelta)
+		e.Ctx().GetSessionVars().StmtCtx.AddRecordRows(1)
+		if clause.delete != nil {
+			return clause.delete.removeRow(ctx, e.tbl, handle, row[e.tblColPosInfo.Start:e.tblColPosInfo.End])
+		}
+		return e.updateRow(ctx, clause.update, row, colsInfo)
+	}
+	return nil
+}
+
+func (e *MergeExec) updateRow(ctx context.Context, upd *UpdateExec, row []types.Datum, colsInfo []*table.Column) error {
+	if err := upd.prepare(row); err != nil {
+		return err
+	}
+	newRow, err := upd.composeNewRow(e.rowIdx, row, colsInfo)
+	if err != nil {
+		return err
+	}
+	if upd.virtualAssignmentsOffset < len(upd.OrderedList) {
+		newRow, err = upd.composeGeneratedColumns(e.rowIdx, newRow, colsInfo)
+		if err != nil {
+			return err
+		}
+	}
+	return upd.exec(ctx, e.Children(0).Schema(), row, newRow)
+}
+
+func (e *MergeExec) insertRow(ctx context.Context, clause *mergeClauseExec, chunkRow chunk.Row) error {
+	vals := make([]types.Datum, 0, len(clause.values))
+	for _, expr := range clause.values {
+		val, err := expr.Eval(e.Ctx().GetExprCtx().GetEvalCtx(), chunkRow)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, val)
+	}
+	ins := clause.insert
+	ins.rowCount++
+	row, err := ins.getRow(ctx, vals)
+	if err != nil {
+		return err
+	}
+	return ins.exec(ctx, [][]types.Datum{row})
+}
+
+// Close implements the Executor Close interface.
+func (e *MergeExec) Close() error {
+	if e.memTracker != nil {
+		defer e.memTracker.ReplaceBytesUsed(0)
+	}
+	var firstErr error
+	for _, clause := range e.clauses {
+		if clause.insert == nil {
+			continue
+		}
+		if err := clause.insert.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := exec.Close(e.Children(0)); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// GetFKChecks implements WithForeignKeyTrigger interface.
+func (e *MergeExec) GetFKChecks() []*FKCheckExec {
+	var fkChecks []*FKCheckExec
+	for _, clause := range e.clauses {
+		fkChecks = append(fkChecks, clause.writer().GetFKChecks()...)
+	}
+	return fkChecks
+}
+
+// GetFKCascades implements WithForeignKeyTrigger interface.
+func (e *MergeExec) GetFKCascades() []*FKCascadeExec {
+	var fkCascades []*FKCascadeExec
+	for _, clause := range e.clauses {
+		fkCascades = append(fkCascades, clause.writer().GetFKCascades()...)
+	}
+	return fkCascades
+}
+
+// HasFKCascades implements WithForeignKeyTrigger interface.
+func (e *MergeExec) HasFKCascades() bool {
+	for _, clause := range e.clauses {
+		if clause.writer().HasFKCascades() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTriggers implements WithTrigger interface.
+func (e *MergeExec) HasTriggers() bool {
+	for _, clause := range e.clauses {
+		if clause.writer().HasTriggers() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, c int as (v * 2), k varchar(10), unique key (k))")
+	tk.MustExec("create table s (id int, v int, op varchar(10))")
+	tk.MustExec("insert into t (id, v, k) values (1, 10, 'a'), (2, 20, 'b'), (3, 30, 'c')")
+	tk.MustExec("insert into s values (1, 11, 'upd'), (2, 0, 'del'), (4, 40, 'ins'), (5, 50, 'skip')")
+
+	tk.MustExec(`merge into t using s on t.id = s.id
+		when matched and s.op = 'del' then delete
+		when matched then update set v = s.v
+		when not matched and s.op = 'ins' then insert (id, v, k) values (s.id, s.v, concat('k', s.id))`)
+	require.Equal(t, uint64(3), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 11 22 a", "3 30 60 c", "4 40 80 k4"))
+
+	// The target and the source can have aliases, and the source can be a subquery.
+	tk.MustExec(`merge into t as x using (select id + 1 as id, v from s where op = 'ins') as y on x.id = y.id
+		when matched then update set x.v = x.v + y.v
+		when not matched then insert values (y.id, y.v, default, null)`)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 11 22 a", "3 30 60 c", "4 40 80 k4", "5 40 80 <nil>"))
+	tk.MustExec("merge into t using s on t.id = s.id when matched then update set v = default")
+	tk.MustQuery("select * from t where id in (1, 4, 5) order by id").Check(testkit.Rows("1 <nil> <nil> a", "4 <nil> <nil> k4", "5 <nil> <nil> <nil>"))
+
+	// The inserted rows can't refer to the target, and the updated columns must be the target columns.
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when not matched then insert (id) values (t.v)", errno.ErrBadField)
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when matched then update set s.v = 1", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when matched then update set c = 1", errno.ErrBadGeneratedColumn)
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when not matched then insert (id, v) values (s.id)", errno.ErrWrongValueCountOnRow)
+	tk.MustGetErrCode("merge into t using t on t.id = t.id when matched then delete", errno.ErrNonuniqTable)
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when not matched then insert (id, k) values (s.id, 'a')", errno.ErrDupEntry)
+
+	// A target row can't be updated or deleted by more than one source row.
+	tk.MustExec("insert into s values (1, 12, 'upd')")
+	tk.MustGetErrCode("merge into t using s on t.id = s.id when matched then update set v = s.v", errno.ErrMergeRowMatchedMoreThanOnce)
+	tk.MustExec("merge into t using s on t.id = s.id when matched and s.v = 12 then update set v = s.v")
+	tk.MustQuery("select v from t where id = 1").Check(testkit.Rows("12"))
+	tk.MustQuery("explain format = 'brief' merge into t using s on t.id = s.id when matched then delete").CheckContain("Merge")
+
+	// The target can be read by the source, and the table without the primary key uses the extra handle.
+	tk.MustExec("create table n (a int, b int)")
+	tk.MustExec("insert into n values (1, 1), (2, 2)")
+	tk.MustExec("merge into n using (select a, b from n) as m on n.a = m.a + 1 when matched then update set b = n.b + m.b when not matched then insert values (m.a + 10, 0)")
+	tk.MustQuery("select * from n order by a").Check(testkit.Rows("1 1", "2 3", "12 0"))
+}
+
+func TestMergeWithTriggersAndForeignKeys(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table p (id int primary key)")
+	tk.MustExec("create table c (id int primary key, pid int, foreign key (pid) references p (id) on delete cascade)")
+	tk.MustExec("create table log (msg varchar(20))")
+	tk.MustExec("create trigger tr after insert on p for each row insert into log values (concat('ins ', new.id))")
+	tk.MustExec("insert into p values (1), (2)")
+	tk.MustExec("insert into c values (1, 1), (2, 2)")
+	tk.MustExec("create table s (id int)")
+	tk.MustExec("insert into s values (1), (3)")
+
+	tk.MustExec("merge into p using s on p.id = s.id when matched then delete when not matched then insert values (s.id)")
+	tk.MustQuery("select * from p order by id").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select * from c").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select * from log order by msg").Check(testkit.Rows("ins 1", "ins 2", "ins 3"))
+	tk.MustGetErrCode("merge into c using s on c.id = s.id when not matched then insert values (s.id, 5)", errno.ErrNoReferencedRow2)
+}
@@ -32,6 +32,17 @@ type dataInfo struct {
 	chk         *chunk.Chunk
 	remaining   uint64
 	accumulated uint64
+	// src is the chunk fetched from the child, the columns of chk refer to its columns.
+	src     *chunk.Chunk
+	numRows uint64
+	// srcIdx and resIdx are the indexes of src and the window function results of chk on disk, they are -1 if the
+	// chunk isn't spilled.
+	srcIdx   int
+	resIdx   int
+	memUsage int64
+	// referred indicates the partial results of the window functions refer to the rows of src, so it can't be
+	// spilled until the partial results are reset.
+	referred bool
 }
 
 // PipelinedWindowExec is the executor for window functions.
@@ -68,8 +79,8 @@ type PipelinedWindowExec struct {
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
 
-	// rows keeps rows starting from curStartRow
-	rows                     []chunk.Row
+	// spillHelper keeps the rows starting from curStartRow, they may be spilled to disk.
+	spillHelper              *windowSpillHelper
 	rowCnt                   uint64
 	whole                    bool
 	isRangeFrame             bool
@@ -79,6 +90,10 @@ type PipelinedWindowExec struct {
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
+	if e.spillHelper != nil {
+		e.spillHelper.close()
+		e.spillHelper = nil
+	}
 	return errors.Trace(e.BaseExecutor.Close())
 }
 
@@ -96,8 +111,12 @@ func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
 			e.slidingWindowFuncs[i] = slidingWindowAggFunc
 		}
 	}
-	e.rows = make([]chunk.Row, 0)
-	return e.BaseExecutor.Open(ctx)
+	err = e.BaseExecutor.Open(ctx)
+	if err != nil {
+		return err
+	}
+	e.spillHelper = newWindowSpillHelper(&e.BaseExecutor, e.numWindowFuncs, e.copyChk)
+	return nil
 }
 
 func (e *PipelinedWindowExec) firstResultChunkNotReady() bool {
@@ -113,6 +132,10 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 	chk.Reset()
 
 	for e.firstResultChunkNotReady() {
+		err = e.spillHelper.spillIfNeeded(e.data, e.dataIdx)
+		if err != nil {
+			return err
+		}
 		// we firstly gathering enough rows and consume them, until we are able to produce.
 		// for unbounded frame, it needs consume the whole partition before being able to produce, in this case
 		// e.p.enoughToProduce will be false until so.
@@ -151,18 +174,29 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 
 		// e.p is ready to produce data
 		if len(e.data) > e.dataIdx && e.data[e.dataIdx].remaining != 0 {
-			produced, err := e.produce(e.Ctx(), e.data[e.dataIdx].chk, e.data[e.dataIdx].remaining)
+			d := &e.data[e.dataIdx]
+			err = e.spillHelper.loadChk(d)
 			if err != nil {
 				return err
 			}
-			e.data[e.dataIdx].remaining -= produced
-			if e.data[e.dataIdx].remaining == 0 {
+			produced, err := e.produce(e.Ctx(), d.chk, d.remaining)
+			if err != nil {
+				return err
+			}
+			d.remaining -= produced
+			if d.remaining == 0 {
+				e.spillHelper.track(d)
 				e.dataIdx++
 			}
 		}
 	}
 	if len(e.data) > 0 {
+		err = e.spillHelper.loadChk(&e.data[0])
+		if err != nil {
+			return err
+		}
 		chk.SwapColumns(e.data[0].chk)
+		e.spillHelper.release(&e.data[0])
 		e.data = e.data[1:]
 		e.dataIdx--
 	}
@@ -171,7 +205,7 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 
 func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error) {
 	e.newPartition = true
-	if len(e.rows) == 0 {
+	if e.rowCnt == e.rowStart {
 		// if getRowsInPartition is called for the first time, we ignore it as a new partition
 		e.newPartition = false
 	}
@@ -198,9 +232,6 @@ func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error
 	}
 	begin, end := e.groupChecker.GetNextGroup()
 	e.rowToConsume += uint64(end - begin)
-	for i := begin; i < end; i++ {
-		e.rows = append(e.rows, e.childResult.GetRow(i))
-	}
 	return
 }
 
@@ -224,7 +255,16 @@ func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (eof bool, err err
 		return false, err
 	}
 	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
+	e.data = append(e.data, dataInfo{
+		chk:         resultChk,
+		remaining:   uint64(numRows),
+		accumulated: e.accumulated,
+		src:         childResult,
+		numRows:     uint64(numRows),
+		srcIdx:      -1,
+		resIdx:      -1,
+	})
+	e.spillHelper.track(&e.data[len(e.data)-1])
 
 	e.childResult = childResult
 	return false, nil
@@ -240,12 +280,14 @@ func (e *PipelinedWindowExec) copyChk(src, dst *chunk.Chunk) error {
 	return nil
 }
 
-func (e *PipelinedWindowExec) getRow(i uint64) chunk.Row {
-	return e.rows[i-e.rowStart]
-}
-
-func (e *PipelinedWindowExec) getRows(start, end uint64) []chunk.Row {
-	return e.rows[start-e.rowStart : end-e.rowStart]
+// partitionRows returns the rows of the current partition.
+func (e *PipelinedWindowExec) partitionRows() windowRows {
+	return windowRows{
+		h:      e.spillHelper,
+		data:   e.data,
+		skip:   e.dataIdx,
+		offset: e.dropped - e.rowStart,
+	}
 }
 
 // finish is called upon a whole partition is consumed
@@ -258,12 +300,20 @@ func (e *PipelinedWindowExec) getStart(ctx sessionctx.Context) (uint64, error) {
 		return 0, nil
 	}
 	if e.isRangeFrame {
+		rows := e.partitionRows()
+		curRow, err := rows.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var start uint64
 		for start = max(e.lastStartRow, e.stagedStartRow); start < e.rowCnt; start++ {
 			var res int64
-			var err error
+			row, err := rows.getRow(start)
+			if err != nil {
+				return 0, err
+			}
 			for i := range e.orderByCols {
-				res, _, err = e.start.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), e.start.CompareCols[i], e.start.CalcFuncs[i], e.getRow(start), e.getRow(e.curRowIdx))
+				res, _, err = e.start.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), e.start.CompareCols[i], e.start.CalcFuncs[i], row, curRow)
 				if err != nil {
 					return 0, err
 				}
@@ -298,12 +348,20 @@ func (e *PipelinedWindowExec) getEnd(ctx sessionctx.Context) (uint64, error) {
 		return e.rowCnt, nil
 	}
 	if e.isRangeFrame {
+		rows := e.partitionRows()
+		curRow, err := rows.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var end uint64
 		for end = max(e.lastEndRow, e.stagedEndRow); end < e.rowCnt; end++ {
 			var res int64
-			var err error
+			row, err := rows.getRow(end)
+			if err != nil {
+				return 0, err
+			}
 			for i := range e.orderByCols {
-				res, _, err = e.end.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), e.end.CalcFuncs[i], e.end.CompareCols[i], e.getRow(e.curRowIdx), e.getRow(end))
+				res, _, err = e.end.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), e.end.CalcFuncs[i], e.end.CompareCols[i], curRow, row)
 				if err != nil {
 					return 0, err
 				}
@@ -342,6 +400,10 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		enough bool
 	)
 	for remained > 0 {
+		err = e.spillHelper.spillIfNeeded(e.data, e.dataIdx)
+		if err != nil {
+			return
+		}
 		enough, err = e.enoughToProduce(ctx)
 		if err != nil {
 			return
@@ -380,21 +442,16 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 			}
 		} else {
 			e.emptyFrame = false
+			rows := e.partitionRows()
 			for i, wf := range e.windowFuncs {
 				slidingWindowAggFunc := e.slidingWindowFuncs[i]
 				if e.lastStartRow != start || e.lastEndRow != end {
 					if slidingWindowAggFunc != nil && e.initializedSlidingWindow {
-						err = slidingWindowAggFunc.Slide(ctx.GetExprCtx().GetEvalCtx(), e.getRow, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
+						err = rows.slide(ctx.GetExprCtx().GetEvalCtx(), slidingWindowAggFunc, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
 					} else {
-						// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
-						// whether elements inside deque are out of current window.
-						if minMaxSlidingWindowAggFunc, ok := wf.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-							// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
-							minMaxSlidingWindowAggFunc.SetWindowStart(start)
-						}
 						// TODO(zhifeng): track memory usage here
 						wf.ResetPartialResult(e.partialResults[i])
-						_, err = wf.UpdatePartialResult(ctx.GetExprCtx().GetEvalCtx(), e.getRows(start, end), e.partialResults[i])
+						err = rows.updatePartialResult(ctx.GetExprCtx().GetEvalCtx(), wf, e.partialResults[i], start, end)
 					}
 				}
 				if err != nil {
@@ -415,9 +472,7 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 	}
 	extend := min(e.curRowIdx, e.lastEndRow, e.lastStartRow)
 	if extend > e.rowStart {
-		numDrop := extend - e.rowStart
-		e.dropped += numDrop
-		e.rows = e.rows[numDrop:]
+		e.dropped += extend - e.rowStart
 		e.rowStart = extend
 	}
 	return
@@ -450,13 +505,12 @@ func (e *PipelinedWindowExec) reset() {
 	e.emptyFrame = false
 	e.curRowIdx = 0
 	e.whole = false
-	numDrop := e.rowCnt - e.rowStart
-	e.dropped += numDrop
-	e.rows = e.rows[numDrop:]
+	e.dropped += e.rowCnt - e.rowStart
 	e.rowStart = 0
 	e.rowCnt = 0
 	e.initializedSlidingWindow = false
 	for i, windowFunc := range e.windowFuncs {
 		windowFunc.ResetPartialResult(e.partialResults[i])
 	}
+	e.spillHelper.unrefer(e.data)
 }
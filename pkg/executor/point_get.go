@@ -149,6 +149,7 @@ type PointGetExecutor struct {
 	done             bool
 	lock             bool
 	lockWaitTime     int64
+	lockSkipLocked   bool
 	rowDecoder       *rowcodec.ChunkDecoder
 
 	columns []*model.ColumnInfo
@@ -207,10 +208,12 @@ func (e *PointGetExecutor) Init(p *plannercore.PointGetPlan) {
 	if e.tblInfo.TempTableType == model.TempTableNone {
 		e.lock = p.Lock
 		e.lockWaitTime = p.LockWaitTime
+		e.lockSkipLocked = p.LockSkipLocked
 	} else {
 		// Temporary table should not do any lock operations
 		e.lock = false
 		e.lockWaitTime = 0
+		e.lockSkipLocked = false
 	}
 	e.rowDecoder = decoder
 	e.partitionDefIdx = p.PartitionIdx
@@ -263,6 +266,16 @@ func (e *PointGetExecutor) Close() error {
 
 // Next implements the Executor interface.
 func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	err := e.next(ctx, req)
+	if e.lockSkipLocked && isLockSkippedErr(err) {
+		// The row is locked by another transaction, `SELECT ... SKIP LOCKED` returns no row instead of an error.
+		req.Reset()
+		return nil
+	}
+	return err
+}
+
+func (e *PointGetExecutor) next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if e.done {
 		return nil
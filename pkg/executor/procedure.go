@@ -0,0 +1,470 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+)
+
+const (
+	// routinesTable is the system table which stores the definitions of the stored routines.
+	routinesTable = "routines"
+	// routineTypeProcedure is the type of the stored procedures in `mysql.routines`.
+	routineTypeProcedure = "PROCEDURE"
+
+	selectRoutinesSQL = `SELECT routine_schema, name, param_list, body, definer, sql_mode, character_set_client,
+		collation_connection, db_collation, created, last_altered, comment FROM %n.%n WHERE type = %?`
+)
+
+// routineInfo is a stored routine read from `mysql.routines`.
+type routineInfo struct {
+	schema              string
+	name                string
+	paramList           string
+	body                string
+	definer             string
+	sqlMode             string
+	charsetClient       string
+	collationConnection string
+	dbCollation         string
+	created             types.Time
+	lastAltered         types.Time
+	comment             string
+}
+
+// createStmtText returns the `CREATE PROCEDURE` statement of the procedure.
+func (r *routineInfo) createStmtText(sqlMode mysql.SQLMode) string {
+	return fmt.Sprintf("CREATE PROCEDURE %s(%s)\n%s", stringutil.Escape(r.name, sqlMode), r.paramList, r.body)
+}
+
+// definerIdentity returns the definer of the routine, it returns nil if the routine is created without a user.
+func (r *routineInfo) definerIdentity() *auth.UserIdentity {
+	idx := strings.LastIndex(r.definer, "@")
+	if idx <= 0 {
+		return nil
+	}
+	return &auth.UserIdentity{Username: r.definer[:idx], Hostname: r.definer[idx+1:]}
+}
+
+// getProcedures reads the stored procedures, the procedure is read only if the schema and the name are not empty.
+func getProcedures(ctx context.Context, sctx sessionctx.Context, schema, name string) ([]*routineInfo, error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	sql := selectRoutinesSQL + " ORDER BY routine_schema, name"
+	args := []any{mysql.SystemDB, routinesTable, routineTypeProcedure}
+	if schema != "" {
+		sql = selectRoutinesSQL + " AND routine_schema = %? AND name = %?"
+		args = append(args, strings.ToLower(schema), name)
+	}
+	rows, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	routines := make([]*routineInfo, 0, len(rows))
+	for _, row := range rows {
+		routines = append(routines, &routineInfo{
+			schema:              row.GetString(0),
+			name:                row.GetString(1),
+			paramList:           row.GetString(2),
+			body:                row.GetString(3),
+			definer:             row.GetString(4),
+			sqlMode:             row.GetString(5),
+			charsetClient:       row.GetString(6),
+			collationConnection: row.GetString(7),
+			dbCollation:         row.GetString(8),
+			created:             row.GetTime(9),
+			lastAltered:         row.GetTime(10),
+			comment:             row.GetString(11),
+		})
+	}
+	return routines, nil
+}
+
+// getProcedure reads the stored procedure, it returns nil if the procedure doesn't exist.
+func getProcedure(ctx context.Context, sctx sessionctx.Context, schema, name string) (*routineInfo, error) {
+	routines, err := getProcedures(ctx, sctx, schema, name)
+	if err != nil || len(routines) == 0 {
+		return nil, err
+	}
+	return routines[0], nil
+}
+
+// routineIsVisible checks whether the current user can see the routines of the schema.
+func routineIsVisible(sctx sessionctx.Context, schema string) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	return checker == nil || sctx.GetSessionVars().User == nil ||
+		checker.DBIsVisible(sctx.GetSessionVars().ActiveRoles, schema)
+}
+
+func (e *SimpleExec) executeCreateProcedure(ctx context.Context, s *ast.ProcedureInfo) error {
+	sessVars := e.Ctx().GetSessionVars()
+	schema, name := s.ProcedureName.Schema, s.ProcedureName.Name
+	dbInfo, ok := e.is.SchemaByName(schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema.O)
+	}
+	if err := checkProcedure(s); err != nil {
+		return err
+	}
+	routine, err := getProcedure(ctx, e.Ctx(), schema.L, name.O)
+	if err != nil {
+		return err
+	}
+	if routine != nil {
+		err = exeerrors.ErrSpAlreadyExists.FastGenByArgs(routineTypeProcedure, name.O)
+		if s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	var definer string
+	if user := sessVars.User; user != nil {
+		definer = (&auth.UserIdentity{Username: user.AuthUsername, Hostname: user.AuthHostname}).String()
+	}
+	charsetClient, err := sessVars.GetSessionOrGlobalSystemVar(ctx, variable.CharacterSetClient)
+	if err != nil {
+		return err
+	}
+	collationConnection, err := sessVars.GetSessionOrGlobalSystemVar(ctx, variable.CollationConnection)
+	if err != nil {
+		return err
+	}
+	sqlMode, _ := sessVars.GetSystemVar(variable.SQLModeVar)
+	dbCollation := dbInfo.Collate
+	if dbCollation == "" {
+		dbCollation = mysql.DefaultCollationName
+	}
+	internalCtx := kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	_, _, err = e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(internalCtx, nil,
+		`INSERT INTO %n.%n (routine_schema, name, type, param_list, body, definer, sql_mode, character_set_client,
+		collation_connection, db_collation, comment) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, '')`,
+		mysql.SystemDB, routinesTable, schema.L, name.O, routineTypeProcedure, s.ProcedureParamStr, s.ProcedureBody.Text(),
+		definer, sqlMode, charsetClient, collationConnection, dbCollation)
+	return err
+}
+
+func (e *SimpleExec) executeDropProcedure(ctx context.Context, s *ast.DropProcedureStmt) error {
+	schema, name := s.ProcedureName.Schema, s.ProcedureName.Name
+	routine, err := getProcedure(ctx, e.Ctx(), schema.L, name.O)
+	if err != nil {
+		return err
+	}
+	if routine == nil {
+		err = exeerrors.ErrSpDoesNotExist.FastGenByArgs(routineTypeProcedure, schema.O+"."+name.O)
+		if s.IfExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	internalCtx := kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	_, _, err = e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(internalCtx, nil,
+		"DELETE FROM %n.%n WHERE routine_schema = %? AND name = %? AND type = %?",
+		mysql.SystemDB, routinesTable, schema.L, name.O, routineTypeProcedure)
+	return err
+}
+
+// dropSchemaRoutines drops the stored routines of the dropped schema.
+func dropSchemaRoutines(sctx sessionctx.Context, schema model.CIStr) error {
+	internalCtx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnOthers)
+	_, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(internalCtx, nil,
+		"DELETE FROM %n.%n WHERE routine_schema = %?", mysql.SystemDB, routinesTable, schema.L)
+	return err
+}
+
+func (e *ShowExec) fetchShowCreateProcedure(ctx context.Context) error {
+	schema, name := e.Procedure.Schema, e.Procedure.Name
+	var routine *routineInfo
+	var err error
+	if routineIsVisible(e.Ctx(), schema.L) {
+		routine, err = getProcedure(ctx, e.Ctx(), schema.L, name.O)
+		if err != nil {
+			return err
+		}
+	}
+	if routine == nil {
+		return exeerrors.ErrSpDoesNotExist.GenWithStackByArgs(routineTypeProcedure, schema.O+"."+name.O)
+	}
+	sqlMode, err := mysql.GetSQLMode(routine.sqlMode)
+	if err != nil {
+		return err
+	}
+	e.appendRow([]any{routine.name, routine.sqlMode, routine.createStmtText(sqlMode),
+		routine.charsetClient, routine.collationConnection, routine.dbCollation})
+	return nil
+}
+
+func (e *ShowExec) fetchShowProcedureStatus(ctx context.Context) error {
+	routines, err := getProcedures(ctx, e.Ctx(), "", "")
+	if err != nil {
+		return err
+	}
+	for _, routine := range routines {
+		if !routineIsVisible(e.Ctx(), routine.schema) {
+			continue
+		}
+		e.appendRow([]any{routine.schema, routine.name, routineTypeProcedure, routine.definer,
+			routine.lastAltered, routine.created, "DEFINER", routine.comment,
+			routine.charsetClient, routine.collationConnection, routine.dbCollation})
+	}
+	return nil
+}
+
+// checkProcedure checks the semantics of the procedure which aren't checked by the parser.
+func checkProcedure(proc *ast.ProcedureInfo) error {
+	params := make(map[string]struct{}, len(proc.ProcedureParam))
+	for _, param := range proc.ProcedureParam {
+		name := strings.ToLower(param.ParamName)
+		if _, ok := params[name]; ok {
+			return exeerrors.ErrSpDupParam.GenWithStackByArgs(param.ParamName)
+		}
+		params[name] = struct{}{}
+	}
+	c := &procedureChecker{}
+	return c.checkStmt(proc.ProcedureBody)
+}
+
+// procedureLabel is the label of a block or a loop.
+type procedureLabel struct {
+	name   string
+	isLoop bool
+}
+
+// procedureChecker checks the labels, the declarations and the cursors of the procedure body.
+type procedureChecker struct {
+	labels  []procedureLabel
+	cursors []map[string]struct{}
+	// trigger checks the statements and the expressions if the body is a trigger body.
+	trigger *triggerChecker
+}
+
+func (c *procedureChecker) checkStmts(stmts []ast.StmtNode) error {
+	for _, stmt := range stmts {
+		if err := c.checkStmt(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *procedureChecker) checkStmt(stmt ast.StmtNode) error {
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlock:
+		return c.checkBlock(x)
+	case *ast.ProcedureLabelBlock:
+		if err := c.pushLabel(x.LabelName, false, x.LabelError, x.LabelEnd); err != nil {
+			return err
+		}
+		defer c.popLabel()
+		return c.checkBlock(x.Block)
+	case *ast.ProcedureLabelLoop:
+		if err := c.pushLabel(x.LabelName, true, x.LabelError, x.LabelEnd); err != nil {
+			return err
+		}
+		defer c.popLabel()
+		return c.checkStmt(x.Block)
+	case *ast.ProcedureIfInfo:
+		return c.checkIfBlock(x.IfBody)
+	case *ast.SimpleCaseStmt:
+		if err := c.checkExpr(x.Condition); err != nil {
+			return err
+		}
+		for _, when := range x.WhenCases {
+			if err := c.checkExpr(when.Expr); err != nil {
+				return err
+			}
+			if err := c.checkStmts(when.ProcedureStmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.ElseCases)
+	case *ast.SearchCaseStmt:
+		for _, when := range x.WhenCases {
+			if err := c.checkExpr(when.Expr); err != nil {
+				return err
+			}
+			if err := c.checkStmts(when.ProcedureStmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.ElseCases)
+	case *ast.ProcedureWhileStmt:
+		if err := c.checkExpr(x.Condition); err != nil {
+			return err
+		}
+		return c.checkStmts(x.Body)
+	case *ast.ProcedureRepeatStmt:
+		if err := c.checkExpr(x.Condition); err != nil {
+			return err
+		}
+		return c.checkStmts(x.Body)
+	case *ast.ProcedureLoopStmt:
+		return c.checkStmts(x.Body)
+	case *ast.ProcedureJump:
+		return c.checkJump(x)
+	case *ast.ProcedureOpenCur:
+		return c.checkCursor(x.CurName)
+	case *ast.ProcedureCloseCur:
+		return c.checkCursor(x.CurName)
+	case *ast.ProcedureFetchInto:
+		return c.checkCursor(x.CurName)
+	}
+	if c.trigger != nil {
+		return c.trigger.checkStmt(stmt)
+	}
+	return nil
+}
+
+// checkExpr checks the expression of the control flow statements.
+func (c *procedureChecker) checkExpr(expr ast.Node) error {
+	if c.trigger != nil {
+		return c.trigger.checkRefs(expr)
+	}
+	return nil
+}
+
+func (c *procedureChecker) checkIfBlock(block *ast.ProcedureIfBlock) error {
+	if err := c.checkExpr(block.IfExpr); err != nil {
+		return err
+	}
+	if err := c.checkStmts(block.ProcedureIfStmts); err != nil {
+		return err
+	}
+	switch x := block.ProcedureElseStmt.(type) {
+	case *ast.ProcedureElseIfBlock:
+		return c.checkIfBlock(x.ProcedureIfStmt)
+	case *ast.ProcedureElseBlock:
+		return c.checkStmts(x.ProcedureIfStmts)
+	}
+	return nil
+}
+
+func (c *procedureChecker) checkBlock(block *ast.ProcedureBlock) error {
+	vars := make(map[string]struct{})
+	cursors := make(map[string]struct{})
+	handlers := make(map[string]struct{})
+	c.cursors = append(c.cursors, cursors)
+	defer func() { c.cursors = c.cursors[:len(c.cursors)-1] }()
+	for _, decl := range block.ProcedureVars {
+		switch x := decl.(type) {
+		case *ast.ProcedureDecl:
+			if err := c.checkExpr(x.DeclDefault); err != nil {
+				return err
+			}
+			for _, name := range x.DeclNames {
+				if _, ok := vars[strings.ToLower(name)]; ok {
+					return exeerrors.ErrSpDupVar.GenWithStackByArgs(name)
+				}
+				vars[strings.ToLower(name)] = struct{}{}
+			}
+		case *ast.ProcedureCursor:
+			if _, ok := cursors[strings.ToLower(x.CurName)]; ok {
+				return exeerrors.ErrSpDupCurs.GenWithStackByArgs(x.CurName)
+			}
+			if err := c.checkExpr(x.Selectstring); err != nil {
+				return err
+			}
+			cursors[strings.ToLower(x.CurName)] = struct{}{}
+		case *ast.ProcedureErrorControl:
+			for _, cond := range x.ErrorCon {
+				key := handlerConditionKey(cond)
+				if _, ok := handlers[key]; ok {
+					return exeerrors.ErrSpDupHandler.GenWithStackByArgs()
+				}
+				handlers[key] = struct{}{}
+			}
+			// The handler body can't jump to the labels outside the handler.
+			labels := c.labels
+			c.labels = nil
+			err := c.checkStmt(x.Operate)
+			c.labels = labels
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return c.checkStmts(block.ProcedureProcStmts)
+}
+
+func (c *procedureChecker) pushLabel(name string, isLoop, labelError bool, labelEnd string) error {
+	if labelError && !strings.EqualFold(name, labelEnd) {
+		return exeerrors.ErrSpLabelMismatch.GenWithStackByArgs(labelEnd)
+	}
+	for _, label := range c.labels {
+		if strings.EqualFold(label.name, name) {
+			return exeerrors.ErrSpLabelRedefine.GenWithStackByArgs(name)
+		}
+	}
+	c.labels = append(c.labels, procedureLabel{name: name, isLoop: isLoop})
+	return nil
+}
+
+func (c *procedureChecker) popLabel() {
+	c.labels = c.labels[:len(c.labels)-1]
+}
+
+func (c *procedureChecker) checkJump(jump *ast.ProcedureJump) error {
+	for i := len(c.labels) - 1; i >= 0; i-- {
+		if strings.EqualFold(c.labels[i].name, jump.Name) {
+			// ITERATE can only appear within a loop.
+			if jump.IsLeave || c.labels[i].isLoop {
+				return nil
+			}
+			break
+		}
+	}
+	if jump.IsLeave {
+		return exeerrors.ErrSpLilabelMismatch.GenWithStackByArgs("LEAVE", jump.Name)
+	}
+	return exeerrors.ErrSpLilabelMismatch.GenWithStackByArgs("ITERATE", jump.Name)
+}
+
+func (c *procedureChecker) checkCursor(name string) error {
+	for _, cursors := range c.cursors {
+		if _, ok := cursors[strings.ToLower(name)]; ok {
+			return nil
+		}
+	}
+	return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(name)
+}
+
+// handlerConditionKey returns the key of the handler condition to find the duplicated handlers.
+func handlerConditionKey(cond ast.ErrNode) string {
+	switch x := cond.(type) {
+	case *ast.ProcedureErrorVal:
+		return fmt.Sprintf("code:%d", x.ErrorNum)
+	case *ast.ProcedureErrorState:
+		return "state:" + x.CodeStatus
+	case *ast.ProcedureErrorCon:
+		return fmt.Sprintf("condition:%d", x.ErrorCon)
+	}
+	return ""
+}
@@ -0,0 +1,1200 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/extension"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/privilege/privileges"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// CallExec executes the `CALL` statement.
+// The procedure is interpreted statement by statement: the SQL statements in the body are executed by the session
+// after the local variables they reference are replaced by their values, and the expressions of the control flow
+// statements are evaluated by `SELECT <expr>`. The rows of the SELECT statement in the body are returned as the
+// result set of the CALL statement, a procedure can only return one result set since the CALL statement returns
+// only one.
+// The procedure is executed when the executor is opened, so the schema of the executor is known after it's opened.
+type CallExec struct {
+	exec.BaseExecutor
+
+	stmt *ast.CallStmt
+
+	schema      *expression.Schema
+	outputNames types.NameSlice
+	retTypes    []*types.FieldType
+	result      *procedureRows
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *CallExec) Open(ctx context.Context) error {
+	sctx := e.Ctx()
+	sessVars := sctx.GetSessionVars()
+	// The statements in the body are executed by the session in the CALL statement, the context of the CALL
+	// statement is restored after them. Like MySQL, the warnings of the last statement are kept.
+	sc, startTime := sessVars.StmtCtx, sessVars.StartTime
+	query := sctx.Value(sessionctx.QueryString)
+	defer func() {
+		if last := sessVars.StmtCtx; last != sc {
+			sc.SetWarnings(last.CopyWarnings(nil))
+		}
+		sessVars.StmtCtx, sessVars.StartTime = sc, startTime
+		sctx.SetValue(sessionctx.QueryString, query)
+	}()
+
+	e.schema = e.BaseExecutor.Schema()
+	e.retTypes = e.BaseExecutor.RetFieldTypes()
+	// The rows of the cursors and the result set are tracked by the CALL statement.
+	e.memTracker = memory.NewTracker(e.ID(), -1)
+	e.memTracker.AttachTo(sc.MemTracker)
+	e.diskTracker = disk.NewTracker(e.ID(), -1)
+	e.diskTracker.AttachTo(sc.DiskTracker)
+	pe := newProcedureExec(sctx, e.memTracker, e.diskTracker)
+	caller := &procedureFrame{exec: pe, parser: newProcedureParser(sessVars, sessVars.SQLMode)}
+	if err := pe.call(ctx, e.stmt, caller, nil); err != nil {
+		if unhandled, ok := err.(*procedureUnhandledError); ok {
+			err = unhandled.err
+		}
+		return err
+	}
+	if pe.result == nil {
+		return nil
+	}
+	e.result = pe.result.rows
+	fields := pe.result.fields
+	cols := make([]*expression.Column, 0, len(fields))
+	e.outputNames = make(types.NameSlice, 0, len(fields))
+	e.retTypes = make([]*types.FieldType, 0, len(fields))
+	for _, field := range fields {
+		tp := field.Column.FieldType.Clone()
+		cols = append(cols, &expression.Column{UniqueID: sessVars.AllocPlanColumnID(), RetType: tp})
+		name := &types.FieldName{
+			DBName:  field.DBName,
+			TblName: field.TableAsName,
+			ColName: field.ColumnAsName,
+		}
+		if !field.EmptyOrgName {
+			name.OrigColName = field.Column.Name
+		}
+		if field.Table != nil {
+			name.OrigTblName = field.Table.Name
+		}
+		e.outputNames = append(e.outputNames, name)
+		e.retTypes = append(e.retTypes, tp)
+	}
+	e.schema = expression.NewSchema(cols...)
+	return nil
+}
+
+// Schema implements the Executor Schema interface, it's the schema of the result set of the procedure.
+func (e *CallExec) Schema() *expression.Schema {
+	return e.schema
+}
+
+// RetFieldTypes implements the Executor RetFieldTypes interface.
+func (e *CallExec) RetFieldTypes() []*types.FieldType {
+	return e.retTypes
+}
+
+// Next implements the Executor Next interface.
+func (e *CallExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.result == nil {
+		return nil
+	}
+	for !req.IsFull() {
+		row, ok, err := e.result.next()
+		if err != nil || !ok {
+			return err
+		}
+		req.AppendRow(row)
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *CallExec) Close() error {
+	if e.result != nil {
+		terror.Log(e.result.close())
+		e.result = nil
+	}
+	return e.BaseExecutor.Close()
+}
+
+// procedureExec is the state of a CALL statement, which is shared by the nested calls.
+type procedureExec struct {
+	sctx sessionctx.Context
+	// active counts the running calls of each procedure to limit the recursion.
+	active map[string]int
+	result *procedureResult
+	// trigger is the running trigger if the statements are executed in a trigger body, they're executed
+	// in the statement which fires the trigger instead of by the session.
+	trigger *triggerInvocation
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+	// spillActions are the spill actions of the rows, they're bound to the memory tracker of the session again
+	// after each statement of the procedure because the statement unbinds the actions of the tracker.
+	spillActions []memory.ActionOnExceed
+}
+
+func newProcedureExec(sctx sessionctx.Context, memTracker *memory.Tracker, diskTracker *disk.Tracker) *procedureExec {
+	return &procedureExec{
+		sctx:        sctx,
+		active:      make(map[string]int),
+		memTracker:  memTracker,
+		diskTracker: diskTracker,
+	}
+}
+
+// procedureResult is the result set of a procedure.
+type procedureResult struct {
+	fields []*ast.ResultField
+	rows   *procedureRows
+}
+
+// procedureRows is the rows of a cursor or the result set of a procedure. They're tracked by the memory tracker of
+// the statement and spilled to disk if the memory quota is exceeded.
+type procedureRows struct {
+	fieldTypes []*types.FieldType
+	container  *chunk.RowContainer
+	// ptr is the next row to read.
+	ptr chunk.RowPtr
+}
+
+func (e *procedureExec) newRows(fieldTypes []*types.FieldType) *procedureRows {
+	container := chunk.NewRowContainer(fieldTypes, e.sctx.GetSessionVars().MaxChunkSize)
+	container.GetMemTracker().AttachTo(e.memTracker)
+	container.GetMemTracker().SetLabel(memory.LabelForRowContainer)
+	container.GetDiskTracker().AttachTo(e.diskTracker)
+	container.GetDiskTracker().SetLabel(memory.LabelForRowContainer)
+	if variable.EnableTmpStorageOnOOM.Load() {
+		action := container.ActionSpill()
+		e.spillActions = append(e.spillActions, action)
+		e.sctx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(action)
+	}
+	return &procedureRows{fieldTypes: fieldTypes, container: container}
+}
+
+// executeStmt executes the statement by the session and binds the spill actions of the open rows again.
+func (e *procedureExec) executeStmt(ctx context.Context, stmt ast.StmtNode) (sqlexec.RecordSet, error) {
+	rs, err := e.sctx.GetSQLExecutor().ExecuteStmt(ctx, stmt)
+	// The actions of the closed rows are finished.
+	actions := e.spillActions[:0]
+	for _, action := range e.spillActions {
+		if !action.IsFinished() {
+			actions = append(actions, action)
+			e.sctx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(action)
+		}
+	}
+	e.spillActions = actions
+	return rs, err
+}
+
+// next returns the next row, it returns false if all the rows are read.
+func (r *procedureRows) next() (chunk.Row, bool, error) {
+	for int(r.ptr.ChkIdx) < r.container.NumChunks() {
+		if int(r.ptr.RowIdx) < r.container.NumRowsOfChunk(int(r.ptr.ChkIdx)) {
+			row, err := r.container.GetRow(r.ptr)
+			r.ptr.RowIdx++
+			return row, err == nil, err
+		}
+		r.ptr.ChkIdx++
+		r.ptr.RowIdx = 0
+	}
+	return chunk.Row{}, false, nil
+}
+
+func (r *procedureRows) close() error {
+	return r.container.Close()
+}
+
+// procedureFrame is a running procedure.
+type procedureFrame struct {
+	exec   *procedureExec
+	name   string
+	parser *parser.Parser
+	// texts caches the restored text of the statements and expressions, they're parsed again for each execution
+	// because the planner modifies the AST.
+	texts map[ast.Node]string
+}
+
+// procedureScope is a BEGIN ... END block, the parameters of a procedure are in the outermost scope.
+type procedureScope struct {
+	parent   *procedureScope
+	vars     map[string]*procedureVar
+	cursors  map[string]*procedureCursor
+	handlers []*ast.ProcedureErrorControl
+	// runningHandlers counts the running handlers declared in the scope. The handlers of the scope aren't
+	// active when one of them is running.
+	runningHandlers int
+}
+
+type procedureVar struct {
+	tp    *types.FieldType
+	value types.Datum
+}
+
+type procedureCursor struct {
+	stmt ast.StmtNode
+	// rows is not nil if the cursor is open.
+	rows *procedureRows
+}
+
+func (c *procedureCursor) close() error {
+	if c.rows == nil {
+		return exeerrors.ErrSpCursorNotOpen.GenWithStackByArgs()
+	}
+	err := c.rows.close()
+	c.rows = nil
+	return err
+}
+
+// procedureJumpSignal is returned by LEAVE and ITERATE, it unwinds the statements to the labeled block or loop.
+type procedureJumpSignal struct {
+	label   string
+	isLeave bool
+}
+
+func (*procedureJumpSignal) Error() string {
+	return "procedure jump signal"
+}
+
+// procedureExitSignal is returned after an EXIT handler runs, it unwinds the statements to the block declaring the handler.
+type procedureExitSignal struct {
+	scope *procedureScope
+}
+
+func (*procedureExitSignal) Error() string {
+	return "procedure exit signal"
+}
+
+// procedureUnhandledError is an error which isn't handled by any handler of the procedure,
+// so the handlers aren't searched again when it's returned by the enclosing statements.
+type procedureUnhandledError struct {
+	err error
+}
+
+func (e *procedureUnhandledError) Error() string {
+	return e.err.Error()
+}
+
+func newProcedureScope(parent *procedureScope) *procedureScope {
+	return &procedureScope{
+		parent:  parent,
+		vars:    make(map[string]*procedureVar),
+		cursors: make(map[string]*procedureCursor),
+	}
+}
+
+func (s *procedureScope) lookupVar(name string) *procedureVar {
+	name = strings.ToLower(name)
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *procedureScope) lookupCursor(name string) *procedureCursor {
+	name = strings.ToLower(name)
+	for ; s != nil; s = s.parent {
+		if c, ok := s.cursors[name]; ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// findHandler finds the handler of the error in the innermost scope which has one, the handler of
+// the most specific condition is chosen if there are more than one in the scope.
+func (s *procedureScope) findHandler(err error) (*ast.ProcedureErrorControl, *procedureScope) {
+	code, state := uint16(mysql.ErrUnknown), mysql.DefaultMySQLState
+	if te, ok := errors.Cause(err).(*terror.Error); ok {
+		sqlErr := terror.ToSQLError(te)
+		code, state = sqlErr.Code, sqlErr.State
+	}
+	for ; s != nil; s = s.parent {
+		if s.runningHandlers > 0 {
+			continue
+		}
+		var handler *ast.ProcedureErrorControl
+		priority := 0
+		for _, h := range s.handlers {
+			for _, cond := range h.ErrorCon {
+				if p := matchHandlerCondition(cond, code, state); p > priority {
+					handler, priority = h, p
+				}
+			}
+		}
+		if handler != nil {
+			return handler, s
+		}
+	}
+	return nil, nil
+}
+
+// matchHandlerCondition returns the priority of the condition if it matches the error, or 0 if it doesn't match.
+func matchHandlerCondition(cond ast.ErrNode, code uint16, state string) int {
+	switch x := cond.(type) {
+	case *ast.ProcedureErrorVal:
+		if x.ErrorNum == uint64(code) {
+			return 3
+		}
+	case *ast.ProcedureErrorState:
+		if x.CodeStatus == state {
+			return 2
+		}
+	case *ast.ProcedureErrorCon:
+		class := state[:2]
+		switch x.ErrorCon {
+		case ast.PROCEDUR_SQLWARNING:
+			if class == "01" {
+				return 1
+			}
+		case ast.PROCEDUR_NOT_FOUND:
+			if class == "02" {
+				return 1
+			}
+		case ast.PROCEDUR_SQLEXCEPTION:
+			if class != "00" && class != "01" && class != "02" {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+func newProcedureParser(sessVars *variable.SessionVars, sqlMode mysql.SQLMode) *parser.Parser {
+	p := parser.New()
+	p.SetSQLMode(sqlMode)
+	p.SetParserConfig(sessVars.BuildParserConfig())
+	return p
+}
+
+// procedureVarType completes the field type of a variable or a parameter.
+func procedureVarType(sessVars *variable.SessionVars, tp *types.FieldType) *types.FieldType {
+	ft := tp.Clone()
+	flen, decimal := mysql.GetDefaultFieldLengthAndDecimal(ft.GetType())
+	if ft.GetFlen() == types.UnspecifiedLength {
+		ft.SetFlen(flen)
+	}
+	if ft.GetDecimal() == types.UnspecifiedLength {
+		ft.SetDecimal(decimal)
+	}
+	if !types.IsString(ft.GetType()) && ft.GetType() != mysql.TypeEnum && ft.GetType() != mysql.TypeSet {
+		ft.SetCharset(charset.CharsetBin)
+		ft.SetCollate(charset.CollationBin)
+		return ft
+	}
+	if ft.GetCharset() == "" {
+		cs, collation := sessVars.GetCharsetInfo()
+		ft.SetCharset(cs)
+		ft.SetCollate(collation)
+	} else if ft.GetCollate() == "" {
+		collation, _ := charset.GetDefaultCollation(ft.GetCharset())
+		ft.SetCollate(collation)
+	}
+	return ft
+}
+
+func (v *procedureVar) set(sctx sessionctx.Context, d types.Datum) error {
+	if d.IsNull() {
+		v.value.SetNull()
+		return nil
+	}
+	converted, err := d.ConvertTo(sctx.GetSessionVars().StmtCtx.TypeCtx(), v.tp)
+	if err != nil {
+		return err
+	}
+	v.value = converted
+	return nil
+}
+
+// call executes a CALL statement, the arguments are evaluated by the caller.
+func (e *procedureExec) call(ctx context.Context, stmt *ast.CallStmt, caller *procedureFrame, callerScope *procedureScope) error {
+	sessVars := e.sctx.GetSessionVars()
+	schema := stmt.Procedure.Schema.O
+	if schema == "" {
+		schema = sessVars.CurrentDB
+	}
+	if schema == "" {
+		return plannererrors.ErrNoDB
+	}
+	fullName := schema + "." + stmt.Procedure.FnName.O
+	if err := e.checkExecutePriv(schema, fullName); err != nil {
+		return err
+	}
+	routine, err := getProcedure(ctx, e.sctx, schema, stmt.Procedure.FnName.O)
+	if err != nil {
+		return err
+	}
+	if routine == nil {
+		return exeerrors.ErrSpDoesNotExist.GenWithStackByArgs(routineTypeProcedure, fullName)
+	}
+
+	key := strings.ToLower(fullName)
+	if e.active[key] > 0 {
+		depth, err := sessVars.GetSessionOrGlobalSystemVar(ctx, variable.MaxSpRecursionDepth)
+		if err != nil {
+			return err
+		}
+		maxDepth, err := strconv.Atoi(depth)
+		if err != nil {
+			return err
+		}
+		if e.active[key] > maxDepth {
+			return exeerrors.ErrSpRecursionLimit.GenWithStackByArgs(maxDepth, routine.name)
+		}
+	}
+	e.active[key]++
+	defer func() { e.active[key]-- }()
+
+	sqlMode, err := mysql.GetSQLMode(routine.sqlMode)
+	if err != nil {
+		return err
+	}
+	frame := &procedureFrame{
+		exec:   e,
+		name:   fullName,
+		parser: newProcedureParser(sessVars, sqlMode),
+		texts:  make(map[ast.Node]string),
+	}
+	node, err := frame.parser.ParseOneStmt(routine.createStmtText(sqlMode), "", "")
+	if err != nil {
+		return err
+	}
+	proc := node.(*ast.ProcedureInfo)
+	args := stmt.Procedure.Args
+	if len(args) != len(proc.ProcedureParam) {
+		return exeerrors.ErrSpWrongNoOfArgs.GenWithStackByArgs(routineTypeProcedure, fullName, len(proc.ProcedureParam), len(args))
+	}
+
+	scope := newProcedureScope(nil)
+	for i, param := range proc.ProcedureParam {
+		v := &procedureVar{tp: procedureVarType(sessVars, param.ParamType)}
+		if param.Paramstatus != ast.MODE_IN && !caller.isVarArg(callerScope, args[i]) {
+			return exeerrors.ErrSpNotVarArg.GenWithStackByArgs(i+1, fullName)
+		}
+		if param.Paramstatus != ast.MODE_OUT {
+			d, err := caller.evalExpr(ctx, callerScope, args[i])
+			if err != nil {
+				return err
+			}
+			if err = v.set(e.sctx, d); err != nil {
+				return err
+			}
+		}
+		scope.vars[strings.ToLower(param.ParamName)] = v
+	}
+
+	// The procedure is executed in its own database with the SQL mode it's created with,
+	// and with the privileges of its definer.
+	switchBack, err := e.switchToDefiner(routine)
+	if err != nil {
+		return err
+	}
+	oldDB, oldSQLMode := sessVars.CurrentDB, sessVars.SQLMode
+	sessVars.CurrentDB, sessVars.SQLMode = routine.schema, sqlMode
+	err = frame.execStmt(ctx, scope, proc.ProcedureBody)
+	sessVars.CurrentDB, sessVars.SQLMode = oldDB, oldSQLMode
+	switchBack()
+	if err != nil {
+		return err
+	}
+
+	for i, param := range proc.ProcedureParam {
+		if param.Paramstatus == ast.MODE_IN {
+			continue
+		}
+		v := scope.vars[strings.ToLower(param.ParamName)]
+		if err = caller.assignVarArg(callerScope, args[i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExecutePriv checks the EXECUTE privilege of the procedure. In a trigger body, the privilege of the definer of
+// the trigger is checked.
+func (e *procedureExec) checkExecutePriv(schema, fullName string) error {
+	checker := privilege.GetPrivilegeManager(e.sctx)
+	user := e.sctx.GetSessionVars().User
+	if checker == nil || user == nil {
+		return nil
+	}
+	if e.trigger != nil && e.trigger.definer != nil {
+		definer := e.trigger.definer
+		if !checker.RequestVerificationWithUser(strings.ToLower(schema), "", "", mysql.ExecutePriv, definer) {
+			return exeerrors.ErrProcaccessDenied.GenWithStackByArgs("execute", definer.Username, definer.Hostname, fullName)
+		}
+		return nil
+	}
+	if !checker.RequestVerification(e.sctx.GetSessionVars().ActiveRoles, strings.ToLower(schema), "", "", mysql.ExecutePriv) {
+		return exeerrors.ErrProcaccessDenied.GenWithStackByArgs("execute", user.AuthUsername, user.AuthHostname, fullName)
+	}
+	return nil
+}
+
+// switchToDefiner switches to the security context of the definer of the procedure, the statements in the body are
+// executed with the privileges and the default roles of the definer like `SQL SECURITY DEFINER` of MySQL, and
+// CURRENT_USER() returns the definer. The procedure created without a user is executed with the privileges of
+// the caller. It returns the function which switches back to the caller.
+func (e *procedureExec) switchToDefiner(routine *routineInfo) (func(), error) {
+	definer := routine.definerIdentity()
+	if definer == nil {
+		return func() {}, nil
+	}
+	if t := e.trigger; t != nil {
+		// The statements in a trigger body are checked against the definer by the trigger.
+		oldDefiner := t.definer
+		t.definer = definer
+		return func() { t.definer = oldDefiner }, nil
+	}
+	sessVars := e.sctx.GetSessionVars()
+	oldPM := privilege.GetPrivilegeManager(e.sctx)
+	if oldPM == nil || sessVars.User == nil {
+		return func() {}, nil
+	}
+	extensions, err := extension.GetExtensions()
+	if err != nil {
+		return nil, err
+	}
+	pm := privileges.NewUserPrivileges(domain.GetDomain(e.sctx).PrivilegeHandle(), extensions)
+	if !pm.GetAuthWithoutVerification(definer.Username, definer.Hostname) {
+		return nil, exeerrors.ErrNoSuchUser.GenWithStackByArgs(definer.Username, definer.Hostname)
+	}
+	oldUser, oldRoles := sessVars.User, sessVars.ActiveRoles
+	sessVars.User = &auth.UserIdentity{
+		Username:     oldUser.Username,
+		Hostname:     oldUser.Hostname,
+		AuthUsername: definer.Username,
+		AuthHostname: definer.Hostname,
+	}
+	sessVars.ActiveRoles = pm.GetDefaultRoles(definer.Username, definer.Hostname)
+	privilege.BindPrivilegeManager(e.sctx, pm)
+	return func() {
+		privilege.BindPrivilegeManager(e.sctx, oldPM)
+		sessVars.User, sessVars.ActiveRoles = oldUser, oldRoles
+	}, nil
+}
+
+// isVarArg checks whether the argument of an OUT or INOUT parameter is a variable.
+func (f *procedureFrame) isVarArg(scope *procedureScope, arg ast.ExprNode) bool {
+	switch x := arg.(type) {
+	case *ast.VariableExpr:
+		return !x.IsSystem
+	case *ast.ColumnNameExpr:
+		return x.Name.Table.L == "" && scope.lookupVar(x.Name.Name.L) != nil
+	}
+	return false
+}
+
+// assignVarArg assigns the value of an OUT or INOUT parameter to the variable passed as the argument.
+func (f *procedureFrame) assignVarArg(scope *procedureScope, arg ast.ExprNode, param *procedureVar) error {
+	switch x := arg.(type) {
+	case *ast.VariableExpr:
+		sessVars := f.exec.sctx.GetSessionVars()
+		name := strings.ToLower(x.Name)
+		if param.value.IsNull() {
+			sessVars.UnsetUserVar(name)
+		} else {
+			sessVars.SetUserVarVal(name, param.value)
+			sessVars.SetUserVarType(name, param.tp)
+		}
+		return nil
+	case *ast.ColumnNameExpr:
+		return scope.lookupVar(x.Name.Name.L).set(f.exec.sctx, param.value)
+	}
+	return nil
+}
+
+func (f *procedureFrame) execStmts(ctx context.Context, scope *procedureScope, stmts []ast.StmtNode) error {
+	for _, stmt := range stmts {
+		if err := f.execStmt(ctx, scope, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execStmt executes a statement of the procedure, the handlers are searched if the statement raises an error.
+func (f *procedureFrame) execStmt(ctx context.Context, scope *procedureScope, stmt ast.StmtNode) error {
+	if err := f.exec.sctx.GetSessionVars().SQLKiller.HandleSignal(); err != nil {
+		return &procedureUnhandledError{err: err}
+	}
+	err := f.execStmtInner(ctx, scope, stmt)
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *procedureJumpSignal, *procedureExitSignal, *procedureUnhandledError:
+		return err
+	}
+	handler, handlerScope := scope.findHandler(err)
+	if handler == nil {
+		return &procedureUnhandledError{err: err}
+	}
+	handlerScope.runningHandlers++
+	err = f.execStmt(ctx, handlerScope, handler.Operate)
+	handlerScope.runningHandlers--
+	if err != nil {
+		return err
+	}
+	if handler.ControlHandle == ast.PROCEDUR_EXIT {
+		return &procedureExitSignal{scope: handlerScope}
+	}
+	return nil
+}
+
+func (f *procedureFrame) execStmtInner(ctx context.Context, scope *procedureScope, stmt ast.StmtNode) error {
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlock:
+		return f.execBlock(ctx, scope, x)
+	case *ast.ProcedureLabelBlock:
+		err := f.execBlock(ctx, scope, x.Block)
+		if jump, ok := err.(*procedureJumpSignal); ok && jump.isLeave && strings.EqualFold(jump.label, x.LabelName) {
+			return nil
+		}
+		return err
+	case *ast.ProcedureLabelLoop:
+		return f.execLoop(ctx, scope, x.Block, x.LabelName)
+	case *ast.ProcedureWhileStmt, *ast.ProcedureRepeatStmt, *ast.ProcedureLoopStmt:
+		return f.execLoop(ctx, scope, x, "")
+	case *ast.ProcedureIfInfo:
+		return f.execIf(ctx, scope, x.IfBody)
+	case *ast.SimpleCaseStmt:
+		return f.execSimpleCase(ctx, scope, x)
+	case *ast.SearchCaseStmt:
+		return f.execSearchCase(ctx, scope, x)
+	case *ast.ProcedureJump:
+		return &procedureJumpSignal{label: x.Name, isLeave: x.IsLeave}
+	case *ast.ProcedureOpenCur:
+		return f.openCursor(ctx, scope, x.CurName)
+	case *ast.ProcedureFetchInto:
+		return f.fetchCursor(scope, x)
+	case *ast.ProcedureCloseCur:
+		return scope.lookupCursor(x.CurName).close()
+	case *ast.SetStmt:
+		return f.execSet(ctx, scope, x)
+	case *ast.CallStmt:
+		err := f.exec.call(ctx, x, f, scope)
+		// The error which isn't handled by the called procedure can be handled by the caller.
+		if unhandled, ok := err.(*procedureUnhandledError); ok {
+			return unhandled.err
+		}
+		return err
+	}
+	return f.execSQL(ctx, scope, stmt)
+}
+
+func (f *procedureFrame) execBlock(ctx context.Context, parent *procedureScope, block *ast.ProcedureBlock) error {
+	sctx := f.exec.sctx
+	scope := newProcedureScope(parent)
+	for _, decl := range block.ProcedureVars {
+		switch x := decl.(type) {
+		case *ast.ProcedureDecl:
+			var d types.Datum
+			if x.DeclDefault != nil {
+				var err error
+				if d, err = f.evalExpr(ctx, scope, x.DeclDefault); err != nil {
+					return err
+				}
+			}
+			for _, name := range x.DeclNames {
+				v := &procedureVar{tp: procedureVarType(sctx.GetSessionVars(), x.DeclType)}
+				if err := v.set(sctx, d); err != nil {
+					return err
+				}
+				scope.vars[strings.ToLower(name)] = v
+			}
+		case *ast.ProcedureCursor:
+			scope.cursors[strings.ToLower(x.CurName)] = &procedureCursor{stmt: x.Selectstring}
+		case *ast.ProcedureErrorControl:
+			scope.handlers = append(scope.handlers, x)
+		}
+	}
+	// The cursors are closed when the block ends.
+	defer func() {
+		for _, cursor := range scope.cursors {
+			if cursor.rows != nil {
+				terror.Log(cursor.close())
+			}
+		}
+	}()
+	err := f.execStmts(ctx, scope, block.ProcedureProcStmts)
+	if exit, ok := err.(*procedureExitSignal); ok && exit.scope == scope {
+		return nil
+	}
+	return err
+}
+
+func (f *procedureFrame) execLoop(ctx context.Context, scope *procedureScope, loop ast.StmtNode, label string) error {
+	for {
+		var body []ast.StmtNode
+		switch x := loop.(type) {
+		case *ast.ProcedureWhileStmt:
+			ok, err := f.evalCondition(ctx, scope, x.Condition)
+			if err != nil || !ok {
+				return err
+			}
+			body = x.Body
+		case *ast.ProcedureRepeatStmt:
+			body = x.Body
+		case *ast.ProcedureLoopStmt:
+			body = x.Body
+		}
+		err := f.execStmts(ctx, scope, body)
+		if jump, ok := err.(*procedureJumpSignal); ok && label != "" && strings.EqualFold(jump.label, label) {
+			if jump.isLeave {
+				return nil
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if x, ok := loop.(*ast.ProcedureRepeatStmt); ok {
+			done, err := f.evalCondition(ctx, scope, x.Condition)
+			if err != nil || done {
+				return err
+			}
+		}
+	}
+}
+
+func (f *procedureFrame) execIf(ctx context.Context, scope *procedureScope, block *ast.ProcedureIfBlock) error {
+	ok, err := f.evalCondition(ctx, scope, block.IfExpr)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return f.execStmts(ctx, scope, block.ProcedureIfStmts)
+	}
+	switch x := block.ProcedureElseStmt.(type) {
+	case *ast.ProcedureElseIfBlock:
+		return f.execIf(ctx, scope, x.ProcedureIfStmt)
+	case *ast.ProcedureElseBlock:
+		return f.execStmts(ctx, scope, x.ProcedureIfStmts)
+	}
+	return nil
+}
+
+func (f *procedureFrame) execSimpleCase(ctx context.Context, scope *procedureScope, stmt *ast.SimpleCaseStmt) error {
+	d, err := f.evalExpr(ctx, scope, stmt.Condition)
+	if err != nil {
+		return err
+	}
+	for _, when := range stmt.WhenCases {
+		// The case value is evaluated only once, it's compared with the when value by `SELECT <value> = <when>`.
+		sel, err := f.parseExprSelect(scope, when.Expr)
+		if err != nil {
+			return err
+		}
+		field := sel.Fields.Fields[0]
+		field.Expr = &ast.BinaryOperationExpr{Op: opcode.EQ, L: datumValueExpr(d, nil), R: field.Expr}
+		ok, err := f.evalSelectCondition(ctx, sel)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return f.execStmts(ctx, scope, when.ProcedureStmts)
+		}
+	}
+	if stmt.ElseCases == nil {
+		return exeerrors.ErrSpCaseNotFound.GenWithStackByArgs()
+	}
+	return f.execStmts(ctx, scope, stmt.ElseCases)
+}
+
+func (f *procedureFrame) execSearchCase(ctx context.Context, scope *procedureScope, stmt *ast.SearchCaseStmt) error {
+	for _, when := range stmt.WhenCases {
+		ok, err := f.evalCondition(ctx, scope, when.Expr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return f.execStmts(ctx, scope, when.ProcedureStmts)
+		}
+	}
+	if stmt.ElseCases == nil {
+		return exeerrors.ErrSpCaseNotFound.GenWithStackByArgs()
+	}
+	return f.execStmts(ctx, scope, stmt.ElseCases)
+}
+
+func (f *procedureFrame) openCursor(ctx context.Context, scope *procedureScope, name string) error {
+	cursor := scope.lookupCursor(name)
+	if cursor.rows != nil {
+		return exeerrors.ErrSpCursorAlreadyOpen.GenWithStackByArgs()
+	}
+	node, err := f.parseStmt(scope, cursor.stmt)
+	if err != nil {
+		return err
+	}
+	if f.exec.trigger != nil {
+		fieldTypes, rows, err := f.exec.trigger.run(ctx, node, true)
+		if err != nil {
+			return err
+		}
+		cursor.rows, err = f.exec.rowsFromSlice(fieldTypes, rows)
+		return err
+	}
+	rs, err := f.exec.executeStmt(ctx, node)
+	if err != nil || rs == nil {
+		return err
+	}
+	cursor.rows, err = f.exec.drainRecordSet(ctx, rs)
+	return err
+}
+
+func (f *procedureFrame) fetchCursor(scope *procedureScope, stmt *ast.ProcedureFetchInto) error {
+	cursor := scope.lookupCursor(stmt.CurName)
+	if cursor.rows == nil {
+		return exeerrors.ErrSpCursorNotOpen.GenWithStackByArgs()
+	}
+	fieldTypes := cursor.rows.fieldTypes
+	if len(stmt.Variables) != len(fieldTypes) {
+		return exeerrors.ErrSpWrongNoOfFetchArgs.GenWithStackByArgs()
+	}
+	row, ok, err := cursor.rows.next()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return exeerrors.ErrSpFetchNoData.GenWithStackByArgs()
+	}
+	for i, name := range stmt.Variables {
+		v := scope.lookupVar(name)
+		if v == nil {
+			return exeerrors.ErrSpUndeclaredVar.GenWithStackByArgs(name)
+		}
+		if err := v.set(f.exec.sctx, row.GetDatum(i, fieldTypes[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execSet executes a SET statement, the local variables are assigned by the procedure and
+// the other variables are assigned by the session.
+func (f *procedureFrame) execSet(ctx context.Context, scope *procedureScope, stmt *ast.SetStmt) error {
+	for _, assign := range stmt.Variables {
+		if row, col, ok := triggerRowVar(assign); ok && f.exec.trigger != nil {
+			d, err := f.evalExpr(ctx, scope, assign.Value)
+			if err != nil {
+				return err
+			}
+			if err = f.exec.trigger.setNewValue(row, col, d); err != nil {
+				return err
+			}
+			continue
+		}
+		if assign.IsSystem && !assign.IsGlobal {
+			if v := scope.lookupVar(assign.Name); v != nil {
+				d, err := f.evalExpr(ctx, scope, assign.Value)
+				if err != nil {
+					return err
+				}
+				if err = v.set(f.exec.sctx, d); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := f.execSQL(ctx, scope, &ast.SetStmt{Variables: []*ast.VariableAssignment{assign}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execSQL executes a SQL statement of the procedure by the session.
+func (f *procedureFrame) execSQL(ctx context.Context, scope *procedureScope, stmt ast.StmtNode) error {
+	node, err := f.parseStmt(scope, stmt)
+	if err != nil {
+		return err
+	}
+	if f.exec.trigger != nil {
+		_, _, err = f.exec.trigger.run(ctx, node, false)
+		return err
+	}
+	rs, err := f.exec.executeStmt(ctx, node)
+	if err != nil || rs == nil {
+		return err
+	}
+	fields := rs.Fields()
+	rows, err := f.exec.drainRecordSet(ctx, rs)
+	if err != nil {
+		return err
+	}
+	if f.exec.result != nil {
+		terror.Log(rows.close())
+		return dbterror.ErrNotSupportedYet.GenWithStackByArgs("returning more than one result set from a procedure")
+	}
+	f.exec.result = &procedureResult{fields: fields, rows: rows}
+	return nil
+}
+
+// query executes the statement and returns the types of its columns and all its rows.
+func (f *procedureFrame) query(ctx context.Context, stmt ast.StmtNode) ([]*types.FieldType, []chunk.Row, error) {
+	if f.exec.trigger != nil {
+		return f.exec.trigger.run(ctx, stmt, true)
+	}
+	rs, err := f.exec.executeStmt(ctx, stmt)
+	if err != nil || rs == nil {
+		return nil, nil, err
+	}
+	rows, err := drainProcedureRecordSet(ctx, f.exec.sctx, rs)
+	fields := make([]*types.FieldType, 0, len(rs.Fields()))
+	for _, field := range rs.Fields() {
+		fields = append(fields, &field.Column.FieldType)
+	}
+	return fields, rows, err
+}
+
+func drainProcedureRecordSet(ctx context.Context, sctx sessionctx.Context, rs sqlexec.RecordSet) ([]chunk.Row, error) {
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, sctx.GetSessionVars().MaxChunkSize)
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	return rows, err
+}
+
+// drainRecordSet reads all the rows of the record set into the tracked rows and closes it.
+func (e *procedureExec) drainRecordSet(ctx context.Context, rs sqlexec.RecordSet) (rows *procedureRows, err error) {
+	fieldTypes := make([]*types.FieldType, 0, len(rs.Fields()))
+	for _, field := range rs.Fields() {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	rows = e.newRows(fieldTypes)
+	for {
+		chk := rs.NewChunk(nil)
+		if err = rs.Next(ctx, chk); err != nil || chk.NumRows() == 0 {
+			break
+		}
+		if err = rows.container.Add(chk); err != nil {
+			break
+		}
+	}
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		terror.Log(rows.close())
+		return nil, err
+	}
+	return rows, nil
+}
+
+// rowsFromSlice puts the rows into the tracked rows.
+func (e *procedureExec) rowsFromSlice(fieldTypes []*types.FieldType, rows []chunk.Row) (*procedureRows, error) {
+	result := e.newRows(fieldTypes)
+	maxChunkSize := e.sctx.GetSessionVars().MaxChunkSize
+	var chk *chunk.Chunk
+	for _, row := range rows {
+		if chk == nil {
+			chk = chunk.NewChunkWithCapacity(fieldTypes, maxChunkSize)
+		}
+		chk.AppendRow(row)
+		if chk.NumRows() >= maxChunkSize {
+			if err := result.container.Add(chk); err != nil {
+				terror.Log(result.close())
+				return nil, err
+			}
+			chk = nil
+		}
+	}
+	if chk != nil {
+		if err := result.container.Add(chk); err != nil {
+			terror.Log(result.close())
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// evalExpr evaluates the expression by `SELECT <expr>`.
+func (f *procedureFrame) evalExpr(ctx context.Context, scope *procedureScope, expr ast.ExprNode) (types.Datum, error) {
+	sel, err := f.parseExprSelect(scope, expr)
+	if err != nil {
+		return types.Datum{}, err
+	}
+	fields, rows, err := f.query(ctx, sel)
+	if err != nil || len(rows) == 0 {
+		return types.Datum{}, err
+	}
+	return rows[0].GetDatum(0, fields[0]), nil
+}
+
+func (f *procedureFrame) evalCondition(ctx context.Context, scope *procedureScope, expr ast.ExprNode) (bool, error) {
+	sel, err := f.parseExprSelect(scope, expr)
+	if err != nil {
+		return false, err
+	}
+	return f.evalSelectCondition(ctx, sel)
+}
+
+func (f *procedureFrame) evalSelectCondition(ctx context.Context, sel *ast.SelectStmt) (bool, error) {
+	fields, rows, err := f.query(ctx, sel)
+	if err != nil || len(rows) == 0 {
+		return false, err
+	}
+	d := rows[0].GetDatum(0, fields[0])
+	if d.IsNull() {
+		return false, nil
+	}
+	v, err := d.ToBool(f.exec.sctx.GetSessionVars().StmtCtx.TypeCtx())
+	return v != 0, err
+}
+
+// parseExprSelect parses `SELECT <expr>` with the local variables replaced by their values.
+func (f *procedureFrame) parseExprSelect(scope *procedureScope, expr ast.ExprNode) (*ast.SelectStmt, error) {
+	text, err := f.restore(expr)
+	if err != nil {
+		return nil, err
+	}
+	node, err := f.parse(scope, "SELECT "+text)
+	if err != nil {
+		return nil, err
+	}
+	return node.(*ast.SelectStmt), nil
+}
+
+// parseStmt parses a new AST of the statement with the local variables replaced by their values.
+func (f *procedureFrame) parseStmt(scope *procedureScope, stmt ast.StmtNode) (ast.StmtNode, error) {
+	text, err := f.restore(stmt)
+	if err != nil {
+		return nil, err
+	}
+	return f.parse(scope, text)
+}
+
+func (f *procedureFrame) parse(scope *procedureScope, sql string) (ast.StmtNode, error) {
+	node, err := f.parser.ParseOneStmt(sql, "", "")
+	if err != nil {
+		return nil, err
+	}
+	replacer := &procedureVarReplacer{scope: scope, trigger: f.exec.trigger}
+	node.Accept(replacer)
+	if replacer.err != nil {
+		return nil, replacer.err
+	}
+	node.SetText(nil, sql)
+	return node, nil
+}
+
+func (f *procedureFrame) restore(node ast.Node) (string, error) {
+	if text, ok := f.texts[node]; ok {
+		return text, nil
+	}
+	var sb strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	if f.texts != nil {
+		f.texts[node] = sb.String()
+	}
+	return sb.String(), nil
+}
+
+// procedureVarReplacer replaces the references of the local variables by their values.
+// Like MySQL, a local variable takes precedence over a column with the same name.
+// In a trigger body, the references of the NEW and OLD rows are replaced by their values as well.
+type procedureVarReplacer struct {
+	scope   *procedureScope
+	trigger *triggerInvocation
+	err     error
+}
+
+// Enter implements the ast.Visitor interface.
+func (*procedureVarReplacer) Enter(in ast.Node) (ast.Node, bool) {
+	// VALUES(col) always references the column.
+	_, ok := in.(*ast.ValuesExpr)
+	return in, ok
+}
+
+// Leave implements the ast.Visitor interface.
+func (v *procedureVarReplacer) Leave(in ast.Node) (ast.Node, bool) {
+	col, ok := in.(*ast.ColumnNameExpr)
+	if !ok {
+		return in, true
+	}
+	if col.Name.Table.L != "" {
+		if v.trigger == nil || col.Name.Schema.L != "" {
+			return in, true
+		}
+		d, ok, err := v.trigger.rowValue(col.Name.Table.L, col.Name.Name)
+		if err != nil {
+			v.err = err
+			return in, false
+		}
+		if ok {
+			return datumValueExpr(d, col), true
+		}
+		return in, true
+	}
+	if pv := v.scope.lookupVar(col.Name.Name.L); pv != nil {
+		return datumValueExpr(pv.value, col), true
+	}
+	return in, true
+}
+
+// datumValueExpr returns the value expression of the datum, it keeps the text of the replaced column
+// so that the name of the output column isn't changed.
+func datumValueExpr(d types.Datum, col *ast.ColumnNameExpr) ast.ValueExpr {
+	cs, collation := charset.CharsetBin, charset.CollationBin
+	if d.Kind() == types.KindString || d.Kind() == types.KindBytes {
+		cs, collation = mysql.DefaultCharset, mysql.DefaultCollationName
+		if info, err := charset.GetCollationByName(d.Collation()); err == nil {
+			cs, collation = info.CharsetName, info.Name
+		}
+	}
+	expr := ast.NewValueExpr(d.GetValue(), cs, collation)
+	if col != nil {
+		expr.SetText(nil, col.Text())
+	}
+	return expr
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDropProcedure(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create procedure p1(in a int, out b varchar(10)) begin select a; end")
+	tk.MustGetErrCode("create procedure p1() begin end", errno.ErrSpAlreadyExists)
+	tk.MustExec("create procedure if not exists p1() begin end")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1304 PROCEDURE p1 already exists"))
+	tk.MustGetErrCode("create procedure p2(a int, a int) begin end", errno.ErrSpDupParam)
+	tk.MustGetErrCode("create procedure p2() begin declare a int; declare a int; end", errno.ErrSpDupVar)
+	tk.MustGetErrCode("create procedure p2() begin leave l; end", errno.ErrSpLilabelMismatch)
+	tk.MustGetErrCode("create procedure p2() begin open c; end", errno.ErrSpCursorMismatch)
+	tk.MustGetErrCode("create procedure test_not_exists.p2() begin end", errno.ErrBadDB)
+
+	tk.MustQuery("select routine_schema, routine_name, routine_type, routine_definition from information_schema.routines where routine_schema = 'test'").
+		Check(testkit.Rows("test p1 PROCEDURE begin select a; end"))
+	tk.MustQuery("show procedure status like 'p1'").CheckAt([]int{0, 1, 2}, testkit.Rows("test p1 PROCEDURE"))
+	rows := tk.MustQuery("show create procedure p1").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "p1", rows[0][0])
+	require.Equal(t, "CREATE PROCEDURE `p1`(in a int, out b varchar(10))\nbegin select a; end", rows[0][2])
+
+	tk.MustExec("drop procedure p1")
+	tk.MustGetErrCode("drop procedure p1", errno.ErrSpDoesNotExist)
+	tk.MustExec("drop procedure if exists p1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1305 PROCEDURE test.p1 does not exist"))
+	tk.MustGetErrCode("call p1(1, @b)", errno.ErrSpDoesNotExist)
+
+	// The procedures are dropped with the database.
+	tk.MustExec("create database proc_db")
+	tk.MustExec("create procedure proc_db.p() begin end")
+	tk.MustExec("drop database proc_db")
+	tk.MustQuery("select count(*) from information_schema.routines where routine_schema = 'proc_db'").Check(testkit.Rows("0"))
+}
+
+func TestCallProcedure(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v varchar(10))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c')")
+
+	// Parameters, local variables and the result set.
+	tk.MustExec(`create procedure p_params(in a int, out b int, inout c varchar(20))
+	begin
+		declare x int default 10;
+		set x = x + a, b = x * 2;
+		set c = concat(c, '-', x);
+		select id, v from t where id <= a order by id;
+	end`)
+	tk.MustExec("set @c = 'in'")
+	tk.MustQuery("call p_params(2, @b, @c)").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustQuery("select @b, @c").Check(testkit.Rows("24 in-12"))
+	rs, err := tk.Exec("call p_params(1, @b, @c)")
+	require.NoError(t, err)
+	fields := rs.Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "id", fields[0].ColumnAsName.O)
+	require.Equal(t, "t", fields[1].TableAsName.O)
+	require.Equal(t, "test", fields[1].DBName.O)
+	require.NoError(t, rs.Close())
+	tk.MustGetErrCode("call p_params(1, @b)", errno.ErrSpWrongNoOfArgs)
+	tk.MustGetErrCode("call p_params(1, 2, @c)", errno.ErrSpNotVarArg)
+	// A procedure can only return one result set.
+	tk.MustExec("create procedure p_two() begin select 1; select 2; end")
+	tk.MustGetErrCode("call p_two()", errno.ErrNotSupportedYet)
+
+	// The statements in the body are executed in the transaction of the CALL statement,
+	// and the warnings of the last statement are kept.
+	tk.MustExec("create procedure p_txn() begin insert into t values (10, 'x'); select cast('1x' as signed); end")
+	tk.MustExec("begin")
+	tk.MustQuery("call p_txn()").Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect INTEGER value: '1x'"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select count(*) from t where id = 10").Check(testkit.Rows("0"))
+
+	// IF, CASE and the loops.
+	tk.MustExec(`create procedure p_flow(in n int, out s varchar(100))
+	begin
+		declare i int default 0;
+		set s = '';
+		l: loop
+			set i = i + 1;
+			if i > n then
+				leave l;
+			elseif i % 2 = 0 then
+				iterate l;
+			end if;
+			case i when 1 then set s = concat(s, 'one,'); else set s = concat(s, i, ','); end case;
+		end loop l;
+		while i > 0 do
+			set i = i - 1;
+		end while;
+		repeat
+			set i = i + 2;
+		until i >= 4 end repeat;
+		case when i = 4 then set s = concat(s, 'four'); end case;
+	end`)
+	tk.MustExec("call p_flow(5, @s)")
+	tk.MustQuery("select @s").Check(testkit.Rows("one,3,5,four"))
+	tk.MustExec(`create procedure p_case(in n int) begin case n when 1 then select 1; end case; end`)
+	tk.MustGetErrCode("call p_case(2)", errno.ErrSpCaseNotFound)
+
+	// Cursors and handlers.
+	tk.MustExec(`create procedure p_cursor(out total varchar(100))
+	begin
+		declare done int default 0;
+		declare cur_id int;
+		declare cur_v varchar(10);
+		declare c cursor for select id, v from t order by id;
+		declare continue handler for not found set done = 1;
+		set total = '';
+		open c;
+		fetch_loop: loop
+			fetch c into cur_id, cur_v;
+			if done then
+				leave fetch_loop;
+			end if;
+			set total = concat(total, cur_id, cur_v);
+		end loop;
+		close c;
+	end`)
+	tk.MustExec("call p_cursor(@total)")
+	tk.MustQuery("select @total").Check(testkit.Rows("1a2b3c"))
+	// The cursor names are case-insensitive.
+	tk.MustExec(`create procedure p_cursor_case(out r varchar(10))
+	begin
+		declare Cur cursor for select v from t where id = 2;
+		open CUR;
+		fetch cur into r;
+		close cUr;
+	end`)
+	tk.MustExec("call p_cursor_case(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("b"))
+	tk.MustGetErrCode("create procedure p_dup_cursor() begin declare c cursor for select 1; declare C cursor for select 2; end", errno.ErrSpDupCurs)
+
+	tk.MustExec(`create procedure p_exit(out r varchar(20))
+	begin
+		set r = 'start';
+		begin
+			declare exit handler for 1062 set r = 'duplicate';
+			insert into t values (1, 'x');
+			set r = 'not reached';
+		end;
+		set r = concat(r, '-end');
+	end`)
+	tk.MustExec("call p_exit(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("duplicate-end"))
+
+	// The error without a handler is returned, the statements before it take effect.
+	tk.MustExec(`create procedure p_err() begin insert into t values (4, 'd'); insert into t values (1, 'x'); end`)
+	tk.MustGetErrCode("call p_err()", errno.ErrDupEntry)
+	tk.MustQuery("select v from t where id = 4").Check(testkit.Rows("d"))
+
+	// Nested calls and the recursion limit.
+	tk.MustExec(`create procedure p_rec(in n int, inout acc int)
+	begin
+		if n > 0 then
+			set acc = acc + n;
+			call p_rec(n - 1, acc);
+		end if;
+	end`)
+	tk.MustExec("set @acc = 0")
+	tk.MustGetErrCode("call p_rec(3, @acc)", errno.ErrSpRecursionLimit)
+	tk.MustExec("set max_sp_recursion_depth = 5")
+	tk.MustExec("call p_rec(3, @acc)")
+	tk.MustQuery("select @acc").Check(testkit.Rows("6"))
+}
+
+func TestCallProcedureDefinerPrivileges(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table secret (id int)")
+	tk.MustExec("insert into secret values (1)")
+	tk.MustExec("create user 'low'@'%', 'caller'@'%'")
+	tk.MustExec("grant create routine, execute on test.* to 'low'@'%'")
+	tk.MustExec("grant execute on test.* to 'caller'@'%'")
+
+	// The body is executed with the privileges of the definer, the caller needn't have them.
+	tk.MustExec("create procedure p_root() begin insert into secret values (2); select count(*), current_user(), user() from secret; end")
+	tkCaller := testkit.NewTestKit(t, store)
+	require.NoError(t, tkCaller.Session().Auth(&auth.UserIdentity{Username: "caller", Hostname: "%"}, nil, nil, nil))
+	tkCaller.MustQuery("call test.p_root()").Check(testkit.Rows("2 root@% caller@%"))
+	tkCaller.MustQuery("select current_user()").Check(testkit.Rows("caller@%"))
+	tkCaller.MustGetErrCode("select * from test.secret", errno.ErrTableaccessDenied)
+	tk.MustQuery("show procedure status like 'p_root'").CheckAt([]int{3, 6}, testkit.Rows("root@% DEFINER"))
+
+	// The body is checked against the definer, even if the caller has the privileges.
+	tkLow := testkit.NewTestKit(t, store)
+	require.NoError(t, tkLow.Session().Auth(&auth.UserIdentity{Username: "low", Hostname: "%"}, nil, nil, nil))
+	tkLow.MustExec("create procedure test.p_low() select * from secret")
+	tk.MustGetErrCode("call p_low()", errno.ErrTableaccessDenied)
+	tk.MustQuery("select current_user()").Check(testkit.Rows("root@%"))
+	tk.MustExec("drop user 'low'@'%'")
+	tk.MustGetErrCode("call p_low()", errno.ErrNoSuchUser)
+}
+
+func TestCallProcedureSpill(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v varchar(1000))")
+	tk.MustExec("insert into t with recursive c(n) as (select 1 union all select n + 1 from c where n < 1000) select n, repeat('a', 1000) from c")
+	// Split the table so the statements in the procedure read a few rows at a time.
+	tk.MustQuery("split table t by (50), (100), (150), (200), (250), (300), (350), (400), (450), (500), (550), (600), (650), (700), (750), (800), (850), (900), (950)")
+	tk.MustExec(`create procedure p_spill(out total int)
+	begin
+		declare done int default 0;
+		declare cur_v varchar(1000);
+		declare c cursor for select v from t;
+		declare continue handler for not found set done = 1;
+		set total = 0;
+		open c;
+		fetch_loop: loop
+			fetch c into cur_v;
+			if done then
+				leave fetch_loop;
+			end if;
+			set total = total + length(cur_v);
+		end loop;
+		close c;
+		select id, v from t;
+	end`)
+	tk.MustExec("set tidb_max_chunk_size = 32, tidb_executor_concurrency = 1, tidb_distsql_scan_concurrency = 1")
+
+	// The rows of the cursor and the result set are spilled to disk if they exceed the memory quota.
+	tk.MustExec("set global tidb_enable_tmp_storage_on_oom = 1")
+	defer tk.MustExec("set global tidb_enable_tmp_storage_on_oom = default")
+	tk.MustExec("set global tidb_mem_oom_action = 'CANCEL'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("set tidb_mem_quota_query = 200000")
+	require.Len(t, tk.MustQuery("call p_spill(@total)").Rows(), 1000)
+	tk.MustQuery("select @total").Check(testkit.Rows("1000000"))
+
+	tk.MustExec("set global tidb_enable_tmp_storage_on_oom = 0")
+	tk.MustContainErrMsg("call p_spill(@total)", "Your query has been cancelled due to exceeding the allowed memory limit")
+}
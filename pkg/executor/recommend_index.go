@@ -0,0 +1,83 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/planner/indexadvisor"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+// RecommendIndexExec represents a recommend index executor.
+type RecommendIndexExec struct {
+	exec.BaseExecutor
+
+	SQL  string
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *RecommendIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	// The workload is loaded and planned by the internal statements in the current session, so the privileges of the
+	// user are respected. They replace the statement context, which is restored for the RECOMMEND INDEX statement.
+	// It's referenced during the internal statements, so it's not reused and reset by them.
+	vars := e.Ctx().GetSessionVars()
+	stmtCtx := vars.StmtCtx
+	if vars.RefCountOfStmtCtx.TryIncrease() {
+		defer vars.RefCountOfStmtCtx.Decrease()
+	}
+	defer func() {
+		vars.StmtCtx = stmtCtx
+	}()
+	internalCtx := kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	var (
+		queries []*indexadvisor.Query
+		err     error
+	)
+	if e.SQL != "" {
+		queries, err = indexadvisor.ParseWorkload(e.Ctx(), e.SQL)
+	} else {
+		queries, err = indexadvisor.LoadWorkload(internalCtx, e.Ctx())
+	}
+	if err != nil {
+		return err
+	}
+	recs, err := indexadvisor.AdviseIndexes(internalCtx, e.Ctx(), queries, indexadvisor.DefaultMaxNumIndexes)
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		req.AppendString(0, rec.Database)
+		req.AppendString(1, rec.Table)
+		req.AppendString(2, rec.IndexName)
+		req.AppendString(3, strings.Join(rec.Columns, ","))
+		req.AppendString(4, fmt.Sprintf("%.2f%%", rec.Benefit*100))
+		req.AppendInt64(5, int64(len(rec.ImpactedQueries)))
+		req.AppendString(6, rec.ImpactedQueries[0].Text)
+		req.AppendString(7, rec.CreateIndexSQL())
+	}
+	return nil
+}
@@ -151,6 +151,13 @@ func (e *ReplaceExec) exec(ctx context.Context, newRows [][]types.Datum) error {
 	 */
 
 	defer trace.StartRegion(ctx, "ReplaceExec").End()
+	e.Ctx().GetSessionVars().StmtCtx.AddRecordRows(uint64(len(newRows)))
+	return e.writeRowsWithTriggers(ctx, newRows, func(newRows [][]types.Datum) error {
+		return e.replaceRows(ctx, newRows)
+	})
+}
+
+func (e *ReplaceExec) replaceRows(ctx context.Context, newRows [][]types.Datum) error {
 	// Get keys need to be checked.
 	toBeCheckedRows, err := getKeysNeedCheck(e.Ctx(), e.Table, newRows)
 	if err != nil {
@@ -180,7 +187,6 @@ func (e *ReplaceExec) exec(ctx context.Context, newRows [][]types.Datum) error {
 	if e.stats != nil {
 		e.stats.Prefetch = time.Since(prefetchStart)
 	}
-	e.Ctx().GetSessionVars().StmtCtx.AddRecordRows(uint64(len(newRows)))
 	for _, r := range toBeCheckedRows {
 		err = e.replaceRow(ctx, r)
 		if err != nil {
@@ -82,6 +82,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/set"
 	"github.com/pingcap/tidb/pkg/util/sqlexec"
 	"github.com/pingcap/tidb/pkg/util/stringutil"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 	"github.com/tikv/client-go/v2/oracle"
 )
 
@@ -96,6 +97,7 @@ type ShowExec struct {
 	Table             *ast.TableName       // Used for showing columns.
 	Partition         model.CIStr          // Used for showing partition
 	Column            *ast.ColumnName      // Used for `desc table column`.
+	Procedure         *ast.TableName       // Used for showing procedure.
 	IndexName         model.CIStr          // Used for show table regions.
 	ResourceGroupName model.CIStr          // Used for showing resource group
 	Flag              int                  // Some flag parsed from sql, such as FULL.
@@ -193,6 +195,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowCreateView()
 	case ast.ShowCreateDatabase:
 		return e.fetchShowCreateDatabase()
+	case ast.ShowCreateProcedure:
+		return e.fetchShowCreateProcedure(ctx)
 	case ast.ShowCreatePlacementPolicy:
 		return e.fetchShowCreatePlacementPolicy()
 	case ast.ShowCreateResourceGroup:
@@ -208,7 +212,7 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	case ast.ShowIndex:
 		return e.fetchShowIndex()
 	case ast.ShowProcedureStatus:
-		return e.fetchShowProcedureStatus()
+		return e.fetchShowProcedureStatus(ctx)
 	case ast.ShowPumpStatus:
 		return e.fetchShowPumpOrDrainerStatus(node.PumpNode)
 	case ast.ShowStatus:
@@ -1074,6 +1078,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 				buf.WriteString(table.OptionalFsp(&col.FieldType))
 			}
 		}
+		if col.SRID != nil {
+			fmt.Fprintf(buf, " /*!80003 SRID %d */", *col.SRID)
+		}
 		if ddl.IsAutoRandomColumnID(tableInfo, col.ID) {
 			s, r := tableInfo.AutoRandomBits, tableInfo.AutoRandomRangeBits
 			if r == 0 || r == autoid.AutoRandomRangeBitsDefault {
@@ -1132,6 +1139,10 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 			buf.WriteString("  PRIMARY KEY ")
 		} else if idxInfo.Unique {
 			fmt.Fprintf(buf, "  UNIQUE KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if idxInfo.IsFullText() {
+			fmt.Fprintf(buf, "  FULLTEXT KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if idxInfo.IsVector() {
+			fmt.Fprintf(buf, "  VECTOR INDEX %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		} else {
 			fmt.Fprintf(buf, "  KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		}
@@ -1141,6 +1152,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 		for _, c := range idxInfo.Columns {
 			if tableInfo.Columns[c.Offset].Hidden {
 				colInfo = fmt.Sprintf("(%s)", tableInfo.Columns[c.Offset].GeneratedExprString)
+			} else if idxInfo.IsVector() {
+				distanceFunc := strings.ToUpper(vectorindex.DistanceFuncOf(idxInfo.VectorInfo.DistanceMetric))
+				colInfo = fmt.Sprintf("(%s(%s))", distanceFunc, stringutil.Escape(c.Name.O, sqlMode))
 			} else {
 				colInfo = stringutil.Escape(c.Name.O, sqlMode)
 				if c.Length != types.UnspecifiedLength {
@@ -1159,6 +1173,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 		if idxInfo.Tp == model.IndexTypeHypo {
 			fmt.Fprintf(buf, ` /* HYPO INDEX */`)
 		}
+		if idxInfo.IsFullText() && idxInfo.FullTextInfo.ParserType == model.FullTextParserTypeNgram {
+			buf.WriteString(" /*!50100 WITH PARSER `ngram` */")
+		}
 		if idxInfo.Primary {
 			if tableInfo.HasClusteredIndex() {
 				buf.WriteString(" /*T![clustered_index] CLUSTERED */")
@@ -1848,14 +1865,6 @@ func (e *ShowExec) fetchShowPrivileges() error {
 	return nil
 }
 
-func (*ShowExec) fetchShowTriggers() error {
-	return nil
-}
-
-func (*ShowExec) fetchShowProcedureStatus() error {
-	return nil
-}
-
 func (e *ShowExec) fetchShowPlugins() error {
 	tiPlugins := plugin.GetAll()
 	for _, ps := range tiPlugins {
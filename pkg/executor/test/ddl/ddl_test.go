@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta"
 	"github.com/pingcap/tidb/pkg/meta/autoid"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
@@ -171,6 +172,86 @@ func TestCreateTable(t *testing.T) {
 	tk.MustQuery("show warnings").Check(testkit.RowsWithSep("|", "Note|1051|Unknown table 'test.t2_if_exists'", "Note|1051|Unknown table 'test.t3_if_exists'"))
 }
 
+func TestCreateTableSelect(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table src (id int primary key, a varchar(10) not null, b decimal(10, 2), c json)")
+	tk.MustExec(`insert into src values (1, 'x', 1.5, '{"k": 1}'), (2, 'y', null, null), (3, 'x', 3.25, '[]')`)
+
+	// Column types are derived from the output schema of the select.
+	tk.MustExec("create table t1 select * from src")
+	tk.MustQuery("select column_name, column_type, is_nullable, column_key from information_schema.columns where table_schema = 'test' and table_name = 't1' order by ordinal_position").
+		Check(testkit.Rows("id int(11) NO ", "a varchar(10) NO ", "b decimal(10,2) YES ", "c json YES "))
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows(`1 x 1.50 {"k": 1}`, "2 y <nil> <nil>", "3 x 3.25 []"))
+
+	// Declared columns come first and keep their definitions, the other select fields are appended.
+	tk.MustExec("create table t2 (a varchar(20) primary key, d int default 7) as select a, count(*) as cnt, sum(b) s from src group by a")
+	tk.MustQuery("select column_name, column_type from information_schema.columns where table_schema = 'test' and table_name = 't2' order by ordinal_position").
+		Check(testkit.Rows("a varchar(20)", "d int(11)", "cnt bigint(21)", "s decimal(32,2)"))
+	tk.MustQuery("select * from t2 order by a").Check(testkit.Rows("x 7 2 4.75", "y 7 1 <nil>"))
+	tk.MustExec("create table t2g (g int as (id * 2)) select id from src where id = 3")
+	tk.MustQuery("select * from t2g").Check(testkit.Rows("6 3"))
+
+	// Duplicate key handling.
+	tk.MustGetErrCode("create table t3 (a varchar(10) primary key) select a from src", errno.ErrDupEntry)
+	tk.MustQuery("show tables like 't3'").Check(testkit.Rows())
+	// The table is filled under a hidden name, which is dropped when the statement fails.
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'test' and table_name like '\\_tidb\\_ctas\\_%'").
+		Check(testkit.Rows("0"))
+	tk.MustExec("create table t3 (a varchar(10) primary key) ignore select a, id from src order by id")
+	tk.MustQuery("select * from t3 order by a").Check(testkit.Rows("x 1", "y 2"))
+	tk.MustExec("create table t4 (a varchar(10) primary key) replace select a, id from src order by id")
+	tk.MustQuery("select * from t4 order by a").Check(testkit.Rows("x 3", "y 2"))
+
+	// The table is not filled if it already exists.
+	tk.MustExec("create table if not exists t1 select * from src where id = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1050 Table 'test.t1' already exists"))
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("3"))
+	tk.MustGetErrCode("create table t1 select * from src", errno.ErrTableExists)
+
+	// Errors of the select are returned before the table is created.
+	tk.MustGetErrCode("create table t5 select * from not_exists", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("create table t5 select id, id from src", errno.ErrDupFieldName)
+	tk.MustQuery("show tables like 't5'").Check(testkit.Rows())
+
+	// It commits the current transaction implicitly.
+	tk.MustExec("begin")
+	tk.MustExec("insert into src values (4, 'z', 4, null)")
+	tk.MustExec("create table t6 select id from src")
+	tk.MustExec("rollback")
+	tk.MustQuery("select count(*) from src").Check(testkit.Rows("4"))
+	tk.MustQuery("select count(*) from t6").Check(testkit.Rows("4"))
+
+	// Local temporary tables are supported, global temporary tables are not.
+	tk.MustExec("create temporary table tmp select id from src where id > 2")
+	tk.MustQuery("select * from tmp order by id").Check(testkit.Rows("3", "4"))
+	tk.MustGetErrCode("create temporary table tmp2 (id int primary key) select 1 as id union all select 1", errno.ErrDupEntry)
+	tk.MustGetErrCode("select * from tmp2", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("create global temporary table gtmp (id int) select id from src on commit delete rows", errno.ErrOptOnTemporaryTable)
+
+	tk.MustExec("prepare stmt from 'create table t7 select id from src where id > 3'")
+	tk.MustExec("execute stmt")
+	tk.MustQuery("select * from t7").Check(testkit.Rows("4"))
+
+	// The rows are inserted into the table, which requires the INSERT privilege besides CREATE.
+	tk.MustExec("create user 'ctas'@'%'")
+	tk.MustExec("grant create, select on test.* to 'ctas'@'%'")
+	tkUser := testkit.NewTestKit(t, store)
+	require.NoError(t, tkUser.Session().Auth(&auth.UserIdentity{Username: "ctas", Hostname: "%"}, nil, nil, nil))
+	tkUser.MustGetErrCode("create table test.t8 select * from test.src", errno.ErrTableaccessDenied)
+	tk.MustExec("grant insert on test.* to 'ctas'@'%'")
+
+	// It is executed as one statement, and the select is only executed once.
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	tkUser.MustExec("create table test.t8 select id, a from test.src where id < 3")
+	tk.MustQuery("select * from t8 order by id").Check(testkit.Rows("1 x", "2 y"))
+	tk.MustQuery("select stmt_type, exec_count, avg_affected_rows from information_schema.statements_summary where query_sample_text like 'create table test.t8%'").
+		Check(testkit.Rows("CreateTable 1 2"))
+	tk.MustQuery("select count(*) from information_schema.statements_summary where query_sample_text like '%limit 0%' or stmt_type = 'Insert'").
+		Check(testkit.Rows("0"))
+}
+
 func TestCreateDropDatabase(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t, mockstore.WithDDLChecker())
 
@@ -28,10 +28,12 @@ import (
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/executor"
 	"github.com/pingcap/tidb/pkg/session"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -870,3 +872,90 @@ func TestIssues46005(t *testing.T) {
 
 	tk.MustQuery("select /*+ USE_INDEX_MERGE(t, idx1, idx2) */ * from t where a = 1 or b = 1 order by c limit 1025")
 }
+
+func TestFullTextIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, title varchar(100), body text, fulltext key ft(title, body))")
+	tk.MustExec(`insert into t values
+		(1, 'MySQL Tutorial', 'DBMS stands for DataBase ...'),
+		(2, 'How To Use MySQL Well', 'After you went through a ...'),
+		(3, 'Optimizing MySQL', 'In this tutorial, we show ...'),
+		(4, '1001 MySQL Tricks', '1. Never run mysqld as root. 2. ...'),
+		(5, 'MySQL vs. YourSQL', 'In the following database comparison ...'),
+		(6, 'MySQL Security', 'When configured properly, MySQL ...')`)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `title` varchar(100) DEFAULT NULL,\n" +
+		"  `body` text DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  FULLTEXT KEY `ft` (`title`,`body`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The number of rows used by the relevance is from the statistics of the table.
+	tk.MustExec("analyze table t")
+	// Natural language mode.
+	tk.MustQuery("select id from t where match(title, body) against ('database') order by id").Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select id, match(title, body) against ('database tutorial') as score from t having score > 0 order by score desc, id").
+		Check(testkit.Rows("1 0.4552893834105299", "3 0.22764469170526494", "5 0.22764469170526494"))
+	// Boolean mode.
+	tk.MustQuery("select id from t where match(title, body) against ('+mysql -yoursql' in boolean mode) order by id").
+		Check(testkit.Rows("1", "2", "3", "4", "6"))
+	tk.MustQuery("select id from t where match(title, body) against ('+tutorial +(dbms optimizing)' in boolean mode) order by id").
+		Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select id from t where match(title, body) against ('secur*' in boolean mode)").Check(testkit.Rows("6"))
+	tk.MustQuery(`select id from t where match(title, body) against ('"following database"' in boolean mode)`).Check(testkit.Rows("5"))
+	tk.MustQuery(`select id from t where match(title, body) against ('"database following"' in boolean mode)`).Check(testkit.Rows())
+
+	// The index is read by IndexMerge and the result is the same as the table scan.
+	query := "select /*+ use_index_merge(t, ft) */ id from t where match(title, body) against ('+mysql -yoursql tutorial' in boolean mode) order by id"
+	tk.MustHavePlan(query, "IndexMerge")
+	tk.MustQuery(query).Check(testkit.Rows("1", "2", "3", "4", "6"))
+	query = "select /*+ use_index_merge(t, ft) */ id from t where match(title, body) against ('secur* comparison' in boolean mode)"
+	tk.MustHavePlan(query, "IndexMerge")
+	tk.MustQuery(query).Sort().Check(testkit.Rows("5", "6"))
+
+	// The index is maintained by DML.
+	tk.MustExec("update t set body = 'a database security guide' where id = 6")
+	tk.MustExec("delete from t where id = 5")
+	tk.MustExec("insert into t values (7, 'Database Internals', null)")
+	query = "select /*+ use_index_merge(t, ft) */ id from t where match(title, body) against ('database') order by id"
+	tk.MustHavePlan(query, "IndexMerge")
+	tk.MustQuery(query).Check(testkit.Rows("1", "6", "7"))
+	tk.MustExec("admin check table t")
+
+	// The relevance counts the rows written by the current transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (8, 'Database', null), (9, 'Database Design', null)")
+	tk.MustExec("delete from t where id = 1")
+	tk.MustQuery("select id, match(title, body) against ('database') as score from t having score > 0 order by id").
+		Check(testkit.Rows("6 0.059067493109241614", "7 0.059067493109241614", "8 0.059067493109241614", "9 0.059067493109241614"))
+	tk.MustExec("rollback")
+
+	// The index is backfilled by ADD INDEX.
+	tk.MustExec("alter table t add fulltext index ft_title(title)")
+	query = "select /*+ use_index_merge(t, ft_title) */ id from t where match(title) against ('mysql') order by id"
+	tk.MustHavePlan(query, "IndexMerge")
+	tk.MustQuery(query).Check(testkit.Rows("1", "2", "3", "4", "6"))
+	tk.MustExec("admin check table t")
+
+	// The ngram parser for CJK text.
+	tk.MustExec("create table t2(id int primary key, c text, fulltext key ft(c) with parser ngram)")
+	tk.MustExec("insert into t2 values (1, '分布式数据库'), (2, '数据仓库'), (3, '关系型数据库')")
+	tk.MustQuery("show create table t2").Check(testkit.Rows("t2 CREATE TABLE `t2` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `c` text DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  FULLTEXT KEY `ft` (`c`) /*!50100 WITH PARSER `ngram` */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	query = "select /*+ use_index_merge(t2, ft) */ id from t2 where match(c) against ('+数据库' in boolean mode) order by id"
+	tk.MustHavePlan(query, "IndexMerge")
+	tk.MustQuery(query).Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select id from t2 where match(c) against ('+仓库 -分布' in boolean mode)").Check(testkit.Rows("2"))
+
+	// Errors.
+	tk.MustGetErrCode("create table t3(a int, fulltext key(a))", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("create table t3(a text, fulltext key(a) with parser mecab)", errno.ErrFunctionNotDefined)
+	tk.MustGetDBError("select * from t where match(body) against ('mysql')", plannererrors.ErrFtMatchingKeyNotFound)
+}
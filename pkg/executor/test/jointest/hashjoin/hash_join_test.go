@@ -477,3 +477,162 @@ func TestIssue31129(t *testing.T) {
 	require.NoError(t, failpoint.Disable(fpName1))
 	require.NoError(t, failpoint.Disable(fpName2))
 }
+
+func TestAdaptiveHashJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("analyze table t1, t2")
+	// the statistics of t1 are outdated, so the row count of t1 is badly underestimated.
+	tk.MustExec("insert into t1 with recursive c(n) as (select 2 union all select n + 1 from c where n < 300) select n, n from c")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	adaptiveInfo := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "HashJoin") {
+				execInfo := row[5].(string)
+				if idx := strings.Index(execInfo, "adaptive:"); idx >= 0 {
+					return execInfo[idx:]
+				}
+				return ""
+			}
+		}
+		require.FailNow(t, "no hash join in the plan")
+		return ""
+	}
+	sql := "select /*+ hash_join_build(t1) */ t1.a, t2.b from t1 join t2 on t1.a = t2.a order by t1.a"
+	tk.MustQuery(sql).Check(testkit.Rows("1 1", "2 2", "3 3"))
+	require.Empty(t, adaptiveInfo(sql))
+
+	tk.MustExec("set @@tidb_enable_adaptive_join = on")
+	tk.MustQuery(sql).Check(testkit.Rows("1 1", "2 2", "3 3"))
+	require.Regexp(t, "^adaptive:\\{swap build and probe side, est_build_rows:1, act_build_rows:>=.*, act_probe_rows:3\\}$", adaptiveInfo(sql))
+	// the other conditions are evaluated on the joined rows in the original column order.
+	tk.MustQuery("select /*+ hash_join_build(t1) */ t1.a, t2.b from t1 join t2 on t1.a = t2.a and t1.b < t2.b + 1 and t2.a > 1").
+		Sort().Check(testkit.Rows("2 2", "3 3"))
+	// the sides are restored when the join is executed again.
+	tk.MustQuery("select x.a, (select /*+ no_decorrelate() hash_join_build(t1) */ count(*) from t1 join t2 on t1.a = t2.a where t2.b >= x.a) from t2 x order by x.a").
+		Check(testkit.Rows("1 3", "2 2", "3 1"))
+
+	// the build side isn't swapped if the probe side is even larger.
+	tk.MustExec("insert into t2 select a, b from t1 union all select a, b from t1")
+	tk.MustQuery("select /*+ hash_join_build(t1) */ count(*) from t1 join t2 on t1.a = t2.a").Check(testkit.Rows("603"))
+	require.Regexp(t, "^adaptive:\\{keep build side, est_build_rows:1, act_build_rows:300\\}$", adaptiveInfo("select /*+ hash_join_build(t1) */ count(*) from t1 join t2 on t1.a = t2.a"))
+
+	// the build side isn't re-decided if the deviation doesn't exceed the threshold.
+	tk.MustExec("set @@tidb_adaptive_join_threshold = 1000")
+	require.Empty(t, adaptiveInfo(sql))
+}
+
+func TestAdaptiveIndexJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, key(a, b))")
+	tk.MustExec("insert into t1 values (1, 1)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("analyze table t1, t2")
+	// the statistics of t1 are outdated, so the row count of the outer side is badly underestimated.
+	tk.MustExec("insert into t1 with recursive c(n) as (select 2 union all select n + 1 from c where n < 300) select n, n from c")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	adaptiveInfo := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "IndexJoin") || strings.Contains(row[0].(string), "IndexHashJoin") {
+				execInfo := row[5].(string)
+				if idx := strings.Index(execInfo, "adaptive:"); idx >= 0 {
+					return execInfo[idx:]
+				}
+				return ""
+			}
+		}
+		require.FailNow(t, "no index join in the plan")
+		return ""
+	}
+	// t2.b > 1 is a part of the index range of the index join, it's evaluated by the hash join as well.
+	sql := "select /*+ inl_join(t2) */ t1.a, t2.b from t1 join t2 on t1.a = t2.a and t2.b > 1 order by t1.a"
+	tk.MustQuery(sql).Check(testkit.Rows("2 2", "3 3"))
+	require.Empty(t, adaptiveInfo(sql))
+
+	tk.MustExec("set @@tidb_enable_adaptive_join = on")
+	tk.MustQuery(sql).Check(testkit.Rows("2 2", "3 3"))
+	require.Regexp(t, "^adaptive:\\{switch to hash join, est_outer_rows:1, act_outer_rows:>\\d+\\}", adaptiveInfo(sql))
+	hashSQL := "select /*+ inl_hash_join(t2) */ t1.a, t2.b from t1 join t2 on t1.a = t2.a and t2.b > 1 order by t1.a"
+	tk.MustQuery(hashSQL).Check(testkit.Rows("2 2", "3 3"))
+	require.Regexp(t, "^adaptive:\\{switch to hash join", adaptiveInfo(hashSQL))
+	tk.MustQuery("select /*+ inl_join(t2) */ count(*), count(t2.b) from t1 left join t2 on t1.a = t2.a and t2.b > 1").
+		Check(testkit.Rows("300 2"))
+	// the index join is executed again with the outer side read in advance each time.
+	tk.MustQuery("select x.a, (select /*+ no_decorrelate() inl_join(t2) */ count(*) from t1 join t2 on t1.a = t2.a where t1.b >= x.a) from t2 x order by x.a").
+		Check(testkit.Rows("1 3", "2 2", "3 1"))
+	// the rows read in advance spill to disk when the memory quota is exceeded.
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	tk.MustQuery(sql).Check(testkit.Rows("2 2", "3 3"))
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+
+	// the index join isn't switched if the deviation doesn't exceed the threshold.
+	tk.MustExec("set @@tidb_adaptive_join_threshold = 1000")
+	tk.MustQuery(sql).Check(testkit.Rows("2 2", "3 3"))
+	require.Empty(t, adaptiveInfo(sql))
+}
+
+func TestHashJoinSpill(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@cte_max_recursion_depth = 10000")
+	tk.MustExec("create table t1(a int, b varchar(100))")
+	tk.MustExec("create table t2(a int, b varchar(100))")
+	tk.MustExec("insert into t1 with recursive c(n) as (select 1 union all select n + 1 from c where n < 5000) select n, repeat('x', 100) from c")
+	tk.MustExec("insert into t2 with recursive c(n) as (select 1 union all select n + 1 from c where n < 2000) select n * 3, repeat('y', 100) from c")
+	tk.MustExec("insert into t2 values (null, 'z')")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	spillInfo := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "HashJoin") {
+				execInfo := row[5].(string)
+				if idx := strings.Index(execInfo, "spill:"); idx >= 0 {
+					return execInfo[idx:]
+				}
+				return ""
+			}
+		}
+		require.FailNow(t, "no hash join in the plan")
+		return ""
+	}
+	sqls := []string{
+		"select /*+ hash_join_build(t1) */ t1.a, t1.b, t2.b from t1 join t2 on t1.a = t2.a",
+		"select /*+ hash_join_build(t1) */ t1.a, t2.a from t2 left join t1 on t1.a = t2.a",
+		"select /*+ hash_join_build(t1) */ t2.a from t2 where t2.a in (select a from t1 where t1.a % 2 = 0)",
+		"select /*+ hash_join_build(t1) */ t2.a from t2 where not exists (select 1 from t1 where t1.a = t2.a and t1.a % 2 = 0)",
+	}
+	results := make([][][]any, 0, len(sqls))
+	for _, sql := range sqls {
+		results = append(results, tk.MustQuery(sql).Sort().Rows())
+	}
+
+	tk.MustExec("set @@tidb_mem_quota_query = 120000")
+	for i, sql := range sqls {
+		tk.MustQuery(sql).Sort().Check(results[i])
+		require.Regexp(t, "^spill:\\{partitions:\\d+, max_depth:1\\}$", spillInfo(sql))
+	}
+
+	// the partition of the skewed key is repartitioned until it can't be repartitioned any more.
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustExec("insert into t1 select 6, b from t1 limit 3000")
+	result := tk.MustQuery(sqls[0]).Sort().Rows()
+	tk.MustExec("set @@tidb_mem_quota_query = 120000")
+	tk.MustQuery(sqls[0]).Sort().Check(result)
+	require.Regexp(t, "^spill:\\{partitions:\\d+, max_depth:3\\}$", spillInfo(sqls[0]))
+
+	// the hash join isn't spilled by partitions if it's disabled.
+	tk.MustExec("set @@tidb_enable_hash_join_spill = off")
+	tk.MustQuery(sqls[0]).Sort().Check(result)
+	require.Empty(t, spillInfo(sqls[0]))
+}
@@ -0,0 +1,631 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/planner"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+)
+
+const (
+	triggerRowNew = "new"
+	triggerRowOld = "old"
+)
+
+// WithTrigger indicates the executor may fire the triggers of the tables it writes.
+type WithTrigger interface {
+	HasTriggers() bool
+}
+
+func (e *DDLExec) executeCreateTrigger(s *ast.CreateTriggerStmt) error {
+	tbl, err := e.is.TableByName(s.Table.Schema, s.Table.Name)
+	if err != nil {
+		return err
+	}
+	if err = checkTrigger(s, tbl.Meta()); err != nil {
+		return err
+	}
+	return domain.GetDomain(e.Ctx()).DDL().CreateTrigger(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeDropTrigger(s *ast.DropTriggerStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().DropTrigger(e.Ctx(), s)
+}
+
+// triggerIsVisible checks whether the current user can see the triggers of the table.
+func triggerIsVisible(sctx sessionctx.Context, schema, tbl string) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	return checker == nil || sctx.GetSessionVars().User == nil ||
+		checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema, tbl, "", mysql.TriggerPriv)
+}
+
+// triggerCreated returns the creation time of the trigger in the session time zone.
+func triggerCreated(sctx sessionctx.Context, trigger *model.TriggerInfo) types.Time {
+	created := trigger.Created.In(sctx.GetSessionVars().Location())
+	return types.NewTime(types.FromGoTime(created), mysql.TypeDatetime, 2)
+}
+
+func (e *ShowExec) fetchShowTriggers() error {
+	if e.DBName.L == "" {
+		return plannererrors.ErrNoDB
+	}
+	if !e.is.SchemaExists(e.DBName) {
+		return exeerrors.ErrBadDB.GenWithStackByArgs(e.DBName)
+	}
+	tblInfos := e.is.SchemaTableInfos(e.DBName)
+	slices.SortFunc(tblInfos, func(a, b *model.TableInfo) int {
+		return strings.Compare(a.Name.L, b.Name.L)
+	})
+	for _, tblInfo := range tblInfos {
+		if len(tblInfo.Triggers) == 0 || !triggerIsVisible(e.Ctx(), e.DBName.L, tblInfo.Name.L) {
+			continue
+		}
+		for _, trigger := range tblInfo.Triggers {
+			e.appendRow([]any{trigger.Name.O, trigger.Event.String(), tblInfo.Name.O, trigger.Body,
+				trigger.Timing.String(), triggerCreated(e.Ctx(), trigger), trigger.SQLMode,
+				trigger.Definer.String(), trigger.CharsetClient, trigger.CollationConnection, trigger.DBCollation})
+		}
+	}
+	return nil
+}
+
+// checkTrigger checks the semantics of the trigger body which aren't checked by the parser.
+func checkTrigger(stmt *ast.CreateTriggerStmt, tblInfo *model.TableInfo) error {
+	c := &procedureChecker{
+		trigger: &triggerChecker{tblInfo: tblInfo, timing: stmt.Timing, event: stmt.Event},
+	}
+	return c.checkStmt(stmt.Body)
+}
+
+// checkTriggerStmt checks whether the statement can be executed in a trigger.
+func checkTriggerStmt(stmt ast.StmtNode) error {
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.ShowStmt, *ast.ExplainStmt:
+		return exeerrors.ErrSpNoRetset.GenWithStackByArgs("trigger")
+	case *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt, ast.DDLNode:
+		return exeerrors.ErrCommitNotAllowedInSfOrTrg.GenWithStackByArgs()
+	}
+	return nil
+}
+
+// triggerChecker checks the statements of the trigger body and their references of the NEW and OLD rows.
+type triggerChecker struct {
+	tblInfo *model.TableInfo
+	timing  model.TriggerTiming
+	event   model.TriggerEvent
+	err     error
+}
+
+func (c *triggerChecker) checkStmt(stmt ast.StmtNode) error {
+	if err := checkTriggerStmt(stmt); err != nil {
+		return err
+	}
+	return c.checkRefs(stmt)
+}
+
+// checkRefs checks the references of the NEW and OLD rows in the node.
+func (c *triggerChecker) checkRefs(node ast.Node) error {
+	if node == nil {
+		return nil
+	}
+	node.Accept(c)
+	err := c.err
+	c.err = nil
+	return err
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *triggerChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnNameExpr:
+		if x.Name.Schema.L == "" {
+			c.err = c.checkRow(x.Name.Table.L, x.Name.Name.O, false)
+		}
+	case *ast.VariableAssignment:
+		if row, col, ok := triggerRowVar(x); ok {
+			c.err = c.checkRow(row, col, true)
+		}
+	}
+	return in, c.err != nil
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *triggerChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.err == nil
+}
+
+func (c *triggerChecker) checkRow(row, col string, isSet bool) error {
+	if row != triggerRowNew && row != triggerRowOld {
+		return nil
+	}
+	if row == triggerRowOld && c.event == model.TriggerEventInsert {
+		return dbterror.ErrTrgNoSuchRowInTrg.GenWithStackByArgs("OLD", "on INSERT")
+	}
+	if row == triggerRowNew && c.event == model.TriggerEventDelete {
+		return dbterror.ErrTrgNoSuchRowInTrg.GenWithStackByArgs("NEW", "on DELETE")
+	}
+	colInfo := model.FindColumnInfo(c.tblInfo.Cols(), strings.ToLower(col))
+	if colInfo == nil {
+		return plannererrors.ErrUnknownColumn.GenWithStackByArgs(col, strings.ToUpper(row))
+	}
+	if !isSet {
+		return nil
+	}
+	if row == triggerRowOld {
+		return dbterror.ErrTrgCantChangeRow.GenWithStackByArgs("OLD", "")
+	}
+	if c.timing == model.TriggerTimingAfter {
+		return dbterror.ErrTrgCantChangeRow.GenWithStackByArgs("NEW", "after ")
+	}
+	if colInfo.IsGenerated() {
+		return plannererrors.ErrBadGeneratedColumn.GenWithStackByArgs(colInfo.Name.O, c.tblInfo.Name.O)
+	}
+	return nil
+}
+
+// triggerRowVar returns the row and the column if the assignment is `SET NEW.col = expr` or `SET OLD.col = expr`.
+func triggerRowVar(assign *ast.VariableAssignment) (row, col string, ok bool) {
+	if !assign.IsSystem || assign.IsGlobal {
+		return "", "", false
+	}
+	row, col, ok = strings.Cut(assign.Name, ".")
+	row = strings.ToLower(row)
+	if !ok || (row != triggerRowNew && row != triggerRowOld) {
+		return "", "", false
+	}
+	return row, col, true
+}
+
+// triggerRuntime is shared by the statements fired by a statement, directly or by the nested triggers.
+type triggerRuntime struct {
+	// tables are the tables written by the running statements, the statements in the trigger bodies can't write them.
+	tables []int64
+}
+
+// checkTargets checks the tables written by the executor aren't written by the running statements.
+func (rt *triggerRuntime) checkTargets(e exec.Executor) error {
+	var tbls []table.Table
+	switch x := e.(type) {
+	case *InsertExec:
+		tbls = append(tbls, x.Table)
+	case *ReplaceExec:
+		tbls = append(tbls, x.Table)
+	case *UpdateExec:
+		for _, tbl := range x.tblID2table {
+			tbls = append(tbls, tbl)
+		}
+	case *DeleteExec:
+		for _, tbl := range x.tblID2Table {
+			tbls = append(tbls, tbl)
+		}
+	}
+	for _, tbl := range tbls {
+		if slices.Contains(rt.tables, tbl.Meta().ID) {
+			return exeerrors.ErrCantUpdateUsedTableInSfOrTrg.GenWithStackByArgs(tbl.Meta().Name.O)
+		}
+	}
+	return nil
+}
+
+// TriggerExec fires the triggers of a table for the rows written by an executor.
+// The trigger bodies are executed like the procedures, the statements in the bodies are executed
+// in the statement which fires the triggers, so they're rolled back with the statement.
+type TriggerExec struct {
+	ctx    sessionctx.Context
+	is     infoschema.InfoSchema
+	rt     *triggerRuntime
+	tbl    table.Table
+	dbName model.CIStr
+	// targets are the tables written by the executor.
+	targets []int64
+	// bodies caches the parsed trigger bodies, the key is the lower case trigger name.
+	bodies map[string]*triggerBody
+	// genExprs are the expressions of the generated columns by the column offsets, they're built when the NEW row is
+	// changed by a BEFORE trigger for the first time.
+	genExprs []expression.Expression
+}
+
+// triggerBody is a parsed trigger body.
+type triggerBody struct {
+	stmt    ast.StmtNode
+	sqlMode mysql.SQLMode
+	parser  *parser.Parser
+	texts   map[ast.Node]string
+}
+
+func (b *executorBuilder) buildTriggerExec(tbl table.Table, targets []int64) (*TriggerExec, error) {
+	if len(tbl.Meta().Triggers) == 0 {
+		return nil, nil
+	}
+	dbInfo, ok := infoschema.SchemaByTable(b.is, tbl.Meta())
+	if !ok {
+		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs("", tbl.Meta().Name.O)
+	}
+	if b.triggerRT == nil {
+		b.triggerRT = &triggerRuntime{}
+	}
+	return &TriggerExec{
+		ctx:     b.ctx,
+		is:      b.is,
+		rt:      b.triggerRT,
+		tbl:     tbl,
+		dbName:  dbInfo.Name,
+		targets: targets,
+		bodies:  make(map[string]*triggerBody),
+	}, nil
+}
+
+func (b *executorBuilder) buildTblID2TriggerExecs(tblID2Table map[int64]table.Table) (map[int64]*TriggerExec, error) {
+	targets := make([]int64, 0, len(tblID2Table))
+	for _, tbl := range tblID2Table {
+		targets = append(targets, tbl.Meta().ID)
+	}
+	triggersMap := make(map[int64]*TriggerExec)
+	for tid, tbl := range tblID2Table {
+		triggers, err := b.buildTriggerExec(tbl, targets)
+		if err != nil {
+			return nil, err
+		}
+		if triggers != nil {
+			triggersMap[tid] = triggers
+		}
+	}
+	return triggersMap, nil
+}
+
+// fire executes the triggers of the timing and the event for a row, the new row can be changed by the BEFORE triggers.
+func (e *TriggerExec) fire(ctx context.Context, timing model.TriggerTiming, event model.TriggerEvent, oldRow, newRow []types.Datum) error {
+	if e == nil {
+		return nil
+	}
+	for _, trigger := range e.tbl.Meta().Triggers {
+		if trigger.Timing != timing || trigger.Event != event {
+			continue
+		}
+		if err := e.execTrigger(ctx, trigger, oldRow, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *TriggerExec) execTrigger(ctx context.Context, trigger *model.TriggerInfo, oldRow, newRow []types.Datum) error {
+	body, err := e.getBody(trigger)
+	if err != nil {
+		return err
+	}
+	rt := e.rt
+	depth := len(rt.tables)
+	rt.tables = append(rt.tables, e.targets...)
+	defer func() { rt.tables = rt.tables[:depth] }()
+
+	// The trigger is executed in the database of its table with the SQL mode it's created with.
+	sessVars := e.ctx.GetSessionVars()
+	oldDB, oldSQLMode := sessVars.CurrentDB, sessVars.SQLMode
+	insertValues, insertExtraCols := sessVars.CurrInsertValues, sessVars.CurrInsertBatchExtraCols
+	sessVars.CurrentDB, sessVars.SQLMode = e.dbName.O, body.sqlMode
+	defer func() {
+		sessVars.CurrentDB, sessVars.SQLMode = oldDB, oldSQLMode
+		sessVars.CurrInsertValues, sessVars.CurrInsertBatchExtraCols = insertValues, insertExtraCols
+	}()
+
+	pe := newProcedureExec(e.ctx, sessVars.StmtCtx.MemTracker, sessVars.StmtCtx.DiskTracker)
+	pe.trigger = &triggerInvocation{
+		exec:     e,
+		definer:  trigger.Definer,
+		oldRow:   oldRow,
+		newRow:   newRow,
+		isBefore: trigger.Timing == model.TriggerTimingBefore,
+	}
+	frame := &procedureFrame{
+		exec:   pe,
+		name:   e.dbName.O + "." + trigger.Name.O,
+		parser: body.parser,
+		texts:  body.texts,
+	}
+	err = frame.execStmt(ctx, newProcedureScope(nil), body.stmt)
+	if unhandled, ok := err.(*procedureUnhandledError); ok {
+		err = unhandled.err
+	}
+	return err
+}
+
+func (e *TriggerExec) getBody(trigger *model.TriggerInfo) (*triggerBody, error) {
+	if body, ok := e.bodies[trigger.Name.L]; ok {
+		return body, nil
+	}
+	sqlMode, err := mysql.GetSQLMode(trigger.SQLMode)
+	if err != nil {
+		return nil, err
+	}
+	p := newProcedureParser(e.ctx.GetSessionVars(), sqlMode)
+	sql := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW %s",
+		stringutil.Escape(trigger.Name.O, sqlMode), trigger.Timing, trigger.Event,
+		stringutil.Escape(e.tbl.Meta().Name.O, sqlMode), trigger.Body)
+	node, err := p.ParseOneStmt(sql, "", "")
+	if err != nil {
+		return nil, err
+	}
+	body := &triggerBody{
+		stmt:    node.(*ast.CreateTriggerStmt).Body,
+		sqlMode: sqlMode,
+		parser:  p,
+		texts:   make(map[ast.Node]string),
+	}
+	e.bodies[trigger.Name.L] = body
+	return body, nil
+}
+
+// HasTriggers implements WithTrigger interface.
+func (e *InsertValues) HasTriggers() bool {
+	return e.triggers != nil
+}
+
+// HasTriggers implements WithTrigger interface.
+func (e *UpdateExec) HasTriggers() bool {
+	return len(e.triggers) > 0
+}
+
+// HasTriggers implements WithTrigger interface.
+func (e *DeleteExec) HasTriggers() bool {
+	return len(e.triggers) > 0
+}
+
+// triggerInvocation is a running trigger for a row.
+type triggerInvocation struct {
+	exec *TriggerExec
+	// definer is the user whose privileges the statements in the trigger body are executed with.
+	definer  *auth.UserIdentity
+	oldRow   []types.Datum
+	newRow   []types.Datum
+	isBefore bool
+}
+
+// rowValue returns the value of `NEW.col` or `OLD.col`, the returned bool is false if the row isn't NEW or OLD.
+func (t *triggerInvocation) rowValue(row string, col model.CIStr) (types.Datum, bool, error) {
+	var data []types.Datum
+	switch row {
+	case triggerRowNew:
+		data = t.newRow
+	case triggerRowOld:
+		data = t.oldRow
+	default:
+		return types.Datum{}, false, nil
+	}
+	if data == nil {
+		return types.Datum{}, true, dbterror.ErrTrgNoSuchRowInTrg.GenWithStackByArgs(strings.ToUpper(row), "on "+t.eventName())
+	}
+	offset := t.columnOffset(col.L)
+	if offset < 0 {
+		return types.Datum{}, true, plannererrors.ErrUnknownColumn.GenWithStackByArgs(col.O, strings.ToUpper(row))
+	}
+	return data[offset], true, nil
+}
+
+// setNewValue executes `SET NEW.col = value`.
+func (t *triggerInvocation) setNewValue(row, col string, d types.Datum) error {
+	if row != triggerRowNew {
+		return dbterror.ErrTrgCantChangeRow.GenWithStackByArgs("OLD", "")
+	}
+	if !t.isBefore {
+		return dbterror.ErrTrgCantChangeRow.GenWithStackByArgs("NEW", "after ")
+	}
+	if t.newRow == nil {
+		return dbterror.ErrTrgNoSuchRowInTrg.GenWithStackByArgs("NEW", "on "+t.eventName())
+	}
+	tbl := t.exec.tbl
+	offset := t.columnOffset(strings.ToLower(col))
+	if offset < 0 {
+		return plannererrors.ErrUnknownColumn.GenWithStackByArgs(col, "NEW")
+	}
+	column := tbl.Cols()[offset]
+	v, err := table.CastValue(t.exec.ctx, d, column.ColumnInfo, false, false)
+	if err != nil {
+		return err
+	}
+	if err = column.CheckNotNull(&v, 0); err != nil {
+		return err
+	}
+	t.newRow[offset] = v
+	// The generated columns are evaluated before the BEFORE triggers, evaluate them again with the changed column.
+	return t.exec.evalGeneratedColumns(t.newRow)
+}
+
+// evalGeneratedColumns evaluates the generated columns of the row in the order of the columns, since a generated
+// column can only refer to the generated columns defined before it.
+func (e *TriggerExec) evalGeneratedColumns(row []types.Datum) error {
+	exprs, err := e.getGeneratedExprs()
+	if err != nil {
+		return err
+	}
+	evalCtx := e.ctx.GetExprCtx().GetEvalCtx()
+	for offset, col := range e.tbl.Cols() {
+		if exprs[offset] == nil {
+			continue
+		}
+		val, err := exprs[offset].Eval(evalCtx, chunk.MutRowFromDatums(row).ToRow())
+		if err != nil {
+			return err
+		}
+		if row[offset], err = table.CastValue(e.ctx, val, col.ColumnInfo, false, false); err != nil {
+			return err
+		}
+		if err = col.CheckNotNull(&row[offset], 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *TriggerExec) getGeneratedExprs() ([]expression.Expression, error) {
+	if e.genExprs != nil {
+		return e.genExprs, nil
+	}
+	tblInfo := e.tbl.Meta()
+	cols := e.tbl.Cols()
+	colInfos := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		colInfos = append(colInfos, col.ColumnInfo)
+	}
+	exprCtx := e.ctx.GetExprCtx()
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(exprCtx, e.dbName, tblInfo.Name, colInfos, tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	schema := expression.NewSchema(columns...)
+	exprs := make([]expression.Expression, len(cols))
+	for i, col := range cols {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, err := expression.ParseSimpleExpr(exprCtx, col.GeneratedExprString,
+			expression.WithInputSchemaAndNames(schema, names, tblInfo), expression.WithAllowCastArray(true))
+		if err != nil {
+			return nil, err
+		}
+		if exprs[i], err = expr.ResolveIndices(schema); err != nil {
+			return nil, err
+		}
+	}
+	e.genExprs = exprs
+	return exprs, nil
+}
+
+func (t *triggerInvocation) columnOffset(lowerName string) int {
+	return slices.IndexFunc(t.exec.tbl.Cols(), func(col *table.Column) bool {
+		return col.Name.L == lowerName
+	})
+}
+
+func (t *triggerInvocation) eventName() string {
+	switch {
+	case t.oldRow == nil:
+		return model.TriggerEventInsert.String()
+	case t.newRow == nil:
+		return model.TriggerEventDelete.String()
+	}
+	return model.TriggerEventUpdate.String()
+}
+
+// run executes a statement of the trigger body, it's built and executed like the foreign key cascades.
+// The statement writes the txn mem-buffer in a staging buffer, the changes are released to the statement
+// which fires the trigger if it succeeds.
+func (t *triggerInvocation) run(ctx context.Context, node ast.StmtNode, isQuery bool) ([]*types.FieldType, []chunk.Row, error) {
+	e := t.exec
+	sctx := e.ctx
+	stmtCtx := sctx.GetSessionVars().StmtCtx
+	inHandleTrigger := stmtCtx.InHandleTrigger
+	stmtCtx.InHandleTrigger = true
+	defer func() { stmtCtx.InHandleTrigger = inHandleTrigger }()
+	if !isQuery {
+		if err := checkTriggerStmt(node); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := plannercore.Preprocess(ctx, sctx, node); err != nil {
+		return nil, nil, err
+	}
+	p, err := planner.OptimizeForTrigger(ctx, sctx.GetPlanCtx(), node, e.is, t.definer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isQuery && !isNoResultPlan(p) {
+		return nil, nil, exeerrors.ErrSpNoRetset.GenWithStackByArgs("trigger")
+	}
+	b := newExecutorBuilder(sctx, e.is)
+	b.triggerRT = e.rt
+	exe := b.build(p)
+	if b.err != nil {
+		return nil, nil, b.err
+	}
+	if err = e.rt.checkTargets(exe); err != nil {
+		return nil, nil, err
+	}
+
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	memBuffer := txn.GetMemBuffer()
+	sh := memBuffer.Staging()
+	defer memBuffer.Cleanup(sh)
+
+	rows, err := drainTriggerExec(ctx, exe)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fk, ok := exe.(WithForeignKeyTrigger); ok {
+		for _, fkCheck := range fk.GetFKChecks() {
+			if err = fkCheck.doCheck(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, fkCascade := range fk.GetFKCascades() {
+			if len(fkCascade.fkValues) > 0 || len(fkCascade.fkUpdatedValuesMap) > 0 {
+				return nil, nil, dbterror.ErrNotSupportedYet.GenWithStackByArgs("foreign key cascades in a trigger")
+			}
+		}
+	}
+	memBuffer.Release(sh)
+	return exec.RetTypes(exe), rows, nil
+}
+
+func drainTriggerExec(ctx context.Context, e exec.Executor) ([]chunk.Row, error) {
+	if err := exec.Open(ctx, e); err != nil {
+		terror.Log(exec.Close(e))
+		return nil, err
+	}
+	var rows []chunk.Row
+	var err error
+	for {
+		chk := exec.NewFirstChunk(e)
+		if err = exec.Next(ctx, e, chk); err != nil || chk.NumRows() == 0 {
+			break
+		}
+		iter := chunk.NewIterator4Chunk(chk)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			rows = append(rows, row)
+		}
+	}
+	closeErr := exec.Close(e)
+	if err == nil {
+		err = closeErr
+	}
+	return rows, err
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDropTrigger(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a int, b int as (a + 1))")
+	tk.MustExec("create table t2 (id int)")
+	tk.MustExec("create view v as select * from t")
+
+	tk.MustExec("create trigger tr1 before insert on t for each row set new.a = new.a * 10")
+	tk.MustGetErrCode("create trigger tr1 before update on t for each row set new.a = 1", errno.ErrTrgAlreadyExists)
+	tk.MustExec("create trigger if not exists tr1 before update on t for each row set new.a = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1359 Trigger already exists"))
+	tk.MustExec("create trigger tr0 before insert on t for each row precedes tr1 set new.a = new.a + 1")
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row follows tr_not_exists set new.a = 1", errno.ErrReferencedTrgDoesNotExist)
+	tk.MustGetErrCode("create trigger tr2 before insert on v for each row set new.a = 1", errno.ErrTrgOnViewOrTempTable)
+	tk.MustGetErrCode("create trigger tr2 before insert on t_not_exists for each row set new.a = 1", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("create trigger mysql.tr2 before insert on test.t for each row set new.a = 1", errno.ErrTrgInWrongSchema)
+	tk.MustGetErrCode("create trigger mysql.tr2 before insert on mysql.user for each row set @a = 1", errno.ErrNoTriggersOnSystemSchema)
+
+	// The trigger bodies are checked when the trigger is created.
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row set new.c = 1", errno.ErrBadField)
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row set @a = old.a", errno.ErrTrgNoSuchRowInTrg)
+	tk.MustGetErrCode("create trigger tr2 before delete on t for each row set @a = new.a", errno.ErrTrgNoSuchRowInTrg)
+	tk.MustGetErrCode("create trigger tr2 after insert on t for each row set new.a = 1", errno.ErrTrgCantChangeRow)
+	tk.MustGetErrCode("create trigger tr2 before update on t for each row set old.a = 1", errno.ErrTrgCantChangeRow)
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row set new.b = 1", errno.ErrBadGeneratedColumn)
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row select 1", errno.ErrSpNoRetset)
+	tk.MustGetErrCode("create trigger tr2 before insert on t for each row begin commit; end", errno.ErrCommitNotAllowedInSfOrTrg)
+
+	tk.MustQuery("show triggers").CheckAt([]int{0, 1, 2, 3, 4}, testkit.RowsWithSep("|",
+		"tr0|INSERT|t|set new.a = new.a + 1|BEFORE",
+		"tr1|INSERT|t|set new.a = new.a * 10|BEFORE",
+	))
+	tk.MustQuery("show triggers like 't2'").Check(testkit.Rows())
+	tk.MustQuery(`select trigger_schema, trigger_name, event_manipulation, event_object_table, action_order, action_timing
+		from information_schema.triggers where trigger_schema = 'test' order by trigger_name`).Check(testkit.Rows(
+		"test tr0 INSERT t 1 BEFORE",
+		"test tr1 INSERT t 2 BEFORE",
+	))
+
+	// The triggers are renamed with the table, and can't be moved to another schema.
+	tk.MustExec("create database trigger_db")
+	tk.MustGetErrCode("rename table t to trigger_db.t", errno.ErrTrgInWrongSchema)
+	tk.MustExec("rename table t to t1")
+	tk.MustQuery("show triggers").CheckAt([]int{0, 2}, testkit.Rows("tr0 t1", "tr1 t1"))
+
+	tk.MustExec("drop trigger tr0")
+	tk.MustGetErrCode("drop trigger tr0", errno.ErrTrgDoesNotExist)
+	tk.MustExec("drop trigger if exists tr0")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1360 Trigger does not exist"))
+	tk.MustExec("create table t3 like t1")
+	tk.MustQuery("show triggers like 't3'").Check(testkit.Rows())
+
+	// The triggers are dropped with the table.
+	tk.MustExec("drop table t1")
+	tk.MustQuery("show triggers").Check(testkit.Rows())
+	tk.MustExec("drop database trigger_db")
+}
+
+func TestFireTrigger(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a int not null, b varchar(20))")
+	tk.MustExec("create table log (seq int auto_increment primary key, msg varchar(100))")
+
+	tk.MustExec("create trigger t_bi before insert on t for each row set new.b = concat('v', new.a)")
+	tk.MustExec("create trigger t_ai after insert on t for each row insert into log(msg) values (concat('insert ', new.id, ' ', new.b))")
+	tk.MustExec(`create trigger t_bu before update on t for each row
+	begin
+		if new.a < old.a then
+			set new.a = old.a;
+		end if;
+		set new.b = concat(old.b, '>', new.a);
+	end`)
+	tk.MustExec("create trigger t_ad after delete on t for each row insert into log(msg) values (concat('delete ', old.id, ' ', old.b))")
+
+	tk.MustExec("insert into t(id, a) values (1, 1), (2, 2)")
+	tk.MustQuery("select row_count()").Check(testkit.Rows("2"))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1 v1", "2 2 v2"))
+	tk.MustExec("update t set a = a + 1 where id = 1")
+	tk.MustExec("update t set a = 0 where id = 2")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 2 v1>2", "2 2 v2>2"))
+	tk.MustExec("insert into t(id, a) values (1, 5) on duplicate key update a = 7")
+	tk.MustQuery("select * from t where id = 1").Check(testkit.Rows("1 7 v1>2>7"))
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec("replace into t(id, a) values (1, 3)")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 3 v3"))
+	tk.MustQuery("select msg from log order by seq").Check(testkit.Rows(
+		"insert 1 v1",
+		"insert 2 v2",
+		"delete 2 v2>2",
+		"delete 1 v1>2>7",
+		"insert 1 v3",
+	))
+
+	// The changes of the triggers are rolled back with the statement.
+	tk.MustExec("create trigger t_bd before delete on t for each row insert into t(id, a) values (old.id, 0)")
+	tk.MustGetErrCode("delete from t", errno.ErrCantUpdateUsedTableInSfOrTrg)
+	tk.MustExec("drop trigger t_bd")
+	tk.MustExec("create trigger log_bi before insert on log for each row set new.msg = concat(new.msg, repeat('x', 100))")
+	tk.MustGetErrCode("insert into t(id, a) values (4, 4)", errno.ErrDataTooLong)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 3 v3"))
+	tk.MustQuery("select count(*) from log").Check(testkit.Rows("5"))
+	tk.MustExec("drop trigger log_bi")
+
+	tk.MustExec("begin")
+	tk.MustExec("insert into t(id, a) values (5, 5)")
+	tk.MustQuery("select count(*) from log").Check(testkit.Rows("6"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select count(*) from log").Check(testkit.Rows("5"))
+
+	// The NEW values are checked like the inserted values.
+	tk.MustExec("create trigger t_bi2 before insert on t for each row follows t_bi set new.a = null")
+	tk.MustGetErrCode("insert into t(id, a) values (6, 6)", errno.ErrBadNull)
+
+	// The triggers of a row are fired right before and after the row is written, rather than firing the BEFORE
+	// triggers of all the rows first.
+	tk.MustExec("create table t2 (id int primary key, a int)")
+	tk.MustExec("create table log2 (seq int auto_increment primary key, msg varchar(100))")
+	tk.MustExec("create trigger t2_bi before insert on t2 for each row insert into log2(msg) values (concat('bi ', new.id))")
+	tk.MustExec("create trigger t2_ai after insert on t2 for each row insert into log2(msg) values (concat('ai ', new.id))")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustExec("replace into t2 values (2, 20), (3, 30)")
+	tk.MustExec("insert into t2 values (4, 4), (1, 10) on duplicate key update a = values(a)")
+	tk.MustExec("insert ignore into t2 values (1, 1), (5, 5)")
+	tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("1 10", "2 20", "3 30", "4 4", "5 5"))
+	tk.MustQuery("select msg from log2 order by seq").Check(testkit.Rows(
+		"bi 1", "ai 1", "bi 2", "ai 2",
+		"bi 2", "ai 2", "bi 3", "ai 3",
+		"bi 4", "ai 4", "bi 1",
+		"bi 1", "bi 5", "ai 5",
+	))
+
+	// The generated columns are evaluated again after the NEW row is changed.
+	tk.MustExec("create table t3 (id int primary key, a int, b int as (a + 1) virtual, c int as (b * 2) stored, key(b))")
+	tk.MustExec("create trigger t3_bi before insert on t3 for each row set new.a = new.a * 10")
+	tk.MustExec("create trigger t3_bu before update on t3 for each row set new.a = new.a + 100")
+	tk.MustExec("insert into t3(id, a) values (1, 1), (2, 2)")
+	tk.MustExec("replace into t3(id, a) values (2, 3)")
+	tk.MustExec("update t3 set a = 0 where id = 1")
+	tk.MustQuery("select * from t3 order by id").Check(testkit.Rows("1 100 101 202", "2 30 31 62"))
+	tk.MustQuery("select id from t3 use index(b) where b = 31").Check(testkit.Rows("2"))
+	tk.MustExec("admin check table t3")
+}
+
+func TestTriggerDefinerPrivileges(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+	tk.MustExec("create table secret (id int)")
+	tk.MustExec("create table log (id int)")
+	tk.MustExec("create user 'low'@'%', 'writer'@'%'")
+	tk.MustExec("grant trigger, insert on test.t to 'low'@'%'")
+	tk.MustExec("grant insert on test.t to 'writer'@'%'")
+
+	// The body is executed with the privileges of the definer, which can't write the table.
+	tk.MustExec("create definer = 'low'@'%' trigger t_low after insert on t for each row insert into secret values (new.id)")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrTableaccessDenied)
+	tk.MustQuery("select count(*) from secret").Check(testkit.Rows("0"))
+	tk.MustExec("drop trigger t_low")
+
+	// The current user needn't have the privileges of the statements in the body.
+	tk.MustExec("create definer = 'root'@'%' trigger t_root after insert on t for each row insert into log values (new.id)")
+	tkWriter := testkit.NewTestKit(t, store)
+	require.NoError(t, tkWriter.Session().Auth(&auth.UserIdentity{Username: "writer", Hostname: "%"}, nil, nil, nil))
+	tkWriter.MustExec("insert into test.t values (2)")
+	tk.MustQuery("select * from log").Check(testkit.Rows("2"))
+	tkWriter.MustGetErrCode("insert into test.log values (3)", errno.ErrTableaccessDenied)
+}
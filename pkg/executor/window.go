@@ -37,17 +37,33 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// data stores the chunks to return, data[i].remaining indicates how many rows of it are not prepared.
+	data []dataInfo
+	// accumulated is the number of rows fetched from the child.
+	accumulated uint64
+	// consumed is the number of rows whose partitions have been processed.
+	consumed    uint64
+	spillHelper *windowSpillHelper
 
 	numWindowFuncs int
 	processor      windowProcessor
 }
 
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.BaseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.spillHelper = newWindowSpillHelper(&e.BaseExecutor, e.numWindowFuncs, e.copyChk)
+	return nil
+}
+
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	if e.spillHelper != nil {
+		e.spillHelper.close()
+		e.spillHelper = nil
+	}
 	return errors.Trace(e.BaseExecutor.Close())
 }
 
@@ -61,21 +77,25 @@ func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
+	if len(e.data) > 0 {
+		err := e.spillHelper.loadChk(&e.data[0])
+		if err != nil {
+			return err
+		}
+		chk.SwapColumns(e.data[0].chk)
+		e.spillHelper.release(&e.data[0])
+		e.data[0] = dataInfo{} // GC it. TODO: Reuse it.
+		e.data = e.data[1:]
 	}
 	return nil
 }
 
 func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
+	return len(e.data) > 0 && e.data[0].remaining == 0
 }
 
 func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
+	var numGroupRows uint64
 	if e.groupChecker.IsExhausted() {
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
@@ -83,7 +103,7 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return e.consumeGroupRows(numGroupRows)
 		}
 		_, err = e.groupChecker.SplitIntoGroups(e.childResult)
 		if err != nil {
@@ -91,19 +111,22 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 	}
 	begin, end := e.groupChecker.GetNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
-	}
+	numGroupRows += uint64(end - begin)
 
 	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
 		meetLastGroup = false
+		// The rows of a large partition are spilled while they are being fetched.
+		err := e.spillHelper.spillIfNeeded(e.data, -1)
+		if err != nil {
+			return err
+		}
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return e.consumeGroupRows(numGroupRows)
 		}
 
 		isFirstGroupSameAsPrev, err := e.groupChecker.SplitIntoGroups(e.childResult)
@@ -113,40 +136,62 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 
 		if isFirstGroupSameAsPrev {
 			begin, end = e.groupChecker.GetNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
-			}
+			numGroupRows += uint64(end - begin)
 			meetLastGroup = end == e.childResult.NumRows()
 		}
 	}
-	return e.consumeGroupRows(groupRows)
+	return e.consumeGroupRows(numGroupRows)
 }
 
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
+func (e *WindowExec) consumeGroupRows(numGroupRows uint64) (err error) {
+	remainingRowsInGroup := numGroupRows
 	if remainingRowsInGroup == 0 {
 		return nil
 	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
-
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.Ctx(), groupRows)
+	groupRows := windowRows{
+		h:       e.spillHelper,
+		data:    e.data,
+		skip:    -1,
+		offset:  e.consumed,
+		numRows: numGroupRows,
+	}
+	e.consumed += numGroupRows
+	// TODO: Combine these three methods.
+	// The old implementation needs the processor has these three methods
+	// but now it does not have to.
+	err = e.processor.consumeGroupRows(e.Ctx(), groupRows)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i := 0; i < len(e.data); i++ {
+		d := &e.data[i]
+		remained := min(d.remaining, remainingRowsInGroup)
+		if remained == 0 {
+			continue
+		}
+		err = e.spillHelper.loadChk(d)
 		if err != nil {
-			return errors.Trace(err)
+			return err
 		}
-		_, err = e.processor.appendResult2Chunk(e.Ctx(), groupRows, e.resultChunks[i], remained)
+		groupRows.skip = i
+		err = e.processor.appendResult2Chunk(e.Ctx(), groupRows, d.chk, int(remained))
 		if err != nil {
 			return errors.Trace(err)
 		}
+		d.remaining -= remained
+		remainingRowsInGroup -= remained
+		if d.remaining == 0 {
+			e.spillHelper.track(d)
+		}
 		if remainingRowsInGroup == 0 {
 			e.processor.resetPartialResult()
+			e.spillHelper.unrefer(e.data)
 			break
 		}
+		err = e.spillHelper.spillIfNeeded(e.data, -1)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -168,8 +213,17 @@ func (e *WindowExec) fetchChild(ctx context.Context) (eof bool, err error) {
 	if err != nil {
 		return false, err
 	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+	e.accumulated += uint64(numRows)
+	e.data = append(e.data, dataInfo{
+		chk:         resultChk,
+		remaining:   uint64(numRows),
+		accumulated: e.accumulated,
+		src:         childResult,
+		numRows:     uint64(numRows),
+		srcIdx:      -1,
+		resIdx:      -1,
+	})
+	e.spillHelper.track(&e.data[len(e.data)-1])
 
 	e.childResult = childResult
 	return false, nil
@@ -189,10 +243,10 @@ func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
+	consumeGroupRows(ctx sessionctx.Context, rows windowRows) error
 	// appendResult2Chunk appends the final results to chunk.
 	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	appendResult2Chunk(ctx sessionctx.Context, rows windowRows, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -202,30 +256,29 @@ type aggWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows windowRows) error {
 	for i, windowFunc := range p.windowFuncs {
 		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx.GetExprCtx().GetEvalCtx(), rows, p.partialResults[i])
+		err := rows.updatePartialResult(ctx.GetExprCtx().GetEvalCtx(), windowFunc, p.partialResults[i], 0, rows.numRows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, _ windowRows, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx.GetExprCtx().GetEvalCtx(), p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -288,12 +341,12 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (*rowFrameWindowProcessor) consumeGroupRows(_ sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (*rowFrameWindowProcessor) consumeGroupRows(sessionctx.Context, windowRows) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows windowRows, chk *chunk.Chunk, remained int) error {
+	numRows := rows.numRows
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -321,16 +374,14 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx.GetExprCtx().GetEvalCtx(), func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = rows.slide(ctx.GetExprCtx().GetEvalCtx(), slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx.GetExprCtx().GetEvalCtx(), p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -339,24 +390,16 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx.GetExprCtx().GetEvalCtx(), func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = rows.slide(ctx.GetExprCtx().GetEvalCtx(), slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
-				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
-				// whether elements inside deque are out of current window.
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
-				}
-				_, err = windowFunc.UpdatePartialResult(ctx.GetExprCtx().GetEvalCtx(), rows[start:end], p.partialResults[i])
+				err = rows.updatePartialResult(ctx.GetExprCtx().GetEvalCtx(), windowFunc, p.partialResults[i], start, end)
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx.GetExprCtx().GetEvalCtx(), p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -369,7 +412,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -389,16 +432,23 @@ type rangeFrameWindowProcessor struct {
 	expectedCmpResult int64
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows windowRows) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	numRows := rows.numRows
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
 		var res int64
-		var err error
+		row, err := rows.getRow(p.lastStartOffset)
+		if err != nil {
+			return 0, err
+		}
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), p.start.CompareCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), p.start.CompareCols[i], p.start.CalcFuncs[i], row, curRow)
 			if err != nil {
 				return 0, err
 			}
@@ -415,16 +465,23 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows windowRows) (uint64, error) {
+	numRows := rows.numRows
 	if p.end.UnBounded {
 		return numRows, nil
 	}
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
 		var res int64
-		var err error
+		row, err := rows.getRow(p.lastEndOffset)
+		if err != nil {
+			return 0, err
+		}
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), p.end.CalcFuncs[i], p.end.CompareCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx.GetExprCtx().GetEvalCtx(), p.end.CalcFuncs[i], p.end.CompareCols[i], curRow, row)
 			if err != nil {
 				return 0, err
 			}
@@ -441,7 +498,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows windowRows, chk *chunk.Chunk, remained int) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -461,11 +518,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		p.curRowIdx++
 		remained--
@@ -475,16 +532,14 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx.GetExprCtx().GetEvalCtx(), func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = rows.slide(ctx.GetExprCtx().GetEvalCtx(), slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx.GetExprCtx().GetEvalCtx(), p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -493,21 +548,16 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx.GetExprCtx().GetEvalCtx(), func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = rows.slide(ctx.GetExprCtx().GetEvalCtx(), slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
-				}
-				_, err = windowFunc.UpdatePartialResult(ctx.GetExprCtx().GetEvalCtx(), rows[start:end], p.partialResults[i])
+				err = rows.updatePartialResult(ctx.GetExprCtx().GetEvalCtx(), windowFunc, p.partialResults[i], start, end)
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx.GetExprCtx().GetEvalCtx(), p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -520,11 +570,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
-func (*rangeFrameWindowProcessor) consumeGroupRows(_ sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (*rangeFrameWindowProcessor) consumeGroupRows(sessionctx.Context, windowRows) error {
+	return nil
 }
 
 func (p *rangeFrameWindowProcessor) resetPartialResult() {
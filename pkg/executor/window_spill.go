@@ -0,0 +1,335 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/pkg/executor/aggfuncs"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"go.uber.org/zap"
+)
+
+const windowSpillLogInfo = "memory exceeds quota, spill the rows of the window function to disk"
+
+// windowSpillHelper tracks the memory usage of the chunks buffered by a window executor, and spills them to disk
+// when the memory quota is exceeded. The spilled chunks are read back when their rows are accessed or they are
+// returned to the parent executor.
+type windowSpillHelper struct {
+	base           *exec.BaseExecutor
+	numWindowFuncs int
+	copyChk        func(src, dst *chunk.Chunk) error
+	// resultColIdxs are the indexes of the window function results in the result chunks.
+	resultColIdxs []int
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+	action      *windowSpillAction
+	// needSpill is set by the spill action. Since the chunks are only accessed by the goroutine of the executor,
+	// they are spilled by the executor itself at its next check point.
+	needSpill atomic.Bool
+	// nothingToSpill is set if the last spill finds no chunk to spill, e.g. all the chunks are referred by the
+	// partial results, then the spill action falls back until the chunks are unreferred.
+	nothingToSpill atomic.Bool
+
+	// srcInDisk stores the chunks fetched from the child, resInDisk stores the window function results of them.
+	srcInDisk *chunk.DataInDiskByChunks
+	resInDisk *chunk.DataInDiskByChunks
+
+	rowBuf []chunk.Row
+}
+
+func newWindowSpillHelper(base *exec.BaseExecutor, numWindowFuncs int, copyChk func(src, dst *chunk.Chunk) error) *windowSpillHelper {
+	sessVars := base.Ctx().GetSessionVars()
+	h := &windowSpillHelper{
+		base:           base,
+		numWindowFuncs: numWindowFuncs,
+		copyChk:        copyChk,
+	}
+	numCols := len(base.RetFieldTypes())
+	for i := numCols - numWindowFuncs; i < numCols; i++ {
+		h.resultColIdxs = append(h.resultColIdxs, i)
+	}
+	h.memTracker = memory.NewTracker(base.ID(), -1)
+	h.memTracker.AttachTo(sessVars.StmtCtx.MemTracker)
+	// The chunks without any column can't be spilled, since they only record the number of rows.
+	if variable.EnableTmpStorageOnOOM.Load() && sessVars.EnableWindowSpill && len(exec.RetTypes(base.Children(0))) > 0 {
+		h.diskTracker = disk.NewTracker(base.ID(), -1)
+		h.diskTracker.AttachTo(sessVars.StmtCtx.DiskTracker)
+		h.action = &windowSpillAction{helper: h}
+		sessVars.MemTracker.FallbackOldAndSetNewAction(h.action)
+	}
+	return h
+}
+
+// track updates the memory usage of the chunks of d.
+func (h *windowSpillHelper) track(d *dataInfo) {
+	var usage int64
+	if d.src != nil {
+		usage += d.src.MemoryUsage()
+	}
+	// The window function results are counted once all of them are appended.
+	if d.chk != nil && d.remaining == 0 {
+		usage += d.chk.Prune(h.resultColIdxs).MemoryUsage()
+	}
+	h.memTracker.Consume(usage - d.memUsage)
+	d.memUsage = usage
+}
+
+// release is called when the result chunk of d is returned to the parent executor.
+func (h *windowSpillHelper) release(d *dataInfo) {
+	h.memTracker.Consume(-d.memUsage)
+	d.memUsage = 0
+}
+
+// loadSrc reads back the chunk fetched from the child if it's spilled.
+func (h *windowSpillHelper) loadSrc(d *dataInfo) (err error) {
+	if d.src != nil {
+		return nil
+	}
+	d.src, err = h.srcInDisk.GetChunk(d.srcIdx)
+	if err != nil {
+		return err
+	}
+	h.track(d)
+	return nil
+}
+
+// loadChk rebuilds the result chunk of d if it's spilled.
+func (h *windowSpillHelper) loadChk(d *dataInfo) error {
+	if d.chk != nil {
+		return nil
+	}
+	if err := h.loadSrc(d); err != nil {
+		return err
+	}
+	chk := h.base.AllocPool.Alloc(h.base.RetFieldTypes(), 0, int(d.numRows))
+	if err := h.copyChk(d.src, chk); err != nil {
+		return err
+	}
+	if d.resIdx >= 0 {
+		res, err := h.resInDisk.GetChunk(d.resIdx)
+		if err != nil {
+			return err
+		}
+		for i, colIdx := range h.resultColIdxs {
+			if err = chk.MakeRefTo(colIdx, res, i); err != nil {
+				return err
+			}
+		}
+	}
+	d.chk = chk
+	h.track(d)
+	return nil
+}
+
+// unrefer is called when the partial results of the window functions are reset, then the chunks in data can be
+// spilled again.
+func (h *windowSpillHelper) unrefer(data []dataInfo) {
+	for i := range data {
+		data[i].referred = false
+	}
+	h.nothingToSpill.Store(false)
+}
+
+// spillIfNeeded spills the chunks in data if the spill action is triggered. The chunk at skip is being filled with
+// the window function results, so it's kept in memory, and so are the chunks whose results are partially appended
+// and the chunks whose rows are referred by the partial results.
+func (h *windowSpillHelper) spillIfNeeded(data []dataInfo, skip int) error {
+	if !h.needSpill.Load() {
+		return nil
+	}
+	defer h.needSpill.Store(false)
+	spilled := false
+	for i := range data {
+		d := &data[i]
+		if i == skip || d.src == nil || d.referred || (d.remaining != 0 && d.remaining != d.numRows) {
+			continue
+		}
+		if err := h.spillChunk(d); err != nil {
+			return err
+		}
+		spilled = true
+	}
+	h.nothingToSpill.Store(!spilled)
+	return nil
+}
+
+func (h *windowSpillHelper) spillChunk(d *dataInfo) (err error) {
+	if d.srcIdx < 0 {
+		if h.srcInDisk == nil {
+			h.srcInDisk = chunk.NewDataInDiskByChunks(exec.RetTypes(h.base.Children(0)))
+			h.srcInDisk.GetDiskTracker().AttachTo(h.diskTracker)
+		}
+		if err = h.srcInDisk.Add(d.src); err != nil {
+			return err
+		}
+		d.srcIdx = h.srcInDisk.NumChunks() - 1
+	}
+	if d.remaining == 0 && d.chk != nil && d.resIdx < 0 {
+		if h.resInDisk == nil {
+			fieldTypes := h.base.RetFieldTypes()
+			h.resInDisk = chunk.NewDataInDiskByChunks(fieldTypes[len(fieldTypes)-h.numWindowFuncs:])
+			h.resInDisk.GetDiskTracker().AttachTo(h.diskTracker)
+		}
+		if err = h.resInDisk.Add(d.chk.Prune(h.resultColIdxs)); err != nil {
+			return err
+		}
+		d.resIdx = h.resInDisk.NumChunks() - 1
+	}
+	d.src, d.chk = nil, nil
+	h.track(d)
+	return nil
+}
+
+func (h *windowSpillHelper) close() {
+	if h.action != nil {
+		h.action.SetFinished()
+	}
+	if h.srcInDisk != nil {
+		h.srcInDisk.Close()
+	}
+	if h.resInDisk != nil {
+		h.resInDisk.Close()
+	}
+	h.memTracker.ReplaceBytesUsed(0)
+}
+
+// windowRows is the rows of the partition processed by a window executor. The rows are addressed by their offsets
+// in the partition instead of a slice, since the chunks containing them may be spilled to disk.
+type windowRows struct {
+	h    *windowSpillHelper
+	data []dataInfo
+	// skip is the index of the chunk being filled with the window function results.
+	skip int
+	// offset is the offset of the first row of the partition in the output of the child.
+	offset  uint64
+	numRows uint64
+}
+
+// locate returns the index of the chunk containing the i-th row of the partition.
+func (r windowRows) locate(i uint64) int {
+	return sort.Search(len(r.data), func(j int) bool {
+		return r.data[j].accumulated > r.offset+i
+	})
+}
+
+// getRow returns the i-th row of the partition, the chunk containing it is read back if it's spilled.
+func (r windowRows) getRow(i uint64) (chunk.Row, error) {
+	d := &r.data[r.locate(i)]
+	if err := r.h.loadSrc(d); err != nil {
+		return chunk.Row{}, err
+	}
+	return d.src.GetRow(int(r.offset + i - (d.accumulated - d.numRows))), nil
+}
+
+// getLoadedRow is like getRow, but the chunk containing the row must be read back by loadRows in advance.
+func (r windowRows) getLoadedRow(i uint64) chunk.Row {
+	d := &r.data[r.locate(i)]
+	return d.src.GetRow(int(r.offset + i - (d.accumulated - d.numRows)))
+}
+
+// loadRows reads back the chunks containing the rows in [start, end) if they are spilled.
+func (r windowRows) loadRows(start, end uint64) error {
+	for i := r.locate(start); start < end; i++ {
+		d := &r.data[i]
+		if err := r.h.loadSrc(d); err != nil {
+			return err
+		}
+		start = d.accumulated - r.offset
+	}
+	return nil
+}
+
+// updatePartialResult updates the partial result of windowFunc with the rows in [start, end). The rows are passed
+// chunk by chunk, so the spilled rows needn't be read back at once.
+func (r windowRows) updatePartialResult(ctx aggfuncs.AggFuncUpdateContext, windowFunc aggfuncs.AggFunc, pr aggfuncs.PartialResult, start, end uint64) error {
+	_, referRows := windowFunc.(aggfuncs.RowsReferringAggFunc)
+	for i := r.locate(start); start < end; i++ {
+		d := &r.data[i]
+		if err := r.h.loadSrc(d); err != nil {
+			return err
+		}
+		d.referred = d.referred || referRows
+		chkStart := d.accumulated - d.numRows
+		chkEnd := min(end, d.accumulated-r.offset)
+		r.h.rowBuf = r.h.rowBuf[:0]
+		for j := r.offset + start; j < r.offset+chkEnd; j++ {
+			r.h.rowBuf = append(r.h.rowBuf, d.src.GetRow(int(j-chkStart)))
+		}
+		// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
+		// whether elements inside deque are out of current window.
+		if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+			// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
+			minMaxSlidingWindowAggFunc.SetWindowStart(start)
+		}
+		if _, err := windowFunc.UpdatePartialResult(ctx, r.h.rowBuf, pr); err != nil {
+			return err
+		}
+		start = chkEnd
+		if err := r.h.spillIfNeeded(r.data, r.skip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slide slides the frame of windowFunc, the rows leaving and entering the frame are read back in advance.
+func (r windowRows) slide(ctx aggfuncs.AggFuncUpdateContext, windowFunc aggfuncs.SlidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd uint64, pr aggfuncs.PartialResult) error {
+	if err := r.loadRows(lastStart, lastStart+shiftStart); err != nil {
+		return err
+	}
+	if err := r.loadRows(lastEnd, lastEnd+shiftEnd); err != nil {
+		return err
+	}
+	return windowFunc.Slide(ctx, r.getLoadedRow, lastStart, lastEnd, shiftStart, shiftEnd, pr)
+}
+
+// windowSpillAction is the action to spill the chunks buffered by a window executor when the memory quota is
+// exceeded.
+type windowSpillAction struct {
+	memory.BaseOOMAction
+	helper *windowSpillHelper
+}
+
+// Action implements memory.ActionOnExceed.
+func (a *windowSpillAction) Action(t *memory.Tracker) {
+	h := a.helper
+	if h.needSpill.Load() {
+		// The executor will spill the chunks soon.
+		return
+	}
+	if !h.nothingToSpill.Load() && memory.HasEnoughDataToSpill(h.memTracker, t) && h.needSpill.CompareAndSwap(false, true) {
+		logutil.BgLogger().Info(windowSpillLogInfo,
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		memory.QueryForceDisk.Add(1)
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority implements memory.ActionOnExceed.
+func (*windowSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
@@ -17,10 +17,12 @@ package executor_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWindowFunctions(t *testing.T) {
@@ -470,3 +472,65 @@ func TestIssue45964And46050(t *testing.T) {
 	testReturnColumnNullableAttribute(tk, "cume_dist()", false)
 	testReturnColumnNullableAttribute(tk, "percent_rank()", false)
 }
+
+func TestWindowSpill(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c varchar(100), primary key (a, b) clustered)")
+	tk.MustExec("set @@cte_max_recursion_depth = 10000")
+	tk.MustExec("insert into t with recursive s(n) as (select 1 union all select n + 1 from s where n < 5000) select 1, n, repeat('x', 100) from s")
+	tk.MustExec("insert into t values (2, 1, 'y'), (2, 2, 'z')")
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	queries := []struct {
+		sql string
+		// bounded indicates the frames are bounded, so the pipelined window executor needn't keep the whole partition.
+		bounded bool
+		// referRows indicates the partial results refer to the rows of the whole partition, so they can't be spilled.
+		referRows bool
+	}{
+		{"select a, b, c, row_number() over w, ntile(7) over w from t window w as (partition by a order by b)", false, false},
+		{"select a, b, c, count(*) over (partition by a) from t", false, false},
+		{"select a, b, c, lead(c, 10) over (partition by a order by b), rank() over (partition by a order by b) from t", false, true},
+		{"select a, b, c, lag(c) over (partition by a order by b), cume_dist() over (partition by a order by b) from t", false, true},
+		{"select a, b, c, sum(b) over (partition by a order by b rows between 3 preceding and 2 following) from t", true, false},
+		{"select a, b, c, max(b) over (partition by a order by b rows between current row and 100 following) from t", true, false},
+		{"select a, b, c, avg(b) over (partition by a order by b range between 10 preceding and 5 following) from t", true, false},
+	}
+	for _, pipelined := range []bool{false, true} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %v", pipelined))
+		for _, query := range queries {
+			tk.MustExec("set @@tidb_mem_quota_query = default")
+			expected := tk.MustQuery(query.sql).Rows()
+			tk.MustExec("set @@tidb_mem_quota_query = 200000")
+			tk.MustQuery(query.sql).Check(expected)
+			if query.referRows {
+				// The referred rows are kept in memory, so the query is cancelled instead of under-counting them.
+				tk.MustExec("set global tidb_mem_oom_action = 'CANCEL'")
+				require.ErrorContains(t, tk.QueryToErr(query.sql), "Your query has been cancelled due to exceeding the allowed memory limit")
+				tk.MustExec("set global tidb_mem_oom_action = default")
+				continue
+			}
+			require.Equal(t, !pipelined || !query.bounded, windowSpilled(t, tk, query.sql), query.sql)
+
+			tk.MustExec("set @@tidb_enable_window_spill = 0")
+			require.False(t, windowSpilled(t, tk, query.sql), query.sql)
+			tk.MustExec("set @@tidb_enable_window_spill = default")
+		}
+	}
+}
+
+// windowSpilled checks whether any window executor of the query spills to disk.
+func windowSpilled(t *testing.T, tk *testkit.TestKit, query string) bool {
+	spilled := false
+	for _, row := range tk.MustQuery("explain analyze " + query).Rows() {
+		require.NotContains(t, row[0], "Sort")
+		if strings.Contains(row[0].(string), "Window") && row[8] != "N/A" && row[8] != "0 Bytes" {
+			spilled = true
+		}
+	}
+	return spilled
+}
@@ -56,11 +56,16 @@ var (
 func updateRecord(
 	ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, modified []bool,
 	t table.Table,
-	onDup bool, _ *memory.Tracker, fkChecks []*FKCheckExec, fkCascades []*FKCascadeExec,
+	onDup bool, _ *memory.Tracker, fkChecks []*FKCheckExec, fkCascades []*FKCascadeExec, triggers *TriggerExec,
 ) (bool, error) {
 	r, ctx := tracing.StartRegionEx(ctx, "executor.updateRecord")
 	defer r.End()
 
+	// The BEFORE UPDATE triggers may change the new row.
+	if err := triggers.fire(ctx, model.TriggerTimingBefore, model.TriggerEventUpdate, oldData, newData); err != nil {
+		return false, err
+	}
+
 	sc := sctx.GetSessionVars().StmtCtx
 	changed, handleChanged := false, false
 	// onUpdateSpecified is for "UPDATE SET ts_field = old_value", the
@@ -137,8 +142,11 @@ func updateRecord(
 		if sctx.GetSessionVars().LockUnchangedKeys {
 			keySet |= lockUniqueKeys
 		}
-		_, err := addUnchangedKeysForLockByRow(sctx, t, h, oldData, keySet)
-		return false, err
+		if _, err := addUnchangedKeysForLockByRow(sctx, t, h, oldData, keySet); err != nil {
+			return false, err
+		}
+		// Like MySQL, the AFTER UPDATE triggers are fired even if the row isn't changed.
+		return false, triggers.fire(ctx, model.TriggerTimingAfter, model.TriggerEventUpdate, oldData, newData)
 	}
 
 	// Fill values into on-update-now fields, only if they are really changed.
@@ -228,6 +236,9 @@ func updateRecord(
 	sc.AddUpdatedRows(1)
 	sc.AddCopiedRows(1)
 
+	if err := triggers.fire(ctx, model.TriggerTimingAfter, model.TriggerEventUpdate, oldData, newData); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -909,6 +909,82 @@ var funcs = map[string]functionClass{
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
+	// spatial functions
+	ast.Point:                        &geomConstructorFunctionClass{baseFunctionClass{ast.Point, 2, 2}, types.GeometryTypePoint},
+	ast.LineString:                   &geomConstructorFunctionClass{baseFunctionClass{ast.LineString, 1, -1}, types.GeometryTypeLineString},
+	ast.Polygon:                      &geomConstructorFunctionClass{baseFunctionClass{ast.Polygon, 1, -1}, types.GeometryTypePolygon},
+	ast.MultiPoint:                   &geomConstructorFunctionClass{baseFunctionClass{ast.MultiPoint, 1, -1}, types.GeometryTypeMultiPoint},
+	ast.MultiLineString:              &geomConstructorFunctionClass{baseFunctionClass{ast.MultiLineString, 1, -1}, types.GeometryTypeMultiLineString},
+	ast.MultiPolygon:                 &geomConstructorFunctionClass{baseFunctionClass{ast.MultiPolygon, 1, -1}, types.GeometryTypeMultiPolygon},
+	ast.GeomCollection:               &geomConstructorFunctionClass{baseFunctionClass{ast.GeomCollection, 0, -1}, types.GeometryTypeGeometryCollection},
+	ast.GeometryCollection:           &geomConstructorFunctionClass{baseFunctionClass{ast.GeometryCollection, 0, -1}, types.GeometryTypeGeometryCollection},
+	ast.STGeomFromText:               &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomFromText, 1, 2}, types.GeometryTypeGeometry, false},
+	ast.STGeometryFromText:           &geomFromTextFunctionClass{baseFunctionClass{ast.STGeometryFromText, 1, 2}, types.GeometryTypeGeometry, false},
+	ast.STPointFromText:              &geomFromTextFunctionClass{baseFunctionClass{ast.STPointFromText, 1, 2}, types.GeometryTypePoint, false},
+	ast.STLineFromText:               &geomFromTextFunctionClass{baseFunctionClass{ast.STLineFromText, 1, 2}, types.GeometryTypeLineString, false},
+	ast.STLineStringFromText:         &geomFromTextFunctionClass{baseFunctionClass{ast.STLineStringFromText, 1, 2}, types.GeometryTypeLineString, false},
+	ast.STPolyFromText:               &geomFromTextFunctionClass{baseFunctionClass{ast.STPolyFromText, 1, 2}, types.GeometryTypePolygon, false},
+	ast.STPolygonFromText:            &geomFromTextFunctionClass{baseFunctionClass{ast.STPolygonFromText, 1, 2}, types.GeometryTypePolygon, false},
+	ast.STMPointFromText:             &geomFromTextFunctionClass{baseFunctionClass{ast.STMPointFromText, 1, 2}, types.GeometryTypeMultiPoint, false},
+	ast.STMultiPointFromText:         &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiPointFromText, 1, 2}, types.GeometryTypeMultiPoint, false},
+	ast.STMLineFromText:              &geomFromTextFunctionClass{baseFunctionClass{ast.STMLineFromText, 1, 2}, types.GeometryTypeMultiLineString, false},
+	ast.STMultiLineStringFromText:    &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiLineStringFromText, 1, 2}, types.GeometryTypeMultiLineString, false},
+	ast.STMPolyFromText:              &geomFromTextFunctionClass{baseFunctionClass{ast.STMPolyFromText, 1, 2}, types.GeometryTypeMultiPolygon, false},
+	ast.STMultiPolygonFromText:       &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiPolygonFromText, 1, 2}, types.GeometryTypeMultiPolygon, false},
+	ast.STGeomCollFromText:           &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomCollFromText, 1, 2}, types.GeometryTypeGeometryCollection, false},
+	ast.STGeomCollFromTxt:            &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomCollFromTxt, 1, 2}, types.GeometryTypeGeometryCollection, false},
+	ast.STGeometryCollectionFromText: &geomFromTextFunctionClass{baseFunctionClass{ast.STGeometryCollectionFromText, 1, 2}, types.GeometryTypeGeometryCollection, false},
+	ast.STGeomFromWKB:                &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomFromWKB, 1, 2}, types.GeometryTypeGeometry, true},
+	ast.STGeometryFromWKB:            &geomFromTextFunctionClass{baseFunctionClass{ast.STGeometryFromWKB, 1, 2}, types.GeometryTypeGeometry, true},
+	ast.STPointFromWKB:               &geomFromTextFunctionClass{baseFunctionClass{ast.STPointFromWKB, 1, 2}, types.GeometryTypePoint, true},
+	ast.STLineFromWKB:                &geomFromTextFunctionClass{baseFunctionClass{ast.STLineFromWKB, 1, 2}, types.GeometryTypeLineString, true},
+	ast.STLineStringFromWKB:          &geomFromTextFunctionClass{baseFunctionClass{ast.STLineStringFromWKB, 1, 2}, types.GeometryTypeLineString, true},
+	ast.STPolyFromWKB:                &geomFromTextFunctionClass{baseFunctionClass{ast.STPolyFromWKB, 1, 2}, types.GeometryTypePolygon, true},
+	ast.STPolygonFromWKB:             &geomFromTextFunctionClass{baseFunctionClass{ast.STPolygonFromWKB, 1, 2}, types.GeometryTypePolygon, true},
+	ast.STMPointFromWKB:              &geomFromTextFunctionClass{baseFunctionClass{ast.STMPointFromWKB, 1, 2}, types.GeometryTypeMultiPoint, true},
+	ast.STMultiPointFromWKB:          &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiPointFromWKB, 1, 2}, types.GeometryTypeMultiPoint, true},
+	ast.STMLineFromWKB:               &geomFromTextFunctionClass{baseFunctionClass{ast.STMLineFromWKB, 1, 2}, types.GeometryTypeMultiLineString, true},
+	ast.STMultiLineStringFromWKB:     &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiLineStringFromWKB, 1, 2}, types.GeometryTypeMultiLineString, true},
+	ast.STMPolyFromWKB:               &geomFromTextFunctionClass{baseFunctionClass{ast.STMPolyFromWKB, 1, 2}, types.GeometryTypeMultiPolygon, true},
+	ast.STMultiPolygonFromWKB:        &geomFromTextFunctionClass{baseFunctionClass{ast.STMultiPolygonFromWKB, 1, 2}, types.GeometryTypeMultiPolygon, true},
+	ast.STGeomCollFromWKB:            &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomCollFromWKB, 1, 2}, types.GeometryTypeGeometryCollection, true},
+	ast.STGeometryCollectionFromWKB:  &geomFromTextFunctionClass{baseFunctionClass{ast.STGeometryCollectionFromWKB, 1, 2}, types.GeometryTypeGeometryCollection, true},
+	ast.STGeomFromGeoJSON:            &geomFromGeoJSONFunctionClass{baseFunctionClass{ast.STGeomFromGeoJSON, 1, 3}},
+	ast.STAsText:                     &geomAsTextFunctionClass{baseFunctionClass{ast.STAsText, 1, 1}},
+	ast.STAsWKT:                      &geomAsTextFunctionClass{baseFunctionClass{ast.STAsWKT, 1, 1}},
+	ast.STAsBinary:                   &geomAsBinaryFunctionClass{baseFunctionClass{ast.STAsBinary, 1, 1}},
+	ast.STAsWKB:                      &geomAsBinaryFunctionClass{baseFunctionClass{ast.STAsWKB, 1, 1}},
+	ast.STAsGeoJSON:                  &geomAsGeoJSONFunctionClass{baseFunctionClass{ast.STAsGeoJSON, 1, 3}},
+	ast.STSRID:                       &geomSRIDFunctionClass{baseFunctionClass{ast.STSRID, 1, 2}},
+	ast.STX:                          &geomCoordinateFunctionClass{baseFunctionClass{ast.STX, 1, 1}, geomCoordinateX},
+	ast.STY:                          &geomCoordinateFunctionClass{baseFunctionClass{ast.STY, 1, 1}, geomCoordinateY},
+	ast.STLatitude:                   &geomCoordinateFunctionClass{baseFunctionClass{ast.STLatitude, 1, 1}, geomCoordinateLatitude},
+	ast.STLongitude:                  &geomCoordinateFunctionClass{baseFunctionClass{ast.STLongitude, 1, 1}, geomCoordinateLongitude},
+	ast.STGeometryType:               &geomTypeFunctionClass{baseFunctionClass{ast.STGeometryType, 1, 1}},
+	ast.STIsEmpty:                    &geomIsEmptyFunctionClass{baseFunctionClass{ast.STIsEmpty, 1, 1}},
+	ast.STContains:                   &geomRelationFunctionClass{baseFunctionClass{ast.STContains, 2, 2}, types.Geometry.Contains},
+	ast.STWithin:                     &geomRelationFunctionClass{baseFunctionClass{ast.STWithin, 2, 2}, types.Geometry.Within},
+	ast.STIntersects:                 &geomRelationFunctionClass{baseFunctionClass{ast.STIntersects, 2, 2}, types.Geometry.Intersects},
+	ast.STDisjoint:                   &geomRelationFunctionClass{baseFunctionClass{ast.STDisjoint, 2, 2}, types.Geometry.Disjoint},
+	ast.STEquals:                     &geomRelationFunctionClass{baseFunctionClass{ast.STEquals, 2, 2}, types.Geometry.Equals},
+	ast.STDistance:                   &geomDistanceFunctionClass{baseFunctionClass{ast.STDistance, 2, 2}},
+	ast.STDistanceSphere:             &geomDistanceSphereFunctionClass{baseFunctionClass{ast.STDistanceSphere, 2, 3}},
+	ast.STArea:                       &geomMeasureFunctionClass{baseFunctionClass{ast.STArea, 1, 1}, types.Geometry.Area},
+	ast.STLength:                     &geomMeasureFunctionClass{baseFunctionClass{ast.STLength, 1, 1}, types.Geometry.Length},
+
+	// vector functions.
+	ast.VecDims:                 &vecDimsFunctionClass{baseFunctionClass{ast.VecDims, 1, 1}},
+	ast.VecL1Distance:           &vecDistanceFunctionClass{baseFunctionClass{ast.VecL1Distance, 2, 2}},
+	ast.VecL2Distance:           &vecDistanceFunctionClass{baseFunctionClass{ast.VecL2Distance, 2, 2}},
+	ast.VecCosineDistance:       &vecDistanceFunctionClass{baseFunctionClass{ast.VecCosineDistance, 2, 2}},
+	ast.VecNegativeInnerProduct: &vecDistanceFunctionClass{baseFunctionClass{ast.VecNegativeInnerProduct, 2, 2}},
+	ast.VecL2Norm:               &vecL2NormFunctionClass{baseFunctionClass{ast.VecL2Norm, 1, 1}},
+	ast.VecFromText:             &vecFromTextFunctionClass{baseFunctionClass{ast.VecFromText, 1, 1}},
+	ast.VecAsText:               &vecAsTextFunctionClass{baseFunctionClass{ast.VecAsText, 1, 1}},
+
+	// fulltext function.
+	ast.FTSMatchAgainst: &matchAgainstFunctionClass{baseFunctionClass{ast.FTSMatchAgainst, MatchAgainstArgs + 1, -1}},
+
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
 	// This function is used to show tidb-server version info.
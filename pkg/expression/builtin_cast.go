@@ -2138,6 +2138,13 @@ func BuildCastFunctionWithCheck(ctx BuildContext, expr Expression, tp *types.Fie
 	if !mysql.HasNotNullFlag(argType.GetFlag()) {
 		tp.DelFlag(mysql.NotNullFlag)
 	}
+	if tp.GetType() == mysql.TypeTiDBVectorFloat32 {
+		// The vectors are converted from the text form, their dimension is checked when they're written to the column.
+		if argType.GetType() == mysql.TypeTiDBVectorFloat32 {
+			return expr, nil
+		}
+		return NewFunction(ctx, ast.VecFromText, tp, expr)
+	}
 	expr = TryPushCastIntoControlFunctionForHybridType(ctx, expr, tp)
 	var fc functionClass
 	switch tp.EvalType() {
@@ -2270,6 +2277,10 @@ func WrapWithCastAsDecimal(ctx BuildContext, expr Expression) Expression {
 // not type string, otherwise, returns `expr` directly.
 func WrapWithCastAsString(ctx BuildContext, expr Expression) Expression {
 	exprTp := expr.GetType()
+	if exprTp.GetType() == mysql.TypeTiDBVectorFloat32 {
+		// The vector is stored in the binary format, it's used as a string in its text form.
+		return NewFunctionInternal(ctx, ast.VecAsText, types.NewFieldType(mysql.TypeVarString), expr)
+	}
 	if exprTp.EvalType() == types.ETString {
 		return expr
 	}
@@ -0,0 +1,172 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/expression/contextopt"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
+)
+
+var (
+	_ functionClass = &matchAgainstFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMatchAgainstSig{}
+)
+
+// MatchAgainstArgs is the number of the leading constant arguments of `match_against`, they are
+// the table ID, the FULLTEXT index ID, the search string, whether it's in boolean mode, the number
+// of rows of the table and the number of rows containing each term of the search string, which is
+// encoded by fulltext.Stats.EncodeDocFreqs. The following arguments are the columns of the FULLTEXT index.
+const MatchAgainstArgs = 6
+
+type matchAgainstFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *matchAgainstFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETInt, types.ETInt, types.ETString, types.ETInt, types.ETInt, types.ETString)
+	for range args[MatchAgainstArgs:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinMatchAgainstSig{baseBuiltinFunc: bf, cache: &matchAgainstCache{}}
+	return sig, nil
+}
+
+// matchAgainstCache caches the parsed query and the statistics of the FULLTEXT index.
+type matchAgainstCache struct {
+	sync.Mutex
+	parser model.FullTextParserType
+	query  *fulltext.Query
+	stats  *fulltext.Stats
+}
+
+// builtinMatchAgainstSig evaluates `MATCH (col1, col2, ...) AGAINST (expr [search_modifier])`
+// and returns the relevance of the row, see https://dev.mysql.com/doc/refman/8.0/en/fulltext-search.html.
+// The relevance is calculated by the statistics of the FULLTEXT index collected by the planner.
+type builtinMatchAgainstSig struct {
+	baseBuiltinFunc
+	contextopt.InfoSchemaPropReader
+
+	cache *matchAgainstCache
+}
+
+func (b *builtinMatchAgainstSig) RequiredOptionalEvalProps() OptionalEvalPropKeySet {
+	return b.InfoSchemaPropReader.RequiredOptionalEvalProps()
+}
+
+func (b *builtinMatchAgainstSig) Clone() builtinFunc {
+	newSig := &builtinMatchAgainstSig{cache: &matchAgainstCache{}}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinMatchAgainstSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	query, stats, parser, err := b.prepare(ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	texts := make([]string, 0, len(b.args)-MatchAgainstArgs)
+	for _, arg := range b.args[MatchAgainstArgs:] {
+		text, isNull, err := arg.EvalString(ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull {
+			texts = append(texts, text)
+		}
+	}
+	_, score := query.Match(fulltext.NewDocument(parser, texts...), stats)
+	return score, false, nil
+}
+
+// prepare parses the search string and decodes the statistics of the FULLTEXT index if they are not cached.
+func (b *builtinMatchAgainstSig) prepare(ctx EvalContext, row chunk.Row) (*fulltext.Query, *fulltext.Stats, model.FullTextParserType, error) {
+	b.cache.Lock()
+	defer b.cache.Unlock()
+	if b.cache.stats != nil {
+		return b.cache.query, b.cache.stats, b.cache.parser, nil
+	}
+
+	tableID, _, err := b.args[0].EvalInt(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	indexID, _, err := b.args[1].EvalInt(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	against, _, err := b.args[2].EvalString(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	booleanMode, _, err := b.args[3].EvalInt(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	docCount, _, err := b.args[4].EvalInt(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	docFreqs, _, err := b.args[5].EvalString(ctx, row)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	is, err := b.GetSessionInfoSchema(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	tblInfo, ok := is.TableInfoByID(tableID)
+	if !ok {
+		return nil, nil, "", errors.Errorf("table %d not found", tableID)
+	}
+	var idxInfo *model.IndexInfo
+	for _, idx := range tblInfo.Indices {
+		if idx.ID == indexID {
+			idxInfo = idx
+			break
+		}
+	}
+	if idxInfo == nil || !idxInfo.IsFullText() {
+		return nil, nil, "", errors.Errorf("FULLTEXT index %d not found in table %s", indexID, tblInfo.Name.O)
+	}
+	parser := idxInfo.FullTextInfo.ParserType
+	query, err := fulltext.ParseQuery(parser, against, booleanMode != 0)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	stats, err := fulltext.DecodeStats(docCount, docFreqs, query.Terms())
+	if err != nil {
+		return nil, nil, "", err
+	}
+	b.cache.parser, b.cache.query, b.cache.stats = parser, query, stats
+	return query, stats, parser, nil
+}
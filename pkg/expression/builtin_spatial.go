@@ -0,0 +1,927 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/hack"
+)
+
+// The spatial functions have no pushdown signatures, so they are always evaluated in TiDB.
+
+var (
+	_ functionClass = &geomFromTextFunctionClass{}
+	_ functionClass = &geomFromGeoJSONFunctionClass{}
+	_ functionClass = &geomConstructorFunctionClass{}
+	_ functionClass = &geomAsTextFunctionClass{}
+	_ functionClass = &geomAsBinaryFunctionClass{}
+	_ functionClass = &geomAsGeoJSONFunctionClass{}
+	_ functionClass = &geomSRIDFunctionClass{}
+	_ functionClass = &geomCoordinateFunctionClass{}
+	_ functionClass = &geomTypeFunctionClass{}
+	_ functionClass = &geomIsEmptyFunctionClass{}
+	_ functionClass = &geomRelationFunctionClass{}
+	_ functionClass = &geomDistanceFunctionClass{}
+	_ functionClass = &geomDistanceSphereFunctionClass{}
+	_ functionClass = &geomMeasureFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinGeomFromTextSig{}
+	_ builtinFunc = &builtinGeomFromGeoJSONSig{}
+	_ builtinFunc = &builtinPointSig{}
+	_ builtinFunc = &builtinGeomConstructorSig{}
+	_ builtinFunc = &builtinGeomAsTextSig{}
+	_ builtinFunc = &builtinGeomAsBinarySig{}
+	_ builtinFunc = &builtinGeomAsGeoJSONSig{}
+	_ builtinFunc = &builtinGeomSRIDSig{}
+	_ builtinFunc = &builtinGeomSetSRIDSig{}
+	_ builtinFunc = &builtinGeomCoordinateSig{}
+	_ builtinFunc = &builtinGeomTypeSig{}
+	_ builtinFunc = &builtinGeomIsEmptySig{}
+	_ builtinFunc = &builtinGeomRelationSig{}
+	_ builtinFunc = &builtinGeomDistanceSig{}
+	_ builtinFunc = &builtinGeomDistanceSphereSig{}
+	_ builtinFunc = &builtinGeomMeasureSig{}
+)
+
+// setGeometryRetType sets the return type of a function which returns a geometry of the type.
+func setGeometryRetType(bf *baseBuiltinFunc, geomTp types.GeometryType) {
+	bf.tp.SetType(mysql.TypeGeometry)
+	bf.tp.SetGeometryType(geomTp)
+	bf.tp.SetFlen(mysql.MaxBlobWidth)
+	bf.tp.SetDecimal(0)
+	types.SetBinChsClnFlag(bf.tp)
+}
+
+// evalGeometry evaluates an argument which is a geometry in the internal format.
+func evalGeometry(ctx EvalContext, arg Expression, row chunk.Row, funcName string) (types.Geometry, bool, error) {
+	s, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return types.Geometry{}, isNull, err
+	}
+	g, err := types.ParseGeometry(hack.Slice(s))
+	if err != nil {
+		return types.Geometry{}, false, types.ErrGISInvalidData.GenWithStackByArgs(funcName)
+	}
+	return g, false, nil
+}
+
+// evalGeometryPair evaluates the two geometry arguments of a binary function, which must be in the same SRS.
+func evalGeometryPair(ctx EvalContext, args []Expression, row chunk.Row, funcName string) (g1, g2 types.Geometry, isNull bool, err error) {
+	g1, isNull, err = evalGeometry(ctx, args[0], row, funcName)
+	if isNull || err != nil {
+		return g1, g2, isNull, err
+	}
+	g2, isNull, err = evalGeometry(ctx, args[1], row, funcName)
+	if isNull || err != nil {
+		return g1, g2, isNull, err
+	}
+	if g1.SRID != g2.SRID {
+		return g1, g2, false, types.ErrGISDifferentSRIDs.GenWithStackByArgs(funcName, g1.SRID, g2.SRID)
+	}
+	return g1, g2, false, nil
+}
+
+// evalSRID evaluates an argument which is a SRID.
+func evalSRID(ctx EvalContext, arg Expression, row chunk.Row) (uint32, bool, error) {
+	srid, isNull, err := arg.EvalInt(ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if srid < 0 || srid > math.MaxUint32 {
+		return 0, false, types.ErrSRSNotFound.GenWithStackByArgs(srid)
+	}
+	return uint32(srid), false, nil
+}
+
+type geomFromTextFunctionClass struct {
+	baseFunctionClass
+
+	// geomTp is the required type of the geometry, any type is accepted if it's GeometryTypeGeometry.
+	geomTp  types.GeometryType
+	fromWKB bool
+}
+
+func (c *geomFromTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString}
+	if len(args) > 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf, c.geomTp)
+	return &builtinGeomFromTextSig{bf, c.geomTp, c.fromWKB, c.funcName}, nil
+}
+
+// builtinGeomFromTextSig constructs a geometry from the WKT or the WKB, like ST_GeomFromText and ST_GeomFromWKB.
+type builtinGeomFromTextSig struct {
+	baseBuiltinFunc
+
+	geomTp  types.GeometryType
+	fromWKB bool
+	name    string
+}
+
+func (b *builtinGeomFromTextSig) Clone() builtinFunc {
+	newSig := &builtinGeomFromTextSig{geomTp: b.geomTp, fromWKB: b.fromWKB, name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomFromTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	s, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	var srid uint32
+	if len(b.args) > 1 {
+		if srid, isNull, err = evalSRID(ctx, b.args[1], row); isNull || err != nil {
+			return "", isNull, err
+		}
+	}
+	var g types.Geometry
+	if b.fromWKB {
+		g, err = types.GeometryFromWKB(hack.Slice(s), srid)
+	} else {
+		g, err = types.GeometryFromText(s, srid)
+	}
+	if types.ErrSRSNotFound.Equal(err) {
+		return "", false, err
+	}
+	if err != nil || b.geomTp != types.GeometryTypeGeometry && g.Shape.GeometryType() != b.geomTp {
+		return "", false, types.ErrGISInvalidData.GenWithStackByArgs(b.name)
+	}
+	if err = g.CheckCoordinates(b.name); err != nil {
+		return "", false, err
+	}
+	return string(g.Encode()), false, nil
+}
+
+type geomFromGeoJSONFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomFromGeoJSONFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson}
+	for range args[1:] {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf, types.GeometryTypeGeometry)
+	return &builtinGeomFromGeoJSONSig{bf, c.funcName}, nil
+}
+
+// builtinGeomFromGeoJSONSig constructs a geometry from the GeoJSON.
+type builtinGeomFromGeoJSONSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomFromGeoJSONSig) Clone() builtinFunc {
+	newSig := &builtinGeomFromGeoJSONSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomFromGeoJSONSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	doc, isNull, err := b.args[0].EvalJSON(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	options := int64(types.GeoJSONRejectHigherDimensions)
+	if len(b.args) > 1 {
+		if options, isNull, err = b.args[1].EvalInt(ctx, row); isNull || err != nil {
+			return "", isNull, err
+		}
+		if options < types.GeoJSONRejectHigherDimensions || options > types.GeoJSONMaxOption {
+			return "", false, errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+	}
+	g, err := types.GeometryFromGeoJSON(doc, int(options))
+	if err != nil {
+		return "", false, types.ErrInvalidGeoJSON.GenWithStackByArgs(b.name)
+	}
+	if len(b.args) > 2 {
+		if g.SRID, isNull, err = evalSRID(ctx, b.args[2], row); isNull || err != nil {
+			return "", isNull, err
+		}
+	}
+	if err = g.CheckCoordinates(b.name); err != nil {
+		return "", false, err
+	}
+	return string(g.Encode()), false, nil
+}
+
+type geomConstructorFunctionClass struct {
+	baseFunctionClass
+
+	geomTp types.GeometryType
+}
+
+func (c *geomConstructorFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := types.ETString
+	if c.geomTp == types.GeometryTypePoint {
+		argTp = types.ETReal
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = argTp
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf, c.geomTp)
+	if c.geomTp == types.GeometryTypePoint {
+		return &builtinPointSig{bf}, nil
+	}
+	return &builtinGeomConstructorSig{bf, c.geomTp, c.funcName}, nil
+}
+
+// builtinPointSig constructs a point from its coordinates.
+type builtinPointSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPointSig) Clone() builtinFunc {
+	newSig := &builtinPointSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinPointSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	y, isNull, err := b.args[1].EvalReal(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(types.Geometry{Shape: types.GeomPoint{X: x, Y: y}}.Encode()), false, nil
+}
+
+// builtinGeomConstructorSig constructs a geometry from its members, like LineString and Polygon.
+type builtinGeomConstructorSig struct {
+	baseBuiltinFunc
+
+	geomTp types.GeometryType
+	name   string
+}
+
+func (b *builtinGeomConstructorSig) Clone() builtinFunc {
+	newSig := &builtinGeomConstructorSig{geomTp: b.geomTp, name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomConstructorSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	members := make([]types.GeomShape, 0, len(b.args))
+	var srid uint32
+	for i, arg := range b.args {
+		g, isNull, err := evalGeometry(ctx, arg, row, b.name)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		if i > 0 && g.SRID != srid {
+			return "", false, types.ErrGISDifferentSRIDs.GenWithStackByArgs(b.name, srid, g.SRID)
+		}
+		srid = g.SRID
+		members = append(members, g.Shape)
+	}
+	shape, ok := buildGeomShape(b.geomTp, members)
+	if !ok {
+		return "", false, types.ErrGISInvalidData.GenWithStackByArgs(b.name)
+	}
+	return string(types.Geometry{SRID: srid, Shape: shape}.Encode()), false, nil
+}
+
+// buildGeomShape builds the shape of the type from the members, it returns false if the members are invalid.
+func buildGeomShape(geomTp types.GeometryType, members []types.GeomShape) (types.GeomShape, bool) {
+	switch geomTp {
+	case types.GeometryTypeLineString, types.GeometryTypeMultiPoint:
+		points := make([]types.GeomPoint, 0, len(members))
+		for _, m := range members {
+			p, ok := m.(types.GeomPoint)
+			if !ok {
+				return nil, false
+			}
+			points = append(points, p)
+		}
+		if geomTp == types.GeometryTypeMultiPoint {
+			return types.GeomMultiPoint(points), true
+		}
+		return types.GeomLineString(points), len(points) >= 2
+	case types.GeometryTypePolygon, types.GeometryTypeMultiLineString:
+		lineStrings := make([]types.GeomLineString, 0, len(members))
+		for _, m := range members {
+			ls, ok := m.(types.GeomLineString)
+			if !ok {
+				return nil, false
+			}
+			// The rings of a polygon must be closed.
+			if geomTp == types.GeometryTypePolygon && (len(ls) < 4 || ls[0] != ls[len(ls)-1]) {
+				return nil, false
+			}
+			lineStrings = append(lineStrings, ls)
+		}
+		if geomTp == types.GeometryTypeMultiLineString {
+			return types.GeomMultiLineString(lineStrings), true
+		}
+		return types.GeomPolygon(lineStrings), true
+	case types.GeometryTypeMultiPolygon:
+		polygons := make(types.GeomMultiPolygon, 0, len(members))
+		for _, m := range members {
+			polygon, ok := m.(types.GeomPolygon)
+			if !ok {
+				return nil, false
+			}
+			polygons = append(polygons, polygon)
+		}
+		return polygons, true
+	default:
+		return types.GeomCollection(members), true
+	}
+}
+
+type geomAsTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomAsTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	charset, collate := ctx.GetCharsetInfo()
+	bf.tp.SetCharset(charset)
+	bf.tp.SetCollate(collate)
+	bf.tp.SetFlen(mysql.MaxBlobWidth)
+	return &builtinGeomAsTextSig{bf, c.funcName}, nil
+}
+
+// builtinGeomAsTextSig returns the WKT of a geometry.
+type builtinGeomAsTextSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomAsTextSig) Clone() builtinFunc {
+	newSig := &builtinGeomAsTextSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomAsTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return g.WKT(), false, nil
+}
+
+type geomAsBinaryFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomAsBinaryFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetType(mysql.TypeLongBlob)
+	bf.tp.SetFlen(mysql.MaxBlobWidth)
+	types.SetBinChsClnFlag(bf.tp)
+	return &builtinGeomAsBinarySig{bf, c.funcName}, nil
+}
+
+// builtinGeomAsBinarySig returns the WKB of a geometry.
+type builtinGeomAsBinarySig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomAsBinarySig) Clone() builtinFunc {
+	newSig := &builtinGeomAsBinarySig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomAsBinarySig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(g.WKB()), false, nil
+}
+
+type geomAsGeoJSONFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomAsGeoJSONFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString}
+	for range args[1:] {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinGeomAsGeoJSONSig{bf, c.funcName}, nil
+}
+
+// builtinGeomAsGeoJSONSig returns the GeoJSON of a geometry.
+type builtinGeomAsGeoJSONSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomAsGeoJSONSig) Clone() builtinFunc {
+	newSig := &builtinGeomAsGeoJSONSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomAsGeoJSONSig) evalJSON(ctx EvalContext, row chunk.Row) (types.BinaryJSON, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return types.BinaryJSON{}, isNull, err
+	}
+	// The coordinates are not rounded by default.
+	maxDecimalDigits, options := int64(math.MaxInt32), int64(0)
+	if len(b.args) > 1 {
+		if maxDecimalDigits, isNull, err = b.args[1].EvalInt(ctx, row); isNull || err != nil {
+			return types.BinaryJSON{}, isNull, err
+		}
+		if maxDecimalDigits < 0 {
+			return types.BinaryJSON{}, false, errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+	}
+	if len(b.args) > 2 {
+		if options, isNull, err = b.args[2].EvalInt(ctx, row); isNull || err != nil {
+			return types.BinaryJSON{}, isNull, err
+		}
+		allOptions := int64(types.GeoJSONOptionBoundingBox | types.GeoJSONOptionShortCRS | types.GeoJSONOptionLongCRS)
+		if options < 0 || options&^allOptions != 0 {
+			return types.BinaryJSON{}, false, errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+	}
+	if maxDecimalDigits > math.MaxInt32 {
+		maxDecimalDigits = math.MaxInt32
+	}
+	bj, err := g.GeoJSON(int(maxDecimalDigits), int(options))
+	return bj, false, err
+}
+
+type geomSRIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomSRIDFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+		if err != nil {
+			return nil, err
+		}
+		bf.tp.AddFlag(mysql.UnsignedFlag)
+		return &builtinGeomSRIDSig{bf, c.funcName}, nil
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf, args[0].GetType().GetGeometryType())
+	return &builtinGeomSetSRIDSig{bf, c.funcName}, nil
+}
+
+// builtinGeomSRIDSig returns the SRID of a geometry.
+type builtinGeomSRIDSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomSRIDSig) Clone() builtinFunc {
+	newSig := &builtinGeomSRIDSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomSRIDSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(g.SRID), false, nil
+}
+
+// builtinGeomSetSRIDSig returns a geometry with the same coordinates in another SRS.
+type builtinGeomSetSRIDSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomSetSRIDSig) Clone() builtinFunc {
+	newSig := &builtinGeomSetSRIDSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomSetSRIDSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if g.SRID, isNull, err = evalSRID(ctx, b.args[1], row); isNull || err != nil {
+		return "", isNull, err
+	}
+	if err = g.CheckCoordinates(b.name); err != nil {
+		return "", false, err
+	}
+	return string(g.Encode()), false, nil
+}
+
+// The coordinates returned by geomCoordinateFunctionClass.
+const (
+	geomCoordinateX = iota
+	geomCoordinateY
+	geomCoordinateLatitude
+	geomCoordinateLongitude
+)
+
+type geomCoordinateFunctionClass struct {
+	baseFunctionClass
+
+	coordinate int
+}
+
+func (c *geomCoordinateFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinGeomCoordinateSig{bf, c.coordinate, c.funcName}, nil
+}
+
+// builtinGeomCoordinateSig returns a coordinate of a point. The X and Y coordinates are in the axis order of the
+// SRS of the point, so ST_X returns the latitude in a geographic SRS.
+type builtinGeomCoordinateSig struct {
+	baseBuiltinFunc
+
+	coordinate int
+	name       string
+}
+
+func (b *builtinGeomCoordinateSig) Clone() builtinFunc {
+	newSig := &builtinGeomCoordinateSig{coordinate: b.coordinate, name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomCoordinateSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	p, ok := g.Shape.(types.GeomPoint)
+	if !ok {
+		return 0, false, types.ErrGISUnsupportedArgument.GenWithStackByArgs(b.name)
+	}
+	srs, err := types.GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return 0, false, err
+	}
+	switch b.coordinate {
+	case geomCoordinateX:
+		if srs.Geographic {
+			return p.Y, false, nil
+		}
+		return p.X, false, nil
+	case geomCoordinateY:
+		if srs.Geographic {
+			return p.X, false, nil
+		}
+		return p.Y, false, nil
+	}
+	if !srs.Geographic {
+		return 0, false, types.ErrGISUnsupportedArgument.GenWithStackByArgs(b.name)
+	}
+	if b.coordinate == geomCoordinateLatitude {
+		return p.Y, false, nil
+	}
+	return p.X, false, nil
+}
+
+type geomTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomTypeFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	charset, collate := ctx.GetCharsetInfo()
+	bf.tp.SetCharset(charset)
+	bf.tp.SetCollate(collate)
+	bf.tp.SetFlen(len("MULTILINESTRING"))
+	return &builtinGeomTypeSig{bf, c.funcName}, nil
+}
+
+// builtinGeomTypeSig returns the type name of a geometry.
+type builtinGeomTypeSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomTypeSig) Clone() builtinFunc {
+	newSig := &builtinGeomTypeSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomTypeSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.ToUpper(g.Shape.GeometryType().String()), false, nil
+}
+
+type geomIsEmptyFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomIsEmptyFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(1)
+	return &builtinGeomIsEmptySig{bf, c.funcName}, nil
+}
+
+// builtinGeomIsEmptySig returns whether a geometry is an empty geometry collection.
+type builtinGeomIsEmptySig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomIsEmptySig) Clone() builtinFunc {
+	newSig := &builtinGeomIsEmptySig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomIsEmptySig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if g.Shape.IsEmpty() {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type geomRelationFunctionClass struct {
+	baseFunctionClass
+
+	relation func(g1, g2 types.Geometry) bool
+}
+
+func (c *geomRelationFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(1)
+	return &builtinGeomRelationSig{bf, c.relation, c.funcName}, nil
+}
+
+// builtinGeomRelationSig returns whether two geometries have a spatial relation, like ST_Contains and
+// ST_Intersects.
+type builtinGeomRelationSig struct {
+	baseBuiltinFunc
+
+	relation func(g1, g2 types.Geometry) bool
+	name     string
+}
+
+func (b *builtinGeomRelationSig) Clone() builtinFunc {
+	newSig := &builtinGeomRelationSig{relation: b.relation, name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomRelationSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if _, err = types.GetSpatialReferenceSystem(g1.SRID); err != nil {
+		return 0, false, err
+	}
+	if b.relation(g1, g2) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type geomDistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomDistanceFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinGeomDistanceSig{bf, c.funcName}, nil
+}
+
+// builtinGeomDistanceSig returns the minimum distance between two geometries.
+type builtinGeomDistanceSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomDistanceSig) Clone() builtinFunc {
+	newSig := &builtinGeomDistanceSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomDistanceSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	dist, err := g1.Distance(g2, b.name)
+	return dist, false, err
+}
+
+type geomDistanceSphereFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomDistanceSphereFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString}
+	if len(args) > 2 {
+		argTps = append(argTps, types.ETReal)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinGeomDistanceSphereSig{bf, c.funcName}, nil
+}
+
+// builtinGeomDistanceSphereSig returns the minimum distance between two points or multipoints on a sphere.
+type builtinGeomDistanceSphereSig struct {
+	baseBuiltinFunc
+
+	name string
+}
+
+func (b *builtinGeomDistanceSphereSig) Clone() builtinFunc {
+	newSig := &builtinGeomDistanceSphereSig{name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomDistanceSphereSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	radius := float64(types.DefaultSphereRadius)
+	if len(b.args) > 2 {
+		if radius, isNull, err = b.args[2].EvalReal(ctx, row); isNull || err != nil {
+			return 0, isNull, err
+		}
+		if radius <= 0 {
+			return 0, false, errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+	}
+	srs, err := types.GetSpatialReferenceSystem(g1.SRID)
+	if err != nil {
+		return 0, false, err
+	}
+	if g1.SRID != 0 && !srs.Geographic {
+		return 0, false, types.ErrGISUnsupportedArgument.GenWithStackByArgs(b.name)
+	}
+	dist, err := g1.DistanceSphere(g2, radius, b.name)
+	return dist, false, err
+}
+
+type geomMeasureFunctionClass struct {
+	baseFunctionClass
+
+	measure func(g types.Geometry, funcName string) (float64, error)
+}
+
+func (c *geomMeasureFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinGeomMeasureSig{bf, c.measure, c.funcName}, nil
+}
+
+// builtinGeomMeasureSig returns a measurement of a geometry, like ST_Area and ST_Length.
+type builtinGeomMeasureSig struct {
+	baseBuiltinFunc
+
+	measure func(g types.Geometry, funcName string) (float64, error)
+	name    string
+}
+
+func (b *builtinGeomMeasureSig) Clone() builtinFunc {
+	newSig := &builtinGeomMeasureSig{measure: b.measure, name: b.name}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGeomMeasureSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	v, err := b.measure(g, b.name)
+	return v, false, err
+}
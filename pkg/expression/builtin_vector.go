@@ -0,0 +1,302 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/hack"
+)
+
+var (
+	_ functionClass = &vecFromTextFunctionClass{}
+	_ functionClass = &vecAsTextFunctionClass{}
+	_ functionClass = &vecDimsFunctionClass{}
+	_ functionClass = &vecL2NormFunctionClass{}
+	_ functionClass = &vecDistanceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinVecFromTextSig{}
+	_ builtinFunc = &builtinVecAsTextSig{}
+	_ builtinFunc = &builtinVecDimsSig{}
+	_ builtinFunc = &builtinVecL2NormSig{}
+	_ builtinFunc = &builtinVecDistanceSig{}
+)
+
+// vectorDistances are the distance functions of the vectors, a NaN distance is NULL.
+var vectorDistances = map[string]func(types.VectorFloat32, types.VectorFloat32) (float64, error){
+	ast.VecL1Distance:     types.VectorFloat32.L1Distance,
+	ast.VecL2Distance:     types.VectorFloat32.L2Distance,
+	ast.VecCosineDistance: types.VectorFloat32.CosineDistance,
+	ast.VecNegativeInnerProduct: func(v1, v2 types.VectorFloat32) (float64, error) {
+		p, err := v1.InnerProduct(v2)
+		return -p, err
+	},
+}
+
+// newVectorFieldType returns the type of the vectors with unknown dimension.
+func newVectorFieldType() *types.FieldType {
+	tp := types.NewFieldType(mysql.TypeTiDBVectorFloat32)
+	tp.SetFlen(types.UnspecifiedLength)
+	tp.SetDecimal(0)
+	tp.SetCharset(charset.CharsetBin)
+	tp.SetCollate(charset.CollationBin)
+	tp.AddFlag(mysql.BinaryFlag)
+	return tp
+}
+
+// wrapWithVecFromText converts the arguments which aren't vectors from the text form, so a string literal like
+// '[1,2,3]' can be used as a vector.
+func wrapWithVecFromText(ctx BuildContext, args []Expression) ([]Expression, error) {
+	ret := make([]Expression, 0, len(args))
+	for _, arg := range args {
+		if arg.GetType().GetType() != mysql.TypeTiDBVectorFloat32 {
+			var err error
+			if arg, err = NewFunction(ctx, ast.VecFromText, newVectorFieldType(), arg); err != nil {
+				return nil, err
+			}
+		}
+		ret = append(ret, arg)
+	}
+	return ret, nil
+}
+
+// evalVector evaluates an argument which is a vector in the binary format.
+func evalVector(ctx EvalContext, arg Expression, row chunk.Row) (types.VectorFloat32, bool, error) {
+	s, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	v, err := types.DecodeVectorFloat32(hack.Slice(s))
+	return v, false, err
+}
+
+type vecFromTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecFromTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp = newVectorFieldType()
+	bf.SetCharsetAndCollation(charset.CharsetBin, charset.CollationBin)
+	return &builtinVecFromTextSig{bf}, nil
+}
+
+// builtinVecFromTextSig converts the text form of a vector to the binary format.
+type builtinVecFromTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecFromTextSig) Clone() builtinFunc {
+	newSig := &builtinVecFromTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinVecFromTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	s, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	v, err := types.ParseVectorFloat32(s)
+	if err != nil {
+		return "", false, err
+	}
+	return string(v.Encode()), false, nil
+}
+
+type vecAsTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecAsTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	args, err := wrapWithVecFromText(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	charset, collate := ctx.GetCharsetInfo()
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.SetCharset(charset)
+	tp.SetCollate(collate)
+	tp.SetFlen(mysql.MaxBlobWidth)
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, tp)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinVecAsTextSig{bf}, nil
+}
+
+// builtinVecAsTextSig returns the text form of a vector.
+type builtinVecAsTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecAsTextSig) Clone() builtinFunc {
+	newSig := &builtinVecAsTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinVecAsTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return v.String(), false, nil
+}
+
+type vecDimsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecDimsFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	args, err := wrapWithVecFromText(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, types.NewFieldType(mysql.TypeLonglong))
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxIntWidth)
+	return &builtinVecDimsSig{bf}, nil
+}
+
+// builtinVecDimsSig returns the dimension of a vector.
+type builtinVecDimsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecDimsSig) Clone() builtinFunc {
+	newSig := &builtinVecDimsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinVecDimsSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	s, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	dim, err := types.VectorDimensionOf(hack.Slice(s))
+	return int64(dim), false, err
+}
+
+type vecL2NormFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecL2NormFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	args, err := wrapWithVecFromText(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, types.NewFieldType(mysql.TypeDouble))
+	if err != nil {
+		return nil, err
+	}
+	return &builtinVecL2NormSig{bf}, nil
+}
+
+// builtinVecL2NormSig returns the Euclidean norm of a vector.
+type builtinVecL2NormSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecL2NormSig) Clone() builtinFunc {
+	newSig := &builtinVecL2NormSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinVecL2NormSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return v.L2Norm(), false, nil
+}
+
+type vecDistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecDistanceFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	args, err := wrapWithVecFromText(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, types.NewFieldType(mysql.TypeDouble))
+	if err != nil {
+		return nil, err
+	}
+	return &builtinVecDistanceSig{bf, vectorDistances[c.funcName]}, nil
+}
+
+// builtinVecDistanceSig returns the distance of two vectors, the vectors must have the same dimension.
+type builtinVecDistanceSig struct {
+	baseBuiltinFunc
+
+	distance func(types.VectorFloat32, types.VectorFloat32) (float64, error)
+}
+
+func (b *builtinVecDistanceSig) Clone() builtinFunc {
+	newSig := &builtinVecDistanceSig{distance: b.distance}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinVecDistanceSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v1, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	v2, isNull, err := evalVector(ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, err := b.distance(v1, v2)
+	if err != nil {
+		return 0, false, err
+	}
+	if math.IsNaN(d) {
+		return 0, true, nil
+	}
+	return d, false, nil
+}
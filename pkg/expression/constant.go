@@ -148,6 +148,9 @@ func (c *Constant) String() string {
 	} else if c.DeferredExpr != nil {
 		return c.DeferredExpr.String()
 	}
+	if c.RetType.GetType() == mysql.TypeTiDBVectorFloat32 && !c.Value.IsNull() {
+		return string(types.VectorTextFromBinary(c.Value.GetBytes()))
+	}
 	return fmt.Sprintf("%v", c.Value.GetValue())
 }
 
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/intest"
@@ -155,6 +156,9 @@ func (expr *Constant) format(dt types.Datum) string {
 		return "NULL"
 	case types.KindString, types.KindBytes, types.KindMysqlEnum, types.KindMysqlSet,
 		types.KindMysqlJSON, types.KindBinaryLiteral, types.KindMysqlBit:
+		if expr.RetType.GetType() == mysql.TypeTiDBVectorFloat32 {
+			return fmt.Sprintf("\"%s\"", types.VectorTextFromBinary(dt.GetBytes()))
+		}
 		return fmt.Sprintf("\"%v\"", dt.GetValue())
 	}
 	return fmt.Sprintf("%v", dt.GetValue())
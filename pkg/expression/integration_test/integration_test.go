@@ -3098,3 +3098,86 @@ func TestIssue43527(t *testing.T) {
 		"SELECT @total := @total + d FROM (SELECT d FROM test) AS temp, (SELECT @total := b FROM test) AS T1 where @total >= 100",
 	).Check(testkit.Rows("200", "300", "400", "500"))
 }
+
+func TestSpatialFunctions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, g geometry, p point srid 4326)")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `g` geometry DEFAULT NULL,\n" +
+		"  `p` point DEFAULT NULL /*!80003 SRID 4326 */,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values (1, st_geomfromtext('POLYGON((0 0,10 0,10 10,0 10,0 0))'), st_geomfromtext('POINT(40 116)', 4326))")
+	tk.MustExec("insert into t values (2, point(5, 5), st_srid(point(121, 31), 4326))")
+	tk.MustGetErrCode("insert into t values (3, null, point(1, 1))", errno.ErrWrongSRIDForColumn)
+	tk.MustGetErrCode("insert into t values (3, 'abc', null)", errno.ErrCantCreateGeometryObject)
+	tk.MustGetErrCode("create index idx on t (g)", errno.ErrUnsupportedDDLOperation)
+
+	// Constructors and output formats.
+	tk.MustQuery("select st_astext(g), st_astext(p), st_geometrytype(g), st_srid(p) from t order by id").Check(testkit.Rows(
+		"POLYGON((0 0,10 0,10 10,0 10,0 0)) POINT(40 116) POLYGON 4326",
+		"POINT(5 5) POINT(31 121) POINT 4326"))
+	tk.MustQuery("select st_x(p), st_y(p), st_latitude(p), st_longitude(p) from t where id = 1").Check(testkit.Rows("40 116 40 116"))
+	tk.MustQuery("select st_astext(linestring(point(1, 2), point(3, 4))), st_astext(multipoint(point(1, 1), point(2, 2)))").Check(
+		testkit.Rows("LINESTRING(1 2,3 4) MULTIPOINT((1 1),(2 2))"))
+	tk.MustQuery("select hex(st_asbinary(point(1, 2)))").Check(testkit.Rows("0101000000000000000000F03F0000000000000040"))
+	tk.MustQuery("select st_astext(st_geomfromwkb(st_asbinary(st_geomfromtext('LINESTRING(0 0,1 1)'))))").Check(testkit.Rows("LINESTRING(0 0,1 1)"))
+	tk.MustQuery(`select st_asgeojson(point(1, 2)), st_astext(st_geomfromgeojson('{"type": "Point", "coordinates": [116, 40]}'))`).Check(
+		testkit.Rows(`{"coordinates": [1, 2], "type": "Point"} POINT(40 116)`))
+	tk.MustQuery("select st_isempty(st_geomfromtext('GEOMETRYCOLLECTION EMPTY')), st_isempty(point(1, 1))").Check(testkit.Rows("1 0"))
+	require.True(t, types.ErrGISInvalidData.Equal(tk.QueryToErr("select st_pointfromtext('LINESTRING(0 0,1 1)')")))
+	require.True(t, types.ErrSRSNotFound.Equal(tk.QueryToErr("select st_geomfromtext('POINT(1 1)', 1)")))
+	require.True(t, types.ErrLatitudeOutOfRange.Equal(tk.QueryToErr("select st_geomfromtext('POINT(100 1)', 4326)")))
+
+	// Predicates and measurements.
+	tk.MustQuery("select st_contains(g, point(5, 5)), st_within(point(5, 5), g), st_intersects(g, point(20, 20)), st_disjoint(g, point(20, 20)) from t where id = 1").Check(
+		testkit.Rows("1 1 0 1"))
+	tk.MustQuery("select st_equals(point(1, 1), st_geomfromtext('MULTIPOINT(1 1,1 1)'))").Check(testkit.Rows("1"))
+	tk.MustQuery("select st_distance(point(0, 0), point(3, 4)), st_area(g), st_length(linestring(point(0, 0), point(3, 4))) from t where id = 1").Check(
+		testkit.Rows("5 100 5"))
+	tk.MustQuery("select round(st_distance_sphere(point(0, 0), point(0, 1)))").Check(testkit.Rows("111195"))
+	tk.MustQuery("select round(st_distance(p, st_srid(point(116, 39), 4326))) from t where id = 1").Check(testkit.Rows("111025"))
+	require.True(t, types.ErrGISDifferentSRIDs.Equal(tk.QueryToErr("select st_contains(g, p) from t")))
+	require.True(t, types.ErrNotImplementedForGeographicSRS.Equal(tk.QueryToErr("select st_area(p) from t")))
+	require.ErrorContains(t, tk.QueryToErr("select st_distance_sphere(point(0, 0), point(0, 1), 0)"), "Incorrect arguments to st_distance_sphere")
+}
+
+func TestVectorFunctions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v vector(3), w vector)")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `v` vector(3) DEFAULT NULL,\n" +
+		"  `w` vector DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values (1, '[1, 2, 3]', '[1.5]'), (2, vec_from_text('[0,0,0]'), '[1,2,3,4,5]'), (3, null, null)")
+	tk.MustGetErrCode("insert into t values (4, '[1, 2]', null)", errno.ErrVectorDimensionNotFit)
+	tk.MustGetErrCode("insert into t values (4, '[1, 2', null)", errno.ErrInvalidVector)
+	tk.MustGetErrCode("insert into t values (4, null, '[1, abc]')", errno.ErrInvalidVector)
+
+	// Input and output formats.
+	tk.MustQuery("select id, v, w, vec_as_text(v), vec_dims(v), vec_dims(w) from t order by id").Check(testkit.Rows(
+		"1 [1,2,3] [1.5] [1,2,3] 3 1",
+		"2 [0,0,0] [1,2,3,4,5] [0,0,0] 3 5",
+		"3 <nil> <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select vec_as_text('[ 1.5 , -2e3 ]'), vec_dims('[]'), vec_as_text(vec_from_text('[]'))").Check(testkit.Rows("[1.5,-2000] 0 []"))
+	tk.MustExec("update t set v = '[4, 5, 6]' where id = 2")
+	tk.MustQuery("select v from t where id = 2").Check(testkit.Rows("[4,5,6]"))
+
+	// Distances.
+	tk.MustQuery("select vec_l2_norm('[3, 4]'), vec_l1_distance('[1, 2]', '[4, 6]'), vec_l2_distance('[1, 2]', '[4, 6]')").Check(testkit.Rows("5 7 5"))
+	tk.MustQuery("select vec_negative_inner_product('[1, 2]', '[3, 4]'), vec_cosine_distance('[1, 0]', '[0, 1]'), round(vec_cosine_distance('[1, 1]', '[2, 2]'), 6)").Check(
+		testkit.Rows("-11 1 0"))
+	tk.MustQuery("select vec_cosine_distance('[0, 0]', '[1, 1]'), vec_l2_distance(null, '[1]')").Check(testkit.Rows("<nil> <nil>"))
+	tk.MustQuery("select id, vec_l2_distance(v, '[1, 2, 3]') from t order by id").Check(testkit.Rows(
+		"1 0", "2 5.196152422706632", "3 <nil>"))
+	require.True(t, types.ErrVectorDimensionMismatch.Equal(tk.QueryToErr("select vec_l2_distance(v, '[1, 2]') from t")))
+	require.True(t, types.ErrVectorDimensionMismatch.Equal(tk.QueryToErr("select vec_l2_distance(v, w) from t")))
+	require.True(t, types.ErrInvalidVector.Equal(tk.QueryToErr("select vec_dims('1, 2')")))
+}
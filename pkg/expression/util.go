@@ -1178,6 +1178,49 @@ func ParamMarkerExpression(ctx variable.SessionVarsProvider, v *driver.ParamMark
 	return value, nil
 }
 
+// CloneExprForPlanCache deep copies the expression and binds the parameters in the copy to the session, so an
+// expression built by a session can be evaluated with the parameters of another one. It's used by the plans shared
+// by the sessions through the instance plan cache.
+func CloneExprForPlanCache(expr Expression, ctx variable.SessionVarsProvider) Expression {
+	if expr == nil {
+		return nil
+	}
+	cloned := expr.Clone()
+	bindParamMarkers(cloned, ctx)
+	return cloned
+}
+
+// CloneExprsForPlanCache deep copies the expressions, see CloneExprForPlanCache.
+func CloneExprsForPlanCache(exprs []Expression, ctx variable.SessionVarsProvider) []Expression {
+	if exprs == nil {
+		return nil
+	}
+	cloned := make([]Expression, 0, len(exprs))
+	for _, expr := range exprs {
+		cloned = append(cloned, CloneExprForPlanCache(expr, ctx))
+	}
+	return cloned
+}
+
+// bindParamMarkers binds the parameters in the expression to the session, the expression must be a copy which isn't
+// shared with others.
+func bindParamMarkers(expr Expression, ctx variable.SessionVarsProvider) {
+	switch x := expr.(type) {
+	case *Constant:
+		if x.ParamMarker != nil {
+			x.ParamMarker = &ParamMarker{order: x.ParamMarker.order, ctx: ctx}
+		}
+		if x.DeferredExpr != nil {
+			// The deferred expression isn't copied by Constant.Clone.
+			x.DeferredExpr = CloneExprForPlanCache(x.DeferredExpr, ctx)
+		}
+	case *ScalarFunction:
+		for _, arg := range x.GetArgs() {
+			bindParamMarkers(arg, ctx)
+		}
+	}
+}
+
 // ParamMarkerInPrepareChecker checks whether the given ast tree has paramMarker and is in prepare statement.
 type ParamMarkerInPrepareChecker struct {
 	InPrepareStmt bool
@@ -1347,6 +1390,12 @@ func IsMutableEffectsExpr(expr Expression) bool {
 	return false
 }
 
+// IsMutableEffectsFunc checks if the function with the name is mutable or has side effects.
+func IsMutableEffectsFunc(name string) bool {
+	_, ok := mutableEffectsFunctions[name]
+	return ok
+}
+
 // IsImmutableFunc checks whether this expression only consists of foldable functions.
 // This expression can be evaluated by using `expr.Eval(chunk.Row{})` directly and the result won't change if it's immutable.
 func IsImmutableFunc(expr Expression) bool {
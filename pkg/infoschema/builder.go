@@ -687,6 +687,7 @@ func applyCreateTable(b *Builder, m *meta.Meta, dbInfo *model.DBInfo, tableID in
 	}
 
 	b.infoSchema.addReferredForeignKeys(dbInfo.Name, tblInfo)
+	b.infoSchema.addMaterializedView(dbInfo.Name, tblInfo)
 
 	if !b.enableV2 {
 		tableNames := b.infoSchema.schemaMap[dbInfo.Name.L]
@@ -774,6 +775,7 @@ func (b *Builder) deleteReferredForeignKeys(dbInfo *model.DBInfo, tableID int64)
 				dbInfo.Tables = append(dbInfo.Tables[:i], dbInfo.Tables[i+1:]...)
 			}
 			b.infoSchema.deleteReferredForeignKeys(dbInfo.Name, tblInfo)
+			b.infoSchema.deleteMaterializedView(dbInfo.Name, tblInfo)
 			break
 		}
 	}
@@ -811,6 +813,7 @@ func (b *Builder) InitWithOldInfoSchema(oldSchema InfoSchema) (*Builder, error)
 	b.copyResourceGroupMap(oldIS)
 	b.copyTemporaryTableIDsMap(oldIS)
 	b.copyReferredForeignKeyMap(oldIS)
+	b.copyMaterializedViewMap(oldIS)
 
 	copy(b.infoSchema.sortedTablesBuckets, oldIS.sortedTablesBuckets)
 	return b, nil
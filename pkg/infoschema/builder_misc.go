@@ -142,6 +142,12 @@ func (b *Builder) copyReferredForeignKeyMap(oldIS *infoSchema) {
 	}
 }
 
+func (b *Builder) copyMaterializedViewMap(oldIS *infoSchema) {
+	for k, v := range oldIS.materializedViewMap {
+		b.infoSchema.materializedViewMap[k] = v
+	}
+}
+
 func (b *Builder) initMisc(dbInfos []*model.DBInfo, policies []*model.PolicyInfo, resourceGroups []*model.ResourceGroupInfo) {
 	info := b.infoSchema
 	// build the policies.
@@ -154,10 +160,11 @@ func (b *Builder) initMisc(dbInfos []*model.DBInfo, policies []*model.PolicyInfo
 		info.setResourceGroup(group)
 	}
 
-	// Maintain foreign key reference information.
+	// Maintain foreign key reference and materialized view information.
 	for _, di := range dbInfos {
 		for _, t := range di.Tables {
 			b.infoSchema.addReferredForeignKeys(di.Name, t)
+			b.infoSchema.addMaterializedView(di.Name, t)
 		}
 	}
 }
@@ -53,4 +53,7 @@ type Misc interface {
 	HasTemporaryTable() bool
 	// GetTableReferredForeignKeys gets the table's ReferredFKInfo by lowercase schema and table name.
 	GetTableReferredForeignKeys(schema, table string) []*model.ReferredFKInfo
+	// GetTableMaterializedViews gets the names of the materialized views reading the table by lowercase schema and
+	// table name. The views are in the same schema as the table.
+	GetTableMaterializedViews(schema, table string) []model.CIStr
 }
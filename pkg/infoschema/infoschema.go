@@ -82,6 +82,10 @@ type infoSchemaMisc struct {
 	// referredForeignKeyMap records all table's ReferredFKInfo.
 	// referredSchemaAndTableName => child SchemaAndTableAndForeignKeyName => *model.ReferredFKInfo
 	referredForeignKeyMap map[SchemaAndTableName][]*model.ReferredFKInfo
+
+	// materializedViewMap records the materialized views of each base table.
+	// baseSchemaAndTableName => the names of the materialized views in the same schema
+	materializedViewMap map[SchemaAndTableName][]model.CIStr
 }
 
 // SchemaAndTableName contains the lower-case schema name and table name.
@@ -553,6 +557,57 @@ func (is *infoSchemaMisc) GetTableReferredForeignKeys(schema, table string) []*m
 	return is.referredForeignKeyMap[name]
 }
 
+func (is *infoSchemaMisc) addMaterializedView(schema model.CIStr, tbInfo *model.TableInfo) {
+	info := tbInfo.MaterializedView
+	if info == nil || info.BaseTable.L == "" {
+		return
+	}
+	base := SchemaAndTableName{schema: schema.L, table: info.BaseTable.L}
+	views := is.materializedViewMap[base]
+	for _, view := range views {
+		if view.L == tbInfo.Name.L {
+			return
+		}
+	}
+	newViews := make([]model.CIStr, 0, len(views)+1)
+	newViews = append(newViews, views...)
+	newViews = append(newViews, tbInfo.Name)
+	sort.Slice(newViews, func(i, j int) bool {
+		return newViews[i].L < newViews[j].L
+	})
+	is.materializedViewMap[base] = newViews
+}
+
+func (is *infoSchemaMisc) deleteMaterializedView(schema model.CIStr, tbInfo *model.TableInfo) {
+	info := tbInfo.MaterializedView
+	if info == nil || info.BaseTable.L == "" {
+		return
+	}
+	base := SchemaAndTableName{schema: schema.L, table: info.BaseTable.L}
+	views := is.materializedViewMap[base]
+	if len(views) == 0 {
+		return
+	}
+	newViews := make([]model.CIStr, 0, len(views)-1)
+	for _, view := range views {
+		if view.L != tbInfo.Name.L {
+			newViews = append(newViews, view)
+		}
+	}
+	if len(newViews) == 0 {
+		delete(is.materializedViewMap, base)
+		return
+	}
+	is.materializedViewMap[base] = newViews
+}
+
+// GetTableMaterializedViews gets the names of the materialized views reading the table by lowercase schema and
+// table name. The views are in the same schema as the table.
+func (is *infoSchemaMisc) GetTableMaterializedViews(schema, table string) []model.CIStr {
+	name := SchemaAndTableName{schema: schema, table: table}
+	return is.materializedViewMap[name]
+}
+
 // SessionTables store local temporary tables
 type SessionTables struct {
 	// Session tables can be accessed after the db is dropped, so there needs a way to retain the DBInfo.
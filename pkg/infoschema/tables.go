@@ -95,7 +95,8 @@ const (
 	tablePlugins    = "PLUGINS"
 	// TableConstraints is the string constant of TABLE_CONSTRAINTS.
 	TableConstraints = "TABLE_CONSTRAINTS"
-	tableTriggers    = "TRIGGERS"
+	// TableTriggers is the string constant of infoschema table.
+	TableTriggers = "TRIGGERS"
 	// TableUserPrivileges is the string constant of infoschema user privilege table.
 	TableUserPrivileges   = "USER_PRIVILEGES"
 	tableSchemaPrivileges = "SCHEMA_PRIVILEGES"
@@ -104,8 +105,9 @@ const (
 	// TableEngines is the string constant of infoschema table.
 	TableEngines = "ENGINES"
 	// TableViews is the string constant of infoschema table.
-	TableViews           = "VIEWS"
-	tableRoutines        = "ROUTINES"
+	TableViews = "VIEWS"
+	// TableRoutines is the string constant of infoschema table.
+	TableRoutines        = "ROUTINES"
 	tableParameters      = "PARAMETERS"
 	tableEvents          = "EVENTS"
 	tableGlobalStatus    = "GLOBAL_STATUS"
@@ -215,6 +217,12 @@ const (
 	TableKeywords = "KEYWORDS"
 	// TableTiDBIndexUsage is a table to show the usage stats of indexes in the current instance.
 	TableTiDBIndexUsage = "TIDB_INDEX_USAGE"
+	// TableMaterializedViews is the list of materialized views and their refresh status.
+	TableMaterializedViews = "MATERIALIZED_VIEWS"
+	// TableInstancePlanCache is the list of plans in the plan cache shared by the sessions of the current instance.
+	TableInstancePlanCache = "INSTANCE_PLAN_CACHE"
+	// TableInstancePlanCacheStats is the statistics of the plan cache shared by the sessions of the current instance.
+	TableInstancePlanCacheStats = "INSTANCE_PLAN_CACHE_STATS"
 )
 
 const (
@@ -257,14 +265,14 @@ var tableIDMap = map[string]int64{
 	TableSessionVar:                         autoid.InformationSchemaDBID + 14,
 	tablePlugins:                            autoid.InformationSchemaDBID + 15,
 	TableConstraints:                        autoid.InformationSchemaDBID + 16,
-	tableTriggers:                           autoid.InformationSchemaDBID + 17,
+	TableTriggers:                           autoid.InformationSchemaDBID + 17,
 	TableUserPrivileges:                     autoid.InformationSchemaDBID + 18,
 	tableSchemaPrivileges:                   autoid.InformationSchemaDBID + 19,
 	tableTablePrivileges:                    autoid.InformationSchemaDBID + 20,
 	tableColumnPrivileges:                   autoid.InformationSchemaDBID + 21,
 	TableEngines:                            autoid.InformationSchemaDBID + 22,
 	TableViews:                              autoid.InformationSchemaDBID + 23,
-	tableRoutines:                           autoid.InformationSchemaDBID + 24,
+	TableRoutines:                           autoid.InformationSchemaDBID + 24,
 	tableParameters:                         autoid.InformationSchemaDBID + 25,
 	tableEvents:                             autoid.InformationSchemaDBID + 26,
 	tableGlobalStatus:                       autoid.InformationSchemaDBID + 27,
@@ -336,6 +344,9 @@ var tableIDMap = map[string]int64{
 	TableKeywords:                        autoid.InformationSchemaDBID + 92,
 	TableTiDBIndexUsage:                  autoid.InformationSchemaDBID + 93,
 	ClusterTableTiDBIndexUsage:           autoid.InformationSchemaDBID + 94,
+	TableMaterializedViews:               autoid.InformationSchemaDBID + 95,
+	TableInstancePlanCache:               autoid.InformationSchemaDBID + 96,
+	TableInstancePlanCacheStats:          autoid.InformationSchemaDBID + 97,
 }
 
 // columnInfo represents the basic column information of all kinds of INFORMATION_SCHEMA tables
@@ -1701,6 +1712,39 @@ var tableTiDBIndexUsage = []columnInfo{
 	{name: "LAST_ACCESS_TIME", tp: mysql.TypeDatetime, size: 21},
 }
 
+var tableMaterializedViewsCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "VIEW_DEFINITION", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: "REFRESH_METHOD", tp: mysql.TypeVarchar, size: 16},
+	{name: "REFRESH_INTERVAL", tp: mysql.TypeVarchar, size: 64},
+	{name: "FAST_REFRESHABLE", tp: mysql.TypeVarchar, size: 3},
+	{name: "LAST_REFRESH_TSO", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "LAST_REFRESH_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "LAST_REFRESH_METHOD", tp: mysql.TypeVarchar, size: 16},
+	{name: "LAST_ERROR", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
+var tableInstancePlanCacheCols = []columnInfo{
+	{name: "SCHEMA_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "SQL_TEXT", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "MEM_USAGE", tp: mysql.TypeLonglong, size: 21},
+	{name: "HITS", tp: mysql.TypeLonglong, size: 21},
+	{name: "LOAD_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "LAST_ACTIVE_TIME", tp: mysql.TypeDatetime, size: 19},
+}
+
+var tableInstancePlanCacheStatsCols = []columnInfo{
+	{name: "PLANS", tp: mysql.TypeLonglong, size: 21},
+	{name: "MEM_USAGE", tp: mysql.TypeLonglong, size: 21},
+	{name: "MEM_CAPACITY", tp: mysql.TypeLonglong, size: 21},
+	{name: "HITS", tp: mysql.TypeLonglong, size: 21},
+	{name: "MISSES", tp: mysql.TypeLonglong, size: 21},
+	{name: "EVICTIONS", tp: mysql.TypeLonglong, size: 21},
+	{name: "EVICTED_MEM_USAGE", tp: mysql.TypeLonglong, size: 21},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //   - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -2281,14 +2325,14 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableSessionVar:                         sessionVarCols,
 	tablePlugins:                            pluginsCols,
 	TableConstraints:                        tableConstraintsCols,
-	tableTriggers:                           tableTriggersCols,
+	TableTriggers:                           tableTriggersCols,
 	TableUserPrivileges:                     tableUserPrivilegesCols,
 	tableSchemaPrivileges:                   tableSchemaPrivilegesCols,
 	tableTablePrivileges:                    tableTablePrivilegesCols,
 	tableColumnPrivileges:                   tableColumnPrivilegesCols,
 	TableEngines:                            tableEnginesCols,
 	TableViews:                              tableViewsCols,
-	tableRoutines:                           tableRoutinesCols,
+	TableRoutines:                           tableRoutinesCols,
 	tableParameters:                         tableParametersCols,
 	tableEvents:                             tableEventsCols,
 	tableGlobalStatus:                       tableGlobalStatusCols,
@@ -2347,6 +2391,9 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableTiDBCheckConstraints:               tableTiDBCheckConstraintsCols,
 	TableKeywords:                           tableKeywords,
 	TableTiDBIndexUsage:                     tableTiDBIndexUsage,
+	TableMaterializedViews:                  tableMaterializedViewsCols,
+	TableInstancePlanCache:                  tableInstancePlanCacheCols,
+	TableInstancePlanCacheStats:             tableInstancePlanCacheStatsCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -218,6 +218,8 @@ const (
 	InternalDistTask = "DistTask"
 	// InternalTimer is the type of internal timer
 	InternalTimer = "Timer"
+	// InternalTxnMView is the type of materialized view refresh.
+	InternalTxnMView = "MView"
 )
 
 // The bitmap:
@@ -0,0 +1,566 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+)
+
+const (
+	// logTablePrefix is the name prefix of the log table which records the changes of the base table.
+	logTablePrefix = "mlog$_"
+	// SignColumn is the column of the log table which is 1 for an inserted row and -1 for a deleted row.
+	SignColumn = "_tidb_mlog_sign"
+	// deltaColumnPrefix is the name prefix of the output columns of the delta query.
+	deltaColumnPrefix = "_c"
+)
+
+// LogTableName returns the name of the log table of a materialized view.
+func LogTableName(mvName model.CIStr) model.CIStr {
+	return model.NewCIStr(logTablePrefix + mvName.O)
+}
+
+// TriggerName returns the name of the trigger which logs the changes of an event for a materialized view.
+func TriggerName(mvID int64, event model.TriggerEvent) model.CIStr {
+	return model.NewCIStr(fmt.Sprintf("_tidb_mview_%d_%s", mvID, strings.ToLower(event.String())))
+}
+
+// Field is a field of the select list of a materialized view.
+type Field struct {
+	Expr ast.ExprNode
+	// Agg is the aggregate function if the field is an aggregate function.
+	Agg *ast.AggregateFuncExpr
+	// Group is the offset of the GROUP BY item the field is, it's -1 if the field isn't a GROUP BY item.
+	Group int
+	// Text is the canonical text of the expression, see ExprText.
+	Text string
+}
+
+// Definition is the analyzed query of a materialized view.
+type Definition struct {
+	sql string
+	// Select is the query, it's nil if the query is a set operation.
+	Select *ast.SelectStmt
+	// Table is the only table the query reads. It's nil if the query can't be maintained incrementally or
+	// used to rewrite queries, which means it reads zero or more than one table, or it has something
+	// (subquery, CTE, window function and so on) whose result can't be derived from the rows of a single table.
+	Table *model.TableInfo
+	// Schema is the schema of the Table.
+	Schema     model.CIStr
+	Fields     []*Field
+	Aggregated bool
+	// GroupBy is the canonical text of the GROUP BY items, the items that refer to the select list by
+	// position or alias are resolved to the referred fields.
+	GroupBy []string
+	// GroupInSelect indicates whether every GROUP BY item is in the select list.
+	GroupInSelect bool
+	// Where is the canonical text of the WHERE clause.
+	Where string
+	// Columns are the offsets of the columns of the Table which are referred by the query.
+	Columns []int
+
+	reason string
+}
+
+// Analyze parses and analyzes the query of a materialized view. The table names of the query must be
+// qualified by the schema names, which is always true for the stored definition.
+func Analyze(is infoschema.InfoSchema, sql string) (*Definition, error) {
+	stmt, err := parser.New().ParseOneStmt(sql, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	def := &Definition{sql: sql}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok {
+		def.reason = "the query is a set operation"
+		return def, nil
+	}
+	def.Select = sel
+	def.analyze(is)
+	return def, nil
+}
+
+// AnalyzeSelect analyzes a query in the same way as the query of a materialized view. The query is not changed.
+func AnalyzeSelect(is infoschema.InfoSchema, sel *ast.SelectStmt) *Definition {
+	def := &Definition{Select: sel}
+	def.analyze(is)
+	return def
+}
+
+func (d *Definition) analyze(is infoschema.InfoSchema) {
+	sel := d.Select
+	tn, reason := singleTable(sel)
+	if reason == "" {
+		reason = checkExprs(sel)
+	}
+	if reason == "" {
+		tbl, err := is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			reason = fmt.Sprintf("table %s.%s doesn't exist", tn.Schema.O, tn.Name.O)
+		} else if tblInfo := tbl.Meta(); !tblInfo.IsBaseTable() || tblInfo.TempTableType != model.TempTableNone {
+			reason = fmt.Sprintf("%s.%s isn't a normal table", tn.Schema.O, tn.Name.O)
+		} else {
+			d.Table, d.Schema = tblInfo, tn.Schema
+		}
+	}
+
+	for _, f := range sel.Fields.Fields {
+		if f.WildCard != nil {
+			if reason == "" {
+				reason = "the select list contains a wildcard"
+			}
+			continue
+		}
+		field := &Field{Expr: f.Expr, Group: -1, Text: ExprText(f.Expr)}
+		if agg, ok := unwrapParentheses(f.Expr).(*ast.AggregateFuncExpr); ok {
+			field.Agg = agg
+		}
+		d.Fields = append(d.Fields, field)
+	}
+	if reason == "" {
+		d.Columns, reason = referredColumns(sel, d.Table, d.Fields)
+	}
+	if d.reason = reason; reason != "" {
+		d.Table = nil
+	}
+	if sel.Where != nil {
+		d.Where = ExprText(sel.Where)
+	}
+	d.Aggregated = sel.GroupBy != nil || hasAggregate(sel.Fields) || sel.Having != nil
+	if sel.GroupBy == nil {
+		d.GroupInSelect = true
+		return
+	}
+	d.GroupInSelect = true
+	for i, item := range sel.GroupBy.Items {
+		offset := d.resolveGroupItem(item.Expr)
+		if offset < 0 {
+			d.GroupInSelect = false
+			d.GroupBy = append(d.GroupBy, ExprText(item.Expr))
+			continue
+		}
+		if d.Fields[offset].Group < 0 {
+			d.Fields[offset].Group = i
+		}
+		d.GroupBy = append(d.GroupBy, d.Fields[offset].Text)
+	}
+}
+
+// resolveGroupItem returns the offset of the field a GROUP BY item refers to, or -1 if it isn't in the select list.
+func (d *Definition) resolveGroupItem(expr ast.ExprNode) int {
+	if pos, ok := expr.(*ast.PositionExpr); ok {
+		if pos.P == nil && pos.N > 0 && pos.N <= len(d.Fields) && len(d.Fields) == len(d.Select.Fields.Fields) {
+			return pos.N - 1
+		}
+		return -1
+	}
+	text := ExprText(expr)
+	for i, field := range d.Fields {
+		if field.Text == text {
+			return i
+		}
+	}
+	// GROUP BY looks up the columns of the table before the aliases of the select list.
+	col, ok := expr.(*ast.ColumnNameExpr)
+	if !ok || col.Name.Table.L != "" || d.Table == nil || model.FindColumnInfo(d.Table.Columns, col.Name.Name.L) != nil {
+		return -1
+	}
+	for i, f := range d.Select.Fields.Fields {
+		if f.AsName.L == col.Name.Name.L && i < len(d.Fields) && len(d.Fields) == len(d.Select.Fields.Fields) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Reason returns why the view can't be maintained incrementally or used to rewrite queries, or "" if it can.
+func (d *Definition) Reason() string {
+	return d.reason
+}
+
+// FastRefreshUnsupportedReason returns why the view can't be refreshed fast, or "" if it can.
+// A view can be refreshed fast if it aggregates a single table and every field is a GROUP BY item,
+// COUNT or SUM, so that the view can be maintained by adding the aggregated changes of the table.
+func (d *Definition) FastRefreshUnsupportedReason() string {
+	if d.reason != "" {
+		return d.reason
+	}
+	sel := d.Select
+	switch {
+	case !d.Aggregated:
+		return "the query has no aggregation"
+	case sel.Having != nil:
+		return "the query has a HAVING clause"
+	case sel.Distinct:
+		return "the query has DISTINCT"
+	case sel.Limit != nil:
+		return "the query has a LIMIT clause"
+	case !d.GroupInSelect:
+		return "a GROUP BY item isn't in the select list"
+	}
+	counts := make(map[string]struct{})
+	hasCountAll := false
+	for _, field := range d.Fields {
+		if field.Agg == nil || field.Agg.Distinct || strings.ToLower(field.Agg.F) != ast.AggFuncCount {
+			continue
+		}
+		counts[ExprText(field.Agg.Args[0])] = struct{}{}
+		hasCountAll = hasCountAll || isCountAll(field.Agg)
+	}
+	if !hasCountAll {
+		return "the query has no COUNT(*)"
+	}
+	for _, field := range d.Fields {
+		if field.Group >= 0 {
+			continue
+		}
+		if field.Agg == nil {
+			return fmt.Sprintf("'%s' isn't an aggregate function or a GROUP BY item", field.Text)
+		}
+		if field.Agg.Distinct {
+			return fmt.Sprintf("'%s' has DISTINCT", field.Text)
+		}
+		switch strings.ToLower(field.Agg.F) {
+		case ast.AggFuncCount:
+		case ast.AggFuncSum:
+			arg := field.Agg.Args[0]
+			if _, ok := counts[ExprText(arg)]; !ok && !d.isNotNullColumn(arg) {
+				return fmt.Sprintf("'%s' requires COUNT of its argument", field.Text)
+			}
+		default:
+			return fmt.Sprintf("aggregate function '%s' isn't supported", field.Agg.F)
+		}
+	}
+	return ""
+}
+
+// countOf returns the offset of the field which counts the not null values of the argument of SUM.
+func (d *Definition) countOf(sum *ast.AggregateFuncExpr) int {
+	arg := ExprText(sum.Args[0])
+	countAll := -1
+	for i, field := range d.Fields {
+		if field.Group >= 0 || field.Agg == nil || field.Agg.Distinct || strings.ToLower(field.Agg.F) != ast.AggFuncCount {
+			continue
+		}
+		if ExprText(field.Agg.Args[0]) == arg {
+			return i
+		}
+		if countAll < 0 && isCountAll(field.Agg) {
+			countAll = i
+		}
+	}
+	return countAll
+}
+
+func (d *Definition) isNotNullColumn(expr ast.ExprNode) bool {
+	col, ok := unwrapParentheses(expr).(*ast.ColumnNameExpr)
+	if !ok {
+		return false
+	}
+	colInfo := model.FindColumnInfo(d.Table.Columns, col.Name.Name.L)
+	return colInfo != nil && mysql.HasNotNullFlag(colInfo.GetFlag())
+}
+
+// DeltaSelect returns the query which aggregates the changes in the log table. The result has the same fields
+// as the view, each COUNT and SUM is the change of the field and the fields are named by DeltaColumn.
+func (d *Definition) DeltaSelect(logTable *ast.TableName) (string, error) {
+	stmt, err := parser.New().ParseOneStmt(d.sql, "", "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	sel := stmt.(*ast.SelectStmt)
+	sel.From.TableRefs.Left = &ast.TableSource{Source: logTable}
+	sel.Accept(&qualifierStripper{})
+	sign := func() ast.ExprNode {
+		return &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.NewCIStr(SignColumn)}}
+	}
+	for i, f := range sel.Fields.Fields {
+		f.AsName = model.NewCIStr(DeltaColumn(i))
+		agg, ok := unwrapParentheses(f.Expr).(*ast.AggregateFuncExpr)
+		if !ok || d.Fields[i].Group >= 0 {
+			continue
+		}
+		arg := agg.Args[0]
+		switch {
+		case strings.ToLower(agg.F) == ast.AggFuncSum:
+			arg = &ast.BinaryOperationExpr{Op: opcode.Mul, L: sign(), R: arg}
+		case isCountAll(agg):
+			arg = sign()
+		default:
+			arg = &ast.FuncCallExpr{
+				FnName: model.NewCIStr(ast.If),
+				Args:   []ast.ExprNode{&ast.IsNullExpr{Expr: arg}, ast.NewValueExpr(0, "", ""), sign()},
+			}
+		}
+		f.Expr = &ast.AggregateFuncExpr{F: ast.AggFuncSum, Args: []ast.ExprNode{arg}}
+	}
+	if sel.GroupBy != nil {
+		items := make([]*ast.ByItem, 0, len(sel.GroupBy.Items))
+		for i, field := range d.Fields {
+			if field.Group >= 0 {
+				items = append(items, &ast.ByItem{Expr: &ast.PositionExpr{N: i + 1}, NullOrder: true})
+			}
+		}
+		sel.GroupBy.Items = items
+	}
+	sel.OrderBy = nil
+	return restore(sel)
+}
+
+// DeltaColumn returns the name of the i-th field of the delta query.
+func DeltaColumn(i int) string {
+	return fmt.Sprintf("%s%d", deltaColumnPrefix, i)
+}
+
+// ExprText returns the canonical text of an expression, which is used to match the expressions of the queries
+// and the views. The names are case-insensitive and the columns are not qualified by the table names.
+func ExprText(expr ast.ExprNode) string {
+	var sb strings.Builder
+	flags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameLowercase |
+		format.RestoreNameBackQuotes | format.RestoreWithoutSchemaName | format.RestoreWithoutTableName
+	if err := expr.Restore(format.NewRestoreCtx(flags, &sb)); err != nil {
+		// Every expression can be restored, an unexpected error makes the expression match nothing.
+		return fmt.Sprintf("<%p>", expr)
+	}
+	return sb.String()
+}
+
+func restore(node ast.Node) (string, error) {
+	var sb strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+func isCountAll(agg *ast.AggregateFuncExpr) bool {
+	if strings.ToLower(agg.F) != ast.AggFuncCount || agg.Distinct {
+		return false
+	}
+	v, ok := agg.Args[0].(ast.ValueExpr)
+	return ok && v.GetValue() != nil
+}
+
+func unwrapParentheses(expr ast.ExprNode) ast.ExprNode {
+	for {
+		p, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.Expr
+	}
+}
+
+// singleTable returns the table the query reads if it reads exactly one table.
+func singleTable(sel *ast.SelectStmt) (*ast.TableName, string) {
+	switch {
+	case sel.With != nil:
+		return nil, "the query has a WITH clause"
+	case sel.From == nil:
+		return nil, "the query reads no table"
+	case len(sel.WindowSpecs) > 0:
+		return nil, "the query has a WINDOW clause"
+	case sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone:
+		return nil, "the query locks rows"
+	case sel.SelectIntoOpt != nil:
+		return nil, "the query has an INTO clause"
+	}
+	join := sel.From.TableRefs
+	if join == nil || join.Right != nil {
+		return nil, "the query reads more than one table"
+	}
+	ts, ok := join.Left.(*ast.TableSource)
+	if !ok {
+		return nil, "the query reads more than one table"
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return nil, "the query reads a derived table"
+	}
+	if len(tn.PartitionNames) > 0 || tn.TableSample != nil || tn.AsOf != nil {
+		return nil, "the query reads a part or a snapshot of the table"
+	}
+	return tn, ""
+}
+
+// checkExprs checks whether the result of the expressions only depends on the rows of the table.
+func checkExprs(sel *ast.SelectStmt) string {
+	c := &exprChecker{}
+	for _, f := range sel.Fields.Fields {
+		if f.Expr != nil {
+			f.Expr.Accept(c)
+		}
+	}
+	for _, expr := range []ast.ExprNode{sel.Where, havingExpr(sel)} {
+		if expr != nil {
+			expr.Accept(c)
+		}
+	}
+	if sel.GroupBy != nil {
+		for _, item := range sel.GroupBy.Items {
+			item.Expr.Accept(c)
+		}
+	}
+	return c.reason
+}
+
+func havingExpr(sel *ast.SelectStmt) ast.ExprNode {
+	if sel.Having == nil {
+		return nil
+	}
+	return sel.Having.Expr
+}
+
+type exprChecker struct {
+	reason string
+}
+
+func (c *exprChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SubqueryExpr:
+		c.reason = "the query has a subquery"
+	case *ast.WindowFuncExpr:
+		c.reason = "the query has a window function"
+	case *ast.VariableExpr:
+		c.reason = "the query refers to a variable"
+	case ast.ParamMarkerExpr:
+		c.reason = "the query has a parameter"
+	case *ast.DefaultExpr, *ast.ValuesExpr:
+		c.reason = fmt.Sprintf("the query has '%s'", ExprText(x.(ast.ExprNode)))
+	case *ast.FuncCallExpr:
+		if _, ok := expression.IllegalFunctions4GeneratedColumns[x.FnName.L]; ok {
+			c.reason = fmt.Sprintf("function '%s' isn't deterministic", x.FnName.O)
+		}
+	}
+	return in, c.reason != ""
+}
+
+func (c *exprChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.reason == ""
+}
+
+func hasAggregate(fields *ast.FieldList) bool {
+	f := &aggregateFinder{}
+	fields.Accept(f)
+	return f.found
+}
+
+type aggregateFinder struct {
+	found bool
+}
+
+func (f *aggregateFinder) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.AggregateFuncExpr:
+		f.found = true
+	case *ast.SubqueryExpr:
+		return in, true
+	}
+	return in, f.found
+}
+
+func (f *aggregateFinder) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// referredColumns returns the offsets of the table columns referred by the query.
+func referredColumns(sel *ast.SelectStmt, tblInfo *model.TableInfo, fields []*Field) ([]int, string) {
+	aliases := make(map[string]struct{}, len(fields))
+	for _, f := range sel.Fields.Fields {
+		if f.AsName.L != "" {
+			aliases[f.AsName.L] = struct{}{}
+		}
+	}
+	c := &columnCollector{tblInfo: tblInfo, offsets: make(map[int]struct{})}
+	for _, f := range sel.Fields.Fields {
+		f.Expr.Accept(c)
+	}
+	if sel.Where != nil {
+		sel.Where.Accept(c)
+	}
+	// The unknown columns of GROUP BY and HAVING may refer to the aliases of the select list.
+	c.aliases = aliases
+	if sel.GroupBy != nil {
+		for _, item := range sel.GroupBy.Items {
+			item.Expr.Accept(c)
+		}
+	}
+	if sel.Having != nil {
+		sel.Having.Expr.Accept(c)
+	}
+	if c.reason != "" {
+		return nil, c.reason
+	}
+	offsets := make([]int, 0, len(c.offsets))
+	for i := range tblInfo.Columns {
+		if _, ok := c.offsets[i]; ok {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets, ""
+}
+
+type columnCollector struct {
+	tblInfo *model.TableInfo
+	aliases map[string]struct{}
+	offsets map[int]struct{}
+	reason  string
+}
+
+func (c *columnCollector) Enter(in ast.Node) (ast.Node, bool) {
+	col, ok := in.(*ast.ColumnNameExpr)
+	if !ok {
+		return in, c.reason != ""
+	}
+	colInfo := model.FindColumnInfo(c.tblInfo.Columns, col.Name.Name.L)
+	if colInfo == nil || colInfo.Hidden {
+		if _, ok := c.aliases[col.Name.Name.L]; !ok || col.Name.Table.L != "" {
+			c.reason = fmt.Sprintf("unknown column '%s'", col.Name.Name.O)
+		}
+		return in, true
+	}
+	c.offsets[colInfo.Offset] = struct{}{}
+	return in, true
+}
+
+func (c *columnCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.reason == ""
+}
+
+// qualifierStripper removes the schema and table names of the columns, so the columns can be read from
+// another table with the same column names.
+type qualifierStripper struct{}
+
+func (*qualifierStripper) Enter(in ast.Node) (ast.Node, bool) {
+	if col, ok := in.(*ast.ColumnName); ok {
+		col.Schema, col.Table = model.CIStr{}, model.CIStr{}
+	}
+	return in, false
+}
+
+func (*qualifierStripper) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func mockInfoSchema() infoschema.InfoSchema {
+	newCol := func(offset int, name string, tp byte, notNull bool) *model.ColumnInfo {
+		ft := types.NewFieldType(tp)
+		if notNull {
+			ft.AddFlag(mysql.NotNullFlag)
+		}
+		return &model.ColumnInfo{ID: int64(offset + 1), Offset: offset, Name: model.NewCIStr(name), FieldType: *ft, State: model.StatePublic}
+	}
+	t := &model.TableInfo{
+		ID:   100,
+		Name: model.NewCIStr("t"),
+		Columns: []*model.ColumnInfo{
+			newCol(0, "a", mysql.TypeLong, true),
+			newCol(1, "b", mysql.TypeLong, false),
+			newCol(2, "c", mysql.TypeVarchar, false),
+		},
+		State: model.StatePublic,
+	}
+	s := t.Clone()
+	s.ID, s.Name = 101, model.NewCIStr("s")
+	return infoschema.MockInfoSchema([]*model.TableInfo{t, s})
+}
+
+func TestAnalyze(t *testing.T) {
+	is := mockInfoSchema()
+	def, err := Analyze(is, "SELECT `test`.`t`.`c` AS `c`, COUNT(1) AS `cnt`, SUM(`test`.`t`.`a`) AS `s` FROM `test`.`t` WHERE `test`.`t`.`b` > 1 GROUP BY `c`")
+	require.NoError(t, err)
+	require.Equal(t, "", def.Reason())
+	require.Equal(t, int64(100), def.Table.ID)
+	require.True(t, def.Aggregated)
+	require.True(t, def.GroupInSelect)
+	require.Len(t, def.Fields, 3)
+	require.Equal(t, 0, def.Fields[0].Group)
+	require.Equal(t, -1, def.Fields[1].Group)
+	require.NotNil(t, def.Fields[2].Agg)
+	require.Equal(t, []string{def.Fields[0].Text}, def.GroupBy)
+	require.Equal(t, []int{0, 1, 2}, def.Columns)
+	require.Equal(t, "", def.FastRefreshUnsupportedReason())
+
+	delta, err := def.DeltaSelect(&ast.TableName{Schema: model.NewCIStr("test"), Name: LogTableName(model.NewCIStr("mv"))})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT `c` AS `_c0`,SUM(`_tidb_mlog_sign`) AS `_c1`,SUM(`_tidb_mlog_sign`*`a`) AS `_c2` FROM `test`.`mlog$_mv` WHERE `b`>1 GROUP BY 1", delta)
+
+	for _, ca := range []struct {
+		sql    string
+		reason string
+	}{
+		{"SELECT `test`.`t`.`a` AS `a` FROM `test`.`t`, `test`.`s`", "the query reads more than one table"},
+		{"SELECT `a` FROM `test`.`t` UNION SELECT `a` FROM `test`.`s`", "the query is a set operation"},
+		{"SELECT * FROM `test`.`t`", "the select list contains a wildcard"},
+		{"SELECT `a` FROM `test`.`nt`", "table test.nt doesn't exist"},
+	} {
+		def, err = Analyze(is, ca.sql)
+		require.NoError(t, err, ca.sql)
+		require.Nil(t, def.Table, ca.sql)
+		require.Equal(t, ca.reason, def.Reason(), ca.sql)
+		require.Equal(t, ca.reason, def.FastRefreshUnsupportedReason(), ca.sql)
+	}
+}
+
+func TestFastRefreshUnsupportedReason(t *testing.T) {
+	is := mockInfoSchema()
+	for _, ca := range []struct {
+		sql    string
+		reason string
+	}{
+		{"SELECT `c`, COUNT(*), SUM(`a`) FROM `test`.`t` GROUP BY `c`", ""},
+		{"SELECT COUNT(*), SUM(`b`), COUNT(`b`) FROM `test`.`t`", ""},
+		{"SELECT `a`, `b` FROM `test`.`t`", "the query has no aggregation"},
+		{"SELECT `c`, COUNT(*) FROM `test`.`t` GROUP BY `c` HAVING COUNT(*) > 1", "the query has a HAVING clause"},
+		{"SELECT `c`, COUNT(*) FROM `test`.`t` GROUP BY `c` LIMIT 1", "the query has a LIMIT clause"},
+		{"SELECT COUNT(*) FROM `test`.`t` GROUP BY `c`", "a GROUP BY item isn't in the select list"},
+		{"SELECT `c`, SUM(`a`) FROM `test`.`t` GROUP BY `c`", "the query has no COUNT(*)"},
+		{"SELECT `c`, COUNT(*), SUM(`b`) FROM `test`.`t` GROUP BY `c`", "'sum(`b`)' requires COUNT of its argument"},
+		{"SELECT `c`, COUNT(*), MAX(`a`) FROM `test`.`t` GROUP BY `c`", "aggregate function 'MAX' isn't supported"},
+		{"SELECT `c`, COUNT(*), COUNT(DISTINCT `a`) FROM `test`.`t` GROUP BY `c`", "'count(distinct `a`)' has DISTINCT"},
+	} {
+		def, err := Analyze(is, ca.sql)
+		require.NoError(t, err, ca.sql)
+		require.Equal(t, ca.reason, def.FastRefreshUnsupportedReason(), ca.sql)
+	}
+}
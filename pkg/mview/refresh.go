@@ -0,0 +1,244 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+	"go.uber.org/zap"
+)
+
+// RefreshStatusTable is the system table which records the last refresh of the materialized views.
+const RefreshStatusTable = "tidb_mview_refresh"
+
+// Refresh refreshes a materialized view and returns the method it's refreshed by.
+// A COMPLETE refresh recomputes the view, while a FAST refresh applies the changes logged since the last
+// refresh. A FAST refresh falls back to COMPLETE if the view hasn't been refreshed since the base table is
+// created or truncated, because the log doesn't contain all the changes in this case.
+// The session must be an internal session which isn't in a transaction.
+func Refresh(ctx context.Context, sctx sessionctx.Context, schema model.CIStr, mv *model.TableInfo, method model.MaterializedViewRefreshMethod) (model.MaterializedViewRefreshMethod, error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnMView)
+	info := mv.MaterializedView
+	if method == model.RefreshMethodUnspecified {
+		method = info.RefreshMethod
+	}
+	def, err := Analyze(sctx.GetDomainInfoSchema().(infoschema.InfoSchema), info.Definition)
+	if err != nil {
+		return method, err
+	}
+	if method == model.RefreshMethodFast {
+		reason := def.FastRefreshUnsupportedReason()
+		if reason == "" && info.LogTable.L == "" {
+			reason = "it has no log table"
+		}
+		if reason != "" {
+			return method, dbterror.ErrMViewNotFastRefreshable.GenWithStackByArgs(mv.Name.O, reason)
+		}
+	}
+
+	r := &refresher{
+		exec: sctx.GetSQLExecutor(),
+		def:  def,
+		info: mv,
+		mv:   quoteName(schema, mv.Name),
+	}
+	if info.LogTable.L != "" {
+		r.logTable = &ast.TableName{Schema: schema, Name: info.LogTable}
+	}
+	if method, err = r.refresh(ctx, method); err != nil {
+		r.recordError(ctx, err)
+	}
+	return method, err
+}
+
+// DeleteRefreshStatus deletes the refresh status of a dropped materialized view.
+func DeleteRefreshStatus(ctx context.Context, exec sqlexec.SQLExecutor, mvID int64) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnMView)
+	_, err := sqlexec.ExecSQL(ctx, exec, "DELETE FROM %n.%n WHERE table_id = %?", mysql.SystemDB, RefreshStatusTable, mvID)
+	return err
+}
+
+type refresher struct {
+	exec     sqlexec.SQLExecutor
+	def      *Definition
+	info     *model.TableInfo
+	mv       string
+	logTable *ast.TableName
+}
+
+func (r *refresher) refresh(ctx context.Context, method model.MaterializedViewRefreshMethod) (_ model.MaterializedViewRefreshMethod, err error) {
+	// The refresh reads the view, the base table and the log at the same snapshot. An optimistic transaction
+	// is used because the statements of a pessimistic transaction read the latest data, which may delete
+	// the logged changes that committed after the changes are applied.
+	if _, err = r.execSQL(ctx, "BEGIN OPTIMISTIC"); err != nil {
+		return method, err
+	}
+	defer func() {
+		if err != nil {
+			_, rollbackErr := r.execSQL(ctx, "ROLLBACK")
+			terror.Log(rollbackErr)
+		}
+	}()
+
+	rows, err := r.execSQL(ctx, "SELECT @@tidb_current_ts")
+	if err != nil {
+		return method, err
+	}
+	tso, err := strconv.ParseUint(rows[0].GetString(0), 10, 64)
+	if err != nil {
+		return method, errors.Trace(err)
+	}
+	if method == model.RefreshMethodFast {
+		rows, err = r.execSQL(ctx, fmt.Sprintf("SELECT base_table_id FROM %s WHERE table_id = %d",
+			quoteName(model.NewCIStr(mysql.SystemDB), model.NewCIStr(RefreshStatusTable)), r.info.ID))
+		if err != nil {
+			return method, err
+		}
+		if len(rows) == 0 || rows[0].IsNull(0) || rows[0].GetInt64(0) != r.def.Table.ID {
+			method = model.RefreshMethodComplete
+		}
+	}
+
+	var stmts []string
+	if method == model.RefreshMethodFast {
+		stmts, err = r.fastRefreshStmts()
+	} else {
+		stmts, err = r.completeRefreshStmts()
+	}
+	if err != nil {
+		return method, err
+	}
+	if r.logTable != nil {
+		stmts = append(stmts, "DELETE FROM "+quoteName(r.logTable.Schema, r.logTable.Name))
+	}
+	baseTableID := "NULL"
+	if r.def.Table != nil {
+		baseTableID = strconv.FormatInt(r.def.Table.ID, 10)
+	}
+	stmts = append(stmts, fmt.Sprintf("REPLACE INTO %s (table_id, base_table_id, last_refresh_tso, last_refresh_time, last_refresh_method, last_error) VALUES (%d, %s, %d, NOW(), '%s', NULL)",
+		quoteName(model.NewCIStr(mysql.SystemDB), model.NewCIStr(RefreshStatusTable)), r.info.ID, baseTableID, tso, method))
+	stmts = append(stmts, "COMMIT")
+	for _, stmt := range stmts {
+		if _, err = r.execSQL(ctx, stmt); err != nil {
+			return method, err
+		}
+	}
+	return method, nil
+}
+
+func (r *refresher) completeRefreshStmts() ([]string, error) {
+	cols := make([]string, 0, len(r.info.Columns))
+	for _, col := range r.info.Columns {
+		cols = append(cols, quote(col.Name.O))
+	}
+	return []string{
+		"DELETE FROM " + r.mv,
+		fmt.Sprintf("INSERT INTO %s (%s) %s", r.mv, strings.Join(cols, ", "), r.info.MaterializedView.Definition),
+	}, nil
+}
+
+// fastRefreshStmts returns the statements which add the aggregated changes to the view. The SUM fields
+// are updated before the COUNT fields they depend on, and the groups whose COUNT(*) becomes 0 are deleted.
+func (r *refresher) fastRefreshStmts() ([]string, error) {
+	delta, err := r.def.DeltaSelect(r.logTable)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		cols, groupCond, insertExprs, sums, counts []string
+		countAll                                   = -1
+	)
+	for i, field := range r.def.Fields {
+		col, d := quote(r.info.Columns[i].Name.O), "d."+quote(DeltaColumn(i))
+		cols = append(cols, col)
+		switch {
+		case field.Group >= 0:
+			groupCond = append(groupCond, fmt.Sprintf("m.%s <=> %s", col, d))
+			insertExprs = append(insertExprs, d)
+		case strings.ToLower(field.Agg.F) == ast.AggFuncCount:
+			if countAll < 0 && isCountAll(field.Agg) {
+				countAll = i
+			}
+			counts = append(counts, fmt.Sprintf("m.%s = m.%s + %s", col, col, d))
+			insertExprs = append(insertExprs, d)
+		default:
+			c := r.def.countOf(field.Agg)
+			mc, dc := "m."+quote(r.info.Columns[c].Name.O), "d."+quote(DeltaColumn(c))
+			sums = append(sums, fmt.Sprintf("m.%s = IF(%s + %s = 0, NULL, IFNULL(m.%s, 0) + IFNULL(%s, 0))", col, mc, dc, col, d))
+			insertExprs = append(insertExprs, fmt.Sprintf("IF(%s = 0, NULL, %s)", dc, d))
+		}
+	}
+
+	where := ""
+	if len(groupCond) > 0 {
+		where = " WHERE " + strings.Join(groupCond, " AND ")
+	}
+	var stmts []string
+	for _, set := range [][]string{sums, counts} {
+		if len(set) > 0 {
+			stmts = append(stmts, fmt.Sprintf("UPDATE %s AS m, (%s) AS d SET %s%s", r.mv, delta, strings.Join(set, ", "), where))
+		}
+	}
+	if len(groupCond) == 0 {
+		// The view without GROUP BY always has one row.
+		return stmts, nil
+	}
+	countAllCol := quote(r.info.Columns[countAll].Name.O)
+	stmts = append(stmts,
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM (%s) AS d WHERE d.%s > 0 AND NOT EXISTS (SELECT 1 FROM %s AS m%s)",
+			r.mv, strings.Join(cols, ", "), strings.Join(insertExprs, ", "), delta, quote(DeltaColumn(countAll)), r.mv, where),
+		fmt.Sprintf("DELETE FROM %s WHERE %s = 0", r.mv, countAllCol),
+	)
+	return stmts, nil
+}
+
+// recordError records the error of the last refresh, the status of the last successful refresh is kept.
+func (r *refresher) recordError(ctx context.Context, refreshErr error) {
+	_, err := sqlexec.ExecSQL(ctx, r.exec, "INSERT INTO %n.%n (table_id, last_error) VALUES (%?, %?) ON DUPLICATE KEY UPDATE last_error = VALUES(last_error)",
+		mysql.SystemDB, RefreshStatusTable, r.info.ID, refreshErr.Error())
+	if err != nil {
+		logutil.Logger(ctx).Warn("record the error of materialized view refresh failed",
+			zap.Int64("tableID", r.info.ID), zap.Error(err))
+	}
+}
+
+// execSQL executes a statement which has been built completely.
+func (r *refresher) execSQL(ctx context.Context, sql string) ([]chunk.Row, error) {
+	return sqlexec.ExecSQL(ctx, r.exec, strings.ReplaceAll(sql, "%", "%%"))
+}
+
+func quote(name string) string {
+	return stringutil.Escape(name, mysql.ModeNone)
+}
+
+func quoteName(schema, name model.CIStr) string {
+	return quote(schema.O) + "." + quote(name.O)
+}
@@ -0,0 +1,296 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+type rewriteMode int
+
+const (
+	// rewriteColumns reads the rows of a view without aggregation.
+	rewriteColumns rewriteMode = iota
+	// rewriteGroups reads the rows of an aggregated view which has the same groups as the query.
+	rewriteGroups
+	// rewriteRollup aggregates the rows of an aggregated view whose groups are finer than the query.
+	rewriteRollup
+)
+
+// Rewrite rewrites a query to read the materialized view mv instead of the base table. It returns nil if the
+// result of the query can't be derived from the rows of the view. The query is analyzed by AnalyzeSelect and
+// the view by Analyze, the query isn't changed.
+func Rewrite(query, view *Definition, schema model.CIStr, mv *model.TableInfo) (*ast.SelectStmt, error) {
+	if query.Table == nil || view.Table == nil || query.Table.ID != view.Table.ID || query.Where != view.Where ||
+		len(view.Fields) != len(mv.Columns) || !query.qualifiersMatch() {
+		return nil, nil
+	}
+	vsel, qsel := view.Select, query.Select
+	if vsel.Distinct || vsel.Limit != nil || vsel.Having != nil || vsel.GroupBy != nil && vsel.GroupBy.Rollup ||
+		qsel.GroupBy != nil && qsel.GroupBy.Rollup {
+		return nil, nil
+	}
+
+	r := &rewriter{
+		exprs:    make(map[string]int, len(view.Fields)),
+		aggs:     make(map[string]int, len(view.Fields)),
+		replaced: make(map[*ast.ColumnNameExpr]struct{}),
+		mv:       mv,
+	}
+	switch {
+	case !view.Aggregated:
+		r.mode = rewriteColumns
+	case !query.Aggregated || !view.GroupInSelect:
+		return nil, nil
+	case sameItems(query.GroupBy, view.GroupBy):
+		r.mode = rewriteGroups
+	case containsItems(view.GroupBy, query.GroupBy):
+		r.mode = rewriteRollup
+	default:
+		return nil, nil
+	}
+	for i, field := range view.Fields {
+		switch {
+		case r.mode == rewriteColumns || field.Group >= 0:
+			r.exprs[field.Text] = i
+		case field.Agg != nil && r.mode == rewriteGroups:
+			r.exprs[field.Text] = i
+		case field.Agg != nil:
+			r.aggs[field.Text] = i
+		}
+	}
+
+	// The query is restored and parsed again to get a copy.
+	text, err := restore(qsel)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := parser.New().ParseOneStmt(text, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sel := stmt.(*ast.SelectStmt)
+	sel.From = &ast.TableRefsClause{TableRefs: &ast.Join{Left: &ast.TableSource{Source: &ast.TableName{Schema: schema, Name: mv.Name}}}}
+	sel.Where = nil
+	if r.mode == rewriteGroups {
+		// Every group of the query is a row of the view.
+		if sel.Having != nil {
+			sel.Where = sel.Having.Expr
+		}
+		sel.GroupBy, sel.Having = nil, nil
+	}
+	if !r.rewrite(sel) {
+		return nil, nil
+	}
+	// The flags of the replaced expressions are stale, e.g. an expression isn't aggregated after the
+	// aggregate functions are replaced by the columns.
+	ast.SetFlag(sel)
+	return sel, nil
+}
+
+// qualifiersMatch checks whether the qualified columns of the query are the columns of its table, a query
+// whose columns refer to an outer query can't be rewritten.
+func (d *Definition) qualifiersMatch() bool {
+	ts := d.Select.From.TableRefs.Left.(*ast.TableSource)
+	tn := ts.Source.(*ast.TableName)
+	name := ts.AsName
+	if name.L == "" {
+		name = tn.Name
+	}
+	c := &qualifierChecker{schema: tn.Schema, table: name, match: true}
+	for _, node := range selectExprNodes(d.Select) {
+		node.Accept(c)
+	}
+	return c.match
+}
+
+// selectExprNodes returns the expressions of a query except the FROM clause.
+func selectExprNodes(sel *ast.SelectStmt) []ast.Node {
+	nodes := []ast.Node{sel.Fields}
+	if sel.Where != nil {
+		nodes = append(nodes, sel.Where)
+	}
+	if sel.GroupBy != nil {
+		nodes = append(nodes, sel.GroupBy)
+	}
+	if sel.Having != nil {
+		nodes = append(nodes, sel.Having)
+	}
+	if sel.OrderBy != nil {
+		nodes = append(nodes, sel.OrderBy)
+	}
+	return nodes
+}
+
+type qualifierChecker struct {
+	schema, table model.CIStr
+	match         bool
+}
+
+func (c *qualifierChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if col, ok := in.(*ast.ColumnName); ok {
+		if col.Schema.L != "" && col.Schema.L != c.schema.L || col.Table.L != "" && col.Table.L != c.table.L {
+			c.match = false
+		}
+	}
+	return in, !c.match
+}
+
+func (c *qualifierChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.match
+}
+
+type rewriter struct {
+	mode rewriteMode
+	// exprs are the offsets of the view fields which can replace the same expressions of the query.
+	exprs map[string]int
+	// aggs are the offsets of the aggregated fields which can be aggregated again by rewriteRollup.
+	aggs     map[string]int
+	replaced map[*ast.ColumnNameExpr]struct{}
+	mv       *model.TableInfo
+	failed   bool
+}
+
+// rewrite replaces the expressions of the query by the columns of the view, it fails if any column of the
+// base table is left.
+func (r *rewriter) rewrite(sel *ast.SelectStmt) bool {
+	for _, node := range selectExprNodes(sel) {
+		node.Accept(r)
+	}
+	if r.failed {
+		return false
+	}
+	c := &leftoverChecker{replaced: r.replaced, noAggregate: r.mode == rewriteGroups}
+	for _, node := range selectExprNodes(sel) {
+		node.Accept(c)
+	}
+	return !c.found
+}
+
+func (r *rewriter) column(offset int) *ast.ColumnNameExpr {
+	col := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: r.mv.Columns[offset].Name}}
+	r.replaced[col] = struct{}{}
+	return col
+}
+
+func (r *rewriter) Enter(in ast.Node) (ast.Node, bool) {
+	if r.failed {
+		return in, true
+	}
+	expr, ok := in.(ast.ExprNode)
+	if !ok {
+		return in, false
+	}
+	switch x := expr.(type) {
+	case ast.ValueExpr, ast.ParamMarkerExpr:
+		return in, true
+	case *ast.AggregateFuncExpr:
+		if r.mode == rewriteRollup {
+			return r.rollup(x), true
+		}
+	}
+	if offset, ok := r.exprs[ExprText(expr)]; ok {
+		return r.column(offset), true
+	}
+	return in, false
+}
+
+func (*rewriter) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// rollup aggregates an aggregated field of the view again.
+func (r *rewriter) rollup(agg *ast.AggregateFuncExpr) ast.ExprNode {
+	offset, ok := r.aggs[ExprText(agg)]
+	if !ok || agg.Distinct {
+		r.failed = true
+		return agg
+	}
+	col := r.column(offset)
+	switch strings.ToLower(agg.F) {
+	case ast.AggFuncCount:
+		// COUNT is the sum of the counts, which is 0 rather than NULL if there is no row.
+		expr, err := parseExpr("CAST(0 AS SIGNED)")
+		if err != nil {
+			r.failed = true
+			return agg
+		}
+		cast := expr.(*ast.FuncCastExpr)
+		cast.Expr = &ast.FuncCallExpr{
+			FnName: model.NewCIStr(ast.Coalesce),
+			Args:   []ast.ExprNode{&ast.AggregateFuncExpr{F: ast.AggFuncSum, Args: []ast.ExprNode{col}}, ast.NewValueExpr(0, "", "")},
+		}
+		return cast
+	case ast.AggFuncSum, ast.AggFuncMax, ast.AggFuncMin:
+		return &ast.AggregateFuncExpr{F: agg.F, Args: []ast.ExprNode{col}}
+	}
+	r.failed = true
+	return agg
+}
+
+// leftoverChecker finds the columns which are not replaced by the columns of the view.
+type leftoverChecker struct {
+	replaced    map[*ast.ColumnNameExpr]struct{}
+	noAggregate bool
+	found       bool
+}
+
+func (c *leftoverChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnNameExpr:
+		if _, ok := c.replaced[x]; !ok {
+			c.found = true
+		}
+	case *ast.AggregateFuncExpr:
+		c.found = c.found || c.noAggregate
+	}
+	return in, c.found
+}
+
+func (c *leftoverChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.found
+}
+
+// parseExpr parses an expression which is built by the rewrite.
+func parseExpr(expr string) (ast.ExprNode, error) {
+	stmt, err := parser.New().ParseOneStmt("SELECT "+expr, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stmt.(*ast.SelectStmt).Fields.Fields[0].Expr, nil
+}
+
+func sameItems(a, b []string) bool {
+	return containsItems(a, b) && containsItems(b, a)
+}
+
+// containsItems checks whether every item of b is in a.
+func containsItems(a, b []string) bool {
+	set := make(map[string]struct{}, len(a))
+	for _, item := range a {
+		set[item] = struct{}{}
+	}
+	for _, item := range b {
+		if _, ok := set[item]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite(t *testing.T) {
+	is := mockInfoSchema()
+	schema := model.NewCIStr("test")
+	mvOf := func(def *Definition) *model.TableInfo {
+		mv := &model.TableInfo{ID: 200, Name: model.NewCIStr("mv")}
+		for i := range def.Fields {
+			mv.Columns = append(mv.Columns, &model.ColumnInfo{Name: model.NewCIStr(DeltaColumn(i))})
+		}
+		return mv
+	}
+	for _, ca := range []struct {
+		view   string
+		query  string
+		result string
+	}{
+		// The view without aggregation.
+		{
+			"SELECT `a`, `b` + 1 FROM `test`.`t` WHERE `c` = 'x'",
+			"SELECT `b` + 1, `a` FROM `test`.`t` WHERE `c` = 'x' ORDER BY `a`",
+			"SELECT `_c1`,`_c0` FROM `test`.`mv` ORDER BY `_c0`",
+		},
+		{
+			"SELECT `a`, `b` + 1 FROM `test`.`t` WHERE `c` = 'x'",
+			"SELECT MAX(`b` + 1) FROM `test`.`t` AS `x` WHERE `x`.`c` = 'x' GROUP BY `x`.`a`",
+			"SELECT MAX(`_c1`) FROM `test`.`mv` GROUP BY `_c0`",
+		},
+		// The view has the same groups as the query.
+		{
+			"SELECT `c`, COUNT(*), SUM(`a`) FROM `test`.`t` GROUP BY `c`",
+			"SELECT SUM(`a`) FROM `test`.`t` GROUP BY `c` HAVING COUNT(*) > 1",
+			"SELECT `_c2` FROM `test`.`mv` WHERE `_c1`>1",
+		},
+		// The groups of the view are finer than the query.
+		{
+			"SELECT `c`, `b`, COUNT(*), SUM(`a`) FROM `test`.`t` GROUP BY `c`, `b`",
+			"SELECT `c`, COUNT(*), SUM(`a`) FROM `test`.`t` GROUP BY `c`",
+			"SELECT `_c0`,CAST(COALESCE(SUM(`_c2`), 0) AS SIGNED),SUM(`_c3`) FROM `test`.`mv` GROUP BY `_c0`",
+		},
+		// The query reads a column which isn't in the view.
+		{
+			"SELECT `a` FROM `test`.`t`",
+			"SELECT `a`, `b` FROM `test`.`t`",
+			"",
+		},
+		// The query has a different WHERE clause.
+		{
+			"SELECT `a` FROM `test`.`t` WHERE `b` > 1",
+			"SELECT `a` FROM `test`.`t` WHERE `b` > 2",
+			"",
+		},
+		// The query reads another table.
+		{
+			"SELECT `a` FROM `test`.`t`",
+			"SELECT `a` FROM `test`.`s`",
+			"",
+		},
+		// The groups of the query are finer than the view.
+		{
+			"SELECT `c`, COUNT(*) FROM `test`.`t` GROUP BY `c`",
+			"SELECT `c`, `b`, COUNT(*) FROM `test`.`t` GROUP BY `c`, `b`",
+			"",
+		},
+		// AVG can't be derived from the aggregated rows.
+		{
+			"SELECT `c`, `b`, AVG(`a`) FROM `test`.`t` GROUP BY `c`, `b`",
+			"SELECT `c`, AVG(`a`) FROM `test`.`t` GROUP BY `c`",
+			"",
+		},
+		// The column refers to an outer query.
+		{
+			"SELECT `a` FROM `test`.`t`",
+			"SELECT `o`.`a` FROM `test`.`t`",
+			"",
+		},
+	} {
+		view, err := Analyze(is, ca.view)
+		require.NoError(t, err)
+		stmt, err := parser.New().ParseOneStmt(ca.query, "", "")
+		require.NoError(t, err)
+		query := AnalyzeSelect(is, stmt.(*ast.SelectStmt))
+		rewritten, err := Rewrite(query, view, schema, mvOf(view))
+		require.NoError(t, err)
+		if ca.result == "" {
+			require.Nil(t, rewritten, ca.query)
+			continue
+		}
+		require.NotNil(t, rewritten, ca.query)
+		result, err := restore(rewritten)
+		require.NoError(t, err)
+		require.Equal(t, ca.result, result, ca.query)
+	}
+}
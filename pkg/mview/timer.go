@@ -0,0 +1,296 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	timerapi "github.com/pingcap/tidb/pkg/timer/api"
+	timerrt "github.com/pingcap/tidb/pkg/timer/runtime"
+	"github.com/pingcap/tidb/pkg/timer/tablestore"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	timerKeyPrefix = "/tidb/mview/"
+	timerHookClass = "tidb.mview"
+)
+
+// syncTimersInterval is the interval to sync the timers with the materialized views, it's a variable for test.
+var syncTimersInterval = 10 * time.Second
+
+// timerData is the data stored in the timer of a materialized view.
+type timerData struct {
+	SchemaID int64 `json:"schema_id"`
+	TableID  int64 `json:"table_id"`
+}
+
+// CheckRefreshInterval checks whether the refresh interval of a materialized view is valid.
+func CheckRefreshInterval(interval string) error {
+	_, err := timerapi.NewSchedIntervalPolicy(interval)
+	return err
+}
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// RefreshManager runs the scheduled refresh of the materialized views which have a refresh interval.
+// Each view has a timer of the timer framework, the timers are synced with the views and run on the DDL owner.
+type RefreshManager struct {
+	pool    sessionPool
+	store   *timerapi.TimerStore
+	cli     timerapi.TimerClient
+	isOwner func() bool
+	rt      *timerrt.TimerGroupRuntime
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRefreshManager creates a RefreshManager.
+func NewRefreshManager(pool sessionPool, etcdCli *clientv3.Client, isOwner func() bool) *RefreshManager {
+	store := tablestore.NewTableTimerStore(1, pool, "mysql", "tidb_timers", etcdCli)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RefreshManager{
+		pool:    pool,
+		store:   store,
+		cli:     timerapi.NewDefaultTimerClient(store),
+		isOwner: isOwner,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start starts the manager.
+func (m *RefreshManager) Start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop stops the manager and waits for the running refreshes.
+func (m *RefreshManager) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *RefreshManager) loop() {
+	defer func() {
+		m.pause()
+		m.store.Close()
+		m.wg.Done()
+	}()
+	ticker := time.NewTicker(syncTimersInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if m.isOwner == nil || !m.isOwner() {
+			m.pause()
+			continue
+		}
+		if err := m.syncTimers(m.ctx); err != nil {
+			logutil.BgLogger().Warn("sync materialized view timers failed", zap.Error(err))
+		}
+		m.resume()
+	}
+}
+
+func (m *RefreshManager) resume() {
+	if m.rt != nil {
+		return
+	}
+	m.rt = timerrt.NewTimerRuntimeBuilder("mview", m.store).
+		SetCond(&timerapi.TimerCond{Key: timerapi.NewOptionalVal(timerKeyPrefix), KeyPrefix: true}).
+		RegisterHookFactory(timerHookClass, func(_ string, cli timerapi.TimerClient) timerapi.Hook {
+			return newRefreshHook(m.pool, cli)
+		}).
+		Build()
+	m.rt.Start()
+}
+
+func (m *RefreshManager) pause() {
+	if rt := m.rt; rt != nil {
+		m.rt = nil
+		rt.Stop()
+	}
+}
+
+// syncTimers creates, updates and deletes the timers according to the refresh intervals of the views.
+func (m *RefreshManager) syncTimers(ctx context.Context) error {
+	is, err := m.infoSchema()
+	if err != nil {
+		return err
+	}
+	timers, err := m.cli.GetTimers(ctx, timerapi.WithKeyPrefix(timerKeyPrefix))
+	if err != nil {
+		return err
+	}
+	key2Timers := make(map[string]*timerapi.TimerRecord, len(timers))
+	for _, timer := range timers {
+		key2Timers[timer.Key] = timer
+	}
+
+	for _, db := range is.AllSchemas() {
+		for _, tblInfo := range is.SchemaTableInfos(db.Name) {
+			if tblInfo.State != model.StatePublic || !tblInfo.IsMaterializedView() || tblInfo.MaterializedView.RefreshInterval == "" {
+				continue
+			}
+			key := buildTimerKey(tblInfo.ID)
+			timer, ok := key2Timers[key]
+			delete(key2Timers, key)
+			if err = m.syncTimer(ctx, timer, ok, db, tblInfo); err != nil {
+				logutil.BgLogger().Warn("sync materialized view timer failed", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	for _, timer := range key2Timers {
+		if _, err = m.cli.DeleteTimer(ctx, timer.ID); err != nil {
+			logutil.BgLogger().Warn("delete materialized view timer failed", zap.String("timerID", timer.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (m *RefreshManager) syncTimer(ctx context.Context, timer *timerapi.TimerRecord, exists bool, db *model.DBInfo, tblInfo *model.TableInfo) error {
+	interval := tblInfo.MaterializedView.RefreshInterval
+	tags := []string{fmt.Sprintf("db=%s", db.Name.O), fmt.Sprintf("table=%s", tblInfo.Name.O)}
+	if !exists {
+		data, err := json.Marshal(&timerData{SchemaID: db.ID, TableID: tblInfo.ID})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// The view is refreshed when it's created, so the first scheduled refresh is after an interval.
+		_, err = m.cli.CreateTimer(ctx, timerapi.TimerSpec{
+			Key:             buildTimerKey(tblInfo.ID),
+			Tags:            tags,
+			Data:            data,
+			SchedPolicyType: timerapi.SchedEventInterval,
+			SchedPolicyExpr: interval,
+			HookClass:       timerHookClass,
+			Watermark:       time.Now(),
+			Enable:          true,
+		})
+		return err
+	}
+	if timer.SchedPolicyExpr == interval && fmt.Sprint(timer.Tags) == fmt.Sprint(tags) {
+		return nil
+	}
+	return m.cli.UpdateTimer(ctx, timer.ID,
+		timerapi.WithSetTags(tags),
+		timerapi.WithSetSchedExpr(timerapi.SchedEventInterval, interval),
+	)
+}
+
+func (m *RefreshManager) infoSchema() (infoschema.InfoSchema, error) {
+	resource, err := m.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer m.pool.Put(resource)
+	return resource.(sessionctx.Context).GetDomainInfoSchema().(infoschema.InfoSchema), nil
+}
+
+func buildTimerKey(tableID int64) string {
+	return timerKeyPrefix + strconv.FormatInt(tableID, 10)
+}
+
+// refreshHook refreshes the materialized view when its timer is triggered.
+type refreshHook struct {
+	pool   sessionPool
+	cli    timerapi.TimerClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newRefreshHook(pool sessionPool, cli timerapi.TimerClient) *refreshHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &refreshHook{pool: pool, cli: cli, ctx: ctx, cancel: cancel}
+}
+
+// Start implements timerapi.Hook interface.
+func (*refreshHook) Start() {}
+
+// Stop implements timerapi.Hook interface.
+func (h *refreshHook) Stop() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+// OnPreSchedEvent implements timerapi.Hook interface.
+func (*refreshHook) OnPreSchedEvent(context.Context, timerapi.TimerShedEvent) (timerapi.PreSchedEventResult, error) {
+	return timerapi.PreSchedEventResult{}, nil
+}
+
+// OnSchedEvent implements timerapi.Hook interface. The view is refreshed in background and the event is closed
+// after the refresh whether it succeeds or not, a failed refresh is recorded in the refresh status.
+func (h *refreshHook) OnSchedEvent(_ context.Context, event timerapi.TimerShedEvent) error {
+	timer := event.Timer()
+	var data timerData
+	if err := json.Unmarshal(timer.Data, &data); err != nil {
+		logutil.BgLogger().Error("invalid materialized view timer data", zap.String("key", timer.Key), zap.ByteString("data", timer.Data))
+		return err
+	}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if err := h.refresh(data); err != nil {
+			logutil.BgLogger().Warn("scheduled refresh of materialized view failed", zap.Int64("tableID", data.TableID), zap.Error(err))
+		}
+		if err := h.cli.CloseTimerEvent(h.ctx, timer.ID, event.EventID(), timerapi.WithSetWatermark(timer.EventStart)); err != nil {
+			logutil.BgLogger().Warn("close materialized view timer event failed", zap.String("timerID", timer.ID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (h *refreshHook) refresh(data timerData) error {
+	resource, err := h.pool.Get()
+	if err != nil {
+		return err
+	}
+	defer h.pool.Put(resource)
+	sctx := resource.(sessionctx.Context)
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	db, ok := is.SchemaByID(data.SchemaID)
+	if !ok {
+		return nil
+	}
+	tblInfo, ok := is.TableInfoByID(data.TableID)
+	if !ok || !tblInfo.IsMaterializedView() {
+		// The view has been dropped, its timer is deleted by the next sync.
+		return nil
+	}
+	_, err = Refresh(h.ctx, sctx, db.Name, tblInfo, model.RefreshMethodUnspecified)
+	return err
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/format"
 )
 
-var _ StmtNode = &IndexAdviseStmt{}
+var (
+	_ StmtNode = &IndexAdviseStmt{}
+	_ StmtNode = &RecommendIndexStmt{}
+)
 
 // IndexAdviseStmt is used to advise indexes
 type IndexAdviseStmt struct {
@@ -83,3 +86,31 @@ func (n *MaxIndexNumClause) Restore(ctx *format.RestoreCtx) error {
 	}
 	return nil
 }
+
+// RecommendIndexStmt is used to recommend indexes for a query or the workload in the statement summary.
+type RecommendIndexStmt struct {
+	stmtNode
+
+	// SQL is the query to recommend indexes for, the queries in the statement summary are used if it's empty.
+	SQL string
+}
+
+// Restore implements Node interface.
+func (n *RecommendIndexStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RECOMMEND INDEX RUN")
+	if n.SQL != "" {
+		ctx.WriteKeyWord(" FOR ")
+		ctx.WriteString(n.SQL)
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *RecommendIndexStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*RecommendIndexStmt)
+	return v.Leave(n)
+}
@@ -217,6 +217,8 @@ func GetStmtLabel(stmtNode StmtNode) string {
 		return "ImportInto"
 	case *LoadDataStmt:
 		return "LoadData"
+	case *MergeStmt:
+		return "Merge"
 	case *RollbackStmt:
 		return "Rollback"
 	case *SelectStmt:
@@ -245,6 +247,8 @@ func GetStmtLabel(stmtNode StmtNode) string {
 		return "CreateBinding"
 	case *IndexAdviseStmt:
 		return "IndexAdvise"
+	case *RecommendIndexStmt:
+		return "RecommendIndex"
 	case *DropBindingStmt:
 		return "DropBinding"
 	case *TraceStmt:
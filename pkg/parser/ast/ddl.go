@@ -526,6 +526,7 @@ const (
 	ColumnOptionColumnFormat
 	ColumnOptionStorage
 	ColumnOptionAutoRandom
+	ColumnOptionSRID
 )
 
 var (
@@ -556,6 +557,8 @@ type ColumnOption struct {
 	// Name is only used for Check Constraint name.
 	ConstraintName string
 	PrimaryKeyTp   model.PrimaryKeyType
+	// SRID is only used for ColumnOptionSRID, it's the spatial reference system of the geometry column.
+	SRID uint64
 }
 
 // Restore implements Node interface.
@@ -668,6 +671,9 @@ func (n *ColumnOption) Restore(ctx *format.RestoreCtx) error {
 			}
 			return nil
 		})
+	case ColumnOptionSRID:
+		ctx.WriteKeyWord("SRID ")
+		ctx.WritePlainf("%d", n.SRID)
 	default:
 		return errors.New("An error occurred while splicing ColumnOption")
 	}
@@ -726,6 +732,10 @@ type IndexOption struct {
 	ParserName   model.CIStr
 	Visibility   IndexVisibility
 	PrimaryKeyTp model.PrimaryKeyType
+	// DistanceFunc is the distance function of a VECTOR index. It isn't parsed from the option but filled by DDL
+	// when the index part `(VEC_..._DISTANCE(col))` is replaced by the column, so the index part can be encoded
+	// in the DDL job without the expression.
+	DistanceFunc model.CIStr
 }
 
 // Restore implements Node interface.
@@ -813,6 +823,7 @@ const (
 	ConstraintForeignKey
 	ConstraintFulltext
 	ConstraintCheck
+	ConstraintVector
 )
 
 // Constraint is constraint for table definition.
@@ -867,6 +878,11 @@ func (n *Constraint) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("UNIQUE INDEX")
 	case ConstraintFulltext:
 		ctx.WriteKeyWord("FULLTEXT")
+	case ConstraintVector:
+		ctx.WriteKeyWord("VECTOR INDEX")
+		if n.IfNotExists {
+			ctx.WriteKeyWord(" IF NOT EXISTS")
+		}
 	case ConstraintCheck:
 		if n.Name != "" {
 			ctx.WriteKeyWord("CONSTRAINT ")
@@ -1772,6 +1788,7 @@ const (
 	IndexKeyTypeUnique
 	IndexKeyTypeSpatial
 	IndexKeyTypeFullText
+	IndexKeyTypeVector
 )
 
 // CreateIndexStmt is a statement to create an index.
@@ -1801,6 +1818,8 @@ func (n *CreateIndexStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("SPATIAL ")
 	case IndexKeyTypeFullText:
 		ctx.WriteKeyWord("FULLTEXT ")
+	case IndexKeyTypeVector:
+		ctx.WriteKeyWord("VECTOR ")
 	}
 	ctx.WriteKeyWord("INDEX ")
 	if n.IfNotExists {
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/format"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
 )
 
 var (
@@ -28,6 +29,7 @@ var (
 	_ DMLNode = &InsertStmt{}
 	_ DMLNode = &SetOprStmt{}
 	_ DMLNode = &UpdateStmt{}
+	_ DMLNode = &MergeStmt{}
 	_ DMLNode = &SelectStmt{}
 	_ DMLNode = &CallStmt{}
 	_ DMLNode = &ShowStmt{}
@@ -44,6 +46,7 @@ var (
 	_ Node = &AsOfClause{}
 	_ Node = &Join{}
 	_ Node = &Limit{}
+	_ Node = &MergeWhenClause{}
 	_ Node = &OnCondition{}
 	_ Node = &OrderByClause{}
 	_ Node = &SelectField{}
@@ -363,6 +366,8 @@ const (
 	HintForce
 	HintOrderIndex
 	HintNoOrderIndex
+	HintSkipScan
+	HintNoSkipScan
 )
 
 // IndexHintScope is the type for index hint for join, order by or group by.
@@ -397,6 +402,10 @@ func (n *IndexHint) Restore(ctx *format.RestoreCtx) error {
 		indexHintType = "ORDER INDEX"
 	case HintNoOrderIndex:
 		indexHintType = "NO ORDER INDEX"
+	case HintSkipScan:
+		indexHintType = "SKIP SCAN"
+	case HintNoSkipScan:
+		indexHintType = "NO SKIP SCAN"
 	default: // Prevent accidents
 		return errors.New("IndexHintType has an error while matching")
 	}
@@ -531,6 +540,9 @@ type TableSource struct {
 
 	// AsName is the alias name of the table source.
 	AsName model.CIStr
+
+	// Lateral indicates the derived table is LATERAL, it can reference the tables before it in the FROM clause.
+	Lateral bool
 }
 
 func (*TableSource) resultSet() {}
@@ -576,6 +588,9 @@ func (n *TableSource) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(")")
 		}
 	} else {
+		if n.Lateral {
+			ctx.WriteKeyWord("LATERAL ")
+		}
 		if needParen {
 			ctx.WritePlain("(")
 		}
@@ -609,6 +624,167 @@ func (n *TableSource) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// JSONTable is the JSON_TABLE table function, which extracts the data of a JSON document as a table.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-table-functions.html
+type JSONTable struct {
+	node
+
+	// Expr is the JSON document.
+	Expr ExprNode
+	// Path is the path of the rows in the document.
+	Path    string
+	Columns []*JSONTableColumn
+}
+
+func (*JSONTable) resultSet() {}
+
+// Restore implements Node interface.
+func (n *JSONTable) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("JSON_TABLE")
+	ctx.WritePlain("(")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTable.Expr")
+	}
+	ctx.WritePlain(", ")
+	ctx.WriteString(n.Path)
+	ctx.WritePlain(" ")
+	if err := restoreJSONTableColumns(ctx, n.Columns); err != nil {
+		return err
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *JSONTable) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*JSONTable)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// JSONTableColumnType is the type of JSON_TABLE columns.
+type JSONTableColumnType int
+
+// JSON_TABLE column types.
+const (
+	// JSONTableColumnPath is `name type PATH path [on_empty] [on_error]`.
+	JSONTableColumnPath JSONTableColumnType = iota
+	// JSONTableColumnExistsPath is `name type EXISTS PATH path`.
+	JSONTableColumnExistsPath
+	// JSONTableColumnOrdinality is `name FOR ORDINALITY`.
+	JSONTableColumnOrdinality
+	// JSONTableColumnNested is `NESTED [PATH] path COLUMNS (column_list)`.
+	JSONTableColumnNested
+)
+
+// JSONTableColumn is a column definition of JSON_TABLE.
+type JSONTableColumn struct {
+	Tp   JSONTableColumnType
+	Name model.CIStr
+	// Type is the column type, it's nil for the ordinality and nested columns.
+	Type *types.FieldType
+	Path string
+	// OnEmpty and OnError are nil if they are not specified.
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+	// Columns are the columns of the nested path.
+	Columns []*JSONTableColumn
+}
+
+// Restore writes the column definition.
+func (n *JSONTableColumn) Restore(ctx *format.RestoreCtx) error {
+	if n.Tp == JSONTableColumnNested {
+		ctx.WriteKeyWord("NESTED PATH ")
+		ctx.WriteString(n.Path)
+		ctx.WritePlain(" ")
+		return restoreJSONTableColumns(ctx, n.Columns)
+	}
+	ctx.WriteName(n.Name.O)
+	switch n.Tp {
+	case JSONTableColumnOrdinality:
+		ctx.WriteKeyWord(" FOR ORDINALITY")
+		return nil
+	case JSONTableColumnExistsPath:
+		ctx.WritePlain(" ")
+		if err := n.Type.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore JSONTableColumn.Type")
+		}
+		ctx.WriteKeyWord(" EXISTS PATH ")
+		ctx.WriteString(n.Path)
+		return nil
+	}
+	ctx.WritePlain(" ")
+	if err := n.Type.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTableColumn.Type")
+	}
+	ctx.WriteKeyWord(" PATH ")
+	ctx.WriteString(n.Path)
+	if n.OnEmpty != nil {
+		ctx.WritePlain(" ")
+		n.OnEmpty.Restore(ctx)
+		ctx.WriteKeyWord(" ON EMPTY")
+	}
+	if n.OnError != nil {
+		ctx.WritePlain(" ")
+		n.OnError.Restore(ctx)
+		ctx.WriteKeyWord(" ON ERROR")
+	}
+	return nil
+}
+
+func restoreJSONTableColumns(ctx *format.RestoreCtx, cols []*JSONTableColumn) error {
+	ctx.WriteKeyWord("COLUMNS")
+	ctx.WritePlain("(")
+	for i, col := range cols {
+		if i > 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := col.Restore(ctx); err != nil {
+			return err
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// JSONTableOnResponseType is the type of the ON EMPTY and ON ERROR clauses of JSON_TABLE columns.
+type JSONTableOnResponseType int
+
+// JSON_TABLE ON EMPTY and ON ERROR response types.
+const (
+	JSONTableOnResponseNull JSONTableOnResponseType = iota
+	JSONTableOnResponseError
+	JSONTableOnResponseDefault
+)
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of JSON_TABLE columns.
+type JSONTableOnResponse struct {
+	Tp JSONTableOnResponseType
+	// Default is the JSON string used by `DEFAULT json_string`.
+	Default string
+}
+
+// Restore writes the response without the ON EMPTY or ON ERROR keywords.
+func (n *JSONTableOnResponse) Restore(ctx *format.RestoreCtx) {
+	switch n.Tp {
+	case JSONTableOnResponseNull:
+		ctx.WriteKeyWord("NULL")
+	case JSONTableOnResponseError:
+		ctx.WriteKeyWord("ERROR")
+	case JSONTableOnResponseDefault:
+		ctx.WriteKeyWord("DEFAULT ")
+		ctx.WriteString(n.Default)
+	}
+}
+
 // SelectLockType is the lock type for SelectStmt.
 type SelectLockType int
 
@@ -2934,6 +3110,208 @@ func (n *UpdateStmt) TableRefsJoin() (*Join, bool) {
 	return n.TableRefs.TableRefs, true
 }
 
+// MergeActionType is the action of a WHEN clause of the MERGE statement.
+type MergeActionType int
+
+// MergeActionType types.
+const (
+	MergeActionUpdate MergeActionType = iota
+	MergeActionDelete
+	MergeActionInsert
+)
+
+// MergeWhenClause is a WHEN clause of the MERGE statement.
+type MergeWhenClause struct {
+	node
+
+	// Matched indicates whether the clause applies to the rows matching a target row.
+	Matched bool
+	// Condition is the optional AND condition of the clause.
+	Condition ExprNode
+	Action    MergeActionType
+	// Assignments is the SET list of the UPDATE action.
+	Assignments []*Assignment
+	// Columns and Values are the inserted columns and values of the INSERT action.
+	Columns []*ColumnName
+	Values  []ExprNode
+}
+
+// Restore implements Node interface.
+func (n *MergeWhenClause) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("WHEN ")
+	if !n.Matched {
+		ctx.WriteKeyWord("NOT ")
+	}
+	ctx.WriteKeyWord("MATCHED")
+	if n.Condition != nil {
+		ctx.WriteKeyWord(" AND ")
+		if err := n.Condition.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore MergeWhenClause.Condition")
+		}
+	}
+	ctx.WriteKeyWord(" THEN ")
+	switch n.Action {
+	case MergeActionUpdate:
+		ctx.WriteKeyWord("UPDATE SET ")
+		for i, assignment := range n.Assignments {
+			if i != 0 {
+				ctx.WritePlain(", ")
+			}
+			if err := assignment.Restore(ctx); err != nil {
+				return errors.Annotatef(err, "An error occurred while restore MergeWhenClause.Assignments[%d]", i)
+			}
+		}
+	case MergeActionDelete:
+		ctx.WriteKeyWord("DELETE")
+	case MergeActionInsert:
+		ctx.WriteKeyWord("INSERT ")
+		if len(n.Columns) > 0 {
+			ctx.WritePlain("(")
+			for i, col := range n.Columns {
+				if i != 0 {
+					ctx.WritePlain(",")
+				}
+				if err := col.Restore(ctx); err != nil {
+					return errors.Annotatef(err, "An error occurred while restore MergeWhenClause.Columns[%d]", i)
+				}
+			}
+			ctx.WritePlain(") ")
+		}
+		ctx.WriteKeyWord("VALUES ")
+		ctx.WritePlain("(")
+		for i, v := range n.Values {
+			if i != 0 {
+				ctx.WritePlain(",")
+			}
+			if err := v.Restore(ctx); err != nil {
+				return errors.Annotatef(err, "An error occurred while restore MergeWhenClause.Values[%d]", i)
+			}
+		}
+		ctx.WritePlain(")")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *MergeWhenClause) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*MergeWhenClause)
+	if n.Condition != nil {
+		node, ok := n.Condition.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Condition = node.(ExprNode)
+	}
+	for i, val := range n.Assignments {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Assignments[i] = node.(*Assignment)
+	}
+	for i, val := range n.Columns {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Columns[i] = node.(*ColumnName)
+	}
+	for i, val := range n.Values {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Values[i] = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// MergeStmt is a statement to update, delete or insert the rows of the target table by joining it with the source.
+// Each source row is handled by the first WHEN clause which it satisfies.
+// See https://en.wikipedia.org/wiki/Merge_(SQL)
+type MergeStmt struct {
+	dmlNode
+
+	// Target is the target table with its alias.
+	Target      *TableSource
+	Source      ResultSetNode
+	On          ExprNode
+	WhenClauses []*MergeWhenClause
+	TableHints  []*TableOptimizerHint
+}
+
+// Restore implements Node interface.
+func (n *MergeStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("MERGE ")
+	if len(n.TableHints) != 0 {
+		ctx.WritePlain("/*+ ")
+		for i, tableHint := range n.TableHints {
+			if i != 0 {
+				ctx.WritePlain(" ")
+			}
+			if err := tableHint.Restore(ctx); err != nil {
+				return errors.Annotatef(err, "An error occurred while restore MergeStmt.TableHints[%d]", i)
+			}
+		}
+		ctx.WritePlain("*/ ")
+	}
+	ctx.WriteKeyWord("INTO ")
+	if err := n.Target.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore MergeStmt.Target")
+	}
+	ctx.WriteKeyWord(" USING ")
+	if err := n.Source.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore MergeStmt.Source")
+	}
+	ctx.WriteKeyWord(" ON ")
+	if err := n.On.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore MergeStmt.On")
+	}
+	for i, clause := range n.WhenClauses {
+		ctx.WritePlain(" ")
+		if err := clause.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore MergeStmt.WhenClauses[%d]", i)
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *MergeStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*MergeStmt)
+	node, ok := n.Target.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Target = node.(*TableSource)
+	node, ok = n.Source.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Source = node.(ResultSetNode)
+	node, ok = n.On.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.On = node.(ExprNode)
+	for i, clause := range n.WhenClauses {
+		node, ok = clause.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.WhenClauses[i] = node.(*MergeWhenClause)
+	}
+	return v.Leave(n)
+}
+
 // Limit is the limit clause.
 type Limit struct {
 	node
@@ -281,6 +281,19 @@ func TestDeleteTableIndexHintRestore(t *testing.T) {
 	runNodeRestoreTest(t, testCases, "%s", extractNodeFunc)
 }
 
+func TestMergeStmtRestore(t *testing.T) {
+	testCases := []NodeRestoreTestCase{
+		{"MERGE INTO t USING s ON t.a=s.a WHEN MATCHED AND s.b>1 THEN DELETE WHEN MATCHED THEN UPDATE SET b=s.b WHEN NOT MATCHED THEN INSERT (a,b) VALUES (s.a,s.b)",
+			"MERGE INTO `t` USING `s` ON `t`.`a`=`s`.`a` WHEN MATCHED AND `s`.`b`>1 THEN DELETE WHEN MATCHED THEN UPDATE SET `b`=`s`.`b` WHEN NOT MATCHED THEN INSERT (`a`,`b`) VALUES (`s`.`a`,`s`.`b`)"},
+		{"MERGE INTO t AS x USING (SELECT a FROM s) AS y ON x.a=y.a WHEN NOT MATCHED THEN INSERT VALUES (y.a)",
+			"MERGE INTO `t` AS `x` USING (SELECT `a` FROM `s`) AS `y` ON `x`.`a`=`y`.`a` WHEN NOT MATCHED THEN INSERT VALUES (`y`.`a`)"},
+	}
+	extractNodeFunc := func(node Node) Node {
+		return node.(*MergeStmt)
+	}
+	runNodeRestoreTest(t, testCases, "%s", extractNodeFunc)
+}
+
 func TestByItemRestore(t *testing.T) {
 	testCases := []NodeRestoreTestCase{
 		{"a", "`a`"},
@@ -355,6 +355,82 @@ const (
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
 
+	// spatial functions
+	Point                        = "point"
+	LineString                   = "linestring"
+	Polygon                      = "polygon"
+	MultiPoint                   = "multipoint"
+	MultiLineString              = "multilinestring"
+	MultiPolygon                 = "multipolygon"
+	GeomCollection               = "geomcollection"
+	GeometryCollection           = "geometrycollection"
+	STGeomFromText               = "st_geomfromtext"
+	STGeometryFromText           = "st_geometryfromtext"
+	STPointFromText              = "st_pointfromtext"
+	STLineFromText               = "st_linefromtext"
+	STLineStringFromText         = "st_linestringfromtext"
+	STPolyFromText               = "st_polyfromtext"
+	STPolygonFromText            = "st_polygonfromtext"
+	STMPointFromText             = "st_mpointfromtext"
+	STMultiPointFromText         = "st_multipointfromtext"
+	STMLineFromText              = "st_mlinefromtext"
+	STMultiLineStringFromText    = "st_multilinestringfromtext"
+	STMPolyFromText              = "st_mpolyfromtext"
+	STMultiPolygonFromText       = "st_multipolygonfromtext"
+	STGeomCollFromText           = "st_geomcollfromtext"
+	STGeomCollFromTxt            = "st_geomcollfromtxt"
+	STGeometryCollectionFromText = "st_geometrycollectionfromtext"
+	STGeomFromWKB                = "st_geomfromwkb"
+	STGeometryFromWKB            = "st_geometryfromwkb"
+	STPointFromWKB               = "st_pointfromwkb"
+	STLineFromWKB                = "st_linefromwkb"
+	STLineStringFromWKB          = "st_linestringfromwkb"
+	STPolyFromWKB                = "st_polyfromwkb"
+	STPolygonFromWKB             = "st_polygonfromwkb"
+	STMPointFromWKB              = "st_mpointfromwkb"
+	STMultiPointFromWKB          = "st_multipointfromwkb"
+	STMLineFromWKB               = "st_mlinefromwkb"
+	STMultiLineStringFromWKB     = "st_multilinestringfromwkb"
+	STMPolyFromWKB               = "st_mpolyfromwkb"
+	STMultiPolygonFromWKB        = "st_multipolygonfromwkb"
+	STGeomCollFromWKB            = "st_geomcollfromwkb"
+	STGeometryCollectionFromWKB  = "st_geometrycollectionfromwkb"
+	STGeomFromGeoJSON            = "st_geomfromgeojson"
+	STAsText                     = "st_astext"
+	STAsWKT                      = "st_aswkt"
+	STAsBinary                   = "st_asbinary"
+	STAsWKB                      = "st_aswkb"
+	STAsGeoJSON                  = "st_asgeojson"
+	STSRID                       = "st_srid"
+	STX                          = "st_x"
+	STY                          = "st_y"
+	STLatitude                   = "st_latitude"
+	STLongitude                  = "st_longitude"
+	STGeometryType               = "st_geometrytype"
+	STIsEmpty                    = "st_isempty"
+	STContains                   = "st_contains"
+	STWithin                     = "st_within"
+	STIntersects                 = "st_intersects"
+	STDisjoint                   = "st_disjoint"
+	STEquals                     = "st_equals"
+	STDistance                   = "st_distance"
+	STDistanceSphere             = "st_distance_sphere"
+	STArea                       = "st_area"
+	STLength                     = "st_length"
+
+	// fulltext function.
+	FTSMatchAgainst = "match_against"
+
+	// vector functions.
+	VecDims                 = "vec_dims"
+	VecL1Distance           = "vec_l1_distance"
+	VecL2Distance           = "vec_l2_distance"
+	VecCosineDistance       = "vec_cosine_distance"
+	VecNegativeInnerProduct = "vec_negative_inner_product"
+	VecL2Norm               = "vec_l2_norm"
+	VecFromText             = "vec_from_text"
+	VecAsText               = "vec_as_text"
+
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
 	TiDBDecodeBase64Key = "tidb_decode_base64_key"
@@ -0,0 +1,103 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterializedViewVisitorCover(t *testing.T) {
+	stmts := []ast.Node{
+		&ast.CreateMaterializedViewStmt{ViewName: &ast.TableName{}, Select: &ast.SelectStmt{}},
+		&ast.DropMaterializedViewStmt{ViewName: &ast.TableName{}},
+		&ast.RefreshMaterializedViewStmt{ViewName: &ast.TableName{}},
+	}
+	for _, v := range stmts {
+		v.Accept(visitor{})
+		v.Accept(visitor1{})
+	}
+}
+
+func TestMaterializedView(t *testing.T) {
+	p := parser.New()
+	stmts, _, err := p.Parse("create materialized view if not exists test.mv (a, cnt) refresh fast every '1h' as select a, count(*) from t group by a", "", "")
+	require.NoError(t, err)
+	stmt := stmts[0].(*ast.CreateMaterializedViewStmt)
+	require.True(t, stmt.IfNotExists)
+	require.Equal(t, "test", stmt.ViewName.Schema.L)
+	require.Equal(t, "mv", stmt.ViewName.Name.L)
+	require.Equal(t, []model.CIStr{model.NewCIStr("a"), model.NewCIStr("cnt")}, stmt.Cols)
+	require.Equal(t, model.RefreshMethodFast, stmt.RefreshMethod)
+	require.Equal(t, "1h", stmt.RefreshInterval)
+	_, ok := stmt.Select.(*ast.SelectStmt)
+	require.True(t, ok)
+
+	stmts, _, err = p.Parse("create materialized view mv refresh every '10m' as select * from t union select * from t2", "", "")
+	require.NoError(t, err)
+	stmt = stmts[0].(*ast.CreateMaterializedViewStmt)
+	require.Equal(t, model.RefreshMethodUnspecified, stmt.RefreshMethod)
+	require.Equal(t, "10m", stmt.RefreshInterval)
+	_, ok = stmt.Select.(*ast.SetOprStmt)
+	require.True(t, ok)
+
+	stmts, _, err = p.Parse("drop materialized view if exists test.mv", "", "")
+	require.NoError(t, err)
+	drop := stmts[0].(*ast.DropMaterializedViewStmt)
+	require.True(t, drop.IfExists)
+	require.Equal(t, "mv", drop.ViewName.Name.L)
+
+	stmts, _, err = p.Parse("refresh materialized view mv complete", "", "")
+	require.NoError(t, err)
+	refresh := stmts[0].(*ast.RefreshMaterializedViewStmt)
+	require.Equal(t, "mv", refresh.ViewName.Name.L)
+	require.Equal(t, model.RefreshMethodComplete, refresh.Method)
+
+	// The new keywords are unreserved.
+	_, _, err = p.Parse("create table materialized (refresh int, fast int, complete int, every int)", "", "")
+	require.NoError(t, err)
+
+	for _, sql := range []string{
+		"create materialized view mv refresh as select 1",
+		"create materialized view mv refresh fast every 1 as select 1",
+		"create or replace materialized view mv as select 1",
+		"refresh materialized view mv incremental",
+		"drop materialized view mv1, mv2",
+	} {
+		_, _, err = p.Parse(sql, "", "")
+		require.Error(t, err, sql)
+	}
+}
+
+func TestMaterializedViewRestore(t *testing.T) {
+	testCases := []NodeRestoreTestCase{
+		{"CREATE MATERIALIZED VIEW `mv` AS SELECT 1", "CREATE MATERIALIZED VIEW `mv` AS SELECT 1"},
+		{"CREATE MATERIALIZED VIEW IF NOT EXISTS `test`.`mv` (`a`,`cnt`) REFRESH FAST EVERY '1h' AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`", "CREATE MATERIALIZED VIEW IF NOT EXISTS `test`.`mv` (`a`,`cnt`) REFRESH FAST EVERY '1h' AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"CREATE MATERIALIZED VIEW `mv` REFRESH COMPLETE AS SELECT * FROM `t`", "CREATE MATERIALIZED VIEW `mv` REFRESH COMPLETE AS SELECT * FROM `t`"},
+		{"CREATE MATERIALIZED VIEW `mv` REFRESH EVERY '5m' AS SELECT * FROM `t`", "CREATE MATERIALIZED VIEW `mv` REFRESH EVERY '5m' AS SELECT * FROM `t`"},
+		{"DROP MATERIALIZED VIEW `mv`", "DROP MATERIALIZED VIEW `mv`"},
+		{"DROP MATERIALIZED VIEW IF EXISTS `test`.`mv`", "DROP MATERIALIZED VIEW IF EXISTS `test`.`mv`"},
+		{"REFRESH MATERIALIZED VIEW `mv`", "REFRESH MATERIALIZED VIEW `mv`"},
+		{"REFRESH MATERIALIZED VIEW `test`.`mv` FAST", "REFRESH MATERIALIZED VIEW `test`.`mv` FAST"},
+	}
+	extractNodeFunc := func(node ast.Node) ast.Node {
+		return node
+	}
+	runNodeRestoreTest(t, testCases, "%s", extractNodeFunc)
+}
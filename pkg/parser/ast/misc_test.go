@@ -243,6 +243,11 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"NO_ORDER_INDEX(t1@sel_1 c1)", "NO_ORDER_INDEX(`t1`@`sel_1` `c1`)"},
 		{"NO_ORDER_INDEX(test.t1@sel_1 c1)", "NO_ORDER_INDEX(`test`.`t1`@`sel_1` `c1`)"},
 		{"NO_ORDER_INDEX(test.t1@sel_1 partition(p0) c1)", "NO_ORDER_INDEX(`test`.`t1`@`sel_1` PARTITION(`p0`) `c1`)"},
+		{"SKIP_SCAN(t1 c1)", "SKIP_SCAN(`t1` `c1`)"},
+		{"SKIP_SCAN(t1)", "SKIP_SCAN(`t1` )"},
+		{"SKIP_SCAN(test.t1@sel_1 c1, c2)", "SKIP_SCAN(`test`.`t1`@`sel_1` `c1`, `c2`)"},
+		{"NO_SKIP_SCAN(t1 c1)", "NO_SKIP_SCAN(`t1` `c1`)"},
+		{"NO_SKIP_SCAN(@sel_1 t1 c1)", "NO_SKIP_SCAN(@`sel_1` `t1` `c1`)"},
 		{"TIDB_SMJ(`t1`)", "TIDB_SMJ(`t1`)"},
 		{"TIDB_SMJ(t1)", "TIDB_SMJ(`t1`)"},
 		{"TIDB_SMJ(t1,t2)", "TIDB_SMJ(`t1`, `t2`)"},
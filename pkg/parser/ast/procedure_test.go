@@ -33,6 +33,7 @@ func TestProcedureVisitorCover(t *testing.T) {
 	}
 	stmts2 := []ast.StmtNode{
 		&ast.ProcedureBlock{},
+		&ast.ProcedureLoopStmt{},
 		&ast.ProcedureInfo{ProcedureBody: &ast.ProcedureBlock{}},
 		&ast.DropProcedureStmt{},
 	}
@@ -99,6 +100,9 @@ func TestProcedure(t *testing.T) {
 		`create procedure proc_2() begin labelname: while id < 10 do set id = id + 1; select 1; end while; end`,
 		`create procedure proc_2() begin labelname: while id < 10 do set id = id + 1; select 1; end while labelname; end`,
 		`create procedure proc_2(id int) begin labelname: REPEAT set id = id + 1; select 1; UNTIL id < 10 end REPEAT labelname; end`,
+		`create procedure proc_2(id int) begin loop set id = id + 1; end loop; end`,
+		`create procedure proc_2(id int) begin labelname: loop set id = id + 1; if id > 10 then leave labelname; end if; end loop labelname; end`,
+		`create procedure proc_2(id int) begin call proc_1(id, @a); call test.proc_1; do sleep(1); end`,
 	}
 	for _, testcase := range testcases {
 		stmt, _, err := p.Parse(testcase, "", "")
@@ -217,6 +221,14 @@ func TestProcedureRestore(t *testing.T) {
 			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: REPEAT SET @@SESSION.`id`=`id`+1;SELECT 1;UNTIL `id`<10 END REPEAT `labelname`; END",
 			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: REPEAT SET @@SESSION.`id`=`id`+1;SELECT 1;UNTIL `id`<10 END REPEAT `labelname`; END",
 		},
+		{
+			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: LOOP SET @@SESSION.`id`=`id`+1;IF `id`>10 THEN LEAVE `labelname`;END IF;END LOOP `labelname`; END",
+			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: LOOP SET @@SESSION.`id`=`id`+1;IF `id`>10 THEN LEAVE `labelname`;END IF;END LOOP `labelname`; END",
+		},
+		{
+			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN CALL `proc_1`(`id`, @`a`);CALL `test`.`proc_1`(); END",
+			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN CALL `proc_1`(`id`, @`a`);CALL `test`.`proc_1`(); END",
+		},
 	}
 	extractNodeFunc := func(node ast.Node) ast.Node {
 		return node.(*ast.ProcedureInfo)
@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerVisitorCover(t *testing.T) {
+	stmts := []ast.Node{
+		&ast.CreateTriggerStmt{TriggerName: &ast.TableName{}, Table: &ast.TableName{}},
+		&ast.DropTriggerStmt{TriggerName: &ast.TableName{}},
+	}
+	for _, v := range stmts {
+		v.Accept(visitor{})
+		v.Accept(visitor1{})
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	p := parser.New()
+	stmts, _, err := p.Parse("create definer = 'root'@'%' trigger if not exists test.tr before update on t for each row follows tr0 begin set new.a = old.a + 1; end", "", "")
+	require.NoError(t, err)
+	stmt := stmts[0].(*ast.CreateTriggerStmt)
+	require.True(t, stmt.IfNotExists)
+	require.Equal(t, "root", stmt.Definer.Username)
+	require.Equal(t, "test", stmt.TriggerName.Schema.L)
+	require.Equal(t, "tr", stmt.TriggerName.Name.L)
+	require.Equal(t, model.TriggerTimingBefore, stmt.Timing)
+	require.Equal(t, model.TriggerEventUpdate, stmt.Event)
+	require.Equal(t, "t", stmt.Table.Name.L)
+	require.True(t, stmt.Order.Follows)
+	require.Equal(t, "tr0", stmt.Order.TriggerName.L)
+	require.Equal(t, "begin set new.a = old.a + 1; end", stmt.Body.Text())
+
+	stmts, _, err = p.Parse("create trigger tr after delete on t for each row precedes tr0 insert into log values (old.id)", "", "")
+	require.NoError(t, err)
+	stmt = stmts[0].(*ast.CreateTriggerStmt)
+	require.Equal(t, model.TriggerTimingAfter, stmt.Timing)
+	require.Equal(t, model.TriggerEventDelete, stmt.Event)
+	require.False(t, stmt.Order.Follows)
+	_, ok := stmt.Body.(*ast.InsertStmt)
+	require.True(t, ok)
+
+	stmts, _, err = p.Parse("drop trigger if exists test.tr", "", "")
+	require.NoError(t, err)
+	drop := stmts[0].(*ast.DropTriggerStmt)
+	require.True(t, drop.IfExists)
+	require.Equal(t, "tr", drop.TriggerName.Name.L)
+
+	for _, sql := range []string{
+		"create trigger tr before insert on t begin end",
+		"create trigger tr before select on t for each row begin end",
+		"create or replace trigger tr before insert on t for each row begin end",
+		"create algorithm = merge trigger tr before insert on t for each row begin end",
+	} {
+		_, _, err = p.Parse(sql, "", "")
+		require.Error(t, err, sql)
+	}
+}
+
+func TestTriggerRestore(t *testing.T) {
+	testCases := []NodeRestoreTestCase{
+		{"CREATE DEFINER = CURRENT_USER TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW BEGIN SELECT 1; END", "CREATE DEFINER = CURRENT_USER TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW BEGIN SELECT 1; END"},
+		{"CREATE DEFINER = `root`@`%` TRIGGER IF NOT EXISTS `test`.`tr` AFTER DELETE ON `test`.`t` FOR EACH ROW FOLLOWS `tr0` DELETE FROM `t2` WHERE `id`=`old`.`id`", "CREATE DEFINER = `root`@`%` TRIGGER IF NOT EXISTS `test`.`tr` AFTER DELETE ON `test`.`t` FOR EACH ROW FOLLOWS `tr0` DELETE FROM `t2` WHERE `id`=`old`.`id`"},
+		{"CREATE DEFINER = CURRENT_USER TRIGGER `tr` AFTER UPDATE ON `t` FOR EACH ROW PRECEDES `tr0` BEGIN SELECT 1; END", "CREATE DEFINER = CURRENT_USER TRIGGER `tr` AFTER UPDATE ON `t` FOR EACH ROW PRECEDES `tr0` BEGIN SELECT 1; END"},
+		{"DROP TRIGGER `tr`", "DROP TRIGGER `tr`"},
+		{"DROP TRIGGER IF EXISTS `test`.`tr`", "DROP TRIGGER IF EXISTS `test`.`tr`"},
+	}
+	extractNodeFunc := func(node ast.Node) ast.Node {
+		return node
+	}
+	runNodeRestoreTest(t, testCases, "%s", extractNodeFunc)
+}
@@ -46,6 +46,9 @@ func TestCacheable(t *testing.T) {
 	stmt = &DoStmt{}
 	require.True(t, IsReadOnly(stmt))
 
+	stmt = &RecommendIndexStmt{}
+	require.True(t, IsReadOnly(stmt))
+
 	stmt = &ExplainStmt{
 		Stmt: &InsertStmt{},
 	}
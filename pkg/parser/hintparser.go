@@ -514,8 +514,8 @@ var (
 		{116, 1},
 		{116, 1},
 		{116, 1},
-		{116, 1},
-		{116, 1},
+		{113, 1},
+		{113, 1},
 		{113, 1},
 		{113, 1},
 		{113, 1},
@@ -635,7 +635,7 @@ var (
 
 	yyhintParseTab = [316][]uint16{
 		// 0
-		{1: 292, 251, 244, 246, 280, 288, 265, 267, 268, 239, 278, 296, 258, 254, 270, 263, 257, 253, 262, 222, 241, 242, 243, 269, 293, 229, 234, 256, 289, 290, 271, 245, 247, 299, 266, 273, 259, 255, 294, 264, 248, 272, 282, 274, 284, 276, 250, 261, 230, 281, 233, 238, 295, 240, 232, 283, 298, 231, 252, 275, 249, 297, 291, 260, 235, 286, 277, 279, 287, 285, 101: 236, 106: 223, 237, 110: 221, 228, 113: 227, 225, 220, 226, 224, 125: 219, 218},
+		{1: 292, 251, 244, 246, 278, 288, 265, 267, 268, 239, 276, 296, 258, 254, 270, 263, 257, 253, 262, 222, 241, 242, 243, 269, 293, 229, 234, 256, 289, 290, 271, 245, 247, 299, 266, 273, 259, 255, 294, 264, 248, 272, 280, 274, 284, 282, 250, 261, 230, 279, 233, 238, 295, 240, 232, 283, 298, 231, 252, 281, 249, 297, 291, 260, 235, 286, 275, 277, 287, 285, 101: 236, 106: 223, 237, 110: 221, 228, 113: 227, 225, 220, 226, 224, 125: 219, 218},
 		{91: 217},
 		{1: 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 183, 404, 91: 216, 95: 530},
 		{1: 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 215, 91: 215},
@@ -1010,7 +1010,7 @@ var (
 		{185, 84: 302, 92: 528},
 		{529},
 		{1: 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 211, 91: 211},
-		{1: 292, 251, 244, 246, 280, 288, 265, 267, 268, 239, 278, 296, 258, 254, 270, 263, 257, 253, 262, 222, 241, 242, 243, 269, 293, 229, 234, 256, 289, 290, 271, 245, 247, 299, 266, 273, 259, 255, 294, 264, 248, 272, 282, 274, 284, 276, 250, 261, 230, 281, 233, 238, 295, 240, 232, 283, 298, 231, 252, 275, 249, 297, 291, 260, 235, 286, 277, 279, 287, 285, 101: 236, 106: 223, 237, 110: 532, 228, 113: 227, 225, 531, 226, 224},
+		{1: 292, 251, 244, 246, 278, 288, 265, 267, 268, 239, 276, 296, 258, 254, 270, 263, 257, 253, 262, 222, 241, 242, 243, 269, 293, 229, 234, 256, 289, 290, 271, 245, 247, 299, 266, 273, 259, 255, 294, 264, 248, 272, 280, 274, 284, 282, 250, 261, 230, 279, 233, 238, 295, 240, 232, 283, 298, 231, 252, 281, 249, 297, 291, 260, 235, 286, 275, 277, 287, 285, 101: 236, 106: 223, 237, 110: 532, 228, 113: 227, 225, 531, 226, 224},
 		{1: 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 214, 91: 214},
 		// 315
 		{1: 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 212, 91: 212},
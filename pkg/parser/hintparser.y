@@ -654,8 +654,6 @@ UnsupportedIndexLevelOptimizerHintName:
 |	"NO_MRR"
 |	"NO_ICP"
 |	"NO_RANGE_OPTIMIZATION"
-|	"SKIP_SCAN"
-|	"NO_SKIP_SCAN"
 
 SupportedIndexLevelOptimizerHintName:
 	"USE_INDEX"
@@ -664,6 +662,8 @@ SupportedIndexLevelOptimizerHintName:
 |	"FORCE_INDEX"
 |	"ORDER_INDEX"
 |	"NO_ORDER_INDEX"
+|	"SKIP_SCAN"
+|	"NO_SKIP_SCAN"
 
 SubqueryOptimizerHintName:
 	"SEMIJOIN"
@@ -441,6 +441,10 @@ func TestOptimizerHintAfterCertainKeywordOnly(t *testing.T) {
 			input:  "CREATE /*+ hint */",
 			tokens: []int{create, hintComment, 0},
 		},
+		{
+			input:  "MERGE /*+ hint */",
+			tokens: []int{merge, hintComment, 0},
+		},
 		{
 			input:  "/*+ hint */ SELECT *",
 			tokens: []int{selectKwd, '*', 0},
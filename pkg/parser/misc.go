@@ -196,6 +196,7 @@ var tokenMap = map[string]int{
 	"BACKUP":                   backup,
 	"BACKUPS":                  backups,
 	"BDR":                      bdr,
+	"BEFORE":                   before,
 	"BEGIN":                    begin,
 	"BETWEEN":                  between,
 	"BERNOULLI":                bernoulli,
@@ -261,6 +262,7 @@ var tokenMap = map[string]int{
 	"COMMIT":                   commit,
 	"COMMITTED":                committed,
 	"COMPACT":                  compact,
+	"COMPLETE":                 complete,
 	"COMPRESSED":               compressed,
 	"COMPRESSION":              compression,
 	"CONCURRENCY":              concurrency,
@@ -344,11 +346,13 @@ var tokenMap = map[string]int{
 	"DUPLICATE":                duplicate,
 	"DURATION":                 timeDuration,
 	"DYNAMIC":                  dynamic,
+	"EACH":                     each,
 	"ELSE":                     elseKwd,
 	"ELSEIF":                   elseIfKwd,
 	"ENABLE":                   enable,
 	"ENABLED":                  enabled,
 	"ENCLOSED":                 enclosed,
+	"EMPTY":                    emptyKwd,
 	"ENCRYPTION":               encryption,
 	"END":                      end,
 	"END_TIME":                 endTime,
@@ -362,6 +366,7 @@ var tokenMap = map[string]int{
 	"ESCAPED":                  escaped,
 	"EVENT":                    event,
 	"EVENTS":                   events,
+	"EVERY":                    every,
 	"EVOLVE":                   evolve,
 	"EXACT":                    exact,
 	"EXEC_ELAPSED":             execElapsed,
@@ -378,6 +383,7 @@ var tokenMap = map[string]int{
 	"EXTENDED":                 extended,
 	"EXTRACT":                  extract,
 	"FALSE":                    falseKwd,
+	"FAST":                     fast,
 	"FAULTS":                   faultsSym,
 	"FETCH":                    fetch,
 	"FIELDS":                   fields,
@@ -393,6 +399,7 @@ var tokenMap = map[string]int{
 	"FOLLOWERS":                followers,
 	"FOLLOWER_CONSTRAINTS":     followerConstraints,
 	"FOLLOWING":                following,
+	"FOLLOWS":                  follows,
 	"FOR":                      forKwd,
 	"FORCE":                    force,
 	"FOREIGN":                  foreign,
@@ -405,6 +412,9 @@ var tokenMap = map[string]int{
 	"FUNCTION":                 function,
 	"GC_TTL":                   gcTTL,
 	"GENERAL":                  general,
+	"GEOMCOLLECTION":           geomCollection,
+	"GEOMETRY":                 geometry,
+	"GEOMETRYCOLLECTION":       geometryCollection,
 	"GENERATED":                generated,
 	"GET_FORMAT":               getFormat,
 	"GLOBAL":                   global,
@@ -472,6 +482,7 @@ var tokenMap = map[string]int{
 	"JOB":                      job,
 	"JOBS":                     jobs,
 	"JOIN":                     join,
+	"JSON_TABLE":               jsonTable,
 	"JSON_ARRAYAGG":            jsonArrayagg,
 	"JSON_OBJECTAGG":           jsonObjectAgg,
 	"JSON":                     jsonType,
@@ -484,6 +495,7 @@ var tokenMap = map[string]int{
 	"LAST_BACKUP":              lastBackup,
 	"LAST":                     last,
 	"LASTVAL":                  lastval,
+	"LATERAL":                  lateral,
 	"LEADER":                   leader,
 	"LEADER_CONSTRAINTS":       leaderConstraints,
 	"LEADING":                  leading,
@@ -498,6 +510,7 @@ var tokenMap = map[string]int{
 	"LIMIT":                    limit,
 	"LINEAR":                   linear,
 	"LINES":                    lines,
+	"LINESTRING":               lineString,
 	"LIST":                     list,
 	"LOAD":                     load,
 	"LOCAL":                    local,
@@ -511,9 +524,12 @@ var tokenMap = map[string]int{
 	"LONG":                     long,
 	"LONGBLOB":                 longblobType,
 	"LONGTEXT":                 longtextType,
+	"LOOP":                     loop,
 	"LOW_PRIORITY":             lowPriority,
 	"MASTER":                   master,
 	"MATCH":                    match,
+	"MATCHED":                  matched,
+	"MATERIALIZED":             materialized,
 	"MAX_CONNECTIONS_PER_HOUR": maxConnectionsPerHour,
 	"MAX_IDXNUM":               max_idxnum,
 	"MAX_MINUTES":              max_minutes,
@@ -543,10 +559,14 @@ var tokenMap = map[string]int{
 	"MODE":                     mode,
 	"MODIFY":                   modify,
 	"MONTH":                    month,
+	"MULTILINESTRING":          multiLineString,
+	"MULTIPOINT":               multiPoint,
+	"MULTIPOLYGON":             multiPolygon,
 	"NAMES":                    names,
 	"NATIONAL":                 national,
 	"NATURAL":                  natural,
 	"NCHAR":                    ncharType,
+	"NESTED":                   nested,
 	"NEVER":                    never,
 	"NEXT_ROW_ID":              next_row_id,
 	"NEXT":                     next,
@@ -586,6 +606,7 @@ var tokenMap = map[string]int{
 	"OPTIMIZE":                 optimize,
 	"OPTION":                   option,
 	"OPTIONAL":                 optional,
+	"ORDINALITY":               ordinality,
 	"OPTIONALLY":               optionally,
 	"OR":                       or,
 	"ORDER":                    order,
@@ -600,6 +621,7 @@ var tokenMap = map[string]int{
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
+	"PATH":                     path,
 	"PAUSE":                    pause,
 	"PERCENT":                  percent,
 	"PER_DB":                   per_db,
@@ -611,9 +633,11 @@ var tokenMap = map[string]int{
 	"PLUGINS":                  plugins,
 	"POINT":                    point,
 	"POLICY":                   policy,
+	"POLYGON":                  polygon,
 	"POSITION":                 position,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
 	"PRECEDING":                preceding,
+	"PRECEDES":                 precedes,
 	"PREDICATE":                predicate,
 	"PRECISION":                precisionType,
 	"PREPARE":                  prepare,
@@ -640,10 +664,12 @@ var tokenMap = map[string]int{
 	"REAL":                     realType,
 	"REBUILD":                  rebuild,
 	"RECENT":                   recent,
+	"RECOMMEND":                recommend,
 	"RECOVER":                  recover,
 	"RECURSIVE":                recursive,
 	"REDUNDANT":                redundant,
 	"REFERENCES":               references,
+	"REFRESH":                  refresh,
 	"REGEXP":                   regexpKwd,
 	"REGION":                   region,
 	"REGIONS":                  regions,
@@ -748,6 +774,7 @@ var tokenMap = map[string]int{
 	"SQLEXCEPTION":             sqlexception,
 	"SQLSTATE":                 sqlstate,
 	"SQLWARNING":               sqlwarning,
+	"SRID":                     srid,
 	"SSL":                      ssl,
 	"STALENESS":                staleness,
 	"START":                    start,
@@ -877,6 +904,7 @@ var tokenMap = map[string]int{
 	"VARCHAR":                  varcharType,
 	"VARCHARACTER":             varcharacter,
 	"VARIABLES":                variables,
+	"VECTOR":                   vectorType,
 	"VARIANCE":                 varPop,
 	"VARYING":                  varying,
 	"VERBOSE":                  verboseType,
@@ -983,6 +1011,7 @@ var hintedTokens = map[int]struct{}{
 	deleteKwd: {},
 	create:    {},
 	partition: {},
+	merge:     {},
 }
 
 var hintTokenMap = map[string]int{
@@ -106,6 +106,8 @@ const (
 	ActionDropResourceGroup      ActionType = 70
 	ActionAlterTablePartitioning ActionType = 71
 	ActionRemovePartitioning     ActionType = 72
+	ActionCreateTrigger          ActionType = 73
+	ActionDropTrigger            ActionType = 74
 )
 
 // ActionMap is the map of DDL ActionType to string.
@@ -177,6 +179,8 @@ var ActionMap = map[ActionType]string{
 	ActionDropResourceGroup:             "drop resource group",
 	ActionAlterTablePartitioning:        "alter table partition by",
 	ActionRemovePartitioning:            "alter table remove partitioning",
+	ActionCreateTrigger:                 "create trigger",
+	ActionDropTrigger:                   "drop trigger",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -266,6 +270,8 @@ var BDRActionMap = map[DDLBDRType][]ActionType{
 		ActionReorganizePartition,
 		ActionAlterTablePartitioning,
 		ActionRemovePartitioning,
+		ActionCreateTrigger,
+		ActionDropTrigger,
 	},
 	UnmanagementDDL: {
 		ActionCreatePlacementPolicy,
@@ -120,6 +120,9 @@ type ColumnInfo struct {
 	// A hidden column is used internally(expression index) and are not accessible by users.
 	Hidden           bool `json:"hidden"`
 	*ChangeStateInfo `json:"change_state_info"`
+	// SRID restricts the spatial reference system of the values of a geometry column, it's nil if there is no
+	// restriction.
+	SRID *uint32 `json:"srid,omitempty"`
 	// Version means the version of the column info.
 	// Version = 0: For OriginDefaultValue and DefaultValue of timestamp column will stores the default time in system time zone.
 	//              That is a bug if multiple TiDB servers in different system time zone.
@@ -542,6 +545,14 @@ type TableInfo struct {
 
 	TTLInfo *TTLInfo `json:"ttl_info"`
 
+	// Triggers are the triggers of the table, the triggers of the same timing and event are executed in their order.
+	Triggers []*TriggerInfo `json:"triggers,omitempty"`
+
+	// MaterializedView is set if the table stores the rows of a materialized view.
+	MaterializedView *MaterializedViewInfo `json:"materialized_view,omitempty"`
+	// IsMaterializedViewLog indicates the table records the changes of a base table for the fast refresh.
+	IsMaterializedViewLog bool `json:"is_materialized_view_log,omitempty"`
+
 	// Revision is per table schema's version, it will be increased when the schema changed.
 	Revision uint64 `json:"revision"`
 
@@ -762,6 +773,15 @@ func (t *TableInfo) Clone() *TableInfo {
 	if t.TTLInfo != nil {
 		nt.TTLInfo = t.TTLInfo.Clone()
 	}
+	if t.Triggers != nil {
+		nt.Triggers = make([]*TriggerInfo, len(t.Triggers))
+		for i := range t.Triggers {
+			nt.Triggers[i] = t.Triggers[i].Clone()
+		}
+	}
+	if t.MaterializedView != nil {
+		nt.MaterializedView = t.MaterializedView.Clone()
+	}
 
 	return &nt
 }
@@ -1028,6 +1048,11 @@ func (t *TableInfo) IsSequence() bool {
 	return t.Sequence != nil
 }
 
+// IsMaterializedView checks if TableInfo is a materialized view.
+func (t *TableInfo) IsMaterializedView() bool {
+	return t.MaterializedView != nil
+}
+
 // IsBaseTable checks to see the table is neither a view or a sequence.
 func (t *TableInfo) IsBaseTable() bool {
 	return t.Sequence == nil && t.View == nil
@@ -1105,6 +1130,118 @@ func (v *ViewCheckOption) String() string {
 	}
 }
 
+// TriggerTiming is the action time of a trigger.
+type TriggerTiming byte
+
+//revive:disable:exported
+const (
+	TriggerTimingBefore TriggerTiming = iota
+	TriggerTimingAfter
+)
+
+//revive:enable:exported
+
+// String implements fmt.Stringer interface.
+func (t TriggerTiming) String() string {
+	if t == TriggerTimingAfter {
+		return "AFTER"
+	}
+	return "BEFORE"
+}
+
+// TriggerEvent is the kind of operation that activates a trigger.
+type TriggerEvent byte
+
+//revive:disable:exported
+const (
+	TriggerEventInsert TriggerEvent = iota
+	TriggerEventUpdate
+	TriggerEventDelete
+)
+
+//revive:enable:exported
+
+// String implements fmt.Stringer interface.
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerEventUpdate:
+		return "UPDATE"
+	case TriggerEventDelete:
+		return "DELETE"
+	default:
+		return "INSERT"
+	}
+}
+
+// TriggerInfo provides meta data describing a trigger.
+type TriggerInfo struct {
+	Name    CIStr              `json:"name"`
+	Timing  TriggerTiming      `json:"timing"`
+	Event   TriggerEvent       `json:"event"`
+	Body    string             `json:"body"`
+	Definer *auth.UserIdentity `json:"definer"`
+	// SQLMode is the sql_mode when the trigger is created, the body is parsed and executed in this mode.
+	SQLMode             string    `json:"sql_mode"`
+	CharsetClient       string    `json:"charset_client"`
+	CollationConnection string    `json:"collation_connection"`
+	DBCollation         string    `json:"db_collation"`
+	Created             time.Time `json:"created"`
+}
+
+// Clone clones TriggerInfo.
+func (t *TriggerInfo) Clone() *TriggerInfo {
+	nt := *t
+	if t.Definer != nil {
+		definer := *t.Definer
+		nt.Definer = &definer
+	}
+	return &nt
+}
+
+// MaterializedViewRefreshMethod is the method to refresh a materialized view.
+type MaterializedViewRefreshMethod byte
+
+//revive:disable:exported
+const (
+	RefreshMethodUnspecified MaterializedViewRefreshMethod = iota
+	RefreshMethodComplete
+	RefreshMethodFast
+)
+
+//revive:enable:exported
+
+// String implements fmt.Stringer interface.
+func (m MaterializedViewRefreshMethod) String() string {
+	switch m {
+	case RefreshMethodComplete:
+		return "COMPLETE"
+	case RefreshMethodFast:
+		return "FAST"
+	default:
+		return ""
+	}
+}
+
+// MaterializedViewInfo provides meta data describing a materialized view.
+type MaterializedViewInfo struct {
+	// Definition is the SELECT statement of the view, its table names are qualified with the schema names.
+	Definition    string                        `json:"definition"`
+	RefreshMethod MaterializedViewRefreshMethod `json:"refresh_method"`
+	// RefreshInterval is the interval of the scheduled refresh, the view is only refreshed on demand if it's empty.
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	// BaseTable is the table in the same schema which the view reads from, it's empty if the view reads more than one table.
+	BaseTable CIStr `json:"base_table"`
+	// LogTable is the table which records the changes of the base table since the last refresh, it's empty
+	// if the view can't be refreshed fast.
+	LogTable CIStr `json:"log_table"`
+}
+
+// Clone clones MaterializedViewInfo.
+func (m *MaterializedViewInfo) Clone() *MaterializedViewInfo {
+	nm := *m
+	return &nm
+}
+
 // ViewInfo provides meta data describing a DB view.
 //
 //revive:disable:exported
@@ -1455,6 +1592,10 @@ func (t IndexType) String() string {
 		return "RTREE"
 	case IndexTypeHypo:
 		return "HYPO"
+	case IndexTypeFullText:
+		return "FULLTEXT"
+	case IndexTypeVector:
+		return "VECTOR"
 	default:
 		return ""
 	}
@@ -1467,8 +1608,67 @@ const (
 	IndexTypeHash
 	IndexTypeRtree
 	IndexTypeHypo
+	IndexTypeFullText
+	IndexTypeVector
+)
+
+// FullTextParserType is the type of the parser used to tokenize the text of a FULLTEXT index.
+type FullTextParserType string
+
+// FullTextParserTypes
+const (
+	// FullTextParserTypeStandard splits latin text into words by delimiters.
+	FullTextParserTypeStandard FullTextParserType = "STANDARD"
+	// FullTextParserTypeNgram splits text into overlapping n-character tokens, which is suitable for CJK text.
+	FullTextParserTypeNgram FullTextParserType = "NGRAM"
 )
 
+// FullTextIndexInfo is the extra information of a FULLTEXT index.
+type FullTextIndexInfo struct {
+	ParserType FullTextParserType `json:"parser_type"`
+}
+
+// Clone clones FullTextIndexInfo.
+func (f *FullTextIndexInfo) Clone() *FullTextIndexInfo {
+	if f == nil {
+		return nil
+	}
+	nf := *f
+	return &nf
+}
+
+// VectorDistanceMetric is the distance metric of the vectors used to build a VECTOR index.
+type VectorDistanceMetric string
+
+// VectorDistanceMetrics
+const (
+	VectorDistanceMetricL2           VectorDistanceMetric = "L2"
+	VectorDistanceMetricCosine       VectorDistanceMetric = "COSINE"
+	VectorDistanceMetricInnerProduct VectorDistanceMetric = "INNER_PRODUCT"
+)
+
+// VectorIndexInfo is the extra information of a VECTOR index.
+type VectorIndexInfo struct {
+	// Dimension is the dimension of the indexed vectors.
+	Dimension int `json:"dimension"`
+	// DistanceMetric is the metric the index is built for, only the queries using the same metric can use the index.
+	DistanceMetric VectorDistanceMetric `json:"distance_metric"`
+	// PartitionBits is the number of random hyperplanes that partition the vector space, the index has
+	// 2^PartitionBits partitions.
+	PartitionBits int `json:"partition_bits"`
+	// Seed is the seed to generate the random hyperplanes.
+	Seed int64 `json:"seed"`
+}
+
+// Clone clones VectorIndexInfo.
+func (v *VectorIndexInfo) Clone() *VectorIndexInfo {
+	if v == nil {
+		return nil
+	}
+	nv := *v
+	return &nv
+}
+
 // IndexInfo provides meta data describing a DB index.
 // It corresponds to the statement `CREATE INDEX Name ON Table (Column);`
 // See https://dev.mysql.com/doc/refman/5.7/en/create-index.html
@@ -1486,6 +1686,10 @@ type IndexInfo struct {
 	Invisible     bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global        bool           `json:"is_global"`    // Whether the index is global.
 	MVIndex       bool           `json:"mv_index"`     // Whether the index is multivalued index.
+	// FullTextInfo is not nil if the index is a FULLTEXT index.
+	FullTextInfo *FullTextIndexInfo `json:"fulltext_info,omitempty"`
+	// VectorInfo is not nil if the index is a VECTOR index.
+	VectorInfo *VectorIndexInfo `json:"vector_info,omitempty"`
 }
 
 // Clone clones IndexInfo.
@@ -1498,9 +1702,21 @@ func (index *IndexInfo) Clone() *IndexInfo {
 	for i := range index.Columns {
 		ni.Columns[i] = index.Columns[i].Clone()
 	}
+	ni.FullTextInfo = index.FullTextInfo.Clone()
+	ni.VectorInfo = index.VectorInfo.Clone()
 	return &ni
 }
 
+// IsFullText returns whether the index is a FULLTEXT index.
+func (index *IndexInfo) IsFullText() bool {
+	return index.FullTextInfo != nil
+}
+
+// IsVector returns whether the index is a VECTOR index.
+func (index *IndexInfo) IsVector() bool {
+	return index.VectorInfo != nil
+}
+
 // HasPrefixIndex returns whether any columns of this index uses prefix length.
 func (index *IndexInfo) HasPrefixIndex() bool {
 	for _, ic := range index.Columns {
@@ -181,11 +181,13 @@ import (
 	is                "IS"
 	iterate           "ITERATE"
 	join              "JOIN"
+	jsonTable         "JSON_TABLE"
 	key               "KEY"
 	keys              "KEYS"
 	kill              "KILL"
 	lag               "LAG"
 	lastValue         "LAST_VALUE"
+	lateral           "LATERAL"
 	lead              "LEAD"
 	leading           "LEADING"
 	leave             "LEAVE"
@@ -201,6 +203,7 @@ import (
 	long              "LONG"
 	longblobType      "LONGBLOB"
 	longtextType      "LONGTEXT"
+	loop              "LOOP"
 	lowPriority       "LOW_PRIORITY"
 	match             "MATCH"
 	maxValue          "MAXVALUE"
@@ -334,6 +337,7 @@ import (
 	backup                "BACKUP"
 	backups               "BACKUPS"
 	bdr                   "BDR"
+	before                "BEFORE"
 	begin                 "BEGIN"
 	bernoulli             "BERNOULLI"
 	binding               "BINDING"
@@ -370,6 +374,7 @@ import (
 	commit                "COMMIT"
 	committed             "COMMITTED"
 	compact               "COMPACT"
+	complete              "COMPLETE"
 	compressed            "COMPRESSED"
 	compression           "COMPRESSION"
 	concurrency           "CONCURRENCY"
@@ -405,6 +410,8 @@ import (
 	do                    "DO"
 	duplicate             "DUPLICATE"
 	dynamic               "DYNAMIC"
+	each                  "EACH"
+	emptyKwd              "EMPTY"
 	enable                "ENABLE"
 	enabled               "ENABLED"
 	encryption            "ENCRYPTION"
@@ -418,6 +425,7 @@ import (
 	escape                "ESCAPE"
 	event                 "EVENT"
 	events                "EVENTS"
+	every                 "EVERY"
 	evolve                "EVOLVE"
 	exchange              "EXCHANGE"
 	exclusive             "EXCLUSIVE"
@@ -426,6 +434,7 @@ import (
 	expire                "EXPIRE"
 	extended              "EXTENDED"
 	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
+	fast                  "FAST"
 	faultsSym             "FAULTS"
 	fields                "FIELDS"
 	file                  "FILE"
@@ -433,11 +442,15 @@ import (
 	fixed                 "FIXED"
 	flush                 "FLUSH"
 	following             "FOLLOWING"
+	follows               "FOLLOWS"
 	format                "FORMAT"
 	found                 "FOUND"
 	full                  "FULL"
 	function              "FUNCTION"
 	general               "GENERAL"
+	geomCollection        "GEOMCOLLECTION"
+	geometry              "GEOMETRY"
+	geometryCollection    "GEOMETRYCOLLECTION"
 	global                "GLOBAL"
 	grants                "GRANTS"
 	handler               "HANDLER"
@@ -471,12 +484,15 @@ import (
 	lastBackup            "LAST_BACKUP"
 	less                  "LESS"
 	level                 "LEVEL"
+	lineString            "LINESTRING"
 	list                  "LIST"
 	local                 "LOCAL"
 	location              "LOCATION"
 	locked                "LOCKED"
 	logs                  "LOGS"
 	master                "MASTER"
+	matched               "MATCHED"
+	materialized          "MATERIALIZED"
 	maxConnectionsPerHour "MAX_CONNECTIONS_PER_HOUR"
 	max_idxnum            "MAX_IDXNUM"
 	max_minutes           "MAX_MINUTES"
@@ -495,9 +511,13 @@ import (
 	mode                  "MODE"
 	modify                "MODIFY"
 	month                 "MONTH"
+	multiLineString       "MULTILINESTRING"
+	multiPoint            "MULTIPOINT"
+	multiPolygon          "MULTIPOLYGON"
 	names                 "NAMES"
 	national              "NATIONAL"
 	ncharType             "NCHAR"
+	nested                "NESTED"
 	never                 "NEVER"
 	next                  "NEXT"
 	nextval               "NEXTVAL"
@@ -522,6 +542,7 @@ import (
 	onDuplicate           "ON_DUPLICATE"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	ordinality            "ORDINALITY"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -530,6 +551,7 @@ import (
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
 	passwordLockTime      "PASSWORD_LOCK_TIME"
+	path                  "PATH"
 	pause                 "PAUSE"
 	percent               "PERCENT"
 	per_db                "PER_DB"
@@ -538,6 +560,8 @@ import (
 	plugins               "PLUGINS"
 	point                 "POINT"
 	policy                "POLICY"
+	polygon               "POLYGON"
+	precedes              "PRECEDES"
 	preceding             "PRECEDING"
 	prepare               "PREPARE"
 	preserve              "PRESERVE"
@@ -555,8 +579,10 @@ import (
 	quick                 "QUICK"
 	rateLimit             "RATE_LIMIT"
 	rebuild               "REBUILD"
+	recommend             "RECOMMEND"
 	recover               "RECOVER"
 	redundant             "REDUNDANT"
+	refresh               "REFRESH"
 	reload                "RELOAD"
 	remove                "REMOVE"
 	reorganize            "REORGANIZE"
@@ -620,6 +646,7 @@ import (
 	sqlTsiSecond          "SQL_TSI_SECOND"
 	sqlTsiWeek            "SQL_TSI_WEEK"
 	sqlTsiYear            "SQL_TSI_YEAR"
+	srid                  "SRID"
 	start                 "START"
 	statsAutoRecalc       "STATS_AUTO_RECALC"
 	statsColChoice        "STATS_COL_CHOICE"
@@ -672,6 +699,7 @@ import (
 	validation            "VALIDATION"
 	value                 "VALUE"
 	variables             "VARIABLES"
+	vectorType            "VECTOR"
 	view                  "VIEW"
 	visible               "VISIBLE"
 	wait                  "WAIT"
@@ -938,141 +966,149 @@ import (
 	WindowFuncCall                  "WINDOW function call"
 	RepeatableOpt                   "Repeatable optional in sample clause"
 	ProcedureCall                   "Procedure call with Identifier or identifier"
+	MergeWhenConditionOpt           "MERGE WHEN clause condition optional"
 
 %type	<statement>
-	AdminStmt                  "Check table statement or show ddl statement"
-	AlterDatabaseStmt          "Alter database statement"
-	AlterTableStmt             "Alter table statement"
-	AlterUserStmt              "Alter user statement"
-	AlterInstanceStmt          "Alter instance statement"
-	AlterRangeStmt             "Alter data range configuration statement"
-	AlterPolicyStmt            "Alter Placement Policy statement"
-	AlterResourceGroupStmt     "Alter Resource Group statement"
-	AlterSequenceStmt          "Alter sequence statement"
-	AnalyzeTableStmt           "Analyze table statement"
-	BeginTransactionStmt       "BEGIN TRANSACTION statement"
-	BinlogStmt                 "Binlog base64 statement"
-	BRIEStmt                   "BACKUP or RESTORE statement"
-	CalibrateResourceStmt      "CALIBRATE RESOURCE statement"
-	CommitStmt                 "COMMIT statement"
-	CreateTableStmt            "CREATE TABLE statement"
-	CreateViewStmt             "CREATE VIEW  statement"
-	CreateUserStmt             "CREATE User statement"
-	CreateRoleStmt             "CREATE Role statement"
-	CreateDatabaseStmt         "Create Database Statement"
-	CreateIndexStmt            "CREATE INDEX statement"
-	CreateBindingStmt          "CREATE BINDING statement"
-	CreatePolicyStmt           "CREATE PLACEMENT POLICY statement"
-	CreateProcedureStmt        "CREATE PROCEDURE statement"
-	AddQueryWatchStmt          "ADD QUERY WATCH statement"
-	CreateResourceGroupStmt    "CREATE RESOURCE GROUP statement"
-	CreateSequenceStmt         "CREATE SEQUENCE statement"
-	CreateStatisticsStmt       "CREATE STATISTICS statement"
-	DoStmt                     "Do statement"
-	DropDatabaseStmt           "DROP DATABASE statement"
-	DropIndexStmt              "DROP INDEX statement"
-	DropProcedureStmt          "DROP PROCEDURE statement"
-	DropQueryWatchStmt         "DROP QUERY WATCH statement"
-	DropResourceGroupStmt      "DROP RESOURCE GROUP statement"
-	DropStatisticsStmt         "DROP STATISTICS statement"
-	DropStatsStmt              "DROP STATS statement"
-	DropTableStmt              "DROP TABLE statement"
-	DropSequenceStmt           "DROP SEQUENCE statement"
-	DropUserStmt               "DROP USER"
-	DropRoleStmt               "DROP ROLE"
-	DropViewStmt               "DROP VIEW statement"
-	DropBindingStmt            "DROP BINDING  statement"
-	DropPolicyStmt             "DROP PLACEMENT POLICY statement"
-	DeallocateStmt             "Deallocate prepared statement"
-	DeleteFromStmt             "DELETE FROM statement"
-	DeleteWithoutUsingStmt     "Normal DELETE statement"
-	DeleteWithUsingStmt        "DELETE USING statement"
-	EmptyStmt                  "empty statement"
-	ExecuteStmt                "Execute statement"
-	ExplainStmt                "EXPLAIN statement"
-	ExplainableStmt            "explainable statement"
-	FlushStmt                  "Flush statement"
-	FlashbackTableStmt         "Flashback table statement"
-	FlashbackToTimestampStmt   "Flashback cluster statement"
-	FlashbackDatabaseStmt      "Flashback Database statement"
-	GrantStmt                  "Grant statement"
-	GrantProxyStmt             "Grant proxy statement"
-	GrantRoleStmt              "Grant role statement"
-	InsertIntoStmt             "INSERT INTO statement"
-	CallStmt                   "CALL statement"
-	IndexAdviseStmt            "INDEX ADVISE statement"
-	ImportIntoStmt             "IMPORT INTO statement"
-	ImportFromSelectStmt       "SELECT statement of IMPORT INTO"
-	KillStmt                   "Kill statement"
-	LoadDataStmt               "Load data statement"
-	LoadStatsStmt              "Load statistic statement"
-	LockStatsStmt              "Lock statistic statement"
-	UnlockStatsStmt            "Unlock statistic statement"
-	LockTablesStmt             "Lock tables statement"
-	NonTransactionalDMLStmt    "Non-transactional DML statement"
-	OptimizeTableStmt          "OPTIMIZE statement"
-	PlanReplayerStmt           "Plan replayer statement"
-	PreparedStmt               "PreparedStmt"
-	ProcedureProcStmt          "The entrance of procedure statements which contains all kinds of statements in procedure"
-	ProcedureStatementStmt     "The normal statements in procedure, such as dml, select, set ..."
-	SelectStmt                 "SELECT statement"
-	SelectStmtWithClause       "common table expression SELECT statement"
-	RenameTableStmt            "rename table statement"
-	RenameUserStmt             "rename user statement"
-	ReplaceIntoStmt            "REPLACE INTO statement"
-	RecoverTableStmt           "recover table statement"
-	RevokeStmt                 "Revoke statement"
-	RevokeRoleStmt             "Revoke role statement"
-	RollbackStmt               "ROLLBACK statement"
-	ReleaseSavepointStmt       "RELEASE SAVEPOINT statement"
-	SavepointStmt              "SAVEPOINT statement"
-	SplitRegionStmt            "Split index region statement"
-	SetStmt                    "Set variable statement"
-	ChangeStmt                 "Change statement"
-	SetBindingStmt             "Set binding statement"
-	SetRoleStmt                "Set active role statement"
-	SetDefaultRoleStmt         "Set default statement for some user"
-	ShowStmt                   "Show engines/databases/tables/user/columns/warnings/status statement"
-	Statement                  "statement"
-	TraceStmt                  "TRACE statement"
-	TraceableStmt              "traceable statement"
-	TruncateTableStmt          "TRUNCATE TABLE statement"
-	UnlockTablesStmt           "Unlock tables statement"
-	UpdateStmt                 "UPDATE statement"
-	SetOprStmt                 "Union/Except/Intersect select statement"
-	SetOprStmtWithLimitOrderBy "Union/Except/Intersect select statement with limit and order by"
-	SetOprStmtWoutLimitOrderBy "Union/Except/Intersect select statement without limit and order by"
-	UseStmt                    "USE statement"
-	ShutdownStmt               "SHUTDOWN statement"
-	RestartStmt                "RESTART statement"
-	CreateViewSelectOpt        "Select/Union/Except/Intersect statement in CREATE VIEW ... AS SELECT"
-	BindableStmt               "Statement that can be created binding on"
-	UpdateStmtNoWith           "Update statement without CTE clause"
-	HelpStmt                   "HELP statement"
-	ShardableStmt              "Shardable statement that can be used in non-transactional DMLs"
-	CancelImportStmt           "CANCEL IMPORT JOB statement"
-	ProcedureUnlabeledBlock    "The statement block without label in procedure"
-	ProcedureBlockContent      "The statement block in procedure expressed with 'Begin ... End'"
-	SimpleWhenThen             "Procedure case when then"
-	SearchWhenThen             "Procedure search when then"
-	ProcedureIfstmt            "The if statement in procedure, expressed by if ... elseif .. else ... end if"
-	procedurceElseIfs          "The else block in procedure, expressed by elseif or else or nil"
-	ProcedureIf                "The if block in procedure, expressed by expr then statement procedurceElseIfs"
-	ProcedureUnlabelLoopBlock  "The loop block without label in procedure "
-	ProcedureUnlabelLoopStmt   "The loop statement in procedure, expressed by repeat/do while/loop"
-	ProcedureCaseStmt          "Case statement in procedure, expressed by `case ... when.. then ..`"
-	ProcedureSimpleCase        "The simpe case statement in procedure, expressed by `case expr when expr then statement ... end case`"
-	ProcedureSearchedCase      "The searched case statement in procedure, expressed by `case when expr then statement ... end case`"
-	ProcedureCursorSelectStmt  "The select stmt can used in procedure cursor."
-	ProcedureOpenCur           "The open cursor statement in procedure, expressed by `open ...`"
-	ProcedureCloseCur          "The close cursor statement in procedure, expressed by `close ...`"
-	ProcedureFetchInto         "The fetch into statement in procedure, expressed by `fetch ... into ...`"
-	ProcedureHcond             "The handler value statement in procedure, expressed by condition_value"
-	ProcedurceCond             "The handler code statement in procedure, expressed by code error num or `sqlstate ...`"
-	ProcedureLabeledBlock      "The statement block with label in procedure"
-	ProcedurelabeledLoopStmt   "The loop block with label in procedure"
-	ProcedureIterate           "The iterate statement in procedure, expressed by `iterate ...`"
-	ProcedureLeave             "The leave statement in procedure, expressed by `leave ...`"
+	AdminStmt                   "Check table statement or show ddl statement"
+	AlterDatabaseStmt           "Alter database statement"
+	AlterTableStmt              "Alter table statement"
+	AlterUserStmt               "Alter user statement"
+	AlterInstanceStmt           "Alter instance statement"
+	AlterRangeStmt              "Alter data range configuration statement"
+	AlterPolicyStmt             "Alter Placement Policy statement"
+	AlterResourceGroupStmt      "Alter Resource Group statement"
+	AlterSequenceStmt           "Alter sequence statement"
+	AnalyzeTableStmt            "Analyze table statement"
+	BeginTransactionStmt        "BEGIN TRANSACTION statement"
+	BinlogStmt                  "Binlog base64 statement"
+	BRIEStmt                    "BACKUP or RESTORE statement"
+	CalibrateResourceStmt       "CALIBRATE RESOURCE statement"
+	CommitStmt                  "COMMIT statement"
+	CreateTableStmt             "CREATE TABLE statement"
+	CreateViewStmt              "CREATE VIEW  statement"
+	CreateMaterializedViewStmt  "CREATE MATERIALIZED VIEW statement"
+	CreateUserStmt              "CREATE User statement"
+	CreateRoleStmt              "CREATE Role statement"
+	CreateDatabaseStmt          "Create Database Statement"
+	CreateIndexStmt             "CREATE INDEX statement"
+	CreateBindingStmt           "CREATE BINDING statement"
+	CreatePolicyStmt            "CREATE PLACEMENT POLICY statement"
+	CreateProcedureStmt         "CREATE PROCEDURE statement"
+	CreateTriggerStmt           "CREATE TRIGGER statement"
+	AddQueryWatchStmt           "ADD QUERY WATCH statement"
+	CreateResourceGroupStmt     "CREATE RESOURCE GROUP statement"
+	CreateSequenceStmt          "CREATE SEQUENCE statement"
+	CreateStatisticsStmt        "CREATE STATISTICS statement"
+	DoStmt                      "Do statement"
+	DropDatabaseStmt            "DROP DATABASE statement"
+	DropIndexStmt               "DROP INDEX statement"
+	DropProcedureStmt           "DROP PROCEDURE statement"
+	DropTriggerStmt             "DROP TRIGGER statement"
+	DropQueryWatchStmt          "DROP QUERY WATCH statement"
+	DropResourceGroupStmt       "DROP RESOURCE GROUP statement"
+	DropStatisticsStmt          "DROP STATISTICS statement"
+	DropStatsStmt               "DROP STATS statement"
+	DropTableStmt               "DROP TABLE statement"
+	DropSequenceStmt            "DROP SEQUENCE statement"
+	DropUserStmt                "DROP USER"
+	DropRoleStmt                "DROP ROLE"
+	DropViewStmt                "DROP VIEW statement"
+	DropMaterializedViewStmt    "DROP MATERIALIZED VIEW statement"
+	DropBindingStmt             "DROP BINDING  statement"
+	DropPolicyStmt              "DROP PLACEMENT POLICY statement"
+	DeallocateStmt              "Deallocate prepared statement"
+	DeleteFromStmt              "DELETE FROM statement"
+	DeleteWithoutUsingStmt      "Normal DELETE statement"
+	DeleteWithUsingStmt         "DELETE USING statement"
+	EmptyStmt                   "empty statement"
+	ExecuteStmt                 "Execute statement"
+	ExplainStmt                 "EXPLAIN statement"
+	ExplainableStmt             "explainable statement"
+	FlushStmt                   "Flush statement"
+	FlashbackTableStmt          "Flashback table statement"
+	FlashbackToTimestampStmt    "Flashback cluster statement"
+	FlashbackDatabaseStmt       "Flashback Database statement"
+	GrantStmt                   "Grant statement"
+	GrantProxyStmt              "Grant proxy statement"
+	GrantRoleStmt               "Grant role statement"
+	InsertIntoStmt              "INSERT INTO statement"
+	CallStmt                    "CALL statement"
+	IndexAdviseStmt             "INDEX ADVISE statement"
+	RecommendIndexStmt          "RECOMMEND INDEX statement"
+	ImportIntoStmt              "IMPORT INTO statement"
+	ImportFromSelectStmt        "SELECT statement of IMPORT INTO"
+	KillStmt                    "Kill statement"
+	LoadDataStmt                "Load data statement"
+	LoadStatsStmt               "Load statistic statement"
+	LockStatsStmt               "Lock statistic statement"
+	UnlockStatsStmt             "Unlock statistic statement"
+	LockTablesStmt              "Lock tables statement"
+	MergeStmt                   "MERGE statement"
+	NonTransactionalDMLStmt     "Non-transactional DML statement"
+	OptimizeTableStmt           "OPTIMIZE statement"
+	PlanReplayerStmt            "Plan replayer statement"
+	PreparedStmt                "PreparedStmt"
+	ProcedureProcStmt           "The entrance of procedure statements which contains all kinds of statements in procedure"
+	ProcedureStatementStmt      "The normal statements in procedure, such as dml, select, set ..."
+	SelectStmt                  "SELECT statement"
+	SelectStmtWithClause        "common table expression SELECT statement"
+	RenameTableStmt             "rename table statement"
+	RenameUserStmt              "rename user statement"
+	ReplaceIntoStmt             "REPLACE INTO statement"
+	RecoverTableStmt            "recover table statement"
+	RefreshMaterializedViewStmt "REFRESH MATERIALIZED VIEW statement"
+	RevokeStmt                  "Revoke statement"
+	RevokeRoleStmt              "Revoke role statement"
+	RollbackStmt                "ROLLBACK statement"
+	ReleaseSavepointStmt        "RELEASE SAVEPOINT statement"
+	SavepointStmt               "SAVEPOINT statement"
+	SplitRegionStmt             "Split index region statement"
+	SetStmt                     "Set variable statement"
+	ChangeStmt                  "Change statement"
+	SetBindingStmt              "Set binding statement"
+	SetRoleStmt                 "Set active role statement"
+	SetDefaultRoleStmt          "Set default statement for some user"
+	ShowStmt                    "Show engines/databases/tables/user/columns/warnings/status statement"
+	Statement                   "statement"
+	TraceStmt                   "TRACE statement"
+	TraceableStmt               "traceable statement"
+	TruncateTableStmt           "TRUNCATE TABLE statement"
+	UnlockTablesStmt            "Unlock tables statement"
+	UpdateStmt                  "UPDATE statement"
+	SetOprStmt                  "Union/Except/Intersect select statement"
+	SetOprStmtWithLimitOrderBy  "Union/Except/Intersect select statement with limit and order by"
+	SetOprStmtWoutLimitOrderBy  "Union/Except/Intersect select statement without limit and order by"
+	UseStmt                     "USE statement"
+	ShutdownStmt                "SHUTDOWN statement"
+	RestartStmt                 "RESTART statement"
+	CreateViewSelectOpt         "Select/Union/Except/Intersect statement in CREATE VIEW ... AS SELECT"
+	BindableStmt                "Statement that can be created binding on"
+	UpdateStmtNoWith            "Update statement without CTE clause"
+	HelpStmt                    "HELP statement"
+	ShardableStmt               "Shardable statement that can be used in non-transactional DMLs"
+	CancelImportStmt            "CANCEL IMPORT JOB statement"
+	ProcedureUnlabeledBlock     "The statement block without label in procedure"
+	ProcedureBlockContent       "The statement block in procedure expressed with 'Begin ... End'"
+	SimpleWhenThen              "Procedure case when then"
+	SearchWhenThen              "Procedure search when then"
+	ProcedureIfstmt             "The if statement in procedure, expressed by if ... elseif .. else ... end if"
+	procedurceElseIfs           "The else block in procedure, expressed by elseif or else or nil"
+	ProcedureIf                 "The if block in procedure, expressed by expr then statement procedurceElseIfs"
+	ProcedureUnlabelLoopBlock   "The loop block without label in procedure "
+	ProcedureUnlabelLoopStmt    "The loop statement in procedure, expressed by repeat/do while/loop"
+	ProcedureCaseStmt           "Case statement in procedure, expressed by `case ... when.. then ..`"
+	ProcedureSimpleCase         "The simpe case statement in procedure, expressed by `case expr when expr then statement ... end case`"
+	ProcedureSearchedCase       "The searched case statement in procedure, expressed by `case when expr then statement ... end case`"
+	ProcedureCursorSelectStmt   "The select stmt can used in procedure cursor."
+	ProcedureOpenCur            "The open cursor statement in procedure, expressed by `open ...`"
+	ProcedureCloseCur           "The close cursor statement in procedure, expressed by `close ...`"
+	ProcedureFetchInto          "The fetch into statement in procedure, expressed by `fetch ... into ...`"
+	ProcedureHcond              "The handler value statement in procedure, expressed by condition_value"
+	ProcedurceCond              "The handler code statement in procedure, expressed by code error num or `sqlstate ...`"
+	ProcedureLabeledBlock       "The statement block with label in procedure"
+	ProcedurelabeledLoopStmt    "The loop block with label in procedure"
+	ProcedureIterate            "The iterate statement in procedure, expressed by `iterate ...`"
+	ProcedureLeave              "The leave statement in procedure, expressed by `leave ...`"
 
 %type	<item>
 	AdminShowSlow                          "Admin Show Slow statement"
@@ -1196,6 +1232,10 @@ import (
 	IntervalExpr                           "Interval expression"
 	JoinTable                              "join table"
 	JoinType                               "join type"
+	JSONTableColumn                        "JSON_TABLE column definition"
+	JSONTableColumnList                    "JSON_TABLE column definition list"
+	JSONTableOnClauseOpt                   "JSON_TABLE column ON EMPTY and ON ERROR clauses optional"
+	JSONTableOnResponse                    "JSON_TABLE column ON EMPTY or ON ERROR response"
 	KillOrKillTiDB                         "Kill or Kill TiDB"
 	LocationLabelList                      "location label name list"
 	LikeTableWithOrWithoutParen            "LIKE table_name or ( LIKE table_name )"
@@ -1327,6 +1367,13 @@ import (
 	TableAliasRefList                      "table alias reference list"
 	TableAsName                            "table alias name"
 	TableAsNameOpt                         "table alias name optional"
+	MergeWhenClause                        "MERGE WHEN clause"
+	MergeWhenClauseList                    "MERGE WHEN clause list"
+	SpatialType                            "Spatial types"
+	VectorType                             "Vector types"
+	VectorIndexConstraint                  "Vector index constraint"
+	AlterTableAddColumns                   "Columns and constraints added by ALTER TABLE ADD [COLUMN]"
+	SpatialTypeName                        "Spatial type name"
 	TableElement                           "table definition element"
 	TableElementList                       "table definition element list"
 	TableElementListOpt                    "table definition element list optional"
@@ -1373,6 +1420,12 @@ import (
 	ViewAlgorithm                          "view algorithm"
 	ViewCheckOption                        "view check option"
 	ViewDefiner                            "view definer"
+	TriggerTiming                          "trigger action time"
+	TriggerEvent                           "trigger event"
+	TriggerOrderOpt                        "trigger order"
+	MaterializedViewRefreshMethod          "materialized view refresh method"
+	MaterializedViewRefreshMethodOpt       "materialized view refresh method optional"
+	MaterializedViewRefreshOpt             "materialized view refresh clause"
 	ViewName                               "view name"
 	ViewFieldList                          "create view statement field list"
 	ViewSQLSecurity                        "view sql security"
@@ -2167,34 +2220,23 @@ AlterTableSpec:
 		}
 		$$ = op
 	}
-|	"ADD" ColumnKeywordOpt IfNotExists ColumnDef ColumnPosition
+|	"ADD" AlterTableAddColumns
 	{
-		$$ = &ast.AlterTableSpec{
-			IfNotExists: $3.(bool),
-			Tp:          ast.AlterTableAddColumns,
-			NewColumns:  []*ast.ColumnDef{$4.(*ast.ColumnDef)},
-			Position:    $5.(*ast.ColumnPosition),
-		}
+		// The optional COLUMN and IF NOT EXISTS are spelled out rather than using ColumnKeywordOpt and IfNotExists,
+		// so ADD `vector` doesn't conflict with ADD VECTOR INDEX.
+		$$ = $2
 	}
-|	"ADD" ColumnKeywordOpt IfNotExists '(' TableElementList ')'
+|	"ADD" "IF" NotSym "EXISTS" AlterTableAddColumns
 	{
-		tes := $5.([]interface{})
-		var columnDefs []*ast.ColumnDef
-		var constraints []*ast.Constraint
-		for _, te := range tes {
-			switch te := te.(type) {
-			case *ast.ColumnDef:
-				columnDefs = append(columnDefs, te)
-			case *ast.Constraint:
-				constraints = append(constraints, te)
-			}
-		}
-		$$ = &ast.AlterTableSpec{
-			IfNotExists:    $3.(bool),
-			Tp:             ast.AlterTableAddColumns,
-			NewColumns:     columnDefs,
-			NewConstraints: constraints,
-		}
+		spec := $5.(*ast.AlterTableSpec)
+		spec.IfNotExists = true
+		$$ = spec
+	}
+|	"ADD" "COLUMN" IfNotExists AlterTableAddColumns
+	{
+		spec := $4.(*ast.AlterTableSpec)
+		spec.IfNotExists = $3.(bool)
+		$$ = spec
 	}
 |	"ADD" Constraint
 	{
@@ -2808,6 +2850,35 @@ KeyOrIndexOpt:
 	{}
 |	KeyOrIndex
 
+AlterTableAddColumns:
+	ColumnDef ColumnPosition
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:         ast.AlterTableAddColumns,
+			NewColumns: []*ast.ColumnDef{$1.(*ast.ColumnDef)},
+			Position:   $2.(*ast.ColumnPosition),
+		}
+	}
+|	'(' TableElementList ')'
+	{
+		tes := $2.([]interface{})
+		var columnDefs []*ast.ColumnDef
+		var constraints []*ast.Constraint
+		for _, te := range tes {
+			switch te := te.(type) {
+			case *ast.ColumnDef:
+				columnDefs = append(columnDefs, te)
+			case *ast.Constraint:
+				constraints = append(constraints, te)
+			}
+		}
+		$$ = &ast.AlterTableSpec{
+			Tp:             ast.AlterTableAddColumns,
+			NewColumns:     columnDefs,
+			NewConstraints: constraints,
+		}
+	}
+
 ColumnKeywordOpt:
 	/* empty */ %prec empty
 	{}
@@ -2941,6 +3012,22 @@ UserToUser:
 		}
 	}
 
+/*******************************************************************
+ *
+ *  Refresh Materialized View Statement
+ *
+ *  Example:
+ *      REFRESH MATERIALIZED VIEW mv FAST
+ *******************************************************************/
+RefreshMaterializedViewStmt:
+	"REFRESH" "MATERIALIZED" "VIEW" TableName MaterializedViewRefreshMethodOpt
+	{
+		$$ = &ast.RefreshMaterializedViewStmt{
+			ViewName: $4.(*ast.TableName),
+			Method:   $5.(model.MaterializedViewRefreshMethod),
+		}
+	}
+
 /*******************************************************************
  *
  *  Recover Table Statement
@@ -3661,6 +3748,10 @@ ColumnOption:
 	{
 		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionAutoRandom, AutoRandOpt: $2.(ast.AutoRandomOption)}
 	}
+|	"SRID" LengthNum
+	{
+		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionSRID, SRID: $2.(uint64)}
+	}
 
 AutoRandomOpt:
 	{
@@ -4110,7 +4201,7 @@ DropStatisticsStmt:
  *
  * TYPE type_name is recognized as a synonym for USING type_name. However, USING is the preferred form.
  *
- * CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX index_name
+ * CREATE [UNIQUE | FULLTEXT | SPATIAL | VECTOR] INDEX index_name
  *     [index_type]
  *     ON tbl_name (key_part,...)
  *     [index_option]
@@ -4247,6 +4338,10 @@ IndexKeyTypeOpt:
 	{
 		$$ = ast.IndexKeyTypeFullText
 	}
+|	"VECTOR"
+	{
+		$$ = ast.IndexKeyTypeVector
+	}
 
 /**************************************AlterDatabaseStmt***************************************
  * See https://dev.mysql.com/doc/refman/5.7/en/alter-database.html
@@ -4946,6 +5041,63 @@ CreateViewStmt:
 		$$ = x
 	}
 
+/*******************************************************************
+ *
+ *  Create Materialized View Statement
+ *
+ *  Example:
+ *      CREATE MATERIALIZED VIEW IF NOT EXISTS mv (a, cnt) REFRESH FAST EVERY '1h'
+ *          AS SELECT a, COUNT(*) FROM t GROUP BY a
+ *******************************************************************/
+CreateMaterializedViewStmt:
+	"CREATE" "MATERIALIZED" "VIEW" IfNotExists ViewName ViewFieldList MaterializedViewRefreshOpt "AS" CreateViewSelectOpt
+	{
+		x := &ast.CreateMaterializedViewStmt{
+			IfNotExists: $4.(bool),
+			ViewName:    $5.(*ast.TableName),
+			Select:      $9.(ast.StmtNode),
+		}
+		if $6 != nil {
+			x.Cols = $6.([]model.CIStr)
+		}
+		if $7 != nil {
+			refresh := $7.(*ast.MaterializedViewRefresh)
+			x.RefreshMethod, x.RefreshInterval = refresh.Method, refresh.Interval
+		}
+		$$ = x
+	}
+
+MaterializedViewRefreshOpt:
+	/* EMPTY */
+	{
+		$$ = nil
+	}
+|	"REFRESH" MaterializedViewRefreshMethod
+	{
+		$$ = &ast.MaterializedViewRefresh{Method: $2.(model.MaterializedViewRefreshMethod)}
+	}
+|	"REFRESH" MaterializedViewRefreshMethodOpt "EVERY" stringLit
+	{
+		$$ = &ast.MaterializedViewRefresh{Method: $2.(model.MaterializedViewRefreshMethod), Interval: $4}
+	}
+
+MaterializedViewRefreshMethodOpt:
+	/* EMPTY */
+	{
+		$$ = model.RefreshMethodUnspecified
+	}
+|	MaterializedViewRefreshMethod
+
+MaterializedViewRefreshMethod:
+	"COMPLETE"
+	{
+		$$ = model.RefreshMethodComplete
+	}
+|	"FAST"
+	{
+		$$ = model.RefreshMethodFast
+	}
+
 OrReplace:
 	/* EMPTY */
 	{
@@ -5208,6 +5360,12 @@ DropViewStmt:
 		$$ = &ast.DropTableStmt{IfExists: true, Tables: $5.([]*ast.TableName), IsView: true}
 	}
 
+DropMaterializedViewStmt:
+	"DROP" "MATERIALIZED" "VIEW" IfExists TableName
+	{
+		$$ = &ast.DropMaterializedViewStmt{IfExists: $4.(bool), ViewName: $5.(*ast.TableName)}
+	}
+
 DropUserStmt:
 	"DROP" "USER" UsernameList
 	{
@@ -6561,6 +6719,7 @@ UnReservedKeyword:
 |	"AUTO_ID_CACHE"
 |	"AUTO_INCREMENT"
 |	"AFTER"
+|	"BEFORE"
 |	"ALWAYS"
 |	"AVG"
 |	"BDR"
@@ -6593,6 +6752,8 @@ UnReservedKeyword:
 |	"DO"
 |	"DUPLICATE"
 |	"DYNAMIC"
+|	"EACH"
+|	"EMPTY"
 |	"ENCRYPTION"
 |	"END"
 |	"ENFORCED"
@@ -6603,6 +6764,7 @@ UnReservedKeyword:
 |	"ERRORS"
 |	"ESCAPE"
 |	"EVOLVE"
+|	"FAST"
 |	"EXECUTE"
 |	"EXTENDED"
 |	"FIELDS"
@@ -6611,6 +6773,7 @@ UnReservedKeyword:
 |	"FIXED"
 |	"FLUSH"
 |	"FOLLOWING"
+|	"FOLLOWS"
 |	"FORMAT"
 |	"FULL"
 |	"GENERAL"
@@ -6634,6 +6797,7 @@ UnReservedKeyword:
 |	"QUICK"
 |	"REBUILD"
 |	"REDUNDANT"
+|	"REFRESH"
 |	"REORGANIZE"
 |	"RESOURCE"
 |	"RESTART"
@@ -6682,6 +6846,17 @@ UnReservedKeyword:
 |	"COMPRESSION"
 |	"KEY_BLOCK_SIZE"
 |	"MASTER"
+|	"MATCHED"
+|	"MATERIALIZED"
+|	"GEOMETRY"
+|	"GEOMCOLLECTION"
+|	"GEOMETRYCOLLECTION"
+|	"LINESTRING"
+|	"POLYGON"
+|	"MULTIPOINT"
+|	"MULTILINESTRING"
+|	"MULTIPOLYGON"
+|	"SRID"
 |	"MAX_ROWS"
 |	"MIN_ROWS"
 |	"NATIONAL"
@@ -6696,6 +6871,7 @@ UnReservedKeyword:
 |	"REPEATABLE"
 |	"RESPECT"
 |	"COMMITTED"
+|	"COMPLETE"
 |	"UNCOMMITTED"
 |	"ONLY"
 |	"SERIAL"
@@ -6720,6 +6896,7 @@ UnReservedKeyword:
 |	"BINDINGS"
 |	"MODIFY"
 |	"EVENTS"
+|	"EVERY"
 |	"PARTITIONS"
 |	"NONE"
 |	"NULLS"
@@ -6736,6 +6913,7 @@ UnReservedKeyword:
 |	"MICROSECOND"
 |	"MINUTE"
 |	"PLUGINS"
+|	"PRECEDES"
 |	"PRECEDING"
 |	"QUERY"
 |	"QUERIES"
@@ -6764,11 +6942,13 @@ UnReservedKeyword:
 |	"UNDEFINED"
 |	"SECURITY"
 |	"CASCADED"
+|	"RECOMMEND"
 |	"RECOVER"
 |	"CIPHER"
 |	"SUBJECT"
 |	"ISSUER"
 |	"X509"
+|	"NESTED"
 |	"NEVER"
 |	"EXPIRE"
 |	"ACCOUNT"
@@ -6793,6 +6973,8 @@ UnReservedKeyword:
 |	"LIST"
 |	"NODEGROUP"
 |	"SYSTEM_TIME"
+|	"ORDINALITY"
+|	"PATH"
 |	"PARTIAL"
 |	"SIMPLE"
 |	"REMOVE"
@@ -6805,6 +6987,7 @@ UnReservedKeyword:
 |	"SECONDARY_LOAD"
 |	"SECONDARY_UNLOAD"
 |	"VALIDATION"
+|	"VECTOR"
 |	"WITHOUT"
 |	"RTREE"
 |	"HYPO"
@@ -7870,6 +8053,13 @@ FunctionNameConflict:
 |	"MONTH"
 |	builtinNow
 |	"POINT"
+|	"LINESTRING"
+|	"POLYGON"
+|	"MULTIPOINT"
+|	"MULTILINESTRING"
+|	"MULTIPOLYGON"
+|	"GEOMETRYCOLLECTION"
+|	"GEOMCOLLECTION"
 |	"QUARTER"
 |	"REPEAT"
 |	"REPLACE"
@@ -9655,12 +9845,111 @@ TableFactor:
 		resultNode := $1.(*ast.SubqueryExpr).Query
 		$$ = &ast.TableSource{Source: resultNode, AsName: $2.(model.CIStr)}
 	}
+|	"LATERAL" SubSelect TableAsNameOpt
+	{
+		resultNode := $2.(*ast.SubqueryExpr).Query
+		$$ = &ast.TableSource{Source: resultNode, AsName: $3.(model.CIStr), Lateral: true}
+	}
 |	'(' TableRefs ')'
 	{
 		j := $2.(*ast.Join)
 		j.ExplicitParens = true
 		$$ = $2
 	}
+|	"JSON_TABLE" '(' Expression ',' stringLit "COLUMNS" '(' JSONTableColumnList ')' ')' TableAsNameOpt
+	{
+		jt := &ast.JSONTable{
+			Expr:    $3,
+			Path:    $5,
+			Columns: $8.([]*ast.JSONTableColumn),
+		}
+		$$ = &ast.TableSource{Source: jt, AsName: $11.(model.CIStr)}
+	}
+
+JSONTableColumnList:
+	JSONTableColumn
+	{
+		$$ = []*ast.JSONTableColumn{$1.(*ast.JSONTableColumn)}
+	}
+|	JSONTableColumnList ',' JSONTableColumn
+	{
+		$$ = append($1.([]*ast.JSONTableColumn), $3.(*ast.JSONTableColumn))
+	}
+
+JSONTableColumn:
+	Identifier "FOR" "ORDINALITY"
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnOrdinality, Name: model.NewCIStr($1)}
+	}
+|	Identifier Type "PATH" stringLit JSONTableOnClauseOpt
+	{
+		onClauses := $5.([]*ast.JSONTableOnResponse)
+		$$ = &ast.JSONTableColumn{
+			Tp:      ast.JSONTableColumnPath,
+			Name:    model.NewCIStr($1),
+			Type:    $2.(*types.FieldType),
+			Path:    $4,
+			OnEmpty: onClauses[0],
+			OnError: onClauses[1],
+		}
+	}
+|	Identifier Type "EXISTS" "PATH" stringLit
+	{
+		$$ = &ast.JSONTableColumn{
+			Tp:   ast.JSONTableColumnExistsPath,
+			Name: model.NewCIStr($1),
+			Type: $2.(*types.FieldType),
+			Path: $5,
+		}
+	}
+|	"NESTED" stringLit "COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = &ast.JSONTableColumn{
+			Tp:      ast.JSONTableColumnNested,
+			Path:    $2,
+			Columns: $5.([]*ast.JSONTableColumn),
+		}
+	}
+|	"NESTED" "PATH" stringLit "COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = &ast.JSONTableColumn{
+			Tp:      ast.JSONTableColumnNested,
+			Path:    $3,
+			Columns: $6.([]*ast.JSONTableColumn),
+		}
+	}
+
+/* JSONTableOnClauseOpt returns the ON EMPTY and ON ERROR responses in order. */
+JSONTableOnClauseOpt:
+	{
+		$$ = []*ast.JSONTableOnResponse{nil, nil}
+	}
+|	JSONTableOnResponse "ON" "EMPTY"
+	{
+		$$ = []*ast.JSONTableOnResponse{$1.(*ast.JSONTableOnResponse), nil}
+	}
+|	JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = []*ast.JSONTableOnResponse{nil, $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "EMPTY" JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = []*ast.JSONTableOnResponse{$1.(*ast.JSONTableOnResponse), $4.(*ast.JSONTableOnResponse)}
+	}
+
+JSONTableOnResponse:
+	"NULL"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}
+	}
+|	"ERROR"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseError}
+	}
+|	"DEFAULT" stringLit
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseDefault, Default: $2}
+	}
 
 PartitionNameListOpt:
 	/* empty */
@@ -12008,11 +12297,13 @@ Statement:
 |	CreateIndexStmt
 |	CreateTableStmt
 |	CreateViewStmt
+|	CreateMaterializedViewStmt
 |	CreateUserStmt
 |	CreateRoleStmt
 |	CreateBindingStmt
 |	CreatePolicyStmt
 |	CreateProcedureStmt
+|	CreateTriggerStmt
 |	CreateResourceGroupStmt
 |	AddQueryWatchStmt
 |	CreateSequenceStmt
@@ -12022,9 +12313,11 @@ Statement:
 |	DropIndexStmt
 |	DropTableStmt
 |	DropProcedureStmt
+|	DropTriggerStmt
 |	DropPolicyStmt
 |	DropSequenceStmt
 |	DropViewStmt
+|	DropMaterializedViewStmt
 |	DropUserStmt
 |	DropResourceGroupStmt
 |	DropQueryWatchStmt
@@ -12048,13 +12341,16 @@ Statement:
 |	LoadStatsStmt
 |	LockStatsStmt
 |	UnlockStatsStmt
+|	MergeStmt
 |	PlanReplayerStmt
 |	PreparedStmt
+|	RecommendIndexStmt
 |	RollbackStmt
 |	RenameTableStmt
 |	RenameUserStmt
 |	ReplaceIntoStmt
 |	RecoverTableStmt
+|	RefreshMaterializedViewStmt
 |	ReleaseSavepointStmt
 |	RevokeStmt
 |	RevokeRoleStmt
@@ -12127,6 +12423,7 @@ TraceableStmt:
 ExplainableStmt:
 	DeleteFromStmt
 |	UpdateStmt
+|	MergeStmt
 |	InsertIntoStmt
 |	ReplaceIntoStmt
 |	SetOprStmt
@@ -12180,6 +12477,25 @@ Constraint:
 		}
 		$$ = cst
 	}
+|	VectorIndexConstraint
+
+/* VECTOR is an unreserved keyword, the VECTOR index can't be prefixed by ConstraintKeywordOpt, otherwise the empty
+ * ConstraintKeywordOpt conflicts with the column named `vector`. */
+VectorIndexConstraint:
+	"VECTOR" KeyOrIndex IfNotExists IndexName '(' IndexPartSpecificationList ')' IndexOptionList
+	{
+		c := &ast.Constraint{
+			IfNotExists:  $3.(bool),
+			Tp:           ast.ConstraintVector,
+			Keys:         $6.([]*ast.IndexPartSpecification),
+			Name:         $4.(*ast.NullString).String,
+			IsEmptyIndex: $4.(*ast.NullString).Empty,
+		}
+		if $8 != nil {
+			c.Option = $8.(*ast.IndexOption)
+		}
+		$$ = c
+	}
 
 CheckConstraintKeyword:
 	"CHECK"
@@ -12557,6 +12873,8 @@ Type:
 	NumericType
 |	StringType
 |	DateAndTimeType
+|	SpatialType
+|	VectorType
 
 NumericType:
 	IntegerType OptFieldLen FieldOpts
@@ -13088,6 +13406,65 @@ DateAndTimeType:
 		$$ = tp
 	}
 
+SpatialType:
+	SpatialTypeName
+	{
+		tp := types.NewFieldType(mysql.TypeGeometry)
+		tp.SetGeometryType($1.(types.GeometryType))
+		tp.SetCharset(charset.CharsetBin)
+		tp.SetCollate(charset.CollationBin)
+		tp.AddFlag(mysql.BinaryFlag)
+		$$ = tp
+	}
+
+VectorType:
+	"VECTOR" OptFieldLen
+	{
+		tp := types.NewFieldType(mysql.TypeTiDBVectorFloat32)
+		tp.SetFlen($2.(int))
+		tp.SetCharset(charset.CharsetBin)
+		tp.SetCollate(charset.CollationBin)
+		$$ = tp
+	}
+
+SpatialTypeName:
+	"GEOMETRY"
+	{
+		$$ = types.GeometryTypeGeometry
+	}
+|	"POINT"
+	{
+		$$ = types.GeometryTypePoint
+	}
+|	"LINESTRING"
+	{
+		$$ = types.GeometryTypeLineString
+	}
+|	"POLYGON"
+	{
+		$$ = types.GeometryTypePolygon
+	}
+|	"MULTIPOINT"
+	{
+		$$ = types.GeometryTypeMultiPoint
+	}
+|	"MULTILINESTRING"
+	{
+		$$ = types.GeometryTypeMultiLineString
+	}
+|	"MULTIPOLYGON"
+	{
+		$$ = types.GeometryTypeMultiPolygon
+	}
+|	"GEOMETRYCOLLECTION"
+	{
+		$$ = types.GeometryTypeGeometryCollection
+	}
+|	"GEOMCOLLECTION"
+	{
+		$$ = types.GeometryTypeGeometryCollection
+	}
+
 FieldLen:
 	'(' LengthNum ')'
 	{
@@ -13804,6 +14181,87 @@ BindableStmt:
 |	InsertIntoStmt
 |	ReplaceIntoStmt
 
+/*******************************************************************
+ *
+ *  Merge Statement
+ *
+ *  Example:
+ *      MERGE INTO t USING s ON t.id = s.id
+ *          WHEN MATCHED AND s.deleted THEN DELETE
+ *          WHEN MATCHED THEN UPDATE SET t.v = s.v
+ *          WHEN NOT MATCHED THEN INSERT (id, v) VALUES (s.id, s.v)
+ *******************************************************************/
+MergeStmt:
+	"MERGE" TableOptimizerHintsOpt "INTO" TableName TableAsNameOpt "USING" TableFactor "ON" Expression MergeWhenClauseList
+	{
+		x := &ast.MergeStmt{
+			Target:      &ast.TableSource{Source: $4.(*ast.TableName), AsName: $5.(model.CIStr)},
+			Source:      $7.(ast.ResultSetNode),
+			On:          $9,
+			WhenClauses: $10.([]*ast.MergeWhenClause),
+		}
+		if $2 != nil {
+			x.TableHints = $2.([]*ast.TableOptimizerHint)
+		}
+		$$ = x
+	}
+
+MergeWhenClauseList:
+	MergeWhenClause
+	{
+		$$ = []*ast.MergeWhenClause{$1.(*ast.MergeWhenClause)}
+	}
+|	MergeWhenClauseList MergeWhenClause
+	{
+		$$ = append($1.([]*ast.MergeWhenClause), $2.(*ast.MergeWhenClause))
+	}
+
+MergeWhenClause:
+	"WHEN" "MATCHED" MergeWhenConditionOpt "THEN" "UPDATE" "SET" AssignmentList
+	{
+		$$ = &ast.MergeWhenClause{
+			Matched:     true,
+			Condition:   $3,
+			Action:      ast.MergeActionUpdate,
+			Assignments: $7.([]*ast.Assignment),
+		}
+	}
+|	"WHEN" "MATCHED" MergeWhenConditionOpt "THEN" "DELETE"
+	{
+		$$ = &ast.MergeWhenClause{
+			Matched:   true,
+			Condition: $3,
+			Action:    ast.MergeActionDelete,
+		}
+	}
+|	"WHEN" "NOT" "MATCHED" MergeWhenConditionOpt "THEN" "INSERT" ValueSym RowValue
+	{
+		$$ = &ast.MergeWhenClause{
+			Condition: $4,
+			Action:    ast.MergeActionInsert,
+			Values:    $8.([]ast.ExprNode),
+		}
+	}
+|	"WHEN" "NOT" "MATCHED" MergeWhenConditionOpt "THEN" "INSERT" '(' ColumnNameListOpt ')' ValueSym RowValue
+	{
+		$$ = &ast.MergeWhenClause{
+			Condition: $4,
+			Action:    ast.MergeActionInsert,
+			Columns:   $8.([]*ast.ColumnName),
+			Values:    $11.([]ast.ExprNode),
+		}
+	}
+
+MergeWhenConditionOpt:
+	/* EMPTY */
+	{
+		$$ = nil
+	}
+|	"AND" Expression
+	{
+		$$ = $2
+	}
+
 /*******************************************************************
  *
  *  Create Binding Statement
@@ -15186,6 +15644,24 @@ IndexAdviseStmt:
 		$$ = x
 	}
 
+/*******************************************************************
+ *
+ *  Recommend Index Statement
+ *
+ *  Example:
+ *      RECOMMEND INDEX RUN
+ *      RECOMMEND INDEX RUN FOR 'SELECT * FROM t WHERE a = 1'
+ *******************************************************************/
+RecommendIndexStmt:
+	"RECOMMEND" "INDEX" "RUN"
+	{
+		$$ = &ast.RecommendIndexStmt{}
+	}
+|	"RECOMMEND" "INDEX" "RUN" "FOR" stringLit
+	{
+		$$ = &ast.RecommendIndexStmt{SQL: $5}
+	}
+
 MaxMinutesOpt:
 	{
 		$$ = uint64(ast.UnspecifiedSize)
@@ -15519,6 +15995,8 @@ ProcedureStatementStmt:
 |	DeleteFromStmt
 |	AnalyzeTableStmt
 |	TruncateTableStmt
+|	CallStmt
+|	DoStmt
 
 ProcedureCursorSelectStmt:
 	SelectStmt
@@ -15909,6 +16387,12 @@ ProcedureUnlabelLoopStmt:
 			Condition: $4.(ast.ExprNode),
 		}
 	}
+|	"LOOP" ProcedureProcStmt1s "END" "LOOP"
+	{
+		$$ = &ast.ProcedureLoopStmt{
+			Body: $2.([]ast.StmtNode),
+		}
+	}
 
 ProcedureLabeledBlock:
 	identifier ':' ProcedureBlockContent ProcedurceLabelOpt
@@ -16031,6 +16515,97 @@ DropProcedureStmt:
 		}
 	}
 
+/********************************************************************************************
+ *
+ *  Create Trigger Statement
+ *
+ *  Example:
+ *  CREATE
+ *  [DEFINER = user]
+ *  TRIGGER [IF NOT EXISTS] trigger_name
+ *  trigger_time trigger_event
+ *  ON tbl_name FOR EACH ROW
+ *  [trigger_order]
+ *  trigger_body
+ *  trigger_time: { BEFORE | AFTER }
+ *  trigger_event: { INSERT | UPDATE | DELETE }
+ *  trigger_order: { FOLLOWS | PRECEDES } other_trigger_name
+ ********************************************************************************************/
+CreateTriggerStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "TRIGGER" IfNotExists TableName TriggerTiming TriggerEvent "ON" TableName "FOR" "EACH" "ROW" TriggerOrderOpt ProcedureProcStmt
+	{
+		// The OR REPLACE and ALGORITHM clauses share the prefix with CREATE VIEW, they aren't allowed here.
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		x := &ast.CreateTriggerStmt{
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			TriggerName: $7.(*ast.TableName),
+			Timing:      $8.(model.TriggerTiming),
+			Event:       $9.(model.TriggerEvent),
+			Table:       $11.(*ast.TableName),
+			Body:        $16,
+		}
+		if $15 != nil {
+			x.Order = $15.(*ast.TriggerOrder)
+		}
+		startOffset := parser.startOffset(&yyS[yypt])
+		x.Body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:parser.yylval.offset]))
+		$$ = x
+	}
+
+TriggerTiming:
+	"BEFORE"
+	{
+		$$ = model.TriggerTimingBefore
+	}
+|	"AFTER"
+	{
+		$$ = model.TriggerTimingAfter
+	}
+
+TriggerEvent:
+	"INSERT"
+	{
+		$$ = model.TriggerEventInsert
+	}
+|	"UPDATE"
+	{
+		$$ = model.TriggerEventUpdate
+	}
+|	"DELETE"
+	{
+		$$ = model.TriggerEventDelete
+	}
+
+TriggerOrderOpt:
+	/* EMPTY */
+	{
+		$$ = nil
+	}
+|	"FOLLOWS" Identifier
+	{
+		$$ = &ast.TriggerOrder{Follows: true, TriggerName: model.NewCIStr($2)}
+	}
+|	"PRECEDES" Identifier
+	{
+		$$ = &ast.TriggerOrder{TriggerName: model.NewCIStr($2)}
+	}
+
+/********************************************************************************************
+*  DROP TRIGGER [IF EXISTS] [schema_name.]trigger_name
+********************************************************************************************/
+DropTriggerStmt:
+	"DROP" "TRIGGER" IfExists TableName
+	{
+		$$ = &ast.DropTriggerStmt{
+			IfExists:    $3.(bool),
+			TriggerName: $4.(*ast.TableName),
+		}
+	}
+
 /********************************************************************
  *
  * Calibrate Resource Statement
@@ -900,6 +900,17 @@ func TestDMLStmt(t *testing.T) {
 		// fail case for update statement
 		{"UPDATE items,month SET items.price=month.price WHERE items.id=month.id LIMIT 10;", false, ""},
 		{"UPDATE items,month SET items.price=month.price WHERE items.id=month.id order by month.id;", false, ""},
+		// for merge statement
+		{"MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN UPDATE SET t.v = s.v WHEN NOT MATCHED THEN INSERT VALUES (s.id, s.v)", true, "MERGE INTO `t` USING `s` ON `t`.`id`=`s`.`id` WHEN MATCHED THEN UPDATE SET `t`.`v`=`s`.`v` WHEN NOT MATCHED THEN INSERT VALUES (`s`.`id`,`s`.`v`)"},
+		{"merge into t as x using (select * from s) y on x.id = y.id when matched and y.d then delete when matched then update set v = y.v, w = default when not matched and y.v > 0 then insert (id, v) value (y.id, y.v)", true, "MERGE INTO `t` AS `x` USING (SELECT * FROM `s`) AS `y` ON `x`.`id`=`y`.`id` WHEN MATCHED AND `y`.`d` THEN DELETE WHEN MATCHED THEN UPDATE SET `v`=`y`.`v`, `w`=DEFAULT WHEN NOT MATCHED AND `y`.`v`>0 THEN INSERT (`id`,`v`) VALUES (`y`.`id`,`y`.`v`)"},
+		{"MERGE /*+ HASH_JOIN(t, s) */ INTO test.t USING test.s ON t.id = s.id WHEN NOT MATCHED THEN INSERT (id) VALUES (s.id)", true, "MERGE /*+ HASH_JOIN(`t`, `s`)*/ INTO `test`.`t` USING `test`.`s` ON `t`.`id`=`s`.`id` WHEN NOT MATCHED THEN INSERT (`id`) VALUES (`s`.`id`)"},
+		{"MERGE INTO t USING s ON t.id = s.id", false, ""},
+		{"MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN INSERT VALUES (1)", false, ""},
+		{"MERGE INTO t USING s ON t.id = s.id WHEN NOT MATCHED THEN DELETE", false, ""},
+		{"MERGE INTO t USING s WHEN MATCHED THEN DELETE", false, ""},
+		{"EXPLAIN MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE", true, "EXPLAIN FORMAT = 'row' MERGE INTO `t` USING `s` ON `t`.`id`=`s`.`id` WHEN MATCHED THEN DELETE"},
+		{"create table matched (matched int)", true, "CREATE TABLE `matched` (`matched` INT)"},
+
 		// for "USE INDEX" in delete statement
 		{"UPDATE t1 USE INDEX(idx_a) SET t1.price=3.25 WHERE t1.id=1;", true, "UPDATE `t1` USE INDEX (`idx_a`) SET `t1`.`price`=3.25 WHERE `t1`.`id`=1"},
 		{"UPDATE t1 USE INDEX(idx_a) JOIN t2 SET t1.price=t2.price WHERE t1.id=t2.id;", true, "UPDATE `t1` USE INDEX (`idx_a`) JOIN `t2` SET `t1`.`price`=`t2`.`price` WHERE `t1`.`id`=`t2`.`id`"},
@@ -3704,6 +3715,28 @@ func TestDDL(t *testing.T) {
 		{"drop sequence seq seq2", false, ""},
 		{"drop sequence seq, seq2", true, "DROP SEQUENCE `seq`, `seq2`"},
 
+		// for spatial types
+		{"create table t (a geometry, b point not null srid 4326, c linestring, d polygon srid 0)", true, "CREATE TABLE `t` (`a` GEOMETRY,`b` POINT NOT NULL SRID 4326,`c` LINESTRING,`d` POLYGON SRID 0)"},
+		{"create table t (a multipoint, b multilinestring, c multipolygon, d geometrycollection, e geomcollection)", true, "CREATE TABLE `t` (`a` MULTIPOINT,`b` MULTILINESTRING,`c` MULTIPOLYGON,`d` GEOMCOLLECTION,`e` GEOMCOLLECTION)"},
+		{"alter table t add column p point srid 3857", true, "ALTER TABLE `t` ADD COLUMN `p` POINT SRID 3857"},
+		{"create table t (a point srid)", false, ""},
+		{"create table point (point point, polygon int, srid int)", true, "CREATE TABLE `point` (`point` POINT,`polygon` INT,`srid` INT)"},
+		{"select point(1, 2), polygon(linestring(point(0, 0), point(1, 1), point(1, 0), point(0, 0)))", true, "SELECT POINT(1, 2),POLYGON(LINESTRING(POINT(0, 0), POINT(1, 1), POINT(1, 0), POINT(0, 0)))"},
+
+		// for vector types
+		{"create table t (a vector, b vector(3) not null)", true, "CREATE TABLE `t` (`a` VECTOR,`b` VECTOR(3) NOT NULL)"},
+		{"create table t (a vector(3), vector index idx ((vec_cosine_distance(a))) comment 'c')", true, "CREATE TABLE `t` (`a` VECTOR(3),VECTOR INDEX `idx`((VEC_COSINE_DISTANCE(`a`))) COMMENT 'c')"},
+		{"create table t (a vector(3), vector key ((vec_l2_distance(a))))", true, "CREATE TABLE `t` (`a` VECTOR(3),VECTOR INDEX((VEC_L2_DISTANCE(`a`))))"},
+		{"create table t (a vector(3), constraint c vector index ((vec_l2_distance(a))))", false, ""},
+		{"create table vector (vector vector(3), index vector (vector))", true, "CREATE TABLE `vector` (`vector` VECTOR(3),INDEX `vector`(`vector`))"},
+		{"alter table t add vector index idx ((vec_l2_distance(a)))", true, "ALTER TABLE `t` ADD VECTOR INDEX `idx`((VEC_L2_DISTANCE(`a`)))"},
+		{"alter table t add vector index if not exists idx ((vec_l2_distance(a)))", true, "ALTER TABLE `t` ADD VECTOR INDEX IF NOT EXISTS `idx`((VEC_L2_DISTANCE(`a`)))"},
+		{"alter table t add vector vector(3)", true, "ALTER TABLE `t` ADD COLUMN `vector` VECTOR(3)"},
+		{"alter table t add if not exists vector int", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS `vector` INT"},
+		{"alter table t add column if not exists (a int, vector index ((vec_l2_distance(b))))", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS (`a` INT, VECTOR INDEX((VEC_L2_DISTANCE(`b`))))"},
+		{"create vector index idx on t ((vec_negative_inner_product(a)))", true, "CREATE VECTOR INDEX `idx` ON `t` ((VEC_NEGATIVE_INNER_PRODUCT(`a`)))"},
+		{"select vec_l2_distance(a, '[1,2]') from t order by vec_cosine_distance(a, vec_from_text('[1,2]')) limit 1", true, "SELECT VEC_L2_DISTANCE(`a`, _UTF8MB4'[1,2]') FROM `t` ORDER BY VEC_COSINE_DISTANCE(`a`, VEC_FROM_TEXT(_UTF8MB4'[1,2]')) LIMIT 1"},
+
 		// for auto_random
 		{"create table t (a bigint auto_random(3) primary key, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM(3) PRIMARY KEY,`b` VARCHAR(255))"},
 		{"create table t (a bigint auto_random primary key, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM PRIMARY KEY,`b` VARCHAR(255))"},
@@ -5980,6 +6013,44 @@ func TestTableSample(t *testing.T) {
 	}
 }
 
+func TestJSONTable(t *testing.T) {
+	table := []testCase{
+		{`select * from json_table('[1, 2]', '$[*]' columns (a int path '$')) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1, 2]', '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt`"},
+		{`select * from json_table('[1, 2]', '$[*]' columns (a int path '$')) jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1, 2]', '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt`"},
+		{`select * from json_table('[1, 2]', '$[*]' columns (a int path '$'))`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1, 2]', '$[*]' COLUMNS(`a` INT PATH '$'))"},
+		{`select * from json_table('[{"a": 1}]', '$[*]' columns (id for ordinality, a varchar(10) path '$.a', b int exists path '$.b')) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[{\"a\": 1}]', '$[*]' COLUMNS(`id` FOR ORDINALITY, `a` VARCHAR(10) PATH '$.a', `b` INT EXISTS PATH '$.b')) AS `jt`"},
+		{`select * from json_table('[]', '$[*]' columns (a int path '$.a' null on empty)) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[]', '$[*]' COLUMNS(`a` INT PATH '$.a' NULL ON EMPTY)) AS `jt`"},
+		{`select * from json_table('[]', '$[*]' columns (a int path '$.a' error on error)) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[]', '$[*]' COLUMNS(`a` INT PATH '$.a' ERROR ON ERROR)) AS `jt`"},
+		{`select * from json_table('[]', '$[*]' columns (a int path '$.a' default '1' on empty default '2' on error)) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[]', '$[*]' COLUMNS(`a` INT PATH '$.a' DEFAULT '1' ON EMPTY DEFAULT '2' ON ERROR)) AS `jt`"},
+		{`select * from json_table('[]', '$[*]' columns (a int path '$.a', nested path '$.b[*]' columns (b int path '$'), nested '$.c[*]' columns (c int path '$'))) as jt`, true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[]', '$[*]' COLUMNS(`a` INT PATH '$.a', NESTED PATH '$.b[*]' COLUMNS(`b` INT PATH '$'), NESTED PATH '$.c[*]' COLUMNS(`c` INT PATH '$'))) AS `jt`"},
+		{`select * from t, json_table(t.j, '$[*]' columns (a int path '$')) as jt`, true, "SELECT * FROM (`t`) JOIN JSON_TABLE(`t`.`j`, '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt`"},
+		{`select * from t left join json_table(t.j, '$[*]' columns (a int path '$')) as jt on true`, true, "SELECT * FROM `t` LEFT JOIN JSON_TABLE(`t`.`j`, '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt` ON TRUE"},
+		{`select path, nested, ordinality, empty from t`, true, "SELECT `path`,`nested`,`ordinality`,`empty` FROM `t`"},
+
+		{`select * from json_table('[]', '$[*]' columns ()) as jt`, false, ""},
+		{`select * from json_table('[]', '$[*]') as jt`, false, ""},
+		{`select * from json_table('[]', '$[*]' columns (a int path '$' error on error null on empty)) as jt`, false, ""},
+		{`select * from json_table('[]', '$[*]' columns (a int exists path '$' null on empty)) as jt`, false, ""},
+		{`create table json_table (a int)`, false, ""},
+	}
+	RunTest(t, table, false)
+}
+
+func TestLateral(t *testing.T) {
+	table := []testCase{
+		{`select * from t, lateral (select * from t1 where t1.a = t.a) as dt`, true, "SELECT * FROM (`t`) JOIN LATERAL (SELECT * FROM `t1` WHERE `t1`.`a`=`t`.`a`) AS `dt`"},
+		{`select * from t, lateral (select * from t1 where t1.a = t.a) dt`, true, "SELECT * FROM (`t`) JOIN LATERAL (SELECT * FROM `t1` WHERE `t1`.`a`=`t`.`a`) AS `dt`"},
+		{`select * from t left join lateral (select * from t1 where t1.a = t.a order by t1.b limit 2) as dt on true`, true, "SELECT * FROM `t` LEFT JOIN LATERAL (SELECT * FROM `t1` WHERE `t1`.`a`=`t`.`a` ORDER BY `t1`.`b` LIMIT 2) AS `dt` ON TRUE"},
+		{`select * from t join lateral (select 1 union select t.a) as dt`, true, "SELECT * FROM `t` JOIN LATERAL (SELECT 1 UNION SELECT `t`.`a`) AS `dt`"},
+
+		{`select * from t, lateral t1`, false, ""},
+		{`select * from t, lateral (t1)`, false, ""},
+		{`select lateral from t`, false, ""},
+		{`create table lateral (a int)`, false, ""},
+	}
+	RunTest(t, table, false)
+}
+
 func TestGeneratedColumn(t *testing.T) {
 	tests := []struct {
 		input string
@@ -6856,6 +6927,20 @@ func TestIndexAdviseStmt(t *testing.T) {
 	RunTest(t, table, false)
 }
 
+func TestRecommendIndexStmt(t *testing.T) {
+	table := []testCase{
+		{"RECOMMEND INDEX RUN", true, "RECOMMEND INDEX RUN"},
+		{"recommend index run for 'select * from t where a = 1'", true, "RECOMMEND INDEX RUN FOR 'select * from t where a = 1'"},
+		{"RECOMMEND INDEX RUN FOR \"select * from t where b = 'x'\"", true, "RECOMMEND INDEX RUN FOR 'select * from t where b = ''x'''"},
+		{"RECOMMEND INDEX", false, ""},
+		{"RECOMMEND INDEX RUN FOR", false, ""},
+		{"RECOMMEND INDEX RUN FOR select 1", false, ""},
+		{"create table recommend (recommend int)", true, "CREATE TABLE `recommend` (`recommend` INT)"},
+	}
+
+	RunTest(t, table, false)
+}
+
 // For BRIE
 func TestBRIE(t *testing.T) {
 	table := []testCase{
@@ -68,6 +68,8 @@ var type2Str = map[byte]string{
 	mysql.TypeVarchar:     "varchar",
 	mysql.TypeVarString:   "var_string",
 	mysql.TypeYear:        "year",
+
+	mysql.TypeTiDBVectorFloat32: "vector",
 }
 
 var str2Type = map[string]byte{
@@ -98,6 +100,7 @@ var str2Type = map[string]byte{
 	"varchar":     mysql.TypeVarchar,
 	"var_string":  mysql.TypeVarString,
 	"year":        mysql.TypeYear,
+	"vector":      mysql.TypeTiDBVectorFloat32,
 }
 
 // TypeStr converts tp to a string.
@@ -141,6 +144,40 @@ func StrToType(ts string) (tp byte) {
 	return mysql.TypeUnspecified
 }
 
+// GeometryType is the subtype of the geometry type, the values are the same as the geometry type codes of WKB.
+type GeometryType byte
+
+// GeometryType types.
+const (
+	GeometryTypeGeometry GeometryType = iota
+	GeometryTypePoint
+	GeometryTypeLineString
+	GeometryTypePolygon
+	GeometryTypeMultiPoint
+	GeometryTypeMultiLineString
+	GeometryTypeMultiPolygon
+	GeometryTypeGeometryCollection
+)
+
+var geometryType2Str = [...]string{
+	GeometryTypeGeometry:           "geometry",
+	GeometryTypePoint:              "point",
+	GeometryTypeLineString:         "linestring",
+	GeometryTypePolygon:            "polygon",
+	GeometryTypeMultiPoint:         "multipoint",
+	GeometryTypeMultiLineString:    "multilinestring",
+	GeometryTypeMultiPolygon:       "multipolygon",
+	GeometryTypeGeometryCollection: "geomcollection",
+}
+
+// String implements the fmt.Stringer interface.
+func (t GeometryType) String() string {
+	if int(t) < len(geometryType2Str) {
+		return geometryType2Str[t]
+	}
+	return geometryType2Str[GeometryTypeGeometry]
+}
+
 var (
 	dig2bytes = [10]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
 )
@@ -0,0 +1,262 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	pctx "github.com/pingcap/tidb/pkg/planner/context"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/hack"
+	"github.com/pingcap/tidb/pkg/util/kvcache"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+const (
+	// dynamicSamplingSize is the max number of sampled rows the filters are evaluated on.
+	dynamicSamplingSize = 1000
+	// dynamicSamplingFullScanRows is the row count under which the whole table is scanned and each row is sampled
+	// with the same probability, otherwise only the first row of each region is read by `TABLESAMPLE REGIONS()`.
+	dynamicSamplingFullScanRows = 10 * dynamicSamplingSize
+	// dynamicSamplingMinRows is the min number of sampled rows which the estimation can be trusted on.
+	dynamicSamplingMinRows = 30
+	// dynamicSamplingCacheCapacity is the max number of the cached dynamic sampling results.
+	dynamicSamplingCacheCapacity = 4096
+)
+
+// dynamicSamplingKey is the digest of the filters on a physical table.
+type dynamicSamplingKey string
+
+// Hash implements the kvcache.Key interface.
+func (k dynamicSamplingKey) Hash() []byte {
+	return hack.Slice(string(k))
+}
+
+type dynamicSamplingResult struct {
+	selectivity float64
+	// modifyCount is the modify count of the table when sampling, the result is outdated once the table is modified.
+	modifyCount int64
+}
+
+var dynamicSamplingCache = struct {
+	sync.Mutex
+	*kvcache.SimpleLRUCache
+}{SimpleLRUCache: kvcache.NewSimpleLRUCache(dynamicSamplingCacheCapacity, 0, 0)}
+
+// ResetDynamicSamplingCache clears the cached dynamic sampling results.
+func ResetDynamicSamplingCache() {
+	dynamicSamplingCache.Lock()
+	defer dynamicSamplingCache.Unlock()
+	dynamicSamplingCache.DeleteAll()
+}
+
+// GetSelectivityByDynamicSampling estimates the selectivity of the filters which can't be estimated by the
+// statistics, by evaluating them on the rows sampled from the table. The total time spent on sampling in a
+// statement is limited by tidb_opt_dynamic_sampling_time_budget, and the results are cached by the digest
+// of the filters until the table is modified.
+func GetSelectivityByDynamicSampling(sctx pctx.PlanContext, coll *statistics.HistColl, filters []expression.Expression) (ok bool, selectivity float64) {
+	vars := sctx.GetSessionVars()
+	// Sampling is done by internal SQL, so we don't sample again when optimizing the internal SQL itself.
+	if !vars.EnableDynamicSampling || vars.InRestrictedSQL || coll.PhysicalID <= 0 || len(filters) == 0 {
+		return false, 0
+	}
+	for _, filter := range filters {
+		if expression.IsMutableEffectsExpr(filter) {
+			return false, 0
+		}
+	}
+	if expression.ContainCorrelatedColumn(filters) || expression.MaybeOverOptimized4PlanCache(sctx.GetExprCtx(), filters) {
+		return false, 0
+	}
+
+	exprStrs := expression.ExprsToStringsForDisplay(filters)
+	slices.Sort(exprStrs)
+	key := dynamicSamplingKey(strconv.FormatInt(coll.PhysicalID, 10) + ":" + strings.Join(exprStrs, " and "))
+	dynamicSamplingCache.Lock()
+	val, hit := dynamicSamplingCache.Get(key)
+	dynamicSamplingCache.Unlock()
+	if hit {
+		result := val.(*dynamicSamplingResult)
+		if result.modifyCount == coll.ModifyCount {
+			return true, result.selectivity
+		}
+	}
+
+	sc := vars.StmtCtx
+	budget := vars.DynamicSamplingTimeBudget - sc.DynamicSamplingElapsed
+	if budget <= 0 {
+		return false, 0
+	}
+	start := time.Now()
+	defer func() {
+		sc.DynamicSamplingElapsed += time.Since(start)
+	}()
+	ctx := sc.OptimizeCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	selectivity, ok, err := sampleFilters(ctx, sctx, coll, filters)
+	if err != nil {
+		logutil.BgLogger().Debug("dynamic sampling failed, use the default selectivity", zap.Error(err))
+		return false, 0
+	}
+	if !ok {
+		return false, 0
+	}
+	dynamicSamplingCache.Lock()
+	dynamicSamplingCache.Put(key, &dynamicSamplingResult{selectivity: selectivity, modifyCount: coll.ModifyCount})
+	dynamicSamplingCache.Unlock()
+	return true, selectivity
+}
+
+// sampleFilters reads the sample rows of the columns used by the filters, and returns the ratio of the sample
+// rows which satisfy the filters.
+func sampleFilters(ctx context.Context, sctx pctx.PlanContext, coll *statistics.HistColl, filters []expression.Expression) (selectivity float64, ok bool, err error) {
+	is := sctx.GetInfoSchema()
+	tblInfo, found := is.TableInfoByID(coll.PhysicalID)
+	partName := ""
+	if !found {
+		var partDef *model.PartitionDefinition
+		tblInfo, _, partDef = is.FindTableInfoByPartitionID(coll.PhysicalID)
+		if tblInfo == nil {
+			return 0, false, nil
+		}
+		partName = partDef.Name.O
+	}
+	// Local temporary tables can't be read by the internal session.
+	if tblInfo.TempTableType == model.TempTableLocal {
+		return 0, false, nil
+	}
+	dbInfo, found := is.SchemaByID(tblInfo.DBID)
+	if !found {
+		return 0, false, nil
+	}
+
+	// Evaluate the filters on a copy to avoid changing the column offsets of the original ones.
+	filters = slices.Clone(filters)
+	for i := range filters {
+		filters[i] = filters[i].Clone()
+	}
+	cols := expression.ExtractColumnsFromExpressions(nil, filters, nil)
+	colInfos := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		offset := slices.IndexFunc(colInfos, func(colInfo *model.ColumnInfo) bool { return colInfo.ID == col.ID })
+		if offset < 0 {
+			colInfo := model.FindColumnInfoByID(tblInfo.Columns, col.ID)
+			if colInfo == nil {
+				return 0, false, nil
+			}
+			offset = len(colInfos)
+			colInfos = append(colInfos, colInfo)
+		}
+		col.Index = offset
+	}
+
+	var sql strings.Builder
+	args := make([]any, 0, len(colInfos)+4)
+	sql.WriteString("select ")
+	for i, colInfo := range colInfos {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString("%n")
+		args = append(args, colInfo.Name.O)
+	}
+	sql.WriteString(" from %n.%n")
+	args = append(args, dbInfo.Name.O, tblInfo.Name.O)
+	if partName != "" {
+		sql.WriteString(" partition(%n)")
+		args = append(args, partName)
+	}
+	if coll.RealtimeCount > dynamicSamplingFullScanRows {
+		sql.WriteString(" tablesample regions()")
+	} else if coll.RealtimeCount > dynamicSamplingSize {
+		// Sample the rows randomly instead of reading the leading ones, which may be skewed. The realtime count may
+		// be outdated, then more rows are sampled but the time is still limited by the budget.
+		sql.WriteString(" where rand() < %?")
+		args = append(args, float64(dynamicSamplingSize)/float64(coll.RealtimeCount))
+	}
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	rows, fields, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseSessionPool}, sql.String(), args...)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(rows) < dynamicSamplingMinRows {
+		return 0, false, nil
+	}
+
+	// Use the reservoir sampling to bound the number of rows the filters are evaluated on.
+	fts := make([]*types.FieldType, 0, len(fields))
+	recordSet := &sqlexec.SimpleRecordSet{ResultFields: fields, Rows: make([][]any, 0, len(rows)), MaxChunkSize: sctx.GetSessionVars().MaxChunkSize}
+	for _, field := range fields {
+		fts = append(fts, &field.Column.FieldType)
+	}
+	for _, row := range rows {
+		datums := row.GetDatumRow(fts)
+		values := make([]any, 0, len(datums))
+		for i := range datums {
+			values = append(values, datums[i].GetValue())
+		}
+		recordSet.Rows = append(recordSet.Rows, values)
+	}
+	builder := &statistics.RowSampleBuilder{
+		RecordSet:       recordSet,
+		Sc:              sctx.GetSessionVars().StmtCtx,
+		Rng:             rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+		ColsFieldType:   fts,
+		Collators:       make([]collate.Collator, len(fts)),
+		MaxSampleSize:   dynamicSamplingSize,
+		MaxFMSketchSize: dynamicSamplingSize,
+	}
+	collector, err := builder.Collect()
+	if err != nil {
+		return 0, false, err
+	}
+	defer collector.DestroyAndPutToPool()
+	samples := collector.Base().Samples
+	chk := chunk.NewChunkWithCapacity(fts, len(samples))
+	for _, sample := range samples {
+		for i := range sample.Columns {
+			chk.AppendDatum(i, &sample.Columns[i])
+		}
+	}
+	selected, err := expression.VectorizedFilter(sctx.GetExprCtx().GetEvalCtx(), sctx.GetSessionVars().EnableVectorizedExpression, filters, chunk.NewIterator4Chunk(chk), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	selectedCnt := 0
+	for _, isTrue := range selected {
+		if isTrue {
+			selectedCnt++
+		}
+	}
+	return float64(selectedCnt) / float64(len(samples)), true, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/planner/context"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"golang.org/x/exp/maps"
+)
+
+// extStatsSelectivityFactor returns the factor to correct the selectivity which is computed by multiplying the
+// selectivities of the columns in usedSets, i.e, under the independence assumption. Only the columns filtered by
+// equal conditions are corrected, by the multi-column NDV and the functional dependency of the extended statistics.
+func extStatsSelectivityFactor(sctx context.PlanContext, coll *statistics.HistColl, usedSets []*StatsNode) float64 {
+	if coll.ExtStats == nil || len(coll.ExtStats.Stats) == 0 {
+		return 1
+	}
+	tc := sctx.GetSessionVars().StmtCtx.TypeCtx()
+	// pointCols maps the column ID to the StatsNode of the column which is only filtered by point ranges.
+	pointCols := make(map[int64]*StatsNode, len(usedSets))
+	for _, set := range usedSets {
+		if set.Tp != ColType || set.partCover || len(set.Ranges) == 0 || set.Selectivity <= 0 {
+			continue
+		}
+		col := coll.Columns[set.ID]
+		if col == nil || col.Info == nil {
+			continue
+		}
+		allPoints := true
+		for _, ran := range set.Ranges {
+			if !ran.IsPointNonNullable(tc) {
+				allPoints = false
+				break
+			}
+		}
+		if allPoints {
+			pointCols[col.Info.ID] = set
+		}
+	}
+	if len(pointCols) < 2 {
+		return 1
+	}
+	names := maps.Keys(coll.ExtStats.Stats)
+	slices.Sort(names)
+	factor := 1.0
+	// The multi-column NDV is applied before the dependency, since it covers all the columns of the group at once.
+	for _, name := range names {
+		item := coll.ExtStats.Stats[name]
+		if item.Tp != ast.StatsTypeCardinality || item.ScalarVals <= 0 {
+			continue
+		}
+		nodes := make([]*StatsNode, 0, len(item.ColIDs))
+		for _, id := range item.ColIDs {
+			node, ok := pointCols[id]
+			if !ok || len(node.Ranges) != 1 {
+				break
+			}
+			nodes = append(nodes, node)
+		}
+		if len(nodes) != len(item.ColIDs) {
+			continue
+		}
+		independent, minSel := 1.0, 1.0
+		for _, node := range nodes {
+			independent *= node.Selectivity
+			minSel = min(minSel, node.Selectivity)
+		}
+		// The rows matching all the values can't be more than the rows matching any one of them.
+		sel := min(minSel, max(independent, 1/item.ScalarVals))
+		factor *= sel / independent
+		for _, id := range item.ColIDs {
+			delete(pointCols, id)
+		}
+	}
+	for _, name := range names {
+		item := coll.ExtStats.Stats[name]
+		if item.Tp != ast.StatsTypeDependency || len(item.ColIDs) != 2 || item.ScalarVals <= 0 {
+			continue
+		}
+		determinant, ok1 := pointCols[item.ColIDs[0]]
+		dependent, ok2 := pointCols[item.ColIDs[1]]
+		if !ok1 || !ok2 {
+			continue
+		}
+		// A fraction `degree` of the rows matching the determinant also match the dependent column, the remaining
+		// ones are assumed to be independent.
+		degree := min(item.ScalarVals, 1)
+		independent := determinant.Selectivity * dependent.Selectivity
+		sel := determinant.Selectivity * (degree + (1-degree)*dependent.Selectivity)
+		sel = min(sel, determinant.Selectivity, dependent.Selectivity)
+		factor *= sel / independent
+		delete(pointCols, item.ColIDs[0])
+		delete(pointCols, item.ColIDs[1])
+	}
+	return factor
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/planner/context"
+	"github.com/pingcap/tidb/pkg/planner/util/debugtrace"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
+)
+
+// FeedbackExprs returns the sorted strings of the expressions, which identify the estimation on them in the
+// cardinality feedback.
+func FeedbackExprs(exprs []expression.Expression) []string {
+	strs := expression.ExprsToStringsForDisplay(exprs)
+	slices.Sort(strs)
+	return strs
+}
+
+// JoinFeedbackExprs returns the sorted strings of the join conditions, which identify the estimation of the join in
+// the cardinality feedback.
+func JoinFeedbackExprs(eqConds []*expression.ScalarFunction, otherConds []expression.Expression) []string {
+	exprs := make([]expression.Expression, 0, len(eqConds)+len(otherConds))
+	for _, cond := range eqConds {
+		exprs = append(exprs, cond)
+	}
+	exprs = append(exprs, otherConds...)
+	return FeedbackExprs(exprs)
+}
+
+// correctSelectivityByFeedback corrects the selectivity of the filters on the table by the cardinality feedback
+// collected from the executed queries.
+func correctSelectivityByFeedback(ctx context.PlanContext, tableID int64, exprs []expression.Expression, selectivity float64) float64 {
+	corrections := feedback.GlobalCollector.GetFilterCorrections(tableID)
+	if len(corrections) == 0 {
+		return selectivity
+	}
+	factor := feedback.CorrectFilters(corrections, FeedbackExprs(exprs))
+	if factor == 1 {
+		return selectivity
+	}
+	if ctx.GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
+		debugtrace.RecordAnyValuesWithNames(ctx, "Cardinality Feedback Correction", factor)
+	}
+	return min(selectivity*factor, 1)
+}
+
+// GetJoinCorrection returns the correction factor of the row count of the inner join of the physical tables with the
+// conditions, collected from the executed queries by the cardinality feedback.
+func GetJoinCorrection(ctx context.PlanContext, tableIDs []int64, eqConds []*expression.ScalarFunction, otherConds []expression.Expression) float64 {
+	if !ctx.GetSessionVars().EnableCardinalityFeedback || len(eqConds)+len(otherConds) == 0 {
+		return 1
+	}
+	keys := feedback.NewJoinKeys(tableIDs, JoinFeedbackExprs(eqConds, otherConds))
+	if len(keys) == 0 {
+		return 1
+	}
+	return feedback.GlobalCollector.GetCorrection(keys[0])
+}
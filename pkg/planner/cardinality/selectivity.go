@@ -215,6 +215,8 @@ func Selectivity(
 		}
 	}
 	usedSets := GetUsableSetsByGreedy(nodes)
+	// Correct the independence assumption between the correlated columns by the extended statistics.
+	ret *= extStatsSelectivityFactor(ctx, coll, usedSets)
 	// Initialize the mask with the full set.
 	mask := (int64(1) << uint(len(remainedExprs))) - 1
 	// curExpr records covered expressions by now. It's for cardinality estimation tracing.
@@ -413,6 +415,23 @@ OUTER:
 		}
 	}
 
+	// Try to estimate the remaining conditions together by evaluating them on the rows sampled from the table.
+	if mask > 0 && ctx.GetSessionVars().EnableDynamicSampling {
+		residualExprs := make([]expression.Expression, 0, len(remainedExprs))
+		for i, expr := range remainedExprs {
+			if mask&(1<<uint64(i)) != 0 {
+				residualExprs = append(residualExprs, expr)
+			}
+		}
+		if ok, sel := GetSelectivityByDynamicSampling(ctx, coll, residualExprs); ok {
+			ret *= sel
+			mask = 0
+			if sc.EnableOptimizerDebugTrace {
+				debugtrace.RecordAnyValuesWithNames(ctx, "Dynamic Sampling Selectivity", sel)
+			}
+		}
+	}
+
 	// At last, if there are still conditions which cannot be estimated, we multiply the selectivity with
 	// the minimal default selectivity of the remaining conditions.
 	// Currently, only string matching functions (like and regexp) may have a different default selectivity,
@@ -439,6 +458,9 @@ OUTER:
 		totalExpr := expression.ComposeCNFCondition(ctx.GetExprCtx(), remainedExprs...)
 		ceTraceExpr(ctx, tableID, "Table Stats-Expression-CNF", totalExpr, ret*float64(coll.RealtimeCount))
 	}
+	if ctx.GetSessionVars().EnableCardinalityFeedback {
+		ret = correctSelectivityByFeedback(ctx, tableID, exprs, ret)
+	}
 	return ret, nodes, nil
 }
 
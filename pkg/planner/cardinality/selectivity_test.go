@@ -21,6 +21,7 @@ import (
 	"os"
 	"runtime/pprof"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -38,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/testkit/testdata"
 	"github.com/pingcap/tidb/pkg/types"
@@ -1347,3 +1349,235 @@ func TestBuiltinInEstWithoutStats(t *testing.T) {
 		"  └─TableFullScan 10.00 cop[tikv] table:t keep order:false, stats:pseudo",
 	))
 }
+
+func TestExtendedStatsSelectivity(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_enable_extended_stats = on")
+	tk.MustExec("create table t(country int, city int, c int)")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		// Every city is in exactly one country.
+		vals = append(vals, fmt.Sprintf("(%d, %d, %d)", i%100%10, i%100, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustExec("alter table t add stats_extended s1 cardinality(country, city)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(city, country)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select name, stats from mysql.stats_extended where name like 's%'").Sort().Check(testkit.Rows(
+		"s1 100.000000",
+		"s2 1.000000",
+	))
+	tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't'").Sort().CheckAt([]int{2, 3, 4, 5}, testkit.Rows(
+		"s1 [country,city] cardinality 100.000000",
+		"s2 [city,country] dependency 1.000000",
+	))
+
+	selectionEstRows := func(query string) string {
+		for _, row := range tk.MustQuery("explain format = 'brief' " + query).Rows() {
+			if strings.Contains(row[0].(string), "Selection") {
+				return row[1].(string)
+			}
+		}
+		return ""
+	}
+	query := "select * from t where country = 3 and city = 13"
+	// The estimation is based on the multi-column NDV.
+	require.Equal(t, "10.00", selectionEstRows(query))
+	// The estimation is based on the dependency degree.
+	tk.MustExec("alter table t drop stats_extended s1")
+	require.Equal(t, "10.00", selectionEstRows(query))
+	// The extended statistics are only applied to the equal conditions.
+	rangeQuery := "select * from t where country = 3 and city > 13"
+	rangeEstRows := selectionEstRows(rangeQuery)
+	// The estimation is based on the independence assumption.
+	tk.MustExec("set @@tidb_enable_extended_stats = off")
+	require.Equal(t, "1.00", selectionEstRows(query))
+	require.Equal(t, rangeEstRows, selectionEstRows(rangeQuery))
+	tk.MustExec("set @@tidb_enable_extended_stats = on")
+	tk.MustExec("alter table t drop stats_extended s2")
+	require.Equal(t, "1.00", selectionEstRows(query))
+
+	// The multi-column NDV is used to estimate the join on the column group.
+	tk.MustExec("create table t2(a int, b int)")
+	vals = vals[:0]
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d)", i%50, i%20))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(vals, ","))
+	tk.MustExec("alter table t2 add stats_extended s4 cardinality(a, b)")
+	tk.MustExec("analyze table t2")
+	tk.MustQuery("select stats from mysql.stats_extended where name = 's4'").Check(testkit.Rows("100.000000"))
+	joinQuery := "select * from t2 x join t2 y on x.a = y.a and x.b = y.b"
+	require.Equal(t, "10000.00", tk.MustQuery("explain format = 'brief' " + joinQuery).Rows()[0][1])
+	tk.MustExec("set @@tidb_enable_extended_stats = off")
+	require.Equal(t, "20000.00", tk.MustQuery("explain format = 'brief' " + joinQuery).Rows()[0][1])
+	tk.MustExec("set @@tidb_enable_extended_stats = on")
+
+	err := tk.ExecToErr("alter table t add stats_extended s3 cardinality(country)")
+	require.EqualError(t, err, "Only support Cardinality statistics type on at least 2 columns")
+	err = tk.ExecToErr("alter table t add stats_extended s3 dependency(country, city, c)")
+	require.EqualError(t, err, "Only support Correlation and Dependency statistics types on 2 columns")
+}
+
+func TestDynamicSampling(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, j json)")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf(`(%d, %d, '{"k": %d}')`, i, i%10, i%5))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t")
+	cardinality.ResetDynamicSamplingCache()
+
+	selectionEstRows := func(query string) string {
+		for _, row := range tk.MustQuery("explain format = 'brief' " + query).Rows() {
+			if strings.Contains(row[0].(string), "Selection") {
+				return row[1].(string)
+			}
+		}
+		return ""
+	}
+	multiColQuery := "select * from t where a + b > 900"
+	jsonQuery := "select * from t where json_extract(j, '$.k') = 3"
+	// The default selectivity is used when the filters can't be estimated by the statistics.
+	require.Equal(t, "800.00", selectionEstRows(multiColQuery))
+	require.Equal(t, "800.00", selectionEstRows(jsonQuery))
+
+	tk.MustExec("set @@tidb_opt_enable_dynamic_sampling = on")
+	// The table is small enough to be fully sampled, so the estimations are exact.
+	require.Equal(t, "103.00", selectionEstRows(multiColQuery))
+	require.Equal(t, "200.00", selectionEstRows(jsonQuery))
+	// The filters estimated by the statistics are not sampled.
+	require.Equal(t, "102.90", selectionEstRows(multiColQuery+" and a > 0"))
+
+	// The cached result is used until the table is modified.
+	tk.MustExec("delete from t where json_extract(j, '$.k') = 3")
+	require.Equal(t, "200.00", selectionEstRows(jsonQuery))
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(dom.InfoSchema()))
+	require.Equal(t, "0.00", selectionEstRows(jsonQuery))
+
+	// The rows of a larger table are sampled randomly, so the estimation is close to the real row count 2495.
+	tk.MustExec("create table t2(a int, b int)")
+	vals = vals[:0]
+	for i := 0; i < 5000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d)", i, i%10))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t2")
+	estRows, err := strconv.ParseFloat(selectionEstRows("select * from t2 where a + b > 2500"), 64)
+	require.NoError(t, err)
+	require.InDelta(t, 2495, estRows, 500)
+}
+
+func TestCardinalityFeedback(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("create table s(a int, b int)")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustExec("insert into s values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t, s")
+	h := dom.StatsHandle()
+	feedback.GlobalCollector.SetCorrections(feedback.NewCorrections())
+	defer feedback.GlobalCollector.SetCorrections(feedback.NewCorrections())
+
+	estRows := func(query, op string) string {
+		for _, row := range tk.MustQuery("explain format = 'brief' " + query).Rows() {
+			if strings.Contains(row[0].(string), op) {
+				return row[1].(string)
+			}
+		}
+		return ""
+	}
+	// The columns a and b are fully correlated, but they are estimated independently, and the other conditions of
+	// the join are not estimated.
+	filterQuery := "select * from t where a < 100 and b < 100"
+	joinQuery := "select /*+ hash_join(t, s) */ * from t, s where t.a = s.a and t.b > s.b"
+	require.Equal(t, "10.00", estRows(filterQuery, "Selection"))
+	require.Equal(t, "1000.00", estRows(joinQuery, "HashJoin"))
+
+	// The feedback isn't recorded if it's disabled.
+	tk.MustQuery(filterQuery)
+	require.Empty(t, feedback.GlobalCollector.GetSamplesAndReset())
+
+	tk.MustExec("set @@tidb_opt_enable_cardinality_feedback = on")
+	for i := 0; i < feedback.MinExecCount-1; i++ {
+		tk.MustQuery(filterQuery)
+		tk.MustQuery(joinQuery)
+	}
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	// The filters pushed down to the join children are estimated well.
+	// The feedback on the join is recorded for each of the joined tables.
+	tk.MustQuery("select kind, exprs, exec_count, misestimate_count, act_rows from mysql.stats_cardinality_feedback order by kind, exprs, table_id").Check(testkit.Rows(
+		`1 ["lt(test.t.a, 100)","lt(test.t.b, 100)"] 2 2 200`,
+		`1 ["not(isnull(test.s.a))","not(isnull(test.s.b))"] 2 0 2000`,
+		`1 ["not(isnull(test.t.a))","not(isnull(test.t.b))"] 2 0 2000`,
+		`2 ["eq(test.t.a, test.s.a)","gt(test.t.b, test.s.b)"] 2 2 0`,
+		`2 ["eq(test.t.a, test.s.a)","gt(test.t.b, test.s.b)"] 2 2 0`,
+	))
+	// Not enough executions to correct the estimations.
+	require.Equal(t, "10.00", estRows(filterQuery, "Selection"))
+	require.Equal(t, "1000.00", estRows(joinQuery, "HashJoin"))
+
+	tk.MustQuery(filterQuery)
+	tk.MustQuery(joinQuery)
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	require.Equal(t, "91.82", estRows(filterQuery, "Selection"))
+	require.Equal(t, "1.00", estRows(joinQuery, "HashJoin"))
+	// The subset of the filters is corrected as well.
+	require.Equal(t, "73.45", estRows(filterQuery+" and a + b < 1000", "Selection"))
+
+	// The feedback recorded against the corrected estimations doesn't change the correction.
+	tk.MustQuery(filterQuery)
+	tk.MustQuery(joinQuery)
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	require.Equal(t, "91.82", estRows(filterQuery, "Selection"))
+	require.Equal(t, "1.00", estRows(joinQuery, "HashJoin"))
+
+	// The feedback on the same join conditions of other tables doesn't apply.
+	tk.MustExec("create table r(a int, b int)")
+	tk.MustExec("insert into r select * from s")
+	tk.MustExec("analyze table r")
+	require.Equal(t, "1000.00", estRows("select /*+ hash_join(t, r) */ * from t, r where t.a = r.a and t.b > r.b", "HashJoin"))
+
+	// The feedback on the join is cleared once any of the joined tables is analyzed again.
+	tk.MustExec("analyze table s")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback where kind = 2").Check(testkit.Rows("0"))
+	require.Equal(t, "91.82", estRows(filterQuery, "Selection"))
+	require.Equal(t, "1000.00", estRows(joinQuery, "HashJoin"))
+
+	// The feedback on the join is cleared once any of the joined tables is dropped.
+	rJoinQuery := "select /*+ hash_join(t, r) */ * from t, r where t.a = r.a and t.b > r.b"
+	for i := 0; i < feedback.MinExecCount; i++ {
+		tk.MustQuery(rJoinQuery)
+	}
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	require.Equal(t, "1.00", estRows(rJoinQuery, "HashJoin"))
+	tk.MustExec("drop table r")
+	require.NoError(t, h.GCStats(dom.InfoSchema(), 0))
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback where kind = 2").Check(testkit.Rows("0"))
+
+	// The feedback is halved once a day and deleted once it decays out.
+	tk.MustExec("update mysql.stats_cardinality_feedback set window_start = now(6) - interval 1 day")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	require.Equal(t, "10.00", estRows(filterQuery, "Selection"))
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("2"))
+	tk.MustExec("update mysql.stats_cardinality_feedback set window_start = now(6) - interval 3 day")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("0"))
+}
@@ -17,6 +17,7 @@ package cascades
 import (
 	"math"
 
+	"github.com/pingcap/tidb/pkg/planner/cardinality"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
 	"github.com/pingcap/tidb/pkg/planner/implementation"
 	"github.com/pingcap/tidb/pkg/planner/memo"
@@ -39,11 +40,22 @@ type Enforcer interface {
 // GetEnforcerRules gets all candidate enforcer rules based
 // on required physical property.
 func GetEnforcerRules(g *memo.Group, prop *property.PhysicalProperty) (enforcers []Enforcer) {
-	if g.EngineType != pattern.EngineTiDB {
-		return
-	}
-	if !prop.IsSortItemEmpty() {
-		enforcers = append(enforcers, orderEnforcer)
+	switch g.EngineType {
+	case pattern.EngineTiDB:
+		if !prop.IsSortItemEmpty() {
+			enforcers = append(enforcers, orderEnforcer)
+		}
+	case pattern.EngineTiFlash:
+		if prop.TaskTp != property.MppTaskType || !prop.CanAddEnforcer {
+			return
+		}
+		if prop.MPPPartitionTp != property.HashType && prop.MPPPartitionTp != property.BroadcastType {
+			return
+		}
+		sctx := g.Equivalents.Front().Value.(*memo.GroupExpr).ExprNode.SCtx()
+		if plannercore.CanEnforceExchanger(sctx, prop) {
+			enforcers = append(enforcers, &MPPExchangeEnforcer{partitionTp: prop.MPPPartitionTp})
+		}
 	}
 	return
 }
@@ -86,3 +98,46 @@ func (*OrderEnforcer) GetEnforceCost(g *memo.Group) float64 {
 	cost := sort.GetCost(g.Prop.Stats.RowCount, g.Prop.Schema)
 	return cost
 }
+
+// MPPExchangeEnforcer enforces MPP partition property on child implementation
+// by exchanging the data between the MPP tasks.
+type MPPExchangeEnforcer struct {
+	partitionTp property.MPPPartitionType
+}
+
+// NewProperty removes MPP partition property from required physical property.
+func (*MPPExchangeEnforcer) NewProperty(prop *property.PhysicalProperty) (newProp *property.PhysicalProperty) {
+	newProp = &property.PhysicalProperty{
+		TaskTp:            property.MppTaskType,
+		ExpectedCnt:       math.MaxFloat64,
+		MPPPartitionTp:    property.AnyType,
+		CTEProducerStatus: prop.CTEProducerStatus,
+	}
+	return
+}
+
+// OnEnforce adds ExchangeSender and ExchangeReceiver to satisfy required MPP
+// partition property.
+func (*MPPExchangeEnforcer) OnEnforce(reqProp *property.PhysicalProperty, child memo.Implementation) (impl memo.Implementation) {
+	receiver := plannercore.EnforceExchanger(child.GetPlan(), reqProp)
+	impl = implementation.NewExchangeReceiverImpl(receiver)
+	return
+}
+
+// GetEnforceCost calculates cost of exchanging the data through network.
+// The broadcast data is sent to every TiFlash node.
+func (e *MPPExchangeEnforcer) GetEnforceCost(g *memo.Group) float64 {
+	sctx := g.Equivalents.Front().Value.(*memo.GroupExpr).ExprNode.SCtx()
+	sessVars := sctx.GetSessionVars()
+	width := 1.0
+	if hists := g.Prop.Stats.HistColl; hists != nil {
+		width = cardinality.GetAvgRowSize(sctx, hists, g.Prop.Schema.Columns, false, false)
+	}
+	cost := g.Prop.Stats.RowCount * sessVars.GetNetworkFactor(nil) * width
+	if e.partitionTp == property.BroadcastType {
+		if storeCnt, err := sctx.GetMPPClient().GetMPPStoreCount(); err == nil && storeCnt > 1 {
+			cost *= float64(storeCnt)
+		}
+	}
+	return cost
+}
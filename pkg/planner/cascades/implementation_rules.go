@@ -54,6 +54,12 @@ var defaultImplementationMap = map[pattern.Operand][]ImplementationRule{
 	pattern.OperandTiKVSingleGather: {
 		&ImplTiKVSingleReadGather{},
 	},
+	pattern.OperandTiKVIndexMergeGather: {
+		&ImplTiKVIndexMergeGather{},
+	},
+	pattern.OperandMPPGather: {
+		&ImplMPPGather{},
+	},
 	pattern.OperandShow: {
 		&ImplShow{},
 	},
@@ -77,6 +83,7 @@ var defaultImplementationMap = map[pattern.Operand][]ImplementationRule{
 		&ImplHashJoinBuildLeft{},
 		&ImplHashJoinBuildRight{},
 		&ImplMergeJoin{},
+		&ImplMPPHashJoin{},
 	},
 	pattern.OperandUnionAll: {
 		&ImplUnionAll{},
@@ -90,6 +97,9 @@ var defaultImplementationMap = map[pattern.Operand][]ImplementationRule{
 	pattern.OperandWindow: {
 		&ImplWindow{},
 	},
+	pattern.OperandCTE: {
+		&ImplCTE{},
+	},
 }
 
 // ImplTableDual implements LogicalTableDual as PhysicalTableDual.
@@ -184,6 +194,50 @@ func (*ImplTiKVSingleReadGather) OnImplement(expr *memo.GroupExpr, reqProp *prop
 	return []memo.Implementation{impl.NewTableReaderImpl(reader, sg.Source)}, nil
 }
 
+// ImplTiKVIndexMergeGather implements TiKVIndexMergeGather as PhysicalIndexMergeReader.
+type ImplTiKVIndexMergeGather struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (*ImplTiKVIndexMergeGather) Match(_ *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return prop.TaskTp == property.RootTaskType
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (*ImplTiKVIndexMergeGather) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	g := expr.ExprNode.(*plannercore.TiKVIndexMergeGather)
+	reader, err := g.GetPhysicalIndexMergeReader(reqProp)
+	if err != nil || reader == nil {
+		return nil, err
+	}
+	return []memo.Implementation{impl.NewIndexMergeReaderImpl(reader)}, nil
+}
+
+// ImplMPPGather implements MPPGather as PhysicalTableReader reading from the
+// MPP tasks of TiFlash.
+type ImplMPPGather struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (*ImplMPPGather) Match(_ *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	// The results of the MPP tasks are not in order.
+	return prop.TaskTp == property.RootTaskType && prop.IsSortItemEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (*ImplMPPGather) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	logicProp := expr.Group.Prop
+	g := expr.ExprNode.(*plannercore.MPPGather)
+	childProp := &property.PhysicalProperty{
+		TaskTp:            property.MppTaskType,
+		ExpectedCnt:       math.MaxFloat64,
+		MPPPartitionTp:    property.AnyType,
+		CTEProducerStatus: reqProp.CTEProducerStatus,
+	}
+	reader := g.GetPhysicalMPPReader(logicProp.Schema, logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), childProp)
+	return []memo.Implementation{impl.NewMPPReaderImpl(reader, g.PruningConds)}, nil
+}
+
 // ImplTableScan implements TableScan as PhysicalTableScan.
 type ImplTableScan struct {
 }
@@ -191,6 +245,10 @@ type ImplTableScan struct {
 // Match implements ImplementationRule Match interface.
 func (*ImplTableScan) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
 	ts := expr.ExprNode.(*plannercore.LogicalTableScan)
+	if expr.Group.EngineType == pattern.EngineTiFlash {
+		// The data of the table scan in MPP tasks is not partitioned by any columns.
+		return prop.TaskTp == property.MppTaskType && prop.MPPPartitionTp == property.AnyType && prop.IsSortItemEmpty()
+	}
 	return prop.IsSortItemEmpty() || (len(prop.SortItems) == 1 && ts.HandleCols != nil && prop.SortItems[0].Col.EqualColumn(ts.HandleCols.GetCol(0)))
 }
 
@@ -198,12 +256,16 @@ func (*ImplTableScan) Match(expr *memo.GroupExpr, prop *property.PhysicalPropert
 func (*ImplTableScan) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
 	logicProp := expr.Group.Prop
 	logicalScan := expr.ExprNode.(*plannercore.LogicalTableScan)
+	tblCols, tblColHists := logicalScan.Source.TblCols, logicalScan.Source.TblColHists
+	if expr.Group.EngineType == pattern.EngineTiFlash {
+		ts := logicalScan.GetPhysicalTiFlashScan(logicProp.Schema, logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt))
+		return []memo.Implementation{impl.NewTableScanImpl(ts, tblCols, tblColHists)}, nil
+	}
 	ts := logicalScan.GetPhysicalScan(logicProp.Schema, logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt))
 	if !reqProp.IsSortItemEmpty() {
 		ts.KeepOrder = true
 		ts.Desc = reqProp.SortItems[0].Desc
 	}
-	tblCols, tblColHists := logicalScan.Source.TblCols, logicalScan.Source.TblColHists
 	return []memo.Implementation{impl.NewTableScanImpl(ts, tblCols, tblColHists)}, nil
 }
 
@@ -277,6 +339,8 @@ func (*ImplSelection) OnImplement(expr *memo.GroupExpr, reqProp *property.Physic
 		return []memo.Implementation{impl.NewTiDBSelectionImpl(physicalSel)}, nil
 	case pattern.EngineTiKV:
 		return []memo.Implementation{impl.NewTiKVSelectionImpl(physicalSel)}, nil
+	case pattern.EngineTiFlash:
+		return []memo.Implementation{impl.NewTiFlashSelectionImpl(physicalSel)}, nil
 	default:
 		return nil, plannererrors.ErrInternal.GenWithStack("Unsupported EngineType '%s' for Selection.", expr.Group.EngineType.String())
 	}
@@ -448,6 +512,9 @@ type ImplHashJoinBuildLeft struct {
 
 // Match implements ImplementationRule Match interface.
 func (*ImplHashJoinBuildLeft) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	if expr.Group.EngineType != pattern.EngineTiDB {
+		return false
+	}
 	switch expr.ExprNode.(*plannercore.LogicalJoin).JoinType {
 	case plannercore.InnerJoin, plannercore.LeftOuterJoin, plannercore.RightOuterJoin:
 		return prop.IsSortItemEmpty()
@@ -476,8 +543,8 @@ type ImplHashJoinBuildRight struct {
 }
 
 // Match implements ImplementationRule Match interface.
-func (*ImplHashJoinBuildRight) Match(_ *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	return prop.IsSortItemEmpty()
+func (*ImplHashJoinBuildRight) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return expr.Group.EngineType == pattern.EngineTiDB && prop.IsSortItemEmpty()
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -502,8 +569,8 @@ type ImplMergeJoin struct {
 }
 
 // Match implements ImplementationRule Match interface.
-func (*ImplMergeJoin) Match(_ *memo.GroupExpr, _ *property.PhysicalProperty) (matched bool) {
-	return true
+func (*ImplMergeJoin) Match(expr *memo.GroupExpr, _ *property.PhysicalProperty) (matched bool) {
+	return expr.Group.EngineType == pattern.EngineTiDB
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -518,6 +585,27 @@ func (*ImplMergeJoin) OnImplement(expr *memo.GroupExpr, reqProp *property.Physic
 	return mergeJoinImpls, nil
 }
 
+// ImplMPPHashJoin implements LogicalJoin to PhysicalHashJoin running in the
+// MPP tasks of TiFlash.
+type ImplMPPHashJoin struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (*ImplMPPHashJoin) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return expr.Group.EngineType == pattern.EngineTiFlash && prop.TaskTp == property.MppTaskType && prop.IsSortItemEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (*ImplMPPHashJoin) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	physicalHashJoins := join.GetMPPHashJoins(reqProp, expr.Schema(), expr.Group.Prop.Stats, expr.Children[0].Prop.Stats, expr.Children[1].Prop.Stats)
+	hashJoinImpls := make([]memo.Implementation, 0, len(physicalHashJoins))
+	for _, physicalPlan := range physicalHashJoins {
+		hashJoinImpls = append(hashJoinImpls, impl.NewHashJoinImpl(physicalPlan.(*plannercore.PhysicalHashJoin)))
+	}
+	return hashJoinImpls, nil
+}
+
 // ImplUnionAll implements LogicalUnionAll to PhysicalUnionAll.
 type ImplUnionAll struct {
 }
@@ -624,3 +712,21 @@ func (*ImplWindow) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalP
 	physicalWindow.SetSchema(expr.Group.Prop.Schema)
 	return []memo.Implementation{impl.NewWindowImpl(physicalWindow)}, nil
 }
+
+// ImplCTE implements LogicalCTE to PhysicalCTE.
+type ImplCTE struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (*ImplCTE) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return len(expr.Children) == 0 && prop.IsSortItemEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+// The seed part and the recursive part of the CTE have been optimized when
+// the stats of the group are derived.
+func (*ImplCTE) OnImplement(expr *memo.GroupExpr, _ *property.PhysicalProperty) ([]memo.Implementation, error) {
+	cte := expr.ExprNode.(*plannercore.LogicalCTE)
+	physicalCTE := cte.GetPhysicalCTE(expr.Group.Prop.Schema, expr.Group.Prop.Stats)
+	return []memo.Implementation{impl.NewCTEImpl(physicalCTE)}, nil
+}
@@ -63,6 +63,22 @@ func (opt *Optimizer) GetImplementationRules(node base.LogicalPlan) []Implementa
 	return opt.implementationRuleMap[pattern.GetOperand(node)]
 }
 
+// IsSupported checks whether all the operators of the logical plan can be optimized
+// by the cascades planner. DataSource is always supported since it is converted to
+// the access paths in the exploration phase.
+func (opt *Optimizer) IsSupported(p base.LogicalPlan) bool {
+	operand := pattern.GetOperand(p)
+	if _, ok := opt.implementationRuleMap[operand]; !ok && operand != pattern.OperandDataSource {
+		return false
+	}
+	for _, child := range p.Children() {
+		if !opt.IsSupported(child) {
+			return false
+		}
+	}
+	return true
+}
+
 // FindBestPlan is the optimization entrance of the cascades planner. The
 // optimization is composed of 3 phases: preprocessing, exploration and implementation.
 //
@@ -16,6 +16,8 @@ package cascades
 
 import (
 	"math"
+	"slices"
+	"strconv"
 
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/expression/aggregation"
@@ -109,13 +111,14 @@ var TiDBLayerOptimizationBatch = TransformationRuleBatch{
 	},
 	pattern.OperandJoin: {
 		NewRuleTransformJoinCondToSel(),
+		NewRuleJoinReorder(),
 	},
 	pattern.OperandWindow: {
 		NewRuleMergeAdjacentWindow(),
 	},
 }
 
-// TiKVLayerOptimizationBatch does the optimization related to TiKV layer.
+// TiKVLayerOptimizationBatch does the optimization related to TiKV and TiFlash layer.
 // For example, rules about pushing down Operators like Selection, Limit,
 // Aggregation into TiKV layer should be inside this batch.
 var TiKVLayerOptimizationBatch = TransformationRuleBatch{
@@ -123,6 +126,10 @@ var TiKVLayerOptimizationBatch = TransformationRuleBatch{
 		NewRuleEnumeratePaths(),
 	},
 	pattern.OperandSelection: {
+		// The rules building new gathers from the Selection must be applied
+		// before PushSelDownTiKVSingleGather, which erases the Selection.
+		NewRuleTransformSelectionToIndexMerge(),
+		NewRulePushSelDownMPPGather(),
 		NewRulePushSelDownTiKVSingleGather(),
 		NewRulePushSelDownTableScan(),
 		NewRulePushSelDownIndexScan(),
@@ -137,6 +144,9 @@ var TiKVLayerOptimizationBatch = TransformationRuleBatch{
 	pattern.OperandTopN: {
 		NewRulePushTopNDownTiKVSingleGather(),
 	},
+	pattern.OperandJoin: {
+		NewRulePushJoinDownMPPGather(),
+	},
 }
 
 // PostTransformationBatch does the transformation which is related to
@@ -216,7 +226,7 @@ func (*PushSelDownTableScan) OnTransform(old *memo.ExprIter) (newExprs []*memo.G
 		return []*memo.GroupExpr{tblScanExpr}, true, false, nil
 	}
 	schema := old.GetExpr().Group.Prop.Schema
-	tblScanGroup := memo.NewGroupWithSchema(tblScanExpr, schema)
+	tblScanGroup := memo.NewGroupWithSchema(tblScanExpr, schema).SetEngineType(old.GetExpr().Group.EngineType)
 	newSel := plannercore.LogicalSelection{Conditions: remained}.Init(sel.SCtx(), sel.QueryBlockOffset())
 	selExpr := memo.NewGroupExpr(newSel)
 	selExpr.Children = append(selExpr.Children, tblScanGroup)
@@ -299,7 +309,7 @@ func (*PushSelDownIndexScan) OnTransform(old *memo.ExprIter) (newExprs []*memo.G
 	if len(res.RemainedConds) == 0 {
 		return []*memo.GroupExpr{isExpr}, true, false, nil
 	}
-	isGroup := memo.NewGroupWithSchema(isExpr, old.Children[0].GetExpr().Group.Prop.Schema)
+	isGroup := memo.NewGroupWithSchema(isExpr, old.Children[0].GetExpr().Group.Prop.Schema).SetEngineType(old.GetExpr().Group.EngineType)
 	newSel := plannercore.LogicalSelection{Conditions: res.RemainedConds}.Init(sel.SCtx(), sel.QueryBlockOffset())
 	selExpr := memo.NewGroupExpr(newSel)
 	selExpr.SetChildren(isGroup)
@@ -342,12 +352,10 @@ func (*PushSelDownTiKVSingleGather) OnTransform(old *memo.ExprIter) (newExprs []
 	pushedSelExpr := memo.NewGroupExpr(pushedSel)
 	pushedSelExpr.Children = append(pushedSelExpr.Children, childGroup)
 	pushedSelGroup := memo.NewGroupWithSchema(pushedSelExpr, childGroup.Prop.Schema).SetEngineType(childGroup.EngineType)
-	// The field content of TiKVSingleGather would not be modified currently, so we
-	// just reference the same tg instead of making a copy of it.
-	//
-	// TODO: if we save pushed filters later in TiKVSingleGather, in order to do partition
-	//       pruning or skyline pruning, we need to make a copy of the TiKVSingleGather here.
-	tblGatherExpr := memo.NewGroupExpr(sg)
+	// The pushed filters are saved in a copy of the TiKVSingleGather to do partition pruning.
+	newSg := sg.Shallow()
+	newSg.PruningConds = append(slices.Clip(sg.PruningConds), pushed...)
+	tblGatherExpr := memo.NewGroupExpr(newSg)
 	tblGatherExpr.Children = append(tblGatherExpr.Children, pushedSelGroup)
 	if len(remained) == 0 {
 		// `oldSel -> oldTg -> any` is transformed to `newTg -> pushedSel -> any`.
@@ -383,9 +391,175 @@ func (*EnumeratePaths) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupEx
 		expr.Children[0].SetEngineType(pattern.EngineTiKV)
 		newExprs = append(newExprs, expr)
 	}
+	if gather := ds.Convert2MPPGather(); gather != nil {
+		expr := memo.Convert2GroupExpr(gather)
+		expr.Children[0].SetEngineType(pattern.EngineTiFlash)
+		newExprs = append(newExprs, expr)
+	}
 	return newExprs, true, false, nil
 }
 
+// TransformSelectionToIndexMerge builds the index merge paths from the filters
+// of the Selection on top of a table gather.
+type TransformSelectionToIndexMerge struct {
+	baseRule
+}
+
+// NewRuleTransformSelectionToIndexMerge creates a new Transformation TransformSelectionToIndexMerge.
+// The pattern of this rule is `Selection -> TiKVSingleGather -> TableScan`.
+func NewRuleTransformSelectionToIndexMerge() Transformation {
+	rule := &TransformSelectionToIndexMerge{}
+	rule.pattern = pattern.BuildPattern(
+		pattern.OperandSelection,
+		pattern.EngineTiDBOnly,
+		pattern.BuildPattern(
+			pattern.OperandTiKVSingleGather,
+			pattern.EngineTiDBOnly,
+			pattern.NewPattern(pattern.OperandTableScan, pattern.EngineTiKVOnly),
+		),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (*TransformSelectionToIndexMerge) Match(expr *memo.ExprIter) bool {
+	sg := expr.Children[0].GetExpr().ExprNode.(*plannercore.TiKVSingleGather)
+	ts := expr.Children[0].Children[0].GetExpr().ExprNode.(*plannercore.LogicalTableScan)
+	// Only the gather of the full table scan is used, so the index merge paths
+	// are built once from all of the filters.
+	return len(sg.PruningConds) == 0 && len(ts.AccessConds) == 0
+}
+
+// OnTransform implements Transformation interface.
+// It transforms `sel -> tg -> ts` to one of the following new exprs:
+// 1. `indexMergeGather`
+// 2. `remainedSel -> indexMergeGather`
+// Filters which can't be pushed down to TiKV are kept in the remained Selection.
+// If the index merge paths are required by hints, the other exprs are erased.
+func (*TransformSelectionToIndexMerge) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
+	ts := old.Children[0].Children[0].GetExpr().ExprNode.(*plannercore.LogicalTableScan)
+	sctx := sel.SCtx()
+	pushed, remained := expression.PushDownExprs(plannercore.GetPushDownCtx(sctx), sel.Conditions, kv.TiKV)
+	if len(pushed) == 0 {
+		return nil, false, false, nil
+	}
+	gathers, forced, err := ts.Source.Convert2IndexMergeGathers(pushed)
+	if err != nil || len(gathers) == 0 {
+		return nil, false, false, err
+	}
+	for _, gather := range gathers {
+		newExprs = append(newExprs, memo.NewGroupExpr(gather))
+	}
+	if len(remained) > 0 {
+		gatherGroup := memo.NewGroupWithSchema(newExprs[0], old.GetExpr().Group.Prop.Schema)
+		for _, expr := range newExprs[1:] {
+			gatherGroup.Insert(expr)
+		}
+		remainedSel := plannercore.LogicalSelection{Conditions: remained}.Init(sctx, sel.QueryBlockOffset())
+		remainedSelExpr := memo.NewGroupExpr(remainedSel)
+		remainedSelExpr.SetChildren(gatherGroup)
+		newExprs = []*memo.GroupExpr{remainedSelExpr}
+	}
+	return newExprs, false, forced, nil
+}
+
+// PushSelDownMPPGather pushes the selection down to child of MPPGather.
+type PushSelDownMPPGather struct {
+	baseRule
+}
+
+// NewRulePushSelDownMPPGather creates a new Transformation PushSelDownMPPGather.
+// The pattern of this rule is `Selection -> MPPGather -> Any`.
+func NewRulePushSelDownMPPGather() Transformation {
+	rule := &PushSelDownMPPGather{}
+	rule.pattern = pattern.BuildPattern(
+		pattern.OperandSelection,
+		pattern.EngineTiDBOnly,
+		pattern.BuildPattern(
+			pattern.OperandMPPGather,
+			pattern.EngineTiDBOnly,
+			pattern.NewPattern(pattern.OperandAny, pattern.EngineTiFlashOnly),
+		),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `oldSel -> oldGather -> any` to one of the following new exprs:
+// 1. `newGather -> pushedSel -> any`
+// 2. `remainedSel -> newGather -> pushedSel -> any`
+//
+// The old Selection is kept since it is also used by the other gathers.
+func (*PushSelDownMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
+	gather := old.Children[0].GetExpr().ExprNode.(*plannercore.MPPGather)
+	childGroup := old.Children[0].Children[0].Group
+	sctx := gather.SCtx()
+	pushed, remained := expression.PushDownExprs(plannercore.GetPushDownCtx(sctx), sel.Conditions, kv.TiFlash)
+	if len(pushed) == 0 {
+		return nil, false, false, nil
+	}
+	pushedSel := plannercore.LogicalSelection{Conditions: pushed}.Init(sctx, sel.QueryBlockOffset())
+	pushedSelExpr := memo.NewGroupExpr(pushedSel)
+	pushedSelExpr.SetChildren(childGroup)
+	pushedSelGroup := memo.NewGroupWithSchema(pushedSelExpr, childGroup.Prop.Schema).SetEngineType(childGroup.EngineType)
+	// The pushed filters are saved in a copy of the MPPGather to do partition pruning.
+	newGather := gather.Shallow()
+	newGather.PruningConds = append(slices.Clip(gather.PruningConds), pushed...)
+	gatherExpr := memo.NewGroupExpr(newGather)
+	gatherExpr.SetChildren(pushedSelGroup)
+	if len(remained) == 0 {
+		return []*memo.GroupExpr{gatherExpr}, false, false, nil
+	}
+	gatherGroup := memo.NewGroupWithSchema(gatherExpr, pushedSelGroup.Prop.Schema)
+	remainedSel := plannercore.LogicalSelection{Conditions: remained}.Init(sctx, sel.QueryBlockOffset())
+	remainedSelExpr := memo.NewGroupExpr(remainedSel)
+	remainedSelExpr.SetChildren(gatherGroup)
+	return []*memo.GroupExpr{remainedSelExpr}, false, false, nil
+}
+
+// PushJoinDownMPPGather pushes the join down to the MPP tasks of TiFlash if
+// both of its children are read by MPPGathers.
+type PushJoinDownMPPGather struct {
+	baseRule
+}
+
+// NewRulePushJoinDownMPPGather creates a new Transformation PushJoinDownMPPGather.
+// The pattern of this rule is `Join -> (MPPGather, MPPGather)`.
+func NewRulePushJoinDownMPPGather() Transformation {
+	rule := &PushJoinDownMPPGather{}
+	rule.pattern = pattern.BuildPattern(
+		pattern.OperandJoin,
+		pattern.EngineTiDBOnly,
+		pattern.NewPattern(pattern.OperandMPPGather, pattern.EngineTiDBOnly),
+		pattern.NewPattern(pattern.OperandMPPGather, pattern.EngineTiDBOnly),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+// It transforms `join -> (gather1 -> any1, gather2 -> any2)` to
+// `newGather -> newJoin -> (any1, any2)`. The old join is kept to be
+// executed in TiDB layer.
+func (*PushJoinDownMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	join := old.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	leftGather := old.Children[0].GetExpr().ExprNode.(*plannercore.MPPGather)
+	rightGather := old.Children[1].GetExpr().ExprNode.(*plannercore.MPPGather)
+	schema := old.GetExpr().Group.Prop.Schema
+	newJoinExpr := memo.NewGroupExpr(join.Shallow())
+	newJoinExpr.SetChildren(old.Children[0].GetExpr().Children[0], old.Children[1].GetExpr().Children[0])
+	newJoinGroup := memo.NewGroupWithSchema(newJoinExpr, schema).SetEngineType(pattern.EngineTiFlash)
+	newGather := plannercore.MPPGather{
+		PruningConds: append(slices.Clip(leftGather.PruningConds), rightGather.PruningConds...),
+	}.Init(join.SCtx(), join.QueryBlockOffset())
+	newGather.SetSchema(schema)
+	gatherExpr := memo.NewGroupExpr(newGather)
+	gatherExpr.SetChildren(newJoinGroup)
+	return []*memo.GroupExpr{gatherExpr}, false, false, nil
+}
+
 // PushAggDownGather splits Aggregation to two stages, final and partial1,
 // and pushed the partial Aggregation down to the child of TiKVSingleGather.
 type PushAggDownGather struct {
@@ -606,6 +780,7 @@ func (*PushSelDownAggregation) OnTransform(old *memo.ExprIter) (newExprs []*memo
 	aggSchema := old.Children[0].Prop.Schema
 	var pushedExprs []expression.Expression
 	var remainedExprs []expression.Expression
+	pushedConstCnt := 0
 	groupByColumns := expression.NewSchema(agg.GetGroupByCols()...)
 	for _, cond := range sel.Conditions {
 		switch cond.(type) {
@@ -615,6 +790,7 @@ func (*PushSelDownAggregation) OnTransform(old *memo.ExprIter) (newExprs []*memo
 			// with value 0 rather than an empty query result.
 			pushedExprs = append(pushedExprs, cond)
 			remainedExprs = append(remainedExprs, cond)
+			pushedConstCnt++
 		case *expression.ScalarFunction:
 			extractedCols := expression.ExtractColumns(cond)
 			canPush := true
@@ -633,8 +809,10 @@ func (*PushSelDownAggregation) OnTransform(old *memo.ExprIter) (newExprs []*memo
 			remainedExprs = append(remainedExprs, cond)
 		}
 	}
-	// If no condition can be pushed, keep the selection unchanged.
-	if len(pushedExprs) == 0 {
+	// If no condition can be pushed, keep the selection unchanged. The constant conditions
+	// are retained in the remained selection, pushing only them would make this rule be
+	// applied on the remained selection again and again.
+	if len(pushedExprs) == pushedConstCnt {
 		return nil, false, false, nil
 	}
 	sctx := sel.SCtx()
@@ -1063,6 +1241,290 @@ func (r *TransformJoinCondToSel) OnTransform(old *memo.ExprIter) (newExprs []*me
 	return []*memo.GroupExpr{newJoinExpr}, true, false, nil
 }
 
+// JoinReorder explores the other join orders of the adjacent inner joins.
+type JoinReorder struct {
+	baseRule
+}
+
+// NewRuleJoinReorder creates a new Transformation JoinReorder.
+// The pattern of this rule is: `Join`.
+func NewRuleJoinReorder() Transformation {
+	rule := &JoinReorder{}
+	rule.pattern = pattern.NewPattern(pattern.OperandJoin, pattern.EngineTiDBOnly)
+	return rule
+}
+
+// maxJoinReorderExhaustiveLeaves is the max number of the join leaves whose left-deep
+// join orders are enumerated exhaustively. For more leaves, only the join orders
+// starting from each leaf are explored.
+const maxJoinReorderExhaustiveLeaves = 5
+
+// Match implements Transformation interface.
+func (*JoinReorder) Match(expr *memo.ExprIter) bool {
+	return isReorderableJoin(expr.GetExpr())
+}
+
+// isReorderableJoin checks whether the GroupExpr is a join which can be reordered
+// together with its adjacent joins. The joins whose Left/RightConditions are not
+// pushed down yet are skipped, TransformJoinCondToSel will generate the one we want.
+func isReorderableJoin(expr *memo.GroupExpr) bool {
+	join, ok := expr.ExprNode.(*plannercore.LogicalJoin)
+	return ok && join.CanBeReordered() && len(join.LeftConditions) == 0 && len(join.RightConditions) == 0
+}
+
+// OnTransform implements Transformation interface.
+// This rule flattens the adjacent inner joins into the join leaves and the join conditions,
+// then builds the left-deep joins of the leaves in the other orders. Since the column order
+// of the reordered join is different, a Projection is added on the top of it.
+// The joins built by this rule are marked as reordered, so they will not be reordered again.
+func (*JoinReorder) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	join := old.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	var leaves []*memo.Group
+	var conds []expression.Expression
+	collectJoinLeaves(old.GetExpr(), &leaves, &conds)
+	if len(leaves) < 3 {
+		// Two leaves can only be commuted, which is covered by the implementation rules.
+		return nil, false, false, nil
+	}
+	schema := old.GetExpr().Group.Prop.Schema
+	for _, col := range schema.Columns {
+		found := false
+		for _, leaf := range leaves {
+			if leaf.Prop.Schema.Contains(col) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false, false, nil
+		}
+	}
+
+	sctx := join.SCtx()
+	builtGroups := make(map[string]*memo.Group)
+	for _, order := range enumerateJoinOrders(leaves, conds) {
+		joinGroup := buildLeftDeepJoinGroup(join, leaves, order, conds, builtGroups)
+		if joinGroup.Prop.Schema.Len() == schema.Len() {
+			sameOrder := true
+			for i, col := range joinGroup.Prop.Schema.Columns {
+				if !col.EqualColumn(schema.Columns[i]) {
+					sameOrder = false
+					break
+				}
+			}
+			if sameOrder {
+				continue
+			}
+		}
+		proj := plannercore.LogicalProjection{
+			Exprs: expression.Column2Exprs(schema.Columns),
+		}.Init(sctx, join.QueryBlockOffset())
+		proj.SetSchema(schema.Clone())
+		projExpr := memo.NewGroupExpr(proj)
+		projExpr.SetChildren(joinGroup)
+		newExprs = append(newExprs, projExpr)
+	}
+	return newExprs, false, false, nil
+}
+
+// collectJoinLeaves flattens the adjacent reorderable joins and collects their children
+// Groups which are not reorderable joins as the join leaves.
+func collectJoinLeaves(expr *memo.GroupExpr, leaves *[]*memo.Group, conds *[]expression.Expression) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	for _, cond := range join.EqualConditions {
+		*conds = append(*conds, cond)
+	}
+	*conds = append(*conds, join.OtherConditions...)
+	for _, child := range expr.Children {
+		var childJoin *memo.GroupExpr
+		for elem := child.Equivalents.Front(); elem != nil; elem = elem.Next() {
+			if e := elem.Value.(*memo.GroupExpr); isReorderableJoin(e) {
+				childJoin = e
+				break
+			}
+		}
+		if childJoin == nil {
+			*leaves = append(*leaves, child)
+			continue
+		}
+		collectJoinLeaves(childJoin, leaves, conds)
+	}
+}
+
+// getJoinLeafEdges returns the leaves connected with each leaf by the equal conditions.
+func getJoinLeafEdges(leaves []*memo.Group, conds []expression.Expression) [][]bool {
+	edges := make([][]bool, len(leaves))
+	for i := range edges {
+		edges[i] = make([]bool, len(leaves))
+	}
+	leafIdx := func(col *expression.Column) int {
+		for i, leaf := range leaves {
+			if leaf.Prop.Schema.Contains(col) {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, cond := range conds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.EQ {
+			continue
+		}
+		lCol, lOk := sf.GetArgs()[0].(*expression.Column)
+		rCol, rOk := sf.GetArgs()[1].(*expression.Column)
+		if !lOk || !rOk {
+			continue
+		}
+		l, r := leafIdx(lCol), leafIdx(rCol)
+		if l >= 0 && r >= 0 && l != r {
+			edges[l][r], edges[r][l] = true, true
+		}
+	}
+	return edges
+}
+
+// enumerateJoinOrders enumerates the left-deep join orders of the leaves except the
+// original one. A leaf is joined without the equal conditions only if none of the
+// remaining leaves is connected with the joined ones. The orders which only differ
+// in the first two leaves are regarded as the same, because the two sides of a hash
+// join are both considered by the implementation rules.
+func enumerateJoinOrders(leaves []*memo.Group, conds []expression.Expression) (orders [][]int) {
+	edges := getJoinLeafEdges(leaves, conds)
+	n := len(leaves)
+	joined := make([]bool, n)
+	candidates := func() []int {
+		var connected, all []int
+		for i := 0; i < n; i++ {
+			if joined[i] {
+				continue
+			}
+			all = append(all, i)
+			for j := 0; j < n; j++ {
+				if joined[j] && edges[i][j] {
+					connected = append(connected, i)
+					break
+				}
+			}
+		}
+		if len(connected) > 0 {
+			return connected
+		}
+		return all
+	}
+	isOriginal := func(order []int) bool {
+		for i, idx := range order {
+			if i != idx {
+				return false
+			}
+		}
+		return true
+	}
+	order := make([]int, 0, n)
+	var enumerate func()
+	enumerate = func() {
+		if len(order) == n {
+			if !isOriginal(order) {
+				orders = append(orders, slices.Clone(order))
+			}
+			return
+		}
+		for _, idx := range candidates() {
+			if len(order) == 1 && idx < order[0] {
+				continue
+			}
+			joined[idx] = true
+			order = append(order, idx)
+			enumerate()
+			order = order[:len(order)-1]
+			joined[idx] = false
+		}
+	}
+	if n <= maxJoinReorderExhaustiveLeaves {
+		for i := 0; i < n; i++ {
+			joined[i] = true
+			order = append(order, i)
+			enumerate()
+			order = order[:0]
+			joined[i] = false
+		}
+		return orders
+	}
+	for i := 0; i < n; i++ {
+		clear(joined)
+		joined[i] = true
+		order = append(order[:0], i)
+		for len(order) < n {
+			idx := candidates()[0]
+			joined[idx] = true
+			order = append(order, idx)
+		}
+		if !isOriginal(order) {
+			orders = append(orders, slices.Clone(order))
+		}
+	}
+	return orders
+}
+
+// buildLeftDeepJoinGroup builds the Group of the left-deep join of the leaves in the given
+// order. Each join condition is attached to the lowest join which can evaluate it. The
+// Groups of the same join prefix are shared by the different orders.
+func buildLeftDeepJoinGroup(
+	join *plannercore.LogicalJoin,
+	leaves []*memo.Group,
+	order []int,
+	conds []expression.Expression,
+	builtGroups map[string]*memo.Group) *memo.Group {
+	sctx := join.SCtx()
+	used := make([]bool, len(conds))
+	cur := leaves[order[0]]
+	key := strconv.Itoa(order[0])
+	for _, idx := range order[1:] {
+		right := leaves[idx]
+		key += "," + strconv.Itoa(idx)
+		schema := expression.MergeSchema(cur.Prop.Schema, right.Prop.Schema)
+		var eqConds []*expression.ScalarFunction
+		var otherConds []expression.Expression
+		for i, cond := range conds {
+			if used[i] || !expression.ExprFromSchema(cond, schema) {
+				continue
+			}
+			used[i] = true
+			if sf, ok := cond.(*expression.ScalarFunction); ok && sf.FuncName.L == ast.EQ {
+				lCol, lOk := sf.GetArgs()[0].(*expression.Column)
+				rCol, rOk := sf.GetArgs()[1].(*expression.Column)
+				if lOk && rOk {
+					if cur.Prop.Schema.Contains(lCol) && right.Prop.Schema.Contains(rCol) {
+						eqConds = append(eqConds, sf)
+						continue
+					}
+					if cur.Prop.Schema.Contains(rCol) && right.Prop.Schema.Contains(lCol) {
+						newSf := expression.NewFunctionInternal(sctx.GetExprCtx(), ast.EQ, sf.GetType(), rCol, lCol).(*expression.ScalarFunction)
+						eqConds = append(eqConds, newSf)
+						continue
+					}
+				}
+			}
+			otherConds = append(otherConds, cond)
+		}
+		if group, ok := builtGroups[key]; ok {
+			cur = group
+			continue
+		}
+		newJoin := plannercore.LogicalJoin{
+			JoinType:        plannercore.InnerJoin,
+			EqualConditions: eqConds,
+			OtherConditions: otherConds,
+		}.Init(sctx, join.QueryBlockOffset())
+		newJoin.SetSchema(schema)
+		newJoin.SetReordered()
+		newJoinExpr := memo.NewGroupExpr(newJoin)
+		newJoinExpr.SetChildren(cur, right)
+		cur = memo.NewGroupWithSchema(newJoinExpr, schema)
+		builtGroups[key] = cur
+	}
+	return cur
+}
+
 // PushSelDownUnionAll pushes selection through union all.
 type PushSelDownUnionAll struct {
 	baseRule
@@ -1680,7 +2142,16 @@ func (*MergeAdjacentSelection) OnTransform(old *memo.ExprIter) (newExprs []*memo
 
 	conditions := make([]expression.Expression, 0, len(sel.Conditions)+len(child.Conditions))
 	conditions = append(conditions, sel.Conditions...)
-	conditions = append(conditions, child.Conditions...)
+	// The derived not-null conditions of a join are pushed down again each time the join
+	// is explored, so skip the conditions which are already kept by the parent selection.
+	evalCtx := sel.SCtx().GetExprCtx().GetEvalCtx()
+	for _, cond := range child.Conditions {
+		if !slices.ContainsFunc(sel.Conditions, func(expr expression.Expression) bool {
+			return expr.Equal(evalCtx, cond)
+		}) {
+			conditions = append(conditions, cond)
+		}
+	}
 	newSel := plannercore.LogicalSelection{Conditions: conditions}.Init(sel.SCtx(), sel.QueryBlockOffset())
 	newSelExpr := memo.NewGroupExpr(newSel)
 	newSelExpr.SetChildren(childGroups...)
@@ -2092,11 +2563,9 @@ func (r *TransformAggregateCaseToSelection) transform(agg *plannercore.LogicalAg
 
 	// `case when a>0 then null else a end` should be converted to `case when !(a>0) then a else null end`.
 	var nullFlip = caseArgsNum == 3 && caseArgs[1].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewNull()) && !caseArgs[2].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewNull())
-	// `case when a>0 then 0 else a end` should be converted to `case when !(a>0) then a else 0 end`.
-	var zeroFlip = !nullFlip && caseArgsNum == 3 && caseArgs[1].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewZero())
 
 	var outputIdx int
-	if nullFlip || zeroFlip {
+	if nullFlip {
 		outputIdx = 2
 		newConditions = []expression.Expression{expression.NewFunctionInternal(ctx.GetExprCtx(), ast.UnaryNot, types.NewFieldType(mysql.TypeTiny), conditionFromCase)}
 	} else {
@@ -2118,16 +2587,17 @@ func (r *TransformAggregateCaseToSelection) transform(agg *plannercore.LogicalAg
 		return false, nil, nil
 	}
 
-	// Two styles supported:
+	// Only one style supported:
 	//
 	// A1: AGG(CASE WHEN x = 'foo' THEN cnt END)
 	//   => newAggFuncDesc: AGG(cnt), newCondition: x = 'foo'
-	// A2: SUM(CASE WHEN x = 'foo' THEN cnt ELSE 0 END)
-	//   => newAggFuncDesc: SUM(cnt), newCondition: x = 'foo'
+	//
+	// `SUM(CASE WHEN x = 'foo' THEN cnt ELSE 0 END)` can not be converted to `SUM(cnt)`
+	// with the condition `x = 'foo'`, because the former returns 0 rather than NULL
+	// when no row satisfies the condition.
 
 	switch {
-	case r.allowsSelection(aggFuncName) && (caseArgsNum == 2 || caseArgs[3-outputIdx].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewNull())), // Case A1
-		aggFuncName == ast.AggFuncSum && caseArgsNum == 3 && caseArgs[3-outputIdx].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewZero()): // Case A2
+	case r.allowsSelection(aggFuncName) && (caseArgsNum == 2 || caseArgs[3-outputIdx].Equal(ctx.GetExprCtx().GetEvalCtx(), expression.NewNull())): // Case A1
 		newAggFuncDesc := aggFuncDesc.Clone()
 		newAggFuncDesc.Args = []expression.Expression{caseArgs[outputIdx]}
 		return true, newConditions, []*aggregation.AggFuncDesc{newAggFuncDesc}
@@ -2349,6 +2819,10 @@ func (*InjectProjectionBelowAgg) OnTransform(old *memo.ExprIter) (newExprs []*me
 			_, isScalarFunc := arg.(*expression.ScalarFunction)
 			hasScalarFunc = hasScalarFunc || isScalarFunc
 		}
+		for _, byItem := range copyFunc.OrderByItems {
+			_, isScalarFunc := byItem.Expr.(*expression.ScalarFunction)
+			hasScalarFunc = hasScalarFunc || isScalarFunc
+		}
 	}
 
 	for i := 0; !hasScalarFunc && i < len(agg.GroupByItems); i++ {
@@ -2380,6 +2854,23 @@ func (*InjectProjectionBelowAgg) OnTransform(old *memo.ExprIter) (newExprs []*me
 				f.Args[i] = newArg
 			}
 		}
+		for _, byItem := range f.OrderByItems {
+			switch expr := byItem.Expr.(type) {
+			case *expression.Constant:
+				continue
+			case *expression.Column:
+				projExprs = append(projExprs, expr)
+				projSchemaCols = append(projSchemaCols, expr)
+			default:
+				projExprs = append(projExprs, expr)
+				newArg := &expression.Column{
+					UniqueID: agg.SCtx().GetSessionVars().AllocPlanColumnID(),
+					RetType:  expr.GetType(),
+				}
+				projSchemaCols = append(projSchemaCols, newArg)
+				byItem.Expr = newArg
+			}
+		}
 	}
 
 	newGroupByItems := make([]expression.Expression, len(agg.GroupByItems))
@@ -2582,8 +3073,10 @@ func (*MergeAdjacentWindow) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 	ctx := old.GetExpr().ExprNode.SCtx()
 
 	newWindowFuncs := make([]*aggregation.WindowFuncDesc, 0, len(curWinPlan.WindowFuncDescs)+len(nextWinPlan.WindowFuncDescs))
-	newWindowFuncs = append(newWindowFuncs, curWinPlan.WindowFuncDescs...)
+	// The output columns of the next window precede the ones of the current window
+	// in the schema of the group, so the window functions must be kept in that order.
 	newWindowFuncs = append(newWindowFuncs, nextWinPlan.WindowFuncDescs...)
+	newWindowFuncs = append(newWindowFuncs, curWinPlan.WindowFuncDescs...)
 	newWindowPlan := plannercore.LogicalWindow{
 		WindowFuncDescs: newWindowFuncs,
 		PartitionBy:     curWinPlan.PartitionBy,
@@ -499,3 +499,25 @@ func TestMergeAdjacentWindow(t *testing.T) {
 	transformationRulesSuiteData.LoadTestCases(t, &input, &output)
 	testGroupToString(t, input, output, optimizer)
 }
+
+func TestJoinReorder(t *testing.T) {
+	optimizer := NewOptimizer()
+	optimizer.ResetTransformationRules(map[pattern.Operand][]Transformation{
+		pattern.OperandSelection: {
+			NewRulePushSelDownJoin(),
+		},
+		pattern.OperandJoin: {
+			NewRuleJoinReorder(),
+		},
+	})
+	defer func() {
+		optimizer.ResetTransformationRules(DefaultRuleBatches...)
+	}()
+	var input []string
+	var output []struct {
+		SQL    string
+		Result []string
+	}
+	transformationRulesSuiteData.LoadTestCases(t, &input, &output)
+	testGroupToString(t, input, output, optimizer)
+}
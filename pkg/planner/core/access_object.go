@@ -285,6 +285,11 @@ func (p *PhysicalTableScan) AccessObject() base.AccessObject {
 	return res
 }
 
+// AccessObject implements dataAccesser interface.
+func (p *PhysicalLooseIndexScan) AccessObject() base.AccessObject {
+	return p.IndexScan.AccessObject()
+}
+
 // AccessObject implements dataAccesser interface.
 func (p *PhysicalMemTable) AccessObject() base.AccessObject {
 	return &ScanAccessObject{
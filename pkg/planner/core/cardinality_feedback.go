@@ -0,0 +1,273 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/pkg/planner/cardinality"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
+	"github.com/pingcap/tidb/pkg/util/execdetails"
+)
+
+// CollectCardinalityFeedback compares the estimated row counts of the filters on a single table and of the inner
+// joins in the executed plan with the actual ones, and records them to the cardinality feedback collector.
+// Only the operators whose inputs are read completely are compared, e.g. the ones under a Limit are skipped, since
+// their actual row counts don't reflect the selectivity.
+func CollectCardinalityFeedback(sctx base.PlanContext, p base.Plan) {
+	sc := sctx.GetSessionVars().StmtCtx
+	if sc.RuntimeStatsColl == nil {
+		return
+	}
+	c := &feedbackCollector{runtimeStatsColl: sc.RuntimeStatsColl}
+	if _, digest := sc.SQLDigest(); digest != nil {
+		c.sqlDigest = digest.String()
+	}
+	c.collectPlan(p)
+}
+
+type feedbackCollector struct {
+	runtimeStatsColl *execdetails.RuntimeStatsColl
+	sqlDigest        string
+}
+
+func (c *feedbackCollector) collectPlan(p base.Plan) {
+	switch x := p.(type) {
+	case *Insert:
+		if x.SelectPlan != nil {
+			c.collect(x.SelectPlan, true)
+		}
+	case *Update:
+		if x.SelectPlan != nil {
+			c.collect(x.SelectPlan, true)
+		}
+	case *Delete:
+		if x.SelectPlan != nil {
+			c.collect(x.SelectPlan, true)
+		}
+	case *Explain:
+		if x.Analyze && x.TargetPlan != nil {
+			c.collectPlan(x.TargetPlan)
+		}
+	case base.PhysicalPlan:
+		c.collect(x, true)
+	}
+}
+
+// collect collects the feedback in the plan tree, complete indicates whether the output of p is read completely.
+func (c *feedbackCollector) collect(p base.PhysicalPlan, complete bool) {
+	if complete {
+		switch x := p.(type) {
+		case *PhysicalSelection:
+			c.collectFilter(x)
+		case *PhysicalHashJoin:
+			c.collectJoin(x)
+		}
+	}
+
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		c.collect(x.tablePlan, complete)
+	case *PhysicalIndexReader:
+		c.collect(x.indexPlan, complete)
+	case *PhysicalIndexLookUpReader:
+		complete = complete && x.PushedLimit == nil
+		c.collect(x.indexPlan, complete)
+		c.collect(x.tablePlan, complete)
+	case *PhysicalIndexMergeReader:
+		complete = complete && x.PushedLimit == nil
+		for _, partialPlan := range x.partialPlans {
+			c.collect(partialPlan, complete)
+		}
+		c.collect(x.tablePlan, complete)
+	case *PhysicalLimit, *PhysicalMaxOneRow, *PhysicalMergeJoin:
+		// They may stop reading the children before the children are exhausted.
+		for _, child := range x.Children() {
+			c.collect(child, false)
+		}
+	case *PhysicalApply:
+		c.collectJoinChildren(x, x.InnerChildIdx, false)
+	case *PhysicalIndexJoin:
+		c.collectJoinChildren(x, x.InnerChildIdx, false)
+	case *PhysicalIndexHashJoin:
+		c.collectJoinChildren(x, x.InnerChildIdx, false)
+	case *PhysicalIndexMergeJoin:
+		c.collectJoinChildren(x, x.InnerChildIdx, false)
+	case *PhysicalHashJoin:
+		buildIdx := x.InnerChildIdx
+		if x.UseOuterToBuild {
+			buildIdx = 1 - buildIdx
+		}
+		// The probe side isn't read completely when the build side is empty.
+		buildRows, ok := c.actRows(x.Children()[buildIdx])
+		c.collect(x.Children()[buildIdx], complete)
+		c.collect(x.Children()[1-buildIdx], complete && ok && buildRows > 0)
+	default:
+		if p == nil {
+			return
+		}
+		for _, child := range p.Children() {
+			c.collect(child, complete)
+		}
+	}
+}
+
+// collectJoinChildren collects the feedback in the children of the join, the inner side is executed once for each
+// outer row, so its actual row counts are not comparable with the estimated ones.
+func (c *feedbackCollector) collectJoinChildren(p base.PhysicalPlan, innerIdx int, complete bool) {
+	c.collect(p.Children()[1-innerIdx], complete)
+	c.collect(p.Children()[innerIdx], false)
+}
+
+func (c *feedbackCollector) collectFilter(sel *PhysicalSelection) {
+	child := sel.Children()[0]
+	tableID, ok := feedbackTableID(child)
+	if !ok {
+		return
+	}
+	actIn, ok := c.actRows(child)
+	if !ok || actIn == 0 || child.StatsInfo().RowCount <= 0 {
+		return
+	}
+	actOut, ok := c.actRows(sel)
+	if !ok {
+		return
+	}
+	exprs := cardinality.FeedbackExprs(sel.Conditions)
+	key := feedback.NewKey(tableID, feedback.KindFilter, exprs)
+	// The estimation may have been corrected by the feedback, so we restore the one by the statistics.
+	estSel := min(sel.StatsInfo().RowCount/child.StatsInfo().RowCount, 1) / feedback.GlobalCollector.GetCorrection(key)
+	feedback.GlobalCollector.Record(key, exprs, c.sqlDigest, estSel*float64(actIn), float64(actOut))
+}
+
+func (c *feedbackCollector) collectJoin(join *PhysicalHashJoin) {
+	if join.JoinType != InnerJoin || len(join.EqualConditions)+len(join.OtherConditions) == 0 {
+		return
+	}
+	left, right := join.Children()[0], join.Children()[1]
+	actLeft, okLeft := c.actRows(left)
+	actRight, okRight := c.actRows(right)
+	actOut, ok := c.actRows(join)
+	if !okLeft || !okRight || !ok || actLeft == 0 || actRight == 0 {
+		return
+	}
+	estCross := left.StatsInfo().RowCount * right.StatsInfo().RowCount
+	if estCross <= 0 {
+		return
+	}
+	exprs := cardinality.JoinFeedbackExprs(join.EqualConditions, join.OtherConditions)
+	keys := feedback.NewJoinKeys(feedbackTableIDsOfPhysicalPlan(join, nil), exprs)
+	if len(keys) == 0 {
+		return
+	}
+	// The estimation may have been corrected by the feedback, so we restore the one by the statistics.
+	estSel := join.StatsInfo().RowCount / estCross / feedback.GlobalCollector.GetCorrection(keys[0])
+	for _, key := range keys {
+		feedback.GlobalCollector.Record(key, exprs, c.sqlDigest, estSel*float64(actLeft)*float64(actRight), float64(actOut))
+	}
+}
+
+func (c *feedbackCollector) actRows(p base.Plan) (int64, bool) {
+	if c.runtimeStatsColl.ExistsCopStats(p.ID()) {
+		return c.runtimeStatsColl.GetCopStats(p.ID()).GetActRows(), true
+	}
+	if c.runtimeStatsColl.ExistsRootStats(p.ID()) {
+		return c.runtimeStatsColl.GetRootStats(p.ID()).GetActRows(), true
+	}
+	return 0, false
+}
+
+// feedbackTableID returns the physical table ID whose rows are output by p, ok is false if p reads more than one
+// table or changes the rows in other ways than filtering.
+func feedbackTableID(p base.PhysicalPlan) (tableID int64, ok bool) {
+	switch x := p.(type) {
+	case *PhysicalTableScan:
+		return x.physicalTableID, true
+	case *PhysicalIndexScan:
+		return x.physicalTableID, true
+	case *PhysicalTableReader:
+		return feedbackTableIDOfCopPlans(x.TablePlans)
+	case *PhysicalIndexReader:
+		return feedbackTableIDOfCopPlans(x.IndexPlans)
+	case *PhysicalIndexLookUpReader:
+		if x.PushedLimit != nil {
+			return 0, false
+		}
+		tableID, ok = feedbackTableIDOfCopPlans(x.IndexPlans)
+		if _, tableOK := feedbackTableIDOfCopPlans(x.TablePlans); !tableOK {
+			return 0, false
+		}
+		return tableID, ok
+	}
+	return 0, false
+}
+
+func feedbackTableIDOfCopPlans(plans []base.PhysicalPlan) (tableID int64, ok bool) {
+	for _, p := range plans {
+		switch p.(type) {
+		case *PhysicalSelection:
+		case *PhysicalTableScan, *PhysicalIndexScan:
+			if ok {
+				return 0, false
+			}
+			tableID, ok = feedbackTableID(p)
+		default:
+			return 0, false
+		}
+	}
+	return tableID, ok
+}
+
+// feedbackTableIDsOfPhysicalPlan appends the IDs of the physical tables read by p to tableIDs, which identify the
+// joins in the cardinality feedback.
+func feedbackTableIDsOfPhysicalPlan(p base.PhysicalPlan, tableIDs []int64) []int64 {
+	switch x := p.(type) {
+	case *PhysicalTableScan:
+		return append(tableIDs, x.physicalTableID)
+	case *PhysicalIndexScan:
+		return append(tableIDs, x.physicalTableID)
+	case *PhysicalTableReader:
+		return feedbackTableIDsOfPhysicalPlan(x.tablePlan, tableIDs)
+	case *PhysicalIndexReader:
+		return feedbackTableIDsOfPhysicalPlan(x.indexPlan, tableIDs)
+	case *PhysicalIndexLookUpReader:
+		return feedbackTableIDsOfPhysicalPlan(x.indexPlan, tableIDs)
+	case *PhysicalIndexMergeReader:
+		if x.tablePlan != nil {
+			return feedbackTableIDsOfPhysicalPlan(x.tablePlan, tableIDs)
+		}
+		return feedbackTableIDsOfPhysicalPlan(x.partialPlans[0], tableIDs)
+	}
+	for _, child := range p.Children() {
+		tableIDs = feedbackTableIDsOfPhysicalPlan(child, tableIDs)
+	}
+	return tableIDs
+}
+
+// feedbackTableIDsOfLogicalPlan appends the IDs of the physical tables read by p to tableIDs, which identify the
+// joins in the cardinality feedback.
+func feedbackTableIDsOfLogicalPlan(p base.LogicalPlan, tableIDs []int64) []int64 {
+	switch x := p.(type) {
+	case *DataSource:
+		return append(tableIDs, x.physicalTableID)
+	case *LogicalTableScan:
+		return append(tableIDs, x.Source.physicalTableID)
+	case *LogicalIndexScan:
+		return append(tableIDs, x.Source.physicalTableID)
+	}
+	for _, child := range p.Children() {
+		tableIDs = feedbackTableIDsOfLogicalPlan(child, tableIDs)
+	}
+	return tableIDs
+}
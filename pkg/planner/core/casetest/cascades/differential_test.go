@@ -0,0 +1,237 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascades
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/planner/util/coretestsdk"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/testkit/external"
+	"github.com/pingcap/tidb/pkg/testkit/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+// checkSameResults runs every query with both the default planner and the cascades planner
+// and requires them to return the same rows. Queries with LIMIT may legally return different
+// rows, so only the number of rows is compared for them.
+func checkSameResults(t *testing.T, tk *testkit.TestKit, queries []string) {
+	for _, q := range queries {
+		tk.MustExec("set @@tidb_enable_cascades_planner = 0")
+		expected := testdata.ConvertRowsToStrings(tk.MustQuery(q).Sort().Rows())
+		tk.MustExec("set @@tidb_enable_cascades_planner = 1")
+		actual := testdata.ConvertRowsToStrings(tk.MustQuery(q).Sort().Rows())
+		for _, warn := range tk.Session().GetSessionVars().StmtCtx.GetWarnings() {
+			require.NotContains(t, warn.Err.Error(), "fall back to the default planner", q)
+		}
+		if strings.Contains(strings.ToLower(q), "limit") {
+			require.Len(t, actual, len(expected), q)
+			continue
+		}
+		require.Equal(t, expected, actual, q)
+	}
+	tk.MustExec("set @@tidb_enable_cascades_planner = 0")
+}
+
+func prepareTransformationRulesTable(tk *testkit.TestKit) {
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int not null, c int not null, d int, e int not null, c_str varchar(40) not null, d_str varchar(40), e_str varchar(40), f int, g int, h int, i_date date, unique key c_d_e(c, d, e), unique key x(e), unique key f(f), key g(g), unique key f_g(f, g), key c_d_e_str(c_str, d_str, e_str), key e_d_c_str_prefix(e_str, d_str, c_str(10)))")
+	tk.MustExec("insert into t values (1, 1, 1, 1, 1, 'a', 'a', 'a', 1, 1, 1, '2020-01-01'), (2, 2, 1, 2, 2, 'b', 'b', 'b', 2, 2, null, null), (3, 1, 2, null, 3, 'c', null, 'c', null, 3, 3, '2020-01-03'), (4, 3, 3, 4, 4, 'd', 'd', null, 4, null, 4, '2020-01-04')")
+	tk.MustExec("analyze table t")
+}
+
+func TestDifferentialTransformationRules(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	prepareTransformationRulesTable(tk)
+	tk.MustExec("set @@sql_mode = ''")
+	suiteData := GetTransformationRulesSuiteData()
+	for _, name := range []string{
+		"TestPredicatePushDown",
+		"TestAggPushDownGather",
+		"TestTopNRules",
+		"TestProjectionElimination",
+		"TestEliminateMaxMin",
+		"TestMergeAggregationProjection",
+		"TestMergeAdjacentLimit",
+		"TestMergeAdjacentTopN",
+		"TestTransformLimitToTableDual",
+		"TestPostTransformationRules",
+		"TestPushLimitDownTiKVSingleGather",
+		"TestEliminateOuterJoin",
+		"TestTransformAggregateCaseToSelection",
+		"TestTransformAggToProj",
+		"TestDecorrelate",
+		"TestInjectProj",
+		"TestMergeAdjacentWindow",
+		"TestJoinReorder",
+	} {
+		var input []string
+		var output []any
+		suiteData.LoadTestCasesByName(name, t, &input, &output)
+		checkSameResults(t, tk, input)
+	}
+}
+
+func TestDifferentialQueries(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	prepareTransformationRulesTable(tk)
+	checkSameResults(t, tk, []string{
+		"select * from t where a in (select b from t)",
+		"select * from t where a not in (select d from t)",
+		"select * from t t1 where exists (select 1 from t t2 where t1.a = t2.d)",
+		"select * from t t1 where not exists (select 1 from t t2 where t1.a = t2.d)",
+		"select a, (select max(b) from t t2 where t2.c = t1.c) from t t1",
+		"select a, (select b from t t2 where t2.a = t1.a) from t t1",
+		"select a from t where b > (select avg(b) from t)",
+		"select * from t t1 where t1.a = (select max(a) from t t2 where t2.b = t1.b)",
+		"select * from t where (a, b) in (select c, d from t)",
+		"select a from t where a > any (select d from t)",
+		"select a from t where a > all (select d from t)",
+		"with cte as (select a, b from t) select * from cte where a > 1",
+		"with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 5) select * from cte",
+		"select /*+ use_index_merge(t, f, g) */ * from t where f = 1 or g = 2",
+		"select a from t union select b from t",
+		"select a from t union all select b from t",
+		"select a from t except select b from t",
+		"select a from t intersect select b from t",
+		"select * from t t1 join t t2 on t1.a = t2.b join t t3 on t2.c = t3.a",
+		"select * from t t1 left join t t2 on t1.a = t2.d right join t t3 on t2.c = t3.a",
+		"select * from t t1, t t2 where t1.a = t2.a and t1.b > 1",
+		"select count(distinct b), c from t group by c",
+		"select b, group_concat(c order by c) from t group by b",
+		"select sum(a) from t having sum(a) > 0",
+		"select distinct b from t",
+		"select a, row_number() over (order by a) from t",
+		"select * from t where a in (1, 2) for update",
+	})
+}
+
+func TestDifferentialJoinReorder(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	for _, tbl := range []string{"t1", "t2", "t3", "t4"} {
+		tk.MustExec("create table " + tbl + "(a int, b int, key(a))")
+		tk.MustExec("insert into " + tbl + " values (1, 1), (2, 2), (null, 3), (3, null), (2, 1)")
+	}
+	var input []string
+	var output []any
+	suiteData := GetJoinReorderSuiteData()
+	suiteData.LoadTestCasesByName("TestOptEnableHashJoin", t, &input, &output)
+	checkSameResults(t, tk, input)
+}
+
+func TestDifferentialPartitionTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	for _, ddl := range []string{
+		"create table t(a int, b int) partition by hash(a) partitions 3",
+		"create table t1(a int, b int) partition by hash(a) partitions 4",
+		"create table t2(a int, b int) partition by hash(a) partitions 5",
+		"create table t3(a int, b int) partition by hash(b) partitions 3",
+		"create table t4(a int, b int) partition by hash(a) partitions 4",
+		"create table t5(a int, b int) partition by hash(a) partitions 5",
+		"create table t6(a int, b int) partition by hash(b) partitions 3",
+	} {
+		tk.MustExec(ddl)
+	}
+	for _, tbl := range []string{"t", "t1", "t2", "t3", "t4", "t5", "t6"} {
+		tk.MustExec("insert into " + tbl + " values (1, 1), (2, 2), (null, 3), (3, null), (2, 1)")
+	}
+	var input []string
+	var output []any
+	suiteData := GetJoinReorderSuiteData()
+	suiteData.LoadTestCasesByName("TestJoinOrderHint4DynamicPartitionTable", t, &input, &output)
+	checkSameResults(t, tk, input)
+}
+
+func TestDifferentialIndexMerge(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	prepareTransformationRulesTable(tk)
+	queries := []string{
+		"select /*+ use_index_merge(t, f, g) */ * from t where f = 1 or g = 2",
+		"select /*+ use_index_merge(t, f, g) */ a, f from t where (f = 1 or g = 3) and b > 0",
+		"select /*+ use_index_merge(t, primary, g) */ * from t where a = 2 or g = 3",
+	}
+	tk.MustExec("set @@tidb_enable_cascades_planner = 1")
+	for _, q := range queries {
+		tk.MustHavePlan(q, "IndexMerge")
+	}
+	checkSameResults(t, tk, queries)
+}
+
+func TestDifferentialMPP(t *testing.T) {
+	store := testkit.CreateMockStore(t, coretestsdk.WithMockTiFlash(2))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	for _, tbl := range []string{"t1", "t2"} {
+		tk.MustExec("create table " + tbl + "(a int, b int, c int)")
+		tk.MustExec("insert into " + tbl + " values (1, 1, 1), (2, 2, 2), (null, 3, 3), (3, null, 4), (2, 1, 5)")
+		tk.MustExec("alter table " + tbl + " set tiflash replica 1")
+		tb := external.GetTableByName(t, tk, "test", tbl)
+		err := domain.GetDomain(tk.Session()).DDL().UpdateTableReplicaInfo(tk.Session(), tb.Meta().ID, true)
+		require.NoError(t, err)
+	}
+	tk.MustExec("set @@tidb_isolation_read_engines = 'tiflash'")
+	tk.MustExec("set @@tidb_enforce_mpp = 1")
+	queries := []string{
+		"select * from t1 where a > 1",
+		"select * from t1 join t2 on t1.a = t2.a",
+		"select /*+ shuffle_join(t1, t2) */ t1.a, t2.c from t1 join t2 on t1.a = t2.a where t1.b > 0",
+		"select /*+ broadcast_join(t1, t2) */ t1.c, t2.b from t1 join t2 on t1.b = t2.b",
+	}
+	tk.MustExec("set @@tidb_enable_cascades_planner = 1")
+	for _, q := range queries {
+		tk.MustHavePlan(q, "ExchangeSender")
+	}
+	checkSameResults(t, tk, queries)
+}
+
+func TestCascadesPlan(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, c int, key(a), key(b))")
+	tk.MustExec("create table t2(a int, b int, c int, key(a), key(b))")
+	tk.MustExec("create table t3(a int, b int, c int, key(a), key(b))")
+	tk.MustExec("create table tp(a int, b int) partition by hash(a) partitions 4")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_enable_cascades_planner = 1")
+	var input []string
+	var output []struct {
+		SQL  string
+		Plan []string
+		Warn []string
+	}
+	suiteData := GetCascadesSuiteData()
+	suiteData.LoadTestCases(t, &input, &output)
+	for i, sql := range input {
+		testdata.OnRecord(func() {
+			output[i].SQL = sql
+			output[i].Plan = testdata.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + sql).Rows())
+			output[i].Warn = testdata.ConvertSQLWarnToStrings(tk.Session().GetSessionVars().StmtCtx.GetWarnings())
+		})
+		tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(output[i].Plan...))
+		require.Equal(t, output[i].Warn, testdata.ConvertSQLWarnToStrings(tk.Session().GetSessionVars().StmtCtx.GetWarnings()))
+	}
+}
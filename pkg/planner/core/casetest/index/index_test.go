@@ -16,11 +16,14 @@ package index
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/testkit/testdata"
 	"github.com/pingcap/tidb/pkg/util"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNullConditionForPrefixIndex(t *testing.T) {
@@ -95,3 +98,138 @@ func TestInvisibleIndex(t *testing.T) {
 			`IndexReader_7 10000.00 root  index:IndexFullScan_6`,
 			`└─IndexFullScan_6 10000.00 cop[tikv] table:t1, index:a(a) keep order:false, stats:pseudo`))
 }
+
+func TestVectorIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v vector(3), vector index idx_l2((vec_l2_distance(v))))")
+	tk.MustExec("alter table t add vector index idx_cos((vec_cosine_distance(v)))")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `v` vector(3) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  VECTOR INDEX `idx_l2` ((VEC_L2_DISTANCE(`v`))),\n" +
+		"  VECTOR INDEX `idx_cos` ((VEC_COSINE_DISTANCE(`v`)))\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select index_name, index_type from information_schema.statistics where table_name = 't' and index_name like 'idx%'").Sort().Check(
+		testkit.Rows("idx_cos VECTOR", "idx_l2 VECTOR"))
+	tk.MustGetErrCode("create index idx on t (v)", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create vector index idx on t ((vec_l1_distance(v)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create vector index idx on t ((vec_l2_distance(id)))", errno.ErrUnsupportedDDLOperation)
+
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, '[%d, %d, %d]')", i, i, 100-i, i%7))
+	}
+	tk.MustExec("insert into t values (100, null)")
+	tk.MustExec("update t set v = '[50, 50, 50]' where id = 99")
+	tk.MustExec("delete from t where id = 98")
+	tk.MustExec("admin check table t")
+
+	// The VECTOR index reads the candidates and the TopN sorts them by the exact distance.
+	tk.MustExec("set @@tidb_vector_search_nprobe = 1")
+	rows := tk.MustQuery("explain format = 'brief' select id from t order by vec_l2_distance(v, '[10, 90, 3]') limit 3").Rows()
+	require.Equal(t, "    └─Projection", rows[3][0])
+	require.Equal(t, `test.t.id, test.t.v, vec_l2_distance(test.t.v, [10,90,3])->Column#4`, rows[3][4])
+	require.Equal(t, "      └─IndexLookUp", rows[4][0])
+	require.Contains(t, rows[5][3], "index:idx_l2(v)")
+	// The NULL vectors are sorted first like the exact search.
+	tk.MustQuery("select id from t order by vec_l2_distance(v, '[10, 90, 3]') limit 2").Check(testkit.Rows("100", "10"))
+	tk.MustQuery("select id from t use index() order by vec_l2_distance(v, '[10, 90, 3]') limit 2").Check(testkit.Rows("100", "10"))
+	rows = tk.MustQuery("explain format = 'brief' select id from t use index(idx_l2) order by vec_l2_distance(v, '[10, 90, 3]') limit 2").Rows()
+	require.Contains(t, rows[5][3], "index:idx_l2(v)")
+	rows = tk.MustQuery("explain format = 'brief' select id from t order by vec_cosine_distance('[1, 1, 1]', v) limit 2").Rows()
+	require.Contains(t, rows[5][3], "index:idx_cos(v)")
+	tk.MustQuery("select id from t order by vec_cosine_distance('[1, 1, 1]', v) limit 2").Check(testkit.Rows("100", "99"))
+
+	// Fall back to the exact search if the index can't be used.
+	for _, sql := range []string{
+		"select id from t where id > 10 order by vec_l2_distance(v, '[10, 90, 3]') limit 3",
+		"select id from t order by vec_l2_distance(v, '[10, 90, 3]') desc limit 3",
+		"select id from t order by vec_negative_inner_product(v, '[10, 90, 3]') limit 3",
+		"select id from t order by vec_l2_distance(v, '[10, 90, 3]') limit 100000",
+		"select id from t order by vec_l2_distance(v, '[10, 90]') limit 3",
+		"select id from t order by vec_l2_distance(v, v) limit 3",
+		"select id from t use index() order by vec_l2_distance(v, '[10, 90, 3]') limit 3",
+		"select id from t ignore index(idx_l2) order by vec_l2_distance(v, '[10, 90, 3]') limit 3",
+		"select /*+ use_index(t, idx_cos) */ id from t order by vec_l2_distance(v, '[10, 90, 3]') limit 3",
+	} {
+		rows = tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		for _, row := range rows {
+			require.NotContains(t, row[0], "IndexLookUp", sql)
+		}
+	}
+	// The index isn't used if the probes cover most of the partitions.
+	tk.MustExec("set @@tidb_vector_search_nprobe = 200")
+	rows = tk.MustQuery("explain format = 'brief' select id from t order by vec_l2_distance(v, '[10, 90, 3]') limit 3").Rows()
+	for _, row := range rows {
+		require.NotContains(t, row[0], "IndexLookUp")
+	}
+	tk.MustQuery("select id from t where id > 10 order by vec_l2_distance(v, '[10, 90, 3]') limit 3").Check(testkit.Rows("100", "11", "12"))
+
+	// The projection over the table pushes no TopN down, the aggregation only makes the TopN push down rule run.
+	rows = tk.MustQuery("select id + 1 from t union all select count(*) from t").Rows()
+	require.Len(t, rows, 101)
+}
+
+func TestOrExpansion(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, c int, key(a), key(b))")
+	tk.MustExec("create table t2(a int, b int, c int, key(a), key(b))")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d, %d)", i, 999-i, i%100))
+	}
+	vals = append(vals, "(null, 1, 1)", "(1, null, 2)", "(null, null, 3)", "(1, 998, 4)")
+	tk.MustExec("insert into t1 values " + strings.Join(vals, ","))
+	tk.MustExec("insert into t2 values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t1, t2")
+
+	queries := []string{
+		"select * from t1 where a = 1 or b = 1",
+		"select * from t1 where a = 1 or b = 998 or c = 2",
+		"select * from t1 where (a = 1 or b is null) or (b = 998 and c = 4)",
+		"select t1.a, t2.b from t1 join t2 on t1.c = t2.c where t1.a = 1 or t2.b = 1",
+		"select t1.a, t2.b from t1 left join t2 on t1.a = t2.a where t1.b = 1 or t2.a is null",
+		"select * from t1 where a = 1 or b in (select b from t2 where a < 3)",
+	}
+	expected := make([][][]any, 0, len(queries))
+	for _, q := range queries {
+		expected = append(expected, tk.MustQuery(q).Sort().Rows())
+		tk.MustNotHavePlan(q, "Union")
+	}
+
+	tk.MustExec("set @@tidb_opt_enable_or_expansion = 1")
+	for i, q := range queries {
+		tk.MustQuery(q).Sort().Check(expected[i])
+	}
+	// The branches of the join are filtered by the indexes of different tables.
+	q := "select t1.a, t2.b from t1 join t2 on t1.c = t2.c where t1.a = 1 or t2.b = 1"
+	tk.MustHavePlan(q, "Union")
+	// The expanded plan is not chosen if it's more expensive, and the plan IDs of the original plan are kept.
+	q = "select * from t1 where a > 1 or b > 1"
+	tk.MustNotHavePlan(q, "Union")
+	plan := tk.MustQuery("explain " + q).Rows()
+	tk.MustExec("set @@tidb_opt_enable_or_expansion = 0")
+	tk.MustQuery("explain " + q).Check(plan)
+	tk.MustExec("set @@tidb_opt_enable_or_expansion = 1")
+	// The statements can't be expanded.
+	for _, q := range []string{
+		"select * from t1 where a = 1 or b = 1 limit 10",
+		"select * from t1 where a = 1 or b = 1 order by c",
+		"select distinct c from t1 where a = 1 or b = 1",
+		"select count(*) from t1 where a = 1 or b = 1",
+		"select * from t1 where a = 1 or b = floor(rand() * 10)",
+		"select * from t1 where a = 1 or b = (select max(b) from t2)",
+		"select * from t1 where a = 1 or c = 1",
+		"select * from t1 where a = 1 or b = 1 for update",
+		"select /*+ use_index(t1@sel_1, a) */ * from t1 where a = 1 or b = 1",
+	} {
+		tk.MustNotHavePlan(q, "Union")
+	}
+	tk.MustExec("prepare stmt from 'select * from t1 where a = ? or b = ?'")
+	tk.MustExec("set @a = 1, @b = 1")
+	tk.MustQuery("execute stmt using @a, @b").Sort().Check(expected[0])
+}
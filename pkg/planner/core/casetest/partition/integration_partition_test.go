@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/planner/util/coretestsdk"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/testkit/testdata"
 	"github.com/stretchr/testify/require"
@@ -246,3 +247,142 @@ func TestBatchPointGetPartitionForAccessObject(t *testing.T) {
 		tk.MustQuery(tt).Check(testkit.Rows(output[i].Plan...))
 	}
 }
+
+func TestPartitionWiseJoin(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	tk.MustExec("create table t1 (a int, b int) partition by hash(a) partitions 3")
+	tk.MustExec("create table t2 (a int, b int) partition by hash(a) partitions 3")
+	tk.MustExec("create table t3 (a bigint, b int) partition by hash(a) partitions 2")
+	tk.MustExec("create table r1 (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than maxvalue)")
+	tk.MustExec("create table r2 (a int, b int) partition by range columns (a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than maxvalue)")
+	tk.MustExec("create table r3 (a int, b int) partition by range (a) (partition p0 values less than (5), partition p1 values less than (20), partition p2 values less than maxvalue)")
+	for _, tbl := range []string{"t1", "t2", "t3", "r1", "r2", "r3"} {
+		tk.MustExec("insert into " + tbl + " values (1, 1), (2, 2), (3, 3), (11, 11), (12, 12), (25, 25), (null, 0)")
+	}
+	tk.MustExec("insert into t2 values (4, 4), (13, 13)")
+	tk.MustExec("insert into r2 values (4, 4), (13, 13)")
+	tk.MustExec("analyze table t1, t2, t3, r1, r2, r3")
+
+	for _, q := range []string{
+		"select * from t1 join t2 on t1.a = t2.a",
+		"select * from t1 left join t2 on t1.a = t2.a and t2.a = 2",
+		"select * from t1 right join t2 on t1.a = t2.a and t1.a = 2",
+		"select * from t2 where exists (select 1 from t1 where t1.a = t2.a and t1.b > t2.b - 2)",
+		"select * from t2 where not exists (select 1 from t1 where t1.a = t2.a)",
+		"select * from t2 where a not in (select a from t1)",
+		"select * from r1 join r2 on r1.a = r2.a where r2.a > 10",
+		"select * from r2 left join r1 on r1.a = r2.a and r1.b < 10",
+	} {
+		tk.MustExec("set @@tidb_opt_enable_partition_wise_join = 0")
+		expected := tk.MustQuery(q).Sort().Rows()
+		tk.MustExec("set @@tidb_opt_enable_partition_wise_join = 1")
+		tk.MustQuery(q).Sort().Check(expected)
+	}
+
+	tk.MustQuery("explain format = 'brief' select * from r1 join r2 on r1.a = r2.a where r2.a > 10").Check(testkit.Rows(
+		"PartitionUnion 3.00 root  ",
+		"├─HashJoin 2.00 root  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"│ ├─TableReader(Build) 2.00 root  data:Selection",
+		"│ │ └─Selection 2.00 cop[tikv]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"│ │   └─TableFullScan 2.00 cop[tikv] table:r1, partition:p1 keep order:false",
+		"│ └─TableReader(Probe) 3.00 root  data:Selection",
+		"│   └─Selection 3.00 cop[tikv]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"│     └─TableFullScan 3.00 cop[tikv] table:r2, partition:p1 keep order:false",
+		"└─HashJoin 1.00 root  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"  ├─TableReader(Build) 1.00 root  data:Selection",
+		"  │ └─Selection 1.00 cop[tikv]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"  │   └─TableFullScan 1.00 cop[tikv] table:r2, partition:p2 keep order:false",
+		"  └─TableReader(Probe) 1.00 root  data:Selection",
+		"    └─Selection 1.00 cop[tikv]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"      └─TableFullScan 1.00 cop[tikv] table:r1, partition:p2 keep order:false"))
+	// The partitions pruned from the inner side of the outer join are replaced by TableDual.
+	tk.MustQuery("explain format = 'brief' select * from t1 left join t2 on t1.a = t2.a and t2.a = 2 where t1.a in (1, 2)").Check(testkit.Rows(
+		"PartitionUnion 2.00 root  ",
+		"├─HashJoin 1.00 root  left outer join, equal:[eq(test.t1.a, test.t2.a)]",
+		"│ ├─TableDual(Build) 0.00 root  rows:0",
+		"│ └─TableReader(Probe) 1.00 root  data:Selection",
+		"│   └─Selection 1.00 cop[tikv]  in(test.t1.a, 1, 2)",
+		"│     └─TableFullScan 2.00 cop[tikv] table:t1, partition:p1 keep order:false",
+		"└─HashJoin 1.00 root  left outer join, equal:[eq(test.t1.a, test.t2.a)]",
+		"  ├─TableReader(Build) 1.00 root  data:Selection",
+		"  │ └─Selection 1.00 cop[tikv]  eq(test.t2.a, 2)",
+		"  │   └─TableFullScan 2.00 cop[tikv] table:t2, partition:p2 keep order:false",
+		"  └─TableReader(Probe) 1.00 root  data:Selection",
+		"    └─Selection 1.00 cop[tikv]  in(test.t1.a, 1, 2)",
+		"      └─TableFullScan 2.00 cop[tikv] table:t1, partition:p2 keep order:false"))
+	// The join isn't split if the join key isn't the partition column, the partitions aren't identical, or the
+	// types of the partition columns are different.
+	for _, q := range []string{
+		"select * from t1 join t2 on t1.b = t2.b",
+		"select * from t1 join t3 on t1.a = t3.a",
+		"select * from r1 join r3 on r1.a = r3.a",
+		"select * from t2 where a not in (select a from t1)",
+	} {
+		rows := tk.MustQuery("explain format = 'brief' " + q).Rows()
+		require.True(t, strings.HasPrefix(rows[0][0].(string), "HashJoin"), q)
+	}
+
+	// Under dynamic partition prune mode, each partition is read by a reader of the partitioned table.
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("analyze table t1, t2, r1, r2")
+	for _, q := range []string{
+		"select * from t1 join t2 on t1.a = t2.a",
+		"select * from t1 left join t2 on t1.a = t2.a and t2.a = 2",
+		"select * from t2 where not exists (select 1 from t1 where t1.a = t2.a)",
+		"select * from r1 join r2 on r1.a = r2.a where r2.a > 10",
+		"select * from t1 partition (p1, p2) join t2 on t1.a = t2.a",
+	} {
+		tk.MustExec("set @@tidb_opt_enable_partition_wise_join = 0")
+		expected := tk.MustQuery(q).Sort().Rows()
+		tk.MustExec("set @@tidb_opt_enable_partition_wise_join = 1")
+		tk.MustQuery(q).Sort().Check(expected)
+		rows := tk.MustQuery("explain format = 'brief' " + q).Rows()
+		require.True(t, strings.HasPrefix(rows[0][0].(string), "PartitionUnion"), q)
+	}
+	tk.MustQuery("explain format = 'brief' select * from r1 join r2 on r1.a = r2.a where r2.a > 10").Check(testkit.Rows(
+		"PartitionUnion 6.75 root  ",
+		"├─HashJoin 3.38 root  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"│ ├─TableReader(Build) 3.00 root partition:p1 data:Selection",
+		"│ │ └─Selection 3.00 cop[tikv]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"│ │   └─TableFullScan 7.00 cop[tikv] table:r1 keep order:false",
+		"│ └─TableReader(Probe) 4.00 root partition:p1 data:Selection",
+		"│   └─Selection 4.00 cop[tikv]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"│     └─TableFullScan 9.00 cop[tikv] table:r2 keep order:false",
+		"└─HashJoin 3.38 root  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"  ├─TableReader(Build) 3.00 root partition:p2 data:Selection",
+		"  │ └─Selection 3.00 cop[tikv]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"  │   └─TableFullScan 7.00 cop[tikv] table:r1 keep order:false",
+		"  └─TableReader(Probe) 4.00 root partition:p2 data:Selection",
+		"    └─Selection 4.00 cop[tikv]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"      └─TableFullScan 9.00 cop[tikv] table:r2 keep order:false"))
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+
+	// The joins of the partitions can be pushed down to TiFlash as MPP tasks.
+	coretestsdk.SetTiFlashReplica(t, dom, "test", "r1")
+	coretestsdk.SetTiFlashReplica(t, dom, "test", "r2")
+	tk.MustExec("set @@tidb_isolation_read_engines = 'tiflash'")
+	tk.MustExec("set @@tidb_enforce_mpp = 1")
+	tk.MustQuery("explain format = 'brief' select * from r1 join r2 on r1.a = r2.a where r2.a > 10").Check(testkit.Rows(
+		"PartitionUnion 3.00 root  ",
+		"├─TableReader 2.00 root  MppVersion: 2, data:ExchangeSender",
+		"│ └─ExchangeSender 2.00 mpp[tiflash]  ExchangeType: PassThrough",
+		"│   └─HashJoin 2.00 mpp[tiflash]  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"│     ├─ExchangeReceiver(Build) 2.00 mpp[tiflash]  ",
+		"│     │ └─ExchangeSender 2.00 mpp[tiflash]  ExchangeType: Broadcast, Compression: FAST",
+		"│     │   └─Selection 2.00 mpp[tiflash]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"│     │     └─TableFullScan 2.00 mpp[tiflash] table:r1, partition:p1 pushed down filter:empty, keep order:false",
+		"│     └─Selection(Probe) 3.00 mpp[tiflash]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"│       └─TableFullScan 3.00 mpp[tiflash] table:r2, partition:p1 pushed down filter:empty, keep order:false",
+		"└─TableReader 1.00 root  MppVersion: 2, data:ExchangeSender",
+		"  └─ExchangeSender 1.00 mpp[tiflash]  ExchangeType: PassThrough",
+		"    └─HashJoin 1.00 mpp[tiflash]  inner join, equal:[eq(test.r1.a, test.r2.a)]",
+		"      ├─ExchangeReceiver(Build) 1.00 mpp[tiflash]  ",
+		"      │ └─ExchangeSender 1.00 mpp[tiflash]  ExchangeType: Broadcast, Compression: FAST",
+		"      │   └─Selection 1.00 mpp[tiflash]  gt(test.r1.a, 10), not(isnull(test.r1.a))",
+		"      │     └─TableFullScan 1.00 mpp[tiflash] table:r1, partition:p2 pushed down filter:empty, keep order:false",
+		"      └─Selection(Probe) 1.00 mpp[tiflash]  gt(test.r2.a, 10), not(isnull(test.r2.a))",
+		"        └─TableFullScan 1.00 mpp[tiflash] table:r2, partition:p2 pushed down filter:empty, keep order:false"))
+}
@@ -499,6 +499,58 @@ func (p *Delete) MemoryUsage() (sum int64) {
 	return
 }
 
+// Merge represents a MERGE plan. The SelectPlan joins the source with the target, each row of it is handled by
+// the first clause whose condition is satisfied.
+type Merge struct {
+	baseSchemaProducer
+
+	SelectPlan base.PhysicalPlan
+
+	// TblColPosInfo is the position of the target columns in the rows of the SelectPlan. The handle is NULL for
+	// the source rows which don't match any target row.
+	TblColPosInfo TblColPosInfo
+
+	Clauses []*MergeClause
+}
+
+// MergeClause represents a WHEN clause of the MERGE plan. Only one of Update, Delete and Insert is set.
+type MergeClause struct {
+	Matched   bool
+	Condition expression.Expression
+
+	// Update and Delete share the SelectPlan of the Merge plan.
+	Update *Update
+	Delete *Delete
+	Insert *Insert
+	// Values are evaluated on the rows of the SelectPlan to build the inserted rows.
+	Values []expression.Expression
+}
+
+// MemoryUsage return the memory usage of Merge
+func (p *Merge) MemoryUsage() (sum int64) {
+	if p == nil {
+		return
+	}
+
+	sum = p.baseSchemaProducer.MemoryUsage() + size.SizeOfInterface + p.TblColPosInfo.MemoryUsage() +
+		size.SizeOfSlice + int64(cap(p.Clauses))*size.SizeOfPointer
+	if p.SelectPlan != nil {
+		sum += p.SelectPlan.MemoryUsage()
+	}
+	for _, clause := range p.Clauses {
+		sum += size.SizeOfBool + size.SizeOfInterface + size.SizeOfPointer*3 + size.SizeOfSlice +
+			int64(cap(clause.Values))*size.SizeOfInterface
+		if clause.Condition != nil {
+			sum += clause.Condition.MemoryUsage()
+		}
+		for _, expr := range clause.Values {
+			sum += expr.MemoryUsage()
+		}
+		sum += clause.Insert.MemoryUsage()
+	}
+	return
+}
+
 // AnalyzeInfo is used to store the database name, table name and partition name of analyze task.
 type AnalyzeInfo struct {
 	DBName        string
@@ -640,6 +692,14 @@ type IndexAdvise struct {
 	LineFieldsInfo
 }
 
+// RecommendIndex represents a recommend index plan.
+type RecommendIndex struct {
+	baseSchemaProducer
+
+	// SQL is the workload to recommend the indexes for, the statement summary is used if it's empty.
+	SQL string
+}
+
 // SplitRegion represents a split regions plan.
 type SplitRegion struct {
 	baseSchemaProducer
@@ -675,6 +735,10 @@ type DDL struct {
 	baseSchemaProducer
 
 	Statement ast.DDLNode
+
+	// SelectPlan and SelectNames are the plan and the output names of the select in `CREATE TABLE ... SELECT`.
+	SelectPlan  base.PhysicalPlan
+	SelectNames types.NameSlice
 }
 
 // SelectInto represents a select-into plan.
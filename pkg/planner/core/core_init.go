@@ -15,6 +15,7 @@
 package core
 
 import (
+	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
 	plannerutil "github.com/pingcap/tidb/pkg/planner/util"
@@ -44,4 +45,7 @@ func init() {
 	plannerutil.RewriteAstExprWithPlanCtx = rewriteAstExprWithPlanCtx
 	DefaultDisabledLogicalRulesList = new(atomic.Value)
 	DefaultDisabledLogicalRulesList.Store(set.NewStringSet())
+
+	// For instance plan cache init.
+	domain.NewInstancePlanCache = NewInstancePlanCache
 }
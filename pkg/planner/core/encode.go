@@ -376,6 +376,8 @@ func getSelectPlan(p base.Plan) base.PhysicalPlan {
 			selectPlan = x.SelectPlan
 		case *Update:
 			selectPlan = x.SelectPlan
+		case *Merge:
+			selectPlan = x.SelectPlan
 		case *Insert:
 			selectPlan = x.SelectPlan
 		case *Explain:
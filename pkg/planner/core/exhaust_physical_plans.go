@@ -574,6 +574,9 @@ func (p *LogicalJoin) constructIndexJoin(
 	if path != nil {
 		join.IdxColLens = path.IdxColLens
 	}
+	if p.SCtx().GetSessionVars().EnableAdaptiveJoin {
+		join.adaptiveJoin = p
+	}
 	join.SetSchema(p.schema)
 	return []base.PhysicalPlan{join}
 }
@@ -939,6 +942,10 @@ func (p *LogicalJoin) buildIndexJoinInner2IndexScan(
 		if path.IsTablePath() {
 			return false
 		}
+		// The skip scan path reads the same index as the regular one, which is enough to build the ranges from the join keys.
+		if path.SkipScanRanges != nil {
+			return false
+		}
 		// if path is index path. index path currently include two kind of, one is normal, and the other is mv index.
 		// for mv index like mvi(a, json, b), if driving condition is a=1, and we build a prefix scan with range [1,1]
 		// on mvi, it will return many index rows which breaks handle-unique attribute here.
@@ -2498,6 +2505,33 @@ func canExprsInJoinPushdown(p *LogicalJoin, storeType kv.StoreType) bool {
 }
 
 func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBCJ bool) []base.PhysicalPlan {
+	return p.getMppHashJoin(prop, useBCJ, p.schema, p.StatsInfo(), p.children[0].StatsInfo(), p.children[1].StatsInfo())
+}
+
+// GetMPPHashJoins converts the logical join to the physical hash joins running in
+// the MPP tasks of TiFlash. Both the shuffle join and the broadcast join are
+// returned to be chosen by cost, unless one of them is required by the hints.
+func (p *LogicalJoin) GetMPPHashJoins(prop *property.PhysicalProperty, schema *expression.Schema, statsInfo, leftStatsInfo, rightStatsInfo *property.StatsInfo) []base.PhysicalPlan {
+	if !isJoinHintSupportedInMPPMode(p.preferJoinType) {
+		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because you have used hint to specify a join algorithm which is not supported by mpp now.")
+		return nil
+	}
+	if (p.preferJoinType & h.PreferShuffleJoin) > 0 {
+		if shuffleJoins := p.getMppHashJoin(prop, false, schema, statsInfo, leftStatsInfo, rightStatsInfo); len(shuffleJoins) > 0 {
+			return shuffleJoins
+		}
+	}
+	if (p.preferJoinType & h.PreferBCJoin) > 0 {
+		if bcastJoins := p.getMppHashJoin(prop, true, schema, statsInfo, leftStatsInfo, rightStatsInfo); len(bcastJoins) > 0 {
+			return bcastJoins
+		}
+	}
+	joins := p.getMppHashJoin(prop, false, schema, statsInfo, leftStatsInfo, rightStatsInfo)
+	return append(joins, p.getMppHashJoin(prop, true, schema, statsInfo, leftStatsInfo, rightStatsInfo)...)
+}
+
+func (p *LogicalJoin) getMppHashJoin(prop *property.PhysicalProperty, useBCJ bool, schema *expression.Schema, statsInfo, leftStatsInfo, rightStatsInfo *property.StatsInfo) []base.PhysicalPlan {
+	childrenStats := []*property.StatsInfo{leftStatsInfo, rightStatsInfo}
 	if !prop.IsSortItemEmpty() {
 		return nil
 	}
@@ -2566,7 +2600,7 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 	preferredBuildIndex := 0
 	fixedBuildSide := false // Used to indicate whether the build side for the MPP join is fixed or not.
 	if p.JoinType == InnerJoin {
-		if p.children[0].StatsInfo().Count() > p.children[1].StatsInfo().Count() {
+		if childrenStats[0].Count() > childrenStats[1].Count() {
 			preferredBuildIndex = 1
 		}
 	} else if p.JoinType.IsSemiJoin() {
@@ -2575,7 +2609,7 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 			preferredBuildIndex = 1
 			// MPPOuterJoinFixedBuildSide default value is false
 			// use MPPOuterJoinFixedBuildSide here as a way to disable using left table as build side
-			if !p.SCtx().GetSessionVars().MPPOuterJoinFixedBuildSide && p.children[1].StatsInfo().Count() > p.children[0].StatsInfo().Count() {
+			if !p.SCtx().GetSessionVars().MPPOuterJoinFixedBuildSide && childrenStats[1].Count() > childrenStats[0].Count() {
 				preferredBuildIndex = 0
 			}
 		} else {
@@ -2597,7 +2631,7 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 			if p.JoinType == LeftOuterJoin {
 				preferredBuildIndex = 1
 			}
-		} else if p.children[0].StatsInfo().Count() > p.children[1].StatsInfo().Count() {
+		} else if childrenStats[0].Count() > childrenStats[1].Count() {
 			preferredBuildIndex = 1
 		}
 	}
@@ -2628,9 +2662,9 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 	if useBCJ {
 		childrenProps[preferredBuildIndex] = &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64, MPPPartitionTp: property.BroadcastType, CanAddEnforcer: true, RejectSort: true, CTEProducerStatus: prop.CTEProducerStatus}
 		expCnt := math.MaxFloat64
-		if prop.ExpectedCnt < p.StatsInfo().RowCount {
-			expCntScale := prop.ExpectedCnt / p.StatsInfo().RowCount
-			expCnt = p.children[1-preferredBuildIndex].StatsInfo().RowCount * expCntScale
+		if prop.ExpectedCnt < statsInfo.RowCount {
+			expCntScale := prop.ExpectedCnt / statsInfo.RowCount
+			expCnt = childrenStats[1-preferredBuildIndex].RowCount * expCntScale
 		}
 		if prop.MPPPartitionTp == property.HashType {
 			lPartitionKeys, rPartitionKeys := p.GetPotentialPartitionKeys()
@@ -2684,8 +2718,8 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 		storeTp:           kv.TiFlash,
 		mppShuffleJoin:    !useBCJ,
 		// Mpp Join has quite heavy cost. Even limit might not suspend it in time, so we don't scale the count.
-	}.Init(p.SCtx(), p.StatsInfo(), p.QueryBlockOffset(), childrenProps...)
-	join.SetSchema(p.schema)
+	}.Init(p.SCtx(), statsInfo, p.QueryBlockOffset(), childrenProps...)
+	join.SetSchema(schema)
 	return []base.PhysicalPlan{join}
 }
 
@@ -3578,6 +3612,9 @@ func (la *LogicalAggregation) ExhaustPhysicalPlans(prop *property.PhysicalProper
 	}
 
 	aggs := append(hashAggs, streamAggs...)
+	if !preferHash && !preferStream {
+		aggs = append(aggs, la.getLooseIndexScans(prop)...)
+	}
 
 	if streamAggs == nil && preferStream && !prop.IsSortItemEmpty() {
 		la.SCtx().GetSessionVars().StmtCtx.SetHintWarning("Optimizer Hint STREAM_AGG is inapplicable")
@@ -58,6 +58,9 @@ func (p *PhysicalIndexScan) ExplainID() fmt.Stringer {
 
 // TP overrides the TP in order to match different range.
 func (p *PhysicalIndexScan) TP() string {
+	if p.SkipScanRanges != nil {
+		return plancodec.TypeIndexSkipScan
+	}
 	if p.isFullScan() {
 		return plancodec.TypeIndexFullScan
 	}
@@ -98,6 +101,18 @@ func (p *PhysicalIndexScan) OperatorInfo(normalized bool) string {
 			}
 			buffer.WriteString("], ")
 		}
+	} else if p.SkipScanRanges != nil {
+		buffer.WriteString("skip:")
+		buffer.WriteString(p.Index.Columns[0].Name.O)
+		if normalized {
+			buffer.WriteString(", range:[?,?], ")
+		} else {
+			buffer.WriteString(", range:")
+			for _, idxRange := range p.SkipScanRanges {
+				buffer.WriteString(idxRange.String())
+				buffer.WriteString(", ")
+			}
+		}
 	} else if len(p.Ranges) > 0 {
 		if normalized {
 			buffer.WriteString("range:[?,?], ")
@@ -464,38 +479,42 @@ func (p *basePhysicalAgg) ExplainInfo() string {
 }
 
 func (p *basePhysicalAgg) explainInfo(normalized bool) string {
+	builder := &strings.Builder{}
+	explainAggFuncs(builder, p.SCtx().GetExprCtx().GetEvalCtx(), p.GroupByItems, p.AggFuncs, p.schema, normalized)
+	if p.TiFlashFineGrainedShuffleStreamCount > 0 {
+		fmt.Fprintf(builder, ", stream_count: %d", p.TiFlashFineGrainedShuffleStreamCount)
+	}
+	return builder.String()
+}
+
+func explainAggFuncs(builder *strings.Builder, ctx expression.EvalContext, groupByItems []expression.Expression,
+	aggFuncs []*aggregation.AggFuncDesc, schema *expression.Schema, normalized bool) {
 	sortedExplainExpressionList := expression.SortedExplainExpressionList
 	if normalized {
 		sortedExplainExpressionList = func(_ expression.EvalContext, exprs []expression.Expression) []byte {
 			return expression.SortedExplainNormalizedExpressionList(exprs)
 		}
 	}
-
-	builder := &strings.Builder{}
-	if len(p.GroupByItems) > 0 {
+	if len(groupByItems) > 0 {
 		builder.WriteString("group by:")
-		builder.Write(sortedExplainExpressionList(p.SCtx().GetExprCtx().GetEvalCtx(), p.GroupByItems))
+		builder.Write(sortedExplainExpressionList(ctx, groupByItems))
 		builder.WriteString(", ")
 	}
-	for i := 0; i < len(p.AggFuncs); i++ {
+	for i := 0; i < len(aggFuncs); i++ {
 		builder.WriteString("funcs:")
 		var colName string
 		if normalized {
-			colName = p.schema.Columns[i].ExplainNormalizedInfo()
+			colName = schema.Columns[i].ExplainNormalizedInfo()
 		} else {
-			colName = p.schema.Columns[i].ExplainInfo(p.SCtx().GetExprCtx().GetEvalCtx())
+			colName = schema.Columns[i].ExplainInfo(ctx)
 		}
-		builder.WriteString(aggregation.ExplainAggFunc(p.SCtx().GetExprCtx().GetEvalCtx(), p.AggFuncs[i], normalized))
+		builder.WriteString(aggregation.ExplainAggFunc(ctx, aggFuncs[i], normalized))
 		builder.WriteString("->")
 		builder.WriteString(colName)
-		if i+1 < len(p.AggFuncs) {
+		if i+1 < len(aggFuncs) {
 			builder.WriteString(", ")
 		}
 	}
-	if p.TiFlashFineGrainedShuffleStreamCount > 0 {
-		fmt.Fprintf(builder, ", stream_count: %d", p.TiFlashFineGrainedShuffleStreamCount)
-	}
-	return builder.String()
 }
 
 // ExplainNormalizedInfo implements Plan interface.
@@ -931,6 +950,25 @@ func (p *LogicalApply) ExplainInfo() string {
 	return p.LogicalJoin.ExplainInfo()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *LogicalJSONTable) ExplainInfo() string {
+	return explainJSONTable(p.SCtx().GetExprCtx().GetEvalCtx(), p.Expr, p.RootPath)
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainInfo() string {
+	return explainJSONTable(p.SCtx().GetExprCtx().GetEvalCtx(), p.Expr, p.RootPath)
+}
+
+func explainJSONTable(ctx expression.EvalContext, expr expression.Expression, path *JSONTablePath) string {
+	var str strings.Builder
+	str.WriteString("json:")
+	str.WriteString(expr.ExplainInfo(ctx))
+	str.WriteString(", path:")
+	str.WriteString(path.Path.String())
+	return str.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *LogicalTableDual) ExplainInfo() string {
 	var str strings.Builder
@@ -1109,6 +1147,57 @@ func (p *TiKVSingleGather) ExplainInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *TiKVIndexMergeGather) ExplainInfo() string {
+	buffer := bytes.NewBufferString(p.Source.ExplainInfo())
+	if len(p.Path.PartialIndexPaths) > 0 {
+		names := make([]string, 0, len(p.Path.PartialIndexPaths))
+		for _, path := range p.Path.PartialIndexPaths {
+			if path.IsTablePath() {
+				names = append(names, "handle")
+			} else {
+				names = append(names, path.Index.Name.String())
+			}
+		}
+		buffer.WriteString(", index merge:" + strings.Join(names, ","))
+	}
+	return buffer.String()
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalLooseIndexScan) ExplainInfo() string {
+	return p.AccessObject().String() + ", " + p.OperatorInfo(false)
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalLooseIndexScan) ExplainNormalizedInfo() string {
+	return p.AccessObject().NormalizedString() + ", " + p.OperatorInfo(true)
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (p *PhysicalLooseIndexScan) OperatorInfo(normalized bool) string {
+	var buffer strings.Builder
+	buffer.WriteString("prefix:")
+	for i, idxCol := range p.IndexScan.Index.Columns[:p.PrefixLen] {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(idxCol.Name.O)
+	}
+	buffer.WriteString(", ")
+	if normalized {
+		buffer.WriteString("range:[?,?], ")
+	} else if !p.IndexScan.isFullScan() {
+		buffer.WriteString("range:")
+		for _, idxRange := range p.IndexScan.Ranges {
+			buffer.WriteString(idxRange.String())
+			buffer.WriteString(", ")
+		}
+	}
+	explainAggFuncs(&buffer, p.SCtx().GetExprCtx().GetEvalCtx(), p.GroupByItems, p.AggFuncs, p.schema, normalized)
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalMemTable) ExplainInfo() string {
 	accessObject, operatorInfo := p.AccessObject().String(), p.OperatorInfo(false)
@@ -45,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
 	"github.com/pingcap/tidb/pkg/util/hint"
 	"github.com/pingcap/tidb/pkg/util/intest"
 	"github.com/pingcap/tidb/pkg/util/sem"
@@ -54,6 +55,10 @@ import (
 // EvalSubqueryFirstRow evaluates incorrelated subqueries once, and get first row.
 var EvalSubqueryFirstRow func(ctx context.Context, p base.PhysicalPlan, is infoschema.InfoSchema, sctx base.PlanContext) (row []types.Datum, err error)
 
+// CountFullTextDocs counts the rows containing each term in the FULLTEXT index by coprocessor requests,
+// it's implemented in the executor package.
+var CountFullTextDocs func(ctx context.Context, sctx base.PlanContext, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, terms []fulltext.Term) (map[fulltext.Term]int64, error)
+
 // evalAstExprWithPlanCtx evaluates ast expression with plan context.
 // Different with expression.EvalSimpleAst, it uses planner context and is more powerful to build some special expressions
 // like subquery, window function, etc.
@@ -1460,6 +1465,10 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		er.toTable(v)
 	case *ast.ColumnName:
 		er.toColumn(v)
+	case *ast.MatchAgainst:
+		withPlanCtx(func(planCtx *exprRewriterPlanCtx) {
+			er.matchAgainstToExpression(planCtx, v)
+		})
 	case *ast.UnaryOperationExpr:
 		er.unaryOpToExpression(v)
 	case *ast.BinaryOperationExpr:
@@ -1641,6 +1650,141 @@ func (er *expressionRewriter) useCache() bool {
 	return er.sctx.IsUseCache()
 }
 
+// matchAgainstToExpression rewrites `MATCH (col1, col2, ...) AGAINST (expr)` to the `match_against` function,
+// the columns must be exactly the columns of a FULLTEXT index.
+func (er *expressionRewriter) matchAgainstToExpression(planCtx *exprRewriterPlanCtx, v *ast.MatchAgainst) {
+	if v.Modifier.WithQueryExpansion() {
+		er.err = plannererrors.ErrNotSupportedYet.GenWithStackByArgs("WITH QUERY EXPANSION")
+		return
+	}
+	stkLen := len(er.ctxStack)
+	colCnt := len(v.ColumnNames)
+	against, ok := er.ctxStack[stkLen-1].(*expression.Constant)
+	if !ok {
+		er.err = plannererrors.ErrWrongArguments.GenWithStackByArgs("AGAINST")
+		return
+	}
+	// The search string decides the index ranges and the statistics of the FULLTEXT index are collected
+	// when planning, so the plan can't be reused.
+	er.sctx.SetSkipPlanCache("MATCH ... AGAINST is un-cacheable")
+	if expression.MaybeOverOptimized4PlanCache(er.sctx, []expression.Expression{against}) {
+		if er.err = expression.RemoveMutableConst(er.sctx, []expression.Expression{against}); er.err != nil {
+			return
+		}
+	}
+	cols := er.ctxStack[stkLen-1-colCnt : stkLen-1]
+	names := er.ctxNameStk[stkLen-1-colCnt : stkLen-1]
+	tbl, idx := findFullTextIndex(planCtx.builder.is, names)
+	if idx == nil {
+		er.err = plannererrors.ErrFtMatchingKeyNotFound
+		return
+	}
+	for _, col := range cols {
+		if _, ok := col.(*expression.Column); !ok {
+			er.err = plannererrors.ErrFtMatchingKeyNotFound
+			return
+		}
+	}
+	booleanMode := int64(0)
+	if v.Modifier.IsBooleanMode() {
+		booleanMode = 1
+	}
+	againstStr, isNull, err := against.EvalString(er.sctx.GetEvalCtx(), chunk.Row{})
+	if err != nil {
+		er.err = err
+		return
+	}
+	if isNull {
+		er.err = plannererrors.ErrWrongArguments.GenWithStackByArgs("AGAINST")
+		return
+	}
+	query, err := fulltext.ParseQuery(idx.FullTextInfo.ParserType, againstStr, booleanMode != 0)
+	if err != nil {
+		er.err = err
+		return
+	}
+	stats, err := collectFullTextStats(er.ctx, planCtx.builder.ctx, tbl, idx, query.Terms())
+	if err != nil {
+		er.err = err
+		return
+	}
+	args := make([]expression.Expression, 0, expression.MatchAgainstArgs+colCnt)
+	args = append(args,
+		&expression.Constant{Value: types.NewIntDatum(tbl.ID), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		&expression.Constant{Value: types.NewIntDatum(idx.ID), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		against,
+		&expression.Constant{Value: types.NewIntDatum(booleanMode), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		&expression.Constant{Value: types.NewIntDatum(stats.DocCount), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		&expression.Constant{Value: types.NewStringDatum(stats.EncodeDocFreqs(query.Terms())), RetType: types.NewFieldType(mysql.TypeVarString)},
+	)
+	args = append(args, cols...)
+	function, err := er.newFunction(ast.FTSMatchAgainst, types.NewFieldType(mysql.TypeDouble), args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(colCnt + 1)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
+// findFullTextIndex finds the public FULLTEXT index whose columns are exactly the given columns.
+func findFullTextIndex(is infoschema.InfoSchema, names []*types.FieldName) (*model.TableInfo, *model.IndexInfo) {
+	if len(names) == 0 || names[0] == nil || names[0].OrigTblName.L == "" {
+		return nil, nil
+	}
+	dbName, tblName := names[0].DBName, names[0].OrigTblName
+	for _, name := range names[1:] {
+		if name == nil || name.DBName.L != dbName.L || name.OrigTblName.L != tblName.L {
+			return nil, nil
+		}
+	}
+	tbl, err := is.TableByName(dbName, tblName)
+	if err != nil {
+		return nil, nil
+	}
+	tblInfo := tbl.Meta()
+	for _, idx := range tblInfo.Indices {
+		if !idx.IsFullText() || idx.State != model.StatePublic || len(idx.Columns) != len(names) {
+			continue
+		}
+		matched := true
+		for _, name := range names {
+			if idx.FindColumnByName(name.OrigColName.L) == nil {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return tblInfo, idx
+		}
+	}
+	return nil, nil
+}
+
+// collectFullTextStats collects the statistics of the FULLTEXT index to calculate the relevance of `MATCH ... AGAINST`.
+// The number of rows is from the statistics of the table and the modifications of the current transaction, the number
+// of rows containing each term is counted from the index by coprocessor requests.
+func collectFullTextStats(ctx context.Context, sctx base.PlanContext, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, terms []fulltext.Term) (*fulltext.Stats, error) {
+	docFreqs, err := CountFullTextDocs(ctx, sctx, tblInfo, idxInfo, terms)
+	if err != nil {
+		return nil, err
+	}
+	stats := &fulltext.Stats{DocCount: getStatsTable(sctx, tblInfo, tblInfo.ID).RealtimeCount, DocFreq: docFreqs}
+	txnCtx := sctx.GetSessionVars().TxnCtx
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			stats.DocCount += txnCtx.TableDeltaMap[def.ID].Delta
+		}
+	} else {
+		stats.DocCount += txnCtx.TableDeltaMap[tblInfo.ID].Delta
+	}
+	// The statistics of the table may fall behind the index.
+	for _, df := range docFreqs {
+		stats.DocCount = max(stats.DocCount, df)
+	}
+	return stats, nil
+}
+
 func (er *expressionRewriter) rewriteVariable(planCtx *exprRewriterPlanCtx, v *ast.VariableExpr) {
 	stkLen := len(er.ctxStack)
 	name := strings.ToLower(v.Name)
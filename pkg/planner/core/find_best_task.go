@@ -124,6 +124,20 @@ func (p *LogicalShow) FindBestTask(prop *property.PhysicalProperty, planCounter
 	return rt, 1, nil
 }
 
+// FindBestTask implements the LogicalPlan interface.
+func (p *LogicalJSONTable) FindBestTask(prop *property.PhysicalProperty, planCounter *base.PlanCounterTp, opt *optimizetrace.PhysicalOptimizeOp) (base.Task, int64, error) {
+	if !prop.IsSortItemEmpty() || planCounter.Empty() {
+		return invalidTask, 0, nil
+	}
+	jt := PhysicalJSONTable{Expr: p.Expr, RootPath: p.RootPath, AsName: p.AsName}.Init(p.SCtx(), p.StatsInfo(), p.QueryBlockOffset())
+	jt.SetSchema(p.schema)
+	planCounter.Dec(1)
+	utilfuncp.AppendCandidate4PhysicalOptimizeOp(opt, p, jt, prop)
+	rt := &RootTask{}
+	rt.SetPlan(jt)
+	return rt, 1, nil
+}
+
 // FindBestTask implements the LogicalPlan interface.
 func (p *LogicalShowDDLJobs) FindBestTask(prop *property.PhysicalProperty, planCounter *base.PlanCounterTp, _ *optimizetrace.PhysicalOptimizeOp) (base.Task, int64, error) {
 	if !prop.IsSortItemEmpty() || planCounter.Empty() {
@@ -702,6 +716,14 @@ func compareCandidates(sctx base.PlanContext, prop *property.PhysicalProperty, l
 	if isMVIndexPath(lhs.path) || isMVIndexPath(rhs.path) {
 		return 0
 	}
+	// The VECTOR index path is left to the cost model, whether it's better depends on the number of the probes.
+	if isVectorIndexPath(lhs.path) || isVectorIndexPath(rhs.path) {
+		return 0
+	}
+	// The skip scan path is left to the cost model as well, whether it's better depends on the NDV of the leading column.
+	if isSkipScanPath(lhs.path) || isSkipScanPath(rhs.path) {
+		return 0
+	}
 
 	// This rule is empirical but not always correct.
 	// If x's range row count is significantly lower than y's, for example, 1000 times, we think x is better.
@@ -1102,7 +1124,7 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 		if path.IsTablePath() {
 			currentCandidate = ds.getTableCandidate(path, prop)
 		} else {
-			if !(len(path.AccessConds) > 0 || !prop.IsSortItemEmpty() || path.Forced || path.IsSingleScan) {
+			if !(len(path.AccessConds) > 0 || !prop.IsSortItemEmpty() || path.Forced || path.IsSingleScan || isVectorIndexPath(path)) {
 				continue
 			}
 			// We will use index to generate physical plan if any of the following conditions is satisfied:
@@ -1110,6 +1132,7 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 			// 2. We have a non-empty prop to match.
 			// 3. This index is forced to choose.
 			// 4. The needed columns are all covered by index columns(and handleCol).
+			// 5. This path reads the candidates of the nearest neighbors from a VECTOR index.
 			currentCandidate = ds.getIndexCandidate(path, prop)
 		}
 		pruned := false
@@ -2361,6 +2384,21 @@ func (s *LogicalTableScan) GetPhysicalScan(schema *expression.Schema, stats *pro
 	return ts
 }
 
+// GetPhysicalTiFlashScan returns PhysicalTableScan for the logical TableScan
+// running in the MPP tasks of TiFlash.
+func (s *LogicalTableScan) GetPhysicalTiFlashScan(schema *expression.Schema, stats *property.StatsInfo) *PhysicalTableScan {
+	ds := s.Source
+	ts := s.GetPhysicalScan(schema, stats)
+	ts.StoreType = kv.TiFlash
+	ts.PlanPartInfo = PhysPlanPartInfo{
+		PruningConds:   pushDownNot(ds.SCtx().GetExprCtx(), ds.allConds),
+		PartitionNames: ds.partitionNames,
+		Columns:        ds.TblCols,
+		ColumnNames:    ds.names,
+	}
+	return ts
+}
+
 // GetPhysicalIndexScan returns PhysicalIndexScan for the logical IndexScan.
 func (s *LogicalIndexScan) GetPhysicalIndexScan(_ *expression.Schema, stats *property.StatsInfo) *PhysicalIndexScan {
 	ds := s.Source
@@ -2842,6 +2880,10 @@ func (ds *DataSource) getOriginalPhysicalIndexScan(prop *property.PhysicalProper
 		constColsByCond:  path.ConstCols,
 		prop:             prop,
 	}.Init(ds.SCtx(), ds.QueryBlockOffset())
+	if isSkipScanPath(path) {
+		is.SkipScanRanges = path.SkipScanRanges
+		is.skipScanNDV = cardinality.EstimateColumnNDV(ds.statisticTable, path.IdxCols[0].ID)
+	}
 	rowCount := path.CountAfterAccess
 	is.initSchema(append(path.FullIdxCols, ds.commonHandleCols...), !isSingleScan)
 
@@ -2883,9 +2925,7 @@ func (p *LogicalCTE) FindBestTask(prop *property.PhysicalProperty, counter *base
 	if !prop.IsSortItemEmpty() && !prop.CanAddEnforcer {
 		return invalidTask, 1, nil
 	}
-	// The physical plan has been build when derive stats.
-	pcte := PhysicalCTE{SeedPlan: p.cte.seedPartPhysicalPlan, RecurPlan: p.cte.recursivePartPhysicalPlan, CTE: p.cte, cteAsName: p.cteAsName, cteName: p.cteName}.Init(p.SCtx(), p.StatsInfo())
-	pcte.SetSchema(p.schema)
+	pcte := p.GetPhysicalCTE(p.schema, p.StatsInfo())
 	if prop.IsFlashProp() && prop.CTEProducerStatus == property.AllCTECanMpp {
 		pcte.readerReceiver = PhysicalExchangeReceiver{IsCTEReader: true}.Init(p.SCtx(), p.StatsInfo())
 		if prop.MPPPartitionTp != property.AnyType {
@@ -2909,6 +2949,14 @@ func (p *LogicalCTE) FindBestTask(prop *property.PhysicalProperty, counter *base
 	return t, 1, nil
 }
 
+// GetPhysicalCTE returns PhysicalCTE for the logical CTE.
+func (p *LogicalCTE) GetPhysicalCTE(schema *expression.Schema, stats *property.StatsInfo) *PhysicalCTE {
+	// The physical plan has been build when derive stats.
+	pcte := PhysicalCTE{SeedPlan: p.cte.seedPartPhysicalPlan, RecurPlan: p.cte.recursivePartPhysicalPlan, CTE: p.cte, cteAsName: p.cteAsName, cteName: p.cteName}.Init(p.SCtx(), stats)
+	pcte.SetSchema(schema)
+	return pcte
+}
+
 // FindBestTask implements the LogicalPlan interface.
 func (p *LogicalCTETable) FindBestTask(prop *property.PhysicalProperty, _ *base.PlanCounterTp, _ *optimizetrace.PhysicalOptimizeOp) (t base.Task, cntPlan int64, err error) {
 	if !prop.IsSortItemEmpty() {
@@ -59,7 +59,7 @@ type FlatPhysicalPlan struct {
 // depth-first traversal plus some special rule for some operators.
 type FlatPlanTree []*FlatOperator
 
-// GetSelectPlan skips Insert, Delete, and Update at the beginning of the FlatPlanTree and the foreign key check/cascade plan at the end of the FlatPlanTree.
+// GetSelectPlan skips Insert, Delete, Update, and Merge at the beginning of the FlatPlanTree and the foreign key check/cascade plan at the end of the FlatPlanTree.
 // Note:
 //
 //	It returns a reference to the original FlatPlanTree, please avoid modifying the returned value.
@@ -71,7 +71,7 @@ func (e FlatPlanTree) GetSelectPlan() (FlatPlanTree, int) {
 	hasDML := false
 	for i, op := range e {
 		switch op.Origin.(type) {
-		case *Insert, *Delete, *Update:
+		case *Insert, *Delete, *Update, *Merge:
 			hasDML = true
 		default:
 			if hasDML {
@@ -412,6 +412,14 @@ func (f *FlatPhysicalPlan) flattenRecursively(p base.Plan, info *operatorCtx, ta
 			childIdxs = append(childIdxs, childIdx)
 		}
 		target, childIdxs = f.flattenForeignKeyChecksAndCascadesMap(childCtx, target, childIdxs, plan.FKChecks, plan.FKCascades)
+	case *Merge:
+		if plan.SelectPlan != nil {
+			childCtx.isRoot = true
+			childCtx.label = Empty
+			childCtx.isLastChild = true
+			target, childIdx = f.flattenRecursively(plan.SelectPlan, childCtx, target)
+			childIdxs = append(childIdxs, childIdx)
+		}
 	case *Execute:
 		f.InExecute = true
 		if plan.Plan != nil {
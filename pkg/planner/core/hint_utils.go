@@ -91,6 +91,12 @@ func extractTableAsName(p base.PhysicalPlan) (*model.CIStr, *model.CIStr) {
 			return &is.DBName, is.TableAsName
 		}
 		return &is.DBName, &is.Table.Name
+	case *PhysicalLooseIndexScan:
+		is := x.IndexScan
+		if is.TableAsName.L != "" {
+			return &is.DBName, is.TableAsName
+		}
+		return &is.DBName, &is.Table.Name
 	case *PhysicalSort, *PhysicalSelection, *PhysicalUnionScan, *PhysicalProjection:
 		return extractTableAsName(p.Children()[0])
 	}
@@ -200,6 +206,14 @@ func genHintsFromSingle(p base.PhysicalPlan, nodeType h.NodeType, storeType kv.S
 			Tables:   []ast.HintTable{{DBName: index.DBName, TableName: getTableName(index.Table.Name, index.TableAsName)}},
 			Indexes:  []model.CIStr{index.Index.Name},
 		})
+		if index.SkipScanRanges != nil {
+			res = append(res, &ast.TableOptimizerHint{
+				QBName:   qbName,
+				HintName: model.NewCIStr(h.HintSkipScan),
+				Tables:   []ast.HintTable{{DBName: index.DBName, TableName: getTableName(index.Table.Name, index.TableAsName)}},
+				Indexes:  []model.CIStr{index.Index.Name},
+			})
+		}
 	case *PhysicalIndexReader:
 		index := pp.IndexPlans[0].(*PhysicalIndexScan)
 		res = append(res, &ast.TableOptimizerHint{
@@ -218,6 +232,22 @@ func genHintsFromSingle(p base.PhysicalPlan, nodeType h.NodeType, storeType kv.S
 			Tables:   []ast.HintTable{{DBName: index.DBName, TableName: getTableName(index.Table.Name, index.TableAsName)}},
 			Indexes:  []model.CIStr{index.Index.Name},
 		})
+		if index.SkipScanRanges != nil {
+			res = append(res, &ast.TableOptimizerHint{
+				QBName:   qbName,
+				HintName: model.NewCIStr(h.HintSkipScan),
+				Tables:   []ast.HintTable{{DBName: index.DBName, TableName: getTableName(index.Table.Name, index.TableAsName)}},
+				Indexes:  []model.CIStr{index.Index.Name},
+			})
+		}
+	case *PhysicalLooseIndexScan:
+		index := pp.IndexScan
+		res = append(res, &ast.TableOptimizerHint{
+			QBName:   qbName,
+			HintName: model.NewCIStr(h.HintUseIndex),
+			Tables:   []ast.HintTable{{DBName: index.DBName, TableName: getTableName(index.Table.Name, index.TableAsName)}},
+			Indexes:  []model.CIStr{index.Index.Name},
+		})
 	case *PhysicalIndexMergeReader:
 		indexs := make([]model.CIStr, 0, 2)
 		var tableName model.CIStr
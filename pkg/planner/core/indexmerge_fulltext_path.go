@@ -0,0 +1,141 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// fullTextTermSelectivity is the estimated selectivity of a term of the FULLTEXT index,
+// since there are no statistics of the tokens.
+const fullTextTermSelectivity = 0.01
+
+// generateIndexMerge4FullTextIndex generates a union IndexMerge path for `MATCH ... AGAINST` on a FULLTEXT index.
+// Each partial path reads the rows containing one of the access terms of the search string, e.g.
+// `MATCH (title) AGAINST ('+mysql -oracle tutorial*' IN BOOLEAN MODE)` reads the rows containing
+// the token `mysql` or the tokens starting with `tutorial`. Since the rows read from the index are a
+// superset of the matching rows, the `MATCH ... AGAINST` condition is kept as a table filter.
+func (ds *DataSource) generateIndexMerge4FullTextIndex(filters []expression.Expression) {
+	for _, filter := range filters {
+		sf, ok := filter.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.FTSMatchAgainst {
+			continue
+		}
+		partialPaths := ds.buildPartialPaths4FullTextIndex(sf)
+		if len(partialPaths) == 0 {
+			continue
+		}
+		indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+		indexMergePath.TableFilters = filters
+		for _, path := range partialPaths {
+			indexMergePath.CountAfterAccess += path.CountAfterAccess
+		}
+		indexMergePath.CountAfterAccess = min(indexMergePath.CountAfterAccess, ds.tableStats.RowCount)
+		ds.possibleAccessPaths = append(ds.possibleAccessPaths, indexMergePath)
+		return
+	}
+}
+
+// buildPartialPaths4FullTextIndex builds a partial path for each access term of the `match_against` function.
+func (ds *DataSource) buildPartialPaths4FullTextIndex(sf *expression.ScalarFunction) []*util.AccessPath {
+	args := sf.GetArgs()
+	consts := make([]*expression.Constant, 0, expression.MatchAgainstArgs)
+	for _, arg := range args[:expression.MatchAgainstArgs] {
+		c, ok := arg.(*expression.Constant)
+		if !ok || c.ParamMarker != nil || c.DeferredExpr != nil {
+			return nil
+		}
+		consts = append(consts, c)
+	}
+	if consts[0].Value.GetInt64() != ds.tableInfo.ID {
+		return nil
+	}
+	var idx *model.IndexInfo
+	for _, index := range ds.tableInfo.Indices {
+		if index.ID == consts[1].Value.GetInt64() && index.IsFullText() && index.State == model.StatePublic && !index.Invisible {
+			idx = index
+			break
+		}
+	}
+	if idx == nil {
+		return nil
+	}
+	against, err := consts[2].Value.ToString()
+	if err != nil {
+		return nil
+	}
+	query, err := fulltext.ParseQuery(idx.FullTextInfo.ParserType, against, consts[3].Value.GetInt64() != 0)
+	if err != nil {
+		return nil
+	}
+	terms := query.AccessTerms()
+	if len(terms) == 0 {
+		return nil
+	}
+	var idxCol *expression.Column
+	colID := ds.tableInfo.Columns[idx.Columns[0].Offset].ID
+	for _, col := range ds.TblCols {
+		if col.ID == colID {
+			idxCol = col
+			break
+		}
+	}
+	if idxCol == nil {
+		return nil
+	}
+
+	// Every key of the FULLTEXT index has only one token, so the partial index scan reads
+	// the index as if it has only the first column.
+	partialIdx := idx.Clone()
+	partialIdx.Columns = partialIdx.Columns[:1]
+	countPerTerm := max(1, ds.tableStats.RowCount*fullTextTermSelectivity)
+	partialPaths := make([]*util.AccessPath, 0, len(terms))
+	for _, term := range terms {
+		partialPaths = append(partialPaths, &util.AccessPath{
+			Index:            partialIdx,
+			IdxCols:          []*expression.Column{idxCol},
+			IdxColLens:       []int{types.UnspecifiedLength},
+			FullIdxCols:      []*expression.Column{idxCol},
+			FullIdxColLens:   []int{types.UnspecifiedLength},
+			Ranges:           []*ranger.Range{BuildFullTextTermRange(term)},
+			CountAfterAccess: countPerTerm,
+			CountAfterIndex:  countPerTerm,
+		})
+	}
+	return partialPaths
+}
+
+// BuildFullTextTermRange builds the range of the tokens matched by the term.
+func BuildFullTextTermRange(term fulltext.Term) *ranger.Range {
+	low := types.NewBytesDatum([]byte(term.Text))
+	ran := &ranger.Range{
+		LowVal:    []types.Datum{low},
+		HighVal:   []types.Datum{low},
+		Collators: []collate.Collator{collate.GetBinaryCollator()},
+	}
+	if term.Prefix {
+		ran.HighVal = []types.Datum{types.NewBytesDatum(kv.Key(term.Text).PrefixNext())}
+		ran.HighExclude = true
+	}
+	return ran
+}
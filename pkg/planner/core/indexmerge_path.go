@@ -87,6 +87,7 @@ func (ds *DataSource) generateIndexMergePath() error {
 	if err := ds.generateIndexMerge4MVIndex(regularPathCount, indexMergeConds); err != nil {
 		return err
 	}
+	ds.generateIndexMerge4FullTextIndex(indexMergeConds)
 	oldIndexMergeCount := len(ds.possibleAccessPaths)
 	if err := ds.generateIndexMerge4ComposedIndex(regularPathCount, indexMergeConds); err != nil {
 		return err
@@ -49,6 +49,18 @@ func (sg TiKVSingleGather) Init(ctx base.PlanContext, offset int) *TiKVSingleGat
 	return &sg
 }
 
+// Init initializes TiKVIndexMergeGather.
+func (g TiKVIndexMergeGather) Init(ctx base.PlanContext, offset int) *TiKVIndexMergeGather {
+	g.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTiKVIndexMergeGather, &g, offset)
+	return &g
+}
+
+// Init initializes MPPGather.
+func (g MPPGather) Init(ctx base.PlanContext, offset int) *MPPGather {
+	g.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeMPPGather, &g, offset)
+	return &g
+}
+
 // Init initializes LogicalTableScan.
 func (ts LogicalTableScan) Init(ctx base.PlanContext, offset int) *LogicalTableScan {
 	ts.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTableScan, &ts, offset)
@@ -246,6 +258,12 @@ func (p Delete) Init(ctx base.PlanContext) *Delete {
 	return &p
 }
 
+// Init initializes Merge.
+func (p Merge) Init(ctx base.PlanContext) *Merge {
+	p.Plan = baseimpl.NewBasePlan(ctx, plancodec.TypeMerge, 0)
+	return &p
+}
+
 // Init initializes Insert.
 func (p Insert) Init(ctx base.PlanContext) *Insert {
 	p.Plan = baseimpl.NewBasePlan(ctx, plancodec.TypeInsert, 0)
@@ -276,6 +294,19 @@ func (p LogicalShowDDLJobs) Init(ctx base.PlanContext) *LogicalShowDDLJobs {
 	return &p
 }
 
+// Init initializes LogicalJSONTable.
+func (p LogicalJSONTable) Init(ctx base.PlanContext, offset int) *LogicalJSONTable {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeJSONTable, &p, offset)
+	return &p
+}
+
+// Init initializes PhysicalJSONTable.
+func (p PhysicalJSONTable) Init(ctx base.PlanContext, stats *property.StatsInfo, offset int) *PhysicalJSONTable {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeJSONTable, &p, offset)
+	p.SetStats(stats)
+	return &p
+}
+
 // Init initializes PhysicalShow.
 func (p PhysicalShow) Init(ctx base.PlanContext) *PhysicalShow {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeShow, &p, 0)
@@ -318,6 +349,14 @@ func (p PhysicalIndexScan) Init(ctx base.PlanContext, offset int) *PhysicalIndex
 	return &p
 }
 
+// Init initializes PhysicalLooseIndexScan.
+func (p PhysicalLooseIndexScan) Init(ctx base.PlanContext, stats *property.StatsInfo, offset int, props ...*property.PhysicalProperty) *PhysicalLooseIndexScan {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeLooseIndexScan, &p, offset)
+	p.childrenReqProps = props
+	p.SetStats(stats)
+	return &p
+}
+
 // Init initializes LogicalMemTable.
 func (p LogicalMemTable) Init(ctx base.PlanContext, offset int) *LogicalMemTable {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeMemTableScan, &p, offset)
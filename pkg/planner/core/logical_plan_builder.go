@@ -446,6 +446,8 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, v, x.AsName)
 		default:
 			err = plannererrors.ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -942,10 +944,15 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (base.L
 		return nil, err
 	}
 
-	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right, false)
+	rightPlan, err := b.buildJoinRightSide(ctx, joinNode, leftPlan)
 	if err != nil {
 		return nil, err
 	}
+	// The right side references the columns of the left side, the join is built as an Apply.
+	lateralCorCols := coreusage.ExtractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())
+	if len(lateralCorCols) > 0 && joinNode.Tp == ast.RightJoin {
+		return nil, plannererrors.ErrTFForbiddenJoinType.GenWithStackByArgs(joinNode.Right.(*ast.TableSource).AsName.O)
+	}
 
 	// The recursive part in CTE must not be on the right side of a LEFT JOIN.
 	if lc, ok := rightPlan.(*LogicalCTETable); ok && joinNode.Tp == ast.LeftJoin {
@@ -1056,7 +1063,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (base.L
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(b.buildLateralApply(joinPlan, lateralCorCols))
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -1066,7 +1073,173 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (base.L
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return b.buildLateralApply(joinPlan, lateralCorCols), nil
+}
+
+// isLateralTableSource checks whether the table source can reference the tables before it in the FROM clause.
+func isLateralTableSource(node ast.ResultSetNode) bool {
+	ts, ok := node.(*ast.TableSource)
+	if !ok {
+		return false
+	}
+	if ts.Lateral {
+		return true
+	}
+	_, ok = ts.Source.(*ast.JSONTable)
+	return ok
+}
+
+// buildJoinRightSide builds the right side of the join. If the right side is a lateral table source,
+// the left side is used as its outer plan, so the columns of the left side are resolved as correlated columns.
+func (b *PlanBuilder) buildJoinRightSide(ctx context.Context, joinNode *ast.Join, leftPlan base.LogicalPlan) (base.LogicalPlan, error) {
+	if !isLateralTableSource(joinNode.Right) {
+		return b.buildResultSetNode(ctx, joinNode.Right, false)
+	}
+	b.outerSchemas = append(b.outerSchemas, leftPlan.Schema())
+	b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	b.outerBlockExpand = append(b.outerBlockExpand, b.currentBlockExpand)
+	defer func() {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+		b.currentBlockExpand = b.outerBlockExpand[len(b.outerBlockExpand)-1]
+		b.outerBlockExpand = b.outerBlockExpand[0 : len(b.outerBlockExpand)-1]
+	}()
+	return b.buildResultSetNode(ctx, joinNode.Right, false)
+}
+
+// buildLateralApply turns the join into an Apply if its right side references the columns of its left side.
+func (b *PlanBuilder) buildLateralApply(join *LogicalJoin, corCols []*expression.CorrelatedColumn) base.LogicalPlan {
+	if len(corCols) == 0 {
+		return join
+	}
+	b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+	setIsInApplyForCTE(join.children[1], join.Schema())
+	ap := &LogicalApply{LogicalJoin: *join, CorCols: corCols}
+	ap.SetTP(plancodec.TypeApply)
+	ap.self = ap
+	return ap
+}
+
+// buildJSONTable builds the plan of JSON_TABLE. The columns of the tables before JSON_TABLE in the
+// FROM clause are resolved as correlated columns, see buildJoinRightSide.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, jt *ast.JSONTable, asName model.CIStr) (base.LogicalPlan, error) {
+	dual := LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
+	dual.SetSchema(expression.NewSchema())
+	expr, np, err := b.rewrite(ctx, jt.Expr, dual, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != dual {
+		return nil, plannererrors.ErrNotSupportedYet.GenWithStackByArgs("subqueries in JSON_TABLE")
+	}
+	expr = expression.BuildCastFunction(b.ctx.GetExprCtx(), expr, types.NewFieldType(mysql.TypeJSON))
+
+	p := LogicalJSONTable{Expr: expr, AsName: asName}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	names := make(types.NameSlice, 0, len(jt.Columns))
+	p.RootPath, err = b.buildJSONTablePath(jt.Path, jt.Columns, asName, schema, &names)
+	if err != nil {
+		return nil, err
+	}
+	p.SetSchema(schema)
+	p.names = names
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildJSONTablePath builds the row path of JSON_TABLE, the columns of the path and its nested
+// paths are appended to the schema in the order they are defined.
+func (b *PlanBuilder) buildJSONTablePath(path string, columns []*ast.JSONTableColumn, asName model.CIStr,
+	schema *expression.Schema, names *types.NameSlice) (*JSONTablePath, error) {
+	pathExpr, err := types.ParseJSONPathExpr(path)
+	if err != nil {
+		return nil, err
+	}
+	jp := &JSONTablePath{Path: pathExpr}
+	for _, col := range columns {
+		if col.Tp == ast.JSONTableColumnNested {
+			nested, err := b.buildJSONTablePath(col.Path, col.Columns, asName, schema, names)
+			if err != nil {
+				return nil, err
+			}
+			jp.Nested = append(jp.Nested, nested)
+			continue
+		}
+		jc := &JSONTableColumn{Tp: col.Tp, Name: col.Name, Offset: schema.Len()}
+		var ft *types.FieldType
+		if col.Tp == ast.JSONTableColumnOrdinality {
+			ft = types.NewFieldType(mysql.TypeLong)
+			ft.AddFlag(mysql.UnsignedFlag)
+			completeJSONTableColumnType(ft)
+		} else {
+			if jc.Path, err = types.ParseJSONPathExpr(col.Path); err != nil {
+				return nil, err
+			}
+			ft = col.Type.Clone()
+			completeJSONTableColumnType(ft)
+			jc.OnEmpty = buildJSONTableOnResponse(col.OnEmpty)
+			jc.OnError = buildJSONTableOnResponse(col.OnError)
+		}
+		jp.Columns = append(jp.Columns, jc)
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  ft,
+		})
+		*names = append(*names, &types.FieldName{
+			TblName:     asName,
+			OrigTblName: asName,
+			ColName:     col.Name,
+			OrigColName: col.Name,
+		})
+	}
+	return jp, nil
+}
+
+// completeJSONTableColumnType fills the charset, collation, length and decimal of the column type
+// which are not specified in the column definition of JSON_TABLE.
+func completeJSONTableColumnType(ft *types.FieldType) {
+	switch ft.GetType() {
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeTinyBlob, mysql.TypeBlob,
+		mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeEnum, mysql.TypeSet:
+		if ft.GetCharset() == "" {
+			cs, co := charset.GetDefaultCharsetAndCollate()
+			ft.SetCharset(cs)
+			ft.SetCollate(co)
+		} else if ft.GetCollate() == "" {
+			co, err := charset.GetDefaultCollation(ft.GetCharset())
+			if err == nil {
+				ft.SetCollate(co)
+			}
+		}
+	default:
+		ft.SetCharset(charset.CharsetBin)
+		ft.SetCollate(charset.CollationBin)
+	}
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.GetType())
+	if ft.GetFlen() == types.UnspecifiedLength {
+		ft.SetFlen(defaultFlen)
+	}
+	if ft.GetDecimal() == types.UnspecifiedLength {
+		ft.SetDecimal(defaultDecimal)
+	}
+}
+
+// buildJSONTableOnResponse builds the ON EMPTY or ON ERROR response of JSON_TABLE columns, which is NULL if
+// it's not specified. The text of `DEFAULT json_string` is parsed as JSON, it's used as a JSON string if it's
+// not a valid JSON text.
+func buildJSONTableOnResponse(resp *ast.JSONTableOnResponse) JSONTableOnResponse {
+	if resp == nil {
+		return JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}
+	}
+	r := JSONTableOnResponse{Tp: resp.Tp}
+	if resp.Tp == ast.JSONTableOnResponseDefault {
+		bj, err := types.ParseBinaryJSONFromString(resp.Default)
+		if err != nil {
+			bj = types.CreateBinaryJSON(resp.Default)
+		}
+		r.Default = bj
+	}
+	return r
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -4024,6 +4197,10 @@ func (b *PlanBuilder) TableHints() *h.PlanHints {
 }
 
 func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p base.LogicalPlan, err error) {
+	if rewritten := b.tryRewriteToMaterializedView(ctx, sel); rewritten != nil {
+		rewritten.QueryBlockOffset = sel.QueryBlockOffset
+		return b.buildSelect(ctx, rewritten)
+	}
 	b.pushSelectOffset(sel.QueryBlockOffset)
 	b.pushTableHints(sel.TableHints, sel.QueryBlockOffset)
 	defer func() {
@@ -4188,7 +4365,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p b
 	}
 	l := sel.LockInfo
 	if l != nil && l.LockType != ast.SelectLockNone {
-		if l.LockType == ast.SelectLockForShare && noopFuncsMode != variable.OnInt {
+		if (l.LockType == ast.SelectLockForShare || l.LockType == ast.SelectLockForShareSkipLocked) && noopFuncsMode != variable.OnInt {
 			err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("LOCK IN SHARE MODE")
 			if noopFuncsMode == variable.OffInt {
 				return nil, err
@@ -5954,7 +6131,7 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		foundListItem := false
 		for _, tl := range tableList {
 			if (tl.Schema.L == "" || tl.Schema.L == name.DBName.L) && (tl.Name.L == name.TblName.L) {
-				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() {
+				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() || b.isReadOnlyMaterializedViewTable(tl.TableInfo) {
 					return nil, nil, false, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(name.TblName.O, "UPDATE")
 				}
 				foundListItem = true
@@ -6276,6 +6453,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (base
 			if tn.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", tn.Name.O)
 			}
+			if b.isReadOnlyMaterializedViewTable(tn.TableInfo) {
+				return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+			}
 			if sessionVars.User != nil {
 				authErr = plannererrors.ErrTableaccessDenied.FastGenByArgs("DELETE", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tb.Name.L)
 			}
@@ -6294,6 +6474,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (base
 			if v.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", v.Name.O)
 			}
+			if b.isReadOnlyMaterializedViewTable(v.TableInfo) {
+				return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(v.Name.O, "DELETE")
+			}
 			dbName := v.Schema.L
 			if dbName == "" {
 				dbName = b.ctx.GetSessionVars().CurrentDB
@@ -18,11 +18,13 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"slices"
 	"unsafe"
 
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/expression/aggregation"
 	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/parser/model"
@@ -44,6 +46,7 @@ import (
 	h "github.com/pingcap/tidb/pkg/util/hint"
 	"github.com/pingcap/tidb/pkg/util/intset"
 	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/plancodec"
 	"github.com/pingcap/tidb/pkg/util/ranger"
 	"github.com/pingcap/tidb/pkg/util/size"
 	"github.com/pingcap/tipb/go-tipb"
@@ -60,6 +63,8 @@ var (
 	_ base.LogicalPlan = &LogicalTableDual{}
 	_ base.LogicalPlan = &DataSource{}
 	_ base.LogicalPlan = &TiKVSingleGather{}
+	_ base.LogicalPlan = &TiKVIndexMergeGather{}
+	_ base.LogicalPlan = &MPPGather{}
 	_ base.LogicalPlan = &LogicalTableScan{}
 	_ base.LogicalPlan = &LogicalIndexScan{}
 	_ base.LogicalPlan = &LogicalUnionAll{}
@@ -500,6 +505,20 @@ func (p *LogicalJoin) AppendJoinConds(eq []*expression.ScalarFunction, left, rig
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+// CanBeReordered checks whether the join can be reordered together with its adjacent joins, that is,
+// it's an inner join which is not generated by join reorder and is not restricted by any join hint.
+func (p *LogicalJoin) CanBeReordered() bool {
+	if p.SCtx().GetSessionVars().StmtCtx.StraightJoinOrder {
+		return false
+	}
+	return p.JoinType == InnerJoin && !p.reordered && !p.StraightJoin && !p.preferJoinOrder && p.preferJoinType == 0
+}
+
+// SetReordered marks the join as generated by join reorder.
+func (p *LogicalJoin) SetReordered() {
+	p.reordered = true
+}
+
 // ExtractCorrelatedCols implements LogicalPlan interface.
 func (p *LogicalJoin) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	corCols := make([]*expression.CorrelatedColumn, 0, len(p.EqualConditions)+len(p.LeftConditions)+len(p.RightConditions)+len(p.OtherConditions))
@@ -1491,6 +1510,9 @@ type DataSource struct {
 	// It's calculated after we generated the access paths and estimated row count for them, and before entering findBestTask.
 	// It considers CountAfterIndex for index paths and CountAfterAccess for table paths and index merge paths.
 	accessPathMinSelectivity float64
+
+	// vectorSearch is the nearest neighbor search pushed down by TopN, which may be accelerated by a VECTOR index.
+	vectorSearch *vectorSearch
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -1512,6 +1534,40 @@ type TiKVSingleGather struct {
 	// PhysicalTableReader or PhysicalIndexReader.
 	IsIndexGather bool
 	Index         *model.IndexInfo
+	// PruningConds are the filters pushed down through this gather, they are
+	// used to prune the partitions read by the generated reader.
+	PruningConds []expression.Expression
+}
+
+// Shallow shallow copies a TiKVSingleGather struct.
+func (sg *TiKVSingleGather) Shallow() *TiKVSingleGather {
+	gather := *sg
+	return gather.Init(sg.SCtx(), sg.QueryBlockOffset())
+}
+
+// TiKVIndexMergeGather is a leaf logical operator of TiDB layer to gather
+// tuples from TiKV regions by an index merge path. It consumes all of the
+// filters used to build the path, so it has no child in TiKV layer.
+type TiKVIndexMergeGather struct {
+	logicalSchemaProducer
+	// Source is a copy of the DataSource whose filters are the consumed ones.
+	Source *DataSource
+	Path   *util.AccessPath
+}
+
+// MPPGather is a leaf logical operator of TiDB layer to gather tuples
+// from the MPP tasks of TiFlash.
+type MPPGather struct {
+	logicalSchemaProducer
+	// PruningConds are the filters pushed down through this gather, they are
+	// used to prune the partitions read by the table scans of the MPP tasks.
+	PruningConds []expression.Expression
+}
+
+// Shallow shallow copies a MPPGather struct.
+func (g *MPPGather) Shallow() *MPPGather {
+	gather := *g
+	return gather.Init(g.SCtx(), g.QueryBlockOffset())
 }
 
 // LogicalTableScan is the logical table scan operator for TiKV.
@@ -1625,6 +1681,64 @@ func (ds *DataSource) Convert2Gathers() (gathers []base.LogicalPlan) {
 	return gathers
 }
 
+// Convert2IndexMergeGathers builds logical TiKVIndexMergeGathers from the index
+// merge paths of the DataSource filtered by conds, which must be able to be pushed
+// down to TiKV. forced reports whether the paths are required by the
+// USE_INDEX_MERGE hints.
+func (ds *DataSource) Convert2IndexMergeGathers(conds []expression.Expression) (gathers []base.LogicalPlan, forced bool, err error) {
+	// The paths are derived on a copy of the DataSource, so the DataSource shared by
+	// other gathers is unchanged.
+	newDS := *ds
+	newDS.baseLogicalPlan = newBaseLogicalPlan(ds.SCtx(), plancodec.TypeTableScan, &newDS, ds.QueryBlockOffset())
+	newDS.SetID(ds.ID())
+	newDS.allConds = append(slices.Clip(ds.allConds), conds...)
+	newDS.pushedDownConds = append(slices.Clip(ds.pushedDownConds), conds...)
+	newDS.possibleAccessPaths = make([]*util.AccessPath, 0, len(ds.possibleAccessPaths))
+	for _, path := range ds.possibleAccessPaths {
+		if path.PartialIndexPaths == nil && len(path.PartialAlternativeIndexPaths) == 0 {
+			newDS.possibleAccessPaths = append(newDS.possibleAccessPaths, path.Clone())
+		}
+	}
+	if _, err = newDS.DeriveStats(nil, newDS.Schema(), nil, nil); err != nil {
+		return nil, false, err
+	}
+	for _, path := range newDS.possibleAccessPaths {
+		if path.PartialIndexPaths == nil && len(path.PartialAlternativeIndexPaths) == 0 {
+			continue
+		}
+		gather := TiKVIndexMergeGather{Source: &newDS, Path: path}.Init(ds.SCtx(), ds.QueryBlockOffset())
+		gather.SetSchema(ds.Schema())
+		gather.SetStats(newDS.StatsInfo())
+		gathers = append(gathers, gather)
+	}
+	return gathers, len(gathers) > 0 && len(newDS.indexMergeHints) > 0, nil
+}
+
+// Convert2MPPGather builds a logical MPPGather from DataSource. It returns nil
+// if the table can't be read by the MPP tasks of TiFlash.
+func (ds *DataSource) Convert2MPPGather() base.LogicalPlan {
+	if !ds.SCtx().GetSessionVars().IsMPPAllowed() {
+		return nil
+	}
+	if !slices.ContainsFunc(ds.possibleAccessPaths, func(path *util.AccessPath) bool {
+		return path.StoreType == kv.TiFlash
+	}) {
+		return nil
+	}
+	for _, col := range ds.schema.Columns {
+		if col.VirtualExpr != nil {
+			ds.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because column `" + col.OrigName + "` is a virtual column which is not supported now.")
+			return nil
+		}
+	}
+	ts := LogicalTableScan{Source: ds, HandleCols: ds.handleCols}.Init(ds.SCtx(), ds.QueryBlockOffset())
+	ts.SetSchema(ds.Schema())
+	g := MPPGather{}.Init(ds.SCtx(), ds.QueryBlockOffset())
+	g.SetSchema(ds.Schema())
+	g.SetChildren(ts)
+	return g
+}
+
 func detachCondAndBuildRangeForPath(
 	sctx base.PlanContext,
 	path *util.AccessPath,
@@ -2195,6 +2309,7 @@ type ShowContents struct {
 	Table             *ast.TableName  // Used for showing columns.
 	Partition         model.CIStr     // Use for showing partition
 	Column            *ast.ColumnName // Used for `desc table column`.
+	Procedure         *ast.TableName  // Used for showing procedure.
 	IndexName         model.CIStr
 	ResourceGroupName string               // Used for showing resource group
 	Flag              int                  // Some flag parsed from sql, such as FULL.
@@ -2240,6 +2355,49 @@ type LogicalShowDDLJobs struct {
 	JobNumber int64
 }
 
+// LogicalJSONTable is the plan of JSON_TABLE, it generates rows from a JSON document.
+// The document may reference the tables before JSON_TABLE in the FROM clause, then
+// the plan is the inner side of an Apply.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	Expr     expression.Expression
+	RootPath *JSONTablePath
+	AsName   model.CIStr
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
+// JSONTablePath is a row path of JSON_TABLE. A row is generated for each value matched by
+// the path, together with the rows of its nested paths.
+type JSONTablePath struct {
+	Path    types.JSONPathExpression
+	Columns []*JSONTableColumn
+	Nested  []*JSONTablePath
+}
+
+// JSONTableColumn is a column of JSON_TABLE.
+type JSONTableColumn struct {
+	Tp   ast.JSONTableColumnType
+	Name model.CIStr
+	// Offset is the offset of the column in the schema of JSON_TABLE.
+	Offset int
+	// Path is the path of the value in the row, it's not used by the ordinality column.
+	Path    types.JSONPathExpression
+	OnEmpty JSONTableOnResponse
+	OnError JSONTableOnResponse
+}
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR response of a JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp ast.JSONTableOnResponseType
+	// Default is the value of `DEFAULT json_string`.
+	Default types.BinaryJSON
+}
+
 // CTEClass holds the information and plan for a CTE. Most of the fields in this struct are the same as cteInfo.
 // But the cteInfo is used when building the plan, and CTEClass is used also for building the executor.
 type CTEClass struct {
@@ -0,0 +1,166 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/expression/aggregation"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/property"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// getLooseIndexScans returns the loose index scans computing the aggregation grouped by the leading columns of an
+// index, they compete with the hash and stream aggregations by the cost. The child is required to read the index in
+// the order of the group by columns by a single read cop task, which decides the index and the ranges to seek. The
+// number of the seeks is decided by the NDV of the prefix, which is the estimated row count of the aggregation.
+func (la *LogicalAggregation) getLooseIndexScans(prop *property.PhysicalProperty) []base.PhysicalPlan {
+	if prop.TaskTp != property.RootTaskType || la.PreferAggToCop || len(la.GroupByItems) == 0 {
+		return nil
+	}
+	if _, ok := la.children[0].(*DataSource); !ok {
+		return nil
+	}
+	all, desc := prop.AllSameOrder()
+	if !all || desc {
+		return nil
+	}
+	groupByCols := la.GetGroupByCols()
+	if len(groupByCols) != len(la.GroupByItems) {
+		return nil
+	}
+	looseScans := make([]base.PhysicalPlan, 0, len(la.possibleProperties))
+	for _, possibleChildProperty := range la.possibleProperties {
+		childProp := &property.PhysicalProperty{
+			TaskTp:      property.CopSingleReadTaskType,
+			ExpectedCnt: math.Max(prop.ExpectedCnt*la.inputCount/la.StatsInfo().RowCount, prop.ExpectedCnt),
+			SortItems:   property.SortItemsFromCols(possibleChildProperty[:len(groupByCols)], false),
+		}
+		if !prop.IsPrefix(childProp) {
+			continue
+		}
+		ls := PhysicalLooseIndexScan{
+			GroupByItems: la.GroupByItems,
+			AggFuncs:     la.AggFuncs,
+		}.Init(la.SCtx(), la.StatsInfo().ScaleByExpectCnt(prop.ExpectedCnt), la.QueryBlockOffset(), childProp)
+		ls.SetSchema(la.schema.Clone())
+		looseScans = append(looseScans, ls)
+	}
+	return looseScans
+}
+
+// Attach2Task implements the PhysicalPlan interface. The loose index scan seeks the index read by the child task
+// instead of reading all the entries, the task is invalid if the child reads the index by anything else than a bare
+// index scan, or the aggregation can't be computed by seeking the distinct prefixes.
+func (p *PhysicalLooseIndexScan) Attach2Task(tasks ...base.Task) base.Task {
+	t, ok := tasks[0].(*CopTask)
+	if !ok || t.tablePlan != nil || len(t.idxMergePartPlans) > 0 || len(t.rootTaskConds) > 0 {
+		return invalidTask
+	}
+	is, ok := t.indexPlan.(*PhysicalIndexScan)
+	if !ok || !p.initIndexScan(is) {
+		return invalidTask
+	}
+	rt := &RootTask{}
+	rt.SetPlan(p)
+	return rt
+}
+
+// initIndexScan sets the index scan to seek, it returns false if the aggregation can't be computed by seeking the
+// distinct prefixes of the index.
+func (p *PhysicalLooseIndexScan) initIndexScan(is *PhysicalIndexScan) bool {
+	tblInfo := is.Table
+	if tblInfo.GetPartitionInfo() != nil || tblInfo.TempTableType != model.TempTableNone ||
+		tblInfo.TableCacheStatusType != model.TableCacheStatusDisable || is.Desc || is.SkipScanRanges != nil ||
+		is.haveCorCol() {
+		return false
+	}
+
+	// Map the columns read from the index to their offsets in the index.
+	idxOffsets := make(map[int64]int, len(is.Columns))
+	for i, col := range is.Columns {
+		for j, idxCol := range is.Index.Columns {
+			if tblInfo.Columns[idxCol.Offset].ID == col.ID && idxCol.Length == types.UnspecifiedLength {
+				idxOffsets[is.schema.Columns[i].UniqueID] = j
+				break
+			}
+		}
+	}
+	offsetOf := func(expr expression.Expression) int {
+		if col, ok := expr.(*expression.Column); ok {
+			if offset, ok := idxOffsets[col.UniqueID]; ok {
+				return offset
+			}
+		}
+		return -1
+	}
+
+	groupByOffsets := make(map[int]struct{}, len(p.GroupByItems))
+	for _, item := range p.GroupByItems {
+		offset := offsetOf(item)
+		if offset < 0 {
+			return false
+		}
+		groupByOffsets[offset] = struct{}{}
+	}
+	prefixLen := len(groupByOffsets)
+	for offset := range groupByOffsets {
+		if offset >= prefixLen {
+			return false
+		}
+	}
+	for _, ran := range is.Ranges {
+		if len(ran.LowVal) > prefixLen || len(ran.HighVal) > prefixLen {
+			return false
+		}
+	}
+
+	aggFuncs := make([]*aggregation.AggFuncDesc, 0, len(p.AggFuncs))
+	argOffsets := make([]int, 0, len(p.AggFuncs))
+	for _, aggFunc := range p.AggFuncs {
+		if len(aggFunc.Args) != 1 || aggFunc.Mode != aggregation.CompleteMode {
+			return false
+		}
+		arg := aggFunc.Args[0]
+		offset := offsetOf(arg)
+		switch aggFunc.Name {
+		case ast.AggFuncFirstRow:
+			if offset < 0 || offset >= prefixLen {
+				return false
+			}
+		case ast.AggFuncMin, ast.AggFuncMax:
+			if offset != prefixLen {
+				return false
+			}
+		default:
+			return false
+		}
+		if aggFunc.RetTp.GetType() != arg.GetType().GetType() {
+			return false
+		}
+		aggFuncs = append(aggFuncs, aggFunc.Clone())
+		argOffsets = append(argOffsets, offset)
+	}
+
+	p.IndexScan = is
+	p.PrefixLen = prefixLen
+	p.AggFuncs = aggFuncs
+	p.ArgOffsets = argOffsets
+	return true
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/mview"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+)
+
+// isReadOnlyMaterializedViewTable checks whether the table is a materialized view or the log of a materialized
+// view, which can't be written by the user statements. The view is written by the refresh, which runs internal
+// statements, and the log is written by the triggers on the base table.
+func (b *PlanBuilder) isReadOnlyMaterializedViewTable(tblInfo *model.TableInfo) bool {
+	vars := b.ctx.GetSessionVars()
+	if vars.InRestrictedSQL {
+		return false
+	}
+	return tblInfo.IsMaterializedView() || tblInfo.IsMaterializedViewLog && !vars.StmtCtx.InHandleTrigger
+}
+
+// tryRewriteToMaterializedView rewrites a query to read a materialized view whose rows can produce the result
+// of the query. It returns nil if there is no such view. The view may be stale, so the rewrite is only enabled
+// by `tidb_opt_enable_materialized_view_rewrite`.
+func (b *PlanBuilder) tryRewriteToMaterializedView(ctx context.Context, sel *ast.SelectStmt) *ast.SelectStmt {
+	vars := b.ctx.GetSessionVars()
+	// The rewritten query can't see the uncommitted changes of the transaction and the old data of stale reads.
+	if !vars.EnableMaterializedViewRewrite || vars.InRestrictedSQL || vars.InTxn() || vars.SnapshotTS != 0 ||
+		b.buildingRecursivePartForCTE || sel.Kind != ast.SelectStmtKindSelect || len(sel.TableHints) > 0 {
+		return nil
+	}
+	query := mview.AnalyzeSelect(b.is, sel)
+	if query.Table == nil || query.Table.TempTableType != model.TempTableNone {
+		return nil
+	}
+	for _, name := range b.is.GetTableMaterializedViews(query.Schema.L, query.Table.Name.L) {
+		tblInfo, err := b.is.TableInfoByName(query.Schema, name)
+		if err != nil || tblInfo.MaterializedView == nil || tblInfo.State != model.StatePublic ||
+			!b.canSelectTable(query.Schema, tblInfo) {
+			continue
+		}
+		info := tblInfo.MaterializedView
+		view, err := mview.Analyze(b.is, info.Definition)
+		if err != nil {
+			continue
+		}
+		rewritten, err := mview.Rewrite(query, view, query.Schema, tblInfo)
+		if err != nil || rewritten == nil {
+			continue
+		}
+		// The rewritten query has the same output names as the query.
+		for i, field := range rewritten.Fields.Fields {
+			if field.AsName.L != "" {
+				continue
+			}
+			origin := sel.Fields.Fields[i]
+			if col, ok := origin.Expr.(*ast.ColumnNameExpr); ok {
+				field.AsName = col.Name.Name
+				continue
+			}
+			if field.AsName, err = b.buildProjectionFieldNameFromExpressions(ctx, origin); err != nil {
+				return nil
+			}
+		}
+		var authErr error
+		if user := vars.User; user != nil {
+			authErr = plannererrors.ErrTableaccessDenied.FastGenByArgs("SELECT", user.AuthUsername, user.AuthHostname, query.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, query.Schema.L, query.Table.Name.L, "", authErr)
+		vars.StmtCtx.SetSkipPlanCache("the query is rewritten to read a materialized view")
+		return rewritten
+	}
+	return nil
+}
+
+func (b *PlanBuilder) canSelectTable(schema model.CIStr, tblInfo *model.TableInfo) bool {
+	pm := privilege.GetPrivilegeManager(b.ctx)
+	return pm == nil || b.ctx.GetSessionVars().User == nil ||
+		pm.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, schema.L, tblInfo.Name.L, "", mysql.SelectPriv)
+}
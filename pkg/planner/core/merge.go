@@ -0,0 +1,282 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+)
+
+// buildMerge builds the MERGE statement. The target is joined with the source, and the join is a right outer
+// join if there are NOT MATCHED clauses, so the source rows which don't match any target row are kept with
+// the NULL target columns. The target columns are at the beginning of the rows, like the table of UPDATE.
+func (b *PlanBuilder) buildMerge(ctx context.Context, merge *ast.MergeStmt) (base.Plan, error) {
+	b.pushSelectOffset(0)
+	b.pushTableHints(merge.TableHints, 0)
+	defer func() {
+		b.popSelectOffset()
+		// table hints are only visible in the current MERGE statement.
+		b.popTableHints()
+	}()
+
+	b.inUpdateStmt = true
+	b.isForUpdateRead = true
+
+	tn, ok := merge.Target.Source.(*ast.TableName)
+	if !ok {
+		return nil, infoschema.ErrTableNotExists.FastGenByArgs()
+	}
+	tblInfo := tn.TableInfo
+	if tblInfo.IsView() || tblInfo.IsSequence() || b.isReadOnlyMaterializedViewTable(tblInfo) {
+		return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "MERGE")
+	}
+	tbl, ok := b.is.TableByID(tblInfo.ID)
+	if !ok {
+		return nil, errors.Errorf("Can't get table %s", tblInfo.Name.O)
+	}
+	// targetName is the name which the columns of the target are referred by.
+	targetName := tn
+	if merge.Target.AsName.L != "" {
+		alias := *tn
+		alias.Name = merge.Target.AsName
+		alias.Schema = model.NewCIStr("")
+		targetName = &alias
+	}
+
+	joinTp := ast.CrossJoin
+	for _, clause := range merge.WhenClauses {
+		if !clause.Matched {
+			joinTp = ast.RightJoin
+			break
+		}
+	}
+	join := &ast.Join{Left: merge.Target, Right: merge.Source, Tp: joinTp, On: &ast.OnCondition{Expr: merge.On}}
+	p, err := b.buildResultSetNode(ctx, join, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range ExtractTableList(join, false) {
+		dbName := t.Schema.L
+		if dbName == "" {
+			dbName = b.ctx.GetSessionVars().CurrentDB
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, "", nil)
+	}
+	targetLen := len(tbl.WritableCols())
+	targetCols := expression.NewSchema(p.Schema().Columns[:targetLen]...)
+
+	mergePlan := Merge{}.Init(b.ctx)
+	for _, clause := range merge.WhenClauses {
+		mc := &MergeClause{Matched: clause.Matched}
+		if clause.Condition != nil {
+			b.curClause = whereClause
+			mc.Condition, p, err = b.rewrite(ctx, clause.Condition, p, nil, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		switch clause.Action {
+		case ast.MergeActionUpdate:
+			// The SET columns always belong to the target, so the unqualified ones are qualified to avoid being
+			// ambiguous with the source columns.
+			assignments := make([]*ast.Assignment, 0, len(clause.Assignments))
+			for _, assign := range clause.Assignments {
+				if assign.Column.Table.L == "" {
+					col := *assign.Column
+					col.Schema, col.Table = targetName.Schema, targetName.Name
+					assign = &ast.Assignment{Column: &col, Expr: assign.Expr}
+				}
+				assignments = append(assignments, assign)
+			}
+			var orderedList []*expression.Assignment
+			orderedList, p, _, err = b.buildUpdateLists(ctx, []*ast.TableName{targetName}, assignments, p)
+			if err != nil {
+				return nil, err
+			}
+			mc.Update = Update{
+				OrderedList:              orderedList,
+				VirtualAssignmentsOffset: len(clause.Assignments),
+			}.Init(b.ctx)
+		case ast.MergeActionDelete:
+			mc.Delete = Delete{}.Init(b.ctx)
+			b.appendTargetVisitInfo(tn, mysql.DeletePriv)
+		case ast.MergeActionInsert:
+			if p, err = b.buildMergeInsert(ctx, tn, tbl, clause, mc, p, targetCols); err != nil {
+				return nil, err
+			}
+		}
+		mergePlan.Clauses = append(mergePlan.Clauses, mc)
+	}
+
+	// Add a projection to freeze the order of the columns, the handle columns of the tables are appended if
+	// they have been pruned.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns)}.Init(b.ctx, b.getSelectOffset())
+	proj.SetSchema(p.Schema().Clone())
+	proj.names = make(types.NameSlice, len(p.OutputNames()))
+	copy(proj.names, p.OutputNames())
+	proj.SetChildren(p)
+	handleColsMap := b.handleHelper.tailMap()
+	for _, cols := range handleColsMap {
+		for _, col := range cols {
+			for i := 0; i < col.NumCols(); i++ {
+				exprCol := col.GetCol(i)
+				if proj.Schema().Contains(exprCol) {
+					continue
+				}
+				proj.Exprs = append(proj.Exprs, exprCol)
+				proj.Schema().Columns = append(proj.Schema().Columns, exprCol)
+				proj.names = append(proj.names, types.EmptyName)
+			}
+		}
+	}
+	mergePlan.names = proj.OutputNames()
+	// The projection can't be eliminated, since the positions of the target columns are fixed.
+	mergePlan.SelectPlan, _, err = DoOptimize(ctx, b.ctx, b.optFlag&^flagEliminateProjection, proj)
+	if err != nil {
+		return nil, err
+	}
+
+	tblID2Handle, err := resolveIndicesForTblID2Handle(handleColsMap, mergePlan.SelectPlan.Schema())
+	if err != nil {
+		return nil, err
+	}
+	hasExtraHandle := !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle
+	for _, handleCols := range tblID2Handle[tblInfo.ID] {
+		// The source may read the target table too. The handle of the target is one of the target columns, or
+		// the extra handle column following them.
+		if idx := handleCols.GetCol(0).Index; idx < targetLen || hasExtraHandle && idx == targetLen {
+			mergePlan.TblColPosInfo = TblColPosInfo{TblID: tblInfo.ID, Start: 0, End: targetLen, HandleCols: handleCols}
+			break
+		}
+	}
+	if mergePlan.TblColPosInfo.HandleCols == nil {
+		return nil, errors.Errorf("Couldn't get column information when do merge")
+	}
+
+	tblID2table := map[int64]table.Table{tblInfo.ID: tbl}
+	posInfos := TblColPosInfoSlice{mergePlan.TblColPosInfo}
+	for _, mc := range mergePlan.Clauses {
+		switch {
+		case mc.Update != nil:
+			mc.Update.names = mergePlan.names
+			mc.Update.SelectPlan = mergePlan.SelectPlan
+			mc.Update.TblColPosInfos = posInfos
+			mc.Update.tblID2Table = tblID2table
+			if err = mc.Update.buildOnUpdateFKTriggers(b.ctx, b.is, tblID2table); err != nil {
+				return nil, err
+			}
+		case mc.Delete != nil:
+			mc.Delete.names = mergePlan.names
+			mc.Delete.SelectPlan = mergePlan.SelectPlan
+			mc.Delete.TblColPosInfos = posInfos
+			if err = mc.Delete.buildOnDeleteFKTriggers(b.ctx, b.is, tblID2table); err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = mergePlan.ResolveIndices()
+	return mergePlan, err
+}
+
+// buildMergeInsert builds the INSERT of a NOT MATCHED clause. The values are evaluated on the rows of the
+// join, they can't refer to the target, whose columns are NULL.
+func (b *PlanBuilder) buildMergeInsert(ctx context.Context, tn *ast.TableName, tbl table.Table, clause *ast.MergeWhenClause,
+	mc *MergeClause, p base.LogicalPlan, targetCols *expression.Schema) (base.LogicalPlan, error) {
+	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx.GetExprCtx(), tn.Schema, tn.TableInfo)
+	if err != nil {
+		return nil, err
+	}
+	insertPlan := Insert{
+		Table:         tbl,
+		Columns:       clause.Columns,
+		tableSchema:   schema,
+		tableColNames: names,
+	}.Init(b.ctx)
+	b.appendTargetVisitInfo(tn, mysql.InsertPriv)
+
+	affectedValuesCols, err := b.getAffectCols(&ast.InsertStmt{Columns: clause.Columns}, insertPlan)
+	if err != nil {
+		return nil, err
+	}
+	if (len(clause.Columns) > 0 || len(clause.Values) > 0) && len(clause.Values) != len(affectedValuesCols) {
+		return nil, plannererrors.ErrWrongValueCountOnRow.GenWithStackByArgs(1)
+	}
+	b.curClause = fieldList
+	for i, value := range clause.Values {
+		col := affectedValuesCols[i]
+		if col.Hidden {
+			return nil, plannererrors.ErrUnknownColumn.GenWithStackByArgs(col.Name, clauseMsg[fieldList])
+		}
+		var expr expression.Expression
+		if defaultExpr := extractDefaultExpr(value); defaultExpr != nil {
+			// Only DEFAULT is permitted for the generated columns, which are computed from the inserted row.
+			if col.IsGenerated() {
+				continue
+			}
+			if expr, err = b.getDefaultValueForInsert(col); err != nil {
+				return nil, err
+			}
+		} else {
+			if col.IsGenerated() {
+				return nil, plannererrors.ErrBadGeneratedColumn.GenWithStackByArgs(col.Name.O, tn.TableInfo.Name.O)
+			}
+			if expr, p, err = b.rewrite(ctx, value, p, nil, true); err != nil {
+				return nil, err
+			}
+			for _, c := range expression.ExtractColumns(expr) {
+				if targetCols.Contains(c) {
+					return nil, plannererrors.ErrUnknownColumn.GenWithStackByArgs(c.OrigName, clauseMsg[fieldList])
+				}
+			}
+		}
+		mc.Values = append(mc.Values, expr)
+	}
+	insertPlan.RowLen = len(mc.Values)
+
+	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
+	mockTablePlan.SetSchema(insertPlan.tableSchema)
+	mockTablePlan.names = insertPlan.tableColNames
+	insertPlan.GenCols, err = b.resolveGeneratedColumns(ctx, insertPlan.Table.Cols(), nil, mockTablePlan)
+	if err != nil {
+		return nil, err
+	}
+	if err = insertPlan.ResolveIndices(); err != nil {
+		return nil, err
+	}
+	if err = insertPlan.buildOnInsertFKTriggers(b.ctx, b.is, tn.DBInfo.Name.L); err != nil {
+		return nil, err
+	}
+	mc.Insert = insertPlan
+	return p, nil
+}
+
+func (b *PlanBuilder) appendTargetVisitInfo(tn *ast.TableName, priv mysql.PrivilegeType) {
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = plannererrors.ErrTableaccessDenied.FastGenByArgs(strings.ToUpper(mysql.Priv2Str[priv]), user.AuthUsername, user.AuthHostname, tn.Name.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, priv, tn.DBInfo.Name.L, tn.Name.L, "", authErr)
+}
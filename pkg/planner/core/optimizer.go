@@ -177,6 +177,24 @@ func CheckPrivilege(activeRoles []*auth.RoleIdentity, pm privilege.Manager, vs [
 	return nil
 }
 
+// CheckPrivilegeWithUser checks the privilege of the user rather than the current user, such as the definer of a
+// trigger whose body is executed with the privileges of the definer. The checks are the same as CheckPrivilege.
+func CheckPrivilegeWithUser(user *auth.UserIdentity, pm privilege.Manager, vs []visitInfo) error {
+	for _, v := range vs {
+		if v.privilege == mysql.ExtendedPriv {
+			if !pm.RequestDynamicVerificationWithUser(v.dynamicPriv, v.dynamicWithGrant, user) {
+				return plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs(v.dynamicPriv)
+			}
+		} else if !pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, user) {
+			if v.table == "" {
+				return plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.Username, user.Hostname, v.db)
+			}
+			return plannererrors.ErrTableaccessDenied.GenWithStackByArgs(v.privilege.String(), user.Username, user.Hostname, v.table)
+		}
+	}
+	return nil
+}
+
 // VisitInfo4PrivCheck generates privilege check infos because privilege check of local temporary tables is different
 // with normal tables. `CREATE` statement needs `CREATE TEMPORARY TABLE` privilege from the database, and subsequent
 // statements do not need any privileges.
@@ -327,6 +345,8 @@ func adjustOptimizationFlags(flag uint64, logic base.LogicalPlan) uint64 {
 	flag |= flagSyncWaitStatsLoadPoint
 	if !logic.SCtx().GetSessionVars().StmtCtx.UseDynamicPruneMode {
 		flag |= flagPartitionProcessor // apply partition pruning under static mode
+	} else if logic.SCtx().GetSessionVars().EnablePartitionWiseJoin {
+		flag |= flagPartitionProcessor // split the joins of the partitions under dynamic mode
 	}
 	return flag
 }
@@ -1081,6 +1101,9 @@ func physicalOptimize(logic base.LogicalPlan, planCounter *base.PlanCounterTp) (
 		return nil, 0, plannererrors.ErrInternal.GenWithStackByArgs(errMsg)
 	}
 
+	if err = planAdaptiveHashJoins(t.Plan(), opt); err != nil {
+		return nil, 0, err
+	}
 	if err = t.Plan().ResolveIndices(); err != nil {
 		return nil, 0, err
 	}
@@ -1088,6 +1111,61 @@ func physicalOptimize(logic base.LogicalPlan, planCounter *base.PlanCounterTp) (
 	return t.Plan(), cost, err
 }
 
+// planAdaptiveHashJoins plans the hash join for each index join of the plan, which the index join switches to at
+// runtime when its outer side is much larger than estimated. See PhysicalIndexJoin.AdaptiveHashJoin.
+func planAdaptiveHashJoins(p base.PhysicalPlan, opt *optimizetrace.PhysicalOptimizeOp) error {
+	for _, child := range p.Children() {
+		if err := planAdaptiveHashJoins(child, opt); err != nil {
+			return err
+		}
+	}
+	var join *PhysicalIndexJoin
+	switch x := p.(type) {
+	case *PhysicalIndexJoin:
+		join = x
+	case *PhysicalIndexHashJoin:
+		join = &x.PhysicalIndexJoin
+	default:
+		return nil
+	}
+	lp := join.adaptiveJoin
+	if lp == nil || lp.shouldSkipHashJoin() || len(lp.EqualConditions) == 0 || len(lp.NAEQConditions) > 0 {
+		return nil
+	}
+	innerIdx := join.InnerChildIdx
+	if (innerIdx == 0 && len(lp.LeftConditions) > 0) || (innerIdx == 1 && len(lp.RightConditions) > 0) {
+		return nil
+	}
+	// The index join under a limit stops once enough rows are joined, so it's not switched.
+	outer := join.Children()[1-innerIdx]
+	if outer.StatsCount() < lp.Children()[1-innerIdx].StatsInfo().RowCount {
+		return nil
+	}
+	prop := &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64}
+	innerTask, _, err := lp.Children()[innerIdx].FindBestTask(prop, &PlanCounterDisabled, opt)
+	if err != nil {
+		return err
+	}
+	if innerTask.Invalid() {
+		return nil
+	}
+	hashJoin := NewPhysicalHashJoin(lp, innerIdx, false, join.StatsInfo())
+	hashJoin.EqualConditions = slices.Clone(hashJoin.EqualConditions)
+	hashJoin.LeftConditions = slices.Clone(hashJoin.LeftConditions)
+	hashJoin.RightConditions = slices.Clone(hashJoin.RightConditions)
+	hashJoin.OtherConditions = slices.Clone(hashJoin.OtherConditions)
+	hashJoin.SetSchema(join.Schema())
+	// The hash join is executed in place of the index join, so its runtime stats are shown as the index join's.
+	hashJoin.SetID(join.ID())
+	children := make([]base.PhysicalPlan, 2)
+	children[innerIdx], children[1-innerIdx] = innerTask.ConvertToRootTask(lp.SCtx()).Plan(), outer
+	hashJoin.SetChildren(children...)
+	join.AdaptiveHashJoin = hashJoin
+	// The inner side of the hash join is planned for the parameters of this execution.
+	lp.SCtx().GetSessionVars().StmtCtx.SetSkipPlanCache("the index join can switch to the hash join")
+	return nil
+}
+
 // eliminateUnionScanAndLock set lock property for PointGet and BatchPointGet and eliminates UnionScan and Lock.
 func eliminateUnionScanAndLock(sctx base.PlanContext, p base.PhysicalPlan) base.PhysicalPlan {
 	var pointGet *PointGetPlan
@@ -1121,12 +1199,15 @@ func eliminateUnionScanAndLock(sctx base.PlanContext, p base.PhysicalPlan) base.
 		if !lock {
 			return p
 		}
+		skipLocked := IsSelectSkipLockedLockType(physLock.Lock.LockType)
 		if pointGet != nil {
 			pointGet.Lock = lock
 			pointGet.LockWaitTime = waitTime
+			pointGet.LockSkipLocked = skipLocked
 		} else {
 			batchPointGet.Lock = lock
 			batchPointGet.LockWaitTime = waitTime
+			batchPointGet.LockSkipLocked = skipLocked
 		}
 	}
 	return transformPhysicalPlan(p, func(p base.PhysicalPlan) base.PhysicalPlan {
@@ -0,0 +1,276 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/property"
+	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+)
+
+// maxOrExpansionBranches is the max number of the UNION ALL branches an OR-expansion can produce.
+const maxOrExpansionBranches = 8
+
+// ExpandOrToUnionAll rewrites `SELECT ... WHERE d1 OR d2 OR ... OR dn` into
+// `SELECT ... WHERE d1 UNION ALL SELECT ... WHERE d2 AND (d1) IS NOT TRUE UNION ALL ...`, so that every branch is
+// optimized independently and can choose its own access paths and join order. The `IS NOT TRUE` predicates work as
+// LNNVL: a row is returned only by the branch of the first disjunct it satisfies, so no duplicate is produced.
+// It returns nil if the statement can't be expanded.
+func ExpandOrToUnionAll(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (ast.StmtNode, error) {
+	sel, ok := node.(*ast.SelectStmt)
+	if !ok || !canExpandOr(sel) {
+		return nil, nil
+	}
+	disjuncts := splitDisjuncts(sel.Where, nil)
+	if len(disjuncts) < 2 || len(disjuncts) > maxOrExpansionBranches || !canUseIndexForDisjuncts(sel, disjuncts) {
+		return nil, nil
+	}
+
+	// Restore every branch with its own WHERE clause and parse them together, so that the branches don't share any
+	// AST node with each other or with the original statement.
+	originWhere := sel.Where
+	defer func() {
+		sel.Where = originWhere
+	}()
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	for i, disjunct := range disjuncts {
+		conds := make([]ast.ExprNode, 0, i+1)
+		conds = append(conds, &ast.ParenthesesExpr{Expr: disjunct})
+		for _, prev := range disjuncts[:i] {
+			conds = append(conds, &ast.IsTruthExpr{Expr: &ast.ParenthesesExpr{Expr: prev}, Not: true, True: 1})
+		}
+		sel.Where = composeAnd(conds)
+		if i > 0 {
+			sb.WriteString(" UNION ALL ")
+		}
+		if err := sel.Restore(restoreCtx); err != nil {
+			return nil, err
+		}
+	}
+
+	sessVars := sctx.GetSessionVars()
+	charset, collation := sessVars.GetCharsetInfo()
+	p := parser.New()
+	p.SetParserConfig(sessVars.BuildParserConfig())
+	p.SetSQLMode(sessVars.SQLMode)
+	stmt, err := p.ParseOneStmt(sb.String(), charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	if err := Preprocess(ctx, sctx, stmt, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is})); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// GetOrExpansionCost returns the cost of the plan of the expanded statement. The concurrency of the UNION ALL is not
+// taken into account, otherwise the expanded plan looks cheaper than the original one even if every branch reads the
+// whole table again.
+func GetOrExpansionCost(p base.PhysicalPlan) (float64, error) {
+	option := optimizetrace.NewDefaultPlanCostOption()
+	union, ok := p.(*PhysicalUnionAll)
+	if !ok {
+		return getPlanCost(p, property.RootTaskType, option)
+	}
+	var cost float64
+	for _, child := range union.Children() {
+		childCost, err := getPlanCost(child, property.RootTaskType, option)
+		if err != nil {
+			return 0, err
+		}
+		cost += childCost
+	}
+	return cost, nil
+}
+
+// canExpandOr checks whether the result of the statement is the union of the results of its disjunctive branches.
+func canExpandOr(sel *ast.SelectStmt) bool {
+	if sel.Kind != ast.SelectStmtKindSelect || sel.From == nil || sel.Where == nil || sel.With != nil ||
+		sel.Distinct || sel.GroupBy != nil || sel.Having != nil || sel.WindowSpecs != nil || sel.OrderBy != nil ||
+		sel.Limit != nil || sel.LockInfo != nil || sel.SelectIntoOpt != nil || sel.AfterSetOperator != nil ||
+		(sel.SelectStmtOpts != nil && sel.SelectStmtOpts.CalcFoundRows) {
+		return false
+	}
+	// The query block offsets are changed by the expansion.
+	for _, hint := range sel.TableHints {
+		if hint.QBName.L != "" {
+			return false
+		}
+		for _, table := range hint.Tables {
+			if table.QBName.L != "" {
+				return false
+			}
+		}
+	}
+	aggChecker := &orExpansionAggChecker{}
+	sel.Fields.Accept(aggChecker)
+	if aggChecker.hasAgg {
+		return false
+	}
+	checker := &orExpansionChecker{}
+	sel.Accept(checker)
+	return !checker.unexpandable
+}
+
+// canUseIndexForDisjuncts checks whether every disjunct refers to the leading column of an index of the tables
+// in the FROM clause. Otherwise, some branch has to scan the whole table, so the expanded statement can't be
+// cheaper than the original one, and the original one can't use index merge either.
+func canUseIndexForDisjuncts(sel *ast.SelectStmt, disjuncts []ast.ExprNode) bool {
+	tables := &orExpansionTableCollector{}
+	sel.From.Accept(tables)
+	leadingCols := make(map[string]struct{})
+	for _, tblInfo := range tables.tblInfos {
+		if pk := tblInfo.GetPkColInfo(); tblInfo.PKIsHandle && pk != nil {
+			leadingCols[pk.Name.L] = struct{}{}
+		}
+		for _, idx := range tblInfo.Indices {
+			if idx.State == model.StatePublic && !idx.Invisible && !idx.IsFullText() {
+				leadingCols[idx.Columns[0].Name.L] = struct{}{}
+			}
+		}
+	}
+	for _, disjunct := range disjuncts {
+		cols := &orExpansionColumnCollector{leadingCols: leadingCols}
+		disjunct.Accept(cols)
+		if !cols.found {
+			return false
+		}
+	}
+	return true
+}
+
+// splitDisjuncts flattens the OR expression into its disjuncts.
+func splitDisjuncts(expr ast.ExprNode, disjuncts []ast.ExprNode) []ast.ExprNode {
+	switch x := expr.(type) {
+	case *ast.BinaryOperationExpr:
+		if x.Op == opcode.LogicOr {
+			disjuncts = splitDisjuncts(x.L, disjuncts)
+			return splitDisjuncts(x.R, disjuncts)
+		}
+	case *ast.ParenthesesExpr:
+		if inner, ok := x.Expr.(*ast.BinaryOperationExpr); ok && inner.Op == opcode.LogicOr {
+			return splitDisjuncts(inner, disjuncts)
+		}
+	}
+	return append(disjuncts, expr)
+}
+
+func composeAnd(conds []ast.ExprNode) ast.ExprNode {
+	expr := conds[0]
+	for _, cond := range conds[1:] {
+		expr = &ast.BinaryOperationExpr{Op: opcode.LogicAnd, L: expr, R: cond}
+	}
+	return expr
+}
+
+// orExpansionChecker finds the expressions which are evaluated differently when the statement is expanded.
+type orExpansionChecker struct {
+	unexpandable bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *orExpansionChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case ast.ParamMarkerExpr:
+		// The parameters can't be bound to the expanded statement.
+		c.unexpandable = true
+	case *ast.FuncCallExpr:
+		// The mutable functions may return different values in the branches.
+		c.unexpandable = expression.IsMutableEffectsFunc(x.FnName.L)
+	case *ast.VariableExpr:
+		c.unexpandable = x.Value != nil
+	case *ast.AsOfClause:
+		c.unexpandable = true
+	case *ast.SubqueryExpr:
+		// The uncorrelated subqueries would be evaluated again when building the expanded statement.
+		c.unexpandable = true
+	}
+	return in, c.unexpandable
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *orExpansionChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.unexpandable
+}
+
+// orExpansionAggChecker finds the aggregate and window functions of the query block.
+type orExpansionAggChecker struct {
+	hasAgg bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *orExpansionAggChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+		c.hasAgg = true
+	case *ast.SubqueryExpr:
+		return in, true
+	}
+	return in, c.hasAgg
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *orExpansionAggChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.hasAgg
+}
+
+// orExpansionTableCollector collects the tables of the FROM clause.
+type orExpansionTableCollector struct {
+	tblInfos []*model.TableInfo
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *orExpansionTableCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok && tn.TableInfo != nil {
+		c.tblInfos = append(c.tblInfos, tn.TableInfo)
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*orExpansionTableCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// orExpansionColumnCollector finds whether the expression refers to the leading column of an index.
+type orExpansionColumnCollector struct {
+	leadingCols map[string]struct{}
+	found       bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *orExpansionColumnCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if col, ok := in.(*ast.ColumnNameExpr); ok {
+		_, c.found = c.leadingCols[col.Name.Name.L]
+	}
+	return in, c.found
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *orExpansionColumnCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.found
+}
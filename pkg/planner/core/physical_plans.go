@@ -16,6 +16,7 @@ package core
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -240,12 +241,7 @@ func setMppOrBatchCopForTableScan(curPlan base.PhysicalPlan) {
 // GetPhysicalTableReader returns PhysicalTableReader for logical TiKVSingleGather.
 func (sg *TiKVSingleGather) GetPhysicalTableReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalTableReader {
 	reader := PhysicalTableReader{}.Init(sg.SCtx(), sg.QueryBlockOffset())
-	reader.PlanPartInfo = PhysPlanPartInfo{
-		PruningConds:   sg.Source.allConds,
-		PartitionNames: sg.Source.partitionNames,
-		Columns:        sg.Source.TblCols,
-		ColumnNames:    sg.Source.names,
-	}
+	reader.PlanPartInfo = sg.getPhysPlanPartInfo()
 	reader.SetStats(stats)
 	reader.SetSchema(schema)
 	reader.childrenReqProps = props
@@ -255,12 +251,81 @@ func (sg *TiKVSingleGather) GetPhysicalTableReader(schema *expression.Schema, st
 // GetPhysicalIndexReader returns PhysicalIndexReader for logical TiKVSingleGather.
 func (sg *TiKVSingleGather) GetPhysicalIndexReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexReader {
 	reader := PhysicalIndexReader{}.Init(sg.SCtx(), sg.QueryBlockOffset())
+	reader.PlanPartInfo = sg.getPhysPlanPartInfo()
+	reader.SetStats(stats)
+	reader.SetSchema(schema)
+	reader.childrenReqProps = props
+	return reader
+}
+
+func (sg *TiKVSingleGather) getPhysPlanPartInfo() PhysPlanPartInfo {
+	pruningConds := sg.Source.allConds
+	if len(sg.PruningConds) > 0 {
+		pruningConds = append(slices.Clone(pruningConds), sg.PruningConds...)
+	}
+	return PhysPlanPartInfo{
+		PruningConds:   pruningConds,
+		PartitionNames: sg.Source.partitionNames,
+		Columns:        sg.Source.TblCols,
+		ColumnNames:    sg.Source.names,
+	}
+}
+
+// GetPhysicalIndexMergeReader returns the physical plan reading the index merge
+// path of the logical TiKVIndexMergeGather. It returns nil if the path can't
+// satisfy the required property.
+func (g *TiKVIndexMergeGather) GetPhysicalIndexMergeReader(prop *property.PhysicalProperty) (base.PhysicalPlan, error) {
+	ds := g.Source
+	var candidate *candidatePath
+	if len(g.Path.PartialAlternativeIndexPaths) > 0 {
+		candidate = ds.convergeIndexMergeCandidate(g.Path, prop)
+	} else {
+		candidate = ds.getIndexMergeCandidate(g.Path, prop)
+	}
+	if candidate == nil {
+		return nil, nil
+	}
+	rootProp := &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: prop.ExpectedCnt, SortItems: prop.SortItems}
+	t, err := ds.convertToIndexMergeScan(rootProp, candidate, nil)
+	if err != nil || t.Invalid() {
+		return nil, err
+	}
+	return t.Plan(), nil
+}
+
+// GetPhysicalMPPReader returns PhysicalTableReader for logical MPPGather,
+// the plan of the MPP tasks is attached to it by AttachMPPPlan.
+func (g *MPPGather) GetPhysicalMPPReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalTableReader {
+	reader := PhysicalTableReader{StoreType: kv.TiFlash}.Init(g.SCtx(), g.QueryBlockOffset())
+	reader.ReadReqType = MPP
 	reader.SetStats(stats)
 	reader.SetSchema(schema)
 	reader.childrenReqProps = props
 	return reader
 }
 
+// AttachMPPPlan attaches the plan of the MPP tasks to the reader, a PassThrough
+// ExchangeSender is added on top of it to send the results back to TiDB. The
+// pruningConds are used to prune the partitions read by the table scans.
+func (p *PhysicalTableReader) AttachMPPPlan(mppPlan base.PhysicalPlan, pruningConds []expression.Expression) {
+	tryExpandVirtualColumn(mppPlan)
+	sender := PhysicalExchangeSender{ExchangeType: tipb.ExchangeType_PassThrough}.Init(p.SCtx(), mppPlan.StatsInfo())
+	sender.SetChildren(mppPlan)
+	p.SetChildren(sender)
+	setMppOrBatchCopForTableScan(sender)
+	p.TableScanAndPartitionInfos = nil
+	collectPartitionInfosFromMPPPlan(p, mppPlan)
+	for i := range p.TableScanAndPartitionInfos {
+		info := &p.TableScanAndPartitionInfos[i].physPlanPartInfo
+		tblSchema := expression.NewSchema(info.Columns...)
+		for _, cond := range pruningConds {
+			if expression.ExprFromSchema(cond, tblSchema) {
+				info.PruningConds = append(slices.Clip(info.PruningConds), cond)
+			}
+		}
+	}
+}
+
 // Clone implements op.PhysicalPlan interface.
 func (p *PhysicalTableReader) Clone() (base.PhysicalPlan, error) {
 	cloned := new(PhysicalTableReader)
@@ -701,6 +766,12 @@ type PhysicalIndexScan struct {
 
 	TableAsName *model.CIStr
 
+	// SkipScanRanges are the ranges on the index columns after the leading one if the index is read by a skip scan,
+	// the Ranges are built by appending them to the distinct values of the leading column at execution.
+	SkipScanRanges []*ranger.Range
+	// skipScanNDV is the NDV of the leading column, which is the number of the seeks of the skip scan.
+	skipScanNDV float64
+
 	// dataSourceSchema is the original schema of DataSource. The schema of index scan in KV and index reader in TiDB
 	// will be different. The schema of index scan will decode all columns of index but the TiDB only need some of them.
 	dataSourceSchema *expression.Schema
@@ -760,6 +831,9 @@ func (p *PhysicalIndexScan) Clone() (base.PhysicalPlan, error) {
 	cloned.IdxColLens = make([]int, len(p.IdxColLens))
 	copy(cloned.IdxColLens, p.IdxColLens)
 	cloned.Ranges = util.CloneRanges(p.Ranges)
+	if p.SkipScanRanges != nil {
+		cloned.SkipScanRanges = util.CloneRanges(p.SkipScanRanges)
+	}
 	cloned.Columns = util.CloneColInfos(p.Columns)
 	if p.dataSourceSchema != nil {
 		cloned.dataSourceSchema = p.dataSourceSchema.Clone()
@@ -809,6 +883,9 @@ func (p *PhysicalIndexScan) MemoryUsage() (sum int64) {
 	for _, rang := range p.Ranges {
 		sum += rang.MemUsage()
 	}
+	for _, rang := range p.SkipScanRanges {
+		sum += rang.MemUsage()
+	}
 	for iid, expr := range p.GenExprs {
 		sum += int64(unsafe.Sizeof(iid)) + expr.MemoryUsage()
 	}
@@ -855,6 +932,76 @@ func (p *PhysicalMemTable) MemoryUsage() (sum int64) {
 	return
 }
 
+// PhysicalLooseIndexScan computes the aggregation grouped by the leading columns of an index by seeking the distinct
+// prefixes one after another rather than reading all the index entries. Each seek is a range read through distsql.
+// Only FIRSTROW of the prefix columns and MIN/MAX of the index column following the prefix are supported.
+type PhysicalLooseIndexScan struct {
+	physicalSchemaProducer
+
+	// IndexScan describes the index and the ranges to read, the ranges are on the prefix columns only.
+	IndexScan *PhysicalIndexScan
+	// PrefixLen is the number of the leading index columns grouped by.
+	PrefixLen    int
+	GroupByItems []expression.Expression
+	AggFuncs     []*aggregation.AggFuncDesc
+	// ArgOffsets are the offsets in the index of the columns the AggFuncs read.
+	ArgOffsets []int
+}
+
+// HasMinMax returns whether the MIN or MAX of the column following the prefix is computed.
+func (p *PhysicalLooseIndexScan) HasMinMax() (hasMin, hasMax bool) {
+	for _, aggFunc := range p.AggFuncs {
+		switch aggFunc.Name {
+		case ast.AggFuncMin:
+			hasMin = true
+		case ast.AggFuncMax:
+			hasMax = true
+		}
+	}
+	return
+}
+
+// Clone implements op.PhysicalPlan interface.
+func (p *PhysicalLooseIndexScan) Clone() (base.PhysicalPlan, error) {
+	cloned := new(PhysicalLooseIndexScan)
+	*cloned = *p
+	base, err := p.physicalSchemaProducer.cloneWithSelf(cloned)
+	if err != nil {
+		return nil, err
+	}
+	cloned.physicalSchemaProducer = *base
+	is, err := p.IndexScan.Clone()
+	if err != nil {
+		return nil, err
+	}
+	cloned.IndexScan = is.(*PhysicalIndexScan)
+	cloned.GroupByItems = util.CloneExprs(p.GroupByItems)
+	cloned.AggFuncs = make([]*aggregation.AggFuncDesc, 0, len(p.AggFuncs))
+	for _, aggDesc := range p.AggFuncs {
+		cloned.AggFuncs = append(cloned.AggFuncs, aggDesc.Clone())
+	}
+	cloned.ArgOffsets = append([]int(nil), p.ArgOffsets...)
+	return cloned, nil
+}
+
+// MemoryUsage return the memory usage of PhysicalLooseIndexScan
+func (p *PhysicalLooseIndexScan) MemoryUsage() (sum int64) {
+	if p == nil {
+		return
+	}
+
+	sum = p.physicalSchemaProducer.MemoryUsage() + p.IndexScan.MemoryUsage() + size.SizeOfInt +
+		size.SizeOfSlice*3 + int64(cap(p.GroupByItems))*size.SizeOfInterface +
+		int64(cap(p.AggFuncs))*size.SizeOfPointer + int64(cap(p.ArgOffsets))*size.SizeOfInt
+	for _, expr := range p.GroupByItems {
+		sum += expr.MemoryUsage()
+	}
+	for _, agg := range p.AggFuncs {
+		sum += agg.MemoryUsage()
+	}
+	return
+}
+
 // PhysicalTableScan represents a table scan plan.
 type PhysicalTableScan struct {
 	physicalSchemaProducer
@@ -1488,6 +1635,13 @@ type PhysicalIndexJoin struct {
 	// InnerHashKeys indicates the inner keys used to build hash table during
 	// execution. InnerJoinKeys is the prefix of InnerHashKeys.
 	InnerHashKeys []*expression.Column
+	// AdaptiveHashJoin is the hash join which the index join switches to at runtime when the outer side turns out
+	// to be much larger than estimated. It shares the outer child with the index join, and its inner child reads
+	// the whole inner side. It's nil if the adaptive join is disabled.
+	AdaptiveHashJoin *PhysicalHashJoin
+
+	// adaptiveJoin is the logical join used to plan AdaptiveHashJoin.
+	adaptiveJoin *LogicalJoin
 }
 
 // MemoryUsage return the memory usage of PhysicalIndexJoin
@@ -1497,7 +1651,7 @@ func (p *PhysicalIndexJoin) MemoryUsage() (sum int64) {
 	}
 
 	sum = p.basePhysicalJoin.MemoryUsage() + size.SizeOfInterface*2 + size.SizeOfSlice*4 +
-		int64(cap(p.KeyOff2IdxOff)+cap(p.IdxColLens))*size.SizeOfInt + size.SizeOfPointer
+		int64(cap(p.KeyOff2IdxOff)+cap(p.IdxColLens))*size.SizeOfInt + size.SizeOfPointer*3
 	if p.innerTask != nil {
 		sum += p.innerTask.MemoryUsage()
 	}
@@ -2459,6 +2613,8 @@ func CollectPlanStatsVersion(plan base.PhysicalPlan, statsInfos map[string]uint6
 		// For index loop up, only the indexPlan is necessary,
 		// because they use the same stats and we do not set the stats info for tablePlan.
 		statsInfos = CollectPlanStatsVersion(copPlan.indexPlan, statsInfos)
+	case *PhysicalLooseIndexScan:
+		statsInfos = CollectPlanStatsVersion(copPlan.IndexScan, statsInfos)
 	case *PhysicalIndexScan:
 		statsInfos[copPlan.Table.Name.O] = copPlan.StatsInfo().StatsVersion
 	case *PhysicalTableScan:
@@ -2523,6 +2679,48 @@ func SafeClone(v base.PhysicalPlan) (_ base.PhysicalPlan, err error) {
 	return v.Clone()
 }
 
+// PhysicalJSONTable is the physical plan of JSON_TABLE.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	Expr     expression.Expression
+	RootPath *JSONTablePath
+	AsName   model.CIStr
+}
+
+// ExtractCorrelatedCols implements op.PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
+// Clone implements op.PhysicalPlan interface.
+func (p *PhysicalJSONTable) Clone() (base.PhysicalPlan, error) {
+	cloned := new(PhysicalJSONTable)
+	base, err := p.physicalSchemaProducer.cloneWithSelf(cloned)
+	if err != nil {
+		return nil, err
+	}
+	cloned.physicalSchemaProducer = *base
+	cloned.Expr = p.Expr.Clone()
+	// The paths are read-only after the plan is built.
+	cloned.RootPath = p.RootPath
+	cloned.AsName = p.AsName
+	return cloned, nil
+}
+
+// MemoryUsage return the memory usage of PhysicalJSONTable
+func (p *PhysicalJSONTable) MemoryUsage() (sum int64) {
+	if p == nil {
+		return
+	}
+
+	sum = p.physicalSchemaProducer.MemoryUsage() + size.SizeOfInterface + size.SizeOfPointer
+	if p.Expr != nil {
+		sum += p.Expr.MemoryUsage()
+	}
+	return
+}
+
 // PhysicalTableSample represents a table sample plan.
 // It returns the sample rows to its parent operand.
 type PhysicalTableSample struct {
@@ -220,6 +220,11 @@ func GetPlanFromSessionPlanCache(ctx context.Context, sctx sessionctx.Context,
 		return nil, nil, err
 	}
 	if stmtCtx.UseCache() {
+		if useInstancePlanCache(sctx, stmt) {
+			if plan, names, ok, err := getInstanceCachedPlan(sctx, isNonPrepared, cacheKey, bindSQL, is, stmt, matchOpts); err != nil || ok {
+				return plan, names, err
+			}
+		}
 		if plan, names, ok, err := getCachedPlan(sctx, isNonPrepared, cacheKey, bindSQL, is, stmt, matchOpts); err != nil || ok {
 			return plan, names, err
 		}
@@ -269,6 +274,50 @@ func getCachedPlan(sctx sessionctx.Context, isNonPrepared bool, cacheKey kvcache
 	return cachedVal.Plan, cachedVal.OutputColumns, true, nil
 }
 
+// getInstanceCachedPlan tries to get a plan from the plan cache shared by all sessions.
+// The cached plan is a template, a copy of it bound to the current session is returned.
+func getInstanceCachedPlan(sctx sessionctx.Context, isNonPrepared bool, cacheKey kvcache.Key, bindSQL string,
+	is infoschema.InfoSchema, stmt *PlanCacheStmt, matchOpts *utilpc.PlanCacheMatchOpts) (base.Plan,
+	[]*types.FieldName, bool, error) {
+	sessVars := sctx.GetSessionVars()
+	stmtCtx := sessVars.StmtCtx
+
+	candidate, exist := domain.GetDomain(sctx).InstancePlanCache().Get(instancePlanCacheKey(cacheKey, sessVars),
+		func(value any) bool {
+			return matchCachedPlan(sctx, value.(*PlanCacheValue), matchOpts)
+		})
+	if !exist {
+		return nil, nil, false, nil
+	}
+	cachedVal := candidate.(*PlanCacheValue)
+	if err := checkPreparedPriv(sctx, stmt, is); err != nil {
+		return nil, nil, false, err
+	}
+	for tblInfo, unionScan := range cachedVal.TblInfo2UnionScan {
+		if !unionScan && tableHasDirtyContent(sctx.GetPlanCtx(), tblInfo) {
+			// The plan is still valid for other sessions, so don't remove it from the cache.
+			return nil, nil, false, nil
+		}
+	}
+	plan, ok := clonePlanForInstanceCache(cachedVal.Plan, sctx.GetPlanCtx())
+	if !ok || !RebuildPlan4CachedPlan(plan) {
+		return nil, nil, false, nil
+	}
+	sessVars.FoundInPlanCache = true
+	if len(bindSQL) > 0 {
+		sessVars.FoundInBinding = true
+	}
+	if metrics.ResettablePlanCacheCounterFortTest {
+		metrics.PlanCacheCounter.WithLabelValues("prepare").Inc()
+	} else {
+		core_metrics.GetPlanCacheHitCounter(isNonPrepared).Inc()
+	}
+	stmt.NormalizedPlan, stmt.PlanDigest = cachedVal.normalizedPlan, cachedVal.planDigest
+	stmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
+	stmtCtx.StmtHints = *cachedVal.stmtHints
+	return plan, cachedVal.OutputColumns, true, nil
+}
+
 // generateNewPlan call the optimizer to generate a new plan for current statement
 // and try to add it to cache
 func generateNewPlan(ctx context.Context, sctx sessionctx.Context, isNonPrepared bool, is infoschema.InfoSchema,
@@ -308,12 +357,40 @@ func generateNewPlan(ctx context.Context, sctx sessionctx.Context, isNonPrepared
 		stmt.NormalizedPlan, stmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlan(p)
 		stmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
-		sctx.GetSessionPlanCache().Put(cacheKey, cached, matchOpts)
+		if !putInstanceCachedPlan(sctx, stmt, cacheKey, cached) {
+			sctx.GetSessionPlanCache().Put(cacheKey, cached, matchOpts)
+		}
 	}
 	sessVars.FoundInPlanCache = false
 	return p, names, err
 }
 
+// putInstanceCachedPlan tries to put the plan into the plan cache shared by all sessions, it returns false if the plan
+// isn't put, then it should be put into the session plan cache instead.
+func putInstanceCachedPlan(sctx sessionctx.Context, stmt *PlanCacheStmt, cacheKey kvcache.Key, cached *PlanCacheValue) bool {
+	if !useInstancePlanCache(sctx, stmt) {
+		return false
+	}
+	// The plan being executed is owned by the session, put a template which isn't bound to any session into the cache.
+	template, ok := clonePlanForInstanceCache(cached.Plan, nil)
+	if !ok {
+		return false
+	}
+	shared := *cached
+	shared.Plan = template
+	shared.memoryUsage = 0
+	shared.stmtDB = stmt.StmtDB
+	if shared.stmtDB == "" {
+		shared.stmtDB = sctx.GetSessionVars().CurrentDB
+	}
+	shared.stmtText = stmt.StmtText
+	shared.normalizedPlan, shared.planDigest = stmt.NormalizedPlan, stmt.PlanDigest
+	return domain.GetDomain(sctx).InstancePlanCache().Put(instancePlanCacheKey(cacheKey, sctx.GetSessionVars()), &shared,
+		func(value any) bool {
+			return matchCachedPlan(sctx, value.(*PlanCacheValue), cached.matchOpts)
+		})
+}
+
 // checkPreparedPriv checks the privilege of the prepared statement
 func checkPreparedPriv(sctx sessionctx.Context, stmt *PlanCacheStmt, is infoschema.InfoSchema) error {
 	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
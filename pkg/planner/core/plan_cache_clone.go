@@ -0,0 +1,357 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/expression/aggregation"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// clonePlanForInstanceCache deep copies the plan and binds the copy to the session.
+// The plans in the instance plan cache are shared by all sessions, so every session has to work on its own copy, since
+// rebuilding the ranges and building the executors modify the plan.
+// It returns false if the plan can't be shared, only some common operators are supported now.
+func clonePlanForInstanceCache(plan base.Plan, sctx base.PlanContext) (base.Plan, bool) {
+	p, ok := plan.(base.PhysicalPlan)
+	if !ok {
+		return nil, false
+	}
+	c := &planCloner{sctx: sctx}
+	return c.clone(p)
+}
+
+// planCloner deep copies the physical plans for the instance plan cache.
+// sctx is nil when making the template which is put into the cache.
+type planCloner struct {
+	sctx base.PlanContext
+}
+
+func (c *planCloner) clone(plan base.PhysicalPlan) (base.PhysicalPlan, bool) {
+	switch x := plan.(type) {
+	case *PointGetPlan:
+		cloned := *x
+		cloned.Plan.SetSCtx(c.sctx)
+		cloned.ctx = c.sctx
+		cloned.schema = c.schema(x.schema)
+		cloned.HandleConstant = c.constant(x.HandleConstant)
+		cloned.IndexValues = cloneDatums(x.IndexValues)
+		cloned.IndexConstants = c.constants(x.IndexConstants)
+		cloned.AccessConditions = c.exprs(x.AccessConditions)
+		cloned.IdxCols = cloneColsForPlanCache(x.IdxCols)
+		cloned.accessCols = cloneColsForPlanCache(x.accessCols)
+		if x.PartitionIdx != nil {
+			partitionIdx := *x.PartitionIdx
+			cloned.PartitionIdx = &partitionIdx
+		}
+		cloned.probeParents = nil
+		return &cloned, true
+	case *BatchPointGetPlan:
+		cloned := *x
+		cloned.Plan.SetSCtx(c.sctx)
+		cloned.ctx = c.sctx
+		cloned.schema = c.schema(x.schema)
+		if x.Handles != nil {
+			cloned.Handles = append(make([]kv.Handle, 0, len(x.Handles)), x.Handles...)
+		}
+		cloned.HandleParams = c.constants(x.HandleParams)
+		if x.IndexValues != nil {
+			cloned.IndexValues = make([][]types.Datum, 0, len(x.IndexValues))
+			for _, values := range x.IndexValues {
+				cloned.IndexValues = append(cloned.IndexValues, cloneDatums(values))
+			}
+		}
+		if x.IndexValueParams != nil {
+			cloned.IndexValueParams = make([][]*expression.Constant, 0, len(x.IndexValueParams))
+			for _, params := range x.IndexValueParams {
+				cloned.IndexValueParams = append(cloned.IndexValueParams, c.constants(params))
+			}
+		}
+		cloned.AccessConditions = c.exprs(x.AccessConditions)
+		cloned.IdxCols = cloneColsForPlanCache(x.IdxCols)
+		cloned.accessCols = cloneColsForPlanCache(x.accessCols)
+		if x.PartitionIdxs != nil {
+			cloned.PartitionIdxs = append(make([]int, 0, len(x.PartitionIdxs)), x.PartitionIdxs...)
+		}
+		cloned.probeParents = nil
+		return &cloned, true
+	case *PhysicalTableReader:
+		if x.StoreType != kv.TiKV || len(x.TableScanAndPartitionInfos) > 0 {
+			return nil, false
+		}
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		tablePlan, ok := c.clone(x.tablePlan)
+		if !ok {
+			return nil, false
+		}
+		cloned.tablePlan = tablePlan
+		cloned.TablePlans = flattenPushDownPlan(tablePlan)
+		cloned.PlanPartInfo = c.partInfo(x.PlanPartInfo)
+		return &cloned, true
+	case *PhysicalIndexReader:
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		indexPlan, ok := c.clone(x.indexPlan)
+		if !ok {
+			return nil, false
+		}
+		cloned.indexPlan = indexPlan
+		cloned.IndexPlans = flattenPushDownPlan(indexPlan)
+		cloned.OutputColumns = cloneColsForPlanCache(x.OutputColumns)
+		cloned.PlanPartInfo = c.partInfo(x.PlanPartInfo)
+		return &cloned, true
+	case *PhysicalIndexLookUpReader:
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		indexPlan, ok := c.clone(x.indexPlan)
+		if !ok {
+			return nil, false
+		}
+		tablePlan, ok := c.clone(x.tablePlan)
+		if !ok {
+			return nil, false
+		}
+		cloned.indexPlan, cloned.tablePlan = indexPlan, tablePlan
+		cloned.IndexPlans = flattenPushDownPlan(indexPlan)
+		cloned.TablePlans = flattenPushDownPlan(tablePlan)
+		if x.ExtraHandleCol != nil {
+			cloned.ExtraHandleCol = x.ExtraHandleCol.Clone().(*expression.Column)
+		}
+		if x.PushedLimit != nil {
+			cloned.PushedLimit = x.PushedLimit.Clone()
+		}
+		cloned.CommonHandleCols = cloneColsForPlanCache(x.CommonHandleCols)
+		cloned.PlanPartInfo = c.partInfo(x.PlanPartInfo)
+		return &cloned, true
+	case *PhysicalTableScan:
+		if len(x.runtimeFilterList) > 0 || x.SampleInfo != nil {
+			return nil, false
+		}
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		cloned.AccessCondition = c.exprs(x.AccessCondition)
+		cloned.filterCondition = c.exprs(x.filterCondition)
+		cloned.LateMaterializationFilterCondition = c.exprs(x.LateMaterializationFilterCondition)
+		cloned.Ranges = cloneRangesForPlanCache(x.Ranges)
+		cloned.ByItems = c.byItems(x.ByItems)
+		cloned.PlanPartInfo = c.partInfo(x.PlanPartInfo)
+		cloned.tblCols = cloneColsForPlanCache(x.tblCols)
+		return &cloned, true
+	case *PhysicalIndexScan:
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		cloned.AccessCondition = c.exprs(x.AccessCondition)
+		cloned.IdxCols = cloneColsForPlanCache(x.IdxCols)
+		cloned.Ranges = cloneRangesForPlanCache(x.Ranges)
+		cloned.SkipScanRanges = cloneRangesForPlanCache(x.SkipScanRanges)
+		cloned.dataSourceSchema = c.schema(x.dataSourceSchema)
+		if x.GenExprs != nil {
+			cloned.GenExprs = make(map[model.TableItemID]expression.Expression, len(x.GenExprs))
+			for id, expr := range x.GenExprs {
+				cloned.GenExprs[id] = c.expr(expr)
+			}
+		}
+		cloned.ByItems = c.byItems(x.ByItems)
+		if x.pkIsHandleCol != nil {
+			cloned.pkIsHandleCol = x.pkIsHandleCol.Clone().(*expression.Column)
+		}
+		return &cloned, true
+	case *PhysicalSelection:
+		cloned := *x
+		if !c.basePhysicalPlan(&cloned.basePhysicalPlan, &x.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.Conditions = c.exprs(x.Conditions)
+		return &cloned, true
+	case *PhysicalProjection:
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		cloned.Exprs = c.exprs(x.Exprs)
+		return &cloned, true
+	case *PhysicalLimit:
+		cloned := *x
+		if !c.schemaProducer(&cloned.physicalSchemaProducer, &x.physicalSchemaProducer, &cloned) {
+			return nil, false
+		}
+		return &cloned, true
+	case *PhysicalTopN:
+		cloned := *x
+		if !c.basePhysicalPlan(&cloned.basePhysicalPlan, &x.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.ByItems = c.byItems(x.ByItems)
+		return &cloned, true
+	case *PhysicalSort:
+		cloned := *x
+		if !c.basePhysicalPlan(&cloned.basePhysicalPlan, &x.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.ByItems = c.byItems(x.ByItems)
+		return &cloned, true
+	case *PhysicalHashAgg:
+		cloned := *x
+		if !c.basePhysicalAgg(&cloned.basePhysicalAgg, &x.basePhysicalAgg, &cloned) {
+			return nil, false
+		}
+		return &cloned, true
+	case *PhysicalStreamAgg:
+		cloned := *x
+		if !c.basePhysicalAgg(&cloned.basePhysicalAgg, &x.basePhysicalAgg, &cloned) {
+			return nil, false
+		}
+		return &cloned, true
+	}
+	return nil, false
+}
+
+// basePhysicalPlan fixes the shallow copy dst of src, which is embedded in self.
+func (c *planCloner) basePhysicalPlan(dst, src *basePhysicalPlan, self base.PhysicalPlan) bool {
+	dst.Plan.SetSCtx(c.sctx)
+	dst.self = self
+	dst.probeParents = nil
+	if src.children != nil {
+		dst.children = make([]base.PhysicalPlan, 0, len(src.children))
+		for _, child := range src.children {
+			cloned, ok := c.clone(child)
+			if !ok {
+				return false
+			}
+			dst.children = append(dst.children, cloned)
+		}
+	}
+	return true
+}
+
+func (c *planCloner) schemaProducer(dst, src *physicalSchemaProducer, self base.PhysicalPlan) bool {
+	if !c.basePhysicalPlan(&dst.basePhysicalPlan, &src.basePhysicalPlan, self) {
+		return false
+	}
+	dst.schema = c.schema(src.schema)
+	return true
+}
+
+func (c *planCloner) basePhysicalAgg(dst, src *basePhysicalAgg, self base.PhysicalPlan) bool {
+	if len(src.MppPartitionCols) > 0 {
+		return false
+	}
+	if !c.schemaProducer(&dst.physicalSchemaProducer, &src.physicalSchemaProducer, self) {
+		return false
+	}
+	if src.AggFuncs != nil {
+		dst.AggFuncs = make([]*aggregation.AggFuncDesc, 0, len(src.AggFuncs))
+		for _, aggFunc := range src.AggFuncs {
+			cloned := aggFunc.Clone()
+			cloned.Args = c.exprs(aggFunc.Args)
+			cloned.OrderByItems = c.byItems(aggFunc.OrderByItems)
+			dst.AggFuncs = append(dst.AggFuncs, cloned)
+		}
+	}
+	dst.GroupByItems = c.exprs(src.GroupByItems)
+	return true
+}
+
+func (*planCloner) schema(schema *expression.Schema) *expression.Schema {
+	if schema == nil {
+		return nil
+	}
+	return schema.Clone()
+}
+
+func (c *planCloner) expr(expr expression.Expression) expression.Expression {
+	return expression.CloneExprForPlanCache(expr, c.sctx)
+}
+
+func (c *planCloner) exprs(exprs []expression.Expression) []expression.Expression {
+	return expression.CloneExprsForPlanCache(exprs, c.sctx)
+}
+
+func (c *planCloner) constant(con *expression.Constant) *expression.Constant {
+	if con == nil {
+		return nil
+	}
+	return c.expr(con).(*expression.Constant)
+}
+
+func (c *planCloner) constants(cons []*expression.Constant) []*expression.Constant {
+	if cons == nil {
+		return nil
+	}
+	cloned := make([]*expression.Constant, 0, len(cons))
+	for _, con := range cons {
+		cloned = append(cloned, c.constant(con))
+	}
+	return cloned
+}
+
+func (c *planCloner) byItems(items []*util.ByItems) []*util.ByItems {
+	if items == nil {
+		return nil
+	}
+	cloned := make([]*util.ByItems, 0, len(items))
+	for _, item := range items {
+		cloned = append(cloned, &util.ByItems{Expr: c.expr(item.Expr), Desc: item.Desc})
+	}
+	return cloned
+}
+
+func (c *planCloner) partInfo(info PhysPlanPartInfo) PhysPlanPartInfo {
+	info.PruningConds = c.exprs(info.PruningConds)
+	info.Columns = cloneColsForPlanCache(info.Columns)
+	return info
+}
+
+// cloneColsForPlanCache is like util.CloneCols, but it keeps the nil slice nil.
+func cloneColsForPlanCache(cols []*expression.Column) []*expression.Column {
+	if cols == nil {
+		return nil
+	}
+	return util.CloneCols(cols)
+}
+
+func cloneRangesForPlanCache(ranges []*ranger.Range) []*ranger.Range {
+	if ranges == nil {
+		return nil
+	}
+	return util.CloneRanges(ranges)
+}
+
+func cloneDatums(datums []types.Datum) []types.Datum {
+	if datums == nil {
+		return nil
+	}
+	cloned := make([]types.Datum, len(datums))
+	for i := range datums {
+		datums[i].Copy(&cloned[i])
+	}
+	return cloned
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/stretchr/testify/require"
+)
+
+type planClonerFieldKind int
+
+const (
+	// clonedField is deep copied or reset by the cloner.
+	clonedField planClonerFieldKind = iota
+	// sharedField is shared by the clones since it isn't modified after the plan is built.
+	sharedField
+	// rejectedField makes the cloner give up cloning the plan if it's set.
+	rejectedField
+)
+
+// planClonerTypes are the plans cloned by planCloner.clone.
+var planClonerTypes = []base.PhysicalPlan{
+	&PointGetPlan{},
+	&BatchPointGetPlan{},
+	&PhysicalTableReader{},
+	&PhysicalIndexReader{},
+	&PhysicalIndexLookUpReader{},
+	&PhysicalTableScan{},
+	&PhysicalIndexScan{},
+	&PhysicalSelection{},
+	&PhysicalProjection{},
+	&PhysicalLimit{},
+	&PhysicalTopN{},
+	&PhysicalSort{},
+	&PhysicalHashAgg{},
+	&PhysicalStreamAgg{},
+}
+
+// planClonerFields are the fields of planClonerTypes which may share memory with the original plan after a shallow
+// copy, keyed by "<struct>.<field>" where the struct is the one declaring the field. The fields of the scalar types
+// are copied by the shallow copy, so they aren't listed.
+var planClonerFields = map[string]planClonerFieldKind{
+	"Plan.ctx":   clonedField,
+	"Plan.stats": sharedField,
+
+	"basePhysicalPlan.childrenReqProps": sharedField,
+	"basePhysicalPlan.self":             clonedField,
+	"basePhysicalPlan.children":         clonedField,
+	"basePhysicalPlan.planCostVer2":     sharedField,
+	"basePhysicalPlan.probeParents":     clonedField,
+
+	"physicalSchemaProducer.schema": clonedField,
+	"baseSchemaProducer.schema":     clonedField,
+	"baseSchemaProducer.names":      sharedField,
+
+	"PointGetPlan.ctx":              clonedField,
+	"PointGetPlan.schema":           clonedField,
+	"PointGetPlan.TblInfo":          sharedField,
+	"PointGetPlan.IndexInfo":        sharedField,
+	"PointGetPlan.PartitionIdx":     clonedField,
+	"PointGetPlan.Handle":           sharedField,
+	"PointGetPlan.HandleConstant":   clonedField,
+	"PointGetPlan.handleFieldType":  sharedField,
+	"PointGetPlan.IndexValues":      clonedField,
+	"PointGetPlan.IndexConstants":   clonedField,
+	"PointGetPlan.ColsFieldType":    sharedField,
+	"PointGetPlan.IdxCols":          clonedField,
+	"PointGetPlan.IdxColLens":       sharedField,
+	"PointGetPlan.AccessConditions": clonedField,
+	"PointGetPlan.outputNames":      sharedField,
+	"PointGetPlan.Columns":          sharedField,
+	"PointGetPlan.planCostVer2":     sharedField,
+	"PointGetPlan.accessCols":       clonedField,
+	"PointGetPlan.probeParents":     clonedField,
+	"PointGetPlan.PartitionNames":   sharedField,
+
+	"BatchPointGetPlan.ctx":              clonedField,
+	"BatchPointGetPlan.TblInfo":          sharedField,
+	"BatchPointGetPlan.IndexInfo":        sharedField,
+	"BatchPointGetPlan.Handles":          clonedField,
+	"BatchPointGetPlan.HandleType":       sharedField,
+	"BatchPointGetPlan.HandleParams":     clonedField,
+	"BatchPointGetPlan.IndexValues":      clonedField,
+	"BatchPointGetPlan.IndexValueParams": clonedField,
+	"BatchPointGetPlan.IndexColTypes":    sharedField,
+	"BatchPointGetPlan.AccessConditions": clonedField,
+	"BatchPointGetPlan.IdxCols":          clonedField,
+	"BatchPointGetPlan.IdxColLens":       sharedField,
+	"BatchPointGetPlan.PartitionIdxs":    clonedField,
+	"BatchPointGetPlan.Columns":          sharedField,
+	"BatchPointGetPlan.planCostVer2":     sharedField,
+	"BatchPointGetPlan.accessCols":       clonedField,
+	"BatchPointGetPlan.probeParents":     clonedField,
+	"BatchPointGetPlan.PartitionNames":   sharedField,
+
+	"PhysicalTableReader.TablePlans":                 clonedField,
+	"PhysicalTableReader.tablePlan":                  clonedField,
+	"PhysicalTableReader.PlanPartInfo":               clonedField,
+	"PhysicalTableReader.TableScanAndPartitionInfos": rejectedField,
+
+	"PhysicalIndexReader.IndexPlans":    clonedField,
+	"PhysicalIndexReader.indexPlan":     clonedField,
+	"PhysicalIndexReader.OutputColumns": clonedField,
+	"PhysicalIndexReader.PlanPartInfo":  clonedField,
+
+	"PhysicalIndexLookUpReader.IndexPlans":       clonedField,
+	"PhysicalIndexLookUpReader.TablePlans":       clonedField,
+	"PhysicalIndexLookUpReader.indexPlan":        clonedField,
+	"PhysicalIndexLookUpReader.tablePlan":        clonedField,
+	"PhysicalIndexLookUpReader.ExtraHandleCol":   clonedField,
+	"PhysicalIndexLookUpReader.PushedLimit":      clonedField,
+	"PhysicalIndexLookUpReader.CommonHandleCols": clonedField,
+	"PhysicalIndexLookUpReader.PlanPartInfo":     clonedField,
+
+	"PhysicalTableScan.AccessCondition":                    clonedField,
+	"PhysicalTableScan.filterCondition":                    clonedField,
+	"PhysicalTableScan.LateMaterializationFilterCondition": clonedField,
+	"PhysicalTableScan.Table":                              sharedField,
+	"PhysicalTableScan.Columns":                            sharedField,
+	"PhysicalTableScan.DBName":                             sharedField,
+	"PhysicalTableScan.Ranges":                             clonedField,
+	"PhysicalTableScan.TableAsName":                        sharedField,
+	"PhysicalTableScan.HandleIdx":                          sharedField,
+	"PhysicalTableScan.HandleCols":                         sharedField,
+	"PhysicalTableScan.ByItems":                            clonedField,
+	"PhysicalTableScan.PlanPartInfo":                       clonedField,
+	"PhysicalTableScan.SampleInfo":                         rejectedField,
+	"PhysicalTableScan.tblCols":                            clonedField,
+	"PhysicalTableScan.tblColHists":                        sharedField,
+	"PhysicalTableScan.prop":                               sharedField,
+	"PhysicalTableScan.constColsByCond":                    sharedField,
+	"PhysicalTableScan.usedStatsInfo":                      sharedField,
+	"PhysicalTableScan.runtimeFilterList":                  rejectedField,
+
+	"PhysicalIndexScan.AccessCondition":  clonedField,
+	"PhysicalIndexScan.Table":            sharedField,
+	"PhysicalIndexScan.Index":            sharedField,
+	"PhysicalIndexScan.IdxCols":          clonedField,
+	"PhysicalIndexScan.IdxColLens":       sharedField,
+	"PhysicalIndexScan.Ranges":           clonedField,
+	"PhysicalIndexScan.Columns":          sharedField,
+	"PhysicalIndexScan.DBName":           sharedField,
+	"PhysicalIndexScan.TableAsName":      sharedField,
+	"PhysicalIndexScan.SkipScanRanges":   clonedField,
+	"PhysicalIndexScan.dataSourceSchema": clonedField,
+	"PhysicalIndexScan.GenExprs":         clonedField,
+	"PhysicalIndexScan.ByItems":          clonedField,
+	"PhysicalIndexScan.tblColHists":      sharedField,
+	"PhysicalIndexScan.pkIsHandleCol":    clonedField,
+	"PhysicalIndexScan.constColsByCond":  sharedField,
+	"PhysicalIndexScan.prop":             sharedField,
+	"PhysicalIndexScan.usedStatsInfo":    sharedField,
+
+	"PhysicalSelection.Conditions": clonedField,
+	"PhysicalProjection.Exprs":     clonedField,
+	"PhysicalLimit.PartitionBy":    sharedField,
+	"PhysicalTopN.ByItems":         clonedField,
+	"PhysicalTopN.PartitionBy":     sharedField,
+	"PhysicalSort.ByItems":         clonedField,
+
+	"basePhysicalAgg.AggFuncs":         clonedField,
+	"basePhysicalAgg.GroupByItems":     clonedField,
+	"basePhysicalAgg.MppPartitionCols": rejectedField,
+}
+
+func collectPlanClonerFields(tp reflect.Type, fields map[string]struct{}) {
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectPlanClonerFields(field.Type, fields)
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64,
+			reflect.String:
+			continue
+		}
+		fields[tp.Name()+"."+field.Name] = struct{}{}
+	}
+}
+
+// TestPlanClonerFields fails when a field is added to the plans cloned by planCloner, so the field has to be cloned
+// by the cloner, or confirmed to be safe to share, before it's listed in planClonerFields.
+func TestPlanClonerFields(t *testing.T) {
+	fields := make(map[string]struct{})
+	for _, plan := range planClonerTypes {
+		collectPlanClonerFields(reflect.TypeOf(plan).Elem(), fields)
+	}
+	for field := range fields {
+		_, ok := planClonerFields[field]
+		require.True(t, ok, "%s isn't covered by planCloner, clone it or confirm it can be shared, then list it in planClonerFields", field)
+	}
+	for field := range planClonerFields {
+		_, ok := fields[field]
+		require.True(t, ok, "%s in planClonerFields doesn't exist anymore", field)
+	}
+}
+
+// TestPlanClonerTypes checks that planClonerTypes are the plans handled by planCloner.clone, so a plan supported by the
+// cloner has to be reviewed by TestPlanClonerFields. The other plans aren't put into the instance plan cache.
+func TestPlanClonerTypes(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "plan_cache_clone.go", nil, 0)
+	require.NoError(t, err)
+	var cloned []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "clone" || fn.Recv == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			clause, ok := node.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, expr := range clause.List {
+				if star, ok := expr.(*ast.StarExpr); ok {
+					cloned = append(cloned, star.X.(*ast.Ident).Name)
+				}
+			}
+			return false
+		})
+	}
+	expected := make([]string, 0, len(planClonerTypes))
+	for _, plan := range planClonerTypes {
+		expected = append(expected, reflect.TypeOf(plan).Elem().Name())
+	}
+	sort.Strings(cloned)
+	sort.Strings(expected)
+	require.Equal(t, expected, cloned)
+}
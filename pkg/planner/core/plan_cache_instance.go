@@ -0,0 +1,267 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	core_metrics "github.com/pingcap/tidb/pkg/planner/core/metrics"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/hack"
+	"github.com/pingcap/tidb/pkg/util/kvcache"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	utilpc "github.com/pingcap/tidb/pkg/util/plancache"
+)
+
+// instancePlanCacheEntry is the value of list.Element in instancePlanCache.
+// The key and the value of an entry are never changed, a new entry replaces the old one of the element instead, so
+// they can be read without holding the lock after the entry is loaded from the element.
+type instancePlanCacheEntry struct {
+	key            string
+	value          *PlanCacheValue
+	memUsage       int64
+	hits           int64
+	loadTime       time.Time
+	lastActiveTime time.Time
+}
+
+// instancePlanCache is the least recently used plan cache shared by all sessions of the instance.
+// Its memory usage is limited by tidb_instance_plan_cache_max_size.
+type instancePlanCache struct {
+	// mu protects the buckets, the LRU list and the statistics of the entries. Get only holds the read lock to find
+	// the candidates and match them, the write lock is held briefly to update the LRU list after a hit.
+	mu sync.RWMutex
+	// buckets maps the key to all plans of the key, the plans of the same key can differ in the param types and so on.
+	buckets    map[string]map[*list.Element]struct{}
+	lruList    *list.List
+	memTracker *memory.Tracker
+
+	hits            atomic.Int64
+	misses          atomic.Int64
+	evictions       int64
+	evictedMemUsage int64
+}
+
+// NewInstancePlanCache creates the plan cache shared by all sessions of the instance.
+func NewInstancePlanCache() sessionctx.InstancePlanCache {
+	return &instancePlanCache{
+		buckets:    make(map[string]map[*list.Element]struct{}),
+		lruList:    list.New(),
+		memTracker: memory.NewTracker(memory.LabelForInstancePlanCache, -1),
+	}
+}
+
+// Get implements the sessionctx.InstancePlanCache interface.
+func (c *instancePlanCache) Get(key string, match func(value any) bool) (value any, ok bool) {
+	c.mu.RLock()
+	bucket := c.buckets[key]
+	elements := make([]*list.Element, 0, len(bucket))
+	entries := make([]*instancePlanCacheEntry, 0, len(bucket))
+	for element := range bucket {
+		elements = append(elements, element)
+		entries = append(entries, element.Value.(*instancePlanCacheEntry))
+	}
+	c.mu.RUnlock()
+
+	for i, entry := range entries {
+		if !match(entry.value) {
+			continue
+		}
+		c.hits.Add(1)
+		c.mu.Lock()
+		// The entry may be evicted or replaced by another session after the read lock is released, then there is
+		// nothing to update, but the matched plan can still be used.
+		if element := elements[i]; element.Value == entry {
+			if _, ok := c.buckets[key][element]; ok {
+				entry.hits++
+				entry.lastActiveTime = time.Now()
+				c.lruList.MoveToFront(element)
+			}
+		}
+		c.mu.Unlock()
+		return entry.value, true
+	}
+	c.misses.Add(1)
+	return nil, false
+}
+
+// Put implements the sessionctx.InstancePlanCache interface.
+func (c *instancePlanCache) Put(key string, value any, match func(value any) bool) bool {
+	v := value.(*PlanCacheValue)
+	memUsage := int64(len(key)) + v.MemoryUsage()
+	capacity := variable.InstancePlanCacheMaxMemSize.Load()
+	if memUsage > capacity { // ignore the plan if it's too large
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	newEntry := &instancePlanCacheEntry{
+		key:            key,
+		value:          v,
+		memUsage:       memUsage,
+		loadTime:       now,
+		lastActiveTime: now,
+	}
+	bucket, ok := c.buckets[key]
+	if !ok {
+		bucket = make(map[*list.Element]struct{}, 1)
+		c.buckets[key] = bucket
+	}
+	var replaced bool
+	for element := range bucket {
+		// Several sessions may miss the cache and build the plans of the same statement concurrently, the later
+		// one replaces the plan put by the earlier one, rather than keeping several plans for the same usage.
+		if entry := element.Value.(*instancePlanCacheEntry); match(entry.value) {
+			newEntry.hits = entry.hits
+			element.Value = newEntry
+			c.lruList.MoveToFront(element)
+			c.memTracker.Consume(memUsage - entry.memUsage)
+			core_metrics.GetInstancePlanCacheMemoryUsage().Add(float64(memUsage - entry.memUsage))
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		bucket[c.lruList.PushFront(newEntry)] = struct{}{}
+		c.memTracker.Consume(memUsage)
+		core_metrics.GetInstancePlanCachePlanNumCounter().Add(1)
+		core_metrics.GetInstancePlanCacheMemoryUsage().Add(float64(memUsage))
+	}
+
+	for c.memTracker.BytesConsumed() > capacity {
+		oldest := c.lruList.Back()
+		c.evictions++
+		c.evictedMemUsage += oldest.Value.(*instancePlanCacheEntry).memUsage
+		c.remove(oldest)
+	}
+	return true
+}
+
+// DeleteAll implements the sessionctx.InstancePlanCache interface.
+func (c *instancePlanCache) DeleteAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	core_metrics.GetInstancePlanCachePlanNumCounter().Sub(float64(c.lruList.Len()))
+	core_metrics.GetInstancePlanCacheMemoryUsage().Sub(float64(c.memTracker.BytesConsumed()))
+	c.buckets = make(map[string]map[*list.Element]struct{})
+	c.lruList = list.New()
+	c.memTracker.Consume(-c.memTracker.BytesConsumed())
+}
+
+// Entries implements the sessionctx.InstancePlanCache interface.
+func (c *instancePlanCache) Entries() []utilpc.InstancePlanCacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]utilpc.InstancePlanCacheEntry, 0, c.lruList.Len())
+	for element := c.lruList.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*instancePlanCacheEntry)
+		var planDigest string
+		if entry.value.planDigest != nil {
+			planDigest = entry.value.planDigest.String()
+		}
+		entries = append(entries, utilpc.InstancePlanCacheEntry{
+			SchemaName:     entry.value.stmtDB,
+			SQLText:        entry.value.stmtText,
+			PlanDigest:     planDigest,
+			MemUsage:       entry.memUsage,
+			Hits:           entry.hits,
+			LoadTime:       entry.loadTime,
+			LastActiveTime: entry.lastActiveTime,
+		})
+	}
+	return entries
+}
+
+// Stats implements the sessionctx.InstancePlanCache interface.
+func (c *instancePlanCache) Stats() utilpc.InstancePlanCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return utilpc.InstancePlanCacheStats{
+		Plans:           int64(c.lruList.Len()),
+		MemUsage:        c.memTracker.BytesConsumed(),
+		MemCapacity:     variable.InstancePlanCacheMaxMemSize.Load(),
+		Hits:            c.hits.Load(),
+		Misses:          c.misses.Load(),
+		Evictions:       c.evictions,
+		EvictedMemUsage: c.evictedMemUsage,
+	}
+}
+
+// remove removes the element from the cache, the caller should hold the lock.
+func (c *instancePlanCache) remove(element *list.Element) {
+	entry := element.Value.(*instancePlanCacheEntry)
+	c.lruList.Remove(element)
+	bucket := c.buckets[entry.key]
+	delete(bucket, element)
+	if len(bucket) == 0 {
+		delete(c.buckets, entry.key)
+	}
+	c.memTracker.Consume(-entry.memUsage)
+	core_metrics.GetInstancePlanCachePlanNumCounter().Sub(1)
+	core_metrics.GetInstancePlanCacheMemoryUsage().Sub(float64(entry.memUsage))
+}
+
+// planAffectingVarNames returns the names of the session variables which can affect the plan but aren't in
+// planCacheKey. The plans built by the sessions with different values of them can't be shared.
+var planAffectingVarNames = sync.OnceValue(func() []string {
+	names := []string{variable.TiDBEnableIndexMerge, variable.TiDBPartitionPruneMode}
+	for name, sv := range variable.GetSysVars() {
+		if strings.HasPrefix(name, "tidb_opt_") && sv.HasSessionScope() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+})
+
+// instancePlanCacheKey makes the key of the instance plan cache from the key of the session plan cache.
+// The connection ID is excluded, and the variables which can affect the plan are included.
+func instancePlanCacheKey(key kvcache.Key, vars *variable.SessionVars) string {
+	k := *key.(*planCacheKey)
+	k.connID = 0
+	k.hash = nil
+	k.memoryUsage = 0
+	hash := k.Hash()
+	for _, name := range planAffectingVarNames() {
+		val, _ := vars.GetSystemVar(name)
+		hash = append(hash, hack.Slice(val)...)
+		hash = append(hash, 0)
+	}
+	return string(hash)
+}
+
+// useInstancePlanCache checks whether the statement can use the plan cache shared by all sessions.
+func useInstancePlanCache(sctx sessionctx.Context, stmt *PlanCacheStmt) bool {
+	if !variable.EnableInstancePlanCache.Load() || !sctx.GetSessionVars().StmtCtx.UseCache() {
+		return false
+	}
+	for _, tbl := range stmt.tbls {
+		// local temporary tables are only visible to the session.
+		if tbl.Meta().TempTableType == model.TempTableLocal {
+			return false
+		}
+	}
+	return domain.GetDomain(sctx).InstancePlanCache() != nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/stretchr/testify/require"
+)
+
+func newInstancePlanCacheTestValue(stmtText string, memUsage int64) *PlanCacheValue {
+	return &PlanCacheValue{
+		Plan:        &PhysicalTableDual{},
+		memoryUsage: memUsage,
+		stmtDB:      "test",
+		stmtText:    stmtText,
+	}
+}
+
+func TestInstancePlanCacheEviction(t *testing.T) {
+	origin := variable.InstancePlanCacheMaxMemSize.Load()
+	defer variable.InstancePlanCacheMaxMemSize.Store(origin)
+	variable.InstancePlanCacheMaxMemSize.Store(300)
+
+	cache := NewInstancePlanCache()
+	matchAll := func(any) bool { return true }
+	require.True(t, cache.Put("k1", newInstancePlanCacheTestValue("q1", 98), matchAll))
+	require.True(t, cache.Put("k2", newInstancePlanCacheTestValue("q2", 98), matchAll))
+	require.False(t, cache.Put("k3", newInstancePlanCacheTestValue("q3", 1000), matchAll)) // too large
+
+	v, ok := cache.Get("k1", matchAll)
+	require.True(t, ok)
+	require.Equal(t, "q1", v.(*PlanCacheValue).stmtText)
+	_, ok = cache.Get("k1", func(any) bool { return false })
+	require.False(t, ok)
+	_, ok = cache.Get("k3", matchAll)
+	require.False(t, ok)
+
+	// k2 is the least recently used one, so it's evicted.
+	require.True(t, cache.Put("k4", newInstancePlanCacheTestValue("q4", 148), matchAll))
+	_, ok = cache.Get("k2", matchAll)
+	require.False(t, ok)
+
+	entries := cache.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, "q4", entries[0].SQLText)
+	require.Equal(t, "q1", entries[1].SQLText)
+	require.Equal(t, int64(1), entries[1].Hits)
+	require.Equal(t, "test", entries[1].SchemaName)
+
+	stats := cache.Stats()
+	require.Equal(t, int64(2), stats.Plans)
+	require.Equal(t, int64(250), stats.MemUsage)
+	require.Equal(t, int64(300), stats.MemCapacity)
+	require.Equal(t, int64(1), stats.Hits)
+	require.Equal(t, int64(3), stats.Misses)
+	require.Equal(t, int64(1), stats.Evictions)
+	require.Equal(t, int64(100), stats.EvictedMemUsage)
+
+	cache.DeleteAll()
+	stats = cache.Stats()
+	require.Equal(t, int64(0), stats.Plans)
+	require.Equal(t, int64(0), stats.MemUsage)
+	require.Empty(t, cache.Entries())
+}
+
+func TestInstancePlanCacheSameKey(t *testing.T) {
+	cache := NewInstancePlanCache()
+	matchNone := func(any) bool { return false }
+	require.True(t, cache.Put("k", newInstancePlanCacheTestValue("q1", 10), matchNone))
+	require.True(t, cache.Put("k", newInstancePlanCacheTestValue("q2", 10), matchNone))
+	for _, text := range []string{"q1", "q2"} {
+		v, ok := cache.Get("k", func(value any) bool { return value.(*PlanCacheValue).stmtText == text })
+		require.True(t, ok)
+		require.Equal(t, text, v.(*PlanCacheValue).stmtText)
+	}
+	require.Equal(t, int64(2), cache.Stats().Plans)
+
+	// The plan matching the new one is replaced.
+	matchQ1 := func(value any) bool { return value.(*PlanCacheValue).stmtText == "q1" }
+	require.True(t, cache.Put("k", newInstancePlanCacheTestValue("q3", 20), matchQ1))
+	_, ok := cache.Get("k", matchQ1)
+	require.False(t, ok)
+	entries := cache.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, "q3", entries[0].SQLText)
+	require.Equal(t, int64(1), entries[0].Hits)
+	require.Equal(t, "q2", entries[1].SQLText)
+	stats := cache.Stats()
+	require.Equal(t, int64(2), stats.Plans)
+	require.Equal(t, int64(32), stats.MemUsage)
+}
+
+func TestInstancePlanCacheConcurrency(t *testing.T) {
+	cache := NewInstancePlanCache()
+	matchText := func(text string) func(any) bool {
+		return func(value any) bool { return value.(*PlanCacheValue).stmtText == text }
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				text := fmt.Sprintf("q%d", (i+j)%4)
+				if _, ok := cache.Get("k", matchText(text)); !ok {
+					require.True(t, cache.Put("k", newInstancePlanCacheTestValue(text, 10), matchText(text)))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	// The plans put by the sessions which miss the cache concurrently replace each other.
+	stats := cache.Stats()
+	require.Equal(t, int64(4), stats.Plans)
+	require.Equal(t, int64(1600), stats.Hits+stats.Misses)
+}
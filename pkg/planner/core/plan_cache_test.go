@@ -1797,3 +1797,56 @@ partition by hash (a) partitions 3`)
 	require.False(t, tk.Session().GetSessionVars().FoundInPlanCache)
 	tk.MustQuery(`show warnings`).Check(testkit.Rows("Warning 1105 skip prepared plan-cache: query accesses partitioned tables is un-cacheable if tidb_partition_pruning_mode = 'static'"))
 }
+
+func TestInstancePlanCache(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec(`use test`)
+	tk1.MustExec(`create table t (a int, b int, c int, primary key(a), key(b))`)
+	tk1.MustExec(`insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)`)
+	tk1.MustExec(`set global tidb_enable_instance_plan_cache=1`)
+	defer tk1.MustExec(`set global tidb_enable_instance_plan_cache=default`)
+	tk1.MustExec(`admin flush instance plan_cache`)
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec(`use test`)
+
+	for _, c := range []struct {
+		query  string
+		result []string
+	}{
+		{`select * from t where a = ?`, []string{"2 2 2"}},
+		{`select * from t where a in (?, 3)`, []string{"2 2 2", "3 3 3"}},
+		{`select * from t where b = ?`, []string{"2 2 2"}},
+		{`select a from t where b > ? order by a limit 10`, []string{"3"}},
+		{`select sum(c) from t where a < ?`, []string{"1"}},
+	} {
+		tk1.MustExec(fmt.Sprintf(`prepare st from '%s'`, c.query))
+		tk1.MustExec(`set @x = 1`)
+		tk1.MustExec(`execute st using @x`)
+		tk1.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("0"))
+
+		// the plan built by tk1 is reused by tk2 with different parameters.
+		tk2.MustExec(fmt.Sprintf(`prepare st from '%s'`, c.query))
+		tk2.MustExec(`set @x = 2`)
+		tk2.MustQuery(`execute st using @x`).Sort().Check(testkit.Rows(c.result...))
+		tk2.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+	}
+	tk1.MustQuery(`select plans, hits, evictions from information_schema.instance_plan_cache_stats`).Check(testkit.Rows("5 5 0"))
+	tk1.MustQuery(`select schema_name, hits from information_schema.instance_plan_cache where sql_text = 'select * from t where b = ?'`).Check(testkit.Rows("test 1"))
+
+	// the plans can't be shared by the sessions with different optimizer variables.
+	tk2.MustExec(`set @@tidb_opt_prefer_range_scan=1`)
+	tk2.MustExec(`execute st using @x`)
+	tk2.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("0"))
+	tk2.MustExec(`set @@tidb_opt_prefer_range_scan=default`)
+
+	// the plans of local temporary tables aren't shared.
+	tk1.MustExec(`create temporary table tmp (a int primary key)`)
+	tk1.MustExec(`prepare st from 'select * from tmp where a = ?'`)
+	tk1.MustExec(`execute st using @x`)
+	tk1.MustExec(`execute st using @x`)
+	tk1.MustQuery(`select count(*) from information_schema.instance_plan_cache where sql_text like '%tmp%'`).Check(testkit.Rows("0"))
+
+	tk1.MustExec(`admin flush instance plan_cache`)
+	tk1.MustQuery(`select plans, mem_usage from information_schema.instance_plan_cache_stats`).Check(testkit.Rows("0 0"))
+}
@@ -402,6 +402,12 @@ type PlanCacheValue struct {
 	matchOpts *utilpc.PlanCacheMatchOpts
 	// stmtHints stores the hints which set session variables, because the hints won't be processed using cached plan.
 	stmtHints *hint.StmtHints
+
+	// below fields are only set for the plans in the instance plan cache, which are shared by the sessions.
+	stmtDB         string
+	stmtText       string
+	normalizedPlan string
+	planDigest     *parser.Digest
 }
 
 // unKnownMemoryUsage represent the memory usage of uncounted structure, maybe need implement later
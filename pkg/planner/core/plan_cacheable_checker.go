@@ -577,6 +577,16 @@ func isPhysicalPlanCacheable(sctx base.PlanContext, p base.PhysicalPlan, paramNu
 		return false, "get a Shuffle plan"
 	case *PhysicalMemTable:
 		return false, "PhysicalMemTable plan is un-cacheable"
+	case *PhysicalIndexReader:
+		if x.IndexPlans[0].(*PhysicalIndexScan).SkipScanRanges != nil {
+			return false, "the index skip scan plan is un-cacheable"
+		}
+	case *PhysicalIndexLookUpReader:
+		if x.IndexPlans[0].(*PhysicalIndexScan).SkipScanRanges != nil {
+			return false, "the index skip scan plan is un-cacheable"
+		}
+	case *PhysicalLooseIndexScan:
+		return false, "the loose index scan plan is un-cacheable"
 	case *PhysicalIndexMergeReader:
 		if x.AccessMVIndex && !enablePlanCacheForGeneratedCols(sctx) {
 			return false, "the plan with IndexMerge accessing Multi-Valued Index is un-cacheable"
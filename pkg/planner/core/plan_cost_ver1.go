@@ -459,11 +459,34 @@ func (p *PhysicalIndexScan) GetPlanCostVer1(_ property.TaskType, option *optimiz
 	if option.GetTracer() != nil {
 		setPhysicalTableOrIndexScanCostDetail(p, option.GetTracer(), rowCount, rowSize, scanFactor, costModelVersion)
 	}
+	if p.SkipScanRanges != nil {
+		// The skip scan seeks the distinct values of the leading column one by one.
+		selfCost += (p.skipScanNDV + 1) * p.SCtx().GetSessionVars().GetSeekFactor(p.Table)
+	}
 	p.planCost = selfCost
 	p.planCostInit = true
 	return p.planCost, nil
 }
 
+// GetPlanCostVer1 calculates the cost of the plan if it has not been calculated yet and returns the cost.
+func (p *PhysicalLooseIndexScan) GetPlanCostVer1(_ property.TaskType, option *optimizetrace.PlanCostOption) (float64, error) {
+	costFlag := option.CostFlag
+	if p.planCostInit && !hasCostFlag(costFlag, costusage.CostFlagRecalculate) {
+		return p.planCost, nil
+	}
+
+	sessVars := p.SCtx().GetSessionVars()
+	ndv := getCardinality(p, costFlag)
+	seeksPerPrefix := 1.0
+	if _, hasMax := p.HasMinMax(); hasMax {
+		seeksPerPrefix++
+	}
+	p.planCost = (ndv+1)*seeksPerPrefix*sessVars.GetSeekFactor(p.IndexScan.Table) +
+		ndv*getAvgRowSize(p.StatsInfo(), p.schema.Columns)*sessVars.GetNetworkFactor(p.IndexScan.Table)
+	p.planCostInit = true
+	return p.planCost, nil
+}
+
 // GetCost computes the cost of index join operator and its children.
 func (p *PhysicalIndexJoin) GetCost(outerCnt, innerCnt, outerCost, innerCost float64, costFlag uint64) float64 {
 	var cpuCost float64
@@ -124,7 +124,34 @@ func (p *PhysicalProjection) GetPlanCostVer2(taskType property.TaskType, option
 }
 
 // GetPlanCostVer2 returns the plan-cost of this sub-plan, which is:
-// plan-cost = rows * log2(row-size) * scan-factor
+// plan-cost = seek-cost + net-cost
+// seek-cost = (ndv + 1) * seeks-per-prefix * request-factor
+// net-cost = ndv * row-size * net-factor
+func (p *PhysicalLooseIndexScan) GetPlanCostVer2(taskType property.TaskType, option *optimizetrace.PlanCostOption) (costusage.CostVer2, error) {
+	if p.planCostInit && !hasCostFlag(option.CostFlag, costusage.CostFlagRecalculate) {
+		return p.planCostVer2, nil
+	}
+
+	ndv := getCardinality(p, option.CostFlag)
+	rowSize := getAvgRowSize(p.StatsInfo(), p.schema.Columns)
+	requestFactor := getTaskRequestFactorVer2(p, taskType)
+	netFactor := getTaskNetFactorVer2(p, taskType)
+
+	// The prefix is sought first, then the MAX is sought backward in the prefix if needed. One more seek is needed to
+	// find there's no more prefix.
+	seeksPerPrefix := 1.0
+	if _, hasMax := p.HasMinMax(); hasMax {
+		seeksPerPrefix++
+	}
+	p.planCostVer2 = costusage.SumCostVer2(seekCostVer2(option, (ndv+1)*seeksPerPrefix, requestFactor),
+		netCostVer2(option, ndv, rowSize, netFactor))
+	p.planCostInit = true
+	return p.planCostVer2, nil
+}
+
+// GetPlanCostVer2 returns the plan-cost of this sub-plan, which is:
+// plan-cost = rows * log2(row-size) * scan-factor + seek-cost
+// seek-cost = (ndv + 1) * request-factor, which is only for the skip scan
 // log2(row-size) is from experiments.
 func (p *PhysicalIndexScan) GetPlanCostVer2(taskType property.TaskType, option *optimizetrace.PlanCostOption) (costusage.CostVer2, error) {
 	if p.planCostInit && !hasCostFlag(option.CostFlag, costusage.CostFlagRecalculate) {
@@ -136,6 +163,12 @@ func (p *PhysicalIndexScan) GetPlanCostVer2(taskType property.TaskType, option *
 	scanFactor := getTaskScanFactorVer2(p, kv.TiKV, taskType)
 
 	p.planCostVer2 = scanCostVer2(option, rows, rowSize, scanFactor)
+	if p.SkipScanRanges != nil {
+		// The skip scan seeks the distinct values of the leading column one by one, each seek is a request.
+		requestFactor := getTaskRequestFactorVer2(p, taskType)
+		// One more seek is needed to find there's no more value.
+		p.planCostVer2 = costusage.SumCostVer2(p.planCostVer2, seekCostVer2(option, p.skipScanNDV+1, requestFactor))
+	}
 	p.planCostInit = true
 	return p.planCostVer2, nil
 }
@@ -894,6 +927,12 @@ func doubleReadCostVer2(option *optimizetrace.PlanCostOption, numTasks float64,
 		func() string { return fmt.Sprintf("doubleRead(tasks(%v)*%v)", numTasks, requestFactor) })
 }
 
+func seekCostVer2(option *optimizetrace.PlanCostOption, numSeeks float64, requestFactor costusage.CostVer2Factor) costusage.CostVer2 {
+	return costusage.NewCostVer2(option, requestFactor,
+		numSeeks*requestFactor.Value,
+		func() string { return fmt.Sprintf("seek(seeks(%v)*%v)", numSeeks, requestFactor) })
+}
+
 // In Cost Ver2, we hide cost factors from users and deprecate SQL variables like `tidb_opt_scan_factor`.
 type costVer2Factors struct {
 	TiDBTemp      costusage.CostVer2Factor // operations on TiDB temporary table
@@ -1026,6 +1065,8 @@ func getTableInfo(p base.PhysicalPlan) *model.TableInfo {
 		return x.Table
 	case *PhysicalIndexScan:
 		return x.Table
+	case *PhysicalLooseIndexScan:
+		return x.IndexScan.Table
 	default:
 		if len(x.Children()) == 0 {
 			return nil
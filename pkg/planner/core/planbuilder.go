@@ -504,6 +504,8 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (base.Plan, erro
 		return b.buildUnlockStats(x), nil
 	case *ast.IndexAdviseStmt:
 		return b.buildIndexAdvise(x), nil
+	case *ast.RecommendIndexStmt:
+		return b.buildRecommendIndex(x), nil
 	case *ast.PlanReplayerStmt:
 		return b.buildPlanReplayer(x), nil
 	case *ast.PrepareStmt:
@@ -517,6 +519,8 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (base.Plan, erro
 		return b.buildSetOpr(ctx, x)
 	case *ast.UpdateStmt:
 		return b.buildUpdate(ctx, x)
+	case *ast.MergeStmt:
+		return b.buildMerge(ctx, x)
 	case *ast.ShowStmt:
 		return b.buildShow(ctx, x)
 	case *ast.DoStmt:
@@ -532,7 +536,8 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (base.Plan, erro
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.AlterRangeStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
 		*ast.RenameUserStmt, *ast.NonTransactionalDMLStmt, *ast.SetSessionStatesStmt, *ast.SetResourceGroupStmt,
-		*ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt, *ast.AddQueryWatchStmt, *ast.DropQueryWatchStmt:
+		*ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt, *ast.AddQueryWatchStmt, *ast.DropQueryWatchStmt,
+		*ast.ProcedureInfo, *ast.DropProcedureStmt, *ast.CallStmt, *ast.RefreshMaterializedViewStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -1006,7 +1011,8 @@ func isForUpdateReadSelectLock(lock *ast.SelectLockInfo) bool {
 	}
 	return lock.LockType == ast.SelectLockForUpdate ||
 		lock.LockType == ast.SelectLockForUpdateNoWait ||
-		lock.LockType == ast.SelectLockForUpdateWaitN
+		lock.LockType == ast.SelectLockForUpdateWaitN ||
+		lock.LockType == ast.SelectLockForUpdateSkipLocked
 }
 
 // getLatestIndexInfo gets the index info of latest schema version from given table id,
@@ -1078,6 +1084,13 @@ func getPossibleAccessPaths(ctx base.PlanContext, tableHints *hint.PlanHints, in
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// FULLTEXT index stores tokens instead of the column values, it can only be accessed
+			// by the IndexMerge path built from MATCH ... AGAINST.
+			// VECTOR index stores partitions, it can only be accessed by the ANN path built from
+			// ORDER BY distance LIMIT k, see DataSource.PushDownTopN.
+			if index.IsFullText() || index.IsVector() {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1138,6 +1151,18 @@ func getPossibleAccessPaths(ctx base.PlanContext, tableHints *hint.PlanHints, in
 			}
 			continue
 		}
+		if hint.HintType == ast.HintSkipScan || hint.HintType == ast.HintNoSkipScan {
+			if hint.IndexNames == nil {
+				// SKIP_SCAN(t) and NO_SKIP_SCAN(t) apply to all the indexes without restricting them.
+				for _, path := range publicPaths {
+					if !path.IsTablePath() {
+						path.ForceSkipScan = hint.HintType == ast.HintSkipScan
+						path.ForceNoSkipScan = hint.HintType == ast.HintNoSkipScan
+					}
+				}
+				continue
+			}
+		}
 		// It is syntactically valid to omit index_list for USE INDEX, which means “use no indexes”.
 		// Omitting index_list for FORCE INDEX or IGNORE INDEX is a syntax error.
 		// See https://dev.mysql.com/doc/refman/8.0/en/index-hints.html.
@@ -1150,6 +1175,11 @@ func getPossibleAccessPaths(ctx base.PlanContext, tableHints *hint.PlanHints, in
 		}
 		for _, idxName := range hint.IndexNames {
 			path := getPathByIndexName(publicPaths, idxName, tblInfo)
+			if idx := tblInfo.FindIndexByName(idxName.L); path == nil && idx != nil && idx.IsVector() {
+				// The hints on VECTOR index are checked again when its ANN path is built.
+				hasUseOrForce = hasUseOrForce || hint.HintType != ast.HintIgnore
+				continue
+			}
 			if path == nil {
 				err := plannererrors.ErrKeyDoesNotExist.FastGenByArgs(idxName, tblInfo.Name)
 				// if hint is from comment-style sql hints, we should throw a warning instead of error.
@@ -1164,6 +1194,10 @@ func getPossibleAccessPaths(ctx base.PlanContext, tableHints *hint.PlanHints, in
 				ignored = append(ignored, path)
 				continue
 			}
+			if hint.HintType == ast.HintNoSkipScan {
+				path.ForceNoSkipScan = true
+				continue
+			}
 			// Currently we don't distinguish between "FORCE" and "USE" because
 			// our cost estimation is not reliable.
 			hasUseOrForce = true
@@ -1174,6 +1208,9 @@ func getPossibleAccessPaths(ctx base.PlanContext, tableHints *hint.PlanHints, in
 			if hint.HintType == ast.HintNoOrderIndex {
 				path.ForceNoKeepOrder = true
 			}
+			if hint.HintType == ast.HintSkipScan {
+				path.ForceSkipScan = true
+			}
 			available = append(available, path)
 		}
 	}
@@ -1428,12 +1465,7 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (base.P
 	case ast.AdminCaptureBindings:
 		return &SQLBindPlan{SQLBindOp: OpCaptureBindings}, nil
 	case ast.AdminEvolveBindings:
-		var err error
-		// The 'baseline evolution' only work in the test environment before the feature is GA.
-		if !config.CheckTableBeforeDrop {
-			err = errors.Errorf("Cannot enable baseline evolution feature, it is not generally available now")
-		}
-		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, err
+		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, nil
 	case ast.AdminReloadBindings:
 		return &SQLBindPlan{SQLBindOp: OpReloadBindings}, nil
 	case ast.AdminReloadStatistics:
@@ -1632,6 +1664,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.IsFullText() || idxInfo.IsVector() {
+			// Skip checking FULLTEXT and VECTOR index, their keys are tokens and partitions rather than the column values.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1785,7 +1821,7 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !idx.IsFullText() && !idx.IsVector() {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -2121,7 +2157,7 @@ func getModifiedIndexesInfoForAnalyze(
 	idxsInfo := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
 	independentIdxsInfo := make([]*model.IndexInfo, 0)
 	for _, originIdx := range tblInfo.Indices {
-		if originIdx.State != model.StatePublic {
+		if originIdx.State != model.StatePublic || originIdx.IsFullText() || originIdx.IsVector() {
 			continue
 		}
 		if originIdx.MVIndex {
@@ -2501,6 +2537,14 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 				continue
 			}
+			if idx.IsFullText() {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
+			if idx.IsVector() {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
 			p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tbl.TableInfo, partitionNames, physicalIDs, version)...)
 		}
 		handleCols := BuildHandleColsForAnalyze(b.ctx, tbl.TableInfo, true, nil)
@@ -2577,6 +2621,14 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 			continue
 		}
+		if idx.IsFullText() {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+			continue
+		}
+		if idx.IsVector() {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+			continue
+		}
 		p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tblInfo, names, physicalIDs, version)...)
 	}
 	return p, nil
@@ -2607,6 +2659,14 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 				continue
 			}
+			if idx.IsFullText() {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
+			if idx.IsVector() {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
 
 			p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tblInfo, names, physicalIDs, version)...)
 		}
@@ -2898,6 +2958,19 @@ func buildTableRegionsSchema() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildRecommendIndexFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(8)
+	schema.Append(buildColumnWithName("", "DATABASE", mysql.TypeVarchar, mysql.MaxDatabaseNameLength))
+	schema.Append(buildColumnWithName("", "TABLE", mysql.TypeVarchar, mysql.MaxTableNameLength))
+	schema.Append(buildColumnWithName("", "INDEX_NAME", mysql.TypeVarchar, mysql.MaxIndexIdentifierLen))
+	schema.Append(buildColumnWithName("", "INDEX_COLUMNS", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "EST_BENEFIT", mysql.TypeVarchar, 16))
+	schema.Append(buildColumnWithName("", "IMPACTED_QUERIES", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "TOP_IMPACTED_QUERY", mysql.TypeVarchar, mysql.MaxBlobWidth))
+	schema.Append(buildColumnWithName("", "CREATE_INDEX_STATEMENT", mysql.TypeVarchar, mysql.MaxBlobWidth))
+	return schema.col2Schema(), schema.names
+}
+
 func buildSplitRegionsSchema() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(2)
 	schema.Append(buildColumnWithName("", "TOTAL_SPLIT_REGION", mysql.TypeLonglong, 4))
@@ -3105,6 +3178,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (base.P
 			Table:                 show.Table,
 			Partition:             show.Partition,
 			Column:                show.Column,
+			Procedure:             show.Procedure,
 			IndexName:             show.IndexName,
 			ResourceGroupName:     show.ResourceGroupName,
 			Flag:                  show.Flag,
@@ -3216,8 +3290,16 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (base.P
 	np = p
 	// If we have ShowPredicateExtractor, we do not buildSelection with Pattern
 	if show.Pattern != nil && buildPattern {
+		patternCol := p.OutputNames()[0].ColName
+		if show.Tp == ast.ShowProcedureStatus || show.Tp == ast.ShowFunctionStatus {
+			// The pattern matches the routine name instead of the database.
+			patternCol = p.OutputNames()[1].ColName
+		} else if show.Tp == ast.ShowTriggers {
+			// The pattern matches the table name of the trigger.
+			patternCol = p.OutputNames()[2].ColName
+		}
 		show.Pattern.Expr = &ast.ColumnNameExpr{
-			Name: &ast.ColumnName{Name: p.OutputNames()[0].ColName},
+			Name: &ast.ColumnName{Name: patternCol},
 		}
 		np, err = b.buildSelection(ctx, np, show.Pattern, nil)
 		if err != nil {
@@ -3342,6 +3424,24 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (base.
 		if raw.DBName == "" {
 			return nil, plannererrors.ErrNoDB
 		}
+	case *ast.ProcedureInfo:
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, raw.ProcedureName.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateRoutinePriv, raw.ProcedureName.Schema.L, "", "", err)
+	case *ast.DropProcedureStmt:
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, raw.ProcedureName.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterRoutinePriv, raw.ProcedureName.Schema.L, "", "", err)
+	case *ast.RefreshMaterializedViewStmt:
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("ALTER", user.AuthUsername, user.AuthHostname, raw.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterPriv, raw.ViewName.Schema.L, raw.ViewName.Name.L, "", err)
 	case *ast.DropUserStmt:
 		// The main privilege checks for DROP USER are currently performed in executor/simple.go
 		// because they use complex OR conditions (not supported by visitInfo).
@@ -3607,6 +3707,13 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 		return nil, err
 	}
+	if b.isReadOnlyMaterializedViewTable(tableInfo) {
+		op := "INSERT"
+		if insert.IsReplace {
+			op = "REPLACE"
+		}
+		return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, op)
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx.GetExprCtx(), tn.Schema, tableInfo)
 	if err != nil {
@@ -4286,6 +4393,12 @@ func (*PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) base.Plan {
 	return p
 }
 
+func (*PlanBuilder) buildRecommendIndex(node *ast.RecommendIndexStmt) base.Plan {
+	p := &RecommendIndex{SQL: node.SQL}
+	p.setSchemaAndNames(buildRecommendIndexFields())
+	return p
+}
+
 func (b *PlanBuilder) buildSplitRegion(node *ast.SplitRegionStmt) (base.Plan, error) {
 	if node.Table.TableInfo.TempTableType != model.TempTableNone {
 		return nil, plannererrors.ErrOptOnTemporaryTable.GenWithStackByArgs("split table")
@@ -4669,6 +4782,21 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (base.Plan
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		// The selected rows are inserted into the created table. No more privilege is checked for a
+		// local temporary table once it is created, which is the same as MySQL.
+		if v.Select != nil && v.TemporaryKeyword != ast.TemporaryLocal {
+			privs := []mysql.PrivilegeType{mysql.InsertPriv}
+			if v.OnDuplicate == ast.OnDuplicateKeyHandlingReplace {
+				privs = append(privs, mysql.DeletePriv)
+			}
+			for _, priv := range privs {
+				if b.ctx.GetSessionVars().User != nil {
+					authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs(strings.ToUpper(mysql.Priv2Str[priv]),
+						b.ctx.GetSessionVars().User.AuthUsername, b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+				}
+				b.visitInfo = appendVisitInfo(b.visitInfo, priv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+			}
+		}
 	case *ast.CreateViewStmt:
 		b.isCreateView = true
 		b.capFlag |= canExpandAST | renameView
@@ -4713,6 +4841,35 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (base.Plan
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
 				"", "", err)
 		}
+	case *ast.CreateMaterializedViewStmt:
+		plan, err := b.Build(ctx, v.Select)
+		if err != nil {
+			return nil, err
+		}
+		if v.Cols == nil {
+			adjustOverlongViewColname(plan.(base.LogicalPlan))
+			names := plan.OutputNames()
+			v.Cols = make([]model.CIStr, len(names))
+			for i, name := range names {
+				v.Cols[i] = name.ColName
+			}
+		}
+		if len(v.Cols) != plan.Schema().Len() {
+			return nil, dbterror.ErrViewWrongList
+		}
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("CREATE", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+	case *ast.DropMaterializedViewStmt:
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("DROP", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
 	case *ast.CreateSequenceStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
@@ -4720,6 +4877,28 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (base.Plan
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.Name.Schema.L,
 			v.Name.Name.L, "", authErr)
+	case *ast.CreateTriggerStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("TRIGGER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.Table.Schema.L,
+			v.Table.Name.L, "", authErr)
+		if v.Definer.CurrentUser && b.ctx.GetSessionVars().User != nil {
+			v.Definer = b.ctx.GetSessionVars().User
+		}
+		if b.ctx.GetSessionVars().User != nil && v.Definer.String() != b.ctx.GetSessionVars().User.String() {
+			err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
+				"", "", err)
+		}
+	case *ast.DropTriggerStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.TriggerName.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.TriggerName.Schema.L,
+			"", "", authErr)
 	case *ast.DropDatabaseStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(b.ctx.GetSessionVars().User.AuthUsername,
@@ -4850,9 +5029,37 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (base.Plan
 		return nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStack("OPTIMIZE TABLE is not supported")
 	}
 	p := &DDL{Statement: node}
+	if v, ok := node.(*ast.CreateTableStmt); ok && v.Select != nil {
+		if err := b.buildSelectPlanOfCreateTable(ctx, v, p); err != nil {
+			return nil, err
+		}
+	}
 	return p, nil
 }
 
+// buildSelectPlanOfCreateTable builds the plan of the select in `CREATE TABLE ... SELECT`.
+// The columns of the table are derived from the output schema of the plan when the statement is executed.
+func (b *PlanBuilder) buildSelectPlanOfCreateTable(ctx context.Context, stmt *ast.CreateTableStmt, p *DDL) error {
+	selectPlan, err := b.Build(ctx, stmt.Select)
+	if err != nil {
+		return err
+	}
+	names := selectPlan.OutputNames()
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name.ColName.L]; ok {
+			return plannererrors.ErrDupFieldName.GenWithStackByArgs(name.ColName.O)
+		}
+		seen[name.ColName.L] = struct{}{}
+	}
+	p.SelectPlan, _, err = DoOptimize(ctx, b.ctx, b.optFlag, selectPlan.(base.LogicalPlan))
+	if err != nil {
+		return err
+	}
+	p.SelectNames = names
+	return nil
+}
+
 const (
 	// TraceFormatRow indicates row tracing format.
 	TraceFormatRow = "row"
@@ -5159,6 +5366,8 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		names = []string{"View", "Create View", "character_set_client", "collation_connection"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
+	case ast.ShowCreateProcedure:
+		names = []string{"Procedure", "sql_mode", "Create Procedure", "character_set_client", "collation_connection", "Database Collation"}
 	case ast.ShowDrainerStatus:
 		names = []string{"NodeID", "Address", "State", "Max_Commit_Ts", "Update_Time"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeVarchar}
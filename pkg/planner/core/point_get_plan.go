@@ -92,6 +92,7 @@ type PointGetPlan struct {
 	Lock             bool
 	outputNames      []*types.FieldName
 	LockWaitTime     int64
+	LockSkipLocked   bool
 	Columns          []*model.ColumnInfo
 	cost             float64
 
@@ -428,13 +429,14 @@ type BatchPointGetPlan struct {
 	SinglePartition bool
 	// pre-calculated partition definition indexes
 	// for Handles or IndexValues
-	PartitionIdxs []int
-	KeepOrder     bool
-	Desc          bool
-	Lock          bool
-	LockWaitTime  int64
-	Columns       []*model.ColumnInfo
-	cost          float64
+	PartitionIdxs  []int
+	KeepOrder      bool
+	Desc           bool
+	Lock           bool
+	LockWaitTime   int64
+	LockSkipLocked bool
+	Columns        []*model.ColumnInfo
+	cost           float64
 
 	// required by cost model
 	planCostInit bool
@@ -896,6 +898,7 @@ func TryFastPlan(ctx base.PlanContext, node ast.Node) (p base.Plan) {
 				return nil
 			}
 			fp.Lock, fp.LockWaitTime = getLockWaitTime(ctx, x.LockInfo)
+			fp.LockSkipLocked = fp.Lock && IsSelectSkipLockedLockType(x.LockInfo.LockType)
 			p = fp
 			return
 		}
@@ -914,6 +917,7 @@ func TryFastPlan(ctx base.PlanContext, node ast.Node) (p base.Plan) {
 				return
 			}
 			fp.Lock, fp.LockWaitTime = getLockWaitTime(ctx, x.LockInfo)
+			fp.LockSkipLocked = fp.Lock && IsSelectSkipLockedLockType(x.LockInfo.LockType)
 			p = fp
 			return
 		}
@@ -930,12 +934,18 @@ func IsSelectForUpdateLockType(lockType ast.SelectLockType) bool {
 	if lockType == ast.SelectLockForUpdate ||
 		lockType == ast.SelectLockForShare ||
 		lockType == ast.SelectLockForUpdateNoWait ||
-		lockType == ast.SelectLockForUpdateWaitN {
+		lockType == ast.SelectLockForUpdateWaitN ||
+		IsSelectSkipLockedLockType(lockType) {
 		return true
 	}
 	return false
 }
 
+// IsSelectSkipLockedLockType checks if the select lock type skips the rows locked by other transactions.
+func IsSelectSkipLockedLockType(lockType ast.SelectLockType) bool {
+	return lockType == ast.SelectLockForUpdateSkipLocked || lockType == ast.SelectLockForShareSkipLocked
+}
+
 func getLockWaitTime(ctx base.PlanContext, lockInfo *ast.SelectLockInfo) (lock bool, waitTime int64) {
 	if lockInfo != nil {
 		if IsSelectForUpdateLockType(lockInfo.LockType) {
@@ -950,7 +960,8 @@ func getLockWaitTime(ctx base.PlanContext, lockInfo *ast.SelectLockInfo) (lock b
 				waitTime = sessVars.LockWaitTimeout
 				if lockInfo.LockType == ast.SelectLockForUpdateWaitN {
 					waitTime = int64(lockInfo.WaitSec * 1000)
-				} else if lockInfo.LockType == ast.SelectLockForUpdateNoWait {
+				} else if lockInfo.LockType == ast.SelectLockForUpdateNoWait || IsSelectSkipLockedLockType(lockInfo.LockType) {
+					// SKIP LOCKED never waits for a lock, the row is skipped when the lock can't be acquired at once.
 					waitTime = tikvstore.LockNoWait
 				}
 			}
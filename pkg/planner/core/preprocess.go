@@ -249,6 +249,13 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		}
 	case *ast.UpdateStmt:
 		p.stmtTp = TypeUpdate
+	case *ast.MergeStmt:
+		p.stmtTp = TypeMerge
+		// The target and the source are joined, so their names must be unique as the tables of a join.
+		tableAliases := make(map[string]any, 2)
+		if p.err = isTableAliasDuplicate(node.Target, tableAliases); p.err == nil {
+			p.err = isTableAliasDuplicate(node.Source, tableAliases)
+		}
 	case *ast.InsertStmt:
 		p.stmtTp = TypeInsert
 		// handle the insert table name imminently
@@ -267,6 +274,13 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.flag |= inCreateOrDropTable
 		p.checkCreateViewGrammar(node)
 		p.checkCreateViewWithSelectGrammar(node)
+	case *ast.CreateMaterializedViewStmt:
+		p.stmtTp = TypeCreate
+		p.flag |= inCreateOrDropTable
+		p.checkCreateMaterializedViewGrammar(node)
+	case *ast.DropMaterializedViewStmt:
+		p.stmtTp = TypeDrop
+		p.flag |= inCreateOrDropTable
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.stmtTp = TypeDrop
@@ -324,6 +338,31 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 			}
 		}
 		return in, true
+	case *ast.ProcedureInfo:
+		p.stmtTp = TypeCreate
+		p.resolveProcedureName(node.ProcedureName)
+		// The statements in the procedure body are resolved when the procedure is called,
+		// the tables they reference may not exist yet.
+		return in, true
+	case *ast.DropProcedureStmt:
+		p.stmtTp = TypeDrop
+		p.resolveProcedureName(node.ProcedureName)
+		return in, true
+	case *ast.CallStmt:
+		// The procedure is resolved and the arguments are evaluated when the procedure is called.
+		return in, true
+	case *ast.CreateTriggerStmt:
+		p.stmtTp = TypeCreate
+		p.resolveProcedureName(node.TriggerName)
+		if p.err == nil {
+			p.handleTableName(node.Table)
+		}
+		// Like the procedure body, the statements in the trigger body are resolved when the trigger is fired.
+		return in, true
+	case *ast.DropTriggerStmt:
+		p.stmtTp = TypeDrop
+		p.resolveProcedureName(node.TriggerName)
+		return in, true
 	case *ast.RecoverTableStmt:
 		// The specified table in recover table statement maybe already been dropped.
 		// So skip check table name here, otherwise, recover table [table_name] syntax will return
@@ -374,6 +413,12 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		if _, ok := node.Source.(*ast.SelectStmt); ok && !isModeOracle && len(node.AsName.L) == 0 {
 			p.err = dbterror.ErrDerivedMustHaveAlias.GenWithStackByArgs()
 		}
+		if node.Lateral && len(node.AsName.L) == 0 {
+			p.err = dbterror.ErrDerivedMustHaveAlias.GenWithStackByArgs()
+		}
+		if _, ok := node.Source.(*ast.JSONTable); ok && len(node.AsName.L) == 0 {
+			p.err = plannererrors.ErrTFMustHaveAlias.GenWithStackByArgs()
+		}
 		if v, ok := node.Source.(*ast.TableName); ok && v.TableSample != nil {
 			switch v.TableSample.SampleMethod {
 			case ast.SampleMethodTypeTiDBRegion:
@@ -458,6 +503,8 @@ const (
 	TypeExecute
 	// TypeImportInto for ImportIntoStmt
 	TypeImportInto
+	// TypeMerge for MergeStmt
+	TypeMerge
 )
 
 func bindableStmtType(node ast.StmtNode) byte {
@@ -569,7 +616,7 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		p.flag &= ^inCreateOrDropTable
 		p.checkAutoIncrement(x)
 		p.checkContainDotColumn(x)
-	case *ast.CreateViewStmt:
+	case *ast.CreateViewStmt, *ast.CreateMaterializedViewStmt, *ast.DropMaterializedViewStmt:
 		p.flag &= ^inCreateOrDropTable
 	case *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt:
 		p.flag &= ^inCreateOrDropTable
@@ -949,9 +996,12 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 		return
 	}
 	if stmt.Select != nil {
-		// FIXME: a temp error noticing 'not implemented' (issue 4754)
-		p.err = errors.New("'CREATE TABLE ... SELECT' is not implemented yet")
-		return
+		// The rows of a global temporary table are only visible to the transaction writing them,
+		// so populating it in the statement's own transaction would be pointless.
+		if stmt.TemporaryKeyword == ast.TemporaryGlobal {
+			p.err = plannererrors.ErrOptOnTemporaryTable.GenWithStackByArgs("create table ... select")
+			return
+		}
 	} else if len(stmt.Cols) == 0 && stmt.ReferTable == nil {
 		p.err = dbterror.ErrTableMustHaveColumns
 		return
@@ -1022,6 +1072,21 @@ func (p *preprocessor) checkCreateViewWithSelectGrammar(stmt *ast.CreateViewStmt
 	}
 }
 
+func (p *preprocessor) checkCreateMaterializedViewGrammar(stmt *ast.CreateMaterializedViewStmt) {
+	vName := stmt.ViewName.Name.String()
+	if util.IsInCorrectIdentifierName(vName) {
+		p.err = dbterror.ErrWrongTableName.GenWithStackByArgs(vName)
+		return
+	}
+	for _, col := range stmt.Cols {
+		if util.IsInCorrectIdentifierName(col.String()) {
+			p.err = dbterror.ErrWrongColumnName.GenWithStackByArgs(col)
+			return
+		}
+	}
+	p.checkCreateViewWithSelectGrammar(&ast.CreateViewStmt{Select: stmt.Select})
+}
+
 func (p *preprocessor) checkDropSequenceGrammar(stmt *ast.DropSequenceStmt) {
 	p.checkDropTableNames(stmt.Sequences)
 }
@@ -1520,6 +1585,8 @@ func (p *preprocessor) stmtType() string {
 		return "SHOW"
 	case TypeImportInto:
 		return "IMPORT INTO"
+	case TypeMerge:
+		return "MERGE"
 	default:
 		return "SELECT" // matches Select and uncaught cases.
 	}
@@ -1638,6 +1705,9 @@ func (p *preprocessor) resolveShowStmt(node *ast.ShowStmt) {
 	} else if node.Table != nil && node.Table.Schema.L == "" {
 		node.Table.Schema = model.NewCIStr(node.DBName)
 	}
+	if node.Procedure != nil {
+		p.resolveProcedureName(node.Procedure)
+	}
 	if node.User != nil && node.User.CurrentUser {
 		// Fill the Username and Hostname with the current user.
 		currentUser := p.sctx.GetSessionVars().User
@@ -1650,6 +1720,19 @@ func (p *preprocessor) resolveShowStmt(node *ast.ShowStmt) {
 	}
 }
 
+// resolveProcedureName fills the schema of the procedure name with the current database.
+func (p *preprocessor) resolveProcedureName(tn *ast.TableName) {
+	if tn.Schema.L != "" {
+		return
+	}
+	currentDB := p.sctx.GetSessionVars().CurrentDB
+	if currentDB == "" {
+		p.err = errors.Trace(plannererrors.ErrNoDB)
+		return
+	}
+	tn.Schema = model.NewCIStr(currentDB)
+}
+
 func (p *preprocessor) resolveExecuteStmt(node *ast.ExecuteStmt) {
 	prepared, err := GetPreparedStmt(node, p.sctx.GetSessionVars())
 	if err != nil {
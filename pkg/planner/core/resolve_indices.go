@@ -350,7 +350,13 @@ func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
 	if foundCnt < colsNeedResolving {
 		return errors.Errorf("Some columns of %v cannot find the reference from its child(ren)", p.ExplainID().String())
 	}
-
+	if p.AdaptiveHashJoin != nil {
+		// The outer child is shared with the index join and has been resolved above.
+		if err = p.AdaptiveHashJoin.Children()[p.InnerChildIdx].ResolveIndices(); err != nil {
+			return err
+		}
+		return p.AdaptiveHashJoin.ResolveIndicesItself()
+	}
 	return
 }
 
@@ -839,6 +845,35 @@ func (p *Update) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *Merge) ResolveIndices() (err error) {
+	err = p.baseSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	schema := p.SelectPlan.Schema()
+	for _, clause := range p.Clauses {
+		if clause.Condition != nil {
+			clause.Condition, err = clause.Condition.ResolveIndices(schema)
+			if err != nil {
+				return err
+			}
+		}
+		for i, expr := range clause.Values {
+			clause.Values[i], err = expr.ResolveIndices(schema)
+			if err != nil {
+				return err
+			}
+		}
+		if clause.Update != nil {
+			if err = clause.Update.ResolveIndices(); err != nil {
+				return err
+			}
+		}
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalLock) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
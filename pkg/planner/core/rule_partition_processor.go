@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/util"
 	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/tables"
@@ -65,11 +66,44 @@ type partitionProcessor struct{}
 
 func (s *partitionProcessor) optimize(_ context.Context, lp base.LogicalPlan, opt *optimizetrace.LogicalOptimizeOp) (base.LogicalPlan, bool, error) {
 	planChanged := false
+	if lp.SCtx().GetSessionVars().StmtCtx.UseDynamicPruneMode {
+		// The partitions are pruned by the readers under dynamic partition prune mode, so only the partition-wise
+		// joins are built here.
+		p, err := s.rewritePartitionWiseJoin(lp, opt)
+		return p, planChanged, err
+	}
 	p, err := s.rewriteDataSource(lp, opt)
 	return p, planChanged, err
 }
 
+func (s *partitionProcessor) rewritePartitionWiseJoin(lp base.LogicalPlan, opt *optimizetrace.LogicalOptimizeOp) (base.LogicalPlan, error) {
+	switch p := lp.(type) {
+	case *LogicalJoin:
+		newPlan, ok, err := s.tryPartitionWiseJoin(p, opt)
+		if err != nil || ok {
+			return newPlan, err
+		}
+	case *LogicalCTE:
+		return lp, nil
+	}
+	children := lp.Children()
+	for i, child := range children {
+		newChild, err := s.rewritePartitionWiseJoin(child, opt)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = newChild
+	}
+	return lp, nil
+}
+
 func (s *partitionProcessor) rewriteDataSource(lp base.LogicalPlan, opt *optimizetrace.LogicalOptimizeOp) (base.LogicalPlan, error) {
+	if join, ok := lp.(*LogicalJoin); ok {
+		newPlan, ok, err := s.tryPartitionWiseJoin(join, opt)
+		if err != nil || ok {
+			return newPlan, err
+		}
+	}
 	// Assert there will not be sel -> sel in the ast.
 	switch p := lp.(type) {
 	case *DataSource:
@@ -115,6 +149,258 @@ func (s *partitionProcessor) rewriteDataSource(lp base.LogicalPlan, opt *optimiz
 	return lp, nil
 }
 
+// tryPartitionWiseJoin splits the join of two tables partitioned identically on the join key into the joins of the
+// matching partitions, e.g. for the tables t1 and t2 both partitioned by hash(a) into 2 partitions,
+/*
+// select * from t1 join t2 on t1.a = t2.a is equal to
+// select * from (union all
+//      select * from t1 partition (p0) join t2 partition (p0) on t1.a = t2.a
+//      select * from t1 partition (p1) join t2 partition (p1) on t1.a = t2.a)
+*/
+// The joins of the partitions are executed concurrently by the union and each of them builds a smaller hash table.
+// Besides, a partition pruned from one side is pruned from the other side as well if the join doesn't output the
+// unmatched rows of the other side. Under dynamic partition prune mode, each partition is read by a reader of the
+// partitioned table which only reads that partition, as `t1 partition (p0)` does. ok is false if the join can't be
+// split, and the children are left unchanged.
+func (s *partitionProcessor) tryPartitionWiseJoin(p *LogicalJoin, opt *optimizetrace.LogicalOptimizeOp) (newPlan base.LogicalPlan, ok bool, err error) {
+	if !p.SCtx().GetSessionVars().EnablePartitionWiseJoin || p.isNAAJ() {
+		return nil, false, nil
+	}
+	// needLeft(needRight) means the rows of the left(right) side are needed to produce any output.
+	var needLeft, needRight bool
+	switch p.JoinType {
+	case InnerJoin, SemiJoin:
+		needLeft, needRight = true, true
+	case LeftOuterJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		needLeft = true
+	case RightOuterJoin:
+		needRight = true
+	default:
+		return nil, false, nil
+	}
+	leftDS, leftOK := p.Children()[0].(*DataSource)
+	rightDS, rightOK := p.Children()[1].(*DataSource)
+	if !leftOK || !rightOK || !isCoPartitionedJoin(p, leftDS, rightDS) {
+		return nil, false, nil
+	}
+	var leftParts, rightParts map[int]base.LogicalPlan
+	if p.SCtx().GetSessionVars().StmtCtx.UseDynamicPruneMode {
+		if leftParts, leftOK, err = s.splitDynamicPartitions(leftDS); err != nil || !leftOK {
+			return nil, false, err
+		}
+		if rightParts, rightOK, err = s.splitDynamicPartitions(rightDS); err != nil || !rightOK {
+			return nil, false, err
+		}
+	} else {
+		left, err := s.prune(leftDS, opt)
+		if err != nil {
+			return nil, false, err
+		}
+		right, err := s.prune(rightDS, opt)
+		if err != nil {
+			return nil, false, err
+		}
+		leftParts, leftOK = partitionsOfPrunedPlan(left)
+		rightParts, rightOK = partitionsOfPrunedPlan(right)
+		if !leftOK || !rightOK {
+			p.SetChildren(left, right)
+			return p, true, nil
+		}
+	}
+
+	joins := make([]base.LogicalPlan, 0, len(leftDS.tableInfo.GetPartitionInfo().Definitions))
+	for i := range leftDS.tableInfo.GetPartitionInfo().Definitions {
+		leftPart, rightPart := leftParts[i], rightParts[i]
+		if (leftPart == nil && (needLeft || rightPart == nil)) || (rightPart == nil && needRight) {
+			continue
+		}
+		if leftPart == nil {
+			leftPart = newPrunedPartitionDual(leftDS)
+		}
+		if rightPart == nil {
+			rightPart = newPrunedPartitionDual(rightDS)
+		}
+		join := p.Shallow()
+		join.SetSchema(p.Schema().Clone())
+		join.EqualConditions = make([]*expression.ScalarFunction, 0, len(p.EqualConditions))
+		for _, cond := range p.EqualConditions {
+			join.EqualConditions = append(join.EqualConditions, cond.Clone().(*expression.ScalarFunction))
+		}
+		join.LeftConditions = util.CloneExprs(p.LeftConditions)
+		join.RightConditions = util.CloneExprs(p.RightConditions)
+		join.OtherConditions = util.CloneExprs(p.OtherConditions)
+		join.SetChildren(leftPart, rightPart)
+		joins = append(joins, join)
+	}
+
+	switch len(joins) {
+	case 0:
+		newPlan = LogicalTableDual{RowCount: 0}.Init(p.SCtx(), p.QueryBlockOffset())
+		newPlan.(*LogicalTableDual).SetSchema(p.Schema())
+	case 1:
+		newPlan = joins[0]
+	default:
+		unionAll := LogicalPartitionUnionAll{}.Init(p.SCtx(), p.QueryBlockOffset())
+		unionAll.SetChildren(joins...)
+		unionAll.SetSchema(p.Schema().Clone())
+		newPlan = unionAll
+	}
+	appendPartitionWiseJoinTraceStep(p, newPlan, joins, opt)
+	return newPlan, true, nil
+}
+
+// isCoPartitionedJoin checks whether the tables of the two sides of the join are partitioned identically by hash or
+// range on the columns which are equally joined, so the matched rows are always in the partitions of the same index.
+func isCoPartitionedJoin(p *LogicalJoin, leftDS, rightDS *DataSource) bool {
+	leftPi, rightPi := leftDS.tableInfo.GetPartitionInfo(), rightDS.tableInfo.GetPartitionInfo()
+	if leftPi == nil || rightPi == nil || leftPi.Type != rightPi.Type ||
+		len(leftPi.Definitions) != len(rightPi.Definitions) {
+		return false
+	}
+	switch leftPi.Type {
+	case model.PartitionTypeHash:
+	case model.PartitionTypeRange:
+		for i := range leftPi.Definitions {
+			if !slices.Equal(leftPi.Definitions[i].LessThan, rightPi.Definitions[i].LessThan) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	leftCol, rightCol := partitionColumnOfDataSource(leftDS), partitionColumnOfDataSource(rightDS)
+	if leftCol == nil || rightCol == nil || !isCoPartitionedJoinKey(leftCol.RetType, rightCol.RetType) {
+		return false
+	}
+	for _, cond := range p.EqualConditions {
+		lCol, lOK := cond.GetArgs()[0].(*expression.Column)
+		rCol, rOK := cond.GetArgs()[1].(*expression.Column)
+		if lOK && rOK && lCol.UniqueID == leftCol.UniqueID && rCol.UniqueID == rightCol.UniqueID {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionColumnOfDataSource returns the column in the schema of ds which the table is partitioned by, nil is
+// returned if the table is partitioned by an expression or by multiple columns.
+func partitionColumnOfDataSource(ds *DataSource) *expression.Column {
+	pi := ds.tableInfo.GetPartitionInfo()
+	var colID int64
+	if len(pi.Columns) > 0 {
+		if len(pi.Columns) > 1 {
+			return nil
+		}
+		colInfo := model.FindColumnInfo(ds.tableInfo.Columns, pi.Columns[0].L)
+		if colInfo == nil {
+			return nil
+		}
+		colID = colInfo.ID
+	} else {
+		pt, ok := ds.table.(partitionTable)
+		if !ok {
+			return nil
+		}
+		col, ok := pt.PartitionExpr().Expr.(*expression.Column)
+		if !ok {
+			return nil
+		}
+		colID = col.ID
+	}
+	for _, col := range ds.Schema().Columns {
+		if col.ID == colID {
+			return col
+		}
+	}
+	return nil
+}
+
+// isCoPartitionedJoinKey checks whether the equal values of the two types are always located in the partitions of
+// the same index by the identical partition definitions.
+func isCoPartitionedJoinKey(left, right *types.FieldType) bool {
+	if left.EvalType() != right.EvalType() {
+		return false
+	}
+	switch left.EvalType() {
+	case types.ETInt:
+		return mysql.HasUnsignedFlag(left.GetFlag()) == mysql.HasUnsignedFlag(right.GetFlag())
+	case types.ETString:
+		return left.GetCollate() == right.GetCollate()
+	default:
+		return left.GetType() == right.GetType()
+	}
+}
+
+// partitionsOfPrunedPlan returns the partitions read by the plan built by partitionProcessor.prune, which are indexed
+// by the offsets of their definitions.
+func partitionsOfPrunedPlan(lp base.LogicalPlan) (map[int]base.LogicalPlan, bool) {
+	switch x := lp.(type) {
+	case *LogicalTableDual:
+		return map[int]base.LogicalPlan{}, true
+	case *DataSource:
+		if x.partitionDefIdx == nil {
+			return nil, false
+		}
+		return map[int]base.LogicalPlan{*x.partitionDefIdx: x}, true
+	case *LogicalPartitionUnionAll:
+		parts := make(map[int]base.LogicalPlan, len(x.Children()))
+		for _, child := range x.Children() {
+			ds, ok := child.(*DataSource)
+			if !ok || ds.partitionDefIdx == nil {
+				return nil, false
+			}
+			parts[*ds.partitionDefIdx] = ds
+		}
+		return parts, true
+	}
+	return nil, false
+}
+
+// splitDynamicPartitions splits the DataSource under dynamic partition prune mode into the DataSources reading the
+// partitions which aren't pruned, indexed by the offsets of their definitions. Each of them is still read by a reader
+// of the partitioned table, whose partition names only contain the partition.
+func (s *partitionProcessor) splitDynamicPartitions(ds *DataSource) (map[int]base.LogicalPlan, bool, error) {
+	pt, ok := ds.table.(table.PartitionedTable)
+	if !ok {
+		return nil, false, nil
+	}
+	names, err := s.reconstructTableColNames(ds)
+	if err != nil {
+		return nil, false, err
+	}
+	used, err := PartitionPruning(ds.SCtx(), pt, ds.allConds, ds.partitionNames, ds.TblCols, names)
+	if err != nil {
+		return nil, false, err
+	}
+	pi := ds.tableInfo.GetPartitionInfo()
+	parts := make(map[int]base.LogicalPlan, len(used))
+	for _, r := range convertToRangeOr(used, pi) {
+		for i := r.start; i < r.end; i++ {
+			if len(ds.partitionNames) != 0 && !s.findByName(ds.partitionNames, pi.Definitions[i].Name.L) {
+				continue
+			}
+			// Not a deep copy.
+			newDataSource := *ds
+			newDataSource.baseLogicalPlan = newBaseLogicalPlan(ds.SCtx(), plancodec.TypeTableScan, &newDataSource, ds.QueryBlockOffset())
+			newDataSource.schema = ds.schema.Clone()
+			newDataSource.Columns = make([]*model.ColumnInfo, len(ds.Columns))
+			copy(newDataSource.Columns, ds.Columns)
+			newDataSource.partitionNames = []model.CIStr{pi.Definitions[i].Name}
+			newDataSource.SetID(ds.ID())
+			parts[i] = &newDataSource
+		}
+	}
+	// The partitions are pruned by the constants of the conditions, which may be the parameters of the cached plan.
+	ds.SCtx().GetSessionVars().StmtCtx.SetSkipPlanCache("the join is split into the joins of the partitions")
+	return parts, true, nil
+}
+
+func newPrunedPartitionDual(ds *DataSource) *LogicalTableDual {
+	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.QueryBlockOffset())
+	tableDual.schema = ds.Schema()
+	return tableDual
+}
+
 // partitionTable is for those tables which implement partition.
 type partitionTable interface {
 	PartitionExpr() *tables.PartitionExpr
@@ -2069,3 +2355,28 @@ func appendNoPartitionChildTraceStep(ds *DataSource, dual base.LogicalPlan, opt
 	}
 	opt.AppendStepToCurrent(dual.ID(), dual.TP(), reason, action)
 }
+
+func appendPartitionWiseJoinTraceStep(origin *LogicalJoin, plan base.LogicalPlan, joins []base.LogicalPlan, opt *optimizetrace.LogicalOptimizeOp) {
+	if opt.TracerIsNil() {
+		return
+	}
+	action := func() string {
+		buffer := bytes.NewBufferString(fmt.Sprintf("%v_%v becomes %v_%v", origin.TP(), origin.ID(), plan.TP(), plan.ID()))
+		if len(joins) > 1 {
+			buffer.WriteString(" with children[")
+			for i, join := range joins {
+				if i > 0 {
+					buffer.WriteString(",")
+				}
+				fmt.Fprintf(buffer, "%s_%v", join.TP(), join.ID())
+			}
+			buffer.WriteString("]")
+		}
+		return buffer.String()
+	}
+	reason := func() string {
+		return fmt.Sprintf("the children of %v_%v are partitioned identically on the join key, and %v pairs of partitions are needed after pruning",
+			origin.TP(), origin.ID(), len(joins))
+	}
+	opt.AppendStepToCurrent(origin.ID(), origin.TP(), reason, action)
+}
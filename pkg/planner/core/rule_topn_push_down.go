@@ -202,6 +202,21 @@ func (p *LogicalLock) PushDownTopN(topNLogicalPlan base.LogicalPlan, opt *optimi
 	if topNLogicalPlan != nil {
 		topN = topNLogicalPlan.(*LogicalTopN)
 	}
+	if topN != nil && IsSelectSkipLockedLockType(p.Lock.LockType) {
+		// The rows skipped by the lock must not be counted by the limit, so only the order is pushed down
+		// and the limit is kept above the lock, which locks the rows one by one as the limit pulls them.
+		child := p.children[0]
+		if len(topN.ByItems) > 0 {
+			sort := LogicalSort{ByItems: topN.ByItems}.Init(topN.SCtx(), topN.QueryBlockOffset())
+			sort.SetChildren(child)
+			child = sort
+		}
+		p.children[0] = child.PushDownTopN(nil, opt)
+		limit := LogicalLimit{Count: topN.Count, Offset: topN.Offset}.Init(topN.SCtx(), topN.QueryBlockOffset())
+		limit.SetChildren(p.self)
+		appendTopNPushDownTraceStep(limit, p.self, opt)
+		return limit
+	}
 	if topN != nil {
 		p.children[0] = p.children[0].PushDownTopN(topN, opt)
 	}
@@ -0,0 +1,134 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/planner/cardinality"
+	"github.com/pingcap/tidb/pkg/planner/core/cost"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"go.uber.org/zap"
+)
+
+// generateSkipScanPaths generates the skip scan paths for the indexes whose leading column isn't restricted by the
+// filters while the following columns are. The skip scan seeks the distinct values of the leading column and reads
+// the ranges on the following columns under each value, so it's cheap when the leading column has a small NDV.
+// The path is left to the cost model unless it's forced by the SKIP_SCAN hint, which replaces the regular path.
+func (ds *DataSource) generateSkipScanPaths() error {
+	canSkipScan := ds.tableInfo.GetPartitionInfo() == nil && ds.tableInfo.TempTableType == model.TempTableNone &&
+		ds.tableInfo.TableCacheStatusType == model.TableCacheStatusDisable && !tableHasDirtyContent(ds.SCtx(), ds.tableInfo)
+	for i, path := range ds.possibleAccessPaths {
+		if path.IsTablePath() || path.PartialIndexPaths != nil || path.PartialAlternativeIndexPaths != nil ||
+			path.ForceNoSkipScan || isMVIndexPath(path) || isVectorIndexPath(path) {
+			continue
+		}
+		// The skip scan is costed by the NDV of the leading column, so it's considered without the hint only if the
+		// column has been analyzed.
+		if !path.ForceSkipScan && !ds.isLeadingColumnAnalyzed(path.IdxCols) {
+			continue
+		}
+		var skipPath *util.AccessPath
+		if canSkipScan {
+			var err error
+			skipPath, err = ds.buildSkipScanPath(path)
+			if err != nil {
+				return err
+			}
+		}
+		if !path.ForceSkipScan {
+			if skipPath != nil {
+				ds.possibleAccessPaths = append(ds.possibleAccessPaths, skipPath)
+			}
+			continue
+		}
+		if skipPath == nil {
+			ds.SCtx().GetSessionVars().StmtCtx.SetHintWarning(
+				fmt.Sprintf("Optimizer Hint SKIP_SCAN is inapplicable for index %s", path.Index.Name.O))
+			continue
+		}
+		ds.possibleAccessPaths[i] = skipPath
+	}
+	return nil
+}
+
+// buildSkipScanPath builds the skip scan path of the index path, it returns nil if the leading column is restricted
+// by the filters already, or the filters can't restrict the following columns.
+func (ds *DataSource) buildSkipScanPath(path *util.AccessPath) (*util.AccessPath, error) {
+	if len(path.AccessConds) > 0 || len(path.IdxCols) < 2 || path.IdxColLens[0] != types.UnspecifiedLength ||
+		path.StoreType != kv.TiKV {
+		return nil, nil
+	}
+	sctx := ds.SCtx()
+	res, err := ranger.DetachCondAndBuildRangeForIndex(sctx.GetRangerCtx(), ds.pushedDownConds, path.IdxCols[1:],
+		path.IdxColLens[1:], sctx.GetSessionVars().RangeMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.AccessConds) == 0 || len(res.Ranges) == 0 {
+		return nil, nil
+	}
+	for _, cond := range res.AccessConds {
+		// The ranges are built at execution, they can't be rebuilt from the correlated columns.
+		if len(expression.ExtractCorColumns(cond)) > 0 {
+			return nil, nil
+		}
+	}
+	skipPath := &util.AccessPath{
+		Index:            path.Index,
+		FullIdxCols:      path.FullIdxCols,
+		FullIdxColLens:   path.FullIdxColLens,
+		IdxCols:          path.IdxCols,
+		IdxColLens:       path.IdxColLens,
+		Ranges:           ranger.FullRange(),
+		SkipScanRanges:   res.Ranges,
+		AccessConds:      res.AccessConds,
+		TableFilters:     res.RemainedConds,
+		StoreType:        path.StoreType,
+		Forced:           path.Forced,
+		ForceKeepOrder:   path.ForceKeepOrder,
+		ForceNoKeepOrder: path.ForceNoKeepOrder,
+		ForceSkipScan:    path.ForceSkipScan,
+		IsSingleScan:     path.IsSingleScan,
+	}
+	selectivity, _, err := cardinality.Selectivity(sctx, ds.tableStats.HistColl, res.AccessConds, nil)
+	if err != nil {
+		logutil.BgLogger().Debug("calculate selectivity failed, use selection factor", zap.Error(err))
+		selectivity = cost.SelectionFactor
+	}
+	skipPath.CountAfterAccess = float64(ds.statisticTable.RealtimeCount) * selectivity
+	ds.deriveIndexPathStats(skipPath, nil, false)
+	return skipPath, nil
+}
+
+func (ds *DataSource) isLeadingColumnAnalyzed(idxCols []*expression.Column) bool {
+	if len(idxCols) == 0 {
+		return false
+	}
+	col, ok := ds.statisticTable.Columns[idxCols[0].ID]
+	return ok && col.IsStatsInitialized()
+}
+
+// isSkipScanPath returns whether the path reads the index by a skip scan, it's comparable with the other paths only
+// by the cost since whether it's better depends on the NDV of the leading column.
+func isSkipScanPath(path *util.AccessPath) bool {
+	return path.SkipScanRanges != nil
+}
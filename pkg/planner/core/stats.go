@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
@@ -107,6 +108,16 @@ func getFakeStats(schema *expression.Schema) *property.StatsInfo {
 	return profile
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(_ []*property.StatsInfo, selfSchema *expression.Schema, _ []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.StatsInfo() != nil {
+		return p.StatsInfo(), nil
+	}
+	// The number of rows can't be known before the document is evaluated, use a fake count like table functions in MySQL.
+	p.SetStats(getFakeStats(selfSchema))
+	return p.StatsInfo(), nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalShowDDLJobs) DeriveStats(_ []*property.StatsInfo, selfSchema *expression.Schema, _ []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.StatsInfo() != nil {
@@ -214,9 +225,53 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	if tbl.ExtStats == nil {
+		return ndvs
+	}
+	// The column groups not covered by any index can use the multi-column NDV of the extended statistics.
+	for _, g := range colGroups {
+		if slices.ContainsFunc(ndvs, func(ndv property.GroupNDV) bool { return groupNDVMatchCols(ndv, g) }) {
+			continue
+		}
+		for _, item := range tbl.ExtStats.Stats {
+			if item.Tp != ast.StatsTypeCardinality || item.ScalarVals <= 0 || len(item.ColIDs) != len(g) {
+				continue
+			}
+			match := true
+			for _, col := range g {
+				if !slices.Contains(item.ColIDs, col.ID) {
+					match = false
+					break
+				}
+			}
+			if match {
+				cols := make([]int64, 0, len(g))
+				for _, col := range g {
+					cols = append(cols, col.UniqueID)
+				}
+				ndvs = append(ndvs, property.GroupNDV{
+					Cols: cols,
+					NDV:  item.ScalarVals,
+				})
+				break
+			}
+		}
+	}
 	return ndvs
 }
 
+func groupNDVMatchCols(ndv property.GroupNDV, cols []*expression.Column) bool {
+	if len(ndv.Cols) != len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		if ndv.Cols[i] != col.UniqueID {
+			return false
+		}
+	}
+	return true
+}
+
 // getTblInfoForUsedStatsByPhysicalID get table name, partition name and HintedTable that will be used to record used stats.
 func getTblInfoForUsedStatsByPhysicalID(sctx base.PlanContext, id int64) (fullName string, tblInfo *model.TableInfo) {
 	fullName = "tableID " + strconv.FormatInt(id, 10)
@@ -272,6 +327,9 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	for _, col := range ds.schema.Columns {
 		tableStats.ColNDVs[col.UniqueID] = cardinality.EstimateColumnNDV(ds.statisticTable, col.ID)
 	}
+	if ds.SCtx().GetSessionVars().EnableExtendedStats && !ds.statisticTable.Pseudo {
+		tableStats.HistColl.ExtStats = ds.statisticTable.ExtendedStats
+	}
 	ds.tableStats = tableStats
 	ds.tableStats.GroupNDVs = ds.getGroupNDVs(colGroups)
 	ds.TblColHists = ds.statisticTable.ID2UniqueID(ds.TblCols)
@@ -494,6 +552,10 @@ func (ds *DataSource) DeriveStats(_ []*property.StatsInfo, _ *expression.Schema,
 	if err := ds.generateIndexMergePath(); err != nil {
 		return nil, err
 	}
+	ds.generateVectorIndexPath()
+	if err := ds.generateSkipScanPaths(); err != nil {
+		return nil, err
+	}
 
 	if ds.SCtx().GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugTraceAccessPaths(ds.SCtx(), ds.possibleAccessPaths)
@@ -832,6 +894,9 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		return p.StatsInfo(), nil
 	}
 	count := p.equalCondOutCnt
+	if p.JoinType == InnerJoin && p.SCtx().GetSessionVars().EnableCardinalityFeedback {
+		count *= cardinality.GetJoinCorrection(p.SCtx(), feedbackTableIDsOfLogicalPlan(p, nil), p.EqualConditions, p.OtherConditions)
+	}
 	if p.JoinType == LeftOuterJoin {
 		count = math.Max(count, leftProfile.RowCount)
 	} else if p.JoinType == RightOuterJoin {
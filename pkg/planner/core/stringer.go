@@ -321,6 +321,8 @@ func toString(in base.Plan, strs []string, idxs []int) ([]string, []int) {
 		str = fmt.Sprintf("%s->Update", ToString(x.SelectPlan))
 	case *Delete:
 		str = fmt.Sprintf("%s->Delete", ToString(x.SelectPlan))
+	case *Merge:
+		str = fmt.Sprintf("%s->Merge", ToString(x.SelectPlan))
 	case *Insert:
 		str = "Insert"
 		if x.SelectPlan != nil {
@@ -2433,30 +2433,45 @@ func (t *MppTask) enforceExchanger(prop *property.PhysicalProperty) *MppTask {
 }
 
 func (t *MppTask) enforceExchangerImpl(prop *property.PhysicalProperty) *MppTask {
-	if collate.NewCollationEnabled() && !t.p.SCtx().GetSessionVars().HashExchangeWithNewCollation && prop.MPPPartitionTp == property.HashType {
+	if !CanEnforceExchanger(t.p.SCtx(), prop) {
+		return &MppTask{}
+	}
+	return &MppTask{
+		p:        EnforceExchanger(t.p, prop),
+		partTp:   prop.MPPPartitionTp,
+		hashCols: prop.MPPPartitionCols,
+	}
+}
+
+// CanEnforceExchanger checks whether the data of the MPP tasks can be exchanged
+// to satisfy the MPP partition of the required property.
+func CanEnforceExchanger(ctx base.PlanContext, prop *property.PhysicalProperty) bool {
+	if collate.NewCollationEnabled() && !ctx.GetSessionVars().HashExchangeWithNewCollation && prop.MPPPartitionTp == property.HashType {
 		for _, col := range prop.MPPPartitionCols {
 			if types.IsString(col.Col.RetType.GetType()) {
-				t.p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because when `new_collation_enabled` is true, HashJoin or HashAgg with string key is not supported now.")
-				return &MppTask{}
+				ctx.GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because when `new_collation_enabled` is true, HashJoin or HashAgg with string key is not supported now.")
+				return false
 			}
 		}
 	}
-	ctx := t.p.SCtx()
+	return true
+}
+
+// EnforceExchanger adds an ExchangeSender and an ExchangeReceiver on top of the
+// MPP plan to exchange its data by the MPP partition of the required property.
+func EnforceExchanger(p base.PhysicalPlan, prop *property.PhysicalProperty) *PhysicalExchangeReceiver {
+	ctx := p.SCtx()
 	sender := PhysicalExchangeSender{
 		ExchangeType: prop.MPPPartitionTp.ToExchangeType(),
 		HashCols:     prop.MPPPartitionCols,
-	}.Init(ctx, t.p.StatsInfo())
+	}.Init(ctx, p.StatsInfo())
 
 	if ctx.GetSessionVars().ChooseMppVersion() >= kv.MppVersionV1 {
 		sender.CompressionMode = ctx.GetSessionVars().ChooseMppExchangeCompressionMode()
 	}
 
-	sender.SetChildren(t.p)
-	receiver := PhysicalExchangeReceiver{}.Init(ctx, t.p.StatsInfo())
+	sender.SetChildren(p)
+	receiver := PhysicalExchangeReceiver{}.Init(ctx, p.StatsInfo())
 	receiver.SetChildren(sender)
-	return &MppTask{
-		p:        receiver,
-		partTp:   prop.MPPPartitionTp,
-		hashCols: prop.MPPPartitionCols,
-	}
+	return receiver
 }
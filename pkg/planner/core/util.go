@@ -327,6 +327,8 @@ func GetStatsInfo(i any) map[string]uint64 {
 		physicalPlan = x.SelectPlan
 	case *Delete:
 		physicalPlan = x.SelectPlan
+	case *Merge:
+		physicalPlan = x.SelectPlan
 	case base.PhysicalPlan:
 		physicalPlan = x
 	}
@@ -0,0 +1,202 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"math"
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
+)
+
+// vectorSearch is the `ORDER BY distance(col, vec) LIMIT k` on a DataSource which can be accelerated by a VECTOR index.
+type vectorSearch struct {
+	index  *model.IndexInfo
+	column *expression.Column
+	query  types.VectorFloat32
+	// count is the number of the nearest neighbors to find, it's the count plus the offset of the TopN.
+	count uint64
+}
+
+// PushDownTopN implements the LogicalPlan interface.
+// The DataSource records the TopN which can read the candidates of the nearest neighbors from a VECTOR index,
+// the TopN is kept above the DataSource to sort the candidates by the exact distance.
+func (ds *DataSource) PushDownTopN(topNLogicalPlan base.LogicalPlan, opt *optimizetrace.LogicalOptimizeOp) base.LogicalPlan {
+	if topN, ok := topNLogicalPlan.(*LogicalTopN); ok && topN != nil {
+		ds.vectorSearch = ds.extractVectorSearch(topN)
+	}
+	return ds.baseLogicalPlan.PushDownTopN(topNLogicalPlan, opt)
+}
+
+// extractVectorSearch returns the vector search of the TopN, it returns nil if the TopN can't use a VECTOR index.
+// Only the TopN ordering by the ascending distance between a column and a constant vector without any filter
+// is supported, since the filters may remove the candidates and make the results fewer than expected.
+func (ds *DataSource) extractVectorSearch(topN *LogicalTopN) *vectorSearch {
+	if len(ds.pushedDownConds) > 0 || len(topN.ByItems) != 1 || topN.ByItems[0].Desc || len(topN.PartitionBy) > 0 ||
+		topN.Count == 0 || topN.Count+topN.Offset > math.MaxInt32 {
+		return nil
+	}
+	sf, ok := topN.ByItems[0].Expr.(*expression.ScalarFunction)
+	if !ok {
+		return nil
+	}
+	metric, ok := vectorindex.DistanceMetricOf(sf.FuncName.L)
+	if !ok {
+		return nil
+	}
+	args := sf.GetArgs()
+	col, ok := args[0].(*expression.Column)
+	con, isConst := args[1].(*expression.Constant)
+	if !ok || !isConst {
+		// The distance is symmetric.
+		col, ok = args[1].(*expression.Column)
+		con, isConst = args[0].(*expression.Constant)
+		if !ok || !isConst {
+			return nil
+		}
+	}
+	if con.ParamMarker != nil || con.DeferredExpr != nil || con.Value.IsNull() ||
+		col.GetType().GetType() != mysql.TypeTiDBVectorFloat32 {
+		return nil
+	}
+	useInvisible := ds.SCtx().GetSessionVars().OptimizerUseInvisibleIndexes
+	var idx *model.IndexInfo
+	for _, index := range ds.tableInfo.Indices {
+		if index.IsVector() && index.State == model.StatePublic && (!index.Invisible || useInvisible) &&
+			index.VectorInfo.DistanceMetric == metric && ds.tableInfo.Columns[index.Columns[0].Offset].ID == col.ID &&
+			ds.isIndexAllowedByHints(index) {
+			idx = index
+			break
+		}
+	}
+	if idx == nil {
+		return nil
+	}
+	query, err := types.DecodeVectorFloat32(con.Value.GetBytes())
+	if err != nil || len(query) != idx.VectorInfo.Dimension {
+		return nil
+	}
+	return &vectorSearch{index: idx, column: col, query: query, count: topN.Count + topN.Offset}
+}
+
+// isIndexAllowedByHints returns whether the index hints of the DataSource allow reading the index, the index must be
+// named by the USE or FORCE hints if there are any, and it must not be named by the IGNORE hints.
+func (ds *DataSource) isIndexAllowedByHints(index *model.IndexInfo) bool {
+	tblName := ds.tableInfo.Name
+	if ds.TableAsName != nil && ds.TableAsName.L != "" {
+		tblName = *ds.TableAsName
+	}
+	hints := make([]*ast.IndexHint, 0, len(ds.astIndexHints)+len(ds.IndexHints))
+	hints = append(hints, ds.astIndexHints...)
+	for i := range ds.IndexHints {
+		if ds.IndexHints[i].Match(ds.DBName, tblName) {
+			hints = append(hints, ds.IndexHints[i].IndexHint)
+		}
+	}
+	hasUseOrForce, used := false, false
+	for _, hint := range hints {
+		if hint.HintScope != ast.HintForScan {
+			continue
+		}
+		named := slices.ContainsFunc(hint.IndexNames, func(name model.CIStr) bool {
+			return name.L == index.Name.L
+		})
+		switch hint.HintType {
+		case ast.HintIgnore:
+			if named {
+				return false
+			}
+		case ast.HintUse, ast.HintForce:
+			hasUseOrForce = true
+			used = used || named
+		}
+	}
+	return !hasUseOrForce || used
+}
+
+// generateVectorIndexPath generates the index path which reads the candidates of the nearest neighbors from the
+// VECTOR index. It probes the partitions closest to the query vector, at least `tidb_vector_search_nprobe` of them
+// and enough to hold twice the number of the neighbors. If the probes cover a large part of the index, the exact
+// search by the table scan is preferred, since the index lookup reads almost every row.
+func (ds *DataSource) generateVectorIndexPath() {
+	search := ds.vectorSearch
+	if search == nil {
+		return
+	}
+	partitioner := vectorindex.GetPartitioner(search.index.VectorInfo)
+	numPartitions := partitioner.NumPartitions()
+	rowCount := ds.tableStats.RowCount
+	rowsPerPartition := max(1, rowCount/float64(numPartitions))
+	nProbe := max(ds.SCtx().GetSessionVars().VectorSearchNProbe, int(math.Ceil(2*float64(search.count)/rowsPerPartition)))
+	if nProbe*2 > numPartitions {
+		return
+	}
+	var idxCol *expression.Column
+	for _, col := range ds.TblCols {
+		if col.ID == search.column.ID {
+			idxCol = col
+			break
+		}
+	}
+	if idxCol == nil {
+		return
+	}
+
+	probes := partitioner.Probes(search.query, nProbe)
+	slices.Sort(probes)
+	vals := make([]types.Datum, 0, len(probes)+1)
+	if !mysql.HasNotNullFlag(idxCol.RetType.GetFlag()) {
+		// The NULL vectors are indexed as NULL, they're sorted first by the distance so they're always read.
+		vals = append(vals, types.Datum{})
+	}
+	for _, probe := range probes {
+		vals = append(vals, types.NewBytesDatum(vectorindex.EncodePartition(probe)))
+	}
+	ranges := make([]*ranger.Range, 0, len(vals))
+	for _, val := range vals {
+		ranges = append(ranges, &ranger.Range{
+			LowVal:    []types.Datum{val},
+			HighVal:   []types.Datum{val},
+			Collators: []collate.Collator{collate.GetBinaryCollator()},
+		})
+	}
+	count := min(rowCount, rowCount*float64(len(probes))/float64(numPartitions))
+	ds.possibleAccessPaths = append(ds.possibleAccessPaths, &util.AccessPath{
+		Index:            search.index,
+		IdxCols:          []*expression.Column{idxCol},
+		IdxColLens:       []int{types.UnspecifiedLength},
+		FullIdxCols:      []*expression.Column{idxCol},
+		FullIdxColLens:   []int{types.UnspecifiedLength},
+		Ranges:           ranges,
+		CountAfterAccess: count,
+		CountAfterIndex:  count,
+	})
+}
+
+// isVectorIndexPath returns whether the path reads a VECTOR index, its ranges are the partitions to probe rather
+// than built from the access conditions, so it isn't comparable with the other paths by the access conditions.
+func isVectorIndexPath(path *util.AccessPath) bool {
+	return !path.IsTablePath() && path.Index != nil && path.Index.IsVector()
+}
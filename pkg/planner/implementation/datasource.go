@@ -22,7 +22,10 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
 	"github.com/pingcap/tidb/pkg/planner/memo"
+	"github.com/pingcap/tidb/pkg/planner/property"
+	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
 	"github.com/pingcap/tidb/pkg/statistics"
 )
 
@@ -102,6 +105,90 @@ func (impl *TableReaderImpl) GetCostLimit(costLimit float64, _ ...memo.Implement
 	return costLimit * copIterWorkers
 }
 
+// MPPReaderImpl implementation of PhysicalTableReader reading from the MPP
+// tasks of TiFlash.
+type MPPReaderImpl struct {
+	baseImpl
+	pruningConds []expression.Expression
+}
+
+// NewMPPReaderImpl creates a new MPP reader Implementation.
+func NewMPPReaderImpl(reader *plannercore.PhysicalTableReader, pruningConds []expression.Expression) *MPPReaderImpl {
+	return &MPPReaderImpl{
+		baseImpl:     baseImpl{plan: reader},
+		pruningConds: pruningConds,
+	}
+}
+
+// CalcCost calculates the cost of the MPP reader Implementation.
+func (impl *MPPReaderImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	mppPlan := children[0].GetPlan()
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	width := 1.0
+	if hists := mppPlan.StatsInfo().HistColl; hists != nil {
+		width = cardinality.GetAvgRowSize(impl.plan.SCtx(), hists, mppPlan.Schema().Columns, false, false)
+	}
+	networkCost := outCount * sessVars.GetNetworkFactor(nil) * width
+	impl.cost = (networkCost + children[0].GetCost()) / impl.getCostScale()
+	return impl.cost
+}
+
+// GetCostLimit implements Implementation interface.
+func (impl *MPPReaderImpl) GetCostLimit(costLimit float64, _ ...memo.Implementation) float64 {
+	scale := impl.getCostScale()
+	if math.MaxFloat64/scale < costLimit {
+		return math.MaxFloat64
+	}
+	return costLimit * scale
+}
+
+// getCostScale returns the divisor of the cost of the MPP tasks. The MPP tasks
+// run in parallel on TiFlash nodes, and the cost is made much lower to prefer
+// them when the MPP mode is enforced.
+func (impl *MPPReaderImpl) getCostScale() float64 {
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	scale := sessVars.CopTiFlashConcurrencyFactor
+	if sessVars.IsMPPEnforced() {
+		scale *= 1000000000
+	}
+	return scale
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *MPPReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalTableReader)
+	reader.AttachMPPPlan(children[0].GetPlan(), impl.pruningConds)
+	return impl
+}
+
+// IndexMergeReaderImpl implementation of PhysicalIndexMergeReader.
+type IndexMergeReaderImpl struct {
+	baseImpl
+}
+
+// NewIndexMergeReaderImpl creates a new IndexMergeReader Implementation. The plan
+// may be a Selection or a Projection on top of the PhysicalIndexMergeReader.
+func NewIndexMergeReaderImpl(plan base.PhysicalPlan) *IndexMergeReaderImpl {
+	return &IndexMergeReaderImpl{baseImpl{plan: plan}}
+}
+
+// CalcCost calculates the cost of the IndexMergeReader Implementation.
+func (impl *IndexMergeReaderImpl) CalcCost(_ float64, _ ...memo.Implementation) float64 {
+	cost, err := impl.plan.GetPlanCostVer1(property.RootTaskType, optimizetrace.NewDefaultPlanCostOption())
+	if err != nil {
+		cost = math.MaxFloat64
+	}
+	impl.cost = cost
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+// The partial and table plans are built with the reader, so there is nothing
+// to attach.
+func (impl *IndexMergeReaderImpl) AttachChildren(_ ...memo.Implementation) memo.Implementation {
+	return impl
+}
+
 // TableScanImpl implementation of PhysicalTableScan.
 type TableScanImpl struct {
 	baseImpl
@@ -124,7 +211,12 @@ func NewTableScanImpl(ts *plannercore.PhysicalTableScan, cols []*expression.Colu
 // CalcCost calculates the cost of the table scan Implementation.
 func (impl *TableScanImpl) CalcCost(outCount float64, _ ...memo.Implementation) float64 {
 	ts := impl.plan.(*plannercore.PhysicalTableScan)
-	width := cardinality.GetTableAvgRowSize(impl.plan.SCtx(), impl.tblColHists, impl.tblCols, kv.TiKV, true)
+	cols := impl.tblCols
+	if ts.StoreType == kv.TiFlash {
+		// TiFlash is a columnar storage, only the scanned columns are read.
+		cols = ts.Schema().Columns
+	}
+	width := cardinality.GetTableAvgRowSize(impl.plan.SCtx(), impl.tblColHists, cols, ts.StoreType, true)
 	sessVars := ts.SCtx().GetSessionVars()
 	impl.cost = outCount * sessVars.GetScanFactor(ts.Table) * width
 	if ts.Desc {
@@ -47,6 +47,16 @@ func NewShowImpl(show *plannercore.PhysicalShow) *ShowImpl {
 	return &ShowImpl{baseImpl: baseImpl{plan: show}}
 }
 
+// CTEImpl is the Implementation of PhysicalCTE.
+type CTEImpl struct {
+	baseImpl
+}
+
+// NewCTEImpl creates a new CTEImpl.
+func NewCTEImpl(cte *plannercore.PhysicalCTE) *CTEImpl {
+	return &CTEImpl{baseImpl: baseImpl{plan: cte}}
+}
+
 // TiDBSelectionImpl is the implementation of PhysicalSelection in TiDB layer.
 type TiDBSelectionImpl struct {
 	baseImpl
@@ -81,6 +91,41 @@ func NewTiKVSelectionImpl(sel *plannercore.PhysicalSelection) *TiKVSelectionImpl
 	return &TiKVSelectionImpl{baseImpl{plan: sel}}
 }
 
+// TiFlashSelectionImpl is the implementation of PhysicalSelection in TiFlash layer.
+type TiFlashSelectionImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (sel *TiFlashSelectionImpl) CalcCost(_ float64, children ...memo.Implementation) float64 {
+	sel.cost = children[0].GetPlan().StatsInfo().RowCount*
+		sel.plan.SCtx().GetSessionVars().GetCPUFactor() + children[0].GetCost()
+	return sel.cost
+}
+
+// NewTiFlashSelectionImpl creates a new TiFlashSelectionImpl.
+func NewTiFlashSelectionImpl(sel *plannercore.PhysicalSelection) *TiFlashSelectionImpl {
+	return &TiFlashSelectionImpl{baseImpl{plan: sel}}
+}
+
+// ExchangeReceiverImpl is the implementation of PhysicalExchangeReceiver.
+type ExchangeReceiverImpl struct {
+	baseImpl
+}
+
+// NewExchangeReceiverImpl creates a new ExchangeReceiverImpl.
+func NewExchangeReceiverImpl(receiver *plannercore.PhysicalExchangeReceiver) *ExchangeReceiverImpl {
+	return &ExchangeReceiverImpl{baseImpl{plan: receiver}}
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+// The child of the receiver is the ExchangeSender, the children are attached to it.
+func (impl *ExchangeReceiverImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	sender := impl.plan.Children()[0]
+	sender.SetChildren(children[0].GetPlan())
+	return impl
+}
+
 // TiDBHashAggImpl is the implementation of PhysicalHashAgg in TiDB layer.
 type TiDBHashAggImpl struct {
 	baseImpl
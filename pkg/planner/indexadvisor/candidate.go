@@ -0,0 +1,344 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/config"
+	infoschema "github.com/pingcap/tidb/pkg/infoschema/context"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+)
+
+// maxIndexColumns is the maximum number of the columns of a candidate index.
+const maxIndexColumns = 3
+
+// candidateIndex is an index which may benefit the workload, it's evaluated as a hypothetical index.
+type candidateIndex struct {
+	id      int
+	schema  model.CIStr
+	table   *model.TableInfo
+	columns []*model.ColumnInfo
+}
+
+// key identifies the candidate by its table and columns.
+func (c *candidateIndex) key() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s.%s(", c.schema.L, c.table.Name.L)
+	for i, col := range c.columns {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(col.Name.L)
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// hypoName returns the name of the candidate as a hypothetical index, it's unique so the index is recognized in the
+// plans.
+func (c *candidateIndex) hypoName() string {
+	return fmt.Sprintf("hypo_idx_%d", c.id)
+}
+
+// indexInfo returns the hypothetical index of the candidate.
+func (c *candidateIndex) indexInfo() *model.IndexInfo {
+	cols := make([]*model.IndexColumn, 0, len(c.columns))
+	for _, col := range c.columns {
+		cols = append(cols, &model.IndexColumn{Name: col.Name, Offset: col.Offset, Length: types.UnspecifiedLength})
+	}
+	return &model.IndexInfo{
+		Name:    model.NewCIStr(c.hypoName()),
+		Table:   c.table.Name,
+		Columns: cols,
+		State:   model.StatePublic,
+		Tp:      model.IndexTypeHypo,
+	}
+}
+
+// tableRef is a base table referenced by a query.
+type tableRef struct {
+	schema model.CIStr
+	table  *model.TableInfo
+	// name is the name referring to the table in the query, it's the alias if there is one.
+	name model.CIStr
+
+	eqCols    []*model.ColumnInfo
+	rangeCols []*model.ColumnInfo
+	// orderCols are the columns of the GROUP BY and ORDER BY clauses which only consist of the columns of the table.
+	orderCols [][]*model.ColumnInfo
+}
+
+// column returns the visible column of the table by its name, it returns nil if there is no such column.
+func (t *tableRef) column(name model.CIStr) *model.ColumnInfo {
+	col := model.FindColumnInfo(t.table.Columns, name.L)
+	if col == nil || col.Hidden || col.State != model.StatePublic {
+		return nil
+	}
+	return col
+}
+
+// tableCollector collects the base tables referenced by a query.
+type tableCollector struct {
+	is        infoschema.MetaOnlyInfoSchema
+	defaultDB string
+	refs      []*tableRef
+}
+
+func (c *tableCollector) Enter(n ast.Node) (ast.Node, bool) {
+	ts, ok := n.(*ast.TableSource)
+	if !ok {
+		return n, false
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return n, false
+	}
+	schema := tn.Schema
+	if schema.L == "" {
+		schema = model.NewCIStr(c.defaultDB)
+	}
+	if util.IsMemOrSysDB(schema.L) {
+		return n, false
+	}
+	// The CTEs and the tables which don't exist are skipped, the columns referring to them are ignored.
+	tbl, err := c.is.TableInfoByName(schema, tn.Name)
+	if err != nil || tbl.IsView() || tbl.IsSequence() || tbl.TempTableType != model.TempTableNone {
+		return n, false
+	}
+	name := ts.AsName
+	if name.L == "" {
+		name = tn.Name
+	}
+	c.refs = append(c.refs, &tableRef{schema: schema, table: tbl, name: name})
+	return n, false
+}
+
+func (*tableCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// columnCollector collects the columns which can be the keys of an index, they're the columns compared with
+// others in the predicates, and the columns of the GROUP BY and ORDER BY clauses.
+type columnCollector struct {
+	refs []*tableRef
+}
+
+func (c *columnCollector) Enter(n ast.Node) (ast.Node, bool) {
+	switch x := n.(type) {
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.EQ, opcode.NullEQ:
+			c.addEq(x.L)
+			c.addEq(x.R)
+		case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+			c.addRange(x.L)
+			c.addRange(x.R)
+		}
+	case *ast.PatternInExpr:
+		if !x.Not {
+			c.addEq(x.Expr)
+		}
+	case *ast.IsNullExpr:
+		if !x.Not {
+			c.addEq(x.Expr)
+		}
+	case *ast.BetweenExpr:
+		if !x.Not {
+			c.addRange(x.Expr)
+		}
+	case *ast.PatternLikeOrIlikeExpr:
+		if !x.Not && x.IsLike {
+			c.addRange(x.Expr)
+		}
+	case *ast.GroupByClause:
+		c.addOrder(x.Items)
+	case *ast.OrderByClause:
+		c.addOrder(x.Items)
+	}
+	return n, false
+}
+
+func (*columnCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// resolve returns the table and the column referred by the expression, it returns nil if the expression isn't a
+// column of the base tables or the column is ambiguous.
+func (c *columnCollector) resolve(expr ast.ExprNode) (*tableRef, *model.ColumnInfo) {
+	e, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	name := e.Name
+	var (
+		ref *tableRef
+		col *model.ColumnInfo
+	)
+	for _, r := range c.refs {
+		if name.Table.L != "" && (r.name.L != name.Table.L || (name.Schema.L != "" && r.schema.L != name.Schema.L)) {
+			continue
+		}
+		if found := r.column(name.Name); found != nil {
+			if ref != nil {
+				return nil, nil
+			}
+			ref, col = r, found
+		}
+	}
+	// The integer primary key is the handle, which is always stored in the indexes.
+	if ref == nil || !isIndexable(col) || (ref.table.PKIsHandle && mysql.HasPriKeyFlag(col.GetFlag())) {
+		return nil, nil
+	}
+	return ref, col
+}
+
+func (c *columnCollector) addEq(expr ast.ExprNode) {
+	if ref, col := c.resolve(expr); ref != nil {
+		ref.eqCols = appendColumn(ref.eqCols, col)
+	}
+}
+
+func (c *columnCollector) addRange(expr ast.ExprNode) {
+	if ref, col := c.resolve(expr); ref != nil {
+		ref.rangeCols = appendColumn(ref.rangeCols, col)
+	}
+}
+
+// addOrder adds the columns of the GROUP BY or ORDER BY clause, an index can only provide the order if all the
+// items are the columns of the same table.
+func (c *columnCollector) addOrder(items []*ast.ByItem) {
+	var (
+		ref  *tableRef
+		cols []*model.ColumnInfo
+	)
+	for _, item := range items {
+		r, col := c.resolve(item.Expr)
+		if r == nil || (ref != nil && r != ref) || item.Desc != items[0].Desc {
+			return
+		}
+		ref, cols = r, appendColumn(cols, col)
+	}
+	if ref != nil {
+		ref.orderCols = append(ref.orderCols, cols)
+	}
+}
+
+func appendColumn(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	if slices.Contains(cols, col) {
+		return cols
+	}
+	return append(cols, col)
+}
+
+// isIndexable returns whether the column can be a key of an index without the prefix length.
+func isIndexable(col *model.ColumnInfo) bool {
+	switch col.GetType() {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeJSON,
+		mysql.TypeGeometry, mysql.TypeTiDBVectorFloat32:
+		return false
+	}
+	if types.IsString(col.GetType()) {
+		cs, err := charset.GetCharsetInfo(col.GetCharset())
+		if err != nil {
+			return false
+		}
+		return col.GetFlen()*cs.Maxlen <= int(config.GetGlobalConfig().MaxIndexLength)
+	}
+	return true
+}
+
+// candidatesOf returns the candidate indexes of a query on the tables it references.
+func candidatesOf(is infoschema.MetaOnlyInfoSchema, defaultDB string, stmt ast.StmtNode) []*candidateIndex {
+	tables := &tableCollector{is: is, defaultDB: defaultDB}
+	stmt.Accept(tables)
+	if len(tables.refs) == 0 {
+		return nil
+	}
+	stmt.Accept(&columnCollector{refs: tables.refs})
+
+	var candidates []*candidateIndex
+	add := func(ref *tableRef, cols []*model.ColumnInfo) {
+		if len(cols) == 0 {
+			return
+		}
+		if len(cols) > maxIndexColumns {
+			cols = cols[:maxIndexColumns]
+		}
+		if isCoveredByExistingIndex(ref.table, cols) {
+			return
+		}
+		candidates = append(candidates, &candidateIndex{schema: ref.schema, table: ref.table, columns: cols})
+	}
+	for _, ref := range tables.refs {
+		for _, col := range ref.eqCols {
+			add(ref, []*model.ColumnInfo{col})
+		}
+		for _, col := range ref.rangeCols {
+			add(ref, []*model.ColumnInfo{col})
+		}
+		if len(ref.eqCols) > 1 {
+			add(ref, ref.eqCols)
+		}
+		// The equal columns come first, then a range column or the order columns, so the index can be read by a
+		// range and return the rows in order.
+		eqPrefix := ref.eqCols[:min(len(ref.eqCols), maxIndexColumns-1)]
+		for _, col := range ref.rangeCols {
+			if len(eqPrefix) > 0 && !slices.Contains(eqPrefix, col) {
+				add(ref, append(slices.Clone(eqPrefix), col))
+			}
+		}
+		for _, orderCols := range ref.orderCols {
+			cols := make([]*model.ColumnInfo, 0, len(eqPrefix)+len(orderCols))
+			for _, col := range eqPrefix {
+				if !slices.Contains(orderCols, col) {
+					cols = append(cols, col)
+				}
+			}
+			add(ref, append(cols, orderCols...))
+		}
+	}
+	return candidates
+}
+
+// isCoveredByExistingIndex returns whether an existing index of the table starts with the columns, such an index can
+// do everything the candidate does.
+func isCoveredByExistingIndex(tbl *model.TableInfo, cols []*model.ColumnInfo) bool {
+	for _, idx := range tbl.Indices {
+		if idx.State != model.StatePublic || idx.Invisible || idx.MVIndex || idx.IsVector() || idx.IsFullText() ||
+			len(idx.Columns) < len(cols) {
+			continue
+		}
+		covered := true
+		for i, col := range cols {
+			if idx.Columns[i].Offset != col.Offset || idx.Columns[i].Length != types.UnspecifiedLength {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
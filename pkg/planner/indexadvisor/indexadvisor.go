@@ -0,0 +1,317 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	infoschema "github.com/pingcap/tidb/pkg/infoschema/context"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+const (
+	// DefaultMaxNumIndexes is the default maximum number of the recommended indexes.
+	DefaultMaxNumIndexes = 5
+	// minBenefit is the minimum ratio of the workload cost an index must reduce to be recommended.
+	minBenefit = 0.01
+)
+
+// Query is a query of the workload to recommend the indexes for.
+type Query struct {
+	SchemaName string
+	Text       string
+	// Frequency is the number of the executions of the query, the cost of the query is weighted by it.
+	Frequency int64
+}
+
+// Recommendation is an index recommended for the workload.
+type Recommendation struct {
+	Database  string
+	Table     string
+	IndexName string
+	Columns   []string
+	// Benefit is the ratio of the workload cost reduced by the index.
+	Benefit float64
+	// ImpactedQueries are the queries whose plans use the index, they're ordered by the cost reduced.
+	ImpactedQueries []*Query
+}
+
+// CreateIndexSQL returns the statement to create the recommended index.
+func (r *Recommendation) CreateIndexSQL() string {
+	var sb strings.Builder
+	sqlescape.MustFormatSQL(&sb, "CREATE INDEX %n ON %n.%n (", r.IndexName, r.Database, r.Table)
+	for i, col := range r.Columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sqlescape.MustFormatSQL(&sb, "%n", col)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// AdviseIndexes recommends at most maxNumIndexes indexes for the queries. The candidates are built from the columns
+// in the predicates, GROUP BY and ORDER BY clauses of the queries, and they're evaluated by the optimizer as the
+// hypothetical indexes. The indexes are chosen greedily: the candidate reducing the workload cost most is added in
+// every round, until no candidate reduces the cost notably. The queries which can't be planned are skipped with a
+// warning.
+func AdviseIndexes(ctx context.Context, sctx sessionctx.Context, queries []*Query, maxNumIndexes int) ([]*Recommendation, error) {
+	vars := sctx.GetSessionVars()
+	// The bindings and the plan cache may make the plans ignore the hypothetical indexes.
+	usePlanBaselines, enablePlanCache := vars.UsePlanBaselines, vars.EnableNonPreparedPlanCache
+	vars.UsePlanBaselines, vars.EnableNonPreparedPlanCache = false, false
+	defer func() {
+		vars.UsePlanBaselines, vars.EnableNonPreparedPlanCache = usePlanBaselines, enablePlanCache
+	}()
+
+	// The statement context is replaced by the EXPLAIN statements, the warnings go to the one of the caller.
+	stmtCtx := vars.StmtCtx
+	a := newAdvisor(&explainOptimizer{sctx: sctx}, stmtCtx.AppendWarning)
+	p := parser.New()
+	p.SetSQLMode(vars.SQLMode)
+	a.addQueries(p, sctx.GetInfoSchema(), queries, vars.GetParseParams()...)
+	return a.advise(ctx, maxNumIndexes)
+}
+
+// workloadQuery is a query of the workload with its candidates.
+type workloadQuery struct {
+	*Query
+	// tables are the IDs of the tables which the query has candidates on, only the candidates on them are planned
+	// with the query.
+	tables   map[int64]struct{}
+	baseCost float64
+}
+
+type planResult struct {
+	cost float64
+	used []*candidateIndex
+}
+
+type advisor struct {
+	optimizer  whatIfOptimizer
+	warn       func(error)
+	queries    []*workloadQuery
+	candidates []*candidateIndex
+	plans      map[string]*planResult
+}
+
+func newAdvisor(optimizer whatIfOptimizer, warn func(error)) *advisor {
+	return &advisor{optimizer: optimizer, warn: warn, plans: make(map[string]*planResult)}
+}
+
+// addQueries adds the queries and their candidates to the advisor, the queries without candidates are ignored.
+func (a *advisor) addQueries(p *parser.Parser, is infoschema.MetaOnlyInfoSchema, queries []*Query, params ...parser.ParseParam) {
+	seen := make(map[string]*candidateIndex, len(a.candidates))
+	for _, c := range a.candidates {
+		seen[c.key()] = c
+	}
+	for _, q := range queries {
+		stmts, _, err := p.ParseSQL(q.Text, params...)
+		if err == nil && len(stmts) != 1 {
+			err = errors.New("not a single statement")
+		}
+		if err != nil {
+			a.warn(errors.NewNoStackErrorf("RECOMMEND INDEX skips the query '%s': %v", q.Text, err))
+			continue
+		}
+		stmt := stmts[0]
+		wq := &workloadQuery{Query: q, tables: make(map[int64]struct{})}
+		for _, c := range candidatesOf(is, q.SchemaName, stmt) {
+			wq.tables[c.table.ID] = struct{}{}
+			if _, ok := seen[c.key()]; ok {
+				continue
+			}
+			c.id = len(a.candidates)
+			seen[c.key()] = c
+			a.candidates = append(a.candidates, c)
+		}
+		if len(wq.tables) > 0 {
+			a.queries = append(a.queries, wq)
+		}
+	}
+}
+
+// plan returns the plan of the query with the candidates on its tables in the configuration.
+func (a *advisor) plan(ctx context.Context, qIdx int, config []*candidateIndex) (*planResult, error) {
+	q := a.queries[qIdx]
+	relevant := make([]*candidateIndex, 0, len(config))
+	for _, c := range config {
+		if _, ok := q.tables[c.table.ID]; ok {
+			relevant = append(relevant, c)
+		}
+	}
+	slices.SortFunc(relevant, func(x, y *candidateIndex) int {
+		return cmp.Compare(x.id, y.id)
+	})
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(qIdx))
+	for _, c := range relevant {
+		sb.WriteByte(',')
+		sb.WriteString(strconv.Itoa(c.id))
+	}
+	key := sb.String()
+	if p, ok := a.plans[key]; ok {
+		return p, nil
+	}
+	cost, used, err := a.optimizer.plan(ctx, q.Query, relevant)
+	if err != nil {
+		return nil, err
+	}
+	p := &planResult{cost: cost, used: used}
+	a.plans[key] = p
+	return p, nil
+}
+
+func (a *advisor) workloadCost(ctx context.Context, config []*candidateIndex) (float64, error) {
+	var total float64
+	for i, q := range a.queries {
+		p, err := a.plan(ctx, i, config)
+		if err != nil {
+			return 0, err
+		}
+		total += p.cost * float64(q.Frequency)
+	}
+	return total, nil
+}
+
+func (a *advisor) advise(ctx context.Context, maxNumIndexes int) ([]*Recommendation, error) {
+	// The queries which can't be planned without any candidate are skipped.
+	queries := a.queries[:0]
+	for _, q := range a.queries {
+		cost, _, err := a.optimizer.plan(ctx, q.Query, nil)
+		if err != nil {
+			a.warn(errors.NewNoStackErrorf("RECOMMEND INDEX skips the query '%s': %v", q.Text, err))
+			continue
+		}
+		q.baseCost = cost
+		queries = append(queries, q)
+	}
+	a.queries = queries
+	var baseCost float64
+	for i, q := range a.queries {
+		a.plans[strconv.Itoa(i)] = &planResult{cost: q.baseCost}
+		baseCost += q.baseCost * float64(q.Frequency)
+	}
+	if baseCost <= 0 {
+		return nil, nil
+	}
+
+	var chosen []*candidateIndex
+	benefits := make(map[*candidateIndex]float64)
+	currentCost := baseCost
+	for len(chosen) < maxNumIndexes {
+		var best *candidateIndex
+		bestCost := currentCost
+		for _, c := range a.candidates {
+			if slices.Contains(chosen, c) {
+				continue
+			}
+			cost, err := a.workloadCost(ctx, append(slices.Clone(chosen), c))
+			if err != nil {
+				return nil, err
+			}
+			if cost < bestCost {
+				best, bestCost = c, cost
+			}
+		}
+		if best == nil || (currentCost-bestCost)/baseCost < minBenefit {
+			break
+		}
+		chosen = append(chosen, best)
+		benefits[best] = (currentCost - bestCost) / baseCost
+		currentCost = bestCost
+	}
+	return a.recommend(ctx, chosen, benefits)
+}
+
+// recommend builds the recommendations of the chosen candidates, the ones not used by the final plans of the
+// workload are dropped.
+func (a *advisor) recommend(ctx context.Context, chosen []*candidateIndex, benefits map[*candidateIndex]float64) ([]*Recommendation, error) {
+	type impact struct {
+		query   *Query
+		reduced float64
+	}
+	impacts := make(map[*candidateIndex][]impact)
+	for i, q := range a.queries {
+		p, err := a.plan(ctx, i, chosen)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range p.used {
+			impacts[c] = append(impacts[c], impact{query: q.Query, reduced: (q.baseCost - p.cost) * float64(q.Frequency)})
+		}
+	}
+	names := make(map[string]struct{})
+	recs := make([]*Recommendation, 0, len(chosen))
+	for _, c := range chosen {
+		if len(impacts[c]) == 0 {
+			continue
+		}
+		slices.SortStableFunc(impacts[c], func(x, y impact) int {
+			return cmp.Compare(y.reduced, x.reduced)
+		})
+		rec := &Recommendation{
+			Database:  c.schema.O,
+			Table:     c.table.Name.O,
+			IndexName: indexNameOf(c, names),
+			Benefit:   benefits[c],
+		}
+		for _, col := range c.columns {
+			rec.Columns = append(rec.Columns, col.Name.O)
+		}
+		for _, im := range impacts[c] {
+			rec.ImpactedQueries = append(rec.ImpactedQueries, im.query)
+		}
+		recs = append(recs, rec)
+	}
+	slices.SortStableFunc(recs, func(x, y *Recommendation) int {
+		return cmp.Compare(y.Benefit, x.Benefit)
+	})
+	return recs, nil
+}
+
+// indexNameOf names the candidate by its columns, the name doesn't conflict with the existing indexes of the table
+// and the names already taken.
+func indexNameOf(c *candidateIndex, taken map[string]struct{}) string {
+	parts := make([]string, 0, len(c.columns)+1)
+	parts = append(parts, "idx")
+	for _, col := range c.columns {
+		parts = append(parts, col.Name.L)
+	}
+	base := strings.Join(parts, "_")
+	if len(base) > mysql.MaxIndexIdentifierLen {
+		base = base[:mysql.MaxIndexIdentifierLen]
+	}
+	tableKey := c.schema.L + "." + c.table.Name.L + "."
+	name := base
+	for i := 2; ; i++ {
+		if _, ok := taken[tableKey+name]; !ok && c.table.FindIndexByName(name) == nil {
+			break
+		}
+		suffix := fmt.Sprintf("_%d", i)
+		name = base[:min(len(base), mysql.MaxIndexIdentifierLen-len(suffix))] + suffix
+	}
+	taken[tableKey+name] = struct{}{}
+	return name
+}
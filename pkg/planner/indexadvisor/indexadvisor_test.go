@@ -0,0 +1,171 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func mockInfoSchema() infoschema.InfoSchema {
+	newCol := func(offset int, name string, tp byte, flag uint) *model.ColumnInfo {
+		ft := types.NewFieldType(tp)
+		ft.AddFlag(flag)
+		if types.IsString(tp) {
+			ft.SetCharset(charset.CharsetUTF8MB4)
+			ft.SetCollate(charset.CollationUTF8MB4)
+			ft.SetFlen(20)
+		}
+		return &model.ColumnInfo{ID: int64(offset + 1), Offset: offset, Name: model.NewCIStr(name), FieldType: *ft, State: model.StatePublic}
+	}
+	t := &model.TableInfo{
+		ID:   100,
+		Name: model.NewCIStr("t"),
+		Columns: []*model.ColumnInfo{
+			newCol(0, "a", mysql.TypeLonglong, mysql.PriKeyFlag|mysql.NotNullFlag),
+			newCol(1, "b", mysql.TypeLong, 0),
+			newCol(2, "c", mysql.TypeVarchar, 0),
+			newCol(3, "d", mysql.TypeBlob, 0),
+			newCol(4, "e", mysql.TypeLong, 0),
+		},
+		Indices: []*model.IndexInfo{{
+			ID:      1,
+			Name:    model.NewCIStr("idx_b"),
+			Columns: []*model.IndexColumn{{Name: model.NewCIStr("e"), Offset: 4, Length: types.UnspecifiedLength}},
+			State:   model.StatePublic,
+			Tp:      model.IndexTypeBtree,
+		}},
+		PKIsHandle: true,
+		State:      model.StatePublic,
+	}
+	s := &model.TableInfo{
+		ID:   101,
+		Name: model.NewCIStr("s"),
+		Columns: []*model.ColumnInfo{
+			newCol(0, "a", mysql.TypeLong, 0),
+			newCol(1, "b", mysql.TypeLong, 0),
+		},
+		State: model.StatePublic,
+	}
+	return infoschema.MockInfoSchema([]*model.TableInfo{t, s})
+}
+
+func TestCandidates(t *testing.T) {
+	is := mockInfoSchema()
+	p := parser.New()
+	cases := []struct {
+		sql        string
+		candidates []string
+	}{
+		{
+			sql:        "select * from t where b = 1 and c > 'x' order by e",
+			candidates: []string{"test.t(b)", "test.t(c)", "test.t(b,c)", "test.t(b,e)"},
+		},
+		{
+			sql:        "select * from t join s x on t.b = x.a where x.b in (1, 2) and t.d = 'a' and t.a = 1",
+			candidates: []string{"test.t(b)", "test.s(a)", "test.s(b)", "test.s(a,b)"},
+		},
+		{
+			sql:        "update test.s set a = 1 where b between 1 and 10 and a is null",
+			candidates: []string{"test.s(a)", "test.s(b)", "test.s(a,b)"},
+		},
+		{
+			// The column existing in both tables is ambiguous.
+			sql: "select * from t, s where b = 1 and e = 1",
+		},
+		{
+			sql:        "select c, count(*) from t where b + 1 = 2 group by c",
+			candidates: []string{"test.t(c)"},
+		},
+		{
+			sql: "select * from mysql.user where user = 'root'",
+		},
+	}
+	for _, c := range cases {
+		stmt, err := p.ParseOneStmt(c.sql, "", "")
+		require.NoError(t, err, c.sql)
+		var keys []string
+		for _, cand := range candidatesOf(is, "test", stmt) {
+			keys = append(keys, cand.key())
+		}
+		require.Equal(t, c.candidates, keys, c.sql)
+	}
+}
+
+// mockOptimizer reduces the cost of the queries to the costs of the candidates.
+type mockOptimizer struct {
+	costs map[string]map[string]float64
+}
+
+func (o *mockOptimizer) plan(_ context.Context, q *Query, indexes []*candidateIndex) (float64, []*candidateIndex, error) {
+	cost := 100.0
+	var used []*candidateIndex
+	for _, idx := range indexes {
+		if c, ok := o.costs[q.Text][idx.key()]; ok && c < cost {
+			cost, used = c, []*candidateIndex{idx}
+		}
+	}
+	return cost, used, nil
+}
+
+func TestAdvise(t *testing.T) {
+	is := mockInfoSchema()
+	queries := []*Query{
+		{SchemaName: "test", Text: "select * from t where b = 1", Frequency: 10},
+		{SchemaName: "test", Text: "select * from t where c > 'x'", Frequency: 1},
+		{SchemaName: "test", Text: "select * from s where a = 1", Frequency: 1},
+		{SchemaName: "test", Text: "select * from", Frequency: 1},
+	}
+	opt := &mockOptimizer{costs: map[string]map[string]float64{
+		"select * from t where b = 1":   {"test.t(b)": 10},
+		"select * from t where c > 'x'": {"test.t(c)": 50},
+		"select * from s where a = 1":   {"test.s(a)": 99.5},
+	}}
+	advise := func(maxNumIndexes int) ([]*Recommendation, []error) {
+		var warnings []error
+		a := newAdvisor(opt, func(err error) {
+			warnings = append(warnings, err)
+		})
+		a.addQueries(parser.New(), is, queries)
+		recs, err := a.advise(context.Background(), maxNumIndexes)
+		require.NoError(t, err)
+		return recs, warnings
+	}
+
+	recs, warnings := advise(DefaultMaxNumIndexes)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), "RECOMMEND INDEX skips the query 'select * from'")
+	require.Len(t, recs, 2)
+	// The name of the existing index is not reused.
+	require.Equal(t, "idx_b_2", recs[0].IndexName)
+	require.Equal(t, []string{"b"}, recs[0].Columns)
+	require.InDelta(t, 0.75, recs[0].Benefit, 1e-9)
+	require.Equal(t, []*Query{queries[0]}, recs[0].ImpactedQueries)
+	require.Equal(t, "CREATE INDEX `idx_b_2` ON `test`.`t` (`b`)", recs[0].CreateIndexSQL())
+	require.Equal(t, "idx_c", recs[1].IndexName)
+	require.InDelta(t, 50.0/1200, recs[1].Benefit, 1e-9)
+
+	recs, _ = advise(1)
+	require.Len(t, recs, 1)
+	require.Equal(t, "idx_b_2", recs[0].IndexName)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// whatIfOptimizer plans the queries as if the candidate indexes exist.
+type whatIfOptimizer interface {
+	// plan returns the estimated cost of the query and the candidates used by its plan.
+	plan(ctx context.Context, q *Query, indexes []*candidateIndex) (float64, []*candidateIndex, error)
+}
+
+// explainOptimizer plans the queries by EXPLAIN in the session, the candidates are the hypothetical indexes of the
+// session, which are only considered by the optimizer for EXPLAIN.
+type explainOptimizer struct {
+	sctx sessionctx.Context
+}
+
+const (
+	explainCostColumn         = 2
+	explainAccessObjectColumn = 4
+)
+
+func (o *explainOptimizer) plan(ctx context.Context, q *Query, indexes []*candidateIndex) (float64, []*candidateIndex, error) {
+	vars := o.sctx.GetSessionVars()
+	hypoIndexes := make(map[string]map[string]map[string]*model.IndexInfo)
+	for _, idx := range indexes {
+		tables := hypoIndexes[idx.schema.L]
+		if tables == nil {
+			tables = make(map[string]map[string]*model.IndexInfo)
+			hypoIndexes[idx.schema.L] = tables
+		}
+		if tables[idx.table.Name.L] == nil {
+			tables[idx.table.Name.L] = make(map[string]*model.IndexInfo)
+		}
+		tables[idx.table.Name.L][idx.hypoName()] = idx.indexInfo()
+	}
+	origHypoIndexes, origDB := vars.HypoIndexes, vars.CurrentDB
+	vars.HypoIndexes, vars.CurrentDB = hypoIndexes, q.SchemaName
+	defer func() {
+		vars.HypoIndexes, vars.CurrentDB = origHypoIndexes, origDB
+	}()
+
+	exec := o.sctx.GetRestrictedSQLExecutor()
+	rows, _, err := exec.ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession},
+		"EXPLAIN FORMAT = 'verbose' "+q.Text)
+	if err != nil {
+		return 0, nil, err
+	}
+	cost := -1.0
+	var used []*candidateIndex
+	for _, row := range rows {
+		if cost < 0 {
+			if c, err := strconv.ParseFloat(row.GetString(explainCostColumn), 64); err == nil {
+				cost = c
+			}
+		}
+		accessObject := row.GetString(explainAccessObjectColumn)
+		for _, idx := range indexes {
+			if strings.Contains(accessObject, "index:"+idx.hypoName()+"(") && !slices.Contains(used, idx) {
+				used = append(used, idx)
+			}
+		}
+	}
+	if cost < 0 {
+		return 0, nil, errors.Errorf("no estimated cost in the plan of %s", q.Text)
+	}
+	return cost, used, nil
+}
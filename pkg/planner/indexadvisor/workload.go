@@ -0,0 +1,76 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// maxNumWorkloadQueries is the maximum number of the queries loaded from the statement summary, the ones taking the
+// most time are loaded.
+const maxNumWorkloadQueries = 100
+
+// LoadWorkload loads the workload from the statement summary, they're the SELECT, UPDATE and DELETE statements
+// visible to the current user. The queries of the same digest are merged, and the frequency is the number of their
+// executions.
+func LoadWorkload(ctx context.Context, sctx sessionctx.Context) ([]*Query, error) {
+	exec := sctx.GetRestrictedSQLExecutor()
+	rows, _, err := exec.ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession},
+		`SELECT schema_name, ANY_VALUE(query_sample_text), CAST(SUM(exec_count) AS SIGNED)
+		FROM information_schema.statements_summary_history
+		WHERE stmt_type IN ('Select', 'Update', 'Delete') AND schema_name IS NOT NULL
+		GROUP BY schema_name, digest
+		ORDER BY SUM(sum_latency) DESC
+		LIMIT %?`, maxNumWorkloadQueries)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]*Query, 0, len(rows))
+	for _, row := range rows {
+		queries = append(queries, &Query{SchemaName: row.GetString(0), Text: row.GetString(1), Frequency: max(1, row.GetInt64(2))})
+	}
+	return queries, nil
+}
+
+// ParseWorkload splits the SQL text into the queries of the workload, every query is executed once in the current
+// database. Only the SELECT, UPDATE and DELETE statements are supported.
+func ParseWorkload(sctx sessionctx.Context, sql string) ([]*Query, error) {
+	vars := sctx.GetSessionVars()
+	p := parser.New()
+	p.SetSQLMode(vars.SQLMode)
+	stmts, _, err := p.ParseSQL(sql, vars.GetParseParams()...)
+	if err != nil {
+		return nil, util.SyntaxError(err)
+	}
+	queries := make([]*Query, 0, len(stmts))
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case *ast.SelectStmt, *ast.SetOprStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		default:
+			return nil, plannererrors.ErrNotSupportedYet.GenWithStackByArgs("RECOMMEND INDEX for " + ast.GetStmtLabel(stmt))
+		}
+		text := strings.TrimSuffix(strings.TrimSpace(stmt.Text()), ";")
+		queries = append(queries, &Query{SchemaName: vars.CurrentDB, Text: text, Frequency: 1})
+	}
+	return queries, nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/planner/cascades"
 	pctx "github.com/pingcap/tidb/pkg/planner/context"
 	"github.com/pingcap/tidb/pkg/planner/core"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/util/debugtrace"
 	"github.com/pingcap/tidb/pkg/privilege"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
@@ -128,6 +130,7 @@ func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Contex
 // Optimize does optimization and creates a Plan.
 func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plan base.Plan, slice types.NameSlice, retErr error) {
 	sessVars := sctx.GetSessionVars()
+	sessVars.StmtCtx.OptimizeCtx = ctx
 	pctx := sctx.GetPlanCtx()
 	if sessVars.StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(pctx)
@@ -387,6 +390,39 @@ func OptimizeForForeignKeyCascade(ctx context.Context, sctx pctx.PlanContext, no
 	return p, nil
 }
 
+// OptimizeForTrigger does optimization and creates a Plan for a statement in the trigger body.
+// Like OptimizeForForeignKeyCascade, it doesn't consider plan cache and plan binding. The body runs on
+// behalf of the trigger definer, so the privileges are checked against the definer rather than the current user.
+func OptimizeForTrigger(ctx context.Context, sctx pctx.PlanContext, node ast.StmtNode, is infoschema.InfoSchema,
+	definer *auth.UserIdentity) (base.Plan, error) {
+	hintProcessor := hint.NewQBHintHandler(sctx.GetSessionVars().StmtCtx)
+	node.Accept(hintProcessor)
+	builder := planBuilderPool.Get().(*core.PlanBuilder)
+	defer planBuilderPool.Put(builder.ResetForReuse())
+	builder.Init(sctx, is, hintProcessor)
+	p, err := builder.Build(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	// The trigger created by a session without a user, such as an internal session, isn't checked like the session.
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil && definer != nil && (definer.Username != "" || definer.Hostname != "") {
+		visitInfo := core.VisitInfo4PrivCheck(is, node, builder.GetVisitInfo())
+		if err := core.CheckPrivilegeWithUser(definer, pm, visitInfo); err != nil {
+			return nil, err
+		}
+	}
+	if err := core.CheckTableLock(sctx, is, builder.GetVisitInfo()); err != nil {
+		return nil, err
+	}
+	logic, isLogicalPlan := p.(base.LogicalPlan)
+	if !isLogicalPlan {
+		return p, nil
+	}
+	core.RecheckCTE(logic)
+	finalPlan, _, err := core.DoOptimize(ctx, sctx, builder.GetOptFlag(), logic)
+	return finalPlan, err
+}
+
 func allowInReadOnlyMode(sctx pctx.PlanContext, node ast.Node) (bool, error) {
 	pm := privilege.GetPrivilegeManager(sctx)
 	if pm == nil {
@@ -494,19 +530,95 @@ func optimize(ctx context.Context, sctx pctx.PlanContext, node ast.Node, is info
 	core.RecheckCTE(logic)
 
 	// Handle the logical plan statement, use cascades planner if enabled.
+	// Fall back to the default planner if some operators are not supported by the cascades planner yet.
 	if sessVars.GetEnableCascadesPlanner() {
-		finalPlan, cost, err := cascades.DefaultOptimizer.FindBestPlan(sctx, logic)
-		return finalPlan, names, cost, err
+		if cascades.DefaultOptimizer.IsSupported(logic) {
+			finalPlan, cost, err := cascades.DefaultOptimizer.FindBestPlan(sctx, logic)
+			return finalPlan, names, cost, err
+		}
+		sessVars.StmtCtx.AppendWarning(errors.NewNoStackError("the cascades planner doesn't support the query, fall back to the default planner"))
 	}
 
 	beginOpt := time.Now()
 	finalPlan, cost, err := core.DoOptimize(ctx, sctx, builder.GetOptFlag(), logic)
 	// TODO: capture plan replayer here if it matches sql and plan digest
+	if err == nil && sessVars.EnableOrExpansion {
+		finalPlan, cost = tryOrExpansion(ctx, sctx, node, is, finalPlan, cost)
+	}
 
 	sessVars.DurationOptimization = time.Since(beginOpt)
 	return finalPlan, names, cost, err
 }
 
+// tryOrExpansion optimizes the statement expanded into UNION ALL branches by its disjunctive WHERE clause, and
+// returns the expanded plan if it's cheaper than the original one, which may be an index merge plan.
+func tryOrExpansion(ctx context.Context, sctx pctx.PlanContext, node ast.Node, is infoschema.InfoSchema,
+	origin base.PhysicalPlan, originCost float64) (base.PhysicalPlan, float64) {
+	sessCtx, err := core.AsSctx(sctx)
+	if err != nil {
+		return origin, originCost
+	}
+	stmtCtx := sctx.GetSessionVars().StmtCtx
+	warns := stmtCtx.GetWarnings()
+	expandedNode, err := core.ExpandOrToUnionAll(ctx, sessCtx, node, is)
+	if err != nil || expandedNode == nil {
+		stmtCtx.SetWarnings(warns)
+		return origin, originCost
+	}
+	// Building the expanded statement resets the plan IDs and the scalar subqueries of the session, they're
+	// restored if the original plan is kept, otherwise they don't match the original plan.
+	state := savePlanBuildState(sctx.GetSessionVars())
+	p, _, _, err := optimize(ctx, sctx, expandedNode, is)
+	if err != nil {
+		state.restore(sctx.GetSessionVars())
+		stmtCtx.SetWarnings(warns)
+		return origin, originCost
+	}
+	expanded, ok := p.(base.PhysicalPlan)
+	if !ok {
+		state.restore(sctx.GetSessionVars())
+		stmtCtx.SetWarnings(warns)
+		return origin, originCost
+	}
+	cost, err := core.GetOrExpansionCost(expanded)
+	if err != nil || cost >= originCost {
+		state.restore(sctx.GetSessionVars())
+		stmtCtx.SetWarnings(warns)
+		return origin, originCost
+	}
+	return expanded, cost
+}
+
+// planBuildState is the state of the session reset by buildLogicalPlan.
+type planBuildState struct {
+	planID                           int32
+	planColumnID                     int64
+	mapScalarSubQ                    []any
+	mapHashCode2UniqueID4ExtendedCol map[string]int
+	rewritePhaseInfo                 variable.RewritePhaseInfo
+	tables                           []stmtctx.TableEntry
+}
+
+func savePlanBuildState(vars *variable.SessionVars) *planBuildState {
+	return &planBuildState{
+		planID:                           vars.PlanID.Load(),
+		planColumnID:                     vars.PlanColumnID.Load(),
+		mapScalarSubQ:                    vars.MapScalarSubQ,
+		mapHashCode2UniqueID4ExtendedCol: vars.MapHashCode2UniqueID4ExtendedCol,
+		rewritePhaseInfo:                 vars.RewritePhaseInfo,
+		tables:                           vars.StmtCtx.Tables,
+	}
+}
+
+func (s *planBuildState) restore(vars *variable.SessionVars) {
+	vars.PlanID.Store(s.planID)
+	vars.PlanColumnID.Store(s.planColumnID)
+	vars.MapScalarSubQ = s.mapScalarSubQ
+	vars.MapHashCode2UniqueID4ExtendedCol = s.mapHashCode2UniqueID4ExtendedCol
+	vars.RewritePhaseInfo = s.rewritePhaseInfo
+	vars.StmtCtx.Tables = s.tables
+}
+
 // OptimizeExecStmt to handle the "execute" statement
 func OptimizeExecStmt(ctx context.Context, sctx sessionctx.Context,
 	execAst *ast.ExecuteStmt, is infoschema.InfoSchema) (base.Plan, types.NameSlice, error) {
@@ -59,6 +59,10 @@ const (
 	OperandLimit
 	// OperandTiKVSingleGather is the operand for TiKVSingleGather.
 	OperandTiKVSingleGather
+	// OperandTiKVIndexMergeGather is the operand for TiKVIndexMergeGather.
+	OperandTiKVIndexMergeGather
+	// OperandMPPGather is the operand for MPPGather.
+	OperandMPPGather
 	// OperandMemTableScan is the operand for MemTableScan.
 	OperandMemTableScan
 	// OperandTableScan is the operand for TableScan.
@@ -69,6 +73,8 @@ const (
 	OperandShow
 	// OperandWindow is the operand for window function.
 	OperandWindow
+	// OperandCTE is the operand for LogicalCTE.
+	OperandCTE
 	// OperandUnsupported is the operand for unsupported operators.
 	OperandUnsupported
 )
@@ -106,6 +112,10 @@ func GetOperand(p base.LogicalPlan) Operand {
 		return OperandLimit
 	case *plannercore.TiKVSingleGather:
 		return OperandTiKVSingleGather
+	case *plannercore.TiKVIndexMergeGather:
+		return OperandTiKVIndexMergeGather
+	case *plannercore.MPPGather:
+		return OperandMPPGather
 	case *plannercore.LogicalTableScan:
 		return OperandTableScan
 	case *plannercore.LogicalMemTable:
@@ -116,6 +126,8 @@ func GetOperand(p base.LogicalPlan) Operand {
 		return OperandShow
 	case *plannercore.LogicalWindow:
 		return OperandWindow
+	case *plannercore.LogicalCTE:
+		return OperandCTE
 	default:
 		return OperandUnsupported
 	}
@@ -82,6 +82,13 @@ type AccessPath struct {
 	Forced           bool
 	ForceKeepOrder   bool
 	ForceNoKeepOrder bool
+	// ForceSkipScan and ForceNoSkipScan are set by the SKIP_SCAN and NO_SKIP_SCAN hints.
+	ForceSkipScan   bool
+	ForceNoSkipScan bool
+	// SkipScanRanges are the ranges on the index columns after the leading one, the index is read by skipping the
+	// distinct values of the leading column and seeking these ranges under each value. It's only set for the
+	// skip scan path, whose Ranges are the full range since the values of the leading column are unknown.
+	SkipScanRanges []*ranger.Range
 	// IsSingleScan indicates whether the path is a single index/table scan or table access after index scan.
 	IsSingleScan bool
 
@@ -117,6 +124,8 @@ func (path *AccessPath) Clone() *AccessPath {
 		Forced:                       path.Forced,
 		ForceKeepOrder:               path.ForceKeepOrder,
 		ForceNoKeepOrder:             path.ForceNoKeepOrder,
+		ForceSkipScan:                path.ForceSkipScan,
+		ForceNoSkipScan:              path.ForceNoSkipScan,
 		IsSingleScan:                 path.IsSingleScan,
 		IsUkShardIndexPath:           path.IsUkShardIndexPath,
 		KeepIndexMergeORSourceFilter: path.KeepIndexMergeORSourceFilter,
@@ -124,6 +133,9 @@ func (path *AccessPath) Clone() *AccessPath {
 	if path.IndexMergeORSourceFilter != nil {
 		ret.IndexMergeORSourceFilter = path.IndexMergeORSourceFilter.Clone()
 	}
+	if path.SkipScanRanges != nil {
+		ret.SkipScanRanges = CloneRanges(path.SkipScanRanges)
+	}
 	for _, partialPath := range path.PartialIndexPaths {
 		ret.PartialIndexPaths = append(ret.PartialIndexPaths, partialPath.Clone())
 	}
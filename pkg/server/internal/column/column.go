@@ -111,6 +111,9 @@ func dumpType(tp byte) byte {
 	switch tp {
 	case mysql.TypeSet, mysql.TypeEnum:
 		return mysql.TypeString
+	case mysql.TypeTiDBVectorFloat32:
+		// The vector isn't a MySQL type, it's sent in its text form.
+		return mysql.TypeVarString
 	default:
 		return tp
 	}
@@ -164,7 +167,7 @@ func DumpTextRow(buffer []byte, columns []*Info, row chunk.Row, d *ResultEncoder
 		case mysql.TypeNewDecimal:
 			buffer = dump.LengthEncodedString(buffer, hack.Slice(row.GetMyDecimal(i).String()))
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
-			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
 			d.UpdateDataEncoding(col.Charset)
 			buffer = dump.LengthEncodedString(buffer, d.EncodeData(row.GetBytes(i)))
 		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
@@ -183,6 +186,8 @@ func DumpTextRow(buffer []byte, columns []*Info, row chunk.Row, d *ResultEncoder
 			// To compatible with MySQL, here we treat it as utf-8.
 			d.UpdateDataEncoding(mysql.DefaultCollationID)
 			buffer = dump.LengthEncodedString(buffer, d.EncodeData(hack.Slice(row.GetJSON(i).String())))
+		case mysql.TypeTiDBVectorFloat32:
+			buffer = dump.LengthEncodedString(buffer, types.VectorTextFromBinary(row.GetBytes(i)))
 		default:
 			return nil, err.ErrInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
@@ -224,7 +229,7 @@ func DumpBinaryRow(buffer []byte, columns []*Info, row chunk.Row, d *ResultEncod
 		case mysql.TypeNewDecimal:
 			buffer = dump.LengthEncodedString(buffer, hack.Slice(row.GetMyDecimal(i).String()))
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
-			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
 			d.UpdateDataEncoding(columns[i].Charset)
 			buffer = dump.LengthEncodedString(buffer, d.EncodeData(row.GetBytes(i)))
 		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
@@ -242,6 +247,8 @@ func DumpBinaryRow(buffer []byte, columns []*Info, row chunk.Row, d *ResultEncod
 			// To compatible with MySQL, here we treat it as utf-8.
 			d.UpdateDataEncoding(mysql.DefaultCollationID)
 			buffer = dump.LengthEncodedString(buffer, d.EncodeData(hack.Slice(row.GetJSON(i).String())))
+		case mysql.TypeTiDBVectorFloat32:
+			buffer = dump.LengthEncodedString(buffer, types.VectorTextFromBinary(row.GetBytes(i)))
 		default:
 			return nil, err.ErrInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
@@ -690,6 +690,71 @@ const (
 		KEY (created_by),
 		KEY (status));`
 
+	// CreateRoutinesTable stores the definitions of the stored routines.
+	CreateRoutinesTable = `CREATE TABLE IF NOT EXISTS mysql.routines (
+		routine_schema VARCHAR(64) NOT NULL,
+		name VARCHAR(64) COLLATE utf8mb4_general_ci NOT NULL,
+		type ENUM('FUNCTION','PROCEDURE') NOT NULL,
+		param_list BLOB NOT NULL,
+		body LONGBLOB NOT NULL,
+		definer VARCHAR(288) NOT NULL,
+		sql_mode VARCHAR(1024) NOT NULL,
+		character_set_client VARCHAR(32) NOT NULL,
+		collation_connection VARCHAR(32) NOT NULL,
+		db_collation VARCHAR(32) NOT NULL,
+		created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_altered TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		comment TEXT NOT NULL,
+		PRIMARY KEY (routine_schema, name, type)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
+	// CreateMViewRefreshTable stores the last refresh of the materialized views.
+	CreateMViewRefreshTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_mview_refresh (
+		table_id BIGINT(64) NOT NULL,
+		base_table_id BIGINT(64) DEFAULT NULL,
+		last_refresh_tso BIGINT(64) UNSIGNED DEFAULT NULL,
+		last_refresh_time TIMESTAMP NULL DEFAULT NULL,
+		last_refresh_method VARCHAR(16) DEFAULT NULL,
+		last_error TEXT DEFAULT NULL,
+		PRIMARY KEY (table_id)
+	);`
+
+	// CreatePlanEvolutionHistoryTable stores the plan regressions detected by the automatic plan evolution and
+	// what was done for them.
+	CreatePlanEvolutionHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.plan_evolution_history (
+		sql_digest VARCHAR(64) NOT NULL,
+		db VARCHAR(64) NOT NULL,
+		original_sql LONGTEXT NOT NULL,
+		regressed_plan_digest VARCHAR(64) NOT NULL,
+		regressed_avg_latency BIGINT UNSIGNED NOT NULL,
+		better_plan_digest VARCHAR(64) NOT NULL,
+		better_avg_latency BIGINT UNSIGNED NOT NULL,
+		bind_sql LONGTEXT DEFAULT NULL,
+		action VARCHAR(16) NOT NULL,
+		reason TEXT DEFAULT NULL,
+		create_time TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		INDEX sql_digest_index (sql_digest),
+		INDEX create_time_index (create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
+	// CreateStatsCardinalityFeedbackTable stores the feedback of the estimations on the filters and joins collected
+	// from the executed queries, which is used to correct the estimations found to be systematically off.
+	CreateStatsCardinalityFeedbackTable = `CREATE TABLE IF NOT EXISTS mysql.stats_cardinality_feedback (
+		table_id BIGINT(64) NOT NULL,
+		kind TINYINT NOT NULL,
+		expr_digest VARCHAR(64) NOT NULL,
+		exprs LONGTEXT NOT NULL,
+		sql_digest VARCHAR(64) NOT NULL DEFAULT '',
+		exec_count BIGINT(64) NOT NULL DEFAULT 0,
+		misestimate_count BIGINT(64) NOT NULL DEFAULT 0,
+		est_rows DOUBLE NOT NULL DEFAULT 0,
+		act_rows DOUBLE NOT NULL DEFAULT 0,
+		sum_log_ratio DOUBLE NOT NULL DEFAULT 0,
+		window_start TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		update_time TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id, kind, expr_digest)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
 	// DropMySQLIndexUsageTable removes the table `mysql.schema_index_usage`
 	DropMySQLIndexUsageTable = "DROP TABLE IF EXISTS mysql.schema_index_usage"
 
@@ -1093,11 +1158,27 @@ const (
 	// version 197
 	//   replace `mysql.tidb_mdl_view` table
 	version197 = 197
+
+	// version 198
+	//   create `mysql.routines` table
+	version198 = 198
+
+	// version 199
+	//   create `mysql.tidb_mview_refresh` table
+	version199 = 199
+
+	// version 200
+	//   create `mysql.plan_evolution_history` table
+	version200 = 200
+
+	// version 201
+	//   create `mysql.stats_cardinality_feedback` table
+	version201 = 201
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version197
+var currentBootstrapVersion int64 = version201
 
 // DDL owner key's expired time is ManagerSessionTTL seconds, we should wait the time and give more time to have a chance to finish it.
 var internalSQLTimeout = owner.ManagerSessionTTL + 15
@@ -1260,6 +1341,10 @@ var (
 		upgradeToVer195,
 		upgradeToVer196,
 		upgradeToVer197,
+		upgradeToVer198,
+		upgradeToVer199,
+		upgradeToVer200,
+		upgradeToVer201,
 	}
 )
 
@@ -3103,6 +3188,38 @@ func upgradeToVer197(s sessiontypes.Session, ver int64) {
 	doReentrantDDL(s, CreateMDLView)
 }
 
+func upgradeToVer198(s sessiontypes.Session, ver int64) {
+	if ver >= version198 {
+		return
+	}
+
+	doReentrantDDL(s, CreateRoutinesTable)
+}
+
+func upgradeToVer199(s sessiontypes.Session, ver int64) {
+	if ver >= version199 {
+		return
+	}
+
+	doReentrantDDL(s, CreateMViewRefreshTable)
+}
+
+func upgradeToVer200(s sessiontypes.Session, ver int64) {
+	if ver >= version200 {
+		return
+	}
+
+	doReentrantDDL(s, CreatePlanEvolutionHistoryTable)
+}
+
+func upgradeToVer201(s sessiontypes.Session, ver int64) {
+	if ver >= version201 {
+		return
+	}
+
+	doReentrantDDL(s, CreateStatsCardinalityFeedbackTable)
+}
+
 func writeOOMAction(s sessiontypes.Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -3234,6 +3351,14 @@ func doDDLWorks(s sessiontypes.Session) {
 	mustExecute(s, CreateSysSchema)
 	// create `sys.schema_unused_indexes` view
 	mustExecute(s, CreateSchemaUnusedIndexesView)
+	// create routines
+	mustExecute(s, CreateRoutinesTable)
+	// create tidb_mview_refresh
+	mustExecute(s, CreateMViewRefreshTable)
+	// create plan_evolution_history
+	mustExecute(s, CreatePlanEvolutionHistoryTable)
+	// create stats_cardinality_feedback
+	mustExecute(s, CreateStatsCardinalityFeedbackTable)
 }
 
 // doBootstrapSQLFile executes SQL commands in a file as the last stage of bootstrap.
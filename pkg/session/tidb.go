@@ -34,11 +34,13 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	session_metrics "github.com/pingcap/tidb/pkg/session/metrics"
-	"github.com/pingcap/tidb/pkg/session/types"
+	sessiontypes "github.com/pingcap/tidb/pkg/session/types"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/dbterror"
@@ -360,7 +362,7 @@ func GetRows4Test(ctx context.Context, _ sessionctx.Context, rs sqlexec.RecordSe
 }
 
 // ResultSetToStringSlice changes the RecordSet to [][]string.
-func ResultSetToStringSlice(ctx context.Context, s types.Session, rs sqlexec.RecordSet) ([][]string, error) {
+func ResultSetToStringSlice(ctx context.Context, s sessiontypes.Session, rs sqlexec.RecordSet) ([][]string, error) {
 	rows, err := GetRows4Test(ctx, s, rs)
 	if err != nil {
 		return nil, err
@@ -376,6 +378,9 @@ func ResultSetToStringSlice(ctx context.Context, s types.Session, rs sqlexec.Rec
 		for j := 0; j < row.Len(); j++ {
 			if row.IsNull(j) {
 				iRow[j] = "<nil>"
+			} else if rs.Fields()[j].Column.GetType() == mysql.TypeTiDBVectorFloat32 {
+				// Show the vector in its text form as the clients receive it.
+				iRow[j] = string(types.VectorTextFromBinary(row.GetBytes(j)))
 			} else {
 				d := row.GetDatum(j, &rs.Fields()[j].Column.FieldType)
 				iRow[j], err = d.ToString()
@@ -64,6 +64,22 @@ type PlanCache interface {
 	Close()
 }
 
+// InstancePlanCache is an interface for the plan cache shared by all the sessions of the instance.
+// The values are always *PlanCacheValue of the planner, they're typed as any to avoid the import cycle.
+type InstancePlanCache interface {
+	// Get returns a value of the key which the match function accepts.
+	Get(key string, match func(value any) bool) (value any, ok bool)
+	// Put puts the value of the key into the cache, it replaces the value of the key which the match function accepts
+	// if there is one. It returns false if the value is too large to be cached.
+	Put(key string, value any, match func(value any) bool) bool
+	// DeleteAll deletes all the values from the cache.
+	DeleteAll()
+	// Entries returns the information of the cached plans.
+	Entries() []utilpc.InstancePlanCacheEntry
+	// Stats returns the statistics of the cache.
+	Stats() utilpc.InstancePlanCacheStats
+}
+
 // Context is an interface for transaction and executive args environment.
 type Context interface {
 	SessionStatesHandler
@@ -16,6 +16,7 @@ package stmtctx
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -319,6 +320,12 @@ type StatementContext struct {
 	EnableOptimizerDebugTrace bool
 	OptimizerDebugTrace       any
 
+	// DynamicSamplingElapsed is the time spent by dynamic sampling in optimizing this statement.
+	DynamicSamplingElapsed time.Duration
+	// OptimizeCtx is the context of optimizing this statement. The internal SQLs run by the optimizer, e.g. the
+	// dynamic sampling, are derived from it since the context isn't passed through the statistics derivation.
+	OptimizeCtx context.Context
+
 	// WaitLockLeaseTime is the duration of cached table read lease expiration time.
 	WaitLockLeaseTime time.Duration
 
@@ -378,6 +385,12 @@ type StatementContext struct {
 		HasFKCascades bool
 	}
 
+	// InHandleTrigger indicates currently are executing the statements in a trigger body.
+	InHandleTrigger bool
+	// HasTriggers indicates the statement fires triggers, the statements in the trigger bodies read the
+	// changes of the statement from the txn mem-buffer.
+	HasTriggers bool
+
 	// MPPQueryInfo stores some id and timestamp of current MPP query statement.
 	MPPQueryInfo struct {
 		QueryID              atomic2.Uint64
@@ -734,8 +747,8 @@ type TableEntry struct {
 
 // AddAffectedRows adds affected rows.
 func (sc *StatementContext) AddAffectedRows(rows uint64) {
-	if sc.InHandleForeignKeyTrigger {
-		// For compatibility with MySQL, not add the affected row cause by the foreign key trigger.
+	if sc.InHandleForeignKeyTrigger || sc.InHandleTrigger {
+		// For compatibility with MySQL, not add the affected row cause by the foreign key trigger or the trigger.
 		return
 	}
 	sc.mu.Lock()
@@ -471,7 +471,6 @@ var noopSysVars = []*SysVar{
 		IsHintUpdatableVerified: true,
 	},
 	{Scope: ScopeNone, Name: "innodb_read_io_threads", Value: "4"},
-	{Scope: ScopeGlobal | ScopeSession, Name: MaxSpRecursionDepth, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: 255},
 	{Scope: ScopeNone, Name: "ignore_builtin_innodb", Value: "0"},
 	{Scope: ScopeGlobal, Name: "slow_query_log_file", Value: "/usr/local/mysql/data/localhost-slow.log"},
 	{Scope: ScopeGlobal, Name: "innodb_thread_sleep_delay", Value: "10000"},
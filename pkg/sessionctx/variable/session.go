@@ -981,6 +981,9 @@ type SessionVars struct {
 	// BatchCommit indicates if we should split the transaction into multiple batches.
 	BatchCommit bool
 
+	// CTASImportInto indicates whether `CREATE TABLE ... SELECT` writes the selected rows through IMPORT INTO.
+	CTASImportInto bool
+
 	// OptimizerSelectivityLevel defines the level of the selectivity estimation in plan.
 	OptimizerSelectivityLevel int
 
@@ -1246,6 +1249,14 @@ type SessionVars struct {
 	// DisableHashJoin indicates whether to disable hash join.
 	DisableHashJoin bool
 
+	// EnableAdaptiveJoin indicates whether the hash join can swap its build side and probe side, and the index join
+	// can switch to the hash join at runtime.
+	EnableAdaptiveJoin bool
+
+	// AdaptiveJoinThreshold is the ratio of the actual row count to the estimated row count of the build side of
+	// the hash join or the outer side of the index join from which the join changes its strategy.
+	AdaptiveJoinThreshold float64
+
 	// EnableHistoricalStats indicates whether to enable historical statistics.
 	EnableHistoricalStats bool
 
@@ -1313,6 +1324,12 @@ type SessionVars struct {
 	// EnableParallelHashaggSpill indicates if parallel hash agg could spill.
 	EnableParallelHashaggSpill bool
 
+	// EnableHashJoinSpill indicates if hash join could spill the rows of both sides into partitions on disk.
+	EnableHashJoinSpill bool
+
+	// EnableWindowSpill indicates if window functions could spill the rows of partitions to disk.
+	EnableWindowSpill bool
+
 	// SysdateIsNow indicates whether Sysdate is an alias of Now function
 	SysdateIsNow bool
 	// EnableMutationChecker indicates whether to check data consistency for mutations
@@ -1457,6 +1474,24 @@ type SessionVars struct {
 	// When set to true, `col is (not) null`(`col` is index prefix column) is regarded as index filter rather than table filter.
 	OptPrefixIndexSingleScan bool
 
+	// EnableOrExpansion indicates whether to try rewriting the disjunctive WHERE clause into UNION ALL branches.
+	EnableOrExpansion bool
+
+	// EnableDynamicSampling indicates whether to estimate the selectivity of the filters which can't be estimated
+	// by the statistics by evaluating them on the rows sampled from the table.
+	EnableDynamicSampling bool
+
+	// DynamicSamplingTimeBudget is the max time spent by dynamic sampling in optimizing a query.
+	DynamicSamplingTimeBudget time.Duration
+
+	// EnableCardinalityFeedback indicates whether to record the actual row counts of the filters and joins of the
+	// executed queries, and to correct the estimations which are found to be systematically off by them.
+	EnableCardinalityFeedback bool
+
+	// EnablePartitionWiseJoin indicates whether to split the join of two tables partitioned identically on the join
+	// key into the joins of the matching partitions.
+	EnablePartitionWiseJoin bool
+
 	// chunkPool Several chunks and columns are cached
 	chunkPool chunk.Allocator
 	// EnableReuseChunk indicates  request chunk whether use chunk alloc
@@ -1500,6 +1535,14 @@ type SessionVars struct {
 	// Enable late materialization: push down some selection condition to tablescan.
 	EnableLateMaterialization bool
 
+	// EnableMaterializedViewRewrite indicates whether the queries can be rewritten to read the materialized views.
+	// The materialized views may be stale, so the rewritten queries may return the results of the last refresh.
+	EnableMaterializedViewRewrite bool
+
+	// VectorSearchNProbe is the min number of partitions of the VECTOR index to read for `ORDER BY distance LIMIT k`.
+	// Reading more partitions makes the results closer to the exact nearest neighbors.
+	VectorSearchNProbe int
+
 	// EnableRowLevelChecksum indicates whether row level checksum is enabled.
 	EnableRowLevelChecksum bool
 
@@ -1974,6 +2017,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		EnableCorrelationAdjustment:   DefOptEnableCorrelationAdjustment,
 		LimitPushDownThreshold:        DefOptLimitPushDownThreshold,
 		CorrelationThreshold:          DefOptCorrelationThreshold,
+		AdaptiveJoinThreshold:         DefTiDBAdaptiveJoinThreshold,
 		CorrelationExpFactor:          DefOptCorrelationExpFactor,
 		cpuFactor:                     DefOptCPUFactor,
 		copCPUFactor:                  DefOptCopCPUFactor,
@@ -2042,6 +2086,9 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		mppExchangeCompressionMode:    DefaultExchangeCompressionMode,
 		mppVersion:                    kv.MppVersionUnspecified,
 		EnableLateMaterialization:     DefTiDBOptEnableLateMaterialization,
+		EnableMaterializedViewRewrite: DefTiDBOptEnableMaterializedViewRewrite,
+		DynamicSamplingTimeBudget:     DefTiDBOptDynamicSamplingTimeBudget * time.Millisecond,
+		VectorSearchNProbe:            DefTiDBVectorSearchNProbe,
 		TiFlashComputeDispatchPolicy:  tiflashcompute.DispatchPolicyConsistentHash,
 		ResourceGroupName:             resourcegroup.DefaultResourceGroupName,
 		DefaultCollationForUTF8MB4:    mysql.DefaultCollationName,
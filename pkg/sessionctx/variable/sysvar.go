@@ -800,6 +800,7 @@ var defaultSysVars = []*SysVar{
 			return stmtsummaryv2.SetMaxSQLLength(TidbOptInt(val, DefTiDBStmtSummaryMaxSQLLength))
 		}},
 	{Scope: ScopeGlobal, Name: TiDBCapturePlanBaseline, Value: DefTiDBCapturePlanBaseline, Type: TypeBool, AllowEmptyAll: true},
+	{Scope: ScopeGlobal, Name: TiDBAutoPlanEvolution, Value: DefTiDBAutoPlanEvolution, Type: TypeEnum, PossibleValues: []string{Off, AutoPlanEvolutionRecommend, On}},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskMaxTime, Value: strconv.Itoa(DefTiDBEvolvePlanTaskMaxTime), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskStartTime, Value: DefTiDBEvolvePlanTaskStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskEndTime, Value: DefTiDBEvolvePlanTaskEndTime, Type: TypeTime},
@@ -1297,6 +1298,18 @@ var defaultSysVars = []*SysVar{
 		}
 		return err
 	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableInstancePlanCache, Value: BoolToOnOff(DefTiDBEnableInstancePlanCache), Type: TypeBool, GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+		return BoolToOnOff(EnableInstancePlanCache.Load()), nil
+	}, SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+		EnableInstancePlanCache.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBInstancePlanCacheMaxMemSize, Value: strconv.FormatInt(DefTiDBInstancePlanCacheMaxMemSize, 10), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+		return strconv.FormatInt(InstancePlanCacheMaxMemSize.Load(), 10), nil
+	}, SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+		InstancePlanCacheMaxMemSize.Store(TidbOptInt64(val, DefTiDBInstancePlanCacheMaxMemSize))
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBMemOOMAction, Value: DefTiDBMemOOMAction, PossibleValues: []string{"CANCEL", "LOG"}, Type: TypeEnum,
 		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
 			return OOMAction.Load(), nil
@@ -2210,6 +2223,7 @@ var defaultSysVars = []*SysVar{
 		s.EnableExtendedStats = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: MaxSpRecursionDepth, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: 255},
 	{Scope: ScopeGlobal | ScopeSession, Name: CTEMaxRecursionDepth, Value: strconv.Itoa(DefCTEMaxRecursionDepth), Type: TypeInt, MinValue: 0, MaxValue: 4294967295, SetSession: func(s *SessionVars, val string) error {
 		s.CTEMaxRecursionDepth = TidbOptInt(val, DefCTEMaxRecursionDepth)
 		return nil
@@ -2283,6 +2297,14 @@ var defaultSysVars = []*SysVar{
 		s.DisableHashJoin = !TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableAdaptiveJoin, Value: BoolToOnOff(DefTiDBEnableAdaptiveJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableAdaptiveJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAdaptiveJoinThreshold, Value: strconv.FormatFloat(DefTiDBAdaptiveJoinThreshold, 'f', -1, 64), Type: TypeFloat, MinValue: 1, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+		s.AdaptiveJoinThreshold = tidbOptFloat64(val, DefTiDBAdaptiveJoinThreshold)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMergeJoin, Value: BoolToOnOff(DefTiDBEnableIndexMergeJoin), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableIndexMergeJoin = TiDBOptOn(val)
 		return nil
@@ -2359,6 +2381,18 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableHashJoinSpill, Value: BoolToOnOff(DefTiDBEnableHashJoinSpill), Type: TypeBool,
+		SetSession: func(vars *SessionVars, s string) error {
+			vars.EnableHashJoinSpill = TiDBOptOn(s)
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableWindowSpill, Value: BoolToOnOff(DefTiDBEnableWindowSpill), Type: TypeBool,
+		SetSession: func(vars *SessionVars, s string) error {
+			vars.EnableWindowSpill = TiDBOptOn(s)
+			return nil
+		},
+	},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableMutationChecker, Hidden: true,
 		Value: BoolToOnOff(DefTiDBEnableMutationChecker), Type: TypeBool,
 		SetSession: func(s *SessionVars, val string) error {
@@ -2569,6 +2603,26 @@ var defaultSysVars = []*SysVar{
 		s.OptPrefixIndexSingleScan = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableOrExpansion, Value: BoolToOnOff(DefTiDBOptEnableOrExpansion), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableOrExpansion = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableDynamicSampling, Value: BoolToOnOff(DefTiDBOptEnableDynamicSampling), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableDynamicSampling = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDynamicSamplingTimeBudget, Value: strconv.Itoa(DefTiDBOptDynamicSamplingTimeBudget), Type: TypeUnsigned, MinValue: 1, MaxValue: 60000, SetSession: func(s *SessionVars, val string) error {
+		s.DynamicSamplingTimeBudget = time.Duration(TidbOptInt(val, DefTiDBOptDynamicSamplingTimeBudget)) * time.Millisecond
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableCardinalityFeedback, Value: BoolToOnOff(DefTiDBOptEnableCardinalityFeedback), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableCardinalityFeedback = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnablePartitionWiseJoin, Value: BoolToOnOff(DefTiDBOptEnablePartitionWiseJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnablePartitionWiseJoin = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBExternalTS, Value: strconv.FormatInt(DefTiDBExternalTS, 10), SetGlobal: func(ctx context.Context, s *SessionVars, val string) error {
 		ts, err := parseTSFromNumberOrTime(s, val)
 		if err != nil {
@@ -2781,6 +2835,14 @@ var defaultSysVars = []*SysVar{
 		s.EnableLateMaterialization = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableMaterializedViewRewrite, Value: BoolToOnOff(DefTiDBOptEnableMaterializedViewRewrite), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableMaterializedViewRewrite = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBVectorSearchNProbe, Value: strconv.Itoa(DefTiDBVectorSearchNProbe), Type: TypeUnsigned, MinValue: 1, MaxValue: 1 << 16, SetSession: func(s *SessionVars, val string) error {
+		s.VectorSearchNProbe = tidbOptPositiveInt32(val, DefTiDBVectorSearchNProbe)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBLoadBasedReplicaReadThreshold, Value: DefTiDBLoadBasedReplicaReadThreshold.String(), Type: TypeDuration, MaxValue: uint64(time.Hour), SetSession: func(s *SessionVars, val string) error {
 		d, err := time.ParseDuration(val)
 		if err != nil {
@@ -3181,6 +3243,12 @@ var defaultSysVars = []*SysVar{
 		},
 		IsHintUpdatableVerified: true,
 	},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCTASImportInto, Value: BoolToOnOff(DefTiDBCTASImportInto), Type: TypeBool,
+		SetSession: func(s *SessionVars, val string) error {
+			s.CTASImportInto = TiDBOptOn(val)
+			return nil
+		},
+	},
 }
 
 // GlobalSystemVariableInitialValue gets the default value for a system variable including ones that are dynamically set (e.g. based on the store)
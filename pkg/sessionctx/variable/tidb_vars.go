@@ -599,6 +599,10 @@ const (
 	// TiDBCapturePlanBaseline indicates whether the capture of plan baselines is enabled.
 	TiDBCapturePlanBaseline = "tidb_capture_plan_baselines"
 
+	// TiDBAutoPlanEvolution controls what to do when a statement's plan regresses: OFF does nothing, RECOMMEND records
+	// the better plan in mysql.plan_evolution_history, and ON also creates a global binding for the better plan.
+	TiDBAutoPlanEvolution = "tidb_auto_plan_evolution"
+
 	// TiDBUsePlanBaselines indicates whether the use of plan baselines is enabled.
 	TiDBUsePlanBaselines = "tidb_use_plan_baselines"
 
@@ -836,6 +840,10 @@ const (
 	TiDBPlanCacheInvalidationOnFreshStats = "tidb_plan_cache_invalidation_on_fresh_stats"
 	// TiDBSessionPlanCacheSize controls the size of session plan cache.
 	TiDBSessionPlanCacheSize = "tidb_session_plan_cache_size"
+	// TiDBEnableInstancePlanCache indicates whether to share the cached plans among the sessions of the instance.
+	TiDBEnableInstancePlanCache = "tidb_enable_instance_plan_cache"
+	// TiDBInstancePlanCacheMaxMemSize controls the memory quota of the plan cache shared by the sessions of the instance.
+	TiDBInstancePlanCacheMaxMemSize = "tidb_instance_plan_cache_max_size"
 
 	// TiDBConstraintCheckInPlacePessimistic controls whether to skip certain kinds of pessimistic locks.
 	TiDBConstraintCheckInPlacePessimistic = "tidb_constraint_check_in_place_pessimistic"
@@ -862,6 +870,21 @@ const (
 	// TiDBOptPrefixIndexSingleScan indicates whether to do some optimizations to avoid double scan for prefix index.
 	// When set to true, `col is (not) null`(`col` is index prefix column) is regarded as index filter rather than table filter.
 	TiDBOptPrefixIndexSingleScan = "tidb_opt_prefix_index_single_scan"
+	// TiDBOptEnableOrExpansion indicates whether to try rewriting the disjunctive WHERE clause of a query into
+	// UNION ALL branches, the rewritten plan is used only if it's cheaper than the original one.
+	TiDBOptEnableOrExpansion = "tidb_opt_enable_or_expansion"
+	// TiDBOptEnableDynamicSampling indicates whether to estimate the selectivity of the filters which can't be
+	// estimated by the statistics by evaluating them on the rows sampled from the table during optimization.
+	TiDBOptEnableDynamicSampling = "tidb_opt_enable_dynamic_sampling"
+	// TiDBOptDynamicSamplingTimeBudget is the max time in milliseconds spent by dynamic sampling in optimizing a query.
+	TiDBOptDynamicSamplingTimeBudget = "tidb_opt_dynamic_sampling_time_budget"
+	// TiDBOptEnableCardinalityFeedback indicates whether to record the actual row counts of the filters and joins of
+	// the executed queries, and to correct the estimations which are found to be systematically off by them.
+	TiDBOptEnableCardinalityFeedback = "tidb_opt_enable_cardinality_feedback"
+	// TiDBOptEnablePartitionWiseJoin indicates whether to split the join of two tables partitioned identically on
+	// the join key into the joins of the matching partitions. Under dynamic partition prune mode, each partition of
+	// the joins is read by a reader of the partitioned table which only reads that partition.
+	TiDBOptEnablePartitionWiseJoin = "tidb_opt_enable_partition_wise_join"
 
 	// TiDBEnableExternalTSRead indicates whether to enable read through an external ts
 	TiDBEnableExternalTSRead = "tidb_enable_external_ts_read"
@@ -901,6 +924,10 @@ const (
 
 	// TiDBOptEnableLateMaterialization indicates whether to enable late materialization
 	TiDBOptEnableLateMaterialization = "tidb_opt_enable_late_materialization"
+	// TiDBOptEnableMaterializedViewRewrite indicates whether the queries can be rewritten to read the materialized views.
+	TiDBOptEnableMaterializedViewRewrite = "tidb_opt_enable_materialized_view_rewrite"
+	// TiDBVectorSearchNProbe is the min number of partitions of the VECTOR index to read for the nearest neighbors.
+	TiDBVectorSearchNProbe = "tidb_vector_search_nprobe"
 	// TiDBLoadBasedReplicaReadThreshold is the wait duration threshold to enable replica read automatically.
 	TiDBLoadBasedReplicaReadThreshold = "tidb_load_based_replica_read_threshold"
 
@@ -935,6 +962,15 @@ const (
 	// TiDBOptEnableHashJoin indicates whether to enable hash join.
 	TiDBOptEnableHashJoin = "tidb_opt_enable_hash_join"
 
+	// TiDBEnableAdaptiveJoin indicates whether the joins can change their strategy at runtime when the actual row
+	// count is much larger than estimated: the hash join swaps its build side and probe side, and the index join
+	// switches to the hash join.
+	TiDBEnableAdaptiveJoin = "tidb_enable_adaptive_join"
+
+	// TiDBAdaptiveJoinThreshold is the ratio of the actual row count to the estimated row count of the build side of
+	// the hash join or the outer side of the index join from which the join changes its strategy.
+	TiDBAdaptiveJoinThreshold = "tidb_adaptive_join_threshold"
+
 	// TiDBOptObjective indicates whether the optimizer should be more stable, predictable or more aggressive.
 	// Please see comments of SessionVars.OptObjective for details.
 	TiDBOptObjective = "tidb_opt_objective"
@@ -942,6 +978,12 @@ const (
 	// TiDBEnableParallelHashaggSpill is the name of the `tidb_enable_parallel_hashagg_spill` system variable
 	TiDBEnableParallelHashaggSpill = "tidb_enable_parallel_hashagg_spill"
 
+	// TiDBEnableHashJoinSpill is the name of the `tidb_enable_hash_join_spill` system variable
+	TiDBEnableHashJoinSpill = "tidb_enable_hash_join_spill"
+
+	// TiDBEnableWindowSpill is the name of the `tidb_enable_window_spill` system variable
+	TiDBEnableWindowSpill = "tidb_enable_window_spill"
+
 	// TiDBTxnEntrySizeLimit indicates the max size of a entry in membuf.
 	TiDBTxnEntrySizeLimit = "tidb_txn_entry_size_limit"
 
@@ -1159,6 +1201,10 @@ const (
 	// The value can be STANDARD, BULK.
 	// Currently, the BULK mode only affects auto-committed DML.
 	TiDBDMLType = "tidb_dml_type"
+	// TiDBCTASImportInto indicates whether `CREATE TABLE ... SELECT` writes the selected rows through
+	// IMPORT INTO instead of an INSERT ... SELECT in a single transaction.
+	// It only takes effect when the statement has no IGNORE or REPLACE clause and the table is not temporary.
+	TiDBCTASImportInto = "tidb_ctas_import_into"
 )
 
 // TiDB intentional limits
@@ -1334,6 +1380,7 @@ const (
 	DefTiDBStmtSummaryMaxStmtCount                 = 3000
 	DefTiDBStmtSummaryMaxSQLLength                 = 4096
 	DefTiDBCapturePlanBaseline                     = Off
+	DefTiDBAutoPlanEvolution                       = Off
 	DefTiDBIgnoreInlistPlanDigest                  = false
 	DefTiDBEnableIndexMerge                        = true
 	DefEnableLegacyInstanceScope                   = true
@@ -1344,6 +1391,8 @@ const (
 	DefTiDBStatsLoadPseudoTimeout                  = true
 	DefSysdateIsNow                                = false
 	DefTiDBEnableParallelHashaggSpill              = true
+	DefTiDBEnableHashJoinSpill                     = true
+	DefTiDBEnableWindowSpill                       = true
 	DefTiDBEnableMutationChecker                   = false
 	DefTiDBTxnAssertionLevel                       = AssertionOffStr
 	DefTiDBIgnorePreparedCacheCloseStmt            = false
@@ -1375,6 +1424,8 @@ const (
 	DefTiDBSessionPlanCacheSize                    = 100
 	DefTiDBEnablePrepPlanCacheMemoryMonitor        = true
 	DefTiDBPrepPlanCacheMemoryGuardRatio           = 0.1
+	DefTiDBEnableInstancePlanCache                 = false
+	DefTiDBInstancePlanCacheMaxMemSize             = 100 << 20 // 100MB.
 	DefTiDBEnableDistTask                          = true
 	DefTiDBEnableFastCreateTable                   = false
 	DefTiDBSimplifiedMetrics                       = false
@@ -1421,6 +1472,11 @@ const (
 	DefTiDBGOGCMaxValue                               = 500
 	DefTiDBGOGCMinValue                               = 100
 	DefTiDBOptPrefixIndexSingleScan                   = true
+	DefTiDBOptEnableOrExpansion                       = false
+	DefTiDBOptEnableDynamicSampling                   = false
+	DefTiDBOptDynamicSamplingTimeBudget               = 100
+	DefTiDBOptEnableCardinalityFeedback               = false
+	DefTiDBOptEnablePartitionWiseJoin                 = false
 	DefTiDBEnableAsyncMergeGlobalStats                = true
 	DefTiDBExternalTS                                 = 0
 	DefTiDBEnableExternalTSRead                       = false
@@ -1454,6 +1510,8 @@ const (
 	DefTiDBEnablePlanCacheForSubquery                 = true
 	DefTiDBLoadBasedReplicaReadThreshold              = time.Second
 	DefTiDBOptEnableLateMaterialization               = true
+	DefTiDBOptEnableMaterializedViewRewrite           = false
+	DefTiDBVectorSearchNProbe                         = 16
 	DefTiDBOptOrderingIdxSelThresh                    = 0.0
 	DefTiDBOptOrderingIdxSelRatio                     = -1
 	DefTiDBOptEnableMPPSharedCTEExecution             = false
@@ -1479,6 +1537,8 @@ const (
 	DefTiDBEnableCheckConstraint                      = false
 	DefTiDBSkipMissingPartitionStats                  = true
 	DefTiDBOptEnableHashJoin                          = true
+	DefTiDBEnableAdaptiveJoin                         = false
+	DefTiDBAdaptiveJoinThreshold                      = 10.0
 	DefTiDBOptObjective                               = OptObjectiveModerate
 	DefTiDBSchemaVersionCacheLimit                    = 16
 	DefTiDBIdleTransactionTimeout                     = 0
@@ -1487,6 +1547,7 @@ const (
 	DefTiDBLowResolutionTSOUpdateInterval             = 2000
 	DefDivPrecisionIncrement                          = 4
 	DefTiDBDMLType                                    = "STANDARD"
+	DefTiDBCTASImportInto                             = false
 	DefGroupConcatMaxLen                              = uint64(1024)
 	DefDefaultWeekFormat                              = "0"
 )
@@ -1533,6 +1594,8 @@ var (
 	MaxAutoAnalyzeTime                   = atomic.NewInt64(DefTiDBMaxAutoAnalyzeTime)
 	// variables for plan cache
 	PreparedPlanCacheMemoryGuardRatio = atomic.NewFloat64(DefTiDBPrepPlanCacheMemoryGuardRatio)
+	EnableInstancePlanCache           = atomic.NewBool(DefTiDBEnableInstancePlanCache)
+	InstancePlanCacheMaxMemSize       = atomic.NewInt64(DefTiDBInstancePlanCacheMaxMemSize)
 	EnableDistTask                    = atomic.NewBool(DefTiDBEnableDistTask)
 	EnableFastCreateTable             = atomic.NewBool(DefTiDBEnableFastCreateTable)
 	DDLForce2Queue                    = atomic.NewBool(false)
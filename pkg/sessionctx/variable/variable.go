@@ -83,6 +83,9 @@ const (
 	OOMActionCancel = "CANCEL"
 	// OOMActionLog constants represents the valid action configurations for OOMAction "LOG".
 	OOMActionLog = "LOG"
+	// AutoPlanEvolutionRecommend is a choice of variable TiDBAutoPlanEvolution that means the better plans of the
+	// regressed statements are only recommended instead of being bound.
+	AutoPlanEvolutionRecommend = "RECOMMEND"
 )
 
 // Global config name list.
@@ -17,12 +17,17 @@ package statistics
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"slices"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"go.uber.org/zap"
 )
@@ -63,8 +68,10 @@ func BuildExtendedStats(sctx sessionctx.Context,
 
 func fillExtendedStatsItemVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeCardinality:
+		return fillExtStatsCardinalityVals(sctx, item, cols, collectors)
+	case ast.StatsTypeDependency:
+		return fillExtStatsDependencyVals(sctx, item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return fillExtStatsCorrVals(sctx, item, cols, collectors)
 	}
@@ -72,15 +79,7 @@ func fillExtendedStatsItemVals(sctx sessionctx.Context, item *ExtendedStatsItem,
 }
 
 func fillExtStatsCorrVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
-	colOffsets := make([]int, 0, 2)
-	for _, id := range item.ColIDs {
-		for i, col := range cols {
-			if col.ID == id {
-				colOffsets = append(colOffsets, i)
-				break
-			}
-		}
-	}
+	colOffsets := extStatsColOffsets(item, cols)
 	if len(colOffsets) != 2 {
 		return nil
 	}
@@ -140,3 +139,144 @@ func fillExtStatsCorrVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols
 	item.ScalarVals = (itemsCount*corrXYSum - corrXSum*corrXSum) / (itemsCount*corrX2Sum - corrXSum*corrXSum)
 	return item
 }
+
+// fillExtStatsCardinalityVals estimates the number of distinct value combinations of the columns, i.e, the NDV of the
+// column group, from the sampled rows in which none of the columns is null.
+func fillExtStatsCardinalityVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
+	colOffsets := extStatsColOffsets(item, cols)
+	if len(colOffsets) != len(item.ColIDs) || len(colOffsets) < 2 {
+		return nil
+	}
+	rows, err := extStatsSampleRows(sctx.GetSessionVars().StmtCtx, colOffsets, collectors)
+	if err != nil {
+		return nil
+	}
+	if len(rows) == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	// The number of rows in which none of the columns is null is at most the smallest non-null count of the columns.
+	rowCount := collectors[colOffsets[0]].Count
+	for _, offset := range colOffsets[1:] {
+		rowCount = min(rowCount, collectors[offset].Count)
+	}
+	counter := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		counter[strings.Join(row, "")]++
+	}
+	var onlyOnceItems uint64
+	for _, cnt := range counter {
+		if cnt == 1 {
+			onlyOnceItems++
+		}
+	}
+	ndv := estimateNDVBySample(uint64(len(rows)), uint64(len(counter)), onlyOnceItems, uint64(max(rowCount, int64(len(rows)))))
+	// The NDV of the column group is not less than the NDV of any column, and not greater than the product of them.
+	lowerBound, upperBound := float64(0), float64(1)
+	for _, offset := range colOffsets {
+		if collectors[offset].FMSketch == nil {
+			upperBound = math.Inf(1)
+			continue
+		}
+		colNDV := float64(collectors[offset].FMSketch.NDV())
+		lowerBound = max(lowerBound, colNDV)
+		upperBound *= colNDV
+	}
+	item.ScalarVals = min(max(float64(ndv), lowerBound), upperBound)
+	return item
+}
+
+// fillExtStatsDependencyVals computes the degree to which the first column functionally determines the second one,
+// i.e, the fraction of the sampled rows whose value of the first column always comes with the same value of the
+// second column.
+func fillExtStatsDependencyVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
+	colOffsets := extStatsColOffsets(item, cols)
+	if len(colOffsets) != 2 {
+		return nil
+	}
+	rows, err := extStatsSampleRows(sctx.GetSessionVars().StmtCtx, colOffsets, collectors)
+	if err != nil {
+		return nil
+	}
+	if len(rows) == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	type dependencyGroup struct {
+		determined string
+		count      int
+		consistent bool
+	}
+	groups := make(map[string]*dependencyGroup, len(rows))
+	for _, row := range rows {
+		group, ok := groups[row[0]]
+		if !ok {
+			groups[row[0]] = &dependencyGroup{determined: row[1], count: 1, consistent: true}
+			continue
+		}
+		group.count++
+		group.consistent = group.consistent && group.determined == row[1]
+	}
+	supportedRows := 0
+	for _, group := range groups {
+		if group.consistent {
+			supportedRows += group.count
+		}
+	}
+	item.ScalarVals = float64(supportedRows) / float64(len(rows))
+	return item
+}
+
+// extStatsColOffsets returns the offsets of the columns of the extended stats in cols.
+func extStatsColOffsets(item *ExtendedStatsItem, cols []*model.ColumnInfo) []int {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	return colOffsets
+}
+
+// extStatsSampleRows joins the samples of the columns by their ordinals, and returns the encoded values of the sampled
+// rows in which none of the columns is null.
+func extStatsSampleRows(sc *stmtctx.StatementContext, colOffsets []int, collectors []*SampleCollector) ([][]string, error) {
+	sampleNum := len(collectors[colOffsets[0]].Samples)
+	rows := make(map[int][]string, sampleNum)
+	for i, offset := range colOffsets {
+		for _, sample := range collectors[offset].Samples {
+			if sample.Value.IsNull() {
+				continue
+			}
+			row, ok := rows[sample.Ordinal]
+			if !ok {
+				if i > 0 {
+					continue
+				}
+				row = make([]string, 0, len(colOffsets))
+			} else if len(row) != i {
+				continue
+			}
+			b, err := codec.EncodeKey(sc.TimeZone(), nil, sample.Value)
+			if err != nil {
+				return nil, err
+			}
+			rows[sample.Ordinal] = append(row, string(b))
+		}
+	}
+	ordinals := make([]int, 0, len(rows))
+	for ordinal, row := range rows {
+		if len(row) == len(colOffsets) {
+			ordinals = append(ordinals, ordinal)
+		}
+	}
+	slices.Sort(ordinals)
+	result := make([][]string, 0, len(ordinals))
+	for _, ordinal := range ordinals {
+		result = append(result, rows[ordinal])
+	}
+	return result, nil
+}
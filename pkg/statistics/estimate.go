@@ -31,6 +31,17 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 		// Nothing to do, no change with scale ratio
 		return sampleNDV, scaleRatio
 	}
+	return estimateNDVBySample(sampleSize, sampleNDV, onlyOnceItems, rowCount), scaleRatio
+}
+
+// estimateNDVBySample estimates the ndv of rowCount rows from a sample of sampleSize rows, in which there are
+// sampleNDV distinct values and onlyOnceItems values occurring only once.
+func estimateNDVBySample(sampleSize, sampleNDV, onlyOnceItems, rowCount uint64) (ndv uint64) {
+	if onlyOnceItems == sampleSize {
+		return rowCount
+	} else if onlyOnceItems == 0 {
+		return sampleNDV
+	}
 	// Charikar, Moses, et al. "Towards estimation error guarantees for distinct values."
 	// Proceedings of the nineteenth ACM SIGMOD-SIGACT-SIGART symposium on Principles of database systems. ACM, 2000.
 	// This is GEE in that paper.
@@ -45,5 +56,5 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 	ndv = uint64(math.Sqrt(rowCountN/n)*f1 + d - f1 + 0.5)
 	ndv = max(ndv, sampleNDV)
 	ndv = min(ndv, rowCount)
-	return ndv, scaleRatio
+	return ndv
 }
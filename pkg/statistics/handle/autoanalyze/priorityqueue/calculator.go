@@ -21,6 +21,9 @@ const (
 	EventNone = 0.0
 	// EventNewIndex represents a special event for newly added indexes.
 	EventNewIndex = 2.0
+	// EventCardinalityMisestimation represents a special event for tables whose statistics are found to cause
+	// systematic misestimation by the cardinality feedback.
+	EventCardinalityMisestimation = 1.0
 )
 
 // TODO: make these configurable.
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle/lockstats"
 	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/timeutil"
@@ -169,6 +170,9 @@ func (r *Refresher) RebuildTableAnalysisJobQueue() error {
 				return err
 			}
 
+			// The tables whose filters are found to be systematically misestimated by the cardinality feedback.
+			misestimatedTables := feedback.GlobalCollector.MisestimatedTables()
+
 			dbs := infoschema.AllSchemaNames(is)
 			for _, db := range dbs {
 				// Sometimes the tables are too many. Auto-analyze will take too much time on it.
@@ -194,12 +198,15 @@ func (r *Refresher) RebuildTableAnalysisJobQueue() error {
 						continue
 					}
 					pi := tblInfo.GetPartitionInfo()
-					pushJobFunc := func(job priorityqueue.AnalysisJob) {
+					pushJobFunc := func(job priorityqueue.AnalysisJob, misestimated bool) {
 						if job == nil {
 							return
 						}
 						// Calculate the weight of the job.
 						weight := calculator.CalculateWeight(job)
+						if misestimated {
+							weight += priorityqueue.EventCardinalityMisestimation
+						}
 						// We apply a penalty to larger tables, which can potentially result in a negative weight.
 						// To prevent this, we filter out any negative weights. Under normal circumstances, table sizes should not be negative.
 						if weight <= 0 {
@@ -215,15 +222,20 @@ func (r *Refresher) RebuildTableAnalysisJobQueue() error {
 					}
 					// No partitions, analyze the whole table.
 					if pi == nil {
+						tblStats := r.statsHandle.GetTableStatsForAutoAnalyze(tblInfo)
 						job := CreateTableAnalysisJob(
 							sctx,
 							db,
 							tblInfo,
-							r.statsHandle.GetTableStatsForAutoAnalyze(tblInfo),
+							tblStats,
 							autoAnalyzeRatio,
 							currentTs,
 						)
-						pushJobFunc(job)
+						_, misestimated := misestimatedTables[tblInfo.ID]
+						if job == nil && misestimated {
+							job = createAnalysisJobForMisestimation(sctx, db, tblInfo, tblInfo.ID, "", tblStats, currentTs)
+						}
+						pushJobFunc(job, misestimated)
 						// Skip the rest of the loop.
 						continue
 					}
@@ -249,7 +261,11 @@ func (r *Refresher) RebuildTableAnalysisJobQueue() error {
 								autoAnalyzeRatio,
 								currentTs,
 							)
-							pushJobFunc(job)
+							_, misestimated := misestimatedTables[pIDAndName.ID]
+							if job == nil && misestimated {
+								job = createAnalysisJobForMisestimation(sctx, db, tblInfo, pIDAndName.ID, pIDAndName.Name, stats, currentTs)
+							}
+							pushJobFunc(job, misestimated)
 						}
 					} else {
 						job := createTableAnalysisJobForPartitions(
@@ -261,7 +277,8 @@ func (r *Refresher) RebuildTableAnalysisJobQueue() error {
 							autoAnalyzeRatio,
 							currentTs,
 						)
-						pushJobFunc(job)
+						_, misestimated := misestimatedTables[tblInfo.ID]
+						pushJobFunc(job, misestimated)
 					}
 				}
 			}
@@ -364,6 +381,60 @@ func CreateStaticPartitionAnalysisJob(
 	return job
 }
 
+// createAnalysisJobForMisestimation creates a TableAnalysisJob for the physical table whose statistics are found to
+// cause systematic misestimation by the cardinality feedback, even if its change percentage doesn't reach the auto
+// analyze ratio. The table is skipped if it's not modified since the last analysis, since the statistics rebuilt from
+// the same data can't fix the misestimation, which is left to the correction factors of the feedback.
+func createAnalysisJobForMisestimation(
+	sctx sessionctx.Context,
+	tableSchema string,
+	tblInfo *model.TableInfo,
+	physicalID int64,
+	partitionName string,
+	tblStats *statistics.Table,
+	currentTs uint64,
+) priorityqueue.AnalysisJob {
+	if !isEligibleForAnalysis(tblStats) || !tblStats.IsAnalyzed() || tblStats.ModifyCount == 0 {
+		return nil
+	}
+
+	tableStatsVer := sctx.GetSessionVars().AnalyzeVersion
+	statistics.CheckAnalyzeVerOnTable(tblStats, &tableStatsVer)
+
+	tblCnt := float64(tblStats.RealtimeCount)
+	if histCnt := tblStats.GetAnalyzeRowCount(); histCnt > 0 {
+		tblCnt = histCnt
+	}
+	changePercentage := float64(tblStats.ModifyCount) / tblCnt
+	tableSize := calculateTableSize(tblInfo, tblStats)
+	lastAnalysisDuration := GetTableLastAnalyzeDuration(tblStats, currentTs)
+
+	if partitionName == "" {
+		return priorityqueue.NewNonPartitionedTableAnalysisJob(
+			tableSchema,
+			tblInfo.Name.O,
+			tblInfo.ID,
+			nil,
+			tableStatsVer,
+			changePercentage,
+			tableSize,
+			lastAnalysisDuration,
+		)
+	}
+	return priorityqueue.NewStaticPartitionTableAnalysisJob(
+		tableSchema,
+		tblInfo.Name.O,
+		tblInfo.ID,
+		partitionName,
+		physicalID,
+		nil,
+		tableStatsVer,
+		changePercentage,
+		tableSize,
+		lastAnalysisDuration,
+	)
+}
+
 // CalculateChangePercentage calculates the change percentage of the table
 // based on the change count and the analysis count.
 func CalculateChangePercentage(
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/refresher"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
@@ -358,6 +359,50 @@ func TestRebuildTableAnalysisJobQueue(t *testing.T) {
 	require.GreaterOrEqual(t, indicators.LastAnalysisDuration, time.Duration(0))
 }
 
+func TestRebuildTableAnalysisJobQueueWithMisestimation(t *testing.T) {
+	old := exec.AutoAnalyzeMinCnt
+	defer func() {
+		exec.AutoAnalyzeMinCnt = old
+	}()
+	exec.AutoAnalyzeMinCnt = 0
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	handle := dom.StatsHandle()
+	require.Nil(t, handle.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t1")
+	// The change percentage doesn't reach the auto analyze ratio.
+	tk.MustExec("insert into t1 values (6, 6)")
+	require.Nil(t, handle.DumpStatsDeltaToKV(true))
+	require.Nil(t, handle.Update(dom.InfoSchema()))
+
+	r := refresher.NewRefresher(handle, dom.SysProcTracker())
+	require.NoError(t, r.RebuildTableAnalysisJobQueue())
+	require.Equal(t, 0, r.Jobs.Len())
+
+	// The table is analyzed since its filters are systematically misestimated.
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	exprs := []string{"lt(test.t1.a, 3)", "lt(test.t1.b, 3)"}
+	corrections := feedback.NewCorrections()
+	corrections.Add(feedback.NewKey(tbl.Meta().ID, feedback.KindFilter, exprs), exprs, 10)
+	feedback.GlobalCollector.SetCorrections(corrections)
+	defer feedback.GlobalCollector.SetCorrections(feedback.NewCorrections())
+	require.NoError(t, r.RebuildTableAnalysisJobQueue())
+	require.Equal(t, 1, r.Jobs.Len())
+	job := r.Jobs.Pop()
+	require.Equal(t, 0.2, job.GetIndicators().ChangePercentage)
+	require.Greater(t, job.GetWeight(), priorityqueue.EventCardinalityMisestimation)
+
+	// The table which isn't modified since the last analysis is skipped.
+	tk.MustExec("analyze table t1")
+	require.Nil(t, handle.Update(dom.InfoSchema()))
+	require.NoError(t, r.RebuildTableAnalysisJobQueue())
+	require.Equal(t, 0, r.Jobs.Len())
+}
+
 func TestCalculateChangePercentage(t *testing.T) {
 	unanalyzedColumns := map[int64]*statistics.Column{
 		1: {},
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache"
 	"github.com/pingcap/tidb/pkg/statistics/handle/lockstats"
 	"github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -159,6 +160,9 @@ func DeleteTableStatsFromKV(sctx sessionctx.Context, statsIDs []int64) (err erro
 		if _, err = util.Exec(sctx, "delete from mysql.analyze_options where table_id = %?", statsID); err != nil {
 			return err
 		}
+		if err = deleteCardinalityFeedback(sctx, statsID); err != nil {
+			return err
+		}
 		if _, err = util.Exec(sctx, lockstats.DeleteLockSQL, statsID); err != nil {
 			return err
 		}
@@ -166,6 +170,27 @@ func DeleteTableStatsFromKV(sctx sessionctx.Context, statsIDs []int64) (err erro
 	return nil
 }
 
+// deleteCardinalityFeedback deletes the cardinality feedback on the filters of the physical table and on the joins
+// it takes part in, which is collected against its outdated statistics.
+func deleteCardinalityFeedback(sctx sessionctx.Context, physicalID int64) error {
+	rows, _, err := util.ExecRows(sctx, "select expr_digest from mysql.stats_cardinality_feedback where table_id = %? and kind = %?", physicalID, feedback.KindJoin)
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		digests := make([]string, 0, len(rows))
+		for _, row := range rows {
+			digests = append(digests, row.GetString(0))
+		}
+		// The feedback of a join is stored for each of the joined tables.
+		if _, err = util.Exec(sctx, "delete from mysql.stats_cardinality_feedback where kind = %? and expr_digest in (%?)", feedback.KindJoin, digests); err != nil {
+			return err
+		}
+	}
+	_, err = util.Exec(sctx, "delete from mysql.stats_cardinality_feedback where table_id = %?", physicalID)
+	return err
+}
+
 func forCount(total int64, batch int64) int64 {
 	result := total / batch
 	if total%batch > 0 {
@@ -22,7 +22,6 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/config"
-	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
@@ -226,16 +225,12 @@ func ExtendedStatsFromStorage(sctx sessionctx.Context, table *statistics.Table,
 				return nil, err
 			}
 			statsStr := row.GetString(4)
-			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation {
-				if statsStr != "" {
-					item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
-					if err != nil {
-						statslogutil.StatsLogger().Error("parse scalar stats failed", zap.String("stats", statsStr), zap.Error(err))
-						return nil, err
-					}
+			if statsStr != "" {
+				item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
+				if err != nil {
+					statslogutil.StatsLogger().Error("parse scalar stats failed", zap.String("stats", statsStr), zap.Error(err))
+					return nil, err
 				}
-			} else {
-				item.StringVals = statsStr
 			}
 			table.ExtendedStats.Stats[name] = item
 		}
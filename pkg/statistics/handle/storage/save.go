@@ -20,7 +20,6 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/statistics"
@@ -295,25 +294,23 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 			}
 		}
 	}
-	// 3. Save extended statistics.
+	// 3. Clear the cardinality feedback, which is collected against the outdated statistics.
+	if err = deleteCardinalityFeedback(sctx, tableID); err != nil {
+		return 0, err
+	}
+	// 4. Save extended statistics.
 	extStats := results.ExtStats
 	if extStats == nil || len(extStats.Stats) == 0 {
 		return
 	}
 	var bytes []byte
-	var statsStr string
 	for name, item := range extStats.Stats {
 		bytes, err = json.Marshal(item.ColIDs)
 		if err != nil {
 			return 0, err
 		}
 		strColIDs := string(bytes)
-		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
-			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
-		}
+		statsStr := fmt.Sprintf("%f", item.ScalarVals)
 		if _, err = util.Exec(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, statistics.ExtendedStatsAnalyzed); err != nil {
 			return 0, err
 		}
@@ -105,7 +105,10 @@ func DumpTableStatColSizeToKV(sctx sessionctx.Context, id int64, delta variable.
 func InsertExtendedStats(sctx sessionctx.Context,
 	statsCache types.StatsCache,
 	statsName string, colIDs []int64, tp int, tableID int64, ifNotExists bool) (statsVer uint64, err error) {
-	slices.Sort(colIDs)
+	// The dependency is from the first column to the second one, so the order of its columns is kept.
+	if tp != int(ast.StatsTypeDependency) {
+		slices.Sort(colIDs)
+	}
 	bytes, err := json.Marshal(colIDs)
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -174,13 +177,7 @@ func SaveExtendedStatsToStorage(sctx sessionctx.Context,
 			return 0, errors.Trace(err)
 		}
 		strColIDs := string(bytes)
-		var statsStr string
-		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
-			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
-		}
+		statsStr := fmt.Sprintf("%f", item.ScalarVals)
 		// If isLoad is true, it's INSERT; otherwise, it's UPDATE.
 		if _, err := statsutil.Exec(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, statistics.ExtendedStatsAnalyzed); err != nil {
 			return 0, err
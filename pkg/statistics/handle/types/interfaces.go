@@ -84,6 +84,10 @@ type StatsUsage interface {
 
 	// DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
 	DumpColStatsUsageToKV() error
+
+	// DumpCardinalityFeedbackToKV dumps the cardinality feedback collected on this node to KV, and reloads the
+	// correction factors from the feedback of all the nodes.
+	DumpCardinalityFeedbackToKV() error
 }
 
 // IndexUsage is an interface to define the function of collecting index usage stats.
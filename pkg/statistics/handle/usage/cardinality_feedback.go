@@ -0,0 +1,125 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics/handle/usage/feedback"
+	utilstats "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+// decayedFeedbackSQL returns the SQL expression of the column of the persisted feedback, halved once for each decay
+// interval passed since the window of the feedback started. The counts are kept integers.
+func decayedFeedbackSQL(column string, isCount bool) string {
+	decayed := fmt.Sprintf("%s / POW(2, FLOOR(TIMESTAMPDIFF(SECOND, window_start, NOW(6)) / %d))", column, int64(feedback.DecayInterval/time.Second))
+	if isCount {
+		return "FLOOR(" + decayed + ")"
+	}
+	return decayed
+}
+
+// DumpCardinalityFeedbackToKV dumps the cardinality feedback collected on this node to KV, and reloads the
+// correction factors from the feedback of all the nodes.
+func (s *statsUsageImpl) DumpCardinalityFeedbackToKV() error {
+	samples := feedback.GlobalCollector.GetSamplesAndReset()
+	keys := make([]feedback.Key, 0, len(samples))
+	for key := range samples {
+		keys = append(keys, key)
+	}
+	defer func() {
+		// Put back the samples which failed to be dumped.
+		feedback.GlobalCollector.Merge(samples)
+	}()
+	// Use batch insert to reduce cost.
+	for i := 0; i < len(keys); i += batchInsertSize {
+		end := min(i+batchInsertSize, len(keys))
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, "INSERT INTO mysql.stats_cardinality_feedback (table_id, kind, expr_digest, exprs, sql_digest, exec_count, misestimate_count, est_rows, act_rows, sum_log_ratio) VALUES ")
+		for j := i; j < end; j++ {
+			sample := samples[keys[j]]
+			exprs, err := json.Marshal(sample.Exprs)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			sqlescape.MustFormatSQL(sql, "(%?, %?, %?, %?, %?, %?, %?, %?, %?, %?)", keys[j].TableID, keys[j].Kind, keys[j].Digest, string(exprs),
+				sample.SQLDigest, sample.ExecCount, sample.MisestimateCount, sample.EstRows, sample.ActRows, sample.SumLogRatio)
+			if j < end-1 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+		}
+		// The persisted feedback is decayed before the new one is added, and the window restarts once it's decayed.
+		// The window is assigned at last, so the other columns are decayed by the old window.
+		sql.WriteString(` ON DUPLICATE KEY UPDATE sql_digest = VALUES(sql_digest)`)
+		fmt.Fprintf(sql, `, exec_count = %s + VALUES(exec_count)`, decayedFeedbackSQL("exec_count", true))
+		fmt.Fprintf(sql, `, misestimate_count = %s + VALUES(misestimate_count)`, decayedFeedbackSQL("misestimate_count", true))
+		fmt.Fprintf(sql, `, est_rows = %s + VALUES(est_rows)`, decayedFeedbackSQL("est_rows", false))
+		fmt.Fprintf(sql, `, act_rows = %s + VALUES(act_rows)`, decayedFeedbackSQL("act_rows", false))
+		fmt.Fprintf(sql, `, sum_log_ratio = %s + VALUES(sum_log_ratio)`, decayedFeedbackSQL("sum_log_ratio", false))
+		fmt.Fprintf(sql, `, window_start = IF(TIMESTAMPDIFF(SECOND, window_start, NOW(6)) >= %d, NOW(6), window_start)`, int64(feedback.DecayInterval/time.Second))
+		if err := utilstats.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+			_, _, err := utilstats.ExecRows(sctx, sql.String())
+			return err
+		}); err != nil {
+			return errors.Trace(err)
+		}
+		for j := i; j < end; j++ {
+			delete(samples, keys[j])
+		}
+	}
+	return errors.Trace(s.loadCardinalityCorrections())
+}
+
+// loadCardinalityCorrections loads the correction factors of the estimations which are systematically off, and
+// deletes the feedback which has decayed out.
+func (s *statsUsageImpl) loadCardinalityCorrections() error {
+	return utilstats.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		execCount := decayedFeedbackSQL("exec_count", true)
+		if _, _, err := utilstats.ExecRows(sctx, fmt.Sprintf("DELETE FROM mysql.stats_cardinality_feedback WHERE %s = 0", execCount)); err != nil {
+			return err
+		}
+		rows, _, err := utilstats.ExecRows(sctx, fmt.Sprintf("SELECT table_id, kind, expr_digest, exprs, CAST(%s AS SIGNED), CAST(%s AS SIGNED), %s FROM mysql.stats_cardinality_feedback WHERE %s >= %%?",
+			execCount, decayedFeedbackSQL("misestimate_count", true), decayedFeedbackSQL("sum_log_ratio", false), execCount), feedback.MinExecCount)
+		if err != nil {
+			return err
+		}
+		corrections := feedback.NewCorrections()
+		for _, row := range rows {
+			sample := &feedback.Sample{
+				ExecCount:        row.GetInt64(4),
+				MisestimateCount: row.GetInt64(5),
+				SumLogRatio:      row.GetFloat64(6),
+			}
+			factor, ok := sample.Correction()
+			if !ok {
+				continue
+			}
+			var exprs []string
+			if err := json.Unmarshal([]byte(row.GetString(3)), &exprs); err != nil {
+				return errors.Trace(err)
+			}
+			key := feedback.Key{TableID: row.GetInt64(0), Kind: feedback.Kind(row.GetInt64(1)), Digest: row.GetString(2)}
+			corrections.Add(key, exprs, factor)
+		}
+		feedback.GlobalCollector.SetCorrections(corrections)
+		return nil
+	})
+}
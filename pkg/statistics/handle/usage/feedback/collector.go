@@ -0,0 +1,291 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feedback
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the kind of the estimation which the feedback is collected for.
+type Kind int8
+
+const (
+	// KindFilter is the feedback on the selectivity of the filters on a physical table.
+	KindFilter Kind = 1
+	// KindJoin is the feedback on the selectivity of an inner join.
+	KindJoin Kind = 2
+)
+
+const (
+	// MinExecCount is the min number of the executions the feedback is collected from before it's trusted.
+	MinExecCount = 3
+	// misestimateRatio is the ratio between the actual and the estimated row count over which (or under whose
+	// reciprocal) an execution is regarded as misestimated.
+	misestimateRatio = 2.0
+	// systematicMisestimateRate is the min rate of the misestimated executions for the misestimation to be regarded
+	// as systematic rather than caused by the data skew of some parameters.
+	systematicMisestimateRate = 0.8
+	// maxCorrection bounds the correction factor, as well as its reciprocal.
+	maxCorrection = 1e4
+	// DecayInterval is the interval after which the persisted feedback is halved, so the feedback collected against
+	// the outdated data fades out and the recent executions outweigh it.
+	DecayInterval = 24 * time.Hour
+)
+
+// Key identifies the estimation the feedback is collected for.
+type Key struct {
+	// Digest is the digest of the expressions of the estimation, as well as the tables joined for the joins.
+	Digest string
+	// TableID is the physical table ID of the filters. A join has one key for each of the joined physical tables,
+	// which share the same digest, so its feedback can be found and cleared by any of them.
+	TableID int64
+	Kind    Kind
+}
+
+// NewKey creates the key of the estimation on the expressions, exprs must be sorted.
+func NewKey(tableID int64, kind Kind, exprs []string) Key {
+	hash := sha256.Sum256([]byte(strings.Join(exprs, "\n")))
+	return Key{TableID: tableID, Kind: kind, Digest: hex.EncodeToString(hash[:])}
+}
+
+// NewJoinKeys creates the keys of the estimation on the join of the physical tables, one for each table, exprs must
+// be sorted. It returns nil if there's no table.
+func NewJoinKeys(tableIDs []int64, exprs []string) []Key {
+	tableIDs = slices.Clone(tableIDs)
+	slices.Sort(tableIDs)
+	tableIDs = slices.Compact(tableIDs)
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	for _, tableID := range tableIDs {
+		sb.WriteString(strconv.FormatInt(tableID, 10))
+		sb.WriteString(",")
+	}
+	for _, expr := range exprs {
+		sb.WriteString("\n")
+		sb.WriteString(expr)
+	}
+	hash := sha256.Sum256([]byte(sb.String()))
+	digest := hex.EncodeToString(hash[:])
+	keys := make([]Key, 0, len(tableIDs))
+	for _, tableID := range tableIDs {
+		keys = append(keys, Key{TableID: tableID, Kind: KindJoin, Digest: digest})
+	}
+	return keys
+}
+
+// Sample is the aggregated feedback of the executions on an estimation.
+type Sample struct {
+	// Exprs are the sorted expressions of the estimation.
+	Exprs []string
+	// SQLDigest is the digest of the last statement the feedback is collected from.
+	SQLDigest string
+	// ExecCount is the number of the executions.
+	ExecCount int64
+	// MisestimateCount is the number of the executions whose estimation is off by more than 2 times.
+	MisestimateCount int64
+	// EstRows sums the row counts estimated by the statistics, without any correction.
+	EstRows float64
+	// ActRows sums the actual row counts.
+	ActRows float64
+	// SumLogRatio sums the logarithms of the ratios between the actual and the estimated row counts.
+	SumLogRatio float64
+}
+
+// Merge merges the other sample into this one.
+func (s *Sample) Merge(other *Sample) {
+	s.Exprs = other.Exprs
+	s.SQLDigest = other.SQLDigest
+	s.ExecCount += other.ExecCount
+	s.MisestimateCount += other.MisestimateCount
+	s.EstRows += other.EstRows
+	s.ActRows += other.ActRows
+	s.SumLogRatio += other.SumLogRatio
+}
+
+// Correction returns the correction factor of the estimation, ok is false if there's not enough evidence that the
+// estimation is systematically off.
+func (s *Sample) Correction() (factor float64, ok bool) {
+	if s.ExecCount < MinExecCount || float64(s.MisestimateCount) < systematicMisestimateRate*float64(s.ExecCount) {
+		return 1, false
+	}
+	factor = math.Exp(s.SumLogRatio / float64(s.ExecCount))
+	return math.Max(math.Min(factor, maxCorrection), 1/maxCorrection), true
+}
+
+// FilterCorrection is the correction factor of the selectivity of a set of filters.
+type FilterCorrection struct {
+	// Exprs are the sorted filters.
+	Exprs  []string
+	Factor float64
+}
+
+// Corrections are the correction factors loaded from the persisted feedback.
+type Corrections struct {
+	// Filters are the corrections on the filters of each physical table.
+	Filters map[int64][]FilterCorrection
+	// Joins are the corrections on the joins, indexed by the digest.
+	Joins map[string]float64
+}
+
+// NewCorrections creates an empty Corrections.
+func NewCorrections() *Corrections {
+	return &Corrections{
+		Filters: make(map[int64][]FilterCorrection),
+		Joins:   make(map[string]float64),
+	}
+}
+
+// Add adds the correction of the estimation identified by the key.
+func (c *Corrections) Add(key Key, exprs []string, factor float64) {
+	switch key.Kind {
+	case KindFilter:
+		c.Filters[key.TableID] = append(c.Filters[key.TableID], FilterCorrection{Exprs: exprs, Factor: factor})
+	case KindJoin:
+		c.Joins[key.Digest] = factor
+	}
+}
+
+// Collector collects the cardinality feedback of the whole node, and holds the correction factors loaded from the
+// persisted feedback.
+type Collector struct {
+	samples     map[Key]*Sample
+	corrections atomic.Pointer[Corrections]
+	mu          sync.Mutex
+}
+
+// NewCollector creates a new Collector.
+func NewCollector() *Collector {
+	c := &Collector{samples: make(map[Key]*Sample)}
+	c.corrections.Store(NewCorrections())
+	return c
+}
+
+// GlobalCollector is the cardinality feedback collector of this node.
+var GlobalCollector = NewCollector()
+
+// Record records the feedback of an execution on the estimation of the expressions, exprs must be sorted.
+// estRows is the row count estimated by the statistics without any correction.
+func (c *Collector) Record(key Key, exprs []string, sqlDigest string, estRows, actRows float64) {
+	// Smooth the ratio to avoid the infinity when there's no row.
+	ratio := (actRows + 1) / (estRows + 1)
+	sample := &Sample{
+		Exprs:       exprs,
+		SQLDigest:   sqlDigest,
+		ExecCount:   1,
+		EstRows:     estRows,
+		ActRows:     actRows,
+		SumLogRatio: math.Log(ratio),
+	}
+	if ratio > misestimateRatio || ratio < 1/misestimateRatio {
+		sample.MisestimateCount = 1
+	}
+	c.Merge(map[Key]*Sample{key: sample})
+}
+
+// Merge merges the samples into the collector.
+func (c *Collector) Merge(samples map[Key]*Sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, sample := range samples {
+		if s, ok := c.samples[key]; ok {
+			s.Merge(sample)
+		} else {
+			c.samples[key] = sample
+		}
+	}
+}
+
+// GetSamplesAndReset returns the collected samples and resets the collector.
+func (c *Collector) GetSamplesAndReset() map[Key]*Sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	samples := c.samples
+	c.samples = make(map[Key]*Sample)
+	return samples
+}
+
+// SetCorrections replaces the correction factors.
+func (c *Collector) SetCorrections(corrections *Corrections) {
+	c.corrections.Store(corrections)
+}
+
+// GetFilterCorrections returns the correction factors on the filters of the physical table.
+func (c *Collector) GetFilterCorrections(tableID int64) []FilterCorrection {
+	return c.corrections.Load().Filters[tableID]
+}
+
+// GetCorrection returns the correction factor of the estimation identified by the key, it's 1 if there's none.
+func (c *Collector) GetCorrection(key Key) float64 {
+	corrections := c.corrections.Load()
+	if key.Kind == KindJoin {
+		if factor, ok := corrections.Joins[key.Digest]; ok {
+			return factor
+		}
+		return 1
+	}
+	for _, correction := range corrections.Filters[key.TableID] {
+		if NewKey(key.TableID, KindFilter, correction.Exprs) == key {
+			return correction.Factor
+		}
+	}
+	return 1
+}
+
+// MisestimatedTables returns the physical tables whose filters are systematically misestimated.
+func (c *Collector) MisestimatedTables() map[int64]struct{} {
+	corrections := c.corrections.Load()
+	tables := make(map[int64]struct{}, len(corrections.Filters))
+	for tableID := range corrections.Filters {
+		tables[tableID] = struct{}{}
+	}
+	return tables
+}
+
+// CorrectFilters returns the correction factor on the selectivity of the filters on the physical table. Each
+// correction whose filters are all in exprs is applied, the ones overlapping with a larger applied one are skipped.
+func CorrectFilters(corrections []FilterCorrection, exprs []string) float64 {
+	corrections = slices.Clone(corrections)
+	slices.SortStableFunc(corrections, func(a, b FilterCorrection) int {
+		return len(b.Exprs) - len(a.Exprs)
+	})
+	covered := make([]bool, len(exprs))
+	factor := 1.0
+OUTER:
+	for _, correction := range corrections {
+		offsets := make([]int, 0, len(correction.Exprs))
+		for _, expr := range correction.Exprs {
+			offset := slices.Index(exprs, expr)
+			if offset < 0 || covered[offset] {
+				continue OUTER
+			}
+			offsets = append(offsets, offset)
+		}
+		for _, offset := range offsets {
+			covered[offset] = true
+		}
+		factor *= correction.Factor
+	}
+	return factor
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectorRecord(t *testing.T) {
+	c := NewCollector()
+	exprs := []string{"gt(test.t.a, 1)", "lt(test.t.b, 2)"}
+	key := NewKey(1, KindFilter, exprs)
+	require.Equal(t, key, NewKey(1, KindFilter, []string{"gt(test.t.a, 1)", "lt(test.t.b, 2)"}))
+	require.NotEqual(t, key, NewKey(2, KindFilter, exprs))
+
+	c.Record(key, exprs, "d1", 9, 99)
+	c.Record(key, exprs, "d2", 9, 99)
+	samples := c.GetSamplesAndReset()
+	require.Len(t, samples, 1)
+	sample := samples[key]
+	require.Equal(t, "d2", sample.SQLDigest)
+	require.Equal(t, int64(2), sample.ExecCount)
+	require.Equal(t, int64(2), sample.MisestimateCount)
+	require.Equal(t, 18.0, sample.EstRows)
+	require.Equal(t, 198.0, sample.ActRows)
+	require.Empty(t, c.GetSamplesAndReset())
+
+	// Not enough executions.
+	_, ok := sample.Correction()
+	require.False(t, ok)
+	c.Merge(samples)
+	c.Record(key, exprs, "d3", 9, 99)
+	sample = c.GetSamplesAndReset()[key]
+	factor, ok := sample.Correction()
+	require.True(t, ok)
+	require.InDelta(t, 10, factor, 1e-9)
+
+	// The misestimation isn't systematic.
+	c.Record(key, exprs, "d4", 9, 9)
+	c.Merge(map[Key]*Sample{key: sample})
+	sample = c.GetSamplesAndReset()[key]
+	_, ok = sample.Correction()
+	require.False(t, ok)
+}
+
+func TestCorrections(t *testing.T) {
+	c := NewCollector()
+	ab := []string{"gt(test.t.a, 1)", "lt(test.t.b, 2)"}
+	a := []string{"gt(test.t.a, 1)"}
+	b := []string{"lt(test.t.b, 2)"}
+	corrections := NewCorrections()
+	corrections.Add(NewKey(1, KindFilter, ab), ab, 10)
+	corrections.Add(NewKey(1, KindFilter, a), a, 2)
+	corrections.Add(NewKey(1, KindFilter, b), b, 3)
+	joinKeys := NewJoinKeys([]int64{2, 1, 2}, a)
+	require.Equal(t, []Key{{TableID: 1, Kind: KindJoin, Digest: joinKeys[0].Digest}, {TableID: 2, Kind: KindJoin, Digest: joinKeys[0].Digest}}, joinKeys)
+	require.Equal(t, joinKeys, NewJoinKeys([]int64{1, 2}, a))
+	require.NotEqual(t, joinKeys[0].Digest, NewJoinKeys([]int64{1, 3}, a)[0].Digest)
+	require.NotEqual(t, joinKeys[0].Digest, NewJoinKeys([]int64{1, 2}, b)[0].Digest)
+	require.Nil(t, NewJoinKeys(nil, a))
+	for _, key := range joinKeys {
+		corrections.Add(key, a, 4)
+	}
+	c.SetCorrections(corrections)
+
+	require.Equal(t, 10.0, c.GetCorrection(NewKey(1, KindFilter, ab)))
+	require.Equal(t, 3.0, c.GetCorrection(NewKey(1, KindFilter, b)))
+	require.Equal(t, 1.0, c.GetCorrection(NewKey(2, KindFilter, b)))
+	require.Equal(t, 4.0, c.GetCorrection(joinKeys[1]))
+	require.Equal(t, 1.0, c.GetCorrection(NewJoinKeys([]int64{1, 3}, a)[0]))
+	require.Equal(t, 1.0, c.GetCorrection(NewJoinKeys([]int64{1, 2}, b)[0]))
+	require.Equal(t, map[int64]struct{}{1: {}}, c.MisestimatedTables())
+
+	filters := c.GetFilterCorrections(1)
+	// The larger correction covers the smaller ones.
+	require.Equal(t, 10.0, CorrectFilters(filters, []string{"eq(test.t.c, 1)", "gt(test.t.a, 1)", "lt(test.t.b, 2)"}))
+	require.Equal(t, 2.0, CorrectFilters(filters, []string{"eq(test.t.c, 1)", "gt(test.t.a, 1)"}))
+	require.Equal(t, 1.0, CorrectFilters(filters, []string{"eq(test.t.c, 1)"}))
+	require.Equal(t, 1.0, CorrectFilters(c.GetFilterCorrections(2), ab))
+}
@@ -247,6 +247,9 @@ type HistColl struct {
 	// For normal index, the column id is enough, as we already have in Idx2ColUniqueIDs. But currently, mv index needs more
 	// information to match the filter against the mv index columns, and we need this map to provide this information.
 	MVIdx2Columns map[int64][]*expression.Column
+	// ExtStats is the extended statistics of the table. It's used to estimate the selectivity and the NDV of the
+	// correlated columns in planner.
+	ExtStats *ExtendedStatsColl
 }
 
 // TableMemoryUsage records tbl memory usage
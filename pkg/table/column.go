@@ -19,6 +19,7 @@
 package table
 
 import (
+	"encoding/binary"
 	"fmt"
 	"strconv"
 	"strings"
@@ -375,6 +376,12 @@ func castColumnValue(tc types.Context, ec errctx.Context, sqlMode mysql.SQLMode,
 	if col.GetType() == mysql.TypeString && !types.IsBinaryStr(&col.FieldType) {
 		truncateTrailingSpaces(&casted)
 	}
+	if col.SRID != nil && !casted.IsNull() {
+		// The geometry has been validated by ConvertTo, it's prefixed by the SRID.
+		if srid := binary.LittleEndian.Uint32(casted.GetBytes()); srid != *col.SRID {
+			return casted, ErrWrongSRIDForColumn.GenWithStackByArgs(col.Name.O, srid, *col.SRID)
+		}
+	}
 	return casted, err
 }
 
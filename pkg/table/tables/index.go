@@ -29,8 +29,10 @@ import (
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tidb/pkg/util/tracing"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 )
 
 // index is the data structure for index data in the KV store.
@@ -91,9 +93,7 @@ func (c *index) GenIndexKey(ec errctx.Context, loc *time.Location, indexedValues
 // GenIndexValue generates the index value.
 func (c *index) GenIndexValue(ec errctx.Context, loc *time.Location, distinct bool, indexedValues []types.Datum,
 	h kv.Handle, restoredData []types.Datum, buf []byte) ([]byte, error) {
-	c.initNeedRestoreData.Do(func() {
-		c.needRestoredData = NeedRestoredData(c.idxInfo.Columns, c.tblInfo.Columns)
-	})
+	c.initNeedRestoreData.Do(c.checkNeedRestoredData)
 	idx, err := tablecodec.GenIndexValuePortal(loc, c.tblInfo, c.idxInfo, c.needRestoredData, distinct, false, indexedValues, h, c.phyTblID, restoredData, buf)
 	err = ec.HandleError(err)
 	return idx, err
@@ -104,7 +104,15 @@ func (c *index) GenIndexValue(ec errctx.Context, loc *time.Location, distinct bo
 // 2. (i1, [m1,m2], i2, ...) ==> [(i1, m1, i2, ...), (i1, m2, i2, ...)]
 // 3. (i1, null, i2, ...) ==> [(i1, null, i2, ...)]
 // 4. (i1, [], i2, ...) ==> nothing.
+// 5. If FULLTEXT index, (t1, t2, ...) ==> [(token1), (token2), ...], the tokens are distinct.
+// 6. If VECTOR index, (v) ==> [(partition of v)], and null ==> [(null)].
 func (c *index) getIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	if c.idxInfo.IsFullText() {
+		return c.getFullTextIndexedValue(indexedValues)
+	}
+	if c.idxInfo.IsVector() {
+		return c.getVectorIndexedValue(indexedValues)
+	}
 	if !c.idxInfo.MVIndex {
 		return [][]types.Datum{indexedValues}
 	}
@@ -151,6 +159,41 @@ out:
 	return vals
 }
 
+func (c *index) checkNeedRestoredData() {
+	// The tokens of FULLTEXT index and the partitions of VECTOR index are never used to restore the column values.
+	c.needRestoredData = !c.idxInfo.IsFullText() && !c.idxInfo.IsVector() && NeedRestoredData(c.idxInfo.Columns, c.tblInfo.Columns)
+}
+
+func (c *index) getFullTextIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	texts := make([]string, 0, len(indexedValues))
+	for _, v := range indexedValues {
+		if v.IsNull() {
+			continue
+		}
+		texts = append(texts, v.GetString())
+	}
+	tokens := fulltext.DistinctTokens(c.idxInfo.FullTextInfo.ParserType, texts...)
+	vals := make([][]types.Datum, 0, len(tokens))
+	for _, token := range tokens {
+		vals = append(vals, []types.Datum{types.NewBytesDatum([]byte(token))})
+	}
+	return vals
+}
+
+func (c *index) getVectorIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	if indexedValues[0].IsNull() {
+		// The NULL vectors are sorted first by the distance, they're indexed so the search can always find them.
+		return [][]types.Datum{indexedValues}
+	}
+	v, err := types.DecodeVectorFloat32(indexedValues[0].GetBytes())
+	if err != nil || len(v) != c.idxInfo.VectorInfo.Dimension {
+		// The value is checked when it's written to the column, it must come from the cleanup of a broken row.
+		return nil
+	}
+	partition := vectorindex.GetPartitioner(c.idxInfo.VectorInfo).Partition(v)
+	return [][]types.Datum{{types.NewBytesDatum(vectorindex.EncodePartition(partition))}}
+}
+
 // Create creates a new entry in the kvIndex data.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
@@ -231,9 +274,7 @@ func (c *index) Create(sctx table.MutateContext, txn kv.Transaction, indexedValu
 
 		// save the key buffer to reuse.
 		writeBufs.IndexKeyBuf = key
-		c.initNeedRestoreData.Do(func() {
-			c.needRestoredData = NeedRestoredData(c.idxInfo.Columns, c.tblInfo.Columns)
-		})
+		c.initNeedRestoreData.Do(c.checkNeedRestoredData)
 		idxVal, err := tablecodec.GenIndexValuePortal(sctx.GetSessionVars().StmtCtx.TimeZone(), c.tblInfo, c.idxInfo,
 			c.needRestoredData, distinct, opt.Untouched, value, h, c.phyTblID, handleRestoreData, nil)
 		err = sctx.GetSessionVars().StmtCtx.HandleError(err)
@@ -469,7 +510,7 @@ func (c *index) Delete(ctx table.MutateContext, txn kv.Transaction, indexedValue
 func (c *index) GenIndexKVIter(ec errctx.Context, loc *time.Location, indexedValue []types.Datum,
 	h kv.Handle, handleRestoreData []types.Datum) table.IndexKVGenerator {
 	var mvIndexValues [][]types.Datum
-	if c.Meta().MVIndex {
+	if c.Meta().MVIndex || c.Meta().IsFullText() || c.Meta().IsVector() {
 		mvIndexValues = c.getIndexedValue(indexedValue)
 		return table.NewMultiValueIndexKVGenerator(c, ec, loc, h, handleRestoreData, mvIndexValues)
 	}
@@ -149,6 +149,10 @@ func checkHandleConsistency(rowInsertion mutation, indexMutations []mutation, in
 		if !ok {
 			return errors.New("index not found")
 		}
+		if indexInfo.IsFullText() || indexInfo.IsVector() {
+			// The keys of FULLTEXT and VECTOR index are tokens and partitions rather than the column values.
+			continue
+		}
 
 		// If this is the temporary index data, need to remove the last byte of index data(version about when it is written).
 		var (
@@ -218,6 +222,10 @@ func checkIndexKeys(
 		if !ok {
 			return errors.New("index not found")
 		}
+		if indexInfo.IsFullText() || indexInfo.IsVector() {
+			// The keys of FULLTEXT and VECTOR index are tokens and partitions rather than the column values.
+			continue
+		}
 		rowColInfos, ok := indexIDToRowColInfos[idxID]
 		if !ok {
 			return errors.New("index not found")
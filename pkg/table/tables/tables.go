@@ -670,9 +670,11 @@ func (t *TableCommon) rebuildIndices(ctx table.MutateContext, txn kv.Transaction
 		// If InHandleForeignKeyTrigger or ForeignKeyTriggerCtx.HasFKCascades is true indicate we may have
 		// foreign key cascade need to handle later, then we still need to write index value,
 		// otherwise, the later foreign cascade executor may see data-index inconsistency in txn-mem-buffer.
+		// It's the same for the statements in the trigger bodies when HasTriggers is true.
 		sessVars := ctx.GetSessionVars()
 		if untouched && !sessVars.InTxn() &&
-			!sessVars.StmtCtx.InHandleForeignKeyTrigger && !sessVars.StmtCtx.ForeignKeyTriggerCtx.HasFKCascades {
+			!sessVars.StmtCtx.InHandleForeignKeyTrigger && !sessVars.StmtCtx.ForeignKeyTriggerCtx.HasFKCascades &&
+			!sessVars.StmtCtx.HasTriggers {
 			continue
 		}
 		newVs, err := idx.FetchValues(newData, nil)
@@ -937,6 +937,10 @@ func (d *Datum) ConvertTo(ctx Context, target *FieldType) (Datum, error) {
 		return d.convertToMysqlSet(ctx, target)
 	case mysql.TypeJSON:
 		return d.convertToMysqlJSON(target)
+	case mysql.TypeGeometry:
+		return d.convertToMysqlGeometry(target)
+	case mysql.TypeTiDBVectorFloat32:
+		return d.convertToVectorFloat32(target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -944,6 +948,54 @@ func (d *Datum) ConvertTo(ctx Context, target *FieldType) (Datum, error) {
 	}
 }
 
+// convertToMysqlGeometry checks the datum is a geometry in the internal format, and it matches the subtype of the
+// target. The geometry is kept as bytes.
+func (d *Datum) convertToMysqlGeometry(target *FieldType) (Datum, error) {
+	var ret Datum
+	switch d.k {
+	case KindString, KindBytes:
+		g, err := ParseGeometry(d.GetBytes())
+		if err != nil {
+			return ret, ErrCantCreateGeometryObject.GenWithStackByArgs()
+		}
+		if tp := target.GetGeometryType(); tp != GeometryTypeGeometry && tp != g.Shape.GeometryType() {
+			return ret, ErrCantCreateGeometryObject.GenWithStackByArgs()
+		}
+		ret.SetBytes(d.GetBytes())
+		return ret, nil
+	default:
+		return ret, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+}
+
+// convertToVectorFloat32 converts the text form or the binary format of a vector to the binary format, and checks the
+// dimension of the vector matches the target.
+func (d *Datum) convertToVectorFloat32(target *FieldType) (Datum, error) {
+	var ret Datum
+	switch d.k {
+	case KindString, KindBytes:
+		v, err := ParseVectorFloat32(d.GetString())
+		if err != nil {
+			// The binary format always has zero bytes in the dimension, so it can't be parsed as the text.
+			var decodeErr error
+			if v, decodeErr = DecodeVectorFloat32(d.GetBytes()); decodeErr != nil {
+				return ret, err
+			}
+		}
+		if flen := target.GetFlen(); flen != UnspecifiedLength && flen != len(v) {
+			return ret, ErrVectorDimensionNotFit.GenWithStackByArgs(len(v), flen)
+		}
+		ret.SetBytes(v.Encode())
+		return ret, nil
+	default:
+		s, err := d.ToString()
+		if err != nil {
+			return ret, err
+		}
+		return ret, ErrInvalidVector.GenWithStackByArgs(s)
+	}
+}
+
 func (d *Datum) convertToFloat(ctx Context, target *FieldType) (Datum, error) {
 	var (
 		f   float64
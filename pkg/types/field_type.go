@@ -377,6 +377,18 @@ func DefaultCharsetForType(tp byte) (defaultCharset string, defaultCollation str
 // the result should be longlong. However, this function returns long for this case. Please use `AggFieldType`
 // function if you need to handle the range bump.
 func mergeFieldType(a byte, b byte) byte {
+	// The vector isn't a MySQL type, it's kept only if it's merged with a vector or NULL, otherwise it's merged as
+	// a blob.
+	if a == mysql.TypeTiDBVectorFloat32 || b == mysql.TypeTiDBVectorFloat32 {
+		if a == b || a == mysql.TypeNull || b == mysql.TypeNull {
+			return mysql.TypeTiDBVectorFloat32
+		}
+		if a == mysql.TypeTiDBVectorFloat32 {
+			a = mysql.TypeLongBlob
+		} else {
+			b = mysql.TypeLongBlob
+		}
+	}
 	ia := getFieldTypeIndex(a)
 	ib := getFieldTypeIndex(b)
 	return fieldTypeMergeRules[ia][ib]
@@ -0,0 +1,632 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pingcap/errors"
+	mysql "github.com/pingcap/tidb/pkg/errno"
+	ast "github.com/pingcap/tidb/pkg/parser/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+// GeometryType is the subtype of the geometry type.
+type GeometryType = ast.GeometryType
+
+// GeometryType types.
+const (
+	GeometryTypeGeometry           = ast.GeometryTypeGeometry
+	GeometryTypePoint              = ast.GeometryTypePoint
+	GeometryTypeLineString         = ast.GeometryTypeLineString
+	GeometryTypePolygon            = ast.GeometryTypePolygon
+	GeometryTypeMultiPoint         = ast.GeometryTypeMultiPoint
+	GeometryTypeMultiLineString    = ast.GeometryTypeMultiLineString
+	GeometryTypeMultiPolygon       = ast.GeometryTypeMultiPolygon
+	GeometryTypeGeometryCollection = ast.GeometryTypeGeometryCollection
+)
+
+var (
+	// ErrCantCreateGeometryObject is returned when the value written to a geometry column isn't a valid geometry.
+	ErrCantCreateGeometryObject = dbterror.ClassTypes.NewStd(mysql.ErrCantCreateGeometryObject)
+	// ErrGISDifferentSRIDs is returned when the geometries of a binary geometry function are in different SRSs.
+	ErrGISDifferentSRIDs = dbterror.ClassTypes.NewStd(mysql.ErrGISDifferentSRIDs)
+	// ErrGISUnsupportedArgument is returned when a geometry function doesn't support the types of the geometries.
+	ErrGISUnsupportedArgument = dbterror.ClassTypes.NewStd(mysql.ErrGISUnsupportedArgument)
+	// ErrGISInvalidData is returned when the argument of a geometry function isn't a valid geometry.
+	ErrGISInvalidData = dbterror.ClassTypes.NewStd(mysql.ErrGISInvalidData)
+	// ErrInvalidGeoJSON is returned when the GeoJSON document can't be converted to a geometry.
+	ErrInvalidGeoJSON = dbterror.ClassTypes.NewStd(mysql.ErrInvalidGeoJSONUnspecified)
+	// ErrSRSNotFound is returned when the SRID isn't a known spatial reference system.
+	ErrSRSNotFound = dbterror.ClassTypes.NewStd(mysql.ErrSRSNotFound)
+	// ErrLongitudeOutOfRange is returned when the longitude of a geographic geometry is out of range.
+	ErrLongitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLongitudeOutOfRange)
+	// ErrLatitudeOutOfRange is returned when the latitude of a geographic geometry is out of range.
+	ErrLatitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLatitudeOutOfRange)
+	// ErrNotImplementedForGeographicSRS is returned when a geometry function can't be computed in a geographic SRS.
+	ErrNotImplementedForGeographicSRS = dbterror.ClassTypes.NewStd(mysql.ErrNotImplementedForGeographicSRS)
+
+	errMalformedGeometry = errors.New("malformed geometry")
+)
+
+// geometrySRIDLen is the length of the SRID which prefixes the WKB in the internal format.
+const geometrySRIDLen = 4
+
+// WKB byte orders.
+const (
+	wkbBigEndian    = 0
+	wkbLittleEndian = 1
+)
+
+// Geometry is a geometry value with its spatial reference system. It's stored in the MySQL internal format, which is
+// the 4-byte little-endian SRID followed by the WKB of the shape. The coordinates of a geographic geometry are stored
+// as (longitude, latitude), though they are (latitude, longitude) in its WKT and WKB.
+type Geometry struct {
+	SRID  uint32
+	Shape GeomShape
+}
+
+// GeomShape is the shape of a geometry.
+type GeomShape interface {
+	// GeometryType returns the type of the shape.
+	GeometryType() GeometryType
+	// IsEmpty returns whether the shape has no point.
+	IsEmpty() bool
+
+	appendWKB(buf []byte) []byte
+	appendWKT(buf []byte, withTag bool) []byte
+	// mapPoints returns a copy of the shape whose points are converted by f.
+	mapPoints(f func(GeomPoint) GeomPoint) GeomShape
+	// eachPoint calls f on the points of the shape until it returns false.
+	eachPoint(f func(GeomPoint) bool) bool
+}
+
+// GeomPoint is a point.
+type GeomPoint struct {
+	X, Y float64
+}
+
+// GeomLineString is a curve with linear interpolation between the points.
+type GeomLineString []GeomPoint
+
+// GeomPolygon is a planar surface. The first ring is the exterior boundary and the others are the holes.
+type GeomPolygon []GeomLineString
+
+// GeomMultiPoint is a collection of points.
+type GeomMultiPoint []GeomPoint
+
+// GeomMultiLineString is a collection of line strings.
+type GeomMultiLineString []GeomLineString
+
+// GeomMultiPolygon is a collection of polygons.
+type GeomMultiPolygon []GeomPolygon
+
+// GeomCollection is a collection of any shapes.
+type GeomCollection []GeomShape
+
+// GeometryType implements GeomShape interface.
+func (GeomPoint) GeometryType() GeometryType { return GeometryTypePoint }
+
+// GeometryType implements GeomShape interface.
+func (GeomLineString) GeometryType() GeometryType { return GeometryTypeLineString }
+
+// GeometryType implements GeomShape interface.
+func (GeomPolygon) GeometryType() GeometryType { return GeometryTypePolygon }
+
+// GeometryType implements GeomShape interface.
+func (GeomMultiPoint) GeometryType() GeometryType { return GeometryTypeMultiPoint }
+
+// GeometryType implements GeomShape interface.
+func (GeomMultiLineString) GeometryType() GeometryType { return GeometryTypeMultiLineString }
+
+// GeometryType implements GeomShape interface.
+func (GeomMultiPolygon) GeometryType() GeometryType { return GeometryTypeMultiPolygon }
+
+// GeometryType implements GeomShape interface.
+func (GeomCollection) GeometryType() GeometryType { return GeometryTypeGeometryCollection }
+
+// IsEmpty implements GeomShape interface.
+func (GeomPoint) IsEmpty() bool { return false }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomLineString) IsEmpty() bool { return len(s) == 0 }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomPolygon) IsEmpty() bool { return len(s) == 0 }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomMultiPoint) IsEmpty() bool { return len(s) == 0 }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomMultiLineString) IsEmpty() bool { return len(s) == 0 }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomMultiPolygon) IsEmpty() bool { return len(s) == 0 }
+
+// IsEmpty implements GeomShape interface.
+func (s GeomCollection) IsEmpty() bool {
+	for _, g := range s {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p GeomPoint) mapPoints(f func(GeomPoint) GeomPoint) GeomShape { return f(p) }
+
+func (s GeomLineString) mapPoints(f func(GeomPoint) GeomPoint) GeomShape { return s.mapLineString(f) }
+
+func (s GeomLineString) mapLineString(f func(GeomPoint) GeomPoint) GeomLineString {
+	ret := make(GeomLineString, len(s))
+	for i, p := range s {
+		ret[i] = f(p)
+	}
+	return ret
+}
+
+func (s GeomPolygon) mapPoints(f func(GeomPoint) GeomPoint) GeomShape { return s.mapPolygon(f) }
+
+func (s GeomPolygon) mapPolygon(f func(GeomPoint) GeomPoint) GeomPolygon {
+	ret := make(GeomPolygon, len(s))
+	for i, ring := range s {
+		ret[i] = ring.mapLineString(f)
+	}
+	return ret
+}
+
+func (s GeomMultiPoint) mapPoints(f func(GeomPoint) GeomPoint) GeomShape {
+	return GeomMultiPoint(GeomLineString(s).mapLineString(f))
+}
+
+func (s GeomMultiLineString) mapPoints(f func(GeomPoint) GeomPoint) GeomShape {
+	return GeomMultiLineString(GeomPolygon(s).mapPolygon(f))
+}
+
+func (s GeomMultiPolygon) mapPoints(f func(GeomPoint) GeomPoint) GeomShape {
+	ret := make(GeomMultiPolygon, len(s))
+	for i, polygon := range s {
+		ret[i] = polygon.mapPolygon(f)
+	}
+	return ret
+}
+
+func (s GeomCollection) mapPoints(f func(GeomPoint) GeomPoint) GeomShape {
+	ret := make(GeomCollection, len(s))
+	for i, g := range s {
+		ret[i] = g.mapPoints(f)
+	}
+	return ret
+}
+
+func (p GeomPoint) eachPoint(f func(GeomPoint) bool) bool { return f(p) }
+
+func (s GeomLineString) eachPoint(f func(GeomPoint) bool) bool {
+	for _, p := range s {
+		if !f(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s GeomPolygon) eachPoint(f func(GeomPoint) bool) bool {
+	for _, ring := range s {
+		if !ring.eachPoint(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s GeomMultiPoint) eachPoint(f func(GeomPoint) bool) bool {
+	return GeomLineString(s).eachPoint(f)
+}
+
+func (s GeomMultiLineString) eachPoint(f func(GeomPoint) bool) bool {
+	return GeomPolygon(s).eachPoint(f)
+}
+
+func (s GeomMultiPolygon) eachPoint(f func(GeomPoint) bool) bool {
+	for _, polygon := range s {
+		if !polygon.eachPoint(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s GeomCollection) eachPoint(f func(GeomPoint) bool) bool {
+	for _, g := range s {
+		if !g.eachPoint(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseGeometry parses the geometry from the internal format.
+func ParseGeometry(data []byte) (Geometry, error) {
+	if len(data) < geometrySRIDLen {
+		return Geometry{}, errMalformedGeometry
+	}
+	shape, err := ParseWKB(data[geometrySRIDLen:])
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: binary.LittleEndian.Uint32(data), Shape: shape}, nil
+}
+
+// Encode encodes the geometry to the internal format.
+func (g Geometry) Encode() []byte {
+	buf := binary.LittleEndian.AppendUint32(make([]byte, 0, 32), g.SRID)
+	return g.Shape.appendWKB(buf)
+}
+
+// ParseWKB parses the shape from the WKB. The shape is validated, the line strings must have 2 points at least, and
+// the rings of the polygons must be closed with 4 points at least.
+func ParseWKB(data []byte) (GeomShape, error) {
+	r := wkbReader{data: data}
+	shape, err := r.readShape(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.data) != 0 {
+		return nil, errMalformedGeometry
+	}
+	return shape, nil
+}
+
+// AppendWKB appends the little-endian WKB of the shape to buf.
+func AppendWKB(buf []byte, shape GeomShape) []byte {
+	return shape.appendWKB(buf)
+}
+
+// maxGeometryNestingDepth limits the nesting of the geometry collections.
+const maxGeometryNestingDepth = 64
+
+type wkbReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) readUint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errMalformedGeometry
+	}
+	v := r.order.Uint32(r.data)
+	r.data = r.data[4:]
+	return v, nil
+}
+
+func (r *wkbReader) readCount(minCount uint32, elemSize int) (int, error) {
+	n, err := r.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	// Every element takes elemSize bytes at least, which rejects the huge counts before allocating.
+	if n < minCount || uint64(n)*uint64(elemSize) > uint64(len(r.data)) {
+		return 0, errMalformedGeometry
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) readPoint() (GeomPoint, error) {
+	if len(r.data) < 16 {
+		return GeomPoint{}, errMalformedGeometry
+	}
+	p := GeomPoint{
+		X: math.Float64frombits(r.order.Uint64(r.data)),
+		Y: math.Float64frombits(r.order.Uint64(r.data[8:])),
+	}
+	r.data = r.data[16:]
+	if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+		return GeomPoint{}, errMalformedGeometry
+	}
+	return p, nil
+}
+
+func (r *wkbReader) readPoints(minCount uint32) ([]GeomPoint, error) {
+	n, err := r.readCount(minCount, 16)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]GeomPoint, n)
+	for i := range points {
+		if points[i], err = r.readPoint(); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (r *wkbReader) readRing() (GeomLineString, error) {
+	ring, err := r.readPoints(4)
+	if err != nil {
+		return nil, err
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return nil, errMalformedGeometry
+	}
+	return ring, nil
+}
+
+func (r *wkbReader) readPolygon() (GeomPolygon, error) {
+	n, err := r.readCount(1, 4)
+	if err != nil {
+		return nil, err
+	}
+	polygon := make(GeomPolygon, n)
+	for i := range polygon {
+		if polygon[i], err = r.readRing(); err != nil {
+			return nil, err
+		}
+	}
+	return polygon, nil
+}
+
+// readHeader reads the byte order and the type of a shape.
+func (r *wkbReader) readHeader() (GeometryType, error) {
+	if len(r.data) < 1 {
+		return 0, errMalformedGeometry
+	}
+	switch r.data[0] {
+	case wkbBigEndian:
+		r.order = binary.BigEndian
+	case wkbLittleEndian:
+		r.order = binary.LittleEndian
+	default:
+		return 0, errMalformedGeometry
+	}
+	r.data = r.data[1:]
+	tp, err := r.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	if tp < uint32(GeometryTypePoint) || tp > uint32(GeometryTypeGeometryCollection) {
+		return 0, errMalformedGeometry
+	}
+	return GeometryType(tp), nil
+}
+
+// readMember reads a member of a multi shape, which has its own header.
+func (r *wkbReader) readMember(tp GeometryType) (GeomShape, error) {
+	memberTp, err := r.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	if memberTp != tp {
+		return nil, errMalformedGeometry
+	}
+	return r.readBody(memberTp, 0)
+}
+
+func (r *wkbReader) readShape(depth int) (GeomShape, error) {
+	tp, err := r.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	return r.readBody(tp, depth)
+}
+
+func (r *wkbReader) readBody(tp GeometryType, depth int) (GeomShape, error) {
+	switch tp {
+	case GeometryTypePoint:
+		return r.readPoint()
+	case GeometryTypeLineString:
+		points, err := r.readPoints(2)
+		return GeomLineString(points), err
+	case GeometryTypePolygon:
+		return r.readPolygon()
+	}
+
+	// The members of the multi shapes and the collections are at least 9 bytes.
+	minCount := uint32(1)
+	if tp == GeometryTypeGeometryCollection {
+		minCount = 0
+	}
+	n, err := r.readCount(minCount, 9)
+	if err != nil {
+		return nil, err
+	}
+	switch tp {
+	case GeometryTypeMultiPoint:
+		ret := make(GeomMultiPoint, n)
+		for i := range ret {
+			member, err := r.readMember(GeometryTypePoint)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = member.(GeomPoint)
+		}
+		return ret, nil
+	case GeometryTypeMultiLineString:
+		ret := make(GeomMultiLineString, n)
+		for i := range ret {
+			member, err := r.readMember(GeometryTypeLineString)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = member.(GeomLineString)
+		}
+		return ret, nil
+	case GeometryTypeMultiPolygon:
+		ret := make(GeomMultiPolygon, n)
+		for i := range ret {
+			member, err := r.readMember(GeometryTypePolygon)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = member.(GeomPolygon)
+		}
+		return ret, nil
+	default:
+		if depth >= maxGeometryNestingDepth {
+			return nil, errMalformedGeometry
+		}
+		ret := make(GeomCollection, n)
+		for i := range ret {
+			if ret[i], err = r.readShape(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+}
+
+func appendWKBHeader(buf []byte, tp GeometryType) []byte {
+	buf = append(buf, wkbLittleEndian)
+	return binary.LittleEndian.AppendUint32(buf, uint32(tp))
+}
+
+func appendWKBPoints(buf []byte, points []GeomPoint) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(points)))
+	for _, p := range points {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+	}
+	return buf
+}
+
+func (p GeomPoint) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypePoint)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+}
+
+func (s GeomLineString) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypeLineString)
+	return appendWKBPoints(buf, s)
+}
+
+func (s GeomPolygon) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypePolygon)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	for _, ring := range s {
+		buf = appendWKBPoints(buf, ring)
+	}
+	return buf
+}
+
+func (s GeomMultiPoint) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypeMultiPoint)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	for _, p := range s {
+		buf = p.appendWKB(buf)
+	}
+	return buf
+}
+
+func (s GeomMultiLineString) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypeMultiLineString)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	for _, ls := range s {
+		buf = ls.appendWKB(buf)
+	}
+	return buf
+}
+
+func (s GeomMultiPolygon) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypeMultiPolygon)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	for _, polygon := range s {
+		buf = polygon.appendWKB(buf)
+	}
+	return buf
+}
+
+func (s GeomCollection) appendWKB(buf []byte) []byte {
+	buf = appendWKBHeader(buf, GeometryTypeGeometryCollection)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	for _, g := range s {
+		buf = g.appendWKB(buf)
+	}
+	return buf
+}
+
+// swapXY swaps the coordinates of a point, it converts the geographic points between the (latitude, longitude) axis
+// order of WKT and WKB and the (longitude, latitude) order of the internal format.
+func swapXY(p GeomPoint) GeomPoint {
+	return GeomPoint{X: p.Y, Y: p.X}
+}
+
+// GeometryFromText creates the geometry from the WKT in the SRS.
+func GeometryFromText(wkt string, srid uint32) (Geometry, error) {
+	shape, err := ParseWKT(wkt)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return newGeometryInSRS(shape, srid)
+}
+
+// GeometryFromWKB creates the geometry from the WKB in the SRS.
+func GeometryFromWKB(wkb []byte, srid uint32) (Geometry, error) {
+	shape, err := ParseWKB(wkb)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return newGeometryInSRS(shape, srid)
+}
+
+// newGeometryInSRS creates the geometry from the shape whose coordinates are in the axis order of the SRS.
+func newGeometryInSRS(shape GeomShape, srid uint32) (Geometry, error) {
+	srs, err := GetSpatialReferenceSystem(srid)
+	if err != nil {
+		return Geometry{}, err
+	}
+	if srs.Geographic {
+		shape = shape.mapPoints(swapXY)
+	}
+	return Geometry{SRID: srid, Shape: shape}, nil
+}
+
+// shapeInSRS returns the shape whose coordinates are in the axis order of its SRS.
+func (g Geometry) shapeInSRS() GeomShape {
+	if srs, err := GetSpatialReferenceSystem(g.SRID); err == nil && srs.Geographic {
+		return g.Shape.mapPoints(swapXY)
+	}
+	return g.Shape
+}
+
+// WKT returns the WKT of the geometry, the coordinates are in the axis order of its SRS.
+func (g Geometry) WKT() string {
+	return string(g.shapeInSRS().appendWKT(nil, true))
+}
+
+// WKB returns the WKB of the geometry, the coordinates are in the axis order of its SRS.
+func (g Geometry) WKB() []byte {
+	return g.shapeInSRS().appendWKB(nil)
+}
+
+// CheckCoordinates checks whether the coordinates of the geometry are in the valid range of its SRS.
+func (g Geometry) CheckCoordinates(funcName string) error {
+	srs, err := GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return err
+	}
+	if !srs.Geographic {
+		return nil
+	}
+	g.Shape.eachPoint(func(p GeomPoint) bool {
+		if p.X <= -180 || p.X > 180 {
+			err = ErrLongitudeOutOfRange.GenWithStackByArgs(p.X, funcName)
+		} else if p.Y < -90 || p.Y > 90 {
+			err = ErrLatitudeOutOfRange.GenWithStackByArgs(p.Y, funcName)
+		}
+		return err == nil
+	})
+	return err
+}
@@ -0,0 +1,331 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The options of ST_AsGeoJSON.
+const (
+	// GeoJSONOptionBoundingBox adds the bounding box to the GeoJSON.
+	GeoJSONOptionBoundingBox = 1 << iota
+	// GeoJSONOptionShortCRS adds the short format CRS URN, e.g. "EPSG:4326", to the GeoJSON.
+	GeoJSONOptionShortCRS
+	// GeoJSONOptionLongCRS adds the long format CRS URN, e.g. "urn:ogc:def:crs:EPSG::4326", to the GeoJSON. It
+	// overrides GeoJSONOptionShortCRS.
+	GeoJSONOptionLongCRS
+)
+
+// The options of ST_GeomFromGeoJSON, which decide how the coordinates of the higher dimensions are handled.
+const (
+	// GeoJSONRejectHigherDimensions rejects the document with the coordinates of the higher dimensions.
+	GeoJSONRejectHigherDimensions = 1
+	// GeoJSONMaxOption is the max option of ST_GeomFromGeoJSON. The higher dimensions are stripped by the options
+	// other than GeoJSONRejectHigherDimensions.
+	GeoJSONMaxOption = 4
+)
+
+// geoJSONTypes are the GeoJSON types of the shapes.
+var geoJSONTypes = map[GeometryType]string{
+	GeometryTypePoint:              "Point",
+	GeometryTypeLineString:         "LineString",
+	GeometryTypePolygon:            "Polygon",
+	GeometryTypeMultiPoint:         "MultiPoint",
+	GeometryTypeMultiLineString:    "MultiLineString",
+	GeometryTypeMultiPolygon:       "MultiPolygon",
+	GeometryTypeGeometryCollection: "GeometryCollection",
+}
+
+// GeoJSON returns the GeoJSON of the geometry. The coordinates are rounded to maxDecimalDigits, and options is the
+// bitmask of the GeoJSONOption flags. The CRS is only added for the geometries whose SRID isn't 0.
+func (g Geometry) GeoJSON(maxDecimalDigits int, options int) (BinaryJSON, error) {
+	round := func(f float64) float64 {
+		if maxDecimalDigits >= 17 {
+			return f
+		}
+		shift := math.Pow10(maxDecimalDigits)
+		return math.Round(f*shift) / shift
+	}
+	obj := geoJSONObject(g.Shape, round)
+	if options&GeoJSONOptionBoundingBox != 0 && !g.Shape.IsEmpty() {
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		g.Shape.eachPoint(func(p GeomPoint) bool {
+			minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+			maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+			return true
+		})
+		obj["bbox"] = []any{round(minX), round(minY), round(maxX), round(maxY)}
+	}
+	if g.SRID != 0 && options&(GeoJSONOptionShortCRS|GeoJSONOptionLongCRS) != 0 {
+		name := "EPSG:" + strconv.FormatUint(uint64(g.SRID), 10)
+		if options&GeoJSONOptionLongCRS != 0 {
+			name = "urn:ogc:def:crs:EPSG::" + strconv.FormatUint(uint64(g.SRID), 10)
+		}
+		obj["crs"] = map[string]any{"type": "name", "properties": map[string]any{"name": name}}
+	}
+	return CreateBinaryJSONWithCheck(obj)
+}
+
+func geoJSONObject(shape GeomShape, round func(float64) float64) map[string]any {
+	point := func(p GeomPoint) any {
+		return []any{round(p.X), round(p.Y)}
+	}
+	points := func(ps []GeomPoint) any {
+		ret := make([]any, len(ps))
+		for i, p := range ps {
+			ret[i] = point(p)
+		}
+		return ret
+	}
+	polygon := func(polygon GeomPolygon) any {
+		ret := make([]any, len(polygon))
+		for i, ring := range polygon {
+			ret[i] = points(ring)
+		}
+		return ret
+	}
+
+	obj := map[string]any{"type": geoJSONTypes[shape.GeometryType()]}
+	switch s := shape.(type) {
+	case GeomPoint:
+		obj["coordinates"] = point(s)
+	case GeomLineString:
+		obj["coordinates"] = points(s)
+	case GeomPolygon:
+		obj["coordinates"] = polygon(s)
+	case GeomMultiPoint:
+		obj["coordinates"] = points(s)
+	case GeomMultiLineString:
+		obj["coordinates"] = polygon(GeomPolygon(s))
+	case GeomMultiPolygon:
+		coords := make([]any, len(s))
+		for i, p := range s {
+			coords[i] = polygon(p)
+		}
+		obj["coordinates"] = coords
+	case GeomCollection:
+		geometries := make([]any, len(s))
+		for i, g := range s {
+			geometries[i] = geoJSONObject(g, round)
+		}
+		obj["geometries"] = geometries
+	}
+	return obj
+}
+
+// GeometryFromGeoJSON creates the geometry from the GeoJSON. The SRID is read from the CRS of the document, and it's
+// 4326 if there is no CRS. The errors are ErrInvalidGeoJSON, which should be generated with the function name.
+func GeometryFromGeoJSON(bj BinaryJSON, options int) (Geometry, error) {
+	if bj.TypeCode != JSONTypeCodeObject {
+		return Geometry{}, ErrInvalidGeoJSON
+	}
+	srid := uint32(4326)
+	if crs, ok := bj.objectSearchKey([]byte("crs")); ok && !isJSONNull(crs) {
+		var err error
+		if srid, err = parseGeoJSONCRS(crs); err != nil {
+			return Geometry{}, err
+		}
+	}
+	p := geoJSONParser{rejectHigherDimensions: options == GeoJSONRejectHigherDimensions}
+	shape, err := p.parseObject(bj, 0)
+	if err != nil {
+		return Geometry{}, err
+	}
+	return Geometry{SRID: srid, Shape: shape}, nil
+}
+
+func isJSONNull(bj BinaryJSON) bool {
+	return bj.TypeCode == JSONTypeCodeLiteral && bj.Value[0] == JSONLiteralNil
+}
+
+func parseGeoJSONCRS(crs BinaryJSON) (uint32, error) {
+	if crs.TypeCode != JSONTypeCodeObject {
+		return 0, ErrInvalidGeoJSON
+	}
+	props, ok := crs.objectSearchKey([]byte("properties"))
+	if !ok || props.TypeCode != JSONTypeCodeObject {
+		return 0, ErrInvalidGeoJSON
+	}
+	nameJSON, ok := props.objectSearchKey([]byte("name"))
+	if !ok || nameJSON.TypeCode != JSONTypeCodeString {
+		return 0, ErrInvalidGeoJSON
+	}
+	name := string(nameJSON.GetString())
+	if name == "urn:ogc:def:crs:OGC:1.3:CRS84" {
+		return 4326, nil
+	}
+	for _, prefix := range []string{"urn:ogc:def:crs:EPSG::", "EPSG:"} {
+		if strings.HasPrefix(name, prefix) {
+			srid, err := strconv.ParseUint(name[len(prefix):], 10, 32)
+			if err != nil {
+				return 0, ErrInvalidGeoJSON
+			}
+			return uint32(srid), nil
+		}
+	}
+	return 0, ErrInvalidGeoJSON
+}
+
+type geoJSONParser struct {
+	rejectHigherDimensions bool
+}
+
+func (p *geoJSONParser) member(obj BinaryJSON, key string, tp JSONTypeCode) (BinaryJSON, error) {
+	v, ok := obj.objectSearchKey([]byte(key))
+	if !ok || v.TypeCode != tp {
+		return BinaryJSON{}, ErrInvalidGeoJSON
+	}
+	return v, nil
+}
+
+func (p *geoJSONParser) parseObject(obj BinaryJSON, depth int) (GeomShape, error) {
+	if obj.TypeCode != JSONTypeCodeObject || depth > maxGeometryNestingDepth {
+		return nil, ErrInvalidGeoJSON
+	}
+	tpJSON, err := p.member(obj, "type", JSONTypeCodeString)
+	if err != nil {
+		return nil, err
+	}
+	tp := string(tpJSON.GetString())
+	switch tp {
+	case "Feature":
+		geometry, ok := obj.objectSearchKey([]byte("geometry"))
+		if !ok {
+			return nil, ErrInvalidGeoJSON
+		}
+		return p.parseObject(geometry, depth+1)
+	case "FeatureCollection", "GeometryCollection":
+		key := "geometries"
+		if tp == "FeatureCollection" {
+			key = "features"
+		}
+		members, err := p.member(obj, key, JSONTypeCodeArray)
+		if err != nil {
+			return nil, err
+		}
+		ret := make(GeomCollection, members.GetElemCount())
+		for i := range ret {
+			if ret[i], err = p.parseObject(members.ArrayGetElem(i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+
+	coords, err := p.member(obj, "coordinates", JSONTypeCodeArray)
+	if err != nil {
+		return nil, err
+	}
+	switch tp {
+	case "Point":
+		return p.point(coords)
+	case "LineString":
+		points, err := p.points(coords, 2)
+		return GeomLineString(points), err
+	case "Polygon":
+		return p.polygon(coords)
+	case "MultiPoint":
+		points, err := p.points(coords, 1)
+		return GeomMultiPoint(points), err
+	case "MultiLineString":
+		ret := make(GeomMultiLineString, coords.GetElemCount())
+		if len(ret) == 0 {
+			return nil, ErrInvalidGeoJSON
+		}
+		for i := range ret {
+			if ret[i], err = p.points(coords.ArrayGetElem(i), 2); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	case "MultiPolygon":
+		ret := make(GeomMultiPolygon, coords.GetElemCount())
+		if len(ret) == 0 {
+			return nil, ErrInvalidGeoJSON
+		}
+		for i := range ret {
+			if ret[i], err = p.polygon(coords.ArrayGetElem(i)); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+	return nil, ErrInvalidGeoJSON
+}
+
+func (p *geoJSONParser) number(bj BinaryJSON) (float64, error) {
+	switch bj.TypeCode {
+	case JSONTypeCodeFloat64:
+		return bj.GetFloat64(), nil
+	case JSONTypeCodeInt64:
+		return float64(bj.GetInt64()), nil
+	case JSONTypeCodeUint64:
+		return float64(bj.GetUint64()), nil
+	}
+	return 0, ErrInvalidGeoJSON
+}
+
+func (p *geoJSONParser) point(coords BinaryJSON) (GeomPoint, error) {
+	if coords.TypeCode != JSONTypeCodeArray {
+		return GeomPoint{}, ErrInvalidGeoJSON
+	}
+	if n := coords.GetElemCount(); n < 2 || n > 2 && p.rejectHigherDimensions {
+		return GeomPoint{}, ErrInvalidGeoJSON
+	}
+	x, err := p.number(coords.ArrayGetElem(0))
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	y, err := p.number(coords.ArrayGetElem(1))
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	return GeomPoint{X: x, Y: y}, nil
+}
+
+func (p *geoJSONParser) points(coords BinaryJSON, minCount int) ([]GeomPoint, error) {
+	if coords.TypeCode != JSONTypeCodeArray || coords.GetElemCount() < minCount {
+		return nil, ErrInvalidGeoJSON
+	}
+	points := make([]GeomPoint, coords.GetElemCount())
+	var err error
+	for i := range points {
+		if points[i], err = p.point(coords.ArrayGetElem(i)); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (p *geoJSONParser) polygon(coords BinaryJSON) (GeomPolygon, error) {
+	if coords.TypeCode != JSONTypeCodeArray || coords.GetElemCount() == 0 {
+		return nil, ErrInvalidGeoJSON
+	}
+	polygon := make(GeomPolygon, coords.GetElemCount())
+	for i := range polygon {
+		ring, err := p.points(coords.ArrayGetElem(i), 4)
+		if err != nil {
+			return nil, err
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return nil, ErrInvalidGeoJSON
+		}
+		polygon[i] = ring
+	}
+	return polygon, nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultSphereRadius is the default radius of ST_Distance_Sphere, which is the mean radius of the earth in meters.
+const DefaultSphereRadius = 6370986
+
+// geomTypeNames returns the WKT tags of the geometries, which are used in the errors.
+func geomTypeNames(gs ...Geometry) string {
+	names := make([]string, len(gs))
+	for i, g := range gs {
+		names[i] = wktTags[g.Shape.GeometryType()]
+	}
+	return strings.Join(names, ", ")
+}
+
+func pointSegmentDistance(p GeomPoint, s geomSegment) float64 {
+	if s.a == s.b {
+		return math.Hypot(p.X-s.a.X, p.Y-s.a.Y)
+	}
+	t := math.Max(0, math.Min(1, s.param(p)))
+	q := s.at(t)
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// Distance returns the minimum distance between g and o. The distance in a geographic SRS is the geodesic distance
+// on the ellipsoid in meters, which is only supported between the points and the multipoints.
+func (g Geometry) Distance(o Geometry, funcName string) (float64, error) {
+	parts, oParts := flattenShape(g.Shape), flattenShape(o.Shape)
+	if parts.isEmpty() || oParts.isEmpty() {
+		return 0, ErrGISInvalidData.GenWithStackByArgs(funcName)
+	}
+	srs, err := GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return 0, err
+	}
+	if srs.Geographic {
+		if len(parts.lineStrings)+len(parts.polygons)+len(oParts.lineStrings)+len(oParts.polygons) > 0 {
+			return 0, ErrNotImplementedForGeographicSRS.GenWithStackByArgs(funcName, geomTypeNames(g, o))
+		}
+		dist := math.Inf(1)
+		for _, p := range parts.points {
+			for _, q := range oParts.points {
+				dist = math.Min(dist, srs.geodesicDistance(p, q))
+			}
+		}
+		return dist, nil
+	}
+
+	if parts.intersects(oParts) {
+		return 0, nil
+	}
+	// The shapes don't intersect, so the minimum distance is between a vertex and a segment or a point.
+	dist := math.Inf(1)
+	distanceToParts := func(p GeomPoint, parts *geomParts) {
+		for _, q := range parts.points {
+			dist = math.Min(dist, math.Hypot(p.X-q.X, p.Y-q.Y))
+		}
+		for _, s := range append(parts.lineSegments(), parts.ringSegments()...) {
+			dist = math.Min(dist, pointSegmentDistance(p, s))
+		}
+	}
+	for _, p := range parts.vertices() {
+		distanceToParts(p, oParts)
+	}
+	for _, p := range oParts.vertices() {
+		distanceToParts(p, parts)
+	}
+	return dist, nil
+}
+
+// geodesicDistance returns the distance between two points on the ellipsoid by the inverse formula of Vincenty.
+func (srs *SpatialReferenceSystem) geodesicDistance(p, q GeomPoint) float64 {
+	if p == q {
+		return 0
+	}
+	a := srs.SemiMajorAxis
+	f := 1 / srs.InverseFlattening
+	b := a * (1 - f)
+	toRad := math.Pi / 180
+	l := (q.X - p.X) * toRad
+	u1 := math.Atan((1 - f) * math.Tan(p.Y*toRad))
+	u2 := math.Atan((1 - f) * math.Tan(q.Y*toRad))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = l + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			break
+		}
+	}
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := bigB * sinSigma * (cos2SigmaM + bigB/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return b * bigA * (sigma - deltaSigma)
+}
+
+// DistanceSphere returns the minimum distance between g and o on a sphere with the radius, the coordinates are
+// treated as the longitudes and the latitudes in degrees. Only the points and the multipoints are supported.
+func (g Geometry) DistanceSphere(o Geometry, radius float64, funcName string) (float64, error) {
+	var points [2][]GeomPoint
+	for i, geom := range []Geometry{g, o} {
+		switch s := geom.Shape.(type) {
+		case GeomPoint:
+			points[i] = []GeomPoint{s}
+		case GeomMultiPoint:
+			points[i] = s
+		default:
+			return 0, ErrGISUnsupportedArgument.GenWithStackByArgs(funcName)
+		}
+		// The coordinates in SRID 0 are checked as the geographic ones.
+		for _, p := range points[i] {
+			if p.X <= -180 || p.X > 180 {
+				return 0, ErrLongitudeOutOfRange.GenWithStackByArgs(p.X, funcName)
+			}
+			if p.Y < -90 || p.Y > 90 {
+				return 0, ErrLatitudeOutOfRange.GenWithStackByArgs(p.Y, funcName)
+			}
+		}
+	}
+	toRad := math.Pi / 180
+	dist := math.Inf(1)
+	for _, p := range points[0] {
+		for _, q := range points[1] {
+			// The haversine formula.
+			sinDLat := math.Sin((q.Y - p.Y) * toRad / 2)
+			sinDLon := math.Sin((q.X - p.X) * toRad / 2)
+			h := sinDLat*sinDLat + math.Cos(p.Y*toRad)*math.Cos(q.Y*toRad)*sinDLon*sinDLon
+			dist = math.Min(dist, 2*radius*math.Asin(math.Min(1, math.Sqrt(h))))
+		}
+	}
+	return dist, nil
+}
+
+func ringArea(ring GeomLineString) float64 {
+	var area float64
+	for i := 1; i < len(ring); i++ {
+		area += ring[i-1].X*ring[i].Y - ring[i].X*ring[i-1].Y
+	}
+	return math.Abs(area) / 2
+}
+
+// Area returns the area of the polygons of g. It's only supported in the Cartesian SRSs.
+func (g Geometry) Area(funcName string) (float64, error) {
+	srs, err := GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return 0, err
+	}
+	if srs.Geographic {
+		return 0, ErrNotImplementedForGeographicSRS.GenWithStackByArgs(funcName, geomTypeNames(g))
+	}
+	var area float64
+	for _, polygon := range flattenShape(g.Shape).polygons {
+		area += ringArea(polygon[0])
+		for _, hole := range polygon[1:] {
+			area -= ringArea(hole)
+		}
+	}
+	return area, nil
+}
+
+// Length returns the length of a line string or a multi line string. The length in a geographic SRS is the sum of
+// the geodesic distances of the segments in meters.
+func (g Geometry) Length(funcName string) (float64, error) {
+	var lineStrings []GeomLineString
+	switch s := g.Shape.(type) {
+	case GeomLineString:
+		lineStrings = []GeomLineString{s}
+	case GeomMultiLineString:
+		lineStrings = s
+	default:
+		return 0, ErrGISUnsupportedArgument.GenWithStackByArgs(funcName)
+	}
+	srs, err := GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return 0, err
+	}
+	var length float64
+	for _, ls := range lineStrings {
+		for i := 1; i < len(ls); i++ {
+			if srs.Geographic {
+				length += srs.geodesicDistance(ls[i-1], ls[i])
+			} else {
+				length += math.Hypot(ls[i].X-ls[i-1].X, ls[i].Y-ls[i-1].Y)
+			}
+		}
+	}
+	return length, nil
+}
@@ -0,0 +1,470 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"slices"
+)
+
+// The spatial relations are computed on the plane for all the SRSs, so the edges of the geographic geometries are
+// the straight lines between the longitudes and the latitudes rather than the geodesics.
+
+// geomSegment is a segment between two points.
+type geomSegment struct {
+	a, b GeomPoint
+}
+
+// geomParts is a shape which is flattened into its points, line strings and polygons.
+type geomParts struct {
+	points      []GeomPoint
+	lineStrings []GeomLineString
+	polygons    []GeomPolygon
+}
+
+func flattenShape(shape GeomShape) *geomParts {
+	parts := &geomParts{}
+	parts.add(shape)
+	return parts
+}
+
+func (parts *geomParts) add(shape GeomShape) {
+	switch s := shape.(type) {
+	case GeomPoint:
+		parts.points = append(parts.points, s)
+	case GeomLineString:
+		parts.lineStrings = append(parts.lineStrings, s)
+	case GeomPolygon:
+		parts.polygons = append(parts.polygons, s)
+	case GeomMultiPoint:
+		parts.points = append(parts.points, s...)
+	case GeomMultiLineString:
+		parts.lineStrings = append(parts.lineStrings, s...)
+	case GeomMultiPolygon:
+		parts.polygons = append(parts.polygons, s...)
+	case GeomCollection:
+		for _, g := range s {
+			parts.add(g)
+		}
+	}
+}
+
+func (parts *geomParts) isEmpty() bool {
+	return len(parts.points) == 0 && len(parts.lineStrings) == 0 && len(parts.polygons) == 0
+}
+
+// vertices returns all the points of the parts.
+func (parts *geomParts) vertices() []GeomPoint {
+	ret := slices.Clone(parts.points)
+	for _, ls := range parts.lineStrings {
+		ret = append(ret, ls...)
+	}
+	for _, polygon := range parts.polygons {
+		for _, ring := range polygon {
+			ret = append(ret, ring...)
+		}
+	}
+	return ret
+}
+
+func appendSegments(segs []geomSegment, ls GeomLineString) []geomSegment {
+	for i := 1; i < len(ls); i++ {
+		segs = append(segs, geomSegment{ls[i-1], ls[i]})
+	}
+	return segs
+}
+
+func polygonSegments(polygon GeomPolygon) []geomSegment {
+	var segs []geomSegment
+	for _, ring := range polygon {
+		segs = appendSegments(segs, ring)
+	}
+	return segs
+}
+
+func (parts *geomParts) lineSegments() []geomSegment {
+	var segs []geomSegment
+	for _, ls := range parts.lineStrings {
+		segs = appendSegments(segs, ls)
+	}
+	return segs
+}
+
+func (parts *geomParts) ringSegments() []geomSegment {
+	var segs []geomSegment
+	for _, polygon := range parts.polygons {
+		segs = append(segs, polygonSegments(polygon)...)
+	}
+	return segs
+}
+
+// cross returns the cross product of (a - o) and (b - o), its sign is the orientation of the three points.
+func cross(o, a, b GeomPoint) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// inBox returns whether p is in the bounding box of the segment.
+func (s geomSegment) inBox(p GeomPoint) bool {
+	return math.Min(s.a.X, s.b.X) <= p.X && p.X <= math.Max(s.a.X, s.b.X) &&
+		math.Min(s.a.Y, s.b.Y) <= p.Y && p.Y <= math.Max(s.a.Y, s.b.Y)
+}
+
+func (s geomSegment) contains(p GeomPoint) bool {
+	return cross(s.a, s.b, p) == 0 && s.inBox(p)
+}
+
+func (s geomSegment) intersects(o geomSegment) bool {
+	d1, d2 := cross(o.a, o.b, s.a), cross(o.a, o.b, s.b)
+	d3, d4 := cross(s.a, s.b, o.a), cross(s.a, s.b, o.b)
+	if (d1 > 0 && d2 < 0 || d1 < 0 && d2 > 0) && (d3 > 0 && d4 < 0 || d3 < 0 && d4 > 0) {
+		return true
+	}
+	return o.contains(s.a) || o.contains(s.b) || s.contains(o.a) || s.contains(o.b)
+}
+
+// param returns the parameter of the projection of p on the line of the segment, which is 0 at a and 1 at b.
+func (s geomSegment) param(p GeomPoint) float64 {
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	return ((p.X-s.a.X)*dx + (p.Y-s.a.Y)*dy) / (dx*dx + dy*dy)
+}
+
+func (s geomSegment) at(t float64) GeomPoint {
+	return GeomPoint{X: s.a.X + (s.b.X-s.a.X)*t, Y: s.a.Y + (s.b.Y-s.a.Y)*t}
+}
+
+// The locations of a point relative to a polygon.
+const (
+	locationExterior = iota
+	locationBoundary
+	locationInterior
+)
+
+func locatePointInPolygon(p GeomPoint, polygon GeomPolygon) int {
+	inside := false
+	for _, ring := range polygon {
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (geomSegment{a, b}).contains(p) {
+				return locationBoundary
+			}
+			// Cast a ray to the right of the point, the even-odd rule handles the holes.
+			if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return locationInterior
+	}
+	return locationExterior
+}
+
+// interiorPoint returns a point in the interior of the polygon. It scans the polygon by a horizontal line which
+// passes no vertex, and returns the middle of the first inner interval.
+func interiorPoint(polygon GeomPolygon) (GeomPoint, bool) {
+	ys := make([]float64, 0, len(polygon[0]))
+	for _, ring := range polygon {
+		for _, p := range ring {
+			ys = append(ys, p.Y)
+		}
+	}
+	slices.Sort(ys)
+	ys = slices.Compact(ys)
+	if len(ys) < 2 {
+		return GeomPoint{}, false
+	}
+	mid := len(ys) / 2
+	y := (ys[mid-1] + ys[mid]) / 2
+	var xs []float64
+	for _, seg := range polygonSegments(polygon) {
+		if (seg.a.Y > y) != (seg.b.Y > y) {
+			xs = append(xs, seg.a.X+(y-seg.a.Y)*(seg.b.X-seg.a.X)/(seg.b.Y-seg.a.Y))
+		}
+	}
+	if len(xs) < 2 {
+		return GeomPoint{}, false
+	}
+	slices.Sort(xs)
+	return GeomPoint{X: (xs[0] + xs[1]) / 2, Y: y}, true
+}
+
+// coversPoint returns whether the point is in the closure of the parts.
+func (parts *geomParts) coversPoint(p GeomPoint) bool {
+	if slices.Contains(parts.points, p) {
+		return true
+	}
+	for _, seg := range parts.lineSegments() {
+		if seg.contains(p) {
+			return true
+		}
+	}
+	for _, polygon := range parts.polygons {
+		if locatePointInPolygon(p, polygon) != locationExterior {
+			return true
+		}
+	}
+	return false
+}
+
+// interiorContainsPoint returns whether the point is in the interior of the parts.
+func (parts *geomParts) interiorContainsPoint(p GeomPoint) bool {
+	if slices.Contains(parts.points, p) {
+		return true
+	}
+	for _, ls := range parts.lineStrings {
+		closed := ls[0] == ls[len(ls)-1]
+		if !closed && (p == ls[0] || p == ls[len(ls)-1]) {
+			continue
+		}
+		for _, seg := range appendSegments(nil, ls) {
+			if seg.contains(p) {
+				return true
+			}
+		}
+	}
+	for _, polygon := range parts.polygons {
+		if locatePointInPolygon(p, polygon) == locationInterior {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentPiece is a piece of a segment which is split by the edges of a shape.
+type segmentPiece struct {
+	mid GeomPoint
+	// onLine and onRing indicate whether the piece overlaps a line string or a ring edge of the shape.
+	onLine, onRing bool
+}
+
+// splitSegment splits the segment at its intersections with the line strings and the rings of the parts. Each
+// piece either overlaps an edge, or lies in the interior or the exterior of every polygon.
+func (parts *geomParts) splitSegment(s geomSegment) []segmentPiece {
+	type interval struct {
+		lo, hi float64
+		ring   bool
+	}
+	ts := []float64{0, 1}
+	var overlaps []interval
+	split := func(e geomSegment, ring bool) {
+		ca, cb := cross(s.a, s.b, e.a), cross(s.a, s.b, e.b)
+		if ca == 0 && cb == 0 {
+			lo, hi := s.param(e.a), s.param(e.b)
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			lo, hi = math.Max(lo, 0), math.Min(hi, 1)
+			if lo < hi {
+				overlaps = append(overlaps, interval{lo, hi, ring})
+				ts = append(ts, lo, hi)
+			}
+			return
+		}
+		if !s.intersects(e) {
+			return
+		}
+		// The intersection is on the line of e, interpolate it by the distances of the ends of e to the segment.
+		t := s.param(e.a)
+		if ca != cb {
+			t = s.param(geomSegment{e.a, e.b}.at(ca / (ca - cb)))
+		}
+		if t > 0 && t < 1 {
+			ts = append(ts, t)
+		}
+	}
+	for _, e := range parts.lineSegments() {
+		split(e, false)
+	}
+	for _, e := range parts.ringSegments() {
+		split(e, true)
+	}
+	slices.Sort(ts)
+	ts = slices.Compact(ts)
+	pieces := make([]segmentPiece, 0, len(ts)-1)
+	for i := 1; i < len(ts); i++ {
+		tm := (ts[i-1] + ts[i]) / 2
+		piece := segmentPiece{mid: s.at(tm)}
+		for _, o := range overlaps {
+			if o.lo <= tm && tm <= o.hi {
+				if o.ring {
+					piece.onRing = true
+				} else {
+					piece.onLine = true
+				}
+			}
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
+
+func (parts *geomParts) locatePiece(piece segmentPiece) int {
+	switch {
+	case piece.onLine:
+		return locationInterior
+	case piece.onRing:
+		return locationBoundary
+	}
+	loc := locationExterior
+	for _, polygon := range parts.polygons {
+		loc = max(loc, locatePointInPolygon(piece.mid, polygon))
+	}
+	return loc
+}
+
+// coversLineString returns whether the line string is in the closure of the parts, and whether its interior
+// intersects the interior of the parts.
+func (parts *geomParts) coversLineString(ls GeomLineString) (covered, interiorIntersects bool) {
+	for _, seg := range appendSegments(nil, ls) {
+		if seg.a == seg.b {
+			if !parts.coversPoint(seg.a) {
+				return false, false
+			}
+			continue
+		}
+		for _, piece := range parts.splitSegment(seg) {
+			switch parts.locatePiece(piece) {
+			case locationExterior:
+				return false, false
+			case locationInterior:
+				interiorIntersects = true
+			}
+		}
+	}
+	return true, interiorIntersects
+}
+
+// coversPolygon returns whether the polygon is in the closure of the parts.
+func (parts *geomParts) coversPolygon(polygon GeomPolygon) bool {
+	for _, ring := range polygon {
+		if covered, _ := parts.coversLineString(ring); !covered {
+			return false
+		}
+	}
+	p, ok := interiorPoint(polygon)
+	if !ok || !parts.coversPoint(p) {
+		return false
+	}
+	// A hole of the parts may lie in the polygon though its boundary is covered, so the edges of the parts which
+	// pass the interior of the polygon must be shared by the polygons of the parts, that is, both sides of them
+	// are covered.
+	polygonParts := &geomParts{polygons: []GeomPolygon{polygon}}
+	for _, e := range parts.ringSegments() {
+		if e.a == e.b {
+			continue
+		}
+		// The offset to the sides is tiny relative to the edge.
+		nx, ny := (e.a.Y-e.b.Y)*1e-7, (e.b.X-e.a.X)*1e-7
+		for _, piece := range polygonParts.splitSegment(e) {
+			if polygonParts.locatePiece(piece) != locationInterior {
+				continue
+			}
+			left := GeomPoint{X: piece.mid.X + nx, Y: piece.mid.Y + ny}
+			right := GeomPoint{X: piece.mid.X - nx, Y: piece.mid.Y - ny}
+			if !parts.coversPoint(left) || !parts.coversPoint(right) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// covers returns whether o is in the closure of the parts, and whether the interior of o intersects the interior
+// of the parts.
+func (parts *geomParts) covers(o *geomParts) (covered, interiorIntersects bool) {
+	for _, p := range o.points {
+		if !parts.coversPoint(p) {
+			return false, false
+		}
+		interiorIntersects = interiorIntersects || parts.interiorContainsPoint(p)
+	}
+	for _, ls := range o.lineStrings {
+		lsCovered, lsInteriorIntersects := parts.coversLineString(ls)
+		if !lsCovered {
+			return false, false
+		}
+		interiorIntersects = interiorIntersects || lsInteriorIntersects
+	}
+	for _, polygon := range o.polygons {
+		if !parts.coversPolygon(polygon) {
+			return false, false
+		}
+		// The interior of the polygon is an open set in the closure of the parts, so it's in the interior.
+		interiorIntersects = true
+	}
+	return true, interiorIntersects
+}
+
+func (parts *geomParts) intersects(o *geomParts) bool {
+	for _, p := range o.vertices() {
+		if parts.coversPoint(p) {
+			return true
+		}
+	}
+	for _, p := range parts.vertices() {
+		if o.coversPoint(p) {
+			return true
+		}
+	}
+	segs := append(parts.lineSegments(), parts.ringSegments()...)
+	for _, e := range append(o.lineSegments(), o.ringSegments()...) {
+		for _, s := range segs {
+			if s.intersects(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Contains returns whether g contains o, that is, no point of o is in the exterior of g, and at least one point of
+// the interior of o is in the interior of g.
+func (g Geometry) Contains(o Geometry) bool {
+	parts, oParts := flattenShape(g.Shape), flattenShape(o.Shape)
+	if parts.isEmpty() || oParts.isEmpty() {
+		return false
+	}
+	covered, interiorIntersects := parts.covers(oParts)
+	return covered && interiorIntersects
+}
+
+// Within returns whether g is within o.
+func (g Geometry) Within(o Geometry) bool {
+	return o.Contains(g)
+}
+
+// Intersects returns whether g and o have a common point.
+func (g Geometry) Intersects(o Geometry) bool {
+	return flattenShape(g.Shape).intersects(flattenShape(o.Shape))
+}
+
+// Disjoint returns whether g and o have no common point.
+func (g Geometry) Disjoint(o Geometry) bool {
+	return !g.Intersects(o)
+}
+
+// Equals returns whether g and o are spatially equal, that is, they cover each other.
+func (g Geometry) Equals(o Geometry) bool {
+	parts, oParts := flattenShape(g.Shape), flattenShape(o.Shape)
+	if parts.isEmpty() || oParts.isEmpty() {
+		return parts.isEmpty() && oParts.isEmpty()
+	}
+	covered, _ := parts.covers(oParts)
+	if !covered {
+		return false
+	}
+	covered, _ = oParts.covers(parts)
+	return covered
+}
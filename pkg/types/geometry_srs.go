@@ -0,0 +1,45 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// SpatialReferenceSystem is a spatial reference system.
+type SpatialReferenceSystem struct {
+	SRID uint32
+	Name string
+	// Geographic indicates whether the SRS is a geographic one, whose coordinates are the longitudes and the
+	// latitudes on an ellipsoid. The axis order of its WKT and WKB is (latitude, longitude).
+	Geographic bool
+	// SemiMajorAxis and InverseFlattening describe the ellipsoid of a geographic SRS.
+	SemiMajorAxis     float64
+	InverseFlattening float64
+}
+
+// spatialReferenceSystems are the supported SRSs. The SRID 0 is the infinite Cartesian plane without unit.
+var spatialReferenceSystems = map[uint32]*SpatialReferenceSystem{
+	0:    {SRID: 0, Name: ""},
+	3857: {SRID: 3857, Name: "WGS 84 / Pseudo-Mercator"},
+	4326: {SRID: 4326, Name: "WGS 84", Geographic: true, SemiMajorAxis: 6378137, InverseFlattening: 298.257223563},
+	4490: {SRID: 4490, Name: "China Geodetic Coordinate System 2000", Geographic: true, SemiMajorAxis: 6378137,
+		InverseFlattening: 298.257222101},
+}
+
+// GetSpatialReferenceSystem returns the SRS of the SRID, ErrSRSNotFound is returned if it isn't supported.
+func GetSpatialReferenceSystem(srid uint32) (*SpatialReferenceSystem, error) {
+	srs, ok := spatialReferenceSystems[srid]
+	if !ok {
+		return nil, ErrSRSNotFound.GenWithStackByArgs(srid)
+	}
+	return srs, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeometryWKT(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"POINT(1 2)", "POINT(1 2)"},
+		{" point ( -1.5  2e20 ) ", "POINT(-1.5 2e20)"},
+		{"LINESTRING(0 0,1 1,2 0)", "LINESTRING(0 0,1 1,2 0)"},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))", "POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))"},
+		{"MULTIPOINT(1 1, 2 2)", "MULTIPOINT((1 1),(2 2))"},
+		{"MULTIPOINT((1 1),(2 2))", "MULTIPOINT((1 1),(2 2))"},
+		{"MULTILINESTRING((0 0,1 1),(2 2,3 3))", "MULTILINESTRING((0 0,1 1),(2 2,3 3))"},
+		{"MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))", "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))"},
+		{"GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))"},
+		{"GEOMCOLLECTION EMPTY", "GEOMETRYCOLLECTION EMPTY"},
+		{"GEOMETRYCOLLECTION()", "GEOMETRYCOLLECTION EMPTY"},
+	}
+	for _, tt := range tests {
+		g, err := GeometryFromText(tt.in, 0)
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.out, g.WKT())
+
+		// The internal format and the WKB can be parsed back.
+		g2, err := ParseGeometry(g.Encode())
+		require.NoError(t, err)
+		require.Equal(t, g, g2)
+		g3, err := GeometryFromWKB(g.WKB(), 0)
+		require.NoError(t, err)
+		require.Equal(t, g, g3)
+	}
+
+	for _, in := range []string{
+		"", "POINT(1)", "POINT(1 2", "POINT(1 2) x", "LINESTRING(0 0)", "POLYGON((0 0,1 0,1 1,0 1))",
+		"POLYGON((0 0,1 0,0 0))", "MULTIPOINT()", "CIRCLE(1 1)", "POINT(1e400 0)",
+	} {
+		_, err := ParseWKT(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestGeometryWKB(t *testing.T) {
+	// The big-endian WKB of POINT(1 2).
+	wkb := []byte{0, 0, 0, 0, 1, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0, 0x40, 0, 0, 0, 0, 0, 0, 0}
+	shape, err := ParseWKB(wkb)
+	require.NoError(t, err)
+	require.Equal(t, GeomPoint{X: 1, Y: 2}, shape)
+	// It's written in little-endian.
+	require.Equal(t, []byte{1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0x40}, AppendWKB(nil, shape))
+
+	for _, data := range [][]byte{
+		nil,
+		wkb[:10],
+		append(wkb, 0),
+		// The type is unknown.
+		{1, 8, 0, 0, 0},
+		// The count is too large.
+		{1, 2, 0, 0, 0, 0xff, 0xff, 0xff, 0xff},
+	} {
+		_, err := ParseWKB(data)
+		require.Error(t, err)
+	}
+	_, err = ParseGeometry([]byte{0, 0})
+	require.Error(t, err)
+}
+
+func TestGeometrySRS(t *testing.T) {
+	// The axis order of the geographic SRS is (latitude, longitude).
+	g, err := GeometryFromText("POINT(10 20)", 4326)
+	require.NoError(t, err)
+	require.Equal(t, GeomPoint{X: 20, Y: 10}, g.Shape)
+	require.Equal(t, "POINT(10 20)", g.WKT())
+	require.NoError(t, g.CheckCoordinates("st_geomfromtext"))
+
+	g, err = GeometryFromText("POINT(100 20)", 4326)
+	require.NoError(t, err)
+	require.True(t, ErrLatitudeOutOfRange.Equal(g.CheckCoordinates("st_geomfromtext")))
+	g, err = GeometryFromText("POINT(10 200)", 4326)
+	require.NoError(t, err)
+	require.True(t, ErrLongitudeOutOfRange.Equal(g.CheckCoordinates("st_geomfromtext")))
+
+	_, err = GeometryFromText("POINT(1 2)", 1234)
+	require.True(t, ErrSRSNotFound.Equal(err))
+	srs, err := GetSpatialReferenceSystem(3857)
+	require.NoError(t, err)
+	require.False(t, srs.Geographic)
+}
+
+func TestGeometryGeoJSON(t *testing.T) {
+	g, err := GeometryFromText("POLYGON((0 0,4 0,4 4,0 0))", 4326)
+	require.NoError(t, err)
+	bj, err := g.GeoJSON(17, 0)
+	require.NoError(t, err)
+	require.Equal(t, `{"coordinates": [[[0, 0], [0, 4], [4, 4], [0, 0]]], "type": "Polygon"}`, bj.String())
+	bj, err = g.GeoJSON(17, GeoJSONOptionBoundingBox|GeoJSONOptionShortCRS)
+	require.NoError(t, err)
+	require.Equal(t, `{"bbox": [0, 0, 4, 4], "coordinates": [[[0, 0], [0, 4], [4, 4], [0, 0]]], "crs": {"properties": {"name": "EPSG:4326"}, "type": "name"}, "type": "Polygon"}`, bj.String())
+
+	g = Geometry{Shape: GeomPoint{X: 1.23456, Y: 2}}
+	bj, err = g.GeoJSON(2, GeoJSONOptionLongCRS)
+	require.NoError(t, err)
+	require.Equal(t, `{"coordinates": [1.23, 2], "type": "Point"}`, bj.String())
+
+	for _, tt := range []struct {
+		in      string
+		options int
+		srid    uint32
+		wkt     string
+	}{
+		{`{"type": "Point", "coordinates": [1, 2]}`, 1, 4326, "POINT(2 1)"},
+		{`{"type": "Point", "coordinates": [1, 2], "crs": {"type": "name", "properties": {"name": "EPSG:0"}}}`, 1, 0, "POINT(1 2)"},
+		{`{"type": "LineString", "coordinates": [[1, 2], [3, 4.5]], "crs": null}`, 1, 4326, "LINESTRING(2 1,4.5 3)"},
+		// The third dimension is stripped by the options other than 1.
+		{`{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[1, 2, 3]]}, "properties": {}}`, 2, 4326, "MULTIPOINT((2 1))"},
+		{`{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}}]}`, 1, 4326, "GEOMETRYCOLLECTION(POINT(2 1))"},
+	} {
+		bj, err := ParseBinaryJSONFromString(tt.in)
+		require.NoError(t, err)
+		g, err := GeometryFromGeoJSON(bj, tt.options)
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.srid, g.SRID)
+		require.Equal(t, tt.wkt, g.WKT())
+	}
+
+	for _, in := range []string{
+		`[]`,
+		`{"type": "Point"}`,
+		`{"type": "Point", "coordinates": [1]}`,
+		`{"type": "Point", "coordinates": [1, 2, 3]}`,
+		`{"type": "Circle", "coordinates": [1, 2]}`,
+		`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`,
+		`{"type": "Point", "coordinates": [1, 2], "crs": {"type": "name", "properties": {"name": "x"}}}`,
+	} {
+		bj, err := ParseBinaryJSONFromString(in)
+		require.NoError(t, err)
+		_, err = GeometryFromGeoJSON(bj, 1)
+		require.True(t, ErrInvalidGeoJSON.Equal(err), in)
+	}
+}
+
+func TestGeometryRelations(t *testing.T) {
+	geom := func(wkt string) Geometry {
+		g, err := GeometryFromText(wkt, 0)
+		require.NoError(t, err)
+		return g
+	}
+	square := geom("POLYGON((0 0,4 0,4 4,0 4,0 0))")
+	squareWithHole := geom("POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,3 1,3 3,1 3,1 1))")
+	tests := []struct {
+		a, b                                 Geometry
+		contains, within, intersects, equals bool
+	}{
+		{square, geom("POINT(2 2)"), true, false, true, false},
+		// A point on the boundary isn't contained.
+		{square, geom("POINT(0 2)"), false, false, true, false},
+		{square, geom("POINT(5 5)"), false, false, false, false},
+		{squareWithHole, geom("POINT(2 2)"), false, false, false, false},
+		{square, geom("LINESTRING(1 1,3 3)"), true, false, true, false},
+		{square, geom("LINESTRING(0 0,4 0)"), false, false, true, false},
+		{square, geom("LINESTRING(1 1,5 5)"), false, false, true, false},
+		{square, geom("POLYGON((1 1,2 1,2 2,1 1))"), true, false, true, false},
+		{square, square, true, true, true, true},
+		{square, geom("POLYGON((4 0,0 0,0 4,4 4,4 0))"), true, true, true, true},
+		{square, geom("MULTIPOLYGON(((0 0,4 0,4 4,0 0)),((0 0,4 4,0 4,0 0)))"), true, true, true, true},
+		// The hole is in the polygon, though the boundary of the polygon is covered.
+		{squareWithHole, square, false, true, true, false},
+		{squareWithHole, geom("POLYGON((0.5 0.5,3.5 0.5,3.5 3.5,0.5 3.5,0.5 0.5))"), false, false, true, false},
+		{square, geom("POLYGON((2 2,6 2,6 6,2 6,2 2))"), false, false, true, false},
+		{square, geom("POLYGON((4 0,8 0,8 4,4 4,4 0))"), false, false, true, false},
+		{geom("LINESTRING(0 0,4 4)"), geom("LINESTRING(0 4,4 0)"), false, false, true, false},
+		{geom("LINESTRING(0 0,4 4)"), geom("LINESTRING(1 1,2 2)"), true, false, true, false},
+		{geom("LINESTRING(0 0,4 4)"), geom("POINT(0 0)"), false, false, true, false},
+		{geom("LINESTRING(0 0,4 4)"), geom("MULTIPOINT((1 1),(2 2))"), true, false, true, false},
+		{geom("MULTIPOINT((1 1),(2 2))"), geom("POINT(1 1)"), true, false, true, false},
+		{geom("GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(5 5,6 6))"), geom("POINT(5.5 5.5)"), true, false, true, false},
+		{geom("GEOMETRYCOLLECTION EMPTY"), geom("POINT(1 1)"), false, false, false, false},
+	}
+	for i, tt := range tests {
+		require.Equal(t, tt.contains, tt.a.Contains(tt.b), i)
+		require.Equal(t, tt.within, tt.a.Within(tt.b), i)
+		require.Equal(t, tt.intersects, tt.a.Intersects(tt.b), i)
+		require.Equal(t, tt.intersects, tt.b.Intersects(tt.a), i)
+		require.Equal(t, !tt.intersects, tt.a.Disjoint(tt.b), i)
+		require.Equal(t, tt.equals, tt.a.Equals(tt.b), i)
+	}
+}
+
+func TestGeometryMeasures(t *testing.T) {
+	geom := func(wkt string, srid uint32) Geometry {
+		g, err := GeometryFromText(wkt, srid)
+		require.NoError(t, err)
+		return g
+	}
+	dist, err := geom("POINT(0 0)", 0).Distance(geom("POINT(3 4)", 0), "st_distance")
+	require.NoError(t, err)
+	require.Equal(t, 5.0, dist)
+	dist, err = geom("POINT(0 5)", 0).Distance(geom("LINESTRING(-1 0,1 0)", 0), "st_distance")
+	require.NoError(t, err)
+	require.Equal(t, 5.0, dist)
+	dist, err = geom("POINT(1 1)", 0).Distance(geom("POLYGON((0 0,4 0,4 4,0 0))", 0), "st_distance")
+	require.NoError(t, err)
+	require.Equal(t, 0.0, dist)
+	dist, err = geom("POLYGON((0 0,1 0,1 1,0 0))", 0).Distance(geom("POLYGON((3 0,4 0,4 1,3 0))", 0), "st_distance")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, dist)
+
+	// One degree of the longitude on the equator of WGS 84.
+	dist, err = geom("POINT(0 0)", 4326).Distance(geom("POINT(0 1)", 4326), "st_distance")
+	require.NoError(t, err)
+	require.InDelta(t, 111319.49, dist, 0.01)
+	_, err = geom("POINT(0 0)", 4326).Distance(geom("LINESTRING(0 1,1 1)", 4326), "st_distance")
+	require.True(t, ErrNotImplementedForGeographicSRS.Equal(err))
+
+	dist, err = geom("POINT(0 0)", 0).DistanceSphere(geom("POINT(180 0)", 0), DefaultSphereRadius, "st_distance_sphere")
+	require.NoError(t, err)
+	require.InDelta(t, math.Pi*DefaultSphereRadius, dist, 1e-6)
+	_, err = geom("POINT(0 0)", 0).DistanceSphere(geom("POINT(0 100)", 0), DefaultSphereRadius, "st_distance_sphere")
+	require.True(t, ErrLatitudeOutOfRange.Equal(err))
+	_, err = geom("POINT(0 0)", 0).DistanceSphere(geom("LINESTRING(0 0,1 1)", 0), DefaultSphereRadius, "st_distance_sphere")
+	require.True(t, ErrGISUnsupportedArgument.Equal(err))
+
+	area, err := geom("POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 2,1 1))", 0).Area("st_area")
+	require.NoError(t, err)
+	require.Equal(t, 15.0, area)
+	length, err := geom("MULTILINESTRING((0 0,3 4),(0 0,1 0))", 0).Length("st_length")
+	require.NoError(t, err)
+	require.Equal(t, 6.0, length)
+	_, err = geom("POINT(0 0)", 0).Length("st_length")
+	require.True(t, ErrGISUnsupportedArgument.Equal(err))
+}
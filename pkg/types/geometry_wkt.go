@@ -0,0 +1,348 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// wktTags are the WKT tags of the shapes.
+var wktTags = map[GeometryType]string{
+	GeometryTypePoint:              "POINT",
+	GeometryTypeLineString:         "LINESTRING",
+	GeometryTypePolygon:            "POLYGON",
+	GeometryTypeMultiPoint:         "MULTIPOINT",
+	GeometryTypeMultiLineString:    "MULTILINESTRING",
+	GeometryTypeMultiPolygon:       "MULTIPOLYGON",
+	GeometryTypeGeometryCollection: "GEOMETRYCOLLECTION",
+}
+
+// ParseWKT parses the shape from the WKT. The tags are case-insensitive, and the points of a multipoint may be
+// parenthesized or not.
+func ParseWKT(wkt string) (GeomShape, error) {
+	p := wktParser{s: wkt}
+	shape, err := p.parseShape(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, errMalformedGeometry
+	}
+	return shape, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// tryConsume consumes the byte c if it's the next non-space byte.
+func (p *wktParser) tryConsume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) consume(c byte) error {
+	if !p.tryConsume(c) {
+		return errMalformedGeometry
+	}
+	return nil
+}
+
+func (p *wktParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !(c >= '0' && c <= '9' || c == '.' || c == '-' || c == '+' || c == 'e' || c == 'E') {
+			break
+		}
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil || math.IsInf(f, 0) {
+		return 0, errMalformedGeometry
+	}
+	return f, nil
+}
+
+func (p *wktParser) point() (GeomPoint, error) {
+	x, err := p.number()
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	return GeomPoint{X: x, Y: y}, nil
+}
+
+// list parses a parenthesized and comma-separated list, elem is called on every element.
+func (p *wktParser) list(elem func() error) error {
+	if err := p.consume('('); err != nil {
+		return err
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if !p.tryConsume(',') {
+			break
+		}
+	}
+	return p.consume(')')
+}
+
+func (p *wktParser) points(minCount int) ([]GeomPoint, error) {
+	var points []GeomPoint
+	err := p.list(func() error {
+		point, err := p.point()
+		points = append(points, point)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(points) < minCount {
+		return nil, errMalformedGeometry
+	}
+	return points, nil
+}
+
+func (p *wktParser) lineString() (GeomLineString, error) {
+	return p.points(2)
+}
+
+func (p *wktParser) polygon() (GeomPolygon, error) {
+	var polygon GeomPolygon
+	err := p.list(func() error {
+		ring, err := p.points(4)
+		if err == nil && ring[0] != ring[len(ring)-1] {
+			err = errMalformedGeometry
+		}
+		polygon = append(polygon, ring)
+		return err
+	})
+	return polygon, err
+}
+
+func (p *wktParser) parseShape(depth int) (GeomShape, error) {
+	tag := p.word()
+	switch tag {
+	case "POINT":
+		if err := p.consume('('); err != nil {
+			return nil, err
+		}
+		point, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		return point, p.consume(')')
+	case "LINESTRING":
+		return p.lineString()
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOINT":
+		var ret GeomMultiPoint
+		err := p.list(func() error {
+			parenthesized := p.tryConsume('(')
+			point, err := p.point()
+			if err == nil && parenthesized {
+				err = p.consume(')')
+			}
+			ret = append(ret, point)
+			return err
+		})
+		return ret, err
+	case "MULTILINESTRING":
+		var ret GeomMultiLineString
+		err := p.list(func() error {
+			ls, err := p.lineString()
+			ret = append(ret, ls)
+			return err
+		})
+		return ret, err
+	case "MULTIPOLYGON":
+		var ret GeomMultiPolygon
+		err := p.list(func() error {
+			polygon, err := p.polygon()
+			ret = append(ret, polygon)
+			return err
+		})
+		return ret, err
+	case "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		ret := GeomCollection{}
+		if p.word() == "EMPTY" {
+			return ret, nil
+		}
+		if p.tryConsume('(') {
+			if p.tryConsume(')') {
+				return ret, nil
+			}
+			p.pos--
+		}
+		if depth >= maxGeometryNestingDepth {
+			return nil, errMalformedGeometry
+		}
+		err := p.list(func() error {
+			g, err := p.parseShape(depth + 1)
+			ret = append(ret, g)
+			return err
+		})
+		return ret, err
+	}
+	return nil, errMalformedGeometry
+}
+
+// appendWKTNumber appends the number in the shortest representation which is parsed back to the same value.
+func appendWKTNumber(buf []byte, f float64) []byte {
+	if f == 0 {
+		// Don't print the negative zero.
+		return append(buf, '0')
+	}
+	buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+	// Go prints 1e+20, while MySQL prints 1e20.
+	for i := len(buf) - 1; i > 0 && buf[i] != 'e'; i-- {
+		if buf[i] == '+' {
+			buf = append(buf[:i], buf[i+1:]...)
+			break
+		}
+	}
+	return buf
+}
+
+func appendWKTPoint(buf []byte, p GeomPoint) []byte {
+	buf = appendWKTNumber(buf, p.X)
+	buf = append(buf, ' ')
+	return appendWKTNumber(buf, p.Y)
+}
+
+func appendWKTPoints(buf []byte, points []GeomPoint) []byte {
+	buf = append(buf, '(')
+	for i, p := range points {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendWKTPoint(buf, p)
+	}
+	return append(buf, ')')
+}
+
+func appendWKTTag(buf []byte, shape GeomShape, withTag bool) []byte {
+	if withTag {
+		buf = append(buf, wktTags[shape.GeometryType()]...)
+	}
+	return buf
+}
+
+func (p GeomPoint) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, p, withTag)
+	buf = append(buf, '(')
+	buf = appendWKTPoint(buf, p)
+	return append(buf, ')')
+}
+
+func (s GeomLineString) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	return appendWKTPoints(buf, s)
+}
+
+func (s GeomPolygon) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	buf = append(buf, '(')
+	for i, ring := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendWKTPoints(buf, ring)
+	}
+	return append(buf, ')')
+}
+
+func (s GeomMultiPoint) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	buf = append(buf, '(')
+	for i, p := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = p.appendWKT(buf, false)
+	}
+	return append(buf, ')')
+}
+
+func (s GeomMultiLineString) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	return GeomPolygon(s).appendWKT(buf, false)
+}
+
+func (s GeomMultiPolygon) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	buf = append(buf, '(')
+	for i, polygon := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = polygon.appendWKT(buf, false)
+	}
+	return append(buf, ')')
+}
+
+func (s GeomCollection) appendWKT(buf []byte, withTag bool) []byte {
+	buf = appendWKTTag(buf, s, withTag)
+	if len(s) == 0 {
+		return append(buf, " EMPTY"...)
+	}
+	buf = append(buf, '(')
+	for i, g := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = g.appendWKT(buf, true)
+	}
+	return append(buf, ')')
+}
@@ -103,3 +103,29 @@ func TestBinaryCompare(t *testing.T) {
 		require.Equal(t, test.result, CompareBinaryJSON(test.left, test.right), "%s should be %s %s", test.left.String(), compareMsg[test.result], test.right.String())
 	}
 }
+
+func TestBinaryJSONExtractAll(t *testing.T) {
+	tests := []struct {
+		doc      string
+		path     string
+		expected []string
+	}{
+		{`[1, [2, 3], {"a": 4}]`, `$[*]`, []string{`1`, `[2, 3]`, `{"a": 4}`}},
+		{`[1, [2, 3], {"a": 4}]`, `$[1]`, []string{`[2, 3]`}},
+		{`[1, [2, 3], {"a": 4}]`, `$[5]`, []string{}},
+		{`{"a": [{"b": 1}, {"b": 2}]}`, `$.a[*].b`, []string{`1`, `2`}},
+		{`{"a": {"a": 1}}`, `$**.a`, []string{`{"a": 1}`, `1`}},
+	}
+	for _, test := range tests {
+		bj, err := ParseBinaryJSONFromString(test.doc)
+		require.NoError(t, err)
+		pathExpr, err := ParseJSONPathExpr(test.path)
+		require.NoError(t, err)
+		values := bj.ExtractAll(pathExpr)
+		actual := make([]string, 0, len(values))
+		for _, v := range values {
+			actual = append(actual, v.String())
+		}
+		require.Equal(t, test.expected, actual, "%s %s", test.doc, test.path)
+	}
+}
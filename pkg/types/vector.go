@@ -0,0 +1,199 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	mysql "github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+var (
+	// ErrInvalidVector is returned when the text or the binary data isn't a valid vector.
+	ErrInvalidVector = dbterror.ClassTypes.NewStd(mysql.ErrInvalidVector)
+	// ErrVectorDimensionMismatch is returned when the vectors of a vector function have different dimensions.
+	ErrVectorDimensionMismatch = dbterror.ClassTypes.NewStd(mysql.ErrVectorDimensionMismatch)
+	// ErrVectorDimensionNotFit is returned when the vector written to a VECTOR(n) column doesn't have n dimensions.
+	ErrVectorDimensionNotFit = dbterror.ClassTypes.NewStd(mysql.ErrVectorDimensionNotFit)
+)
+
+// MaxVectorDimension is the max dimension of a vector.
+const MaxVectorDimension = 16383
+
+// vectorDimensionLen is the length of the dimension which prefixes the values in the binary format.
+const vectorDimensionLen = 4
+
+// VectorFloat32 is a vector of float32 values. It's stored in a compact binary format, which is the 4-byte
+// little-endian dimension followed by the little-endian float32 values, so a 768-dimension embedding takes 3076 bytes.
+// The values are never NaN or infinity.
+type VectorFloat32 []float32
+
+// ParseVectorFloat32 parses the vector from its text form, e.g. `[1, 2.5, -3]`.
+func ParseVectorFloat32(s string) (VectorFloat32, error) {
+	text := strings.TrimSpace(s)
+	if len(text) < 2 || text[0] != '[' || text[len(text)-1] != ']' {
+		return nil, ErrInvalidVector.GenWithStackByArgs(s)
+	}
+	text = strings.TrimSpace(text[1 : len(text)-1])
+	if len(text) == 0 {
+		return VectorFloat32{}, nil
+	}
+	elems := strings.Split(text, ",")
+	if len(elems) > MaxVectorDimension {
+		return nil, ErrInvalidVector.GenWithStackByArgs(s)
+	}
+	v := make(VectorFloat32, len(elems))
+	for i, elem := range elems {
+		f, err := strconv.ParseFloat(strings.TrimSpace(elem), 32)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrInvalidVector.GenWithStackByArgs(s)
+		}
+		v[i] = float32(f)
+	}
+	return v, nil
+}
+
+// DecodeVectorFloat32 decodes the vector from the binary format.
+func DecodeVectorFloat32(data []byte) (VectorFloat32, error) {
+	if len(data) < vectorDimensionLen {
+		return nil, ErrInvalidVector.GenWithStackByArgs("malformed binary data")
+	}
+	dim := binary.LittleEndian.Uint32(data)
+	data = data[vectorDimensionLen:]
+	if dim > MaxVectorDimension || uint64(len(data)) != uint64(dim)*4 {
+		return nil, ErrInvalidVector.GenWithStackByArgs("malformed binary data")
+	}
+	v := make(VectorFloat32, dim)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+		if f := float64(v[i]); math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrInvalidVector.GenWithStackByArgs("malformed binary data")
+		}
+	}
+	return v, nil
+}
+
+// VectorDimensionOf returns the dimension of the vector in the binary format without decoding the values.
+func VectorDimensionOf(data []byte) (int, error) {
+	if len(data) < vectorDimensionLen {
+		return 0, ErrInvalidVector.GenWithStackByArgs("malformed binary data")
+	}
+	return int(binary.LittleEndian.Uint32(data)), nil
+}
+
+// Encode encodes the vector to the binary format.
+func (v VectorFloat32) Encode() []byte {
+	buf := make([]byte, 0, vectorDimensionLen+len(v)*4)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+	for _, f := range v {
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(f))
+	}
+	return buf
+}
+
+// String returns the text form of the vector.
+func (v VectorFloat32) String() string {
+	buf := make([]byte, 0, 2+len(v)*8)
+	buf = append(buf, '[')
+	for i, f := range v {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, float64(f), 'g', -1, 32)
+	}
+	return string(append(buf, ']'))
+}
+
+// VectorTextFromBinary converts the vector in the binary format to its text form. The data is returned as it is if
+// it isn't a valid vector.
+func VectorTextFromBinary(data []byte) []byte {
+	v, err := DecodeVectorFloat32(data)
+	if err != nil {
+		return data
+	}
+	return []byte(v.String())
+}
+
+func (v VectorFloat32) checkDimension(other VectorFloat32) error {
+	if len(v) != len(other) {
+		return ErrVectorDimensionMismatch.GenWithStackByArgs(len(v), len(other))
+	}
+	return nil
+}
+
+// L1Distance returns the Manhattan distance between the vectors.
+func (v VectorFloat32) L1Distance(other VectorFloat32) (float64, error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, err
+	}
+	var d float64
+	for i := range v {
+		d += math.Abs(float64(v[i]) - float64(other[i]))
+	}
+	return d, nil
+}
+
+// L2Distance returns the Euclidean distance between the vectors.
+func (v VectorFloat32) L2Distance(other VectorFloat32) (float64, error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, err
+	}
+	var d float64
+	for i := range v {
+		diff := float64(v[i]) - float64(other[i])
+		d += diff * diff
+	}
+	return math.Sqrt(d), nil
+}
+
+// InnerProduct returns the inner product of the vectors.
+func (v VectorFloat32) InnerProduct(other VectorFloat32) (float64, error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, err
+	}
+	var p float64
+	for i := range v {
+		p += float64(v[i]) * float64(other[i])
+	}
+	return p, nil
+}
+
+// CosineDistance returns 1 minus the cosine similarity of the vectors, it's in [0, 2]. The distance is NaN if any of
+// the vectors is zero, the caller should treat it as NULL.
+func (v VectorFloat32) CosineDistance(other VectorFloat32) (float64, error) {
+	p, err := v.InnerProduct(other)
+	if err != nil {
+		return 0, err
+	}
+	norm := v.L2Norm() * other.L2Norm()
+	if norm == 0 {
+		return math.NaN(), nil
+	}
+	similarity := max(-1, min(1, p/norm))
+	return 1 - similarity, nil
+}
+
+// L2Norm returns the Euclidean norm of the vector.
+func (v VectorFloat32) L2Norm() float64 {
+	var n float64
+	for _, f := range v {
+		n += float64(f) * float64(f)
+	}
+	return math.Sqrt(n)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorFloat32Text(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"[1,2,3]", "[1,2,3]"},
+		{" [ 1.5 , -2e3, 0.1 ] ", "[1.5,-2000,0.1]"},
+		{"[]", "[]"},
+		{"[ ]", "[]"},
+	}
+	for _, tt := range tests {
+		v, err := ParseVectorFloat32(tt.in)
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.out, v.String())
+	}
+	for _, in := range []string{"", "1,2", "[1,2", "[1,,2]", "[a]", "[1e50]", "[NaN]", "[inf]"} {
+		_, err := ParseVectorFloat32(in)
+		require.True(t, ErrInvalidVector.Equal(err), in)
+	}
+}
+
+func TestVectorFloat32Binary(t *testing.T) {
+	v := VectorFloat32{1, -2.5, 3}
+	data := v.Encode()
+	require.Len(t, data, 16)
+	dim, err := VectorDimensionOf(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, dim)
+	decoded, err := DecodeVectorFloat32(data)
+	require.NoError(t, err)
+	require.Equal(t, v, decoded)
+	require.Equal(t, []byte("[1,-2.5,3]"), VectorTextFromBinary(data))
+
+	_, err = DecodeVectorFloat32(data[:15])
+	require.True(t, ErrInvalidVector.Equal(err))
+	_, err = DecodeVectorFloat32(VectorFloat32{float32(math.Inf(1))}.Encode())
+	require.True(t, ErrInvalidVector.Equal(err))
+	// The data which isn't a vector is returned as it is.
+	require.Equal(t, []byte("abc"), VectorTextFromBinary([]byte("abc")))
+}
+
+func TestVectorFloat32Distance(t *testing.T) {
+	v1, v2 := VectorFloat32{1, 2, 3}, VectorFloat32{4, 6, 3}
+	d, err := v1.L1Distance(v2)
+	require.NoError(t, err)
+	require.Equal(t, 7.0, d)
+	d, err = v1.L2Distance(v2)
+	require.NoError(t, err)
+	require.Equal(t, 5.0, d)
+	d, err = v1.InnerProduct(v2)
+	require.NoError(t, err)
+	require.Equal(t, 25.0, d)
+	d, err = VectorFloat32{1, 0}.CosineDistance(VectorFloat32{0, 2})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, d)
+	d, err = VectorFloat32{1, 1}.CosineDistance(VectorFloat32{-2, -2})
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, d, 1e-9)
+	d, err = VectorFloat32{0, 0}.CosineDistance(VectorFloat32{1, 1})
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(d))
+	require.Equal(t, 5.0, VectorFloat32{3, 4}.L2Norm())
+
+	_, err = v1.L2Distance(VectorFloat32{1, 2})
+	require.True(t, ErrVectorDimensionMismatch.Equal(err))
+}
+
+func TestConvertToVectorFloat32(t *testing.T) {
+	ft := NewFieldType(mysql.TypeTiDBVectorFloat32)
+	ft.SetFlen(3)
+	d := NewStringDatum("[1, 2, 3]")
+	v, err := d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.NoError(t, err)
+	require.Equal(t, VectorFloat32{1, 2, 3}.Encode(), v.GetBytes())
+
+	// The binary format is accepted too.
+	d = NewBytesDatum(VectorFloat32{4, 5, 6}.Encode())
+	v, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.NoError(t, err)
+	require.Equal(t, VectorFloat32{4, 5, 6}.Encode(), v.GetBytes())
+
+	d = NewStringDatum("[1, 2]")
+	_, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.True(t, ErrVectorDimensionNotFit.Equal(err))
+	d = NewStringDatum("abc")
+	_, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.True(t, ErrInvalidVector.Equal(err))
+	d = NewIntDatum(1)
+	_, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.True(t, ErrInvalidVector.Equal(err))
+}
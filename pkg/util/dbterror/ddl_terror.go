@@ -37,6 +37,10 @@ var (
 	ErrPausedDDLJob = ClassDDL.NewStd(mysql.ErrPausedDDLJob)
 	// ErrBDRRestrictedDDL means the DDL is restricted in BDR mode.
 	ErrBDRRestrictedDDL = ClassDDL.NewStd(mysql.ErrBDRRestrictedDDL)
+	// ErrMViewNotFastRefreshable means the materialized view can't be refreshed fast.
+	ErrMViewNotFastRefreshable = ClassDDL.NewStd(mysql.ErrMViewNotFastRefreshable)
+	// ErrMViewInvalidRefreshInterval means the refresh interval of the materialized view is invalid.
+	ErrMViewInvalidRefreshInterval = ClassDDL.NewStd(mysql.ErrMViewInvalidRefreshInterval)
 	// ErrRunMultiSchemaChanges means we run multi schema changes.
 	ErrRunMultiSchemaChanges = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "multi schema change for %s"), nil))
 	// ErrOperateSameColumn means we change the same columns multiple times in a DDL.
@@ -272,6 +276,10 @@ var (
 	ErrWrongObject = ClassDDL.NewStd(mysql.ErrWrongObject)
 	// ErrTableCantHandleFt returns FULLTEXT keys are not supported by table type
 	ErrTableCantHandleFt = ClassDDL.NewStd(mysql.ErrTableCantHandleFt)
+	// ErrBadFtColumn returns when the column can't be a part of a FULLTEXT index.
+	ErrBadFtColumn = ClassDDL.NewStd(mysql.ErrBadFtColumn)
+	// ErrFtParserNotDefined returns when the parser of a FULLTEXT index is unknown.
+	ErrFtParserNotDefined = ClassDDL.NewStd(mysql.ErrFunctionNotDefined)
 	// ErrFieldNotFoundPart returns an error when 'partition by columns' are not found in table columns.
 	ErrFieldNotFoundPart = ClassDDL.NewStd(mysql.ErrFieldNotFoundPart)
 	// ErrWrongTypeColumnValue returns 'Partition column values of incorrect type'
@@ -491,6 +499,22 @@ var (
 	)
 	// ErrCheckConstraintDupName is for duplicate check constraint names
 	ErrCheckConstraintDupName = ClassDDL.NewStd(mysql.ErrCheckConstraintDupName)
+	// ErrTrgAlreadyExists is returned when creating a trigger which already exists.
+	ErrTrgAlreadyExists = ClassDDL.NewStd(mysql.ErrTrgAlreadyExists)
+	// ErrTrgDoesNotExist is returned when dropping a trigger which doesn't exist.
+	ErrTrgDoesNotExist = ClassDDL.NewStd(mysql.ErrTrgDoesNotExist)
+	// ErrTrgOnViewOrTempTable is returned when creating a trigger on a view or a temporary table.
+	ErrTrgOnViewOrTempTable = ClassDDL.NewStd(mysql.ErrTrgOnViewOrTempTable)
+	// ErrTrgInWrongSchema is returned when the trigger and its table are in different schemas.
+	ErrTrgInWrongSchema = ClassDDL.NewStd(mysql.ErrTrgInWrongSchema)
+	// ErrNoTriggersOnSystemSchema is returned when creating a trigger on a system table.
+	ErrNoTriggersOnSystemSchema = ClassDDL.NewStd(mysql.ErrNoTriggersOnSystemSchema)
+	// ErrTrgCantChangeRow is returned when a trigger updates the OLD row or the NEW row of an AFTER trigger.
+	ErrTrgCantChangeRow = ClassDDL.NewStd(mysql.ErrTrgCantChangeRow)
+	// ErrTrgNoSuchRowInTrg is returned when a trigger refers to the OLD row of an INSERT trigger or the NEW row of a DELETE trigger.
+	ErrTrgNoSuchRowInTrg = ClassDDL.NewStd(mysql.ErrTrgNoSuchRowInTrg)
+	// ErrReferencedTrgDoesNotExist is returned when the trigger in the FOLLOWS or PRECEDES clause doesn't exist.
+	ErrReferencedTrgDoesNotExist = ClassDDL.NewStd(mysql.ErrReferencedTrgDoesNotExist)
 	// ErrUnsupportedDistTask is for `tidb_enable_dist_task enabled` but `tidb_ddl_enable_fast_reorg` disabled.
 	ErrUnsupportedDistTask = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation,
 		parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw,
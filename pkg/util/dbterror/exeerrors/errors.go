@@ -81,6 +81,35 @@ var (
 	ErrUnsupportedFlashbackTmpTable = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Recover/flashback table is not supported on temporary tables", nil))
 	ErrTruncateWrongInsertValue     = dbterror.ClassTable.NewStdErr(mysql.ErrTruncatedWrongValue, parser_mysql.Message("Incorrect %-.32s value: '%-.128s' for column '%.192s' at row %d", nil))
 	ErrExistsInHistoryPassword      = dbterror.ClassExecutor.NewStd(mysql.ErrExistsInHistoryPassword)
+	ErrMissingJSONTableValue        = dbterror.ClassExecutor.NewStd(mysql.ErrMissingJSONTableValue)
+	ErrWrongJSONTableValue          = dbterror.ClassExecutor.NewStd(mysql.ErrWrongJSONTableValue)
+	ErrJTValueOutOfRange            = dbterror.ClassExecutor.NewStd(mysql.ErrJTValueOutOfRange)
+
+	ErrSpAlreadyExists              = dbterror.ClassExecutor.NewStd(mysql.ErrSpAlreadyExists)
+	ErrSpDoesNotExist               = dbterror.ClassExecutor.NewStd(mysql.ErrSpDoesNotExist)
+	ErrSpLilabelMismatch            = dbterror.ClassExecutor.NewStd(mysql.ErrSpLilabelMismatch)
+	ErrSpLabelRedefine              = dbterror.ClassExecutor.NewStd(mysql.ErrSpLabelRedefine)
+	ErrSpLabelMismatch              = dbterror.ClassExecutor.NewStd(mysql.ErrSpLabelMismatch)
+	ErrSpWrongNoOfArgs              = dbterror.ClassExecutor.NewStd(mysql.ErrSpWrongNoOfArgs)
+	ErrSpCursorMismatch             = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorMismatch)
+	ErrSpCursorAlreadyOpen          = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorAlreadyOpen)
+	ErrSpCursorNotOpen              = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorNotOpen)
+	ErrSpUndeclaredVar              = dbterror.ClassExecutor.NewStd(mysql.ErrSpUndeclaredVar)
+	ErrSpWrongNoOfFetchArgs         = dbterror.ClassExecutor.NewStd(mysql.ErrSpWrongNoOfFetchArgs)
+	ErrSpFetchNoData                = dbterror.ClassExecutor.NewStd(mysql.ErrSpFetchNoData)
+	ErrSpDupParam                   = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupParam)
+	ErrSpDupVar                     = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupVar)
+	ErrSpDupCurs                    = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupCurs)
+	ErrSpCaseNotFound               = dbterror.ClassExecutor.NewStd(mysql.ErrSpCaseNotFound)
+	ErrSpDupHandler                 = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupHandler)
+	ErrSpNotVarArg                  = dbterror.ClassExecutor.NewStd(mysql.ErrSpNotVarArg)
+	ErrSpRecursionLimit             = dbterror.ClassExecutor.NewStd(mysql.ErrSpRecursionLimit)
+	ErrProcaccessDenied             = dbterror.ClassExecutor.NewStd(mysql.ErrProcaccessDenied)
+	ErrSpNoRetset                   = dbterror.ClassExecutor.NewStd(mysql.ErrSpNoRetset)
+	ErrNoSuchUser                   = dbterror.ClassExecutor.NewStd(mysql.ErrNoSuchUser)
+	ErrCommitNotAllowedInSfOrTrg    = dbterror.ClassExecutor.NewStd(mysql.ErrCommitNotAllowedInSfOrTrg)
+	ErrCantUpdateUsedTableInSfOrTrg = dbterror.ClassExecutor.NewStd(mysql.ErrCantUpdateUsedTableInSfOrTrg)
+	ErrMergeRowMatchedMoreThanOnce  = dbterror.ClassExecutor.NewStd(mysql.ErrMergeRowMatchedMoreThanOnce)
 
 	ErrWarnTooFewRecords              = dbterror.ClassExecutor.NewStd(mysql.ErrWarnTooFewRecords)
 	ErrWarnTooManyRecords             = dbterror.ClassExecutor.NewStd(mysql.ErrWarnTooManyRecords)
@@ -112,6 +112,9 @@ var (
 	ErrSubqueryMoreThan1Row     = dbterror.ClassOptimizer.NewStd(mysql.ErrSubqueryNo1Row)
 	ErrKeyPart0                 = dbterror.ClassOptimizer.NewStd(mysql.ErrKeyPart0)
 	ErrGettingNoopVariable      = dbterror.ClassOptimizer.NewStd(mysql.ErrGettingNoopVariable)
+	ErrTFMustHaveAlias          = dbterror.ClassOptimizer.NewStd(mysql.ErrTFMustHaveAlias)
+	ErrTFForbiddenJoinType      = dbterror.ClassOptimizer.NewStd(mysql.ErrTFForbiddenJoinType)
+	ErrFtMatchingKeyNotFound    = dbterror.ClassOptimizer.NewStd(mysql.ErrFtMatchingKeyNotFound)
 
 	ErrPrepareMulti     = dbterror.ClassExecutor.NewStd(mysql.ErrPrepareMulti)
 	ErrUnsupportedPs    = dbterror.ClassExecutor.NewStd(mysql.ErrUnsupportedPs)
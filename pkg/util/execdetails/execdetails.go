@@ -918,6 +918,8 @@ const (
 	TpFKCascadeRuntimeStats
 	// TpRURuntimeStats is the tp for RURuntimeStats
 	TpRURuntimeStats
+	// TpAdaptiveJoinRuntimeStats is the tp for AdaptiveJoinRuntimeStats
+	TpAdaptiveJoinRuntimeStats
 )
 
 // RuntimeStats is used to express the executor runtime information.
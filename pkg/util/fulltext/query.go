@@ -0,0 +1,403 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+// Term is a token to look up in a FULLTEXT index.
+type Term struct {
+	Text string
+	// Prefix means the term matches all the tokens starting with Text, e.g. `data*` in boolean mode.
+	Prefix bool
+}
+
+// Stats is the statistics of a FULLTEXT index used to calculate the relevance.
+type Stats struct {
+	// DocCount is the number of rows of the table.
+	DocCount int64
+	// DocFreq is the number of rows containing the term.
+	DocFreq map[Term]int64
+}
+
+// EncodeDocFreqs encodes the document frequencies of the terms as a comma separated list in the order of the terms.
+func (s *Stats) EncodeDocFreqs(terms []Term) string {
+	var sb strings.Builder
+	for i, t := range terms {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatInt(s.DocFreq[t], 10))
+	}
+	return sb.String()
+}
+
+// DecodeStats decodes the statistics encoded by EncodeDocFreqs.
+func DecodeStats(docCount int64, docFreqs string, terms []Term) (*Stats, error) {
+	stats := &Stats{DocCount: docCount, DocFreq: make(map[Term]int64, len(terms))}
+	if len(terms) == 0 {
+		return stats, nil
+	}
+	freqs := strings.Split(docFreqs, ",")
+	if len(freqs) != len(terms) {
+		return nil, errors.Errorf("the document frequencies %q don't match %d terms", docFreqs, len(terms))
+	}
+	for i, t := range terms {
+		df, err := strconv.ParseInt(freqs[i], 10, 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		stats.DocFreq[t] = df
+	}
+	return stats, nil
+}
+
+// idf returns the inverse document frequency of the term, it is calculated in the same way as InnoDB.
+func (s *Stats) idf(t Term) float64 {
+	df := max(s.DocFreq[t], 1)
+	n := max(s.DocCount, df)
+	if df >= n {
+		return math.Log10(1.0001)
+	}
+	return math.Log10(float64(n) / float64(df))
+}
+
+// Document is the tokenized text of a row.
+type Document struct {
+	positions map[string][]int
+}
+
+// NewDocument tokenizes the texts into a document.
+func NewDocument(parser model.FullTextParserType, texts ...string) *Document {
+	doc := &Document{positions: make(map[string][]int)}
+	for _, t := range Tokenize(parser, texts...) {
+		doc.positions[t.Text] = append(doc.positions[t.Text], t.Pos)
+	}
+	return doc
+}
+
+// termFreq returns the number of occurrences of the term in the document.
+func (d *Document) termFreq(t Term) int {
+	if !t.Prefix {
+		return len(d.positions[t.Text])
+	}
+	cnt := 0
+	for token, pos := range d.positions {
+		if strings.HasPrefix(token, t.Text) {
+			cnt += len(pos)
+		}
+	}
+	return cnt
+}
+
+// phraseFreq returns the number of occurrences of the consecutive tokens in the document.
+func (d *Document) phraseFreq(tokens []string) int {
+	cnt := 0
+	for _, start := range d.positions[tokens[0]] {
+		found := true
+		for i := 1; i < len(tokens) && found; i++ {
+			found = false
+			for _, p := range d.positions[tokens[i]] {
+				if p == start+i {
+					found = true
+					break
+				}
+			}
+		}
+		if found {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+type nodeKind byte
+
+const (
+	nodeTerm nodeKind = iota
+	nodePhrase
+	nodeGroup
+)
+
+// node is an item of a boolean mode query.
+type node struct {
+	// op is one of the boolean operators `+`, `-`, `~`, `>` and `<`, or 0 if the item has no operator.
+	op       byte
+	kind     nodeKind
+	terms    []Term
+	children []*node
+}
+
+// weights of the boolean operators which change the contribution of an item to the relevance.
+var opWeights = map[byte]float64{
+	0:   1,
+	'+': 1,
+	'>': 1.5,
+	'<': 0.5,
+	'~': -1,
+}
+
+// Query is a parsed search string of `MATCH ... AGAINST`.
+type Query struct {
+	parser  model.FullTextParserType
+	boolean bool
+	// terms is the search terms of a natural language mode query.
+	terms []Term
+	// root is the parsed expression of a boolean mode query.
+	root *node
+}
+
+// ParseQuery parses the search string of `MATCH ... AGAINST`.
+func ParseQuery(parser model.FullTextParserType, against string, booleanMode bool) (*Query, error) {
+	q := &Query{parser: parser, boolean: booleanMode}
+	if !booleanMode {
+		for _, token := range DistinctTokens(parser, against) {
+			q.terms = append(q.terms, Term{Text: token})
+		}
+		return q, nil
+	}
+	p := &boolParser{parser: parser, s: against}
+	root, err := p.parseGroup(0)
+	if err != nil {
+		return nil, err
+	}
+	q.root = root
+	return q, nil
+}
+
+// Terms returns all the terms whose statistics are needed to calculate the relevance.
+func (q *Query) Terms() []Term {
+	if !q.boolean {
+		return q.terms
+	}
+	var terms []Term
+	var collect func(n *node)
+	collect = func(n *node) {
+		terms = append(terms, n.terms...)
+		for _, c := range n.children {
+			collect(c)
+		}
+	}
+	collect(q.root)
+	return terms
+}
+
+// AccessTerms returns the terms such that every row matching the query contains at least one of them,
+// so the matching rows can be found by reading these terms from the index. It returns nil if no row
+// can match the query.
+func (q *Query) AccessTerms() []Term {
+	if !q.boolean {
+		return q.terms
+	}
+	return accessTerms(q.root)
+}
+
+func accessTerms(n *node) []Term {
+	if n.kind != nodeGroup {
+		return n.terms
+	}
+	var terms []Term
+	for _, c := range n.children {
+		if c.op == '-' {
+			continue
+		}
+		terms = append(terms, accessTerms(c)...)
+	}
+	return terms
+}
+
+// Match returns whether the document matches the query and its relevance.
+func (q *Query) Match(doc *Document, stats *Stats) (bool, float64) {
+	if !q.boolean {
+		matched, score := false, 0.0
+		for _, t := range q.terms {
+			if tf := doc.termFreq(t); tf > 0 {
+				matched = true
+				idf := stats.idf(t)
+				score += float64(tf) * idf * idf
+			}
+		}
+		return matched, score
+	}
+	return q.root.match(doc, stats)
+}
+
+func (n *node) match(doc *Document, stats *Stats) (bool, float64) {
+	switch n.kind {
+	case nodeTerm:
+		tf := doc.termFreq(n.terms[0])
+		idf := stats.idf(n.terms[0])
+		return tf > 0, float64(tf) * idf * idf
+	case nodePhrase:
+		tokens := make([]string, 0, len(n.terms))
+		for _, t := range n.terms {
+			tokens = append(tokens, t.Text)
+		}
+		tf := doc.phraseFreq(tokens)
+		score := 0.0
+		for _, t := range n.terms {
+			idf := stats.idf(t)
+			score += float64(tf) * idf * idf
+		}
+		return tf > 0, score
+	}
+	hasRequired := false
+	anyMatched := false
+	score := 0.0
+	for _, c := range n.children {
+		matched, s := c.match(doc, stats)
+		switch c.op {
+		case '+':
+			hasRequired = true
+			if !matched {
+				return false, 0
+			}
+		case '-':
+			if matched {
+				return false, 0
+			}
+			continue
+		}
+		if matched {
+			anyMatched = true
+			score += opWeights[c.op] * s
+		}
+	}
+	return hasRequired || anyMatched, score
+}
+
+// boolParser parses the search string of a boolean mode query.
+type boolParser struct {
+	parser model.FullTextParserType
+	s      string
+	pos    int
+}
+
+func (p *boolParser) peek() (rune, int) {
+	if p.pos >= len(p.s) {
+		return utf8.RuneError, 0
+	}
+	return utf8.DecodeRuneInString(p.s[p.pos:])
+}
+
+// parseGroup parses the items until the end of the string or the closing parenthesis of the group.
+func (p *boolParser) parseGroup(depth int) (*node, error) {
+	group := &node{kind: nodeGroup}
+	var op byte
+	for {
+		r, size := p.peek()
+		if size == 0 {
+			if depth > 0 {
+				return nil, errors.New("unmatched parenthesis in the search string")
+			}
+			return group, nil
+		}
+		switch {
+		case r == ')':
+			p.pos += size
+			if depth == 0 {
+				// Ignore the unmatched parenthesis like MySQL.
+				continue
+			}
+			return group, nil
+		case r == '(':
+			p.pos += size
+			child, err := p.parseGroup(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(child.children) > 0 {
+				child.op = op
+				group.children = append(group.children, child)
+			}
+			op = 0
+		case r == '"':
+			p.pos += size
+			end := strings.IndexByte(p.s[p.pos:], '"')
+			if end < 0 {
+				end = len(p.s) - p.pos
+			}
+			phrase := p.s[p.pos : p.pos+end]
+			p.pos = min(p.pos+end+1, len(p.s))
+			if child := p.newPhrase(phrase); child != nil {
+				child.op = op
+				group.children = append(group.children, child)
+			}
+			op = 0
+		case r == '+' || r == '-' || r == '~' || r == '>' || r == '<':
+			p.pos += size
+			if op == 0 {
+				op = byte(r)
+			}
+		case isWordChar(r):
+			start := p.pos
+			for {
+				r, size = p.peek()
+				if size > 0 && (isWordChar(r) || r == '\'') {
+					p.pos += size
+					continue
+				}
+				break
+			}
+			word := strings.TrimRight(p.s[start:p.pos], "'")
+			prefix := false
+			if r == '*' {
+				p.pos += size
+				prefix = true
+			}
+			if child := p.newWord(word, prefix); child != nil {
+				child.op = op
+				group.children = append(group.children, child)
+			}
+			op = 0
+		default:
+			p.pos += size
+			op = 0
+		}
+	}
+}
+
+func (p *boolParser) newPhrase(phrase string) *node {
+	tokens := Tokenize(p.parser, phrase)
+	if len(tokens) == 0 {
+		return nil
+	}
+	n := &node{kind: nodePhrase}
+	for _, t := range tokens {
+		n.terms = append(n.terms, Term{Text: t.Text})
+	}
+	if len(n.terms) == 1 {
+		n.kind = nodeTerm
+	}
+	return n
+}
+
+func (p *boolParser) newWord(word string, prefix bool) *node {
+	if prefix {
+		word = strings.ToLower(word)
+		if p.parser != model.FullTextParserTypeNgram || utf8.RuneCountInString(word) <= NgramTokenSize {
+			return &node{kind: nodeTerm, terms: []Term{{Text: word, Prefix: true}}}
+		}
+	}
+	// A word is split into several tokens by the ngram parser, it is searched as a phrase then.
+	return p.newPhrase(word)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"mysql", "don't", "use", "database_1", "tutorial"},
+		DistinctTokens(model.FullTextParserTypeStandard, "MySQL: don't use the database_1 tutorial", "ab mysql'"))
+	require.Equal(t, []string{"数据", "据库", "ab"},
+		DistinctTokens(model.FullTextParserTypeNgram, "数据库，a ab", "数据"))
+
+	tokens := Tokenize(model.FullTextParserTypeStandard, "hello world", "world")
+	require.Equal(t, []Token{{"hello", 0}, {"world", 1}, {"world", 3}}, tokens)
+}
+
+func TestNaturalLanguageMode(t *testing.T) {
+	q, err := ParseQuery(model.FullTextParserTypeStandard, "database tutorial", false)
+	require.NoError(t, err)
+	require.Equal(t, []Term{{Text: "database"}, {Text: "tutorial"}}, q.AccessTerms())
+
+	stats := &Stats{DocCount: 10, DocFreq: map[Term]int64{{Text: "database"}: 5, {Text: "tutorial"}: 1}}
+	matched, s1 := q.Match(NewDocument(model.FullTextParserTypeStandard, "a database tutorial"), stats)
+	require.True(t, matched)
+	matched, s2 := q.Match(NewDocument(model.FullTextParserTypeStandard, "database and database"), stats)
+	require.True(t, matched)
+	require.Greater(t, s1, s2)
+	matched, s3 := q.Match(NewDocument(model.FullTextParserTypeStandard, "nothing here"), stats)
+	require.False(t, matched)
+	require.Zero(t, s3)
+
+	encoded := stats.EncodeDocFreqs(q.Terms())
+	require.Equal(t, "5,1", encoded)
+	decoded, err := DecodeStats(stats.DocCount, encoded, q.Terms())
+	require.NoError(t, err)
+	require.Equal(t, stats, decoded)
+	_, err = DecodeStats(stats.DocCount, "5", q.Terms())
+	require.Error(t, err)
+}
+
+func TestBooleanMode(t *testing.T) {
+	stats := &Stats{DocCount: 10, DocFreq: map[Term]int64{}}
+	docs := []*Document{
+		NewDocument(model.FullTextParserTypeStandard, "MySQL tutorial for beginners"),
+		NewDocument(model.FullTextParserTypeStandard, "Optimizing MySQL databases"),
+		NewDocument(model.FullTextParserTypeStandard, "Database security tutorial"),
+	}
+	cases := []struct {
+		query   string
+		matched []bool
+	}{
+		{"+mysql -optimizing", []bool{true, false, false}},
+		{"mysql security", []bool{true, true, true}},
+		{"+tutorial +(mysql database)", []bool{true, false, true}},
+		{"datab*", []bool{false, true, true}},
+		{`"mysql tutorial"`, []bool{true, false, false}},
+		{`"tutorial mysql"`, []bool{false, false, false}},
+		{"-mysql", []bool{false, false, false}},
+		{"~security tutorial", []bool{true, false, true}},
+	}
+	for _, c := range cases {
+		q, err := ParseQuery(model.FullTextParserTypeStandard, c.query, true)
+		require.NoError(t, err)
+		for i, doc := range docs {
+			matched, _ := q.Match(doc, stats)
+			require.Equal(t, c.matched[i], matched, "query %s, doc %d", c.query, i)
+		}
+	}
+
+	q, err := ParseQuery(model.FullTextParserTypeStandard, "+mysql -optimizing (>tutorial <datab*)", true)
+	require.NoError(t, err)
+	require.Equal(t, []Term{{Text: "mysql"}, {Text: "tutorial"}, {Text: "datab", Prefix: true}}, q.AccessTerms())
+	q, err = ParseQuery(model.FullTextParserTypeStandard, "-mysql", true)
+	require.NoError(t, err)
+	require.Empty(t, q.AccessTerms())
+	_, err = ParseQuery(model.FullTextParserTypeStandard, "(mysql", true)
+	require.Error(t, err)
+}
+
+func TestNgramBooleanMode(t *testing.T) {
+	stats := &Stats{DocCount: 2, DocFreq: map[Term]int64{}}
+	doc := NewDocument(model.FullTextParserTypeNgram, "分布式数据库")
+	q, err := ParseQuery(model.FullTextParserTypeNgram, "+数据库", true)
+	require.NoError(t, err)
+	matched, score := q.Match(doc, stats)
+	require.True(t, matched)
+	require.Greater(t, score, 0.0)
+	q, err = ParseQuery(model.FullTextParserTypeNgram, "+数库", true)
+	require.NoError(t, err)
+	matched, _ = q.Match(doc, stats)
+	require.False(t, matched)
+}
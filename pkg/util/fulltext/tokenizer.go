@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+const (
+	// MinTokenSize is the minimum length in characters of a token produced by the standard parser.
+	// It is the same as the default value of `innodb_ft_min_token_size`.
+	MinTokenSize = 3
+	// MaxTokenSize is the maximum length in characters of a token produced by the standard parser.
+	// It is the same as the default value of `innodb_ft_max_token_size`.
+	MaxTokenSize = 84
+	// NgramTokenSize is the length in characters of a token produced by the ngram parser.
+	// It is the same as the default value of `ngram_token_size`.
+	NgramTokenSize = 2
+)
+
+// stopwords is the default stopword list of InnoDB, see `INFORMATION_SCHEMA.INNODB_FT_DEFAULT_STOPWORD`.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {},
+	"de": {}, "en": {}, "for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {},
+	"la": {}, "of": {}, "on": {}, "or": {}, "that": {}, "the": {}, "this": {}, "to": {}, "was": {},
+	"what": {}, "when": {}, "where": {}, "who": {}, "will": {}, "with": {}, "und": {}, "www": {},
+}
+
+// IsStopword returns whether the word is ignored by the standard parser.
+func IsStopword(word string) bool {
+	_, ok := stopwords[word]
+	return ok
+}
+
+// Token is a word produced by a parser together with its position in the text.
+type Token struct {
+	Text string
+	Pos  int
+}
+
+// Tokenize splits the texts into tokens by the given parser. The tokens are lower-cased
+// and their positions are counted across all the texts, so that a phrase can't span two texts.
+func Tokenize(parser model.FullTextParserType, texts ...string) []Token {
+	var tokens []Token
+	pos := 0
+	for _, text := range texts {
+		switch parser {
+		case model.FullTextParserTypeNgram:
+			tokens, pos = tokenizeNgram(tokens, pos, text)
+		default:
+			tokens, pos = tokenizeStandard(tokens, pos, text)
+		}
+		// Leave a gap between texts.
+		pos++
+	}
+	return tokens
+}
+
+// DistinctTokens returns the distinct token texts of the given texts, in the order they first appear.
+func DistinctTokens(parser model.FullTextParserType, texts ...string) []string {
+	tokens := Tokenize(parser, texts...)
+	seen := make(map[string]struct{}, len(tokens))
+	result := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if _, ok := seen[t.Text]; ok {
+			continue
+		}
+		seen[t.Text] = struct{}{}
+		result = append(result, t.Text)
+	}
+	return result
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// tokenizeStandard splits latin text by delimiters. An apostrophe between two word characters
+// is kept as a part of the word, so "don't" is a single token.
+func tokenizeStandard(tokens []Token, pos int, text string) ([]Token, int) {
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWordChar(r) {
+			i += size
+			continue
+		}
+		start := i
+		for i < len(text) {
+			r, size = utf8.DecodeRuneInString(text[i:])
+			if isWordChar(r) {
+				i += size
+				continue
+			}
+			if r == '\'' && i+size < len(text) {
+				next, _ := utf8.DecodeRuneInString(text[i+size:])
+				if isWordChar(next) {
+					i += size
+					continue
+				}
+			}
+			break
+		}
+		word := strings.ToLower(text[start:i])
+		n := utf8.RuneCountInString(word)
+		if n < MinTokenSize || n > MaxTokenSize || IsStopword(word) {
+			continue
+		}
+		tokens = append(tokens, Token{Text: word, Pos: pos})
+		pos++
+	}
+	return tokens, pos
+}
+
+// tokenizeNgram splits each run of non-delimiter characters into overlapping tokens of
+// NgramTokenSize characters. Runs shorter than NgramTokenSize are ignored.
+func tokenizeNgram(tokens []Token, pos int, text string) ([]Token, int) {
+	var run []rune
+	flush := func() {
+		for i := 0; i+NgramTokenSize <= len(run); i++ {
+			tokens = append(tokens, Token{Text: strings.ToLower(string(run[i : i+NgramTokenSize])), Pos: pos})
+			pos++
+		}
+		if len(run) > 0 {
+			// Leave a gap between runs, so that a phrase can't span a delimiter.
+			pos++
+		}
+		run = run[:0]
+	}
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			flush()
+			continue
+		}
+		run = append(run, r)
+	}
+	flush()
+	return tokens, pos
+}
@@ -80,6 +80,8 @@ func ExtractTableHintsFromStmtNode(node ast.Node, warnHandler hintWarnHandler) [
 		return x.TableHints
 	case *ast.DeleteStmt:
 		return x.TableHints
+	case *ast.MergeStmt:
+		return x.TableHints
 	case *ast.InsertStmt:
 		// check duplicated hints
 		checkInsertStmtHintDuplicated(node, warnHandler)
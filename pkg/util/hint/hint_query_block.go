@@ -67,6 +67,8 @@ func (p *QBHintHandler) Enter(in ast.Node) (ast.Node, bool) {
 		p.checkQueryBlockHints(node.TableHints, 0)
 	case *ast.DeleteStmt:
 		p.checkQueryBlockHints(node.TableHints, 0)
+	case *ast.MergeStmt:
+		p.checkQueryBlockHints(node.TableHints, 0)
 	case *ast.SelectStmt:
 		p.selectStmtOffset++
 		node.QueryBlockOffset = p.selectStmtOffset
@@ -93,6 +93,12 @@ func (b *BaseOOMAction) GetFallback() ActionOnExceed {
 	return b.fallbackAction
 }
 
+// HasEnoughDataToSpill checks whether the executor tracked by t holds at least 20% of the quota of the tracker
+// triggering the spill action. Otherwise, spilling the executor releases little memory and is triggered too frequently.
+func HasEnoughDataToSpill(t *Tracker, triggeredTracker *Tracker) bool {
+	return t.BytesConsumed() >= triggeredTracker.GetBytesLimit()/5
+}
+
 // Default OOM Action priority.
 const (
 	DefPanicPriority = iota
@@ -860,6 +860,8 @@ const (
 	LabelForChunkDataInDiskByChunks int = -30
 	// LabelForSortPartition represents the label of the sort partition
 	LabelForSortPartition = -31
+	// LabelForInstancePlanCache represents the label of the instance plan cache memory usage
+	LabelForInstancePlanCache int = -32
 )
 
 // MetricsTypes is used to get label for metrics
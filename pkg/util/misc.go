@@ -431,6 +431,11 @@ func ColumnToProto(c *model.ColumnInfo, forIndex bool) *tipb.ColumnInfo {
 		}
 	} else {
 		pc.Tp = int32(c.GetType())
+		// The storage engines read the geometry in the internal format as bytes, the spatial functions are always
+		// evaluated in TiDB.
+		if c.GetType() == mysql.TypeGeometry {
+			pc.Tp = int32(mysql.TypeLongBlob)
+		}
 	}
 	return pc
 }
@@ -15,6 +15,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/pingcap/tidb/pkg/types"
 )
 
@@ -34,3 +36,27 @@ type PlanCacheMatchOpts struct {
 	// Below are some variables that can affect the plan
 	ForeignKeyChecks bool
 }
+
+// InstancePlanCacheEntry is the information of a plan in the instance plan cache.
+type InstancePlanCacheEntry struct {
+	SchemaName string
+	SQLText    string
+	PlanDigest string
+	MemUsage   int64
+	// Hits is the number of the executions which use the plan.
+	Hits           int64
+	LoadTime       time.Time
+	LastActiveTime time.Time
+}
+
+// InstancePlanCacheStats is the statistics of the instance plan cache.
+type InstancePlanCacheStats struct {
+	Plans       int64
+	MemUsage    int64
+	MemCapacity int64
+	Hits        int64
+	Misses      int64
+	// Evictions is the number of the plans evicted to keep the memory usage under the capacity.
+	Evictions       int64
+	EvictedMemUsage int64
+}
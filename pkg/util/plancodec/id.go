@@ -83,6 +83,8 @@ const (
 	TypeUpdate = "Update"
 	// TypeDelete is the type of Delete.
 	TypeDelete = "Delete"
+	// TypeMerge is the type of Merge.
+	TypeMerge = "Merge"
 	// TypeIndexLookUp is the type of IndexLookUp.
 	TypeIndexLookUp = "IndexLookUp"
 	// TypeTableReader is the type of TableReader.
@@ -97,6 +99,10 @@ const (
 	TypeShuffleReceiver = "ShuffleReceiver"
 	// TypeTiKVSingleGather is the type of TiKVSingleGather.
 	TypeTiKVSingleGather = "TiKVSingleGather"
+	// TypeTiKVIndexMergeGather is the type of TiKVIndexMergeGather.
+	TypeTiKVIndexMergeGather = "TiKVIndexMergeGather"
+	// TypeMPPGather is the type of MPPGather.
+	TypeMPPGather = "MPPGather"
 	// TypeIndexMerge is the type of IndexMergeReader
 	TypeIndexMerge = "IndexMerge"
 	// TypePointGet is the type of PointGetPlan.
@@ -123,6 +129,10 @@ const (
 	TypeIndexFullScan = "IndexFullScan"
 	// TypeIndexRangeScan is the type of IndexRangeScan.
 	TypeIndexRangeScan = "IndexRangeScan"
+	// TypeIndexSkipScan is the type of IndexSkipScan.
+	TypeIndexSkipScan = "IndexSkipScan"
+	// TypeLooseIndexScan is the type of LooseIndexScan.
+	TypeLooseIndexScan = "LooseIndexScan"
 	// TypeCTETable is the type of TypeCTETable.
 	TypeCTETable = "CTETable"
 	// TypeCTE is the type of CTEFullScan.
@@ -139,6 +149,8 @@ const (
 	TypeSequence = "Sequence"
 	// TypeScalarSubQuery is the type of ScalarQuery
 	TypeScalarSubQuery = "ScalarSubQuery"
+	// TypeJSONTable is the type of JSON_TABLE.
+	TypeJSONTable = "JSONTable"
 )
 
 // plan id.
@@ -204,6 +216,12 @@ const (
 	typeExpandID              int = 58
 	typeImportIntoID          int = 59
 	TypeScalarSubQueryID      int = 60
+	typeJSONTableID           int = 61
+	typeMergeID               int = 62
+	typeIndexSkipScanID       int = 63
+	typeLooseIndexScanID      int = 64
+	typeTiKVIndexMergeGather  int = 65
+	typeMPPGather             int = 66
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -329,6 +347,18 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeImportIntoID
 	case TypeScalarSubQuery:
 		return TypeScalarSubQueryID
+	case TypeJSONTable:
+		return typeJSONTableID
+	case TypeMerge:
+		return typeMergeID
+	case TypeIndexSkipScan:
+		return typeIndexSkipScanID
+	case TypeLooseIndexScan:
+		return typeLooseIndexScanID
+	case TypeTiKVIndexMergeGather:
+		return typeTiKVIndexMergeGather
+	case TypeMPPGather:
+		return typeMPPGather
 	}
 	// Should never reach here.
 	return 0
@@ -457,6 +487,18 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeImportInto
 	case TypeScalarSubQueryID:
 		return TypeScalarSubQuery
+	case typeJSONTableID:
+		return TypeJSONTable
+	case typeMergeID:
+		return TypeMerge
+	case typeIndexSkipScanID:
+		return TypeIndexSkipScan
+	case typeLooseIndexScanID:
+		return TypeLooseIndexScan
+	case typeTiKVIndexMergeGather:
+		return TypeTiKVIndexMergeGather
+	case typeMPPGather:
+		return TypeMPPGather
 	}
 
 	// Should never reach here.
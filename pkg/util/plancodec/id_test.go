@@ -82,6 +82,12 @@ func TestPlanIDChanged(t *testing.T) {
 		{typeShuffleID, 54},
 		{typeShuffleReceiverID, 55},
 		{typeImportIntoID, 59},
+		{typeJSONTableID, 61},
+		{typeMergeID, 62},
+		{typeIndexSkipScanID, 63},
+		{typeLooseIndexScanID, 64},
+		{typeTiKVIndexMergeGather, 65},
+		{typeMPPGather, 66},
 	}
 
 	for _, testcase := range testCases {
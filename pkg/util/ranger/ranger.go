@@ -381,6 +381,99 @@ func AppendRanges2PointRanges(pointRanges Ranges, ranges Ranges, rangeMaxSize in
 	return newRanges, false
 }
 
+// SkipScanPrefixRange returns the range on the leading index column after the given value, or before it if desc is
+// true, it's used to seek the next distinct value of the leading column in an index skip scan. The range covers all
+// the values, including NULL, if the given value is nil.
+func SkipScanPrefixRange(prev *types.Datum, ft *types.FieldType, desc bool) *Range {
+	ran := &Range{
+		LowVal:    []types.Datum{{}},
+		HighVal:   []types.Datum{types.MaxValueDatum()},
+		Collators: []collate.Collator{collate.GetCollator(ft.GetCollate())},
+	}
+	if prev == nil {
+		return ran
+	}
+	if desc {
+		ran.HighVal[0] = datumToSortKey(*prev, ft)
+		ran.HighExclude = true
+	} else {
+		ran.LowVal[0] = datumToSortKey(*prev, ft)
+		ran.LowExclude = true
+	}
+	return ran
+}
+
+// BuildSkipScanRanges builds the ranges of an index skip scan, the ranges on the index columns after the leading one
+// are appended to each distinct value of the leading column. The values should be sorted in the order of the index
+// so that the ranges are sorted as well.
+func BuildSkipScanRanges(prefixes []types.Datum, ft *types.FieldType, suffixRanges Ranges) Ranges {
+	collator := collate.GetCollator(ft.GetCollate())
+	pointRanges := make(Ranges, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		v := datumToSortKey(prefix, ft)
+		pointRanges = append(pointRanges, &Range{
+			LowVal:    []types.Datum{v},
+			HighVal:   []types.Datum{v},
+			Collators: []collate.Collator{collator},
+		})
+	}
+	ranges, _ := AppendRanges2PointRanges(pointRanges, suffixRanges, 0)
+	return ranges
+}
+
+// NextPrefixRange returns the part of the range after the given values of the leading index columns, it's used to seek
+// the next distinct prefix of an index in a loose index scan. The range should be on the prefix columns only.
+func NextPrefixRange(prefix []types.Datum, fts []*types.FieldType, ran *Range) *Range {
+	return &Range{
+		LowVal:      prefixToSortKeys(prefix, fts),
+		LowExclude:  true,
+		HighVal:     ran.HighVal,
+		HighExclude: ran.HighExclude,
+		Collators:   prefixCollators(fts),
+	}
+}
+
+// PrefixRange returns the range covering the index entries with the given values of the leading index columns. The
+// entries whose column following the prefix is NULL are excluded if notNull is true.
+func PrefixRange(prefix []types.Datum, fts []*types.FieldType, notNull bool) *Range {
+	ran := &Range{
+		LowVal:    prefixToSortKeys(prefix, fts),
+		HighVal:   prefixToSortKeys(prefix, fts),
+		Collators: prefixCollators(fts),
+	}
+	if notNull {
+		ran.LowVal = append(ran.LowVal, types.MinNotNullDatum())
+		ran.HighVal = append(ran.HighVal, types.MaxValueDatum())
+		ran.Collators = append(ran.Collators, collate.GetBinaryCollator())
+	}
+	return ran
+}
+
+func prefixToSortKeys(prefix []types.Datum, fts []*types.FieldType) []types.Datum {
+	vals := make([]types.Datum, 0, len(prefix))
+	for i := range prefix {
+		vals = append(vals, datumToSortKey(prefix[i], fts[i]))
+	}
+	return vals
+}
+
+func prefixCollators(fts []*types.FieldType) []collate.Collator {
+	collators := make([]collate.Collator, 0, len(fts))
+	for _, ft := range fts {
+		collators = append(collators, collate.GetCollator(ft.GetCollate()))
+	}
+	return collators
+}
+
+// datumToSortKey converts the string value read from an index to the sort key, which is encoded in the index key
+// under the new collation, see pointConvertToSortKey.
+func datumToSortKey(d types.Datum, ft *types.FieldType) types.Datum {
+	if d.Kind() != types.KindString || ft.GetCollate() == charset.CollationBin || !collate.NewCollationEnabled() {
+		return d
+	}
+	return types.NewBytesDatum(collate.GetCollator(ft.GetCollate()).Key(d.GetString()))
+}
+
 // points2TableRanges build ranges for table scan from range points.
 // It will remove the nil and convert MinNotNull and MaxValue to MinInt64 or MinUint64 and MaxInt64 or MaxUint64.
 // rangeMaxSize is the max memory limit for ranges. O indicates no memory limit.
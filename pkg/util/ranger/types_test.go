@@ -15,10 +15,12 @@
 package ranger_test
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/core"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/collate"
@@ -238,3 +240,43 @@ func TestRangeMemUsage(t *testing.T) {
 	ranges := ranger.Ranges{&r1, &r2}
 	require.Equal(t, mem1+mem2, ranges.MemUsage())
 }
+
+func TestBuildSkipScanRanges(t *testing.T) {
+	ft := types.NewFieldType(mysql.TypeLong)
+	require.Equal(t, "[NULL,+inf]", ranger.SkipScanPrefixRange(nil, ft, false).String())
+	require.Equal(t, "[NULL,+inf]", ranger.SkipScanPrefixRange(nil, ft, true).String())
+	prev := types.NewIntDatum(1)
+	require.Equal(t, "(1,+inf]", ranger.SkipScanPrefixRange(&prev, ft, false).String())
+	require.Equal(t, "[NULL,1)", ranger.SkipScanPrefixRange(&prev, ft, true).String())
+
+	suffixRanges := ranger.Ranges{
+		{
+			LowVal:    []types.Datum{types.NewIntDatum(2)},
+			HighVal:   []types.Datum{types.NewIntDatum(3)},
+			Collators: collate.GetBinaryCollatorSlice(1),
+		},
+		{
+			LowVal:     []types.Datum{types.NewIntDatum(5)},
+			HighVal:    []types.Datum{types.MaxValueDatum()},
+			LowExclude: true,
+			Collators:  collate.GetBinaryCollatorSlice(1),
+		},
+	}
+	prefixes := []types.Datum{{}, types.NewIntDatum(1), types.NewIntDatum(4)}
+	ranges := ranger.BuildSkipScanRanges(prefixes, ft, suffixRanges)
+	require.Equal(t, "[[NULL 2,NULL 3] (NULL 5,NULL +inf] [1 2,1 3] (1 5,1 +inf] [4 2,4 3] (4 5,4 +inf]]", fmt.Sprint(ranges))
+	require.Len(t, ranger.BuildSkipScanRanges(nil, ft, suffixRanges), 0)
+}
+
+func TestPrefixRange(t *testing.T) {
+	fts := []*types.FieldType{types.NewFieldType(mysql.TypeLong), types.NewFieldType(mysql.TypeLong)}
+	prefix := []types.Datum{types.NewIntDatum(1), types.NewIntDatum(2)}
+	ran := &ranger.Range{
+		LowVal:    []types.Datum{types.NewIntDatum(0)},
+		HighVal:   []types.Datum{types.NewIntDatum(3)},
+		Collators: collate.GetBinaryCollatorSlice(1),
+	}
+	require.Equal(t, "(1 2,3]", ranger.NextPrefixRange(prefix, fts, ran).String())
+	require.Equal(t, "[1 2,1 2]", ranger.PrefixRange(prefix, fts, false).String())
+	require.Equal(t, "[1 -inf,1 +inf]", ranger.PrefixRange(prefix[:1], fts[:1], true).String())
+}
@@ -394,6 +394,14 @@ type BindableStmt struct {
 	Charset   string
 	Collation string
 	Users     map[string]struct{} // which users have processed this stmt
+
+	// below fields are used to compare the plans of the same statement.
+	Digest     string
+	PlanDigest string
+	ExecCount  int64
+	SumLatency time.Duration
+	FirstSeen  time.Time
+	LastSeen   time.Time
 }
 
 // GetMoreThanCntBindableStmt gets users' select/update/delete SQLs that occurred more than the specified count.
@@ -410,6 +418,7 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 			defer ssbd.Unlock()
 			if ssbd.initialized && (ssbd.stmtType == "Select" || ssbd.stmtType == "Delete" || ssbd.stmtType == "Update" || ssbd.stmtType == "Insert" || ssbd.stmtType == "Replace") {
 				if ssbd.history.Len() > 0 {
+					execCount, sumLatency, firstSeen, lastSeen := ssbd.sumExecStats()
 					ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
 					ssElement.Lock()
 
@@ -422,6 +431,13 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 							Charset:   ssElement.charset,
 							Collation: ssElement.collation,
 							Users:     make(map[string]struct{}),
+
+							Digest:     ssbd.digest,
+							PlanDigest: ssbd.planDigest,
+							ExecCount:  execCount,
+							SumLatency: sumLatency,
+							FirstSeen:  firstSeen,
+							LastSeen:   lastSeen,
 						}
 						maps.Copy(stmt.Users, ssElement.authUsers)
 						// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
@@ -439,6 +455,24 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 	return stmts
 }
 
+// sumExecStats sums up the executions in all intervals, the caller should hold the lock of ssbd.
+func (ssbd *stmtSummaryByDigest) sumExecStats() (execCount int64, sumLatency time.Duration, firstSeen, lastSeen time.Time) {
+	for e := ssbd.history.Front(); e != nil; e = e.Next() {
+		ssElement := e.Value.(*stmtSummaryByDigestElement)
+		ssElement.Lock()
+		execCount += ssElement.execCount
+		sumLatency += ssElement.sumLatency
+		if firstSeen.IsZero() || ssElement.firstSeen.Before(firstSeen) {
+			firstSeen = ssElement.firstSeen
+		}
+		if ssElement.lastSeen.After(lastSeen) {
+			lastSeen = ssElement.lastSeen
+		}
+		ssElement.Unlock()
+	}
+	return
+}
+
 // SetEnabled enables or disables statement summary
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value bool) error {
 	// `optEnabled` and `ssMap` don't need to be strictly atomically updated.
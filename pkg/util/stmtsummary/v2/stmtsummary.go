@@ -360,6 +360,13 @@ func (s *StmtSummary) GetMoreThanCntBindableStmt(cnt int64) []*stmtsummary.Binda
 						Charset:   record.Charset,
 						Collation: record.Collation,
 						Users:     make(map[string]struct{}),
+
+						Digest:     record.Digest,
+						PlanDigest: record.PlanDigest,
+						ExecCount:  record.ExecCount,
+						SumLatency: record.SumLatency,
+						FirstSeen:  record.FirstSeen,
+						LastSeen:   record.LastSeen,
 					}
 					maps.Copy(stmt.Users, record.AuthUsers)
 
@@ -0,0 +1,207 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectorindex
+
+import (
+	"cmp"
+	"container/heap"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+const (
+	// DefaultPartitionBits is the default number of hyperplanes of a VECTOR index, which makes 256 partitions.
+	DefaultPartitionBits = 8
+	// DefaultSeed is the default seed to generate the hyperplanes of a VECTOR index.
+	DefaultSeed = 1
+)
+
+// distanceMetrics are the distance functions which can be accelerated by a VECTOR index.
+var distanceMetrics = map[string]model.VectorDistanceMetric{
+	ast.VecL2Distance:           model.VectorDistanceMetricL2,
+	ast.VecCosineDistance:       model.VectorDistanceMetricCosine,
+	ast.VecNegativeInnerProduct: model.VectorDistanceMetricInnerProduct,
+}
+
+// DistanceMetricOf returns the metric of the distance function, it returns false if the function can't be accelerated
+// by a VECTOR index.
+func DistanceMetricOf(funcName string) (model.VectorDistanceMetric, bool) {
+	metric, ok := distanceMetrics[funcName]
+	return metric, ok
+}
+
+// DistanceFuncOf returns the name of the distance function of the metric.
+func DistanceFuncOf(metric model.VectorDistanceMetric) string {
+	for name, m := range distanceMetrics {
+		if m == metric {
+			return name
+		}
+	}
+	return ""
+}
+
+// Partitioner splits the vector space into 2^bits partitions by random hyperplanes through the origin, it's the
+// random projection LSH for the angular distance. The vectors in the same partition are on the same side of every
+// hyperplane, so the nearest neighbors of a vector are likely in its own partition or the partitions differing from
+// it on the hyperplanes close to it. The hyperplanes don't depend on the data, so the index needs no training and
+// its keys are stable when the data changes.
+type Partitioner struct {
+	bits int
+	// hyperplanes are the unit normal vectors of the hyperplanes.
+	hyperplanes [][]float64
+}
+
+type partitionerKey struct {
+	dim  int
+	bits int
+	seed int64
+}
+
+var partitioners sync.Map
+
+// GetPartitioner returns the partitioner of the VECTOR index.
+func GetPartitioner(info *model.VectorIndexInfo) *Partitioner {
+	key := partitionerKey{dim: info.Dimension, bits: info.PartitionBits, seed: info.Seed}
+	if p, ok := partitioners.Load(key); ok {
+		return p.(*Partitioner)
+	}
+	p, _ := partitioners.LoadOrStore(key, newPartitioner(key))
+	return p.(*Partitioner)
+}
+
+func newPartitioner(key partitionerKey) *Partitioner {
+	// The generator of a fixed seed always returns the same sequence, so the hyperplanes are the same on every node.
+	r := rand.New(rand.NewSource(key.seed)) // #nosec G404
+	p := &Partitioner{bits: key.bits, hyperplanes: make([][]float64, key.bits)}
+	for i := range p.hyperplanes {
+		h := make([]float64, key.dim)
+		var norm float64
+		for j := range h {
+			h[j] = r.NormFloat64()
+			norm += h[j] * h[j]
+		}
+		norm = math.Sqrt(norm)
+		for j := range h {
+			h[j] /= norm
+		}
+		p.hyperplanes[i] = h
+	}
+	return p
+}
+
+// NumPartitions returns the number of the partitions.
+func (p *Partitioner) NumPartitions() int {
+	return 1 << p.bits
+}
+
+// project returns the partition of the vector and its signed distances to the hyperplanes.
+func (p *Partitioner) project(v []float32) (uint32, []float64) {
+	var partition uint32
+	dists := make([]float64, p.bits)
+	for i, h := range p.hyperplanes {
+		for j := range h {
+			dists[i] += h[j] * float64(v[j])
+		}
+		if dists[i] >= 0 {
+			partition |= 1 << i
+		}
+	}
+	return partition, dists
+}
+
+// Partition returns the partition of the vector, the dimension of the vector must match the index.
+func (p *Partitioner) Partition(v []float32) uint32 {
+	partition, _ := p.project(v)
+	return partition
+}
+
+// Probes returns at most n partitions to search for the nearest neighbors of the vector. They're ordered by the
+// likelihood of containing the neighbors, which is measured by the sum of the distances to the hyperplanes that have
+// to be crossed to reach the partition, see "Multi-Probe LSH: Efficient Indexing for High-Dimensional Similarity
+// Search" by Lv et al.
+func (p *Partitioner) Probes(v []float32, n int) []uint32 {
+	partition, dists := p.project(v)
+	n = min(n, p.NumPartitions())
+	probes := make([]uint32, 0, n)
+	probes = append(probes, partition)
+	if p.bits == 0 {
+		return probes
+	}
+	// order sorts the hyperplanes by the distance, the sets of hyperplanes to cross are generated from it.
+	order := make([]int, p.bits)
+	for i := range order {
+		order[i] = i
+		dists[i] = math.Abs(dists[i])
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(dists[a], dists[b])
+	})
+	h := &probeHeap{{positions: []int{0}, score: dists[order[0]]}}
+	for len(probes) < n && h.Len() > 0 {
+		set := heap.Pop(h).(probeSet)
+		probe := partition
+		for _, pos := range set.positions {
+			probe ^= 1 << order[pos]
+		}
+		probes = append(probes, probe)
+		// Every set of hyperplanes is generated exactly once by shifting or expanding its last position, and its
+		// score is not less than the score of the set it comes from.
+		last := set.positions[len(set.positions)-1]
+		if last+1 >= p.bits {
+			continue
+		}
+		next := dists[order[last+1]]
+		shifted := append(slices.Clone(set.positions[:len(set.positions)-1]), last+1)
+		heap.Push(h, probeSet{positions: shifted, score: set.score - dists[order[last]] + next})
+		expanded := append(slices.Clone(set.positions), last+1)
+		heap.Push(h, probeSet{positions: expanded, score: set.score + next})
+	}
+	return probes
+}
+
+// EncodePartition encodes the partition to the key of the VECTOR index.
+func EncodePartition(partition uint32) []byte {
+	return binary.BigEndian.AppendUint16(nil, uint16(partition))
+}
+
+// probeSet is a set of hyperplanes to cross, the positions are the ascending positions in the sorted hyperplanes.
+type probeSet struct {
+	positions []int
+	score     float64
+}
+
+type probeHeap []probeSet
+
+func (h probeHeap) Len() int           { return len(h) }
+func (h probeHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h probeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *probeHeap) Push(x any) {
+	*h = append(*h, x.(probeSet))
+}
+
+func (h *probeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
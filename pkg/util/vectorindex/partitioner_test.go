@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectorindex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartition(t *testing.T) {
+	info := &model.VectorIndexInfo{Dimension: 3, PartitionBits: 4, Seed: 42}
+	p := GetPartitioner(info)
+	require.Same(t, p, GetPartitioner(info.Clone()))
+	require.Equal(t, 16, p.NumPartitions())
+
+	// The partition only depends on the direction of the vector.
+	v := []float32{1, 2, 3}
+	require.Equal(t, p.Partition(v), p.Partition([]float32{2, 4, 6}))
+	require.Less(t, p.Partition(v), uint32(16))
+	require.Equal(t, p.Partition(v), newPartitioner(partitionerKey{dim: 3, bits: 4, seed: 42}).Partition(v))
+	require.Equal(t, []byte{0, 5}, EncodePartition(5))
+}
+
+func TestProbes(t *testing.T) {
+	p := GetPartitioner(&model.VectorIndexInfo{Dimension: 8, PartitionBits: 6, Seed: 1})
+	v := []float32{0.5, -1, 2, 0, 3, -0.5, 1, 1}
+	probes := p.Probes(v, 100)
+	require.Len(t, probes, 64)
+	require.Equal(t, p.Partition(v), probes[0])
+	seen := make(map[uint32]struct{}, len(probes))
+	for _, probe := range probes {
+		seen[probe] = struct{}{}
+	}
+	require.Len(t, seen, 64)
+
+	// The probes are ordered by the distances to the crossed hyperplanes.
+	_, dists := p.project(v)
+	cost := func(probe uint32) float64 {
+		var c float64
+		for i := range dists {
+			if (probe^probes[0])&(1<<i) != 0 {
+				c += max(dists[i], -dists[i])
+			}
+		}
+		return c
+	}
+	for i := 1; i < len(probes); i++ {
+		require.LessOrEqual(t, cost(probes[i-1]), cost(probes[i])+1e-9)
+	}
+	require.Equal(t, probes[:5], p.Probes(v, 5))
+}
+
+func TestProbesRecall(t *testing.T) {
+	const dim, n = 16, 2000
+	r := rand.New(rand.NewSource(7))
+	vecs := make([][]float32, n)
+	for i := range vecs {
+		vecs[i] = make([]float32, dim)
+		for j := range vecs[i] {
+			vecs[i][j] = float32(r.NormFloat64())
+		}
+	}
+	p := GetPartitioner(&model.VectorIndexInfo{Dimension: dim, PartitionBits: 8, Seed: 3})
+	partitions := make([]uint32, n)
+	for i, v := range vecs {
+		partitions[i] = p.Partition(v)
+	}
+	// The query is a slightly moved data vector, it should be found by probing a small part of the partitions.
+	found := 0
+	for i := 0; i < 100; i++ {
+		q := make([]float32, dim)
+		for j := range q {
+			q[j] = vecs[i][j] + float32(r.NormFloat64()*0.1)
+		}
+		for _, probe := range p.Probes(q, 16) {
+			if probe == partitions[i] {
+				found++
+				break
+			}
+		}
+	}
+	require.Greater(t, found, 90)
+}
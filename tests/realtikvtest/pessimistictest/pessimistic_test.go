@@ -647,6 +647,66 @@ func TestSelectForUpdateNoWait(t *testing.T) {
 	tk2.MustExec("commit")
 }
 
+func TestSelectForUpdateSkipLocked(t *testing.T) {
+	store := realtikvtest.CreateMockStoreAndSetup(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk3 := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk2.MustExec("use test")
+	tk3.MustExec("use test")
+
+	tk.MustExec("drop table if exists tk")
+	tk.MustExec("create table tk (c1 int primary key, c2 int, unique key uk(c2))")
+	tk.MustExec("insert into tk values(1,1),(2,2),(3,3),(4,4),(5,5)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from tk where c1 in (2, 4) for update").Check(testkit.Rows("2 2", "4 4"))
+
+	// point get and batch point get
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from tk where c1 = 2 for update skip locked").Check(testkit.Rows())
+	tk2.MustQuery("select * from tk where c2 = 4 for update skip locked").Check(testkit.Rows())
+	tk2.MustQuery("select * from tk where c2 = 3 for update skip locked").Check(testkit.Rows("3 3"))
+	tk2.MustQuery("select * from tk where c1 in (1, 2, 4) for update skip locked").Check(testkit.Rows("1 1"))
+	tk2.MustQuery("select * from tk where c2 in (4, 5) for update skip locked").Check(testkit.Rows("5 5"))
+	tk2.MustExec("rollback")
+
+	// The limit only counts the rows not skipped, and only the returned rows are locked.
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from tk where c2 > 0 order by c1 limit 2 for update skip locked").Check(testkit.Rows("1 1", "3 3"))
+	tk3.MustExec("begin pessimistic")
+	tk3.MustQuery("select * from tk where c2 > 0 for update skip locked").Check(testkit.Rows("5 5"))
+	tk3.MustQuery("select * from tk where c1 > 0 for update skip locked").Check(testkit.Rows("5 5"))
+	tk.MustExec("commit")
+	tk3.MustQuery("select * from tk where c2 > 0 order by c1 desc for update skip locked").Check(testkit.Rows("5 5", "4 4", "2 2"))
+	tk2.MustExec("commit")
+	tk3.MustExec("commit")
+
+	// Rows are not locked and not skipped in auto-commit transactions.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("select * from tk where c1 = 1 for update")
+	tk2.MustQuery("select * from tk where c1 = 1 for update skip locked").Check(testkit.Rows("1 1"))
+	tk.MustExec("commit")
+
+	tk2.MustGetErrCode("select * from tk for share skip locked", errno.ErrNotSupportedYet)
+	tk2.MustExec("set tidb_enable_noop_functions = 1")
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("select * from tk where c1 = 1 for update")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select c1 from tk order by c1 for share skip locked").Check(testkit.Rows("2", "3", "4", "5"))
+	tk2.MustExec("commit")
+	tk.MustExec("commit")
+
+	tk.MustQuery("explain format = 'brief' select * from tk where c2 > 0 order by c1 limit 2 for update skip locked").Check(testkit.Rows(
+		"Limit 2.00 root  offset:0, count:2",
+		"└─SelectLock 2.00 root  for update skip locked 0",
+		"  └─TableReader 2.00 root  data:Selection",
+		"    └─Selection 2.00 cop[tikv]  gt(test.tk.c2, 0)",
+		"      └─TableFullScan 6.00 cop[tikv] table:tk keep order:true, stats:pseudo"))
+}
+
 func TestAsyncRollBackNoWait(t *testing.T) {
 	store := realtikvtest.CreateMockStoreAndSetup(t)
 